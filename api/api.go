@@ -12,8 +12,10 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	calendarfeedmodule "github.com/alejandro-albiol/athenai/internal/calendar_feed/module"
 	customequipmentmodule "github.com/alejandro-albiol/athenai/internal/custom_equipment/module"
 	customexercisemodule "github.com/alejandro-albiol/athenai/internal/custom_exercise/module"
+	customexercisecontraindicationmodule "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/module"
 	customexerciseequipmentmodule "github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/module"
 	customexercisemuscgroupmodule "github.com/alejandro-albiol/athenai/internal/custom_exercise_muscular_group/module"
 	custommemberworkoutmodule "github.com/alejandro-albiol/athenai/internal/custom_member_workout/module"
@@ -21,11 +23,25 @@ import (
 	customworkoutinstancemodule "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/module"
 	customworkouttemplatemodule "github.com/alejandro-albiol/athenai/internal/custom_workout_template/module"
 	equipmentmodule "github.com/alejandro-albiol/athenai/internal/equipment/module"
+	equipmentinventorymodule "github.com/alejandro-albiol/athenai/internal/equipment_inventory/module"
 	exercisemodule "github.com/alejandro-albiol/athenai/internal/exercise/module"
+	exercisecontraindicationmodule "github.com/alejandro-albiol/athenai/internal/exercise_contraindication/module"
 	exerciseequipmentmodule "github.com/alejandro-albiol/athenai/internal/exercise_equipment/module"
+	exerciselibrarymodule "github.com/alejandro-albiol/athenai/internal/exercise_library/module"
+	exercisemediamodule "github.com/alejandro-albiol/athenai/internal/exercise_media/module"
 	exercisemuscgroupmodule "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/module"
+	exerciseoverridemodule "github.com/alejandro-albiol/athenai/internal/exercise_override/module"
+	memberengagementmodule "github.com/alejandro-albiol/athenai/internal/member_engagement/module"
 	musculargroupmodule "github.com/alejandro-albiol/athenai/internal/muscular_group/module"
+	progressionmodule "github.com/alejandro-albiol/athenai/internal/progression/module"
+	revisionmodule "github.com/alejandro-albiol/athenai/internal/revision/module"
 	templateblockmodule "github.com/alejandro-albiol/athenai/internal/template_block/module"
+	templateclonemodule "github.com/alejandro-albiol/athenai/internal/template_clone/module"
+	templatemarketplacemodule "github.com/alejandro-albiol/athenai/internal/template_marketplace/module"
+	templateversionmodule "github.com/alejandro-albiol/athenai/internal/template_version/module"
+	trainingprogrammodule "github.com/alejandro-albiol/athenai/internal/training_program/module"
+	translationmodule "github.com/alejandro-albiol/athenai/internal/translation/module"
+	workoutschedulemodule "github.com/alejandro-albiol/athenai/internal/workout_schedule/module"
 	workouttemplatemodule "github.com/alejandro-albiol/athenai/internal/workout_template/module"
 	// workoutgeneratormodule "github.com/alejandro-albiol/athenai/internal/workout_generator/module"
 	// adminmodule "github.com/alejandro-albiol/athenai/internal/admin/module"
@@ -39,6 +55,14 @@ func NewAPIRouter(db *sql.DB) http.Handler {
 	auth := authmodule.NewAuthModule(db)
 	r.Mount("/auth", auth.Router)
 
+	// Signed media links are public, the signature in the URL is the credential
+	media := exercisemediamodule.NewExerciseMediaModule(db)
+	r.Mount("/media/file", media.FileRouter)
+
+	// Calendar feeds are public so calendar apps can subscribe, the token in the URL is the credential
+	calendarFeed := calendarfeedmodule.NewCalendarFeedModule(db)
+	r.Mount("/calendar", calendarFeed.FeedRouter)
+
 	// Protected routes subrouter
 	protected := chi.NewRouter()
 	protected.Use(middleware.AuthMiddleware(auth.Service))
@@ -55,17 +79,33 @@ func NewAPIRouter(db *sql.DB) http.Handler {
 	protected.Mount("/muscular-group", musculargroupmodule.NewMuscularGroupModule(db))
 	protected.Mount("/exercise-equipment", exerciseequipmentmodule.NewExerciseEquipmentModule(db))
 	protected.Mount("/exercise-muscular-group", exercisemuscgroupmodule.NewExerciseMuscularGroupModule(db))
+	protected.Mount("/exercise-contraindication", exercisecontraindicationmodule.NewExerciseContraindicationModule(db))
+	protected.Mount("/exercise-library", exerciselibrarymodule.NewExerciseLibraryModule(db))
+	protected.Mount("/translation", translationmodule.NewTranslationModule(db))
+	protected.Mount("/revision", revisionmodule.NewRevisionModule(db))
+	protected.Mount("/template-version", templateversionmodule.NewTemplateVersionModule(db))
+	protected.Mount("/template-clone", templateclonemodule.NewTemplateCloneModule(db))
+	protected.Mount("/template-marketplace", templatemarketplacemodule.NewTemplateMarketplaceModule(db))
+	protected.Mount("/training-program", trainingprogrammodule.NewTrainingProgramModule(db))
+	protected.Mount("/workout-schedule", workoutschedulemodule.NewWorkoutScheduleModule(db))
+	protected.Mount("/progression", progressionmodule.NewProgressionModule(db))
+	protected.Mount("/engagement", memberengagementmodule.NewMemberEngagementModule(db))
+	protected.Mount("/media", media.Router)
+	protected.Mount("/calendar-feed", calendarFeed.Router)
 	// Uncomment when implemented:
 	// protected.Mount("/admin", adminmodule.NewAdminModule(db))
 	// protected.Mount("/custom-exercise-muscular-group", customexercisemuscgroupmodule.NewCustomExerciseMuscularGroupModule(db))
 	protected.Mount("/custom-equipment", customequipmentmodule.NewCustomEquipmentModule(db))
 	protected.Mount("/custom-exercise", customexercisemodule.NewCustomExerciseModule(db))
+	protected.Mount("/custom-exercise-contraindication", customexercisecontraindicationmodule.NewCustomExerciseContraindicationModule(db))
 	protected.Mount("/custom-exercise-equipment", customexerciseequipmentmodule.NewCustomExerciseEquipmentModule(db))
 	protected.Mount("/custom-exercise-muscular-group", customexercisemuscgroupmodule.NewCustomExerciseMuscularGroupModule(db))
 	protected.Mount("/custom-member-workout", custommemberworkoutmodule.NewCustomMemberWorkoutModule(db))
 	protected.Mount("/custom-template-block", customtemplateblockmodule.NewCustomTemplateBlockModule(db))
 	protected.Mount("/custom-workout-instance", customworkoutinstancemodule.NewCustomWorkoutInstanceModule(db))
 	protected.Mount("/custom-workout-template", customworkouttemplatemodule.NewCustomWorkoutTemplateModule(db))
+	protected.Mount("/equipment-inventory", equipmentinventorymodule.NewEquipmentInventoryModule(db))
+	protected.Mount("/exercise-override", exerciseoverridemodule.NewExerciseOverrideModule(db))
 
 	r.Mount("/", protected)
 	return r
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/config"
+	"github.com/alejandro-albiol/athenai/internal/database"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/module"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/service"
+)
+
+const usage = `Usage:
+  exercise-library import -file <path> [-format json|csv] [-dry-run] [-gym <gym-id> -created-by <user-id>]
+  exercise-library export -file <path> [-format json|csv] [-gym <gym-id>]
+
+Without -gym the public library is used. The format defaults to the file extension.`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	file := flags.String("file", "", "path of the library document, - for stdin/stdout")
+	formatFlag := flags.String("format", "", "json or csv")
+	gymID := flags.String("gym", "", "gym ID to use the gym's custom library instead of the public one")
+	createdBy := flags.String("created-by", "", "user ID recorded as creator of new custom exercises")
+	dryRun := flags.Bool("dry-run", false, "report the changes without writing them")
+	flags.Parse(os.Args[2:])
+
+	if *file == "" {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	format := resolveFormat(*formatFlag, *file)
+	if !format.IsValid() {
+		log.Fatalf("❌ Invalid format %q, must be json or csv", format)
+	}
+
+	// Load environment variables
+	config.LoadEnv()
+
+	// Initialize database connection
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	libraryService := module.NewExerciseLibraryService(db)
+
+	switch os.Args[1] {
+	case "import":
+		runImport(libraryService, format, *file, *gymID, *createdBy, *dryRun)
+	case "export":
+		runExport(libraryService, format, *file, *gymID)
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func runImport(libraryService *service.ExerciseLibraryService, format enum.LibraryFormat, file, gymID, createdBy string, dryRun bool) {
+	var input io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Fatalf("❌ Failed to open %s: %v", file, err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	doc, err := service.DecodeLibrary(format, input)
+	if err != nil {
+		log.Fatalf("❌ Failed to read library document: %v", err)
+	}
+
+	var report *dto.ImportReport
+	if gymID != "" {
+		report, err = libraryService.ImportGymLibrary(gymID, createdBy, doc, dryRun)
+	} else {
+		var creator *string
+		if createdBy != "" {
+			creator = &createdBy
+		}
+		report, err = libraryService.ImportPublicLibrary(doc, creator, dryRun)
+	}
+	if err != nil {
+		log.Fatalf("❌ Import failed: %v", err)
+	}
+
+	for _, change := range report.Changes {
+		if change.Action == string(enum.Unchanged) {
+			continue
+		}
+		line := fmt.Sprintf("  %-7s %-15s %s", change.Action, change.Entity, change.Name)
+		if len(change.Fields) > 0 {
+			line += " (" + strings.Join(change.Fields, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+	for _, rowErr := range report.Errors {
+		fmt.Printf("  ❌ %s row %d %q: %s\n", rowErr.Entity, rowErr.Row, rowErr.Name, rowErr.Message)
+	}
+	fmt.Printf("📋 %d created, %d updated, %d unchanged, %d errors\n", report.Created, report.Updated, report.Unchanged, len(report.Errors))
+
+	switch {
+	case len(report.Errors) > 0:
+		fmt.Println("❌ Nothing was imported, fix the errors above and retry.")
+		os.Exit(1)
+	case report.DryRun:
+		fmt.Println("🔍 Dry run, nothing was imported.")
+	default:
+		fmt.Println("✅ Exercise library imported successfully.")
+	}
+}
+
+func runExport(libraryService *service.ExerciseLibraryService, format enum.LibraryFormat, file, gymID string) {
+	var doc *dto.LibraryDocument
+	var err error
+	if gymID != "" {
+		doc, err = libraryService.ExportGymLibrary(gymID)
+	} else {
+		doc, err = libraryService.ExportPublicLibrary()
+	}
+	if err != nil {
+		log.Fatalf("❌ Export failed: %v", err)
+	}
+
+	var output io.Writer = os.Stdout
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			log.Fatalf("❌ Failed to create %s: %v", file, err)
+		}
+		defer f.Close()
+		output = f
+	}
+	if err := service.EncodeLibrary(format, output, doc); err != nil {
+		log.Fatalf("❌ Failed to write library document: %v", err)
+	}
+	if file != "-" {
+		fmt.Printf("✅ Exported %d exercises to %s\n", len(doc.Exercises), file)
+	}
+}
+
+// resolveFormat prefers the explicit flag and falls back to the file extension
+func resolveFormat(flagValue, file string) enum.LibraryFormat {
+	if flagValue != "" {
+		return enum.LibraryFormat(flagValue)
+	}
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		return enum.CSV
+	}
+	return enum.JSON
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	_ "time/tzdata" // gym timezones resolve even on images without zoneinfo
 
 	"github.com/alejandro-albiol/athenai/api"
 	"github.com/alejandro-albiol/athenai/config"
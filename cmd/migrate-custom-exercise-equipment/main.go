@@ -0,0 +1,97 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alejandro-albiol/athenai/config"
+	"github.com/alejandro-albiol/athenai/internal/database"
+	"github.com/lib/pq"
+)
+
+func main() {
+	log.Println("=== AthenAI Database Migration: Custom Exercise Equipment ===")
+	log.Println("This will let custom exercises link to public equipment or to the gym's custom equipment")
+
+	// Load environment variables
+	config.LoadEnv()
+
+	// Initialize database connection
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	schemas, err := database.ListTenantSchemas(db)
+	if err != nil {
+		log.Fatalf("Failed to list tenant schemas: %v", err)
+	}
+
+	migrated := 0
+	for _, schema := range schemas {
+		done, err := migrateSchema(db, schema)
+		if err != nil {
+			log.Fatalf("❌ Failed to migrate %s: %v", schema, err)
+		}
+		if done {
+			log.Printf("✅ %s migrated", schema)
+			migrated++
+		} else {
+			log.Printf("⏭️  %s already up to date", schema)
+		}
+	}
+
+	log.Printf("🎉 Migration completed successfully! %d of %d tenant schemas migrated", migrated, len(schemas))
+}
+
+// migrateSchema moves the legacy equipment_id links to public_equipment_id in one transaction.
+// Schemas without the legacy column are left untouched.
+func migrateSchema(db *sql.DB, schemaName string) (bool, error) {
+	var legacy bool
+	err := db.QueryRow(`SELECT EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = 'custom_exercise_equipment' AND column_name = 'equipment_id')`, schemaName).Scan(&legacy)
+	if err != nil || !legacy {
+		return false, err
+	}
+
+	schema := pq.QuoteIdentifier(schemaName)
+	table := schema + ".custom_exercise_equipment"
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE %s
+			ADD COLUMN IF NOT EXISTS id UUID NOT NULL DEFAULT gen_random_uuid(),
+			ADD COLUMN IF NOT EXISTS equipment_source TEXT NOT NULL DEFAULT 'public',
+			ADD COLUMN IF NOT EXISTS public_equipment_id UUID REFERENCES public.equipment(id) ON DELETE RESTRICT,
+			ADD COLUMN IF NOT EXISTS gym_equipment_id UUID REFERENCES %s.custom_equipment(id) ON DELETE RESTRICT,
+			ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT NOW()`, table, schema),
+		fmt.Sprintf(`UPDATE %s SET public_equipment_id = equipment_id`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS custom_exercise_equipment_pkey`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN equipment_id`, table),
+		fmt.Sprintf(`ALTER TABLE %s
+			ALTER COLUMN equipment_source DROP DEFAULT,
+			ADD PRIMARY KEY (id),
+			ADD CONSTRAINT custom_exercise_equipment_source_check CHECK (equipment_source IN ('public', 'gym')),
+			ADD CONSTRAINT custom_exercise_equipment_reference_check CHECK (
+				(equipment_source = 'public' AND public_equipment_id IS NOT NULL AND gym_equipment_id IS NULL) OR
+				(equipment_source = 'gym' AND gym_equipment_id IS NOT NULL AND public_equipment_id IS NULL)
+			),
+			ADD UNIQUE (custom_exercise_id, public_equipment_id),
+			ADD UNIQUE (custom_exercise_id, gym_equipment_id)`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s(public_equipment_id)`, pq.QuoteIdentifier("idx_"+schemaName+"_custom_exercise_equipment_public"), table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s(gym_equipment_id)`, pq.QuoteIdentifier("idx_"+schemaName+"_custom_exercise_equipment_gym"), table),
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
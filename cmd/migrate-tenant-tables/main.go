@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/alejandro-albiol/athenai/config"
+	"github.com/alejandro-albiol/athenai/internal/database"
+)
+
+func main() {
+	log.Println("=== AthenAI Database Migration: Tenant Tables ===")
+	log.Println("This will create tenant tables and indexes added since each gym was created, such as the equipment inventory")
+
+	// Load environment variables
+	config.LoadEnv()
+
+	// Initialize database connection
+	db, err := database.NewPostgresDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	schemas, err := database.ListTenantSchemas(db)
+	if err != nil {
+		log.Fatalf("Failed to list tenant schemas: %v", err)
+	}
+
+	// Every statement of the tenant schema is IF NOT EXISTS, so existing tables and data are left alone
+	for _, schema := range schemas {
+		if err := database.CreateTenantSchema(db, &schema); err != nil {
+			log.Fatalf("❌ Failed to migrate %s: %v", schema, err)
+		}
+		log.Printf("✅ %s up to date", schema)
+	}
+
+	log.Printf("🎉 Migration completed successfully! %d tenant schemas checked", len(schemas))
+}
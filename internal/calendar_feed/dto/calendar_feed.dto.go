@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// CreateCalendarFeedDTO asks for a feed URL. Asking again for the same scope rotates the URL, and the
+// previous one stops working.
+type CreateCalendarFeedDTO struct {
+	Scope string `json:"scope"` // member (default) or trainer
+}
+
+// CalendarFeedDTO is an active feed; FeedURL is only given when the feed is created, as only a hash of its token is kept
+type CalendarFeedDTO struct {
+	ID         string     `json:"id"`
+	Scope      string     `json:"scope"`
+	FeedURL    string     `json:"feed_url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // last time a calendar fetched the feed
+}
+
+// FeedToken is the active token a feed URL resolves to
+type FeedToken struct {
+	ID     string
+	GymID  string
+	UserID string
+	Scope  string
+}
+
+// FeedUser is the owner of a feed
+type FeedUser struct {
+	Username string
+	Role     string
+}
+
+// FeedWorkout is a member workout shown in a feed
+type FeedWorkout struct {
+	ID                string
+	MemberID          string
+	MemberName        string
+	WorkoutInstanceID string
+	Status            string
+	ScheduledDate     *string    // YYYY-MM-DD
+	ScheduledAt       *time.Time // start time, when the workout has one
+	ScheduleID        *string
+	OccurrenceDate    *string // rule date of a scheduled workout, YYYY-MM-DD
+	UpdatedAt         time.Time
+}
+
+// FeedSchedule is a recurring schedule shown in a feed as one recurring event
+type FeedSchedule struct {
+	ID                string
+	MemberID          string
+	MemberName        string
+	WorkoutInstanceID string
+	RRule             string
+	StartDate         string // YYYY-MM-DD
+	StartTime         string // HH:MM
+	Timezone          string
+	ModifiedDates     []string // rule dates of occurrences edited on their own
+	UpdatedAt         time.Time
+}
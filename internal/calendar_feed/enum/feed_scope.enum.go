@@ -0,0 +1,17 @@
+package enum
+
+// FeedScope is whose workouts a calendar feed shows
+type FeedScope string
+
+const (
+	Member  FeedScope = "member"  // the member's own workouts
+	Trainer FeedScope = "trainer" // the workouts the trainer assigned or scheduled for their members
+)
+
+func (s FeedScope) IsValid() bool {
+	switch s {
+	case Member, Trainer:
+		return true
+	}
+	return false
+}
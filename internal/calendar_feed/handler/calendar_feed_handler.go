@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/enum"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarFeedHandler struct {
+	service interfaces.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(service interfaces.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: service}
+}
+
+func (h *CalendarFeedHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	var feed dto.CreateCalendarFeedDTO
+	if err := json.NewDecoder(r.Body).Decode(&feed); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	if enum.FeedScope(feed.Scope) == enum.Trainer && !canCoach(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators and trainers can follow member sessions", nil))
+		return
+	}
+
+	created, err := h.service.CreateFeed(middleware.GetGymID(r), middleware.GetUserID(r), &feed)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to create calendar feed")
+		return
+	}
+	response.WriteAPICreated(w, "Calendar feed created successfully", created)
+}
+
+func (h *CalendarFeedHandler) ListFeeds(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	feeds, err := h.service.ListFeeds(middleware.GetGymID(r), middleware.GetUserID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list calendar feeds")
+		return
+	}
+	response.WriteAPISuccess(w, "Calendar feeds retrieved successfully", feeds)
+}
+
+func (h *CalendarFeedHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	if err := h.service.RevokeFeed(middleware.GetGymID(r), middleware.GetUserID(r), chi.URLParam(r, "feedID")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to revoke calendar feed")
+		return
+	}
+	response.WriteAPISuccess(w, "Calendar feed revoked successfully", nil)
+}
+
+// ServeFeed writes the iCalendar feed of a token. It is mounted outside the auth middleware so calendar
+// apps can subscribe to it; the token in the URL is the credential.
+func (h *CalendarFeedHandler) ServeFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.RenderFeed(chi.URLParam(r, "token"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to render calendar feed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="athenai.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed)
+}
+
+// requireGymUser writes a 400 unless the request is scoped to a gym
+func requireGymUser(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetGymID(r) != "" && middleware.GetUserID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Calendar feeds belong to a gym user", nil))
+	return false
+}
+
+func canCoach(r *http.Request) bool {
+	return middleware.IsGymAdmin(r) || middleware.GetUserRole(r) == string(user_enum.Trainer)
+}
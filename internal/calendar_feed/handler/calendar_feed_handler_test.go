@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/router"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.CalendarFeedService
+	created  *dto.CreateCalendarFeedDTO
+	userID   string
+	rendered string
+}
+
+func (m *mockService) CreateFeed(gymID, userID string, feed *dto.CreateCalendarFeedDTO) (*dto.CalendarFeedDTO, error) {
+	m.created, m.userID = feed, userID
+	return &dto.CalendarFeedDTO{ID: "feed-1", Scope: feed.Scope, FeedURL: "https://api.example.com/calendar/abc.ics"}, nil
+}
+func (m *mockService) RenderFeed(token string) ([]byte, error) {
+	m.rendered = token
+	if token != "abc" {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Calendar feed not found", nil)
+	}
+	return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+}
+
+func serve(svc *mockService, method, target, body, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewCalendarFeedRouter(NewCalendarFeedHandler(svc)),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, method, target, body)
+}
+
+func TestOnlyTrainersGetMemberSessionFeeds(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/", `{"scope":"trainer"}`, "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.created)
+
+	w = serve(svc, http.MethodPost, "/", `{"scope":"trainer"}`, "coach-1", "trainer")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "coach-1", svc.userID)
+	assert.Contains(t, w.Body.String(), `"feed_url":"https://api.example.com/calendar/abc.ics"`)
+
+	// An empty body asks for the member's own feed
+	w = serve(svc, http.MethodPost, "/", "", "member-1", "member")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "", svc.created.Scope)
+}
+
+func TestServeFeedWithoutAuthentication(t *testing.T) {
+	svc := &mockService{}
+	feeds := router.NewFeedFileRouter(NewCalendarFeedHandler(svc))
+
+	w := httptest.NewRecorder()
+	feeds.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abc.ics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", svc.rendered)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	feeds.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/revoked.ics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
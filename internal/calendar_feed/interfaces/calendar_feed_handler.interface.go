@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type CalendarFeedHandler interface {
+	CreateFeed(w http.ResponseWriter, r *http.Request)
+	ListFeeds(w http.ResponseWriter, r *http.Request)
+	RevokeFeed(w http.ResponseWriter, r *http.Request)
+	ServeFeed(w http.ResponseWriter, r *http.Request)
+}
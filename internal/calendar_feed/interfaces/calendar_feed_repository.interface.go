@@ -0,0 +1,23 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+
+type CalendarFeedRepository interface {
+	// CreateToken revokes the user's active token for the scope and saves the new token hash
+	CreateToken(gymID, userID, scope, tokenHash string) (*dto.CalendarFeedDTO, error)
+	FindActiveByUser(gymID, userID string) ([]*dto.CalendarFeedDTO, error)
+	// Revoke revokes an active token of the user; sql.ErrNoRows when there is none
+	Revoke(gymID, userID, feedID string) error
+	// UseToken resolves an active token hash and records that it was used; sql.ErrNoRows when there is none
+	UseToken(tokenHash string) (*dto.FeedToken, error)
+
+	// FindUser finds an active user of the gym; sql.ErrNoRows when the user is gone or deactivated
+	FindUser(gymID, userID string) (*dto.FeedUser, error)
+	// FindWorkouts lists the workouts outside schedules from one date to another, of the member or created by
+	// the trainer depending on the scope
+	FindWorkouts(gymID, scope, userID, fromDate, toDate string) ([]dto.FeedWorkout, error)
+	// FindSchedules lists the schedules, of the member or created by the trainer, with occurrences from fromDate
+	FindSchedules(gymID, scope, userID, fromDate string) ([]dto.FeedSchedule, error)
+	// FindScheduleWorkouts lists every workout of the schedules
+	FindScheduleWorkouts(gymID string, scheduleIDs []string) ([]dto.FeedWorkout, error)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+
+type CalendarFeedService interface {
+	CreateFeed(gymID, userID string, feed *dto.CreateCalendarFeedDTO) (*dto.CalendarFeedDTO, error)
+	ListFeeds(gymID, userID string) ([]*dto.CalendarFeedDTO, error)
+	RevokeFeed(gymID, userID, feedID string) error
+	// RenderFeed writes the iCalendar document of the feed a token belongs to
+	RenderFeed(token string) ([]byte, error)
+}
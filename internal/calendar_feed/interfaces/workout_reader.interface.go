@@ -0,0 +1,8 @@
+package interfaces
+
+import instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+
+// WorkoutReader gives a workout instance with its exercises and timed blocks, to describe the feed's events
+type WorkoutReader interface {
+	GetByID(gymID, id string) (*instanceDTO.ResponseCustomWorkoutInstanceDTO, error)
+}
@@ -0,0 +1,49 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+	"os"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/handler"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/repository"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/router"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/service"
+	instance_repository "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/repository"
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+)
+
+// Defaults used when the calendar variables are not set; calendar apps need absolute URLs
+const (
+	defaultFeedBaseURL = "http://localhost:8080/api/v1/calendar"
+	defaultAppBaseURL  = "http://localhost:3000"
+)
+
+// CalendarFeedModule holds the authenticated router that manages feeds and the public router that serves them
+type CalendarFeedModule struct {
+	Router     http.Handler
+	FeedRouter http.Handler
+}
+
+// NewCalendarFeedModule builds feed URLs under CALENDAR_FEED_BASE_URL and links events to APP_BASE_URL
+func NewCalendarFeedModule(db *sql.DB) *CalendarFeedModule {
+	service := service.NewCalendarFeedService(
+		repository.NewCalendarFeedRepository(db),
+		gym_repository.NewGymRepository(db),
+		instance_repository.NewCustomWorkoutInstanceRepository(db),
+		getEnv("CALENDAR_FEED_BASE_URL", defaultFeedBaseURL),
+		getEnv("APP_BASE_URL", defaultAppBaseURL),
+	)
+	handler := handler.NewCalendarFeedHandler(service)
+	return &CalendarFeedModule{
+		Router:     router.NewCalendarFeedRouter(handler),
+		FeedRouter: router.NewFeedFileRouter(handler),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/enum"
+	"github.com/lib/pq"
+)
+
+type CalendarFeedRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarFeedRepository(db *sql.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func (r *CalendarFeedRepository) CreateToken(gymID, userID, scope, tokenHash string) (*dto.CalendarFeedDTO, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE public.calendar_feed_token SET revoked_at = NOW()
+		WHERE gym_id = $1 AND user_id = $2 AND scope = $3 AND revoked_at IS NULL`, gymID, userID, scope); err != nil {
+		return nil, err
+	}
+	feed := dto.CalendarFeedDTO{Scope: scope}
+	if err := tx.QueryRow(`INSERT INTO public.calendar_feed_token (token_hash, gym_id, user_id, scope)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`, tokenHash, gymID, userID, scope).Scan(&feed.ID, &feed.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &feed, tx.Commit()
+}
+
+func (r *CalendarFeedRepository) FindActiveByUser(gymID, userID string) ([]*dto.CalendarFeedDTO, error) {
+	rows, err := r.db.Query(`SELECT id, scope, created_at, last_used_at FROM public.calendar_feed_token
+		WHERE gym_id = $1 AND user_id = $2 AND revoked_at IS NULL ORDER BY scope`, gymID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	feeds := []*dto.CalendarFeedDTO{}
+	for rows.Next() {
+		var feed dto.CalendarFeedDTO
+		if err := rows.Scan(&feed.ID, &feed.Scope, &feed.CreatedAt, &feed.LastUsedAt); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, &feed)
+	}
+	return feeds, rows.Err()
+}
+
+func (r *CalendarFeedRepository) Revoke(gymID, userID, feedID string) error {
+	result, err := r.db.Exec(`UPDATE public.calendar_feed_token SET revoked_at = NOW()
+		WHERE id = $1 AND gym_id = $2 AND user_id = $3 AND revoked_at IS NULL`, feedID, gymID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CalendarFeedRepository) UseToken(tokenHash string) (*dto.FeedToken, error) {
+	var token dto.FeedToken
+	err := r.db.QueryRow(`UPDATE public.calendar_feed_token SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING id, gym_id, user_id, scope`, tokenHash).Scan(&token.ID, &token.GymID, &token.UserID, &token.Scope)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *CalendarFeedRepository) FindUser(gymID, userID string) (*dto.FeedUser, error) {
+	var user dto.FeedUser
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT username, role FROM %s.user WHERE id = $1 AND is_active`,
+		pq.QuoteIdentifier(gymID)), userID).Scan(&user.Username, &user.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ownerColumn is the column that ties rows to the feed's user: members follow their own workouts,
+// trainers the ones they created
+func ownerColumn(scope string) string {
+	if enum.FeedScope(scope) == enum.Trainer {
+		return "created_by"
+	}
+	return "member_id"
+}
+
+const selectWorkouts = `SELECT w.id, w.member_id, COALESCE(u.username, ''), w.workout_instance_id, w.status,
+	to_char(w.scheduled_date, 'YYYY-MM-DD'), w.scheduled_at, w.schedule_id, to_char(w.occurrence_date, 'YYYY-MM-DD'), w.updated_at
+	FROM %[1]s.custom_member_workout w LEFT JOIN %[1]s.user u ON u.id = w.member_id`
+
+func (r *CalendarFeedRepository) FindWorkouts(gymID, scope, userID, fromDate, toDate string) ([]dto.FeedWorkout, error) {
+	return r.queryWorkouts(fmt.Sprintf(selectWorkouts+` WHERE w.%[2]s = $1 AND w.schedule_id IS NULL
+		AND w.scheduled_date BETWEEN $2 AND $3 ORDER BY w.scheduled_date, w.scheduled_at`,
+		pq.QuoteIdentifier(gymID), ownerColumn(scope)), userID, fromDate, toDate)
+}
+
+func (r *CalendarFeedRepository) FindScheduleWorkouts(gymID string, scheduleIDs []string) ([]dto.FeedWorkout, error) {
+	return r.queryWorkouts(fmt.Sprintf(selectWorkouts+` WHERE w.schedule_id = ANY($1) ORDER BY w.occurrence_date`,
+		pq.QuoteIdentifier(gymID)), pq.Array(scheduleIDs))
+}
+
+func (r *CalendarFeedRepository) queryWorkouts(query string, args ...any) ([]dto.FeedWorkout, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	workouts := []dto.FeedWorkout{}
+	for rows.Next() {
+		var workout dto.FeedWorkout
+		if err := rows.Scan(&workout.ID, &workout.MemberID, &workout.MemberName, &workout.WorkoutInstanceID, &workout.Status,
+			&workout.ScheduledDate, &workout.ScheduledAt, &workout.ScheduleID, &workout.OccurrenceDate, &workout.UpdatedAt); err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, workout)
+	}
+	return workouts, rows.Err()
+}
+
+func (r *CalendarFeedRepository) FindSchedules(gymID, scope, userID, fromDate string) ([]dto.FeedSchedule, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT s.id, s.member_id, COALESCE(u.username, ''), s.workout_instance_id, s.rrule,
+			to_char(s.start_date, 'YYYY-MM-DD'), to_char(s.start_time, 'HH24:MI'), s.timezone,
+			ARRAY(SELECT to_char(e.occurrence_date, 'YYYY-MM-DD') FROM %[1]s.workout_schedule_exception e
+				WHERE e.schedule_id = s.id AND e.kind = 'modified' ORDER BY e.occurrence_date),
+			s.updated_at
+		FROM %[1]s.workout_schedule s LEFT JOIN %[1]s.user u ON u.id = s.member_id
+		WHERE s.%[2]s = $1 AND EXISTS (
+			SELECT 1 FROM %[1]s.custom_member_workout w WHERE w.schedule_id = s.id AND w.occurrence_date >= $2
+		)
+		ORDER BY s.start_date, s.id`, pq.QuoteIdentifier(gymID), ownerColumn(scope)), userID, fromDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	schedules := []dto.FeedSchedule{}
+	for rows.Next() {
+		var schedule dto.FeedSchedule
+		if err := rows.Scan(&schedule.ID, &schedule.MemberID, &schedule.MemberName, &schedule.WorkoutInstanceID, &schedule.RRule,
+			&schedule.StartDate, &schedule.StartTime, &schedule.Timezone, pq.Array(&schedule.ModifiedDates), &schedule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
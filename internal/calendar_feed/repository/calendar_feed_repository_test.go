@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTokenRevokesThePreviousOne(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewCalendarFeedRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE public.calendar_feed_token SET revoked_at = NOW\(\)`).
+		WithArgs("gym-1", "member-1", "member").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO public.calendar_feed_token`).
+		WithArgs("hash", "gym-1", "member-1", "member").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("feed-1", now))
+	mock.ExpectCommit()
+
+	feed, err := repo.CreateToken("gym-1", "member-1", "member", "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "feed-1", feed.ID)
+	assert.Equal(t, "member", feed.Scope)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseTokenOnlyResolvesActiveTokens(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewCalendarFeedRepository(db)
+
+	mock.ExpectQuery(`UPDATE public.calendar_feed_token SET last_used_at = NOW\(\)\s+WHERE token_hash = \$1 AND revoked_at IS NULL`).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "gym_id", "user_id", "scope"}).AddRow("feed-1", "gym-1", "coach-1", "trainer"))
+	token, err := repo.UseToken("hash")
+	require.NoError(t, err)
+	assert.Equal(t, "trainer", token.Scope)
+
+	mock.ExpectQuery(`UPDATE public.calendar_feed_token`).WithArgs("revoked").WillReturnError(sql.ErrNoRows)
+	_, err = repo.UseToken("revoked")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	mock.ExpectExec(`UPDATE public.calendar_feed_token SET revoked_at`).
+		WithArgs("feed-9", "gym-1", "member-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Revoke("gym-1", "member-1", "feed-9"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrainerFeedsFollowTheWorkoutsTheyCreated(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewCalendarFeedRepository(db)
+	columns := []string{"id", "member_id", "username", "workout_instance_id", "status", "scheduled_date", "scheduled_at",
+		"schedule_id", "occurrence_date", "updated_at"}
+
+	mock.ExpectQuery(`FROM "gym-1".custom_member_workout w LEFT JOIN "gym-1".user u ON u.id = w.member_id WHERE w.created_by = \$1 AND w.schedule_id IS NULL`).
+		WithArgs("coach-1", "2026-07-21", "2027-10-19").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("workout-1", "member-1", "alice", "instance-1", "scheduled", "2026-10-21", nil, nil, nil, time.Now()))
+	workouts, err := repo.FindWorkouts("gym-1", "trainer", "coach-1", "2026-07-21", "2027-10-19")
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+	assert.Equal(t, "2026-10-21", *workouts[0].ScheduledDate)
+	assert.Nil(t, workouts[0].ScheduledAt)
+
+	mock.ExpectQuery(`FROM "gym-1".workout_schedule s LEFT JOIN "gym-1".user u ON u.id = s.member_id\s+WHERE s.member_id = \$1`).
+		WithArgs("member-1", "2026-07-21").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "member_id", "username", "workout_instance_id", "rrule", "start_date", "start_time",
+			"timezone", "modified", "updated_at"}).
+			AddRow("schedule-1", "member-1", "alice", "instance-1", "FREQ=DAILY;COUNT=3", "2026-10-19", "07:30", "Europe/Madrid", "{2026-10-20}", time.Now()))
+	schedules, err := repo.FindSchedules("gym-1", "member", "member-1", "2026-07-21")
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, []string{"2026-10-20"}, schedules[0].ModifiedDates)
+
+	mock.ExpectQuery(`WHERE w.schedule_id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"schedule-1"})).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.FindScheduleWorkouts("gym-1", []string{"schedule-1"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewCalendarFeedRouter(handler interfaces.CalendarFeedHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/", handler.CreateFeed)           // POST /calendar-feed, creates or rotates the feed URL of a scope
+	r.Get("/", handler.ListFeeds)             // GET /calendar-feed
+	r.Delete("/{feedID}", handler.RevokeFeed) // DELETE /calendar-feed/{feedID}
+	return r
+}
+
+// NewFeedFileRouter serves feeds to calendar apps and must be mounted without the auth middleware
+func NewFeedFileRouter(handler interfaces.CalendarFeedHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/{token}.ics", handler.ServeFeed) // GET /calendar/{token}.ics
+	return r
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/enum"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/interfaces"
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	blockEnum "github.com/alejandro-albiol/athenai/internal/template_block/enum"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/ical"
+	"github.com/alejandro-albiol/athenai/pkg/rrule"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	prodID     = "-//AthenAI//Workout Calendar//EN"
+	uidDomain  = "@athenai"
+	// Feeds show workouts outside schedules from pastDays ago to futureDays ahead, and every schedule
+	// with occurrences in that span
+	pastDays   = 90
+	futureDays = 365
+	// defaultDuration is used for workouts without exercises to estimate their length from
+	defaultDuration = time.Hour
+	// maxOccurrences matches the cap schedules are created with
+	maxOccurrences = 366
+)
+
+// blockModeLabels name the timed block formats in event descriptions
+var blockModeLabels = map[blockEnum.BlockMode]string{
+	blockEnum.EMOM:      "EMOM",
+	blockEnum.AMRAP:     "AMRAP",
+	blockEnum.Tabata:    "Tabata",
+	blockEnum.ForTime:   "For Time",
+	blockEnum.Intervals: "Intervals",
+}
+
+type CalendarFeedService struct {
+	repo        interfaces.CalendarFeedRepository
+	gyms        gymIF.GymRepository
+	workouts    interfaces.WorkoutReader
+	feedBaseURL string
+	appBaseURL  string
+	now         func() time.Time
+}
+
+// NewCalendarFeedService builds feed URLs under feedBaseURL and links events to the app under appBaseURL
+func NewCalendarFeedService(
+	repo interfaces.CalendarFeedRepository,
+	gyms gymIF.GymRepository,
+	workouts interfaces.WorkoutReader,
+	feedBaseURL, appBaseURL string,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		repo:        repo,
+		gyms:        gyms,
+		workouts:    workouts,
+		feedBaseURL: strings.TrimRight(feedBaseURL, "/"),
+		appBaseURL:  strings.TrimRight(appBaseURL, "/"),
+		now:         time.Now,
+	}
+}
+
+// CreateFeed gives the user a new feed URL for the scope, revoking the previous one
+func (s *CalendarFeedService) CreateFeed(gymID, userID string, feed *dto.CreateCalendarFeedDTO) (*dto.CalendarFeedDTO, error) {
+	scope := enum.FeedScope(feed.Scope)
+	if scope == "" {
+		scope = enum.Member
+	}
+	if !scope.IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "scope must be member or trainer", nil)
+	}
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to generate feed token", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	created, err := s.repo.CreateToken(gymID, userID, string(scope), hashToken(token))
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create calendar feed", err)
+	}
+	created.FeedURL = s.feedBaseURL + "/" + token + ".ics"
+	return created, nil
+}
+
+func (s *CalendarFeedService) ListFeeds(gymID, userID string) ([]*dto.CalendarFeedDTO, error) {
+	feeds, err := s.repo.FindActiveByUser(gymID, userID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list calendar feeds", err)
+	}
+	return feeds, nil
+}
+
+func (s *CalendarFeedService) RevokeFeed(gymID, userID, feedID string) error {
+	if err := s.repo.Revoke(gymID, userID, feedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Calendar feed not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to revoke calendar feed", err)
+	}
+	return nil
+}
+
+// RenderFeed resolves the token and writes the feed. Revoked tokens, deactivated users and trainer feeds of
+// users who no longer coach are all reported as not found.
+func (s *CalendarFeedService) RenderFeed(token string) ([]byte, error) {
+	notFound := apierror.New(errorcode_enum.CodeNotFound, "Calendar feed not found", nil)
+	if token == "" {
+		return nil, notFound
+	}
+	feed, err := s.repo.UseToken(hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read calendar feed", err)
+	}
+	user, err := s.repo.FindUser(feed.GymID, feed.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read calendar feed", err)
+	}
+	scope := enum.FeedScope(feed.Scope)
+	if scope == enum.Trainer && !canCoach(user.Role) {
+		return nil, notFound
+	}
+
+	gym, err := s.gyms.GetGymByID(feed.GymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym", err)
+	}
+	timezone := gym.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load gym timezone", err)
+	}
+
+	today := s.now().In(loc)
+	from, to := today.AddDate(0, 0, -pastDays).Format(dateLayout), today.AddDate(0, 0, futureDays).Format(dateLayout)
+	workouts, err := s.repo.FindWorkouts(feed.GymID, feed.Scope, feed.UserID, from, to)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list workouts", err)
+	}
+	schedules, err := s.repo.FindSchedules(feed.GymID, feed.Scope, feed.UserID, from)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list workout schedules", err)
+	}
+	scheduled := make(map[string][]dto.FeedWorkout)
+	if len(schedules) > 0 {
+		ids := make([]string, len(schedules))
+		for i, schedule := range schedules {
+			ids[i] = schedule.ID
+		}
+		rows, err := s.repo.FindScheduleWorkouts(feed.GymID, ids)
+		if err != nil {
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list scheduled workouts", err)
+		}
+		for _, row := range rows {
+			scheduled[*row.ScheduleID] = append(scheduled[*row.ScheduleID], row)
+		}
+	}
+
+	b := &feedBuilder{service: s, gymID: feed.GymID, trainer: scope == enum.Trainer, loc: loc, instances: make(map[string]*instanceDTO.ResponseCustomWorkoutInstanceDTO)}
+	for _, workout := range workouts {
+		if err := b.addWorkout(workout, loc); err != nil {
+			return nil, err
+		}
+	}
+	for _, schedule := range schedules {
+		if err := b.addSchedule(schedule, scheduled[schedule.ID]); err != nil {
+			return nil, err
+		}
+	}
+
+	name := gym.Name + " · My workouts"
+	if scope == enum.Trainer {
+		name = gym.Name + " · Member sessions"
+	}
+	cal := ical.Calendar{ProdID: prodID, Name: name, Timezone: timezone, Events: b.events}
+	return []byte(cal.String()), nil
+}
+
+// feedBuilder turns the workouts and schedules of a feed into events, reading each workout instance once
+type feedBuilder struct {
+	service   *CalendarFeedService
+	gymID     string
+	trainer   bool
+	loc       *time.Location
+	instances map[string]*instanceDTO.ResponseCustomWorkoutInstanceDTO
+	events    []ical.Event
+}
+
+// addWorkout adds a workout outside a schedule, or one that no longer falls on a date of its schedule's rule.
+// Workouts without a start time are all-day events.
+func (b *feedBuilder) addWorkout(workout dto.FeedWorkout, loc *time.Location) error {
+	event := ical.Event{UID: workout.ID + uidDomain, Stamp: workout.UpdatedAt}
+	switch {
+	case workout.ScheduledAt != nil:
+		event.Start = workout.ScheduledAt.In(loc)
+	case workout.ScheduledDate != nil:
+		day, err := time.Parse(dateLayout, *workout.ScheduledDate)
+		if err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Failed to read workout date", err)
+		}
+		event.Start, event.AllDay = day, true
+	default:
+		return nil
+	}
+	if err := b.describe(&event, workout.WorkoutInstanceID, workout.MemberName, workout.Status, b.service.appBaseURL+"/workouts/"+workout.ID); err != nil {
+		return err
+	}
+	b.events = append(b.events, event)
+	return nil
+}
+
+// addSchedule adds the schedule as one recurring event. Rule dates whose workout was cancelled or removed are
+// EXDATEs, and workouts edited on their own or no longer just scheduled override their occurrence.
+func (b *feedBuilder) addSchedule(schedule dto.FeedSchedule, workouts []dto.FeedWorkout) error {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = b.loc
+	}
+	rule, err := rrule.Parse(schedule.RRule)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to read workout schedule rule", err)
+	}
+	start, err := time.ParseInLocation(dateLayout+" 15:04", schedule.StartDate+" "+schedule.StartTime, loc)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to read workout schedule start", err)
+	}
+	dates, err := rule.Expand(start, maxOccurrences)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to expand workout schedule rule", err)
+	}
+
+	byDate := make(map[string]dto.FeedWorkout, len(workouts))
+	for _, workout := range workouts {
+		if workout.OccurrenceDate != nil {
+			byDate[*workout.OccurrenceDate] = workout
+		}
+	}
+	modified := make(map[string]bool, len(schedule.ModifiedDates))
+	for _, date := range schedule.ModifiedDates {
+		modified[date] = true
+	}
+
+	uid := "schedule-" + schedule.ID + uidDomain
+	var overrides []ical.Event
+	var exDates []time.Time
+	for _, date := range dates {
+		key := date.Format(dateLayout)
+		workout, ok := byDate[key]
+		delete(byDate, key)
+		if !ok || workout.Status == "cancelled" {
+			exDates = append(exDates, date)
+			continue
+		}
+		if !modified[key] && workout.Status == "scheduled" && workout.WorkoutInstanceID == schedule.WorkoutInstanceID &&
+			(workout.ScheduledAt == nil || workout.ScheduledAt.Equal(date)) {
+			continue
+		}
+		recurrenceID := date
+		override := ical.Event{UID: uid, Stamp: workout.UpdatedAt, RecurrenceID: &recurrenceID, Start: date}
+		if workout.ScheduledAt != nil {
+			override.Start = workout.ScheduledAt.In(loc)
+		}
+		if err := b.describe(&override, workout.WorkoutInstanceID, workout.MemberName, workout.Status, b.service.appBaseURL+"/workouts/"+workout.ID); err != nil {
+			return err
+		}
+		overrides = append(overrides, override)
+	}
+
+	// With a TZID on DTSTART, RFC 5545 requires UNTIL as a UTC time: a date-only UNTIL becomes the
+	// last second of that day in the schedule's timezone
+	if rule.Until != nil && rule.UntilIsDate {
+		until := time.Date(rule.Until.Year(), rule.Until.Month(), rule.Until.Day(), 23, 59, 59, 0, loc).UTC()
+		rule.Until, rule.UntilIsDate = &until, false
+	}
+	if len(dates) > 0 {
+		series := ical.Event{UID: uid, Stamp: schedule.UpdatedAt, Start: dates[0], RRule: rule.String(), ExDates: exDates}
+		if err := b.describe(&series, schedule.WorkoutInstanceID, schedule.MemberName, "scheduled", b.service.appBaseURL+"/schedules/"+schedule.ID); err != nil {
+			return err
+		}
+		b.events = append(b.events, series)
+		b.events = append(b.events, overrides...)
+	}
+	// Workouts carried over from the schedule this one continues keep rule dates of the previous rule
+	for _, workout := range workouts {
+		if workout.OccurrenceDate == nil {
+			continue
+		}
+		if _, ok := byDate[*workout.OccurrenceDate]; ok {
+			if err := b.addWorkout(workout, loc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describe fills the summary, description, duration, link and status of an event from its workout instance
+func (b *feedBuilder) describe(event *ical.Event, instanceID, memberName, status, link string) error {
+	instance, err := b.instance(instanceID)
+	if err != nil {
+		return err
+	}
+	summary, duration := "Workout", defaultDuration
+	var lines []string
+	if b.trainer && memberName != "" {
+		lines = append(lines, "Member: "+memberName)
+	}
+	lines = append(lines, "Status: "+strings.ReplaceAll(status, "_", " "))
+	if instance != nil {
+		summary = instance.Name
+		if instance.EstimatedDurationMinutes > 0 {
+			duration = time.Duration(instance.EstimatedDurationMinutes) * time.Minute
+		}
+		if blocks := blocksSummary(instance); len(blocks) > 0 {
+			lines = append(lines, "Blocks:")
+			lines = append(lines, blocks...)
+		}
+	}
+	if b.trainer && memberName != "" {
+		summary = memberName + ": " + summary
+	}
+	lines = append(lines, "Open in AthenAI: "+link)
+
+	event.Summary = summary
+	event.Description = strings.Join(lines, "\n")
+	event.URL = link
+	event.Status = ical.StatusConfirmed
+	if status == "cancelled" {
+		event.Status = ical.StatusCancelled
+	}
+	if !event.AllDay {
+		event.Duration = duration
+	}
+	return nil
+}
+
+// instance reads a workout instance once per feed; instances that were deleted give nil
+func (b *feedBuilder) instance(instanceID string) (*instanceDTO.ResponseCustomWorkoutInstanceDTO, error) {
+	if instance, ok := b.instances[instanceID]; ok {
+		return instance, nil
+	}
+	instance, err := b.service.workouts.GetByID(b.gymID, instanceID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout instance", err)
+	}
+	b.instances[instanceID] = instance
+	return instance, nil
+}
+
+// blocksSummary lists the blocks of a workout in exercise order with their exercise count and timed format,
+// such as "- Finisher (AMRAP): 3 exercises"
+func blocksSummary(instance *instanceDTO.ResponseCustomWorkoutInstanceDTO) []string {
+	formats := make(map[string]string, len(instance.TimedBlocks))
+	for _, block := range instance.TimedBlocks {
+		formats[block.BlockName] = blockModeLabels[block.Mode()]
+	}
+	var names []string
+	counts := make(map[string]int)
+	for _, exercise := range instance.Exercises {
+		if counts[exercise.BlockName] == 0 {
+			names = append(names, exercise.BlockName)
+		}
+		counts[exercise.BlockName]++
+	}
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		label := name
+		if format := formats[name]; format != "" {
+			label += " (" + format + ")"
+		}
+		noun := "exercises"
+		if counts[name] == 1 {
+			noun = "exercise"
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %d %s", label, counts[name], noun))
+	}
+	return lines
+}
+
+// canCoach reports whether a gym role can follow the sessions of the members it trains
+func canCoach(role string) bool {
+	return role == "admin" || user_enum.UserRole(role).CanManageWorkouts()
+}
+
+// hashToken is how tokens are stored, so a leaked table does not leak working feed URLs
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
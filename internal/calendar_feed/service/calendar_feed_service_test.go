@@ -0,0 +1,242 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/dto"
+	"github.com/alejandro-albiol/athenai/internal/calendar_feed/interfaces"
+	exerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	gymDTO "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.CalendarFeedRepository
+	tokenHash         string
+	token             *dto.FeedToken
+	user              *dto.FeedUser
+	workouts          []dto.FeedWorkout
+	schedules         []dto.FeedSchedule
+	scheduleWorkouts  []dto.FeedWorkout
+	from, to          string
+	scheduleIDsLoaded []string
+}
+
+func (m *mockRepository) CreateToken(gymID, userID, scope, tokenHash string) (*dto.CalendarFeedDTO, error) {
+	m.tokenHash = tokenHash
+	return &dto.CalendarFeedDTO{ID: "feed-1", Scope: scope}, nil
+}
+func (m *mockRepository) Revoke(gymID, userID, feedID string) error {
+	return sql.ErrNoRows
+}
+func (m *mockRepository) UseToken(tokenHash string) (*dto.FeedToken, error) {
+	if m.token == nil || tokenHash != m.tokenHash {
+		return nil, sql.ErrNoRows
+	}
+	return m.token, nil
+}
+func (m *mockRepository) FindUser(gymID, userID string) (*dto.FeedUser, error) {
+	if m.user == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.user, nil
+}
+func (m *mockRepository) FindWorkouts(gymID, scope, userID, fromDate, toDate string) ([]dto.FeedWorkout, error) {
+	m.from, m.to = fromDate, toDate
+	return m.workouts, nil
+}
+func (m *mockRepository) FindSchedules(gymID, scope, userID, fromDate string) ([]dto.FeedSchedule, error) {
+	return m.schedules, nil
+}
+func (m *mockRepository) FindScheduleWorkouts(gymID string, scheduleIDs []string) ([]dto.FeedWorkout, error) {
+	m.scheduleIDsLoaded = scheduleIDs
+	return m.scheduleWorkouts, nil
+}
+
+type mockGyms struct {
+	gymIF.GymRepository
+}
+
+func (m *mockGyms) GetGymByID(id string) (*gymDTO.GymResponseDTO, error) {
+	return &gymDTO.GymResponseDTO{ID: id, Name: "Iron Temple", Timezone: "Europe/Madrid"}, nil
+}
+
+type mockWorkouts struct {
+	reads int
+}
+
+func (m *mockWorkouts) GetByID(gymID, id string) (*instanceDTO.ResponseCustomWorkoutInstanceDTO, error) {
+	m.reads++
+	if id != "instance-1" {
+		return nil, sql.ErrNoRows
+	}
+	amrap := "amrap"
+	return &instanceDTO.ResponseCustomWorkoutInstanceDTO{
+		ID: id, Name: "Upper body", EstimatedDurationMinutes: 45,
+		Exercises: []exerciseDTO.ResponseCustomWorkoutExerciseDTO{
+			{BlockName: "Main"}, {BlockName: "Main"}, {BlockName: "Finisher"},
+		},
+		TimedBlocks: []instanceDTO.TimedBlockDTO{{BlockName: "Finisher", BlockTiming: templateBlockDTO.BlockTiming{BlockMode: &amrap}}},
+	}, nil
+}
+
+func newService(repo *mockRepository, workouts *mockWorkouts) *CalendarFeedService {
+	s := NewCalendarFeedService(repo, &mockGyms{}, workouts, "https://api.example.com/api/v1/calendar/", "https://app.example.com")
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// unfold joins folded lines back so assertions can look for whole properties
+func unfold(feed []byte) string {
+	return strings.ReplaceAll(string(feed), "\r\n ", "")
+}
+
+func TestCreateFeedStoresOnlyTheTokenHash(t *testing.T) {
+	repo := &mockRepository{}
+	s := newService(repo, &mockWorkouts{})
+
+	feed, err := s.CreateFeed("gym-1", "member-1", &dto.CreateCalendarFeedDTO{})
+	require.NoError(t, err)
+	assert.Equal(t, "member", feed.Scope)
+	require.True(t, strings.HasPrefix(feed.FeedURL, "https://api.example.com/api/v1/calendar/"))
+	require.True(t, strings.HasSuffix(feed.FeedURL, ".ics"))
+
+	token := strings.TrimSuffix(strings.TrimPrefix(feed.FeedURL, "https://api.example.com/api/v1/calendar/"), ".ics")
+	assert.Len(t, token, 64)
+	assert.Equal(t, hashToken(token), repo.tokenHash)
+	assert.NotContains(t, repo.tokenHash, token)
+
+	_, err = s.CreateFeed("gym-1", "member-1", &dto.CreateCalendarFeedDTO{Scope: "everyone"})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
+
+func TestRenderFeedRejectsUnknownAndRevokedTokens(t *testing.T) {
+	repo := &mockRepository{tokenHash: hashToken("secret"), token: &dto.FeedToken{GymID: "gym-1", UserID: "member-1", Scope: "member"}}
+	s := newService(repo, &mockWorkouts{})
+
+	_, err := s.RenderFeed("guess")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+
+	// The owner was deactivated
+	_, err = s.RenderFeed("secret")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+
+	// A trainer feed of someone who is now a plain member
+	repo.token.Scope, repo.user = "trainer", &dto.FeedUser{Username: "alice", Role: "member"}
+	_, err = s.RenderFeed("secret")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+
+	assert.Equal(t, errorcode_enum.CodeNotFound, s.RevokeFeed("gym-1", "member-1", "feed-9").(*apierror.APIError).Code)
+}
+
+func TestRenderFeedMapsSchedulesToRecurringEvents(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	updated := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		tokenHash: hashToken("secret"),
+		token:     &dto.FeedToken{GymID: "gym-1", UserID: "member-1", Scope: "member"},
+		user:      &dto.FeedUser{Username: "alice", Role: "member"},
+		workouts: []dto.FeedWorkout{
+			{ID: "workout-1", MemberID: "member-1", WorkoutInstanceID: "instance-1", Status: "scheduled", ScheduledDate: ptr("2026-10-21"), UpdatedAt: updated},
+			{ID: "workout-2", MemberID: "member-1", WorkoutInstanceID: "instance-gone", Status: "cancelled",
+				ScheduledDate: ptr("2026-10-23"), ScheduledAt: ptr(time.Date(2026, 10, 23, 16, 0, 0, 0, time.UTC)), UpdatedAt: updated},
+		},
+		schedules: []dto.FeedSchedule{{
+			ID: "schedule-1", MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4",
+			StartDate: "2026-10-19", StartTime: "07:30", Timezone: "Europe/Madrid", ModifiedDates: []string{"2026-10-22"}, UpdatedAt: updated,
+		}},
+		scheduleWorkouts: []dto.FeedWorkout{
+			{ID: "occ-1", WorkoutInstanceID: "instance-1", Status: "completed", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-19"),
+				ScheduledAt: ptr(time.Date(2026, 10, 19, 7, 30, 0, 0, madrid)), UpdatedAt: updated},
+			{ID: "occ-2", WorkoutInstanceID: "instance-1", Status: "scheduled", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-22"),
+				ScheduledAt: ptr(time.Date(2026, 10, 22, 18, 0, 0, 0, madrid)), UpdatedAt: updated},
+			{ID: "occ-3", WorkoutInstanceID: "instance-1", Status: "cancelled", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-26"),
+				ScheduledAt: ptr(time.Date(2026, 10, 26, 7, 30, 0, 0, madrid)), UpdatedAt: updated},
+			// Carried over from the schedule this one continues, on a date its rule does not produce
+			{ID: "occ-0", WorkoutInstanceID: "instance-1", Status: "scheduled", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-20"),
+				ScheduledAt: ptr(time.Date(2026, 10, 20, 9, 0, 0, 0, madrid)), UpdatedAt: updated},
+		},
+	}
+	workouts := &mockWorkouts{}
+	feed, err := newService(repo, workouts).RenderFeed("secret")
+	require.NoError(t, err)
+	out := unfold(feed)
+
+	assert.Equal(t, "2026-07-21", repo.from)
+	assert.Equal(t, "2027-10-19", repo.to)
+	assert.Equal(t, []string{"schedule-1"}, repo.scheduleIDsLoaded)
+	assert.Contains(t, out, "X-WR-CALNAME:Iron Temple · My workouts\r\n")
+	assert.Contains(t, out, "X-WR-TIMEZONE:Europe/Madrid\r\n")
+
+	// The standalone workouts: an all-day one and a cancelled one whose instance was deleted
+	assert.Contains(t, out, "UID:workout-1@athenai\r\nDTSTAMP:20261018T120000Z\r\nDTSTART;VALUE=DATE:20261021\r\n")
+	assert.Contains(t, out, "SUMMARY:Upper body\r\nDESCRIPTION:Status: scheduled\\nBlocks:\\n- Main: 2 exercises\\n- Finisher (AMRAP): 1 exercise\\nOpen in AthenAI: https://app.example.com/workouts/workout-1\r\n")
+	assert.Contains(t, out, "UID:workout-2@athenai\r\nDTSTAMP:20261018T120000Z\r\nDTSTART;TZID=Europe/Madrid:20261023T180000\r\nDURATION:PT1H\r\nSUMMARY:Workout\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+
+	// The series, with the cancelled and the missing occurrence excluded
+	assert.Contains(t, out, "UID:schedule-schedule-1@athenai\r\nDTSTAMP:20261018T120000Z\r\nDTSTART;TZID=Europe/Madrid:20261019T073000\r\nDURATION:PT45M\r\nRRULE:FREQ=WEEKLY;COUNT=4;BYDAY=MO,TH\r\n"+
+		"EXDATE;TZID=Europe/Madrid:20261026T073000\r\nEXDATE;TZID=Europe/Madrid:20261029T073000\r\n")
+	assert.Contains(t, out, "URL:https://app.example.com/schedules/schedule-1\r\n")
+
+	// Overrides for the completed occurrence and the one moved to the evening
+	assert.Contains(t, out, "RECURRENCE-ID;TZID=Europe/Madrid:20261019T073000\r\nDTSTART;TZID=Europe/Madrid:20261019T073000\r\n")
+	assert.Contains(t, out, "DESCRIPTION:Status: completed\\n")
+	assert.Contains(t, out, "RECURRENCE-ID;TZID=Europe/Madrid:20261022T073000\r\nDTSTART;TZID=Europe/Madrid:20261022T180000\r\n")
+	assert.Equal(t, 2, strings.Count(out, "RECURRENCE-ID"))
+
+	// The carried over occurrence is an event of its own
+	assert.Contains(t, out, "UID:occ-0@athenai\r\nDTSTAMP:20261018T120000Z\r\nDTSTART;TZID=Europe/Madrid:20261020T090000\r\n")
+	assert.Equal(t, 6, strings.Count(out, "BEGIN:VEVENT"))
+	assert.Equal(t, 2, workouts.reads, "each instance is read once")
+}
+
+func TestRenderFeedWritesDateUntilAsUTCTime(t *testing.T) {
+	repo := &mockRepository{
+		tokenHash: hashToken("secret"),
+		token:     &dto.FeedToken{GymID: "gym-1", UserID: "member-1", Scope: "member"},
+		user:      &dto.FeedUser{Username: "alice", Role: "member"},
+		schedules: []dto.FeedSchedule{{
+			ID: "schedule-1", MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "FREQ=DAILY;UNTIL=20261020",
+			StartDate: "2026-10-19", StartTime: "07:30", Timezone: "Europe/Madrid",
+		}},
+		scheduleWorkouts: []dto.FeedWorkout{
+			{ID: "occ-1", WorkoutInstanceID: "instance-1", Status: "scheduled", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-19")},
+			{ID: "occ-2", WorkoutInstanceID: "instance-1", Status: "scheduled", ScheduleID: ptr("schedule-1"), OccurrenceDate: ptr("2026-10-20")},
+		},
+	}
+	feed, err := newService(repo, &mockWorkouts{}).RenderFeed("secret")
+	require.NoError(t, err)
+	out := unfold(feed)
+	// The end of 20 October in Madrid (UTC+2) keeps the last occurrence inside the rule
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Madrid:20261019T073000\r\nDURATION:PT45M\r\nRRULE:FREQ=DAILY;UNTIL=20261020T215959Z\r\n")
+	assert.NotContains(t, out, "EXDATE")
+}
+
+func TestTrainerFeedNamesTheMember(t *testing.T) {
+	repo := &mockRepository{
+		tokenHash: hashToken("secret"),
+		token:     &dto.FeedToken{GymID: "gym-1", UserID: "coach-1", Scope: "trainer"},
+		user:      &dto.FeedUser{Username: "coach", Role: "trainer"},
+		workouts: []dto.FeedWorkout{
+			{ID: "workout-1", MemberID: "member-1", MemberName: "alice", WorkoutInstanceID: "instance-1", Status: "in_progress", ScheduledDate: ptr("2026-10-19")},
+		},
+	}
+	feed, err := newService(repo, &mockWorkouts{}).RenderFeed("secret")
+	require.NoError(t, err)
+	out := unfold(feed)
+	assert.Contains(t, out, "X-WR-CALNAME:Iron Temple · Member sessions\r\n")
+	assert.Contains(t, out, "SUMMARY:alice: Upper body\r\nDESCRIPTION:Member: alice\\nStatus: in progress\\n")
+	assert.Nil(t, repo.scheduleIDsLoaded, "schedule workouts are only read when there are schedules")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise/repository"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise/router"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise/service"
+	exercisemediamodule "github.com/alejandro-albiol/athenai/internal/exercise_media/module"
 )
 
 type CustomExerciseModule struct {
@@ -16,8 +17,12 @@ type CustomExerciseModule struct {
 }
 
 func NewCustomExerciseModule(db *sql.DB) http.Handler {
-	repo := repository.NewCustomExerciseRepository(db)
-	svc := service.NewCustomExerciseService(repo)
-	handler := handler.NewCustomExerciseHandler(svc)
+	handler := handler.NewCustomExerciseHandler(NewCustomExerciseService(db))
 	return router.NewCustomExerciseRouter(handler)
 }
+
+// NewCustomExerciseService builds the service other modules use to read a gym's own exercises
+func NewCustomExerciseService(db *sql.DB) *service.CustomExerciseService {
+	repo := repository.NewCustomExerciseRepository(db)
+	return service.NewCustomExerciseService(repo, exercisemediamodule.NewExerciseMediaService(db))
+}
@@ -5,20 +5,22 @@ import (
 
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise/interfaces"
+	mediaIF "github.com/alejandro-albiol/athenai/internal/exercise_media/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 )
 
 type CustomExerciseService struct {
-	Repo interfaces.CustomExerciseRepository
+	Repo         interfaces.CustomExerciseRepository
+	MediaCleaner mediaIF.MediaCleaner
 }
 
-func NewCustomExerciseService(repo interfaces.CustomExerciseRepository) *CustomExerciseService {
-	return &CustomExerciseService{Repo: repo}
+func NewCustomExerciseService(repo interfaces.CustomExerciseRepository, mediaCleaner mediaIF.MediaCleaner) *CustomExerciseService {
+	return &CustomExerciseService{Repo: repo, MediaCleaner: mediaCleaner}
 }
 
 func (s *CustomExerciseService) CreateCustomExercise(gymID string, exercise *dto.CustomExerciseCreationDTO) (*string, error) {
-	id, err := s.Repo.CreateCustomExercise(gymID, exercise);
+	id, err := s.Repo.CreateCustomExercise(gymID, exercise)
 	if err != nil {
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create custom exercise", err)
 	}
@@ -55,5 +57,10 @@ func (s *CustomExerciseService) DeleteCustomExercise(gymID, id string) error {
 	if err := s.Repo.DeleteCustomExercise(gymID, id); err != nil {
 		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete custom exercise", err)
 	}
+	if s.MediaCleaner != nil {
+		if err := s.MediaCleaner.CleanupExerciseMedia(gymID, id); err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Custom exercise deleted but its media could not be removed", err)
+		}
+	}
 	return nil
 }
@@ -46,7 +46,7 @@ func TestCreateCustomExerciseService(t *testing.T) {
 			return &id, nil
 		},
 	}
-	svc := NewCustomExerciseService(repo)
+	svc := NewCustomExerciseService(repo, nil)
 	dtoReq := &dto.CustomExerciseCreationDTO{
 		Name:            "Push Up",
 		Synonyms:        []string{"Pushup"},
@@ -73,7 +73,7 @@ func TestGetCustomExerciseByIDService(t *testing.T) {
 			return &dto.CustomExerciseResponseDTO{ID: id, Name: "Push Up"}, nil
 		},
 	}
-	svc := NewCustomExerciseService(repo)
+	svc := NewCustomExerciseService(repo, nil)
 	result, err := svc.GetCustomExerciseByID("tenant1", "ex-1")
 	assert.NoError(t, err)
 	assert.Equal(t, "ex-1", result.ID)
@@ -88,7 +88,7 @@ func TestListCustomExercisesService(t *testing.T) {
 			return []*dto.CustomExerciseResponseDTO{{ID: "ex-1", Name: "Push Up"}}, nil
 		},
 	}
-	svc := NewCustomExerciseService(repo)
+	svc := NewCustomExerciseService(repo, nil)
 	results, err := svc.ListCustomExercises("tenant1")
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -104,10 +104,40 @@ func TestDeleteCustomExerciseService(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewCustomExerciseService(repo)
+	svc := NewCustomExerciseService(repo, nil)
 	err := svc.DeleteCustomExercise("tenant1", "ex-1")
 	assert.NoError(t, err)
 
 	err = svc.DeleteCustomExercise("tenant1", "notfound")
 	assert.Error(t, err)
 }
+
+type mockMediaCleaner struct {
+	gymID      string
+	exerciseID string
+}
+
+func (m *mockMediaCleaner) CleanupExerciseMedia(gymID, exerciseID string) error {
+	m.gymID, m.exerciseID = gymID, exerciseID
+	return nil
+}
+
+func TestDeleteCustomExerciseServiceCleansUpMedia(t *testing.T) {
+	repo := &mockRepo{
+		DeleteFn: func(gymID, id string) error {
+			if id == "notfound" {
+				return errors.New("not found")
+			}
+			return nil
+		},
+	}
+	cleaner := &mockMediaCleaner{}
+	svc := NewCustomExerciseService(repo, cleaner)
+
+	assert.Error(t, svc.DeleteCustomExercise("tenant1", "notfound"))
+	assert.Empty(t, cleaner.exerciseID)
+
+	assert.NoError(t, svc.DeleteCustomExercise("tenant1", "ex-1"))
+	assert.Equal(t, "tenant1", cleaner.gymID)
+	assert.Equal(t, "ex-1", cleaner.exerciseID)
+}
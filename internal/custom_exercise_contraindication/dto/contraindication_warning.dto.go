@@ -0,0 +1,18 @@
+package dto
+
+// ContraindicationWarning reports a tagged exercise that matches a member's special situation
+type ContraindicationWarning struct {
+	MemberID         string  `json:"member_id"`
+	ExerciseSource   string  `json:"exercise_source"` // public or gym
+	ExerciseID       string  `json:"exercise_id"`
+	SpecialSituation string  `json:"special_situation"`
+	BodyRegion       string  `json:"body_region"`
+	Severity         string  `json:"severity"`
+	Notes            *string `json:"notes,omitempty"`
+}
+
+// MemberSituation is the special situation of a member scheduled on a workout instance
+type MemberSituation struct {
+	MemberID         string
+	SpecialSituation string
+}
@@ -0,0 +1,11 @@
+package dto
+
+type CustomExerciseContraindication struct {
+	ID               string  `json:"id"`
+	CustomExerciseID string  `json:"custom_exercise_id"`
+	SpecialSituation string  `json:"special_situation"`
+	BodyRegion       string  `json:"body_region"`
+	Severity         string  `json:"severity"`
+	Notes            *string `json:"notes,omitempty"`
+	CreatedAt        string  `json:"created_at"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type CustomExerciseContraindicationCreationDTO struct {
+	CustomExerciseID string  `json:"custom_exercise_id" validate:"required"`
+	SpecialSituation string  `json:"special_situation" validate:"required,oneof=pregnancy post_partum injury_recovery chronic_condition elderly_population physical_limitation"`
+	BodyRegion       string  `json:"body_region" validate:"omitempty,oneof=general neck shoulder elbow wrist upper_back lower_back abdomen pelvic_floor hip knee ankle"`
+	Severity         string  `json:"severity" validate:"required,oneof=contraindicated caution"`
+	Notes            *string `json:"notes,omitempty"`
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type CustomExerciseContraindicationHandler struct {
+	service interfaces.CustomExerciseContraindicationService
+}
+
+func NewCustomExerciseContraindicationHandler(svc interfaces.CustomExerciseContraindicationService) *CustomExerciseContraindicationHandler {
+	return &CustomExerciseContraindicationHandler{service: svc}
+}
+
+func (h *CustomExerciseContraindicationHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	var req dto.CustomExerciseContraindicationCreationDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	if req.CustomExerciseID == "" || req.SpecialSituation == "" || req.Severity == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Missing required fields", nil))
+		return
+	}
+	id, err := h.service.CreateTag(gymID, &req)
+	if apiErr, ok := err.(*apierror.APIError); err != nil && ok {
+		response.WriteAPIError(w, apiErr)
+		return
+	}
+	response.WriteAPICreated(w, "Custom exercise contraindication created", id)
+}
+
+func (h *CustomExerciseContraindicationHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	id := chi.URLParam(r, "id")
+	if apiErr, ok := h.service.DeleteTag(gymID, id).(*apierror.APIError); apiErr != nil && ok {
+		response.WriteAPIError(w, apiErr)
+		return
+	}
+	response.WriteAPISuccess(w, "Custom exercise contraindication deleted", nil)
+}
+
+func (h *CustomExerciseContraindicationHandler) GetTagByID(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	id := chi.URLParam(r, "id")
+	tag, err := h.service.GetTagByID(gymID, id)
+	if apiErr, ok := err.(*apierror.APIError); err != nil && ok {
+		response.WriteAPIError(w, apiErr)
+		return
+	}
+	response.WriteAPISuccess(w, "Custom exercise contraindication found", tag)
+}
+
+func (h *CustomExerciseContraindicationHandler) GetTagsByCustomExerciseID(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	customExerciseID := chi.URLParam(r, "customExerciseID")
+	tags, err := h.service.GetTagsByCustomExerciseID(gymID, customExerciseID)
+	if apiErr, ok := err.(*apierror.APIError); err != nil && ok {
+		response.WriteAPIError(w, apiErr)
+		return
+	}
+	response.WriteAPISuccess(w, "Custom exercise contraindications for exercise", tags)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+
+// ContraindicationChecker checks exercise assignments against members' special situations.
+// Both methods return the matching warnings, or a conflict error when the gym policy blocks
+// contraindicated exercises and at least one matched.
+type ContraindicationChecker interface {
+	CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*dto.ContraindicationWarning, error)
+	CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*dto.ContraindicationWarning, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type CustomExerciseContraindicationHandler interface {
+	CreateTag(w http.ResponseWriter, r *http.Request)
+	DeleteTag(w http.ResponseWriter, r *http.Request)
+	GetTagByID(w http.ResponseWriter, r *http.Request)
+	GetTagsByCustomExerciseID(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+
+type CustomExerciseContraindicationRepository interface {
+	CreateTag(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error)
+	DeleteTag(gymID, id string) error
+	FindByID(gymID, id string) (*dto.CustomExerciseContraindication, error)
+	FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error)
+
+	// Lookups used when checking assignments against members' special situations
+	FindMemberSituation(gymID, memberID string) (string, error)
+	FindScheduledMembers(gymID, workoutInstanceID string) ([]*dto.MemberSituation, error)
+	FindTagsForWorkoutInstance(gymID, workoutInstanceID, specialSituation string) ([]*dto.ContraindicationWarning, error)
+	FindTagsForExercise(gymID, exerciseSource, exerciseID, specialSituation string) ([]*dto.ContraindicationWarning, error)
+}
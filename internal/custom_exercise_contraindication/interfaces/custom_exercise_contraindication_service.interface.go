@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+
+type CustomExerciseContraindicationService interface {
+	CreateTag(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error)
+	DeleteTag(gymID, id string) error
+	GetTagByID(gymID, id string) (*dto.CustomExerciseContraindication, error)
+	GetTagsByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error)
+}
@@ -0,0 +1,18 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/handler"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/repository"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/router"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/service"
+)
+
+func NewCustomExerciseContraindicationModule(db *sql.DB) http.Handler {
+	repo := repository.NewCustomExerciseContraindicationRepository(db)
+	svc := service.NewCustomExerciseContraindicationService(repo)
+	handler := handler.NewCustomExerciseContraindicationHandler(svc)
+	return router.NewCustomExerciseContraindicationRouter(handler)
+}
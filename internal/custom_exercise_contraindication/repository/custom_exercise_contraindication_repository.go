@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/lib/pq"
+)
+
+type CustomExerciseContraindicationRepository struct {
+	db *sql.DB
+}
+
+func NewCustomExerciseContraindicationRepository(db *sql.DB) *CustomExerciseContraindicationRepository {
+	return &CustomExerciseContraindicationRepository{db: db}
+}
+
+func (r *CustomExerciseContraindicationRepository) CreateTag(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`INSERT INTO %s.custom_exercise_contraindication (custom_exercise_id, special_situation, body_region, severity, notes)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, schema)
+	var id string
+	err := r.db.QueryRow(query, tag.CustomExerciseID, tag.SpecialSituation, tag.BodyRegion, tag.Severity, tag.Notes).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *CustomExerciseContraindicationRepository) DeleteTag(gymID, id string) error {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf("DELETE FROM %s.custom_exercise_contraindication WHERE id = $1", schema)
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CustomExerciseContraindicationRepository) FindByID(gymID, id string) (*dto.CustomExerciseContraindication, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`SELECT id, custom_exercise_id, special_situation, body_region, severity, notes, created_at
+		FROM %s.custom_exercise_contraindication WHERE id = $1`, schema)
+	var tag dto.CustomExerciseContraindication
+	err := r.db.QueryRow(query, id).Scan(&tag.ID, &tag.CustomExerciseID, &tag.SpecialSituation, &tag.BodyRegion, &tag.Severity, &tag.Notes, &tag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *CustomExerciseContraindicationRepository) FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`SELECT id, custom_exercise_id, special_situation, body_region, severity, notes, created_at
+		FROM %s.custom_exercise_contraindication WHERE custom_exercise_id = $1 ORDER BY special_situation, body_region`, schema)
+	rows, err := r.db.Query(query, customExerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*dto.CustomExerciseContraindication
+	for rows.Next() {
+		var tag dto.CustomExerciseContraindication
+		if err := rows.Scan(&tag.ID, &tag.CustomExerciseID, &tag.SpecialSituation, &tag.BodyRegion, &tag.Severity, &tag.Notes, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *CustomExerciseContraindicationRepository) FindMemberSituation(gymID, memberID string) (string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf("SELECT COALESCE(special_situation, 'none') FROM %s.user WHERE id = $1", schema)
+	var situation string
+	if err := r.db.QueryRow(query, memberID).Scan(&situation); err != nil {
+		return "", err
+	}
+	return situation, nil
+}
+
+// FindScheduledMembers returns members with a special situation who still have the instance ahead of them
+func (r *CustomExerciseContraindicationRepository) FindScheduledMembers(gymID, workoutInstanceID string) ([]*dto.MemberSituation, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`SELECT DISTINCT u.id, u.special_situation
+		FROM %s.custom_member_workout cmw
+		JOIN %s.user u ON u.id = cmw.member_id
+		WHERE cmw.workout_instance_id = $1
+		AND cmw.status IN ('scheduled', 'in_progress')
+		AND u.special_situation IS NOT NULL AND u.special_situation <> 'none'`, schema, schema)
+	rows, err := r.db.Query(query, workoutInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*dto.MemberSituation
+	for rows.Next() {
+		var m dto.MemberSituation
+		if err := rows.Scan(&m.MemberID, &m.SpecialSituation); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+// FindTagsForWorkoutInstance returns the public and gym tags matching the situation for every exercise in the instance
+func (r *CustomExerciseContraindicationRepository) FindTagsForWorkoutInstance(gymID, workoutInstanceID, specialSituation string) ([]*dto.ContraindicationWarning, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`
+		SELECT 'public', ec.exercise_id, ec.special_situation, ec.body_region, ec.severity, ec.notes
+		FROM %s.custom_workout_exercise cwe
+		JOIN public.exercise_contraindication ec ON ec.exercise_id = cwe.public_exercise_id
+		WHERE cwe.workout_instance_id = $1 AND cwe.exercise_source = 'public' AND ec.special_situation = $2
+		UNION ALL
+		SELECT 'gym', cec.custom_exercise_id, cec.special_situation, cec.body_region, cec.severity, cec.notes
+		FROM %s.custom_workout_exercise cwe
+		JOIN %s.custom_exercise_contraindication cec ON cec.custom_exercise_id = cwe.gym_exercise_id
+		WHERE cwe.workout_instance_id = $1 AND cwe.exercise_source = 'gym' AND cec.special_situation = $2`, schema, schema, schema)
+	return r.queryWarnings(query, workoutInstanceID, specialSituation)
+}
+
+func (r *CustomExerciseContraindicationRepository) FindTagsForExercise(gymID, exerciseSource, exerciseID, specialSituation string) ([]*dto.ContraindicationWarning, error) {
+	var query string
+	if exerciseSource == "public" {
+		query = `SELECT 'public', exercise_id, special_situation, body_region, severity, notes
+			FROM public.exercise_contraindication WHERE exercise_id = $1 AND special_situation = $2`
+	} else {
+		query = fmt.Sprintf(`SELECT 'gym', custom_exercise_id, special_situation, body_region, severity, notes
+			FROM %s.custom_exercise_contraindication WHERE custom_exercise_id = $1 AND special_situation = $2`, pq.QuoteIdentifier(gymID))
+	}
+	return r.queryWarnings(query, exerciseID, specialSituation)
+}
+
+func (r *CustomExerciseContraindicationRepository) queryWarnings(query string, args ...any) ([]*dto.ContraindicationWarning, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []*dto.ContraindicationWarning
+	for rows.Next() {
+		var w dto.ContraindicationWarning
+		if err := rows.Scan(&w.ExerciseSource, &w.ExerciseID, &w.SpecialSituation, &w.BodyRegion, &w.Severity, &w.Notes); err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, &w)
+	}
+	return warnings, rows.Err()
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTag(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewCustomExerciseContraindicationRepository(db)
+	tag := &dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "pregnancy", BodyRegion: "abdomen", Severity: "contraindicated"}
+	mock.ExpectQuery(`INSERT INTO "gym1".custom_exercise_contraindication`).
+		WithArgs("c1", "pregnancy", "abdomen", "contraindicated", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("tag1"))
+	id, err := repo.CreateTag("gym1", tag)
+	assert.NoError(t, err)
+	assert.Equal(t, "tag1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindScheduledMembers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewCustomExerciseContraindicationRepository(db)
+	mock.ExpectQuery(`SELECT DISTINCT u.id, u.special_situation FROM "gym1".custom_member_workout cmw JOIN "gym1".user u`).
+		WithArgs("w1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "special_situation"}).AddRow("m1", "pregnancy"))
+	members, err := repo.FindScheduledMembers("gym1", "w1")
+	assert.NoError(t, err)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, "m1", members[0].MemberID)
+		assert.Equal(t, "pregnancy", members[0].SpecialSituation)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindTagsForWorkoutInstance(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewCustomExerciseContraindicationRepository(db)
+	rows := sqlmock.NewRows([]string{"source", "exercise_id", "special_situation", "body_region", "severity", "notes"}).
+		AddRow("public", "ex1", "injury_recovery", "knee", "contraindicated", nil).
+		AddRow("gym", "c1", "injury_recovery", "general", "caution", "Keep range of motion short")
+	mock.ExpectQuery(`FROM "gym1".custom_workout_exercise cwe JOIN public.exercise_contraindication ec (.+) UNION ALL (.+) JOIN "gym1".custom_exercise_contraindication cec`).
+		WithArgs("w1", "injury_recovery").
+		WillReturnRows(rows)
+	warnings, err := repo.FindTagsForWorkoutInstance("gym1", "w1", "injury_recovery")
+	assert.NoError(t, err)
+	if assert.Len(t, warnings, 2) {
+		assert.Equal(t, "public", warnings[0].ExerciseSource)
+		assert.Nil(t, warnings[0].Notes)
+		assert.Equal(t, "gym", warnings[1].ExerciseSource)
+		assert.Equal(t, "Keep range of motion short", *warnings[1].Notes)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindTagsForExercise_PublicSource(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewCustomExerciseContraindicationRepository(db)
+	mock.ExpectQuery(`FROM public.exercise_contraindication WHERE exercise_id = \$1 AND special_situation = \$2`).
+		WithArgs("ex1", "pregnancy").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "exercise_id", "special_situation", "body_region", "severity", "notes"}))
+	warnings, err := repo.FindTagsForExercise("gym1", "public", "ex1", "pregnancy")
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
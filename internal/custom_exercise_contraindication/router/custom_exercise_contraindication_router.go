@@ -0,0 +1,17 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewCustomExerciseContraindicationRouter(h interfaces.CustomExerciseContraindicationHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/custom_exercise_contraindication", h.CreateTag)
+	r.Get("/custom_exercise_contraindication/{id}", h.GetTagByID)
+	r.Get("/custom_exercise_contraindication/custom_exercise/{customExerciseID}", h.GetTagsByCustomExerciseID)
+	r.Delete("/custom_exercise_contraindication/{id}", h.DeleteTag)
+	return r
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	contraindication_enum "github.com/alejandro-albiol/athenai/internal/exercise_contraindication/enum"
+	gym_enum "github.com/alejandro-albiol/athenai/internal/gym/enum"
+	gyminterfaces "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type ContraindicationChecker struct {
+	repository interfaces.CustomExerciseContraindicationRepository
+	gymRepo    gyminterfaces.GymRepository
+}
+
+func NewContraindicationChecker(repository interfaces.CustomExerciseContraindicationRepository, gymRepo gyminterfaces.GymRepository) *ContraindicationChecker {
+	return &ContraindicationChecker{repository: repository, gymRepo: gymRepo}
+}
+
+// CheckMemberWorkout matches every exercise of the instance against the member's special situation
+func (c *ContraindicationChecker) CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*dto.ContraindicationWarning, error) {
+	situation, err := c.repository.FindMemberSituation(gymID, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Member not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get member special situation", err)
+	}
+	if situation == "" || user_enum.SpecialSituation(situation) == user_enum.None {
+		return nil, nil
+	}
+
+	warnings, err := c.repository.FindTagsForWorkoutInstance(gymID, workoutInstanceID, situation)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check exercise contraindications", err)
+	}
+	for _, w := range warnings {
+		w.MemberID = memberID
+	}
+	return c.applyPolicy(gymID, warnings)
+}
+
+// CheckWorkoutExercise matches a single exercise against every member already scheduled on the instance
+func (c *ContraindicationChecker) CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*dto.ContraindicationWarning, error) {
+	members, err := c.repository.FindScheduledMembers(gymID, workoutInstanceID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get scheduled members", err)
+	}
+
+	var warnings []*dto.ContraindicationWarning
+	// Tags only depend on the situation, so members sharing one reuse the same lookup
+	tagsBySituation := make(map[string][]*dto.ContraindicationWarning)
+	for _, member := range members {
+		tags, ok := tagsBySituation[member.SpecialSituation]
+		if !ok {
+			tags, err = c.repository.FindTagsForExercise(gymID, exerciseSource, exerciseID, member.SpecialSituation)
+			if err != nil {
+				return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check exercise contraindications", err)
+			}
+			tagsBySituation[member.SpecialSituation] = tags
+		}
+		for _, tag := range tags {
+			w := *tag
+			w.MemberID = member.MemberID
+			warnings = append(warnings, &w)
+		}
+	}
+	return c.applyPolicy(gymID, warnings)
+}
+
+// applyPolicy turns contraindicated matches into a conflict when the gym blocks them.
+// Caution matches are always returned as warnings.
+func (c *ContraindicationChecker) applyPolicy(gymID string, warnings []*dto.ContraindicationWarning) ([]*dto.ContraindicationWarning, error) {
+	var blocking *dto.ContraindicationWarning
+	for _, w := range warnings {
+		if contraindication_enum.ContraindicationSeverity(w.Severity) == contraindication_enum.Contraindicated {
+			blocking = w
+			break
+		}
+	}
+	if blocking == nil {
+		return warnings, nil
+	}
+
+	gym, err := c.gymRepo.GetGymByID(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym contraindication policy", err)
+	}
+	if gym_enum.ContraindicationPolicy(gym.ContraindicationPolicy) == gym_enum.ContraindicationBlock {
+		return nil, apierror.New(errorcode_enum.CodeConflict,
+			fmt.Sprintf("Exercise %s is contraindicated for %s (%s) and gym policy blocks it", blocking.ExerciseID, blocking.SpecialSituation, blocking.BodyRegion), nil)
+	}
+	return warnings, nil
+}
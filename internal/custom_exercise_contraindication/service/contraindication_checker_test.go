@@ -0,0 +1,133 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	gymdto "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGymRepo struct {
+	policy string
+}
+
+func (m *mockGymRepo) CreateGym(gym *gymdto.GymCreationDTO) (*string, error) { return nil, nil }
+func (m *mockGymRepo) GetGymByID(id string) (*gymdto.GymResponseDTO, error) {
+	return &gymdto.GymResponseDTO{ID: id, ContraindicationPolicy: m.policy}, nil
+}
+func (m *mockGymRepo) GetGymByName(name string) (*gymdto.GymResponseDTO, error) { return nil, nil }
+func (m *mockGymRepo) GetAllGyms() ([]*gymdto.GymResponseDTO, error)            { return nil, nil }
+func (m *mockGymRepo) UpdateGym(id string, gym *gymdto.GymUpdateDTO) (*gymdto.GymResponseDTO, error) {
+	return nil, nil
+}
+func (m *mockGymRepo) SetGymActive(id string, active bool) error { return nil }
+func (m *mockGymRepo) DeleteGym(id string) error                 { return nil }
+
+func TestCheckMemberWorkout_WarnPolicyReturnsWarnings(t *testing.T) {
+	repo := &mockRepo{
+		FindMemberSituationFn: func(gymID, memberID string) (string, error) { return "injury_recovery", nil },
+		FindTagsForWorkoutInstanceFn: func(gymID, workoutInstanceID, situation string) ([]*dto.ContraindicationWarning, error) {
+			assert.Equal(t, "injury_recovery", situation)
+			return []*dto.ContraindicationWarning{
+				{ExerciseSource: "public", ExerciseID: "ex1", SpecialSituation: situation, BodyRegion: "knee", Severity: "contraindicated"},
+			}, nil
+		},
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "warn"})
+
+	warnings, err := checker.CheckMemberWorkout("gym1", "member1", "w1")
+	assert.NoError(t, err)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, "member1", warnings[0].MemberID)
+	}
+}
+
+func TestCheckMemberWorkout_BlockPolicyRejectsContraindicated(t *testing.T) {
+	repo := &mockRepo{
+		FindMemberSituationFn: func(gymID, memberID string) (string, error) { return "pregnancy", nil },
+		FindTagsForWorkoutInstanceFn: func(gymID, workoutInstanceID, situation string) ([]*dto.ContraindicationWarning, error) {
+			return []*dto.ContraindicationWarning{
+				{ExerciseSource: "gym", ExerciseID: "c1", SpecialSituation: situation, BodyRegion: "abdomen", Severity: "contraindicated"},
+			}, nil
+		},
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "block"})
+
+	warnings, err := checker.CheckMemberWorkout("gym1", "member1", "w1")
+	assert.Nil(t, warnings)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, errorcode_enum.CodeConflict, apiErr.Code)
+}
+
+func TestCheckMemberWorkout_BlockPolicyAllowsCaution(t *testing.T) {
+	repo := &mockRepo{
+		FindMemberSituationFn: func(gymID, memberID string) (string, error) { return "elderly_population", nil },
+		FindTagsForWorkoutInstanceFn: func(gymID, workoutInstanceID, situation string) ([]*dto.ContraindicationWarning, error) {
+			return []*dto.ContraindicationWarning{
+				{ExerciseSource: "public", ExerciseID: "ex1", SpecialSituation: situation, BodyRegion: "general", Severity: "caution"},
+			}, nil
+		},
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "block"})
+
+	warnings, err := checker.CheckMemberWorkout("gym1", "member1", "w1")
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+}
+
+func TestCheckMemberWorkout_NoSituationSkipsLookup(t *testing.T) {
+	repo := &mockRepo{
+		FindMemberSituationFn: func(gymID, memberID string) (string, error) { return "none", nil },
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "block"})
+
+	warnings, err := checker.CheckMemberWorkout("gym1", "member1", "w1")
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestCheckMemberWorkout_MemberNotFound(t *testing.T) {
+	repo := &mockRepo{
+		FindMemberSituationFn: func(gymID, memberID string) (string, error) { return "", sql.ErrNoRows },
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "warn"})
+
+	_, err := checker.CheckMemberWorkout("gym1", "missing", "w1")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+}
+
+func TestCheckWorkoutExercise_WarnsEveryScheduledMember(t *testing.T) {
+	lookups := 0
+	repo := &mockRepo{
+		FindScheduledMembersFn: func(gymID, workoutInstanceID string) ([]*dto.MemberSituation, error) {
+			return []*dto.MemberSituation{
+				{MemberID: "m1", SpecialSituation: "pregnancy"},
+				{MemberID: "m2", SpecialSituation: "pregnancy"},
+				{MemberID: "m3", SpecialSituation: "elderly_population"},
+			}, nil
+		},
+		FindTagsForExerciseFn: func(gymID, source, exerciseID, situation string) ([]*dto.ContraindicationWarning, error) {
+			lookups++
+			if situation != "pregnancy" {
+				return nil, nil
+			}
+			return []*dto.ContraindicationWarning{
+				{ExerciseSource: source, ExerciseID: exerciseID, SpecialSituation: situation, BodyRegion: "abdomen", Severity: "caution"},
+			}, nil
+		},
+	}
+	checker := NewContraindicationChecker(repo, &mockGymRepo{policy: "block"})
+
+	warnings, err := checker.CheckWorkoutExercise("gym1", "w1", "public", "ex1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, lookups)
+	if assert.Len(t, warnings, 2) {
+		assert.Equal(t, "m1", warnings[0].MemberID)
+		assert.Equal(t, "m2", warnings[1].MemberID)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	contraindication_service "github.com/alejandro-albiol/athenai/internal/exercise_contraindication/service"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type CustomExerciseContraindicationService struct {
+	repository interfaces.CustomExerciseContraindicationRepository
+}
+
+func NewCustomExerciseContraindicationService(repository interfaces.CustomExerciseContraindicationRepository) *CustomExerciseContraindicationService {
+	return &CustomExerciseContraindicationService{repository: repository}
+}
+
+func (s *CustomExerciseContraindicationService) CreateTag(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error) {
+	if tag.CustomExerciseID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Custom exercise ID is required", nil)
+	}
+	if err := contraindication_service.ValidateTag(&tag.SpecialSituation, &tag.BodyRegion, tag.Severity); err != nil {
+		return nil, err
+	}
+	existing, err := s.repository.FindByCustomExerciseID(gymID, tag.CustomExerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check existing contraindications", err)
+	}
+	for _, e := range existing {
+		if e.SpecialSituation == tag.SpecialSituation && e.BodyRegion == tag.BodyRegion {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "Custom exercise is already tagged for this situation and body region", nil)
+		}
+	}
+	id, err := s.repository.CreateTag(gymID, tag)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create custom exercise contraindication", err)
+	}
+	return id, nil
+}
+
+func (s *CustomExerciseContraindicationService) DeleteTag(gymID, id string) error {
+	err := s.repository.DeleteTag(gymID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Custom exercise contraindication not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete custom exercise contraindication", err)
+	}
+	return nil
+}
+
+func (s *CustomExerciseContraindicationService) GetTagByID(gymID, id string) (*dto.CustomExerciseContraindication, error) {
+	tag, err := s.repository.FindByID(gymID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Custom exercise contraindication not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get custom exercise contraindication", err)
+	}
+	return tag, nil
+}
+
+func (s *CustomExerciseContraindicationService) GetTagsByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error) {
+	tags, err := s.repository.FindByCustomExerciseID(gymID, customExerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get contraindications by custom exercise ID", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepo struct {
+	CreateTagFn                  func(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error)
+	DeleteTagFn                  func(gymID, id string) error
+	FindByIDFn                   func(gymID, id string) (*dto.CustomExerciseContraindication, error)
+	FindByCustomExerciseIDFn     func(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error)
+	FindMemberSituationFn        func(gymID, memberID string) (string, error)
+	FindScheduledMembersFn       func(gymID, workoutInstanceID string) ([]*dto.MemberSituation, error)
+	FindTagsForWorkoutInstanceFn func(gymID, workoutInstanceID, specialSituation string) ([]*dto.ContraindicationWarning, error)
+	FindTagsForExerciseFn        func(gymID, exerciseSource, exerciseID, specialSituation string) ([]*dto.ContraindicationWarning, error)
+}
+
+func (m *mockRepo) CreateTag(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error) {
+	return m.CreateTagFn(gymID, tag)
+}
+func (m *mockRepo) DeleteTag(gymID, id string) error {
+	return m.DeleteTagFn(gymID, id)
+}
+func (m *mockRepo) FindByID(gymID, id string) (*dto.CustomExerciseContraindication, error) {
+	return m.FindByIDFn(gymID, id)
+}
+func (m *mockRepo) FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error) {
+	return m.FindByCustomExerciseIDFn(gymID, customExerciseID)
+}
+func (m *mockRepo) FindMemberSituation(gymID, memberID string) (string, error) {
+	return m.FindMemberSituationFn(gymID, memberID)
+}
+func (m *mockRepo) FindScheduledMembers(gymID, workoutInstanceID string) ([]*dto.MemberSituation, error) {
+	return m.FindScheduledMembersFn(gymID, workoutInstanceID)
+}
+func (m *mockRepo) FindTagsForWorkoutInstance(gymID, workoutInstanceID, specialSituation string) ([]*dto.ContraindicationWarning, error) {
+	return m.FindTagsForWorkoutInstanceFn(gymID, workoutInstanceID, specialSituation)
+}
+func (m *mockRepo) FindTagsForExercise(gymID, exerciseSource, exerciseID, specialSituation string) ([]*dto.ContraindicationWarning, error) {
+	return m.FindTagsForExerciseFn(gymID, exerciseSource, exerciseID, specialSituation)
+}
+
+func TestCreateTag_DefaultsBodyRegion(t *testing.T) {
+	repo := &mockRepo{
+		FindByCustomExerciseIDFn: func(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error) {
+			return nil, nil
+		},
+		CreateTagFn: func(gymID string, tag *dto.CustomExerciseContraindicationCreationDTO) (*string, error) {
+			assert.Equal(t, "general", tag.BodyRegion)
+			id := "tag1"
+			return &id, nil
+		},
+	}
+	svc := NewCustomExerciseContraindicationService(repo)
+	id, err := svc.CreateTag("gym1", &dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "pregnancy", Severity: "caution"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tag1", *id)
+}
+
+func TestCreateTag_InvalidValues(t *testing.T) {
+	svc := NewCustomExerciseContraindicationService(&mockRepo{})
+	cases := []struct {
+		name string
+		tag  dto.CustomExerciseContraindicationCreationDTO
+	}{
+		{"none situation", dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "none", Severity: "caution"}},
+		{"unknown region", dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "pregnancy", BodyRegion: "tail", Severity: "caution"}},
+		{"unknown severity", dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "pregnancy", Severity: "avoid"}},
+	}
+	for _, c := range cases {
+		_, err := svc.CreateTag("gym1", &c.tag)
+		assert.Error(t, err, c.name)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code, c.name)
+	}
+}
+
+func TestCreateTag_Duplicate(t *testing.T) {
+	repo := &mockRepo{
+		FindByCustomExerciseIDFn: func(gymID, customExerciseID string) ([]*dto.CustomExerciseContraindication, error) {
+			return []*dto.CustomExerciseContraindication{{SpecialSituation: "injury_recovery", BodyRegion: "knee"}}, nil
+		},
+	}
+	svc := NewCustomExerciseContraindicationService(repo)
+	_, err := svc.CreateTag("gym1", &dto.CustomExerciseContraindicationCreationDTO{CustomExerciseID: "c1", SpecialSituation: "injury_recovery", BodyRegion: "knee", Severity: "contraindicated"})
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+}
@@ -1,7 +1,13 @@
 package dto
 
+// CustomExerciseEquipment links a custom exercise to public equipment or to the gym's own custom equipment.
+// Exactly one of PublicEquipmentID and GymEquipmentID is set, matching EquipmentSource.
 type CustomExerciseEquipment struct {
-	ID               string `json:"id"`
-	CustomExerciseID string `json:"custom_exercise_id"`
-	EquipmentID      string `json:"equipment_id"`
+	ID                string  `json:"id"`
+	CustomExerciseID  string  `json:"custom_exercise_id"`
+	EquipmentSource   string  `json:"equipment_source"` // public or gym
+	PublicEquipmentID *string `json:"public_equipment_id,omitempty"`
+	GymEquipmentID    *string `json:"gym_equipment_id,omitempty"`
+	EquipmentName     string  `json:"equipment_name,omitempty"`
+	EquipmentCategory string  `json:"equipment_category,omitempty"`
 }
@@ -0,0 +1,17 @@
+package enum
+
+// EquipmentSource tells which catalog a custom exercise equipment link points to
+type EquipmentSource string
+
+const (
+	SourcePublic EquipmentSource = "public"
+	SourceGym    EquipmentSource = "gym"
+)
+
+func (e EquipmentSource) IsValid() bool {
+	switch e {
+	case SourcePublic, SourceGym:
+		return true
+	}
+	return false
+}
@@ -29,11 +29,11 @@ func (h *CustomExerciseEquipmentHandler) CreateLink(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if link.CustomExerciseID == "" || link.EquipmentID == "" {
-		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "CustomExerciseID and EquipmentID are required", nil))
+	if link == nil || link.CustomExerciseID == "" || link.EquipmentSource == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "CustomExerciseID and EquipmentSource are required", nil))
 		return
 	}
-	
+
 	id, err := h.service.CreateLink(gymID, link)
 	if apiErr, ok := err.(*apierror.APIError); err != nil && ok {
 		response.WriteAPIError(w, apiErr)
@@ -31,7 +31,7 @@ func (m *mockService) FindByID(gymID, id string) (*dto.CustomExerciseEquipment,
 func TestCreateLinkHandler(t *testing.T) {
 	service := &mockService{
 		CreateLinkFn: func(gymID string, link *dto.CustomExerciseEquipment) (*string, error) {
-			if link == nil || link.CustomExerciseID == "" || link.EquipmentID == "" {
+			if link == nil || link.CustomExerciseID == "" || link.GymEquipmentID == nil {
 				return nil, assert.AnError
 			}
 			id := "link-1"
@@ -39,7 +39,7 @@ func TestCreateLinkHandler(t *testing.T) {
 		},
 	}
 	h := &CustomExerciseEquipmentHandler{service: service}
-	body := `{"custom_exercise_id":"ex-1","equipment_id":"eq-1"}`
+	body := `{"custom_exercise_id":"ex-1","equipment_source":"gym","gym_equipment_id":"eq-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/custom-exercise-equipment", strings.NewReader(body))
 	w := httptest.NewRecorder()
 
@@ -66,7 +66,7 @@ func TestFindByIDHandler(t *testing.T) {
 			if id == "notfound" {
 				return nil, assert.AnError
 			}
-			return &dto.CustomExerciseEquipment{ID: id, CustomExerciseID: "ex-1", EquipmentID: "eq-1"}, nil
+			return &dto.CustomExerciseEquipment{ID: id, CustomExerciseID: "ex-1", EquipmentSource: "public", EquipmentName: "Barbell"}, nil
 		},
 	}
 	h := &CustomExerciseEquipmentHandler{service: service}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/dto"
+	"github.com/lib/pq"
 )
 
 type CustomExerciseEquipmentRepository struct {
@@ -15,11 +16,18 @@ func NewCustomExerciseEquipmentRepository(db *sql.DB) *CustomExerciseEquipmentRe
 	return &CustomExerciseEquipmentRepository{db: db}
 }
 
+// selectLinks resolves the equipment name and category from whichever catalog the link points to
+const selectLinks = `SELECT l.id, l.custom_exercise_id, l.equipment_source, l.public_equipment_id, l.gym_equipment_id,
+		COALESCE(e.name, ce.name), COALESCE(e.category, ce.category)
+		FROM %[1]s.custom_exercise_equipment l
+		LEFT JOIN public.equipment e ON e.id = l.public_equipment_id
+		LEFT JOIN %[1]s.custom_equipment ce ON ce.id = l.gym_equipment_id`
+
 func (r *CustomExerciseEquipmentRepository) CreateLink(gymID string, link *dto.CustomExerciseEquipment) (*string, error) {
-	schema := gymID
-	query := fmt.Sprintf("INSERT INTO %s.custom_exercise_equipment (custom_exercise_id, equipment_id) VALUES ($1, $2) RETURNING id", schema)
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf("INSERT INTO %s.custom_exercise_equipment (custom_exercise_id, equipment_source, public_equipment_id, gym_equipment_id) VALUES ($1, $2, $3, $4) RETURNING id", schema)
 	var id string
-	err := r.db.QueryRow(query, link.CustomExerciseID, link.EquipmentID).Scan(&id)
+	err := r.db.QueryRow(query, link.CustomExerciseID, link.EquipmentSource, link.PublicEquipmentID, link.GymEquipmentID).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +35,7 @@ func (r *CustomExerciseEquipmentRepository) CreateLink(gymID string, link *dto.C
 }
 
 func (r *CustomExerciseEquipmentRepository) DeleteLink(gymID, id string) error {
-	schema := gymID
+	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf("DELETE FROM %s.custom_exercise_equipment WHERE id = $1", schema)
 	result, err := r.db.Exec(query, id)
 	if err != nil {
@@ -44,7 +52,7 @@ func (r *CustomExerciseEquipmentRepository) DeleteLink(gymID, id string) error {
 }
 
 func (r *CustomExerciseEquipmentRepository) RemoveAllLinksForExercise(gymID, customExerciseID string) error {
-	schema := gymID
+	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf("DELETE FROM %s.custom_exercise_equipment WHERE custom_exercise_id = $1", schema)
 	_, err := r.db.Exec(query, customExerciseID)
 	if err != nil {
@@ -54,20 +62,27 @@ func (r *CustomExerciseEquipmentRepository) RemoveAllLinksForExercise(gymID, cus
 }
 
 func (r *CustomExerciseEquipmentRepository) FindByID(gymID, id string) (*dto.CustomExerciseEquipment, error) {
-	schema := gymID
-	query := fmt.Sprintf("SELECT custom_exercise_id, equipment_id FROM %s.custom_exercise_equipment WHERE id = $1", schema)
-	var link dto.CustomExerciseEquipment
-	err := r.db.QueryRow(query, id).Scan(&link.CustomExerciseID, &link.EquipmentID)
+	query := fmt.Sprintf(selectLinks+" WHERE l.id = $1", pq.QuoteIdentifier(gymID))
+	link, err := scanLink(r.db.QueryRow(query, id))
 	if err != nil {
 		return nil, err
 	}
-	return &link, nil
+	return link, nil
 }
 
 func (r *CustomExerciseEquipmentRepository) FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseEquipment, error) {
-	schema := gymID
-	query := fmt.Sprintf("SELECT id, equipment_id FROM %s.custom_exercise_equipment WHERE custom_exercise_id = $1", schema)
-	rows, err := r.db.Query(query, customExerciseID)
+	query := fmt.Sprintf(selectLinks+" WHERE l.custom_exercise_id = $1 ORDER BY 6", pq.QuoteIdentifier(gymID))
+	return r.queryLinks(query, customExerciseID)
+}
+
+// FindByEquipmentID matches the ID against both sources, so callers do not need to know where the equipment lives
+func (r *CustomExerciseEquipmentRepository) FindByEquipmentID(gymID, equipmentID string) ([]*dto.CustomExerciseEquipment, error) {
+	query := fmt.Sprintf(selectLinks+" WHERE l.public_equipment_id = $1 OR l.gym_equipment_id = $1", pq.QuoteIdentifier(gymID))
+	return r.queryLinks(query, equipmentID)
+}
+
+func (r *CustomExerciseEquipmentRepository) queryLinks(query string, arg string) ([]*dto.CustomExerciseEquipment, error) {
+	rows, err := r.db.Query(query, arg)
 	if err != nil {
 		return nil, err
 	}
@@ -75,31 +90,26 @@ func (r *CustomExerciseEquipmentRepository) FindByCustomExerciseID(gymID, custom
 
 	var links []*dto.CustomExerciseEquipment
 	for rows.Next() {
-		var link dto.CustomExerciseEquipment
-		if err := rows.Scan(&link.ID, &link.EquipmentID); err != nil {
+		link, err := scanLink(rows)
+		if err != nil {
 			return nil, err
 		}
-		links = append(links, &link)
+		links = append(links, link)
 	}
-	return links, nil
+	return links, rows.Err()
 }
 
-func (r *CustomExerciseEquipmentRepository) FindByEquipmentID(gymID, equipmentID string) ([]*dto.CustomExerciseEquipment, error) {
-	schema := gymID
-	query := fmt.Sprintf("SELECT id, custom_exercise_id FROM %s.custom_exercise_equipment WHERE equipment_id = $1", schema)
-	rows, err := r.db.Query(query, equipmentID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	var links []*dto.CustomExerciseEquipment
-	for rows.Next() {
-		var link dto.CustomExerciseEquipment
-		if err := rows.Scan(&link.ID, &link.CustomExerciseID); err != nil {
-			return nil, err
-		}
-		links = append(links, &link)
+func scanLink(row rowScanner) (*dto.CustomExerciseEquipment, error) {
+	var link dto.CustomExerciseEquipment
+	var name, category sql.NullString
+	if err := row.Scan(&link.ID, &link.CustomExerciseID, &link.EquipmentSource, &link.PublicEquipmentID, &link.GymEquipmentID, &name, &category); err != nil {
+		return nil, err
 	}
-	return links, nil
+	link.EquipmentName = name.String
+	link.EquipmentCategory = category.String
+	return &link, nil
 }
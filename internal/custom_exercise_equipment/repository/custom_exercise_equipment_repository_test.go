@@ -12,8 +12,11 @@ import (
 func TestCreateLink(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := NewCustomExerciseEquipmentRepository(db)
-	link := &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentID: "eq-1"}
-	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("link-1"))
+	equipmentID := "eq-1"
+	link := &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "gym", GymEquipmentID: &equipmentID}
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tenant1".custom_exercise_equipment (custom_exercise_id, equipment_source, public_equipment_id, gym_equipment_id)`)).
+		WithArgs("ex-1", "gym", nil, &equipmentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("link-1"))
 	id, err := repo.CreateLink("tenant1", link)
 	assert.NoError(t, err)
 	assert.NotNil(t, id)
@@ -31,9 +34,31 @@ func TestDeleteLink(t *testing.T) {
 func TestFindByID(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := NewCustomExerciseEquipmentRepository(db)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT custom_exercise_id, equipment_id FROM")).WillReturnRows(sqlmock.NewRows([]string{"custom_exercise_id", "equipment_id"}).AddRow("ex-1", "eq-1"))
+	mock.ExpectQuery(`SELECT l.id, l.custom_exercise_id, l.equipment_source, (.+) FROM "tenant1".custom_exercise_equipment l (.+) WHERE l.id = \$1`).
+		WillReturnRows(sqlmock.NewRows(linkColumns).AddRow("link-1", "ex-1", "public", "eq-1", nil, "Barbell", "free_weights"))
 	link, err := repo.FindByID("tenant1", "link-1")
 	assert.NoError(t, err)
 	assert.NotNil(t, link)
 	assert.Equal(t, "ex-1", link.CustomExerciseID)
+	assert.Equal(t, "eq-1", *link.PublicEquipmentID)
+	assert.Nil(t, link.GymEquipmentID)
+	assert.Equal(t, "Barbell", link.EquipmentName)
+}
+
+var linkColumns = []string{"id", "custom_exercise_id", "equipment_source", "public_equipment_id", "gym_equipment_id", "name", "category"}
+
+func TestFindByEquipmentID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewCustomExerciseEquipmentRepository(db)
+	mock.ExpectQuery(`WHERE l.public_equipment_id = \$1 OR l.gym_equipment_id = \$1`).
+		WithArgs("sled-1").
+		WillReturnRows(sqlmock.NewRows(linkColumns).AddRow("link-2", "ex-2", "gym", nil, "sled-1", "Sled", "custom"))
+	links, err := repo.FindByEquipmentID("tenant1", "sled-1")
+	assert.NoError(t, err)
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, "gym", links[0].EquipmentSource)
+		assert.Equal(t, "sled-1", *links[0].GymEquipmentID)
+		assert.Equal(t, "Sled", links[0].EquipmentName)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -3,9 +3,12 @@ package service
 import (
 	"database/sql"
 
+	customEquipmentDTO "github.com/alejandro-albiol/athenai/internal/custom_equipment/dto"
 	customEquipmentInterfaces "github.com/alejandro-albiol/athenai/internal/custom_equipment/interfaces"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/enum"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/interfaces"
+	publicEquipmentDTO "github.com/alejandro-albiol/athenai/internal/equipment/dto"
 	publicEquipmentInterfaces "github.com/alejandro-albiol/athenai/internal/equipment/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
@@ -30,22 +33,45 @@ func NewCustomExerciseEquipmentService(
 }
 
 func (s *CustomExerciseEquipmentService) CreateLink(gymID string, link *dto.CustomExerciseEquipment) (*string, error) {
-	// Validate equipment exists in either tenant or public table
-	var found bool
-	if s.customEquipmentRepo != nil {
-		eq, err := s.customEquipmentRepo.GetByID(gymID, link.EquipmentID)
-		if err == nil && eq != nil {
-			found = true
-		}
+	if link.CustomExerciseID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "CustomExerciseID is required", nil)
 	}
-	if !found && s.publicEquipmentRepo != nil {
-		eq, err := s.publicEquipmentRepo.GetEquipmentByID(link.EquipmentID)
-		if err == nil && eq.ID != "" {
-			found = true
+
+	// Validate equipment source and IDs
+	switch enum.EquipmentSource(link.EquipmentSource) {
+	case enum.SourcePublic:
+		if link.PublicEquipmentID == nil || *link.PublicEquipmentID == "" {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicEquipmentID is required when EquipmentSource is 'public'", nil)
+		}
+		if link.GymEquipmentID != nil && *link.GymEquipmentID != "" {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "GymEquipmentID must be empty when EquipmentSource is 'public'", nil)
 		}
+		link.GymEquipmentID = nil
+	case enum.SourceGym:
+		if link.GymEquipmentID == nil || *link.GymEquipmentID == "" {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "GymEquipmentID is required when EquipmentSource is 'gym'", nil)
+		}
+		if link.PublicEquipmentID != nil && *link.PublicEquipmentID != "" {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicEquipmentID must be empty when EquipmentSource is 'gym'", nil)
+		}
+		link.PublicEquipmentID = nil
+	default:
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "EquipmentSource must be 'public' or 'gym'", nil)
 	}
-	if !found {
-		return nil, apierror.New(errorcode_enum.CodeNotFound, "The equipment is nonexistent", nil)
+
+	// Validate the equipment exists in the catalog the source points to
+	if err := s.checkEquipment(gymID, link); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.FindByCustomExerciseID(gymID, link.CustomExerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check existing equipment links", err)
+	}
+	for _, other := range existing {
+		if equipmentID(other) == equipmentID(link) {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "The equipment is already linked to this custom exercise", nil)
+		}
 	}
 
 	id, err := s.repository.CreateLink(gymID, link)
@@ -55,6 +81,38 @@ func (s *CustomExerciseEquipmentService) CreateLink(gymID string, link *dto.Cust
 	return id, nil
 }
 
+func (s *CustomExerciseEquipmentService) checkEquipment(gymID string, link *dto.CustomExerciseEquipment) error {
+	var active bool
+	var err error
+	if link.EquipmentSource == string(enum.SourceGym) {
+		var eq *customEquipmentDTO.ResponseCustomEquipmentDTO
+		eq, err = s.customEquipmentRepo.GetByID(gymID, *link.GymEquipmentID)
+		active = err == nil && eq.IsActive
+	} else {
+		var eq *publicEquipmentDTO.EquipmentResponseDTO
+		eq, err = s.publicEquipmentRepo.GetEquipmentByID(*link.PublicEquipmentID)
+		active = err == nil && eq.IsActive
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to check equipment", err)
+	}
+	if !active {
+		return apierror.New(errorcode_enum.CodeNotFound, "The equipment is nonexistent", err)
+	}
+	return nil
+}
+
+// equipmentID returns the linked equipment ID regardless of its source
+func equipmentID(link *dto.CustomExerciseEquipment) string {
+	if link.GymEquipmentID != nil {
+		return *link.GymEquipmentID
+	}
+	if link.PublicEquipmentID != nil {
+		return *link.PublicEquipmentID
+	}
+	return ""
+}
+
 func (s *CustomExerciseEquipmentService) DeleteLink(gymID, id string) error {
 	err := s.repository.DeleteLink(gymID, id)
 	if err != nil {
@@ -1,19 +1,25 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 
 	customEquipmentDTO "github.com/alejandro-albiol/athenai/internal/custom_equipment/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/dto"
 	equipmentDTO "github.com/alejandro-albiol/athenai/internal/equipment/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockCustomEquipmentRepository struct{}
 
 func (f *mockCustomEquipmentRepository) GetByID(gymID, equipmentID string) (*customEquipmentDTO.ResponseCustomEquipmentDTO, error) {
-	return &customEquipmentDTO.ResponseCustomEquipmentDTO{ID: equipmentID}, nil
+	if equipmentID == "retired" {
+		return &customEquipmentDTO.ResponseCustomEquipmentDTO{ID: equipmentID}, nil
+	}
+	return &customEquipmentDTO.ResponseCustomEquipmentDTO{ID: equipmentID, IsActive: true}, nil
 }
 
 // Add the missing Create method to satisfy the interface
@@ -45,7 +51,10 @@ func (f *mockPublicEquipmentRepository) GetAllEquipment() ([]*equipmentDTO.Equip
 type mockPublicEquipmentRepository struct{}
 
 func (f *mockPublicEquipmentRepository) GetEquipmentByID(equipmentID string) (*equipmentDTO.EquipmentResponseDTO, error) {
-	return &equipmentDTO.EquipmentResponseDTO{ID: equipmentID}, nil
+	if equipmentID == "missing" {
+		return nil, sql.ErrNoRows
+	}
+	return &equipmentDTO.EquipmentResponseDTO{ID: equipmentID, IsActive: true}, nil
 }
 
 func (f *mockPublicEquipmentRepository) CreateEquipment(equipment *equipmentDTO.EquipmentCreationDTO) (*string, error) {
@@ -82,7 +91,10 @@ func (m *mockRepo) FindByID(gymID, id string) (*dto.CustomExerciseEquipment, err
 	return m.FindByIDFn(gymID, id)
 }
 func (m *mockRepo) FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseEquipment, error) {
-	return m.FindByCustomExerciseIDFn(gymID, customExerciseID)
+	if m.FindByCustomExerciseIDFn != nil {
+		return m.FindByCustomExerciseIDFn(gymID, customExerciseID)
+	}
+	return nil, nil
 }
 func (m *mockRepo) FindByEquipmentID(gymID, equipmentID string) ([]*dto.CustomExerciseEquipment, error) {
 	if m.FindByEquipmentIDFn != nil {
@@ -101,7 +113,7 @@ func (m *mockRepo) RemoveAllLinksForExercise(gymID, customExerciseID string) err
 func TestCreateLinkService(t *testing.T) {
 	repo := &mockRepo{
 		CreateLinkFn: func(gymID string, link *dto.CustomExerciseEquipment) (*string, error) {
-			if link.CustomExerciseID == "" || link.EquipmentID == "" {
+			if link.CustomExerciseID == "" || link.PublicEquipmentID == nil {
 				return nil, errors.New("missing fields")
 			}
 			id := "link-1"
@@ -114,20 +126,66 @@ func TestCreateLinkService(t *testing.T) {
 		customEquipmentRepo: &mockCustomEquipmentRepository{},
 		publicEquipmentRepo: &mockPublicEquipmentRepository{},
 	}
-	link := &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentID: "eq-1"}
+	equipmentID := "eq-1"
+	link := &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "public", PublicEquipmentID: &equipmentID}
 	id, err := svc.CreateLink("tenant1", link)
 	assert.NoError(t, err)
 	assert.NotNil(t, id)
 	assert.Equal(t, "link-1", *id)
 }
 
+func TestCreateLinkService_Validation(t *testing.T) {
+	sled := "sled-1"
+	retired := "retired"
+	missing := "missing"
+	repo := &mockRepo{
+		CreateLinkFn: func(gymID string, link *dto.CustomExerciseEquipment) (*string, error) {
+			id := "link-2"
+			return &id, nil
+		},
+		FindByCustomExerciseIDFn: func(gymID, customExerciseID string) ([]*dto.CustomExerciseEquipment, error) {
+			if customExerciseID == "ex-linked" {
+				return []*dto.CustomExerciseEquipment{{ID: "link-1", EquipmentSource: "gym", GymEquipmentID: &sled}}, nil
+			}
+			return nil, nil
+		},
+	}
+	svc := NewCustomExerciseEquipmentService(repo, &mockCustomEquipmentRepository{}, &mockPublicEquipmentRepository{})
+
+	tests := []struct {
+		name string
+		link *dto.CustomExerciseEquipment
+		code string
+	}{
+		{"gym equipment", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "gym", GymEquipmentID: &sled}, ""},
+		{"unknown source", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "partner", GymEquipmentID: &sled}, errorcode_enum.CodeBadRequest},
+		{"gym source without gym id", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "gym", PublicEquipmentID: &sled}, errorcode_enum.CodeBadRequest},
+		{"both ids", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "public", PublicEquipmentID: &sled, GymEquipmentID: &sled}, errorcode_enum.CodeBadRequest},
+		{"inactive gym equipment", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "gym", GymEquipmentID: &retired}, errorcode_enum.CodeNotFound},
+		{"missing public equipment", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-1", EquipmentSource: "public", PublicEquipmentID: &missing}, errorcode_enum.CodeNotFound},
+		{"already linked", &dto.CustomExerciseEquipment{CustomExerciseID: "ex-linked", EquipmentSource: "gym", GymEquipmentID: &sled}, errorcode_enum.CodeConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateLink("tenant1", tt.link)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if apiErr, ok := err.(*apierror.APIError); assert.True(t, ok) {
+				assert.Equal(t, tt.code, apiErr.Code)
+			}
+		})
+	}
+}
+
 func TestFindByIDService(t *testing.T) {
 	repo := &mockRepo{
 		FindByIDFn: func(gymID, id string) (*dto.CustomExerciseEquipment, error) {
 			if id == "notfound" {
 				return nil, errors.New("not found")
 			}
-			return &dto.CustomExerciseEquipment{ID: id, CustomExerciseID: "ex-1", EquipmentID: "eq-1"}, nil
+			return &dto.CustomExerciseEquipment{ID: id, CustomExerciseID: "ex-1", EquipmentSource: "public"}, nil
 		},
 	}
 	svc := &CustomExerciseEquipmentService{repository: repo}
@@ -1,7 +1,9 @@
 package dto
 
 type CustomExerciseMuscularGroup struct {
-	ID               string `json:"id"`
-	CustomExerciseID string `json:"custom_exercise_id"`
-	MuscularGroupID  string `json:"muscular_group_id"`
+	ID               string  `json:"id"`
+	CustomExerciseID string  `json:"custom_exercise_id"`
+	MuscularGroupID  string  `json:"muscular_group_id"`
+	Role             string  `json:"role"`
+	ActivationWeight float64 `json:"activation_weight"`
 }
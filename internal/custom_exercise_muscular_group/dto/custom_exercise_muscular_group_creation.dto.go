@@ -1,6 +1,8 @@
 package dto
 
 type CustomExerciseMuscularGroupCreationDTO struct {
-	CustomExerciseID string `json:"custom_exercise_id" validate:"required"`
-	MuscularGroupID  string `json:"muscular_group_id" validate:"required"`
+	CustomExerciseID string  `json:"custom_exercise_id" validate:"required"`
+	MuscularGroupID  string  `json:"muscular_group_id" validate:"required"`
+	Role             string  `json:"role" validate:"omitempty,oneof=primary secondary stabilizer"`
+	ActivationWeight float64 `json:"activation_weight" validate:"omitempty,gt=0,lte=1"`
 }
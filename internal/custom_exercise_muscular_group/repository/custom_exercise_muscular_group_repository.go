@@ -17,9 +17,9 @@ func NewCustomExerciseMuscularGroupRepository(db *sql.DB) *CustomExerciseMuscula
 
 func (r *CustomExerciseMuscularGroupRepository) CreateLink(gymID string, link *dto.CustomExerciseMuscularGroupCreationDTO) (*string, error) {
 	schema := gymID
-	query := fmt.Sprintf("INSERT INTO %s.custom_exercise_muscular_group (custom_exercise_id, muscular_group_id) VALUES ($1, $2) RETURNING id", schema)
+	query := fmt.Sprintf("INSERT INTO %s.custom_exercise_muscular_group (custom_exercise_id, muscular_group_id, role, activation_weight) VALUES ($1, $2, $3, $4) RETURNING id", schema)
 	var id string
-	err := r.db.QueryRow(query, link.CustomExerciseID, link.MuscularGroupID).Scan(&id)
+	err := r.db.QueryRow(query, link.CustomExerciseID, link.MuscularGroupID, link.Role, link.ActivationWeight).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
@@ -55,9 +55,9 @@ func (r *CustomExerciseMuscularGroupRepository) RemoveAllLinksForExercise(gymID,
 
 func (r *CustomExerciseMuscularGroupRepository) FindByID(gymID, id string) (*dto.CustomExerciseMuscularGroup, error) {
 	schema := gymID
-	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id FROM %s.custom_exercise_muscular_group WHERE id = $1", schema)
+	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM %s.custom_exercise_muscular_group WHERE id = $1", schema)
 	var link dto.CustomExerciseMuscularGroup
-	err := r.db.QueryRow(query, id).Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID)
+	err := r.db.QueryRow(query, id).Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (r *CustomExerciseMuscularGroupRepository) FindByID(gymID, id string) (*dto
 
 func (r *CustomExerciseMuscularGroupRepository) FindByCustomExerciseID(gymID, customExerciseID string) ([]*dto.CustomExerciseMuscularGroup, error) {
 	schema := gymID
-	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id FROM %s.custom_exercise_muscular_group WHERE custom_exercise_id = $1", schema)
+	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM %s.custom_exercise_muscular_group WHERE custom_exercise_id = $1", schema)
 	rows, err := r.db.Query(query, customExerciseID)
 	if err != nil {
 		return nil, err
@@ -76,7 +76,7 @@ func (r *CustomExerciseMuscularGroupRepository) FindByCustomExerciseID(gymID, cu
 	var links []*dto.CustomExerciseMuscularGroup
 	for rows.Next() {
 		var link dto.CustomExerciseMuscularGroup
-		if err := rows.Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID); err != nil {
+		if err := rows.Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight); err != nil {
 			return nil, err
 		}
 		links = append(links, &link)
@@ -86,7 +86,7 @@ func (r *CustomExerciseMuscularGroupRepository) FindByCustomExerciseID(gymID, cu
 
 func (r *CustomExerciseMuscularGroupRepository) FindByMuscularGroupID(gymID, muscularGroupID string) ([]*dto.CustomExerciseMuscularGroup, error) {
 	schema := gymID
-	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id FROM %s.custom_exercise_muscular_group WHERE muscular_group_id = $1", schema)
+	query := fmt.Sprintf("SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM %s.custom_exercise_muscular_group WHERE muscular_group_id = $1", schema)
 	rows, err := r.db.Query(query, muscularGroupID)
 	if err != nil {
 		return nil, err
@@ -96,7 +96,7 @@ func (r *CustomExerciseMuscularGroupRepository) FindByMuscularGroupID(gymID, mus
 	var links []*dto.CustomExerciseMuscularGroup
 	for rows.Next() {
 		var link dto.CustomExerciseMuscularGroup
-		if err := rows.Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID); err != nil {
+		if err := rows.Scan(&link.ID, &link.CustomExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight); err != nil {
 			return nil, err
 		}
 		links = append(links, &link)
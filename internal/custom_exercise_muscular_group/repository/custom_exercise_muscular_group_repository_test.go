@@ -12,9 +12,9 @@ func TestCreateLink_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := &CustomExerciseMuscularGroupRepository{db: db}
 	mock.ExpectQuery("INSERT INTO tenant1.custom_exercise_muscular_group").
-		WithArgs("ex1", "mg1").
+		WithArgs("ex1", "mg1", "secondary", 0.5).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("123"))
-	id, err := repo.CreateLink("tenant1", &dto.CustomExerciseMuscularGroupCreationDTO{CustomExerciseID: "ex1", MuscularGroupID: "mg1"})
+	id, err := repo.CreateLink("tenant1", &dto.CustomExerciseMuscularGroupCreationDTO{CustomExerciseID: "ex1", MuscularGroupID: "mg1", Role: "secondary", ActivationWeight: 0.5})
 	assert.NoError(t, err)
 	assert.NotNil(t, id)
 	assert.Equal(t, "123", *id)
@@ -43,10 +43,10 @@ func TestRemoveAllLinksForExercise_Success(t *testing.T) {
 func TestFindByID_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := &CustomExerciseMuscularGroupRepository{db: db}
-	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id FROM tenant1.custom_exercise_muscular_group WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM tenant1.custom_exercise_muscular_group WHERE id = \$1`).
 		WithArgs("id1").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id"}).
-			AddRow("id1", "ex1", "mg1"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id", "role", "activation_weight"}).
+			AddRow("id1", "ex1", "mg1", "primary", 1.0))
 	link, err := repo.FindByID("tenant1", "id1")
 	assert.NoError(t, err)
 	assert.NotNil(t, link)
@@ -56,10 +56,10 @@ func TestFindByID_Success(t *testing.T) {
 func TestFindByCustomExerciseID_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := &CustomExerciseMuscularGroupRepository{db: db}
-	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id FROM tenant1.custom_exercise_muscular_group WHERE custom_exercise_id = \$1`).
+	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM tenant1.custom_exercise_muscular_group WHERE custom_exercise_id = \$1`).
 		WithArgs("ex1").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id"}).
-			AddRow("id1", "ex1", "mg1"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id", "role", "activation_weight"}).
+			AddRow("id1", "ex1", "mg1", "primary", 1.0))
 	links, err := repo.FindByCustomExerciseID("tenant1", "ex1")
 	assert.NoError(t, err)
 	assert.Len(t, links, 1)
@@ -69,10 +69,10 @@ func TestFindByCustomExerciseID_Success(t *testing.T) {
 func TestFindByMuscularGroupID_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	repo := &CustomExerciseMuscularGroupRepository{db: db}
-	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id FROM tenant1.custom_exercise_muscular_group WHERE muscular_group_id = \$1`).
+	mock.ExpectQuery(`SELECT id, custom_exercise_id, muscular_group_id, role, activation_weight FROM tenant1.custom_exercise_muscular_group WHERE muscular_group_id = \$1`).
 		WithArgs("mg1").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id"}).
-			AddRow("id1", "ex1", "mg1"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "custom_exercise_id", "muscular_group_id", "role", "activation_weight"}).
+			AddRow("id1", "ex1", "mg1", "primary", 1.0))
 	links, err := repo.FindByMuscularGroupID("tenant1", "mg1")
 	assert.NoError(t, err)
 	assert.Len(t, links, 1)
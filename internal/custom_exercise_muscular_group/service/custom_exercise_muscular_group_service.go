@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_muscular_group/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_exercise_muscular_group/interfaces"
+	involvement_enum "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/enum"
 	publicMuscularGroupInterfaces "github.com/alejandro-albiol/athenai/internal/muscular_group/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
@@ -21,6 +22,11 @@ func NewCustomExerciseMuscularGroupService(repository interfaces.CustomExerciseM
 }
 
 func (s *CustomExerciseMuscularGroupService) CreateLink(gymID string, link *dto.CustomExerciseMuscularGroupCreationDTO) (*string, error) {
+	var msg string
+	link.Role, link.ActivationWeight, msg = involvement_enum.NormalizeInvolvement(link.Role, link.ActivationWeight)
+	if msg != "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, msg, nil)
+	}
 
 	var found bool
 	if s.publicMuscularGroupRepo != nil {
@@ -70,6 +70,34 @@ func TestCreateLink_Success(t *testing.T) {
 	assert.NotNil(t, id)
 }
 
+func TestCreateLink_InvalidRole(t *testing.T) {
+	repo := &mockRepo{
+		CreateLinkFn: func(gymID string, req *dto.CustomExerciseMuscularGroupCreationDTO) (*string, error) {
+			t.Fatal("repository should not be called for an invalid role")
+			return nil, nil
+		},
+	}
+	svc := &CustomExerciseMuscularGroupService{repository: repo, publicMuscularGroupRepo: &mockPublicMuscularGroupRepo{}}
+	id, err := svc.CreateLink("tenant1", &dto.CustomExerciseMuscularGroupCreationDTO{CustomExerciseID: "ex1", MuscularGroupID: "mg1", Role: "assistant"})
+	assert.Error(t, err)
+	assert.Nil(t, id)
+}
+
+func TestCreateLink_DefaultsActivationWeight(t *testing.T) {
+	var stored *dto.CustomExerciseMuscularGroupCreationDTO
+	repo := &mockRepo{
+		CreateLinkFn: func(gymID string, req *dto.CustomExerciseMuscularGroupCreationDTO) (*string, error) {
+			stored = req
+			id := "123"
+			return &id, nil
+		},
+	}
+	svc := &CustomExerciseMuscularGroupService{repository: repo, publicMuscularGroupRepo: &mockPublicMuscularGroupRepo{}}
+	_, err := svc.CreateLink("tenant1", &dto.CustomExerciseMuscularGroupCreationDTO{CustomExerciseID: "ex1", MuscularGroupID: "mg1", Role: "secondary"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, stored.ActivationWeight)
+}
+
 func TestDeleteLink_Success(t *testing.T) {
 	repo := &mockRepo{
 		DeleteLinkFn: func(gymID, id string) error { return nil },
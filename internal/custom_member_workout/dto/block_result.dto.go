@@ -0,0 +1,26 @@
+package dto
+
+// RecordBlockResultDTO is the result of one timed block in a session. Which fields apply depends on the block mode:
+// AMRAP takes rounds (plus reps into the unfinished round), For-Time a finish time, or the rounds reached
+// when the cap ran out, and EMOM, Tabata and intervals the rounds completed.
+type RecordBlockResultDTO struct {
+	MemberWorkoutID   string  `json:"-"`
+	BlockName         string  `json:"block_name"`
+	RoundsCompleted   *int    `json:"rounds_completed,omitempty"`
+	ExtraReps         *int    `json:"extra_reps,omitempty"`
+	FinishTimeSeconds *int    `json:"finish_time_seconds,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+}
+
+type BlockResultDTO struct {
+	ID                string  `json:"id"`
+	MemberWorkoutID   string  `json:"member_workout_id"`
+	BlockName         string  `json:"block_name"`
+	BlockMode         string  `json:"block_mode"`
+	RoundsCompleted   *int    `json:"rounds_completed,omitempty"`
+	ExtraReps         *int    `json:"extra_reps,omitempty"`
+	FinishTimeSeconds *int    `json:"finish_time_seconds,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
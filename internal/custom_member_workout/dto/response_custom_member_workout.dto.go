@@ -1,15 +1,21 @@
 package dto
 
+import record_dto "github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+
 type ResponseCustomMemberWorkoutDTO struct {
 	ID                string  `json:"id"`
 	MemberID          string  `json:"member_id"`
 	WorkoutInstanceID string  `json:"workout_instance_id"`
 	ScheduledDate     string  `json:"scheduled_date"`
 	StartedAt         *string `json:"started_at,omitempty"`
+	PausedAt          *string `json:"paused_at,omitempty"`
+	PausedSeconds     int     `json:"paused_seconds"`
 	CompletedAt       *string `json:"completed_at,omitempty"`
 	Status            string  `json:"status"`
 	Notes             *string `json:"notes,omitempty"`
 	Rating            *int    `json:"rating,omitempty"`
 	CreatedAt         string  `json:"created_at"`
 	UpdatedAt         string  `json:"updated_at"`
+	// NewRecords are the personal records the session beat; only given when a single workout is fetched
+	NewRecords []*record_dto.PersonalRecordDTO `json:"new_records,omitempty"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+import record_dto "github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+
+// LogSetDTO is one set a member did of a workout exercise. The set number defaults to the next one for the exercise.
+type LogSetDTO struct {
+	MemberWorkoutID   string   `json:"-"`
+	WorkoutExerciseID string   `json:"workout_exercise_id"`
+	SetNumber         *int     `json:"set_number,omitempty"`
+	Reps              *int     `json:"reps,omitempty"`
+	WeightKg          *float64 `json:"weight_kg,omitempty"`
+	DurationSeconds   *int     `json:"duration_seconds,omitempty"`
+	DistanceMeters    *float64 `json:"distance_meters,omitempty"`
+	RPE               *float64 `json:"rpe,omitempty"`
+	RIR               *int     `json:"rir,omitempty"`
+	Completed         *bool    `json:"completed,omitempty"` // true unless given
+	Notes             *string  `json:"notes,omitempty"`
+}
+
+// UpdateSetLogDTO corrects a logged set; only the given fields change
+type UpdateSetLogDTO struct {
+	ID              string   `json:"-"`
+	MemberWorkoutID string   `json:"-"`
+	Reps            *int     `json:"reps,omitempty"`
+	WeightKg        *float64 `json:"weight_kg,omitempty"`
+	DurationSeconds *int     `json:"duration_seconds,omitempty"`
+	DistanceMeters  *float64 `json:"distance_meters,omitempty"`
+	RPE             *float64 `json:"rpe,omitempty"`
+	RIR             *int     `json:"rir,omitempty"`
+	Completed       *bool    `json:"completed,omitempty"`
+	Notes           *string  `json:"notes,omitempty"`
+}
+
+type SetLogDTO struct {
+	ID                string   `json:"id"`
+	MemberWorkoutID   string   `json:"member_workout_id"`
+	WorkoutExerciseID string   `json:"workout_exercise_id"`
+	SetNumber         int      `json:"set_number"`
+	Reps              *int     `json:"reps,omitempty"`
+	WeightKg          *float64 `json:"weight_kg,omitempty"`
+	DurationSeconds   *int     `json:"duration_seconds,omitempty"`
+	DistanceMeters    *float64 `json:"distance_meters,omitempty"`
+	RPE               *float64 `json:"rpe,omitempty"`
+	RIR               *int     `json:"rir,omitempty"`
+	Completed         bool     `json:"completed"`
+	Notes             *string  `json:"notes,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+	// NewRecords are the personal records the set beat when it was logged or corrected
+	NewRecords []*record_dto.PersonalRecordDTO `json:"new_records,omitempty"`
+}
@@ -1,10 +1,9 @@
 package dto
 
+// UpdateCustomMemberWorkoutDTO changes the notes and rating of a member workout; its status and timestamps
+// only change through transitions
 type UpdateCustomMemberWorkoutDTO struct {
-	ID          string  `json:"id"`
-	StartedAt   *string `json:"started_at,omitempty"`
-	CompletedAt *string `json:"completed_at,omitempty"`
-	Status      *string `json:"status,omitempty"`
-	Notes       *string `json:"notes,omitempty"`
-	Rating      *int    `json:"rating,omitempty"`
+	ID     string  `json:"id"`
+	Notes  *string `json:"notes,omitempty"`
+	Rating *int    `json:"rating,omitempty"`
 }
@@ -0,0 +1,26 @@
+package dto
+
+// TransitionMemberWorkoutDTO moves a member workout to a new status and records the transition as an event
+type TransitionMemberWorkoutDTO struct {
+	MemberWorkoutID string  `json:"-"`
+	Transition      string  `json:"-"`
+	Note            *string `json:"note,omitempty"`
+	// Reason is why the workout is skipped; only given with skip
+	Reason    *string `json:"reason,omitempty"`
+	ChangedBy string  `json:"-"`
+}
+
+// MemberWorkoutEventDTO is one status transition of a member workout
+type MemberWorkoutEventDTO struct {
+	ID              string  `json:"id"`
+	Sequence        int64   `json:"sequence"`
+	MemberWorkoutID string  `json:"member_workout_id"`
+	MemberID        string  `json:"member_id"`
+	Transition      string  `json:"transition"`
+	FromStatus      string  `json:"from_status"`
+	ToStatus        string  `json:"to_status"`
+	Note            *string `json:"note,omitempty"`
+	Reason          *string `json:"reason,omitempty"`
+	ChangedBy       *string `json:"changed_by,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package enum
+
+// SkipReason is why a member skipped a workout, counted by member engagement
+type SkipReason string
+
+const (
+	SkipIllness          SkipReason = "illness"
+	SkipInjury           SkipReason = "injury"
+	SkipFatigue          SkipReason = "fatigue"
+	SkipScheduleConflict SkipReason = "schedule_conflict"
+	SkipTravel           SkipReason = "travel"
+	SkipMotivation       SkipReason = "motivation"
+	SkipOther            SkipReason = "other"
+)
+
+func (r SkipReason) IsValid() bool {
+	switch r {
+	case SkipIllness, SkipInjury, SkipFatigue, SkipScheduleConflict, SkipTravel, SkipMotivation, SkipOther:
+		return true
+	}
+	return false
+}
@@ -5,6 +5,7 @@ type CustomMemberWorkoutStatus string
 const (
 	StatusScheduled  CustomMemberWorkoutStatus = "scheduled"
 	StatusInProgress CustomMemberWorkoutStatus = "in_progress"
+	StatusPaused     CustomMemberWorkoutStatus = "paused"
 	StatusCompleted  CustomMemberWorkoutStatus = "completed"
 	StatusSkipped    CustomMemberWorkoutStatus = "skipped"
 	StatusCancelled  CustomMemberWorkoutStatus = "cancelled"
@@ -12,7 +13,7 @@ const (
 
 func (e CustomMemberWorkoutStatus) IsValid() bool {
 	switch e {
-	case StatusScheduled, StatusInProgress, StatusCompleted, StatusSkipped, StatusCancelled:
+	case StatusScheduled, StatusInProgress, StatusPaused, StatusCompleted, StatusSkipped, StatusCancelled:
 		return true
 	}
 	return false
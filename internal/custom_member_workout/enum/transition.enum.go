@@ -0,0 +1,66 @@
+package enum
+
+// WorkoutTransition moves a member workout between statuses. Completed, skipped and cancelled workouts are final.
+type WorkoutTransition string
+
+const (
+	TransitionStart    WorkoutTransition = "start"
+	TransitionPause    WorkoutTransition = "pause"
+	TransitionResume   WorkoutTransition = "resume"
+	TransitionComplete WorkoutTransition = "complete"
+	TransitionSkip     WorkoutTransition = "skip"
+	TransitionCancel   WorkoutTransition = "cancel"
+)
+
+func (t WorkoutTransition) IsValid() bool {
+	switch t {
+	case TransitionStart, TransitionPause, TransitionResume, TransitionComplete, TransitionSkip, TransitionCancel:
+		return true
+	}
+	return false
+}
+
+// Target is the status the transition moves a workout to
+func (t WorkoutTransition) Target() CustomMemberWorkoutStatus {
+	switch t {
+	case TransitionStart, TransitionResume:
+		return StatusInProgress
+	case TransitionPause:
+		return StatusPaused
+	case TransitionComplete:
+		return StatusCompleted
+	case TransitionSkip:
+		return StatusSkipped
+	case TransitionCancel:
+		return StatusCancelled
+	}
+	return ""
+}
+
+// AllowedFrom reports whether a workout in the given status can take the transition
+func (t WorkoutTransition) AllowedFrom(status CustomMemberWorkoutStatus) bool {
+	switch t {
+	case TransitionStart, TransitionSkip:
+		return status == StatusScheduled
+	case TransitionPause:
+		return status == StatusInProgress
+	case TransitionResume:
+		return status == StatusPaused
+	case TransitionComplete:
+		return status == StatusInProgress || status == StatusPaused
+	case TransitionCancel:
+		return status == StatusScheduled || status == StatusInProgress || status == StatusPaused
+	}
+	return false
+}
+
+// TransitionsFrom lists the transitions a workout in the given status can take
+func TransitionsFrom(status CustomMemberWorkoutStatus) []WorkoutTransition {
+	allowed := []WorkoutTransition{}
+	for _, t := range []WorkoutTransition{TransitionStart, TransitionPause, TransitionResume, TransitionComplete, TransitionSkip, TransitionCancel} {
+		if t.AllowedFrom(status) {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
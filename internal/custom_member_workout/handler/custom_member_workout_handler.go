@@ -2,11 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/enum"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
@@ -29,12 +33,23 @@ func (h *CustomMemberWorkoutHandler) Create(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	gymID := middleware.GetGymID(r)
-	id, err := h.Service.CreateCustomMemberWorkout(gymID, &req)
+	id, warnings, err := h.Service.CreateCustomMemberWorkout(gymID, &req)
 	if err != nil {
+		// Validation and blocked contraindications keep their own status code
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			response.WriteAPIError(w, apiErr)
+			return
+		}
 		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeInternal, "Failed to create custom member workout", err))
 		return
 	}
-	response.WriteAPICreated(w, "Custom member workout created", id)
+	message := "Custom member workout created"
+	if len(warnings) > 0 {
+		message = "Custom member workout created with contraindication warnings"
+	} else {
+		warnings = []*contraindication_dto.ContraindicationWarning{}
+	}
+	response.WriteAPICreatedWithDetails(w, message, id, map[string]any{"warnings": warnings})
 }
 
 func (h *CustomMemberWorkoutHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -85,3 +100,161 @@ func (h *CustomMemberWorkoutHandler) Delete(w http.ResponseWriter, r *http.Reque
 	}
 	response.WriteAPISuccess(w, "Custom member workout deleted", nil)
 }
+
+func (h *CustomMemberWorkoutHandler) RecordBlockResult(w http.ResponseWriter, r *http.Request) {
+	var req dto.RecordBlockResultDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	gymID := middleware.GetGymID(r)
+	req.MemberWorkoutID = chi.URLParam(r, "id")
+	res, err := h.Service.RecordBlockResult(gymID, &req)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to record block result")
+		return
+	}
+	response.WriteAPISuccess(w, "Block result recorded", res)
+}
+
+func (h *CustomMemberWorkoutHandler) ListBlockResults(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	res, err := h.Service.ListBlockResults(gymID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list block results")
+		return
+	}
+	response.WriteAPISuccess(w, "Block results fetched", res)
+}
+
+func (h *CustomMemberWorkoutHandler) LogSet(w http.ResponseWriter, r *http.Request) {
+	var req dto.LogSetDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	gymID := middleware.GetGymID(r)
+	req.MemberWorkoutID = chi.URLParam(r, "id")
+	res, err := h.Service.LogSet(gymID, &req)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to log set")
+		return
+	}
+	response.WriteAPICreated(w, "Set logged", res)
+}
+
+func (h *CustomMemberWorkoutHandler) ListSetLogs(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	res, err := h.Service.ListSetLogs(gymID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list logged sets")
+		return
+	}
+	response.WriteAPISuccess(w, "Logged sets fetched", res)
+}
+
+func (h *CustomMemberWorkoutHandler) UpdateSetLog(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateSetLogDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	gymID := middleware.GetGymID(r)
+	req.MemberWorkoutID = chi.URLParam(r, "id")
+	req.ID = chi.URLParam(r, "setID")
+	res, err := h.Service.UpdateSetLog(gymID, &req)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update logged set")
+		return
+	}
+	response.WriteAPISuccess(w, "Logged set updated", res)
+}
+
+func (h *CustomMemberWorkoutHandler) DeleteSetLog(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	err := h.Service.DeleteSetLog(gymID, chi.URLParam(r, "id"), chi.URLParam(r, "setID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete logged set")
+		return
+	}
+	response.WriteAPISuccess(w, "Logged set deleted", nil)
+}
+
+func (h *CustomMemberWorkoutHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionStart)
+}
+
+func (h *CustomMemberWorkoutHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionPause)
+}
+
+func (h *CustomMemberWorkoutHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionResume)
+}
+
+func (h *CustomMemberWorkoutHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionComplete)
+}
+
+func (h *CustomMemberWorkoutHandler) Skip(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionSkip)
+}
+
+func (h *CustomMemberWorkoutHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, enum.TransitionCancel)
+}
+
+// transition applies a status transition; the body, with an optional note, may be empty
+func (h *CustomMemberWorkoutHandler) transition(w http.ResponseWriter, r *http.Request, transition enum.WorkoutTransition) {
+	var req dto.TransitionMemberWorkoutDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	gymID := middleware.GetGymID(r)
+	req.MemberWorkoutID = chi.URLParam(r, "id")
+	req.Transition = string(transition)
+	req.ChangedBy = middleware.GetUserID(r)
+	res, err := h.Service.TransitionCustomMemberWorkout(gymID, &req)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update custom member workout status")
+		return
+	}
+	response.WriteAPISuccess(w, "Custom member workout status updated", res)
+}
+
+func (h *CustomMemberWorkoutHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	gymID := middleware.GetGymID(r)
+	res, err := h.Service.ListEvents(gymID, chi.URLParam(r, "id"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list workout events")
+		return
+	}
+	response.WriteAPISuccess(w, "Workout events fetched", res)
+}
+
+func (h *CustomMemberWorkoutHandler) ListGymEvents(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "limit must be a number", err))
+			return
+		}
+	}
+	var after int64
+	if value := r.URL.Query().Get("after"); value != "" {
+		var err error
+		if after, err = strconv.ParseInt(value, 10, 64); err != nil {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "after must be a number", err))
+			return
+		}
+	}
+	gymID := middleware.GetGymID(r)
+	res, err := h.Service.ListGymEvents(gymID, after, limit)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list workout events")
+		return
+	}
+	response.WriteAPISuccess(w, "Workout events fetched", res)
+}
@@ -8,8 +8,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/handler"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,10 +23,21 @@ type mockService struct {
 	ListByMemberIDFn func(string, string) ([]*dto.ResponseCustomMemberWorkoutDTO, error)
 	UpdateFn         func(string, *dto.UpdateCustomMemberWorkoutDTO) error
 	DeleteFn         func(string, string) error
+	RecordResultFn   func(string, *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error)
+	ListResultsFn    func(string, string) ([]*dto.BlockResultDTO, error)
+	LogSetFn         func(string, *dto.LogSetDTO) (*dto.SetLogDTO, error)
+	ListSetsFn       func(string, string) ([]*dto.SetLogDTO, error)
+	UpdateSetFn      func(string, *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error)
+	DeleteSetFn      func(string, string, string) error
+	TransitionFn     func(string, *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error)
+	ListEventsFn     func(string, string) ([]*dto.MemberWorkoutEventDTO, error)
+	GymEventsFn      func(string, int64, int) ([]*dto.MemberWorkoutEventDTO, error)
+	CreateWarnings   []*contraindication_dto.ContraindicationWarning
 }
 
-func (m *mockService) CreateCustomMemberWorkout(gymID string, d *dto.CreateCustomMemberWorkoutDTO) (*string, error) {
-	return m.CreateFn(gymID, d)
+func (m *mockService) CreateCustomMemberWorkout(gymID string, d *dto.CreateCustomMemberWorkoutDTO) (*string, []*contraindication_dto.ContraindicationWarning, error) {
+	id, err := m.CreateFn(gymID, d)
+	return id, m.CreateWarnings, err
 }
 func (m *mockService) GetCustomMemberWorkoutByID(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
 	return m.GetByIDFn(gymID, id)
@@ -37,6 +51,35 @@ func (m *mockService) UpdateCustomMemberWorkout(gymID string, d *dto.UpdateCusto
 func (m *mockService) DeleteCustomMemberWorkout(gymID, id string) error {
 	return m.DeleteFn(gymID, id)
 }
+func (m *mockService) RecordBlockResult(gymID string, d *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+	return m.RecordResultFn(gymID, d)
+}
+func (m *mockService) ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error) {
+	return m.ListResultsFn(gymID, memberWorkoutID)
+}
+
+func (m *mockService) LogSet(gymID string, d *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+	return m.LogSetFn(gymID, d)
+}
+func (m *mockService) ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error) {
+	return m.ListSetsFn(gymID, memberWorkoutID)
+}
+func (m *mockService) UpdateSetLog(gymID string, d *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error) {
+	return m.UpdateSetFn(gymID, d)
+}
+func (m *mockService) DeleteSetLog(gymID, memberWorkoutID, id string) error {
+	return m.DeleteSetFn(gymID, memberWorkoutID, id)
+}
+
+func (m *mockService) TransitionCustomMemberWorkout(gymID string, d *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+	return m.TransitionFn(gymID, d)
+}
+func (m *mockService) ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error) {
+	return m.ListEventsFn(gymID, memberWorkoutID)
+}
+func (m *mockService) ListGymEvents(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error) {
+	return m.GymEventsFn(gymID, after, limit)
+}
 
 func TestCreateCustomMemberWorkoutHandler_BadRequest(t *testing.T) {
 	h := handler.NewCustomMemberWorkoutHandler(&mockService{})
@@ -74,3 +117,90 @@ func TestUpdateCustomMemberWorkoutHandler_BadRequest(t *testing.T) {
 	h.Update(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestRecordBlockResultHandler_ValidationError(t *testing.T) {
+	var got *dto.RecordBlockResultDTO
+	h := handler.NewCustomMemberWorkoutHandler(&mockService{
+		RecordResultFn: func(gymID string, d *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+			got = d
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "amrap results need rounds_completed", nil)
+		},
+	})
+	req := httptest.NewRequest(http.MethodPut, "/custom-member-workout/123/block-results", bytes.NewBufferString(`{"block_name":"Finisher"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.RecordBlockResult(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "123", got.MemberWorkoutID)
+	assert.Equal(t, "Finisher", got.BlockName)
+}
+
+func TestLogSetHandler_Created(t *testing.T) {
+	h := handler.NewCustomMemberWorkoutHandler(&mockService{
+		LogSetFn: func(gymID string, d *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+			return &dto.SetLogDTO{ID: "set-1", MemberWorkoutID: d.MemberWorkoutID, WorkoutExerciseID: d.WorkoutExerciseID, SetNumber: 1, Reps: d.Reps, Completed: true}, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/custom-member-workout/123/sets", bytes.NewBufferString(`{"workout_exercise_id":"we-1","reps":8,"weight_kg":60}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.LogSet(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"member_workout_id":"123"`)
+}
+
+func TestUpdateSetLogHandler_NotFound(t *testing.T) {
+	h := handler.NewCustomMemberWorkoutHandler(&mockService{
+		UpdateSetFn: func(gymID string, d *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Logged set not found", nil)
+		},
+	})
+	req := httptest.NewRequest(http.MethodPut, "/custom-member-workout/123/sets/s1", bytes.NewBufferString(`{"reps":6}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	rctx.URLParams.Add("setID", "s1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.UpdateSetLog(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCompleteHandler(t *testing.T) {
+	var got *dto.TransitionMemberWorkoutDTO
+	h := handler.NewCustomMemberWorkoutHandler(&mockService{
+		TransitionFn: func(gymID string, d *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+			got = d
+			return &dto.ResponseCustomMemberWorkoutDTO{ID: d.MemberWorkoutID, Status: "completed"}, nil
+		},
+	})
+	// The body is optional
+	req := httptest.NewRequest(http.MethodPost, "/custom-member-workout/123/complete", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.Complete(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "complete", got.Transition)
+	assert.Equal(t, "123", got.MemberWorkoutID)
+}
+
+func TestStartHandler_InvalidTransition(t *testing.T) {
+	h := handler.NewCustomMemberWorkoutHandler(&mockService{
+		TransitionFn: func(gymID string, d *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+			return nil, apierror.New(errorcode_enum.CodeInvalidTransition, "Cannot start a completed workout", nil)
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/custom-member-workout/123/start", bytes.NewBufferString(`{"note":"again"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	h.Start(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"INVALID_TRANSITION"`)
+}
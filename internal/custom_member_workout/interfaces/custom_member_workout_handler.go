@@ -10,4 +10,18 @@ type CustomMemberWorkoutHandler interface {
 	ListByMemberID(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	Start(w http.ResponseWriter, r *http.Request)
+	Pause(w http.ResponseWriter, r *http.Request)
+	Resume(w http.ResponseWriter, r *http.Request)
+	Complete(w http.ResponseWriter, r *http.Request)
+	Skip(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+	ListEvents(w http.ResponseWriter, r *http.Request)
+	ListGymEvents(w http.ResponseWriter, r *http.Request)
+	RecordBlockResult(w http.ResponseWriter, r *http.Request)
+	ListBlockResults(w http.ResponseWriter, r *http.Request)
+	LogSet(w http.ResponseWriter, r *http.Request)
+	ListSetLogs(w http.ResponseWriter, r *http.Request)
+	UpdateSetLog(w http.ResponseWriter, r *http.Request)
+	DeleteSetLog(w http.ResponseWriter, r *http.Request)
 }
@@ -1,11 +1,26 @@
 package interfaces
 
-import "github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+)
 
 type CustomMemberWorkoutRepository interface {
 	Create(gymID string, memberWorkout *dto.CreateCustomMemberWorkoutDTO) (*string, error)
 	GetByID(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error)
 	ListByMemberID(gymID, memberID string) ([]*dto.ResponseCustomMemberWorkoutDTO, error)
 	Update(gymID string, memberWorkout *dto.UpdateCustomMemberWorkoutDTO) error
-	Delete(gymID, id string) error
+	// Delete, Transition, CreateSetLog, UpdateSetLog and DeleteSetLog run their hook, when set, in the write's transaction
+	Delete(gymID, id string, onDeleted func(tx *sql.Tx, memberID string) error) error
+	Transition(gymID string, memberWorkout *dto.ResponseCustomMemberWorkoutDTO, toStatus string, transition *dto.TransitionMemberWorkoutDTO, onTransition func(tx *sql.Tx) error) error
+	ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error)
+	ListEventsAfter(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error)
+	UpsertBlockResult(gymID, blockMode string, result *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error)
+	ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error)
+	IsInstanceExercise(gymID, instanceID, workoutExerciseID string) (bool, error)
+	CreateSetLog(gymID string, set *dto.LogSetDTO, onLogged func(tx *sql.Tx, logged *dto.SetLogDTO) error) (*dto.SetLogDTO, error)
+	ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error)
+	UpdateSetLog(gymID string, set *dto.UpdateSetLogDTO, onUpdated func(tx *sql.Tx, updated *dto.SetLogDTO) error) (*dto.SetLogDTO, error)
+	DeleteSetLog(gymID, memberWorkoutID, id string, onDeleted func(tx *sql.Tx) error) error
 }
@@ -1,11 +1,23 @@
 package interfaces
 
-import "github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+import (
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+)
 
 type CustomMemberWorkoutService interface {
-	CreateCustomMemberWorkout(gymID string, memberWorkout *dto.CreateCustomMemberWorkoutDTO) (*string, error)
+	CreateCustomMemberWorkout(gymID string, memberWorkout *dto.CreateCustomMemberWorkoutDTO) (*string, []*contraindication_dto.ContraindicationWarning, error)
 	GetCustomMemberWorkoutByID(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error)
 	ListCustomMemberWorkoutsByMemberID(gymID, memberID string) ([]*dto.ResponseCustomMemberWorkoutDTO, error)
 	UpdateCustomMemberWorkout(gymID string, memberWorkout *dto.UpdateCustomMemberWorkoutDTO) error
 	DeleteCustomMemberWorkout(gymID, id string) error
+	TransitionCustomMemberWorkout(gymID string, transition *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error)
+	ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error)
+	ListGymEvents(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error)
+	RecordBlockResult(gymID string, result *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error)
+	ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error)
+	LogSet(gymID string, set *dto.LogSetDTO) (*dto.SetLogDTO, error)
+	ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error)
+	UpdateSetLog(gymID string, set *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error)
+	DeleteSetLog(gymID, memberWorkoutID, id string) error
 }
@@ -4,15 +4,30 @@ import (
 	"database/sql"
 	"net/http"
 
+	contraindication_repository "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/repository"
+	contraindication_service "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/service"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/handler"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/repository"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/router"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/service"
+	instance_repository "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/repository"
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	engagement_repository "github.com/alejandro-albiol/athenai/internal/member_engagement/repository"
+	engagement_service "github.com/alejandro-albiol/athenai/internal/member_engagement/service"
+	record_repository "github.com/alejandro-albiol/athenai/internal/personal_record/repository"
+	record_service "github.com/alejandro-albiol/athenai/internal/personal_record/service"
 )
 
 func NewCustomMemberWorkoutModule(db *sql.DB) http.Handler {
 	repo := repository.NewCustomMemberWorkoutRepository(db)
-	service := service.NewCustomMemberWorkoutService(repo)
+	gyms := gym_repository.NewGymRepository(db)
+	checker := contraindication_service.NewContraindicationChecker(
+		contraindication_repository.NewCustomExerciseContraindicationRepository(db),
+		gyms,
+	)
+	records := record_service.NewPersonalRecordService(record_repository.NewPersonalRecordRepository(db))
+	engagement := engagement_service.NewMemberEngagementService(engagement_repository.NewMemberEngagementRepository(db), gyms)
+	service := service.NewCustomMemberWorkoutService(repo, checker, instance_repository.NewCustomWorkoutInstanceRepository(db), records, engagement)
 	handler := handler.NewCustomMemberWorkoutHandler(service)
 	return router.NewCustomMemberWorkoutRouter(handler)
 }
@@ -39,7 +39,7 @@ func (r *CustomMemberWorkoutRepository) Create(gymID string, memberWorkout *dto.
 }
 
 func (r *CustomMemberWorkoutRepository) GetByID(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
-	query := `SELECT id, member_id, workout_instance_id, scheduled_date, started_at, completed_at, status, notes, rating, created_at, updated_at
+	query := `SELECT id, member_id, workout_instance_id, scheduled_date, started_at, paused_at, paused_seconds, completed_at, status, notes, rating, created_at, updated_at
 		FROM "` + gymID + `".custom_member_workout WHERE id = $1`
 	row := r.DB.QueryRow(query, id)
 	var res dto.ResponseCustomMemberWorkoutDTO
@@ -49,6 +49,8 @@ func (r *CustomMemberWorkoutRepository) GetByID(gymID, id string) (*dto.Response
 		&res.WorkoutInstanceID,
 		&res.ScheduledDate,
 		&res.StartedAt,
+		&res.PausedAt,
+		&res.PausedSeconds,
 		&res.CompletedAt,
 		&res.Status,
 		&res.Notes,
@@ -63,7 +65,7 @@ func (r *CustomMemberWorkoutRepository) GetByID(gymID, id string) (*dto.Response
 }
 
 func (r *CustomMemberWorkoutRepository) ListByMemberID(gymID, memberID string) ([]*dto.ResponseCustomMemberWorkoutDTO, error) {
-	query := `SELECT id, member_id, workout_instance_id, scheduled_date, started_at, completed_at, status, notes, rating, created_at, updated_at
+	query := `SELECT id, member_id, workout_instance_id, scheduled_date, started_at, paused_at, paused_seconds, completed_at, status, notes, rating, created_at, updated_at
 		FROM "` + gymID + `".custom_member_workout WHERE member_id = $1 ORDER BY scheduled_date DESC`
 	rows, err := r.DB.Query(query, memberID)
 	if err != nil {
@@ -79,6 +81,8 @@ func (r *CustomMemberWorkoutRepository) ListByMemberID(gymID, memberID string) (
 			&res.WorkoutInstanceID,
 			&res.ScheduledDate,
 			&res.StartedAt,
+			&res.PausedAt,
+			&res.PausedSeconds,
 			&res.CompletedAt,
 			&res.Status,
 			&res.Notes,
@@ -96,18 +100,12 @@ func (r *CustomMemberWorkoutRepository) ListByMemberID(gymID, memberID string) (
 
 func (r *CustomMemberWorkoutRepository) Update(gymID string, memberWorkout *dto.UpdateCustomMemberWorkoutDTO) error {
 	query := `UPDATE "` + gymID + `".custom_member_workout SET
-	       started_at = COALESCE($1, started_at),
-	       completed_at = COALESCE($2, completed_at),
-	       status = COALESCE($3, status),
-	       notes = COALESCE($4, notes),
-	       rating = COALESCE($5, rating),
+	       notes = COALESCE($1, notes),
+	       rating = COALESCE($2, rating),
 	       updated_at = NOW()
-       WHERE id = $6`
+       WHERE id = $3`
 	res, err := r.DB.Exec(
 		query,
-		memberWorkout.StartedAt,
-		memberWorkout.CompletedAt,
-		memberWorkout.Status,
 		memberWorkout.Notes,
 		memberWorkout.Rating,
 		memberWorkout.ID,
@@ -125,9 +123,47 @@ func (r *CustomMemberWorkoutRepository) Update(gymID string, memberWorkout *dto.
 	return nil
 }
 
-func (r *CustomMemberWorkoutRepository) Delete(gymID, id string) error {
-	query := `DELETE FROM "` + gymID + `".custom_member_workout WHERE id = $1`
-	res, err := r.DB.Exec(query, id)
+// Delete removes the workout; onDeleted, when set, runs in the same transaction with the workout's member
+func (r *CustomMemberWorkoutRepository) Delete(gymID, id string, onDeleted func(tx *sql.Tx, memberID string) error) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM "` + gymID + `".custom_member_workout WHERE id = $1 RETURNING member_id`
+	var memberID string
+	if err := tx.QueryRow(query, id).Scan(&memberID); err != nil {
+		return err
+	}
+	if onDeleted != nil {
+		if err := onDeleted(tx, memberID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Transition moves the workout from its current status and records the event in the same transaction.
+// Starting stamps started_at and completing completed_at; the time between a pause and the next transition
+// adds to paused_seconds. It returns sql.ErrNoRows when another request changed the status first.
+// onTransition, when set, runs in the transaction after the event is recorded.
+func (r *CustomMemberWorkoutRepository) Transition(gymID string, memberWorkout *dto.ResponseCustomMemberWorkoutDTO, toStatus string, transition *dto.TransitionMemberWorkoutDTO, onTransition func(tx *sql.Tx) error) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE "` + gymID + `".custom_member_workout SET
+		status = $2,
+		started_at = CASE WHEN $4 = 'start' THEN NOW() ELSE started_at END,
+		completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE completed_at END,
+		paused_seconds = paused_seconds + COALESCE(EXTRACT(EPOCH FROM NOW() - paused_at)::INTEGER, 0),
+		paused_at = CASE WHEN $2 = 'paused' THEN NOW() ELSE NULL END,
+		updated_at = NOW()
+	WHERE id = $1 AND status = $3`
+	res, err := tx.Exec(query, memberWorkout.ID, toStatus, memberWorkout.Status, transition.Transition)
 	if err != nil {
 		return err
 	}
@@ -138,5 +174,318 @@ func (r *CustomMemberWorkoutRepository) Delete(gymID, id string) error {
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
-	return nil
+
+	// Transitions made by the system rather than a user have no changed_by
+	var changedBy *string
+	if transition.ChangedBy != "" {
+		changedBy = &transition.ChangedBy
+	}
+	if _, err := tx.Exec(eventLock, gymID); err != nil {
+		return err
+	}
+	query = `INSERT INTO "` + gymID + `".custom_member_workout_event (
+		member_workout_id, member_id, transition, from_status, to_status, note, reason, changed_by
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	if _, err := tx.Exec(query, memberWorkout.ID, memberWorkout.MemberID, transition.Transition, memberWorkout.Status, toStatus, transition.Note, transition.Reason, changedBy); err != nil {
+		return err
+	}
+	if onTransition != nil {
+		if err := onTransition(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *CustomMemberWorkoutRepository) ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error) {
+	query := `SELECT ` + eventColumns + `
+		FROM "` + gymID + `".custom_member_workout_event WHERE member_workout_id = $1 ORDER BY seq`
+	return r.queryEvents(query, memberWorkoutID)
+}
+
+// ListEventsAfter gives the gym's transitions with a sequence above after, in sequence order, for consumers catching up
+func (r *CustomMemberWorkoutRepository) ListEventsAfter(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error) {
+	query := `SELECT ` + eventColumns + `
+		FROM "` + gymID + `".custom_member_workout_event WHERE seq > $1 ORDER BY seq LIMIT $2`
+	return r.queryEvents(query, after, limit)
+}
+
+func (r *CustomMemberWorkoutRepository) queryEvents(query string, args ...any) ([]*dto.MemberWorkoutEventDTO, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := []*dto.MemberWorkoutEventDTO{}
+	for rows.Next() {
+		var event dto.MemberWorkoutEventDTO
+		err := rows.Scan(
+			&event.ID,
+			&event.Sequence,
+			&event.MemberWorkoutID,
+			&event.MemberID,
+			&event.Transition,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.Note,
+			&event.Reason,
+			&event.ChangedBy,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// eventLock serializes the gym's event inserts until commit, so sequences become visible in order and a
+// consumer paging by sequence never skips an event committed late. Take it right before inserting events.
+const eventLock = `SELECT pg_advisory_xact_lock(hashtext($1 || '.custom_member_workout_event'))`
+
+const eventColumns = `id, seq, member_workout_id, member_id, transition, from_status, to_status, note, reason, changed_by, created_at`
+
+// UpsertBlockResult records the result of a timed block, replacing the one already logged for it
+func (r *CustomMemberWorkoutRepository) UpsertBlockResult(gymID, blockMode string, result *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+	query := `INSERT INTO "` + gymID + `".custom_member_workout_block_result (
+		member_workout_id, block_name, block_mode, rounds_completed, extra_reps, finish_time_seconds, notes
+	) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (member_workout_id, block_name) DO UPDATE SET
+		block_mode = EXCLUDED.block_mode,
+		rounds_completed = EXCLUDED.rounds_completed,
+		extra_reps = EXCLUDED.extra_reps,
+		finish_time_seconds = EXCLUDED.finish_time_seconds,
+		notes = EXCLUDED.notes,
+		updated_at = NOW()
+	RETURNING id, member_workout_id, block_name, block_mode, rounds_completed, extra_reps, finish_time_seconds, notes, created_at, updated_at`
+	row := r.DB.QueryRow(
+		query,
+		result.MemberWorkoutID,
+		result.BlockName,
+		blockMode,
+		result.RoundsCompleted,
+		result.ExtraReps,
+		result.FinishTimeSeconds,
+		result.Notes,
+	)
+	return scanBlockResult(row)
+}
+
+func (r *CustomMemberWorkoutRepository) ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error) {
+	query := `SELECT id, member_workout_id, block_name, block_mode, rounds_completed, extra_reps, finish_time_seconds, notes, created_at, updated_at
+		FROM "` + gymID + `".custom_member_workout_block_result WHERE member_workout_id = $1 ORDER BY block_name`
+	rows, err := r.DB.Query(query, memberWorkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []*dto.BlockResultDTO{}
+	for rows.Next() {
+		res, err := scanBlockResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, res)
+	}
+	return result, rows.Err()
+}
+
+// IsInstanceExercise reports whether the workout exercise belongs to the workout instance
+func (r *CustomMemberWorkoutRepository) IsInstanceExercise(gymID, instanceID, workoutExerciseID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM "` + gymID + `".custom_workout_exercise WHERE id = $1 AND workout_instance_id = $2)`
+	var exists bool
+	if err := r.DB.QueryRow(query, workoutExerciseID, instanceID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// CreateSetLog logs a set, numbering it after the sets already logged for the exercise when no number is given.
+// It returns sql.ErrNoRows when the set number is already logged.
+// CreateSetLog logs the set, returning sql.ErrNoRows when its set number is taken. onLogged, when set, runs in
+// the same transaction with the logged set.
+func (r *CustomMemberWorkoutRepository) CreateSetLog(gymID string, set *dto.LogSetDTO, onLogged func(tx *sql.Tx, logged *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	table := `"` + gymID + `".custom_member_workout_set_log`
+	query := `INSERT INTO ` + table + ` (
+		member_workout_id, workout_exercise_id, set_number, reps, weight_kg, duration_seconds, distance_meters, rpe, rir, completed, notes
+	) VALUES (
+		$1, $2,
+		COALESCE($3, (SELECT COALESCE(MAX(set_number), 0) + 1 FROM ` + table + ` WHERE member_workout_id = $1 AND workout_exercise_id = $2)),
+		$4, $5, $6, $7, $8, $9, COALESCE($10, TRUE), $11
+	)
+	ON CONFLICT (member_workout_id, workout_exercise_id, set_number) DO NOTHING
+	RETURNING ` + setLogColumns
+	row := tx.QueryRow(
+		query,
+		set.MemberWorkoutID,
+		set.WorkoutExerciseID,
+		set.SetNumber,
+		set.Reps,
+		set.WeightKg,
+		set.DurationSeconds,
+		set.DistanceMeters,
+		set.RPE,
+		set.RIR,
+		set.Completed,
+		set.Notes,
+	)
+	return commitSetLog(tx, row, onLogged)
+}
+
+func (r *CustomMemberWorkoutRepository) ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error) {
+	query := `SELECT ` + setLogColumns + `
+		FROM "` + gymID + `".custom_member_workout_set_log WHERE member_workout_id = $1 ORDER BY workout_exercise_id, set_number`
+	rows, err := r.DB.Query(query, memberWorkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []*dto.SetLogDTO{}
+	for rows.Next() {
+		set, err := scanSetLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, set)
+	}
+	return result, rows.Err()
+}
+
+// UpdateSetLog corrects the given fields of a logged set, returning sql.ErrNoRows when the workout has no such set.
+// onUpdated, when set, runs in the same transaction with the corrected set.
+func (r *CustomMemberWorkoutRepository) UpdateSetLog(gymID string, set *dto.UpdateSetLogDTO, onUpdated func(tx *sql.Tx, updated *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE "` + gymID + `".custom_member_workout_set_log SET
+		reps = COALESCE($1, reps),
+		weight_kg = COALESCE($2, weight_kg),
+		duration_seconds = COALESCE($3, duration_seconds),
+		distance_meters = COALESCE($4, distance_meters),
+		rpe = COALESCE($5, rpe),
+		rir = COALESCE($6, rir),
+		completed = COALESCE($7, completed),
+		notes = COALESCE($8, notes),
+		updated_at = NOW()
+	WHERE id = $9 AND member_workout_id = $10
+	RETURNING ` + setLogColumns
+	row := tx.QueryRow(
+		query,
+		set.Reps,
+		set.WeightKg,
+		set.DurationSeconds,
+		set.DistanceMeters,
+		set.RPE,
+		set.RIR,
+		set.Completed,
+		set.Notes,
+		set.ID,
+		set.MemberWorkoutID,
+	)
+	return commitSetLog(tx, row, onUpdated)
+}
+
+// commitSetLog reads the written set, runs the hook on it and commits
+func commitSetLog(tx *sql.Tx, row *sql.Row, hook func(tx *sql.Tx, set *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	set, err := scanSetLog(row)
+	if err != nil {
+		return nil, err
+	}
+	if hook != nil {
+		if err := hook(tx, set); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// DeleteSetLog removes a logged set; onDeleted, when set, runs in the same transaction
+func (r *CustomMemberWorkoutRepository) DeleteSetLog(gymID, memberWorkoutID, id string, onDeleted func(tx *sql.Tx) error) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM "` + gymID + `".custom_member_workout_set_log WHERE id = $1 AND member_workout_id = $2`
+	res, err := tx.Exec(query, id, memberWorkoutID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	if onDeleted != nil {
+		if err := onDeleted(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const setLogColumns = `id, member_workout_id, workout_exercise_id, set_number, reps, weight_kg, duration_seconds, distance_meters, rpe, rir, completed, notes, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBlockResult(row rowScanner) (*dto.BlockResultDTO, error) {
+	var res dto.BlockResultDTO
+	err := row.Scan(
+		&res.ID,
+		&res.MemberWorkoutID,
+		&res.BlockName,
+		&res.BlockMode,
+		&res.RoundsCompleted,
+		&res.ExtraReps,
+		&res.FinishTimeSeconds,
+		&res.Notes,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func scanSetLog(row rowScanner) (*dto.SetLogDTO, error) {
+	var set dto.SetLogDTO
+	err := row.Scan(
+		&set.ID,
+		&set.MemberWorkoutID,
+		&set.WorkoutExerciseID,
+		&set.SetNumber,
+		&set.Reps,
+		&set.WeightKg,
+		&set.DurationSeconds,
+		&set.DistanceMeters,
+		&set.RPE,
+		&set.RIR,
+		&set.Completed,
+		&set.Notes,
+		&set.CreatedAt,
+		&set.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -42,7 +43,7 @@ func TestGetByID_NotFound(t *testing.T) {
 	defer cleanup()
 	repo := NewCustomMemberWorkoutRepository(db)
 
-	mock.ExpectQuery(`SELECT id, member_id, workout_instance_id, scheduled_date, started_at, completed_at, status, notes, rating, created_at, updated_at`).
+	mock.ExpectQuery(`SELECT id, member_id, workout_instance_id, scheduled_date, started_at, paused_at, paused_seconds, completed_at, status, notes, rating, created_at, updated_at`).
 		WithArgs("notfound-id").
 		WillReturnError(sql.ErrNoRows)
 
@@ -57,7 +58,7 @@ func TestUpdateCustomMemberWorkout_NotFound(t *testing.T) {
 	repo := NewCustomMemberWorkoutRepository(db)
 
 	mock.ExpectExec(`UPDATE ".*".custom_member_workout SET`).
-		WithArgs(nil, nil, nil, nil, nil, "notfound-id").
+		WithArgs(nil, nil, "notfound-id").
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
 	input := &dto.UpdateCustomMemberWorkoutDTO{ID: "notfound-id"}
@@ -70,10 +71,186 @@ func TestDeleteCustomMemberWorkout_NotFound(t *testing.T) {
 	defer cleanup()
 	repo := NewCustomMemberWorkoutRepository(db)
 
-	mock.ExpectExec(`DELETE FROM ".*".custom_member_workout`).
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM ".*".custom_member_workout WHERE id = \$1 RETURNING member_id`).
 		WithArgs("notfound-id").
+		WillReturnRows(sqlmock.NewRows([]string{"member_id"}))
+	mock.ExpectRollback()
+
+	err := repo.Delete("gym-id", "notfound-id", func(*sql.Tx, string) error {
+		t.Fatal("hook ran for a missing workout")
+		return nil
+	})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCustomMemberWorkout_HookRunsWithTheMember(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM ".*".custom_member_workout WHERE id = \$1 RETURNING member_id`).
+		WithArgs("mw-1").
+		WillReturnRows(sqlmock.NewRows([]string{"member_id"}).AddRow("member-1"))
+	mock.ExpectRollback()
+
+	err := repo.Delete("gym-id", "mw-1", func(tx *sql.Tx, memberID string) error {
+		assert.Equal(t, "member-1", memberID)
+		return errors.New("engagement failed")
+	})
+	assert.EqualError(t, err, "engagement failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBlockResult(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	rounds, reps := 7, 12
+	mock.ExpectQuery(`INSERT INTO ".*".custom_member_workout_block_result .* ON CONFLICT \(member_workout_id, block_name\) DO UPDATE`).
+		WithArgs("mw-1", "Finisher", "amrap", 7, 12, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "member_workout_id", "block_name", "block_mode", "rounds_completed", "extra_reps", "finish_time_seconds", "notes", "created_at", "updated_at"}).
+			AddRow("res-1", "mw-1", "Finisher", "amrap", 7, 12, nil, nil, "2025-09-08", "2025-09-08"))
+
+	res, err := repo.UpsertBlockResult("gym-id", "amrap", &dto.RecordBlockResultDTO{
+		MemberWorkoutID: "mw-1",
+		BlockName:       "Finisher",
+		RoundsCompleted: &rounds,
+		ExtraReps:       &reps,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "res-1", res.ID)
+	assert.Equal(t, 7, *res.RoundsCompleted)
+	assert.Nil(t, res.FinishTimeSeconds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var setLogRowColumns = []string{"id", "member_workout_id", "workout_exercise_id", "set_number", "reps", "weight_kg", "duration_seconds", "distance_meters", "rpe", "rir", "completed", "notes", "created_at", "updated_at"}
+
+func TestCreateSetLog_NextSetNumber(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	reps, weight := 8, 82.5
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO ".*".custom_member_workout_set_log .* COALESCE\(\$3, \(SELECT COALESCE\(MAX\(set_number\), 0\) \+ 1 .* ON CONFLICT .* DO NOTHING`).
+		WithArgs("mw-1", "we-1", nil, 8, 82.5, nil, nil, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows(setLogRowColumns).
+			AddRow("set-3", "mw-1", "we-1", 3, 8, 82.5, nil, nil, nil, nil, true, nil, "2025-09-08", "2025-09-08"))
+	mock.ExpectExec(`DELETE FROM "gym-id".personal_record`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var hooked string
+	set, err := repo.CreateSetLog("gym-id", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: &reps, WeightKg: &weight},
+		func(tx *sql.Tx, logged *dto.SetLogDTO) error {
+			hooked = logged.ID
+			_, err := tx.Exec(`DELETE FROM "gym-id".personal_record`)
+			return err
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "set-3", hooked)
+	assert.Equal(t, 3, set.SetNumber)
+	assert.True(t, set.Completed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSetLog_NotFound(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	completed := false
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ".*".custom_member_workout_set_log SET`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, "missing", "mw-1").
+		WillReturnRows(sqlmock.NewRows(setLogRowColumns))
+	mock.ExpectRollback()
+
+	_, err := repo.UpdateSetLog("gym-id", &dto.UpdateSetLogDTO{ID: "missing", MemberWorkoutID: "mw-1", Completed: &completed}, func(*sql.Tx, *dto.SetLogDTO) error {
+		t.Fatal("the hook only runs for an updated set")
+		return nil
+	})
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransition_RecordsEvent(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	workout := &dto.ResponseCustomMemberWorkoutDTO{ID: "mw-1", MemberID: "member-1", Status: "in_progress"}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE ".*".custom_member_workout SET status = \$2, .* paused_seconds = paused_seconds \+ .* WHERE id = \$1 AND status = \$3`).
+		WithArgs("mw-1", "paused", "in_progress", "pause").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("gym-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO ".*".custom_member_workout_event`).
+		WithArgs("mw-1", "member-1", "pause", "in_progress", "paused", nil, nil, "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Transition("gym-id", workout, "paused", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "pause", ChangedBy: "coach-1"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransition_StatusChangedMeanwhile(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	workout := &dto.ResponseCustomMemberWorkoutDTO{ID: "mw-1", MemberID: "member-1", Status: "scheduled"}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE ".*".custom_member_workout SET`).
+		WithArgs("mw-1", "in_progress", "scheduled", "start").
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
 
-	err := repo.Delete("gym-id", "notfound-id")
+	err := repo.Transition("gym-id", workout, "in_progress", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "start"}, nil)
 	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEventsAfter_PagesBySequence(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	mock.ExpectQuery(`SELECT id, seq, member_workout_id, .* FROM ".*".custom_member_workout_event WHERE seq > \$1 ORDER BY seq LIMIT \$2`).
+		WithArgs(int64(41), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq", "member_workout_id", "member_id", "transition", "from_status", "to_status", "note", "reason", "changed_by", "created_at"}).
+			AddRow("ev-1", 42, "mw-1", "member-1", "start", "scheduled", "in_progress", nil, nil, "member-1", "2025-09-08T10:00:00Z").
+			AddRow("ev-2", 43, "mw-1", "member-1", "complete", "in_progress", "completed", nil, nil, "member-1", "2025-09-08T09:59:59Z"))
+
+	events, err := repo.ListEventsAfter("gym-id", 41, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(42), events[0].Sequence)
+		assert.Equal(t, int64(43), events[1].Sequence)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSetLog_HookFailureRollsBack(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	repo := NewCustomMemberWorkoutRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM ".*".custom_member_workout_set_log WHERE id = \$1 AND member_workout_id = \$2`).
+		WithArgs("set-1", "mw-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	err := repo.DeleteSetLog("gym-id", "mw-1", "set-1", func(*sql.Tx) error { return errors.New("records failed") })
+	assert.EqualError(t, err, "records failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -8,9 +8,23 @@ import (
 func NewCustomMemberWorkoutRouter(h *handler.CustomMemberWorkoutHandler) chi.Router {
 	r := chi.NewRouter()
 	r.Post("/custom-member-workout", h.Create)
+	r.Get("/custom-member-workout/events", h.ListGymEvents)
 	r.Get("/custom-member-workout/{id}", h.GetByID)
 	r.Get("/custom-member-workout/member/{memberID}", h.ListByMemberID)
 	r.Put("/custom-member-workout/{id}", h.Update)
 	r.Delete("/custom-member-workout/{id}", h.Delete)
+	r.Post("/custom-member-workout/{id}/start", h.Start)
+	r.Post("/custom-member-workout/{id}/pause", h.Pause)
+	r.Post("/custom-member-workout/{id}/resume", h.Resume)
+	r.Post("/custom-member-workout/{id}/complete", h.Complete)
+	r.Post("/custom-member-workout/{id}/skip", h.Skip)
+	r.Post("/custom-member-workout/{id}/cancel", h.Cancel)
+	r.Get("/custom-member-workout/{id}/events", h.ListEvents)
+	r.Put("/custom-member-workout/{id}/block-results", h.RecordBlockResult)
+	r.Get("/custom-member-workout/{id}/block-results", h.ListBlockResults)
+	r.Post("/custom-member-workout/{id}/sets", h.LogSet)
+	r.Get("/custom-member-workout/{id}/sets", h.ListSetLogs)
+	r.Put("/custom-member-workout/{id}/sets/{setID}", h.UpdateSetLog)
+	r.Delete("/custom-member-workout/{id}/sets/{setID}", h.DeleteSetLog)
 	return r
 }
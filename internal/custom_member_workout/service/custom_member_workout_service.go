@@ -2,48 +2,88 @@ package service
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	contraindication_interfaces "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/enum"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/interfaces"
+	instance_interfaces "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	engagement_interfaces "github.com/alejandro-albiol/athenai/internal/member_engagement/interfaces"
+	record_interfaces "github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+	blockEnum "github.com/alejandro-albiol/athenai/internal/template_block/enum"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 )
 
 type CustomMemberWorkoutService struct {
 	repository interfaces.CustomMemberWorkoutRepository
+	checker    contraindication_interfaces.ContraindicationChecker
+	timings    instance_interfaces.BlockTimingReader
+	records    record_interfaces.RecordTracker
+	engagement engagement_interfaces.EngagementTracker
 }
 
-func NewCustomMemberWorkoutService(repo interfaces.CustomMemberWorkoutRepository) *CustomMemberWorkoutService {
-	return &CustomMemberWorkoutService{repository: repo}
+func NewCustomMemberWorkoutService(repo interfaces.CustomMemberWorkoutRepository, checker contraindication_interfaces.ContraindicationChecker, timings instance_interfaces.BlockTimingReader, records record_interfaces.RecordTracker, engagement engagement_interfaces.EngagementTracker) *CustomMemberWorkoutService {
+	return &CustomMemberWorkoutService{repository: repo, checker: checker, timings: timings, records: records, engagement: engagement}
 }
 
-func (s *CustomMemberWorkoutService) CreateCustomMemberWorkout(gymID string, memberWorkout *dto.CreateCustomMemberWorkoutDTO) (*string, error) {
+func (s *CustomMemberWorkoutService) CreateCustomMemberWorkout(gymID string, memberWorkout *dto.CreateCustomMemberWorkoutDTO) (*string, []*contraindication_dto.ContraindicationWarning, error) {
 	// Validate required fields
 	if memberWorkout.MemberID == "" || memberWorkout.WorkoutInstanceID == "" || memberWorkout.ScheduledDate == "" {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Missing required fields", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "Missing required fields", nil)
 	}
 	// Validate rating if present
 	if memberWorkout.Rating != nil {
 		if *memberWorkout.Rating < 1 || *memberWorkout.Rating > 5 {
-			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Rating must be between 1 and 5", nil)
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "Rating must be between 1 and 5", nil)
+		}
+	}
+	// Check the instance's exercises against the member's special situation
+	var warnings []*contraindication_dto.ContraindicationWarning
+	if s.checker != nil {
+		var err error
+		warnings, err = s.checker.CheckMemberWorkout(gymID, memberWorkout.MemberID, memberWorkout.WorkoutInstanceID)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
 	// Status is always scheduled on create
-	return s.repository.Create(gymID, memberWorkout)
+	id, err := s.repository.Create(gymID, memberWorkout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, warnings, nil
 }
 
+// GetCustomMemberWorkoutByID gets a member workout with the personal records it beat
 func (s *CustomMemberWorkoutService) GetCustomMemberWorkoutByID(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+	res, err := s.getMemberWorkout(gymID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.records != nil {
+		if res.NewRecords, err = s.records.GetWorkoutRecords(gymID, id); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (s *CustomMemberWorkoutService) getMemberWorkout(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
 	if id == "" {
 		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
 	}
 	res, err := s.repository.GetByID(gymID, id)
-    if err != nil {
-        if err == sql.ErrNoRows {
-            return nil, apierror.New(errorcode_enum.CodeNotFound, "Custom member workout not found", err)
-        }
-        return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get custom member workout", err)
-    }
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Custom member workout not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get custom member workout", err)
+	}
 	return res, nil
 }
 
@@ -58,12 +98,6 @@ func (s *CustomMemberWorkoutService) UpdateCustomMemberWorkout(gymID string, mem
 	if memberWorkout.ID == "" {
 		return apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
 	}
-	if memberWorkout.Status != nil {
-		status := enum.CustomMemberWorkoutStatus(*memberWorkout.Status)
-		if !status.IsValid() {
-			return apierror.New(errorcode_enum.CodeBadRequest, "Invalid status value", nil)
-		}
-	}
 	if memberWorkout.Rating != nil {
 		if *memberWorkout.Rating < 1 || *memberWorkout.Rating > 5 {
 			return apierror.New(errorcode_enum.CodeBadRequest, "Rating must be between 1 and 5", nil)
@@ -76,5 +110,341 @@ func (s *CustomMemberWorkoutService) DeleteCustomMemberWorkout(gymID, id string)
 	if id == "" {
 		return apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
 	}
-	return s.repository.Delete(gymID, id)
+	// Deleting a completed or skipped workout takes it out of the member's streaks and counts
+	var onDeleted func(tx *sql.Tx, memberID string) error
+	if s.engagement != nil {
+		onDeleted = func(tx *sql.Tx, memberID string) error {
+			return s.engagement.ForgetWorkout(tx, gymID, memberID)
+		}
+	}
+	return s.repository.Delete(gymID, id, onDeleted)
+}
+
+// TransitionCustomMemberWorkout applies a start, pause, resume, complete, skip or cancel to a member workout.
+// Transitions the current status does not allow are an INVALID_TRANSITION conflict.
+func (s *CustomMemberWorkoutService) TransitionCustomMemberWorkout(gymID string, transition *dto.TransitionMemberWorkoutDTO) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+	next := enum.WorkoutTransition(transition.Transition)
+	if !next.IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Transition must be one of start, pause, resume, complete, skip or cancel", nil)
+	}
+	if transition.Reason != nil {
+		if next != enum.TransitionSkip {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "A reason can only be given when skipping a workout", nil)
+		}
+		if !enum.SkipReason(*transition.Reason).IsValid() {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Reason must be one of illness, injury, fatigue, schedule_conflict, travel, motivation or other", nil)
+		}
+	}
+	memberWorkout, err := s.getMemberWorkout(gymID, transition.MemberWorkoutID)
+	if err != nil {
+		return nil, err
+	}
+	current := enum.CustomMemberWorkoutStatus(memberWorkout.Status)
+	if !next.AllowedFrom(current) {
+		msg := fmt.Sprintf("Cannot %s a %s workout", next, current)
+		if allowed := enum.TransitionsFrom(current); len(allowed) > 0 {
+			names := make([]string, len(allowed))
+			for i, t := range allowed {
+				names[i] = string(t)
+			}
+			msg += "; allowed transitions: " + strings.Join(names, ", ")
+		}
+		return nil, apierror.New(errorcode_enum.CodeInvalidTransition, msg, nil)
+	}
+
+	// Completing a workout counts its volume, and the sets of skipped or cancelled ones no longer count
+	updatesRecords := s.records != nil && next != enum.TransitionStart && next != enum.TransitionPause && next != enum.TransitionResume
+	onTransition := func(tx *sql.Tx) error {
+		if updatesRecords {
+			if _, err := s.records.UpdateWorkoutRecords(tx, gymID, memberWorkout.ID); err != nil {
+				return err
+			}
+		}
+		if s.engagement != nil {
+			return s.engagement.TrackTransition(tx, gymID, memberWorkout.MemberID, string(next), transition.Reason)
+		}
+		return nil
+	}
+	if err := s.repository.Transition(gymID, memberWorkout, string(next.Target()), transition, onTransition); err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			return nil, apiErr
+		}
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeInvalidTransition, "Workout was updated by someone else, reload it and try again", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update workout status", err)
+	}
+	return s.GetCustomMemberWorkoutByID(gymID, memberWorkout.ID)
+}
+
+func (s *CustomMemberWorkoutService) ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error) {
+	if _, err := s.getMemberWorkout(gymID, memberWorkoutID); err != nil {
+		return nil, err
+	}
+	events, err := s.repository.ListEvents(gymID, memberWorkoutID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list workout events", err)
+	}
+	return events, nil
+}
+
+// ListGymEvents pages through the gym's workout transitions: consumers pass the sequence of the last event they saw
+func (s *CustomMemberWorkoutService) ListGymEvents(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error) {
+	if after < 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "after must not be negative", nil)
+	}
+	if limit == 0 {
+		limit = defaultEventLimit
+	}
+	if limit < 1 || limit > maxEventLimit {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxEventLimit), nil)
+	}
+	events, err := s.repository.ListEventsAfter(gymID, after, limit)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list workout events", err)
+	}
+	return events, nil
+}
+
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 500
+)
+
+// RecordBlockResult logs the result of a timed block of the session's workout, replacing any earlier result for it
+func (s *CustomMemberWorkoutService) RecordBlockResult(gymID string, result *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+	if result.MemberWorkoutID == "" || result.BlockName == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ID and block_name are required", nil)
+	}
+	memberWorkout, err := s.getMemberWorkout(gymID, result.MemberWorkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	timings := map[string]templateBlockDTO.BlockTiming{}
+	if s.timings != nil {
+		timings, err = s.timings.GetBlockTimings(gymID, memberWorkout.WorkoutInstanceID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, apierror.New(errorcode_enum.CodeNotFound, "Workout instance not found", err)
+			}
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get block timings", err)
+		}
+	}
+	timing, ok := timings[result.BlockName]
+	if !ok {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Block %q is not a timed block of this workout", result.BlockName), nil)
+	}
+	if err := validateBlockResult(timing, result); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repository.UpsertBlockResult(gymID, string(timing.Mode()), result)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to record block result", err)
+	}
+	return saved, nil
+}
+
+func (s *CustomMemberWorkoutService) ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error) {
+	if _, err := s.getMemberWorkout(gymID, memberWorkoutID); err != nil {
+		return nil, err
+	}
+	results, err := s.repository.ListBlockResults(gymID, memberWorkoutID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list block results", err)
+	}
+	return results, nil
+}
+
+// validateBlockResult checks the result fits the block mode: AMRAP records rounds and extra reps,
+// For-Time a finish time within the cap or the rounds reached when it ran out, and EMOM, Tabata and
+// intervals the rounds completed out of the block's rounds.
+func validateBlockResult(timing templateBlockDTO.BlockTiming, result *dto.RecordBlockResultDTO) error {
+	badRequest := func(msg string) error { return apierror.New(errorcode_enum.CodeBadRequest, msg, nil) }
+	switch {
+	case result.RoundsCompleted != nil && *result.RoundsCompleted < 0:
+		return badRequest("rounds_completed cannot be negative")
+	case result.ExtraReps != nil && *result.ExtraReps < 0:
+		return badRequest("extra_reps cannot be negative")
+	case result.FinishTimeSeconds != nil && *result.FinishTimeSeconds <= 0:
+		return badRequest("finish_time_seconds must be greater than 0")
+	}
+
+	switch timing.Mode() {
+	case blockEnum.AMRAP:
+		if result.RoundsCompleted == nil {
+			return badRequest("amrap results need rounds_completed")
+		}
+		if result.FinishTimeSeconds != nil {
+			return badRequest("amrap results take no finish_time_seconds")
+		}
+	case blockEnum.ForTime:
+		if result.FinishTimeSeconds == nil && result.RoundsCompleted == nil {
+			return badRequest("for_time results need finish_time_seconds, or rounds_completed when the time cap ran out")
+		}
+		if result.FinishTimeSeconds != nil && timing.TimeCapSeconds != nil && *result.FinishTimeSeconds > *timing.TimeCapSeconds {
+			return badRequest("finish_time_seconds cannot exceed the block time cap")
+		}
+	default:
+		if result.RoundsCompleted == nil {
+			return badRequest(fmt.Sprintf("%s results need rounds_completed", timing.Mode()))
+		}
+		if result.FinishTimeSeconds != nil || result.ExtraReps != nil {
+			return badRequest(fmt.Sprintf("%s results take only rounds_completed", timing.Mode()))
+		}
+	}
+	if result.RoundsCompleted != nil && timing.Rounds != nil && timing.Mode() != blockEnum.AMRAP && *result.RoundsCompleted > *timing.Rounds {
+		return badRequest("rounds_completed cannot exceed the block rounds")
+	}
+	return nil
+}
+
+// LogSet records a set of an exercise of the session's workout instance while the session is not skipped or cancelled
+func (s *CustomMemberWorkoutService) LogSet(gymID string, set *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+	if set.MemberWorkoutID == "" || set.WorkoutExerciseID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ID and workout_exercise_id are required", nil)
+	}
+	if set.SetNumber != nil && *set.SetNumber < 1 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "set_number must be greater than 0", nil)
+	}
+	if err := validateSetValues(set.Reps, set.WeightKg, set.DurationSeconds, set.DistanceMeters, set.RPE, set.RIR); err != nil {
+		return nil, err
+	}
+	memberWorkout, err := s.getLoggableWorkout(gymID, set.MemberWorkoutID)
+	if err != nil {
+		return nil, err
+	}
+	inInstance, err := s.repository.IsInstanceExercise(gymID, memberWorkout.WorkoutInstanceID, set.WorkoutExerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check workout exercise", err)
+	}
+	if !inInstance {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Exercise is not part of this workout", nil)
+	}
+
+	logged, err := s.repository.CreateSetLog(gymID, set, s.updateRecords(gymID))
+	if err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			return nil, apiErr
+		}
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "Set is already logged for this exercise; correct it instead", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to log set", err)
+	}
+	return logged, nil
+}
+
+func (s *CustomMemberWorkoutService) ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error) {
+	if _, err := s.getMemberWorkout(gymID, memberWorkoutID); err != nil {
+		return nil, err
+	}
+	sets, err := s.repository.ListSetLogs(gymID, memberWorkoutID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list logged sets", err)
+	}
+	return sets, nil
+}
+
+// UpdateSetLog corrects a logged set, also after the session is completed
+func (s *CustomMemberWorkoutService) UpdateSetLog(gymID string, set *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error) {
+	if set.MemberWorkoutID == "" || set.ID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ID and set ID are required", nil)
+	}
+	if err := validateSetValues(set.Reps, set.WeightKg, set.DurationSeconds, set.DistanceMeters, set.RPE, set.RIR); err != nil {
+		return nil, err
+	}
+	if _, err := s.getLoggableWorkout(gymID, set.MemberWorkoutID); err != nil {
+		return nil, err
+	}
+	updated, err := s.repository.UpdateSetLog(gymID, set, s.updateRecords(gymID))
+	if err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			return nil, apiErr
+		}
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Logged set not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update logged set", err)
+	}
+	return updated, nil
+}
+
+func (s *CustomMemberWorkoutService) DeleteSetLog(gymID, memberWorkoutID, id string) error {
+	if memberWorkoutID == "" || id == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, "ID and set ID are required", nil)
+	}
+	if _, err := s.getLoggableWorkout(gymID, memberWorkoutID); err != nil {
+		return err
+	}
+	var onDeleted func(tx *sql.Tx) error
+	if s.records != nil {
+		onDeleted = func(tx *sql.Tx) error {
+			_, err := s.records.UpdateWorkoutRecords(tx, gymID, memberWorkoutID)
+			return err
+		}
+	}
+	if err := s.repository.DeleteSetLog(gymID, memberWorkoutID, id, onDeleted); err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			return apiErr
+		}
+		if err == sql.ErrNoRows {
+			return apierror.New(errorcode_enum.CodeNotFound, "Logged set not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete logged set", err)
+	}
+	return nil
+}
+
+// updateRecords recomputes the member's records in the transaction that logged or corrected a set and keeps the
+// ones the set beat on it
+func (s *CustomMemberWorkoutService) updateRecords(gymID string) func(tx *sql.Tx, set *dto.SetLogDTO) error {
+	if s.records == nil {
+		return nil
+	}
+	return func(tx *sql.Tx, set *dto.SetLogDTO) error {
+		beaten, err := s.records.UpdateWorkoutRecords(tx, gymID, set.MemberWorkoutID)
+		if err != nil {
+			return err
+		}
+		for _, record := range beaten {
+			if record.SetLogID != nil && *record.SetLogID == set.ID {
+				set.NewRecords = append(set.NewRecords, record)
+			}
+		}
+		return nil
+	}
+}
+
+// getLoggableWorkout gets a member workout whose sets can still be logged: skipped and cancelled sessions have none
+func (s *CustomMemberWorkoutService) getLoggableWorkout(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+	memberWorkout, err := s.getMemberWorkout(gymID, id)
+	if err != nil {
+		return nil, err
+	}
+	switch enum.CustomMemberWorkoutStatus(memberWorkout.Status) {
+	case enum.StatusSkipped, enum.StatusCancelled:
+		return nil, apierror.New(errorcode_enum.CodeConflict, "Sets cannot be logged for a "+memberWorkout.Status+" workout", nil)
+	}
+	return memberWorkout, nil
+}
+
+func validateSetValues(reps *int, weightKg *float64, durationSeconds *int, distanceMeters *float64, rpe *float64, rir *int) error {
+	badRequest := func(msg string) error { return apierror.New(errorcode_enum.CodeBadRequest, msg, nil) }
+	switch {
+	case reps != nil && *reps < 0:
+		return badRequest("reps cannot be negative")
+	case weightKg != nil && *weightKg < 0:
+		return badRequest("weight_kg cannot be negative")
+	case durationSeconds != nil && *durationSeconds < 0:
+		return badRequest("duration_seconds cannot be negative")
+	case distanceMeters != nil && *distanceMeters < 0:
+		return badRequest("distance_meters cannot be negative")
+	case rpe != nil && (*rpe < 1 || *rpe > 10):
+		return badRequest("rpe must be between 1 and 10")
+	case rir != nil && *rir < 0:
+		return badRequest("rir cannot be negative")
+	}
+	return nil
 }
@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"testing"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_member_workout/dto"
+	record_dto "github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +19,16 @@ type mockRepo struct {
 	ListByMemberIDFn func(string, string) ([]*dto.ResponseCustomMemberWorkoutDTO, error)
 	UpdateFn         func(string, *dto.UpdateCustomMemberWorkoutDTO) error
 	DeleteFn         func(string, string) error
+	UpsertResultFn   func(string, string, *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error)
+	ListResultsFn    func(string, string) ([]*dto.BlockResultDTO, error)
+	InInstanceFn     func(string, string, string) (bool, error)
+	CreateSetFn      func(string, *dto.LogSetDTO) (*dto.SetLogDTO, error)
+	ListSetsFn       func(string, string) ([]*dto.SetLogDTO, error)
+	UpdateSetFn      func(string, *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error)
+	DeleteSetFn      func(string, string, string) error
+	TransitionFn     func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error
+	ListEventsFn     func(string, string) ([]*dto.MemberWorkoutEventDTO, error)
+	EventsAfterFn    func(string, int64, int) ([]*dto.MemberWorkoutEventDTO, error)
 }
 
 func (m *mockRepo) Create(gymID string, d *dto.CreateCustomMemberWorkoutDTO) (*string, error) {
@@ -30,12 +43,88 @@ func (m *mockRepo) ListByMemberID(gymID, memberID string) ([]*dto.ResponseCustom
 func (m *mockRepo) Update(gymID string, d *dto.UpdateCustomMemberWorkoutDTO) error {
 	return m.UpdateFn(gymID, d)
 }
-func (m *mockRepo) Delete(gymID, id string) error {
-	return m.DeleteFn(gymID, id)
+func (m *mockRepo) Delete(gymID, id string, onDeleted func(*sql.Tx, string) error) error {
+	if err := m.DeleteFn(gymID, id); err != nil || onDeleted == nil {
+		return err
+	}
+	return onDeleted(nil, "member-1")
+}
+func (m *mockRepo) UpsertBlockResult(gymID, blockMode string, d *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+	return m.UpsertResultFn(gymID, blockMode, d)
+}
+func (m *mockRepo) ListBlockResults(gymID, memberWorkoutID string) ([]*dto.BlockResultDTO, error) {
+	return m.ListResultsFn(gymID, memberWorkoutID)
+}
+
+func (m *mockRepo) IsInstanceExercise(gymID, instanceID, workoutExerciseID string) (bool, error) {
+	return m.InInstanceFn(gymID, instanceID, workoutExerciseID)
+}
+
+// The set log and transition mocks run their hook as the repository would before committing
+func (m *mockRepo) CreateSetLog(gymID string, d *dto.LogSetDTO, onLogged func(*sql.Tx, *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	set, err := m.CreateSetFn(gymID, d)
+	return runSetHook(set, err, onLogged)
+}
+func (m *mockRepo) ListSetLogs(gymID, memberWorkoutID string) ([]*dto.SetLogDTO, error) {
+	return m.ListSetsFn(gymID, memberWorkoutID)
+}
+func (m *mockRepo) UpdateSetLog(gymID string, d *dto.UpdateSetLogDTO, onUpdated func(*sql.Tx, *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	set, err := m.UpdateSetFn(gymID, d)
+	return runSetHook(set, err, onUpdated)
+}
+func (m *mockRepo) DeleteSetLog(gymID, memberWorkoutID, id string, onDeleted func(*sql.Tx) error) error {
+	return runHook(m.DeleteSetFn(gymID, memberWorkoutID, id), onDeleted)
+}
+
+func (m *mockRepo) Transition(gymID string, w *dto.ResponseCustomMemberWorkoutDTO, toStatus string, d *dto.TransitionMemberWorkoutDTO, onTransition func(*sql.Tx) error) error {
+	return runHook(m.TransitionFn(gymID, w, toStatus, d), onTransition)
+}
+
+func runHook(err error, hook func(*sql.Tx) error) error {
+	if err != nil || hook == nil {
+		return err
+	}
+	return hook(nil)
+}
+
+func runSetHook(set *dto.SetLogDTO, err error, hook func(*sql.Tx, *dto.SetLogDTO) error) (*dto.SetLogDTO, error) {
+	if err != nil || hook == nil {
+		return set, err
+	}
+	if err := hook(nil, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+func (m *mockRepo) ListEvents(gymID, memberWorkoutID string) ([]*dto.MemberWorkoutEventDTO, error) {
+	return m.ListEventsFn(gymID, memberWorkoutID)
+}
+func (m *mockRepo) ListEventsAfter(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error) {
+	return m.EventsAfterFn(gymID, after, limit)
+}
+
+type mockTimings map[string]templateBlockDTO.BlockTiming
+
+func (m mockTimings) GetBlockTimings(gymID, instanceID string) (map[string]templateBlockDTO.BlockTiming, error) {
+	return m, nil
+}
+
+type mockRecords struct {
+	updated []string
+	beaten  []*record_dto.PersonalRecordDTO
+	err     error
+}
+
+func (m *mockRecords) UpdateWorkoutRecords(tx *sql.Tx, gymID, memberWorkoutID string) ([]*record_dto.PersonalRecordDTO, error) {
+	m.updated = append(m.updated, memberWorkoutID)
+	return m.beaten, m.err
+}
+func (m *mockRecords) GetWorkoutRecords(gymID, memberWorkoutID string) ([]*record_dto.PersonalRecordDTO, error) {
+	return m.beaten, nil
 }
 
 func TestCreateCustomMemberWorkout_Validation(t *testing.T) {
-	svc := NewCustomMemberWorkoutService(&mockRepo{})
+	svc := NewCustomMemberWorkoutService(&mockRepo{}, nil, nil, nil, nil)
 	cases := []struct {
 		name    string
 		input   dto.CreateCustomMemberWorkoutDTO
@@ -46,7 +135,7 @@ func TestCreateCustomMemberWorkout_Validation(t *testing.T) {
 		{"bad rating", dto.CreateCustomMemberWorkoutDTO{MemberID: "m", WorkoutInstanceID: "w", ScheduledDate: "d", Rating: intPtr(10)}, errorcode_enum.CodeBadRequest},
 	}
 	for _, c := range cases {
-		_, err := svc.CreateCustomMemberWorkout("gym", &c.input)
+		_, _, err := svc.CreateCustomMemberWorkout("gym", &c.input)
 		assert.Error(t, err, c.name)
 		apiErr := err.(*apierror.APIError)
 		assert.Equal(t, c.wantErr, apiErr.Code, c.name)
@@ -60,18 +149,60 @@ func TestCreateCustomMemberWorkout_Success(t *testing.T) {
 			id := "okid"
 			return &id, nil
 		},
-	})
+	}, nil, nil, nil, nil)
 	input := &dto.CreateCustomMemberWorkoutDTO{MemberID: "m", WorkoutInstanceID: "w", ScheduledDate: "d"}
-	id, err := svc.CreateCustomMemberWorkout("gym", input)
+	id, _, err := svc.CreateCustomMemberWorkout("gym", input)
 	assert.NoError(t, err)
 	assert.Equal(t, "okid", *id)
 }
+
+type mockChecker struct {
+	warnings []*contraindication_dto.ContraindicationWarning
+	err      error
+}
+
+func (m *mockChecker) CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return m.warnings, m.err
+}
+func (m *mockChecker) CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return m.warnings, m.err
+}
+
+func TestCreateCustomMemberWorkout_ContraindicationWarnings(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		CreateFn: func(gymID string, d *dto.CreateCustomMemberWorkoutDTO) (*string, error) {
+			id := "okid"
+			return &id, nil
+		},
+	}, &mockChecker{warnings: []*contraindication_dto.ContraindicationWarning{
+		{MemberID: "m", ExerciseSource: "public", ExerciseID: "ex1", SpecialSituation: "injury_recovery", BodyRegion: "knee", Severity: "contraindicated"},
+	}}, nil, nil, nil)
+	input := &dto.CreateCustomMemberWorkoutDTO{MemberID: "m", WorkoutInstanceID: "w", ScheduledDate: "d"}
+	id, warnings, err := svc.CreateCustomMemberWorkout("gym", input)
+	assert.NoError(t, err)
+	assert.Equal(t, "okid", *id)
+	assert.Len(t, warnings, 1)
+}
+func TestCreateCustomMemberWorkout_ContraindicationBlocked(t *testing.T) {
+	created := false
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		CreateFn: func(gymID string, d *dto.CreateCustomMemberWorkoutDTO) (*string, error) {
+			created = true
+			return nil, nil
+		},
+	}, &mockChecker{err: apierror.New(errorcode_enum.CodeConflict, "blocked", nil)}, nil, nil, nil)
+	input := &dto.CreateCustomMemberWorkoutDTO{MemberID: "m", WorkoutInstanceID: "w", ScheduledDate: "d"}
+	_, _, err := svc.CreateCustomMemberWorkout("gym", input)
+	assert.Error(t, err)
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+	assert.False(t, created)
+}
 func TestGetCustomMemberWorkoutByID_Success(t *testing.T) {
 	svc := NewCustomMemberWorkoutService(&mockRepo{
 		GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
 			return &dto.ResponseCustomMemberWorkoutDTO{ID: id}, nil
 		},
-	})
+	}, nil, nil, nil, nil)
 	res, err := svc.GetCustomMemberWorkoutByID("gym", "id1")
 	assert.NoError(t, err)
 	assert.Equal(t, "id1", res.ID)
@@ -79,14 +210,14 @@ func TestGetCustomMemberWorkoutByID_Success(t *testing.T) {
 func TestUpdateCustomMemberWorkout_Success(t *testing.T) {
 	svc := NewCustomMemberWorkoutService(&mockRepo{
 		UpdateFn: func(gymID string, d *dto.UpdateCustomMemberWorkoutDTO) error { return nil },
-	})
+	}, nil, nil, nil, nil)
 	err := svc.UpdateCustomMemberWorkout("gym", &dto.UpdateCustomMemberWorkoutDTO{ID: "id"})
 	assert.NoError(t, err)
 }
 func TestDeleteCustomMemberWorkout_Success(t *testing.T) {
 	svc := NewCustomMemberWorkoutService(&mockRepo{
 		DeleteFn: func(gymID, id string) error { return nil },
-	})
+	}, nil, nil, nil, nil)
 	err := svc.DeleteCustomMemberWorkout("gym", "id")
 	assert.NoError(t, err)
 }
@@ -96,17 +227,16 @@ func TestGetCustomMemberWorkoutByID_NotFound(t *testing.T) {
 		GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
 			return nil, sql.ErrNoRows
 		},
-	})
+	}, nil, nil, nil, nil)
 	_, err := svc.GetCustomMemberWorkoutByID("gym", "notfound")
 	assert.Error(t, err)
 	apiErr := err.(*apierror.APIError)
 	assert.Equal(t, errorcode_enum.CodeNotFound, apiErr.Code)
 }
 
-func TestUpdateCustomMemberWorkout_InvalidStatus(t *testing.T) {
-	svc := NewCustomMemberWorkoutService(&mockRepo{})
-	badStatus := "bad"
-	err := svc.UpdateCustomMemberWorkout("gym", &dto.UpdateCustomMemberWorkoutDTO{ID: "id", Status: &badStatus})
+func TestUpdateCustomMemberWorkout_InvalidRating(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{}, nil, nil, nil, nil)
+	err := svc.UpdateCustomMemberWorkout("gym", &dto.UpdateCustomMemberWorkoutDTO{ID: "id", Rating: intPtr(0)})
 	assert.Error(t, err)
 	apiErr := err.(*apierror.APIError)
 	assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
@@ -115,7 +245,356 @@ func TestUpdateCustomMemberWorkout_InvalidStatus(t *testing.T) {
 func TestDeleteCustomMemberWorkout_NotFound(t *testing.T) {
 	svc := NewCustomMemberWorkoutService(&mockRepo{
 		DeleteFn: func(gymID, id string) error { return sql.ErrNoRows },
-	})
+	}, nil, nil, nil, nil)
 	err := svc.DeleteCustomMemberWorkout("gym", "notfound")
 	assert.Error(t, err)
 }
+
+func strPtr(s string) *string { return &s }
+
+func TestRecordBlockResult(t *testing.T) {
+	timings := mockTimings{
+		"Finisher": {BlockMode: strPtr("amrap"), TimeCapSeconds: intPtr(600)},
+		"Chipper":  {BlockMode: strPtr("for_time"), Rounds: intPtr(1), TimeCapSeconds: intPtr(900)},
+		"Bike":     {BlockMode: strPtr("tabata"), WorkSeconds: intPtr(20), IntervalRestSeconds: intPtr(10), Rounds: intPtr(8)},
+	}
+	cases := []struct {
+		name     string
+		input    dto.RecordBlockResultDTO
+		wantMode string
+		wantErr  string
+	}{
+		{"amrap rounds and reps", dto.RecordBlockResultDTO{BlockName: "Finisher", RoundsCompleted: intPtr(7), ExtraReps: intPtr(12)}, "amrap", ""},
+		{"amrap without rounds", dto.RecordBlockResultDTO{BlockName: "Finisher", ExtraReps: intPtr(3)}, "", errorcode_enum.CodeBadRequest},
+		{"amrap with finish time", dto.RecordBlockResultDTO{BlockName: "Finisher", RoundsCompleted: intPtr(7), FinishTimeSeconds: intPtr(500)}, "", errorcode_enum.CodeBadRequest},
+		{"for time finished", dto.RecordBlockResultDTO{BlockName: "Chipper", FinishTimeSeconds: intPtr(754)}, "for_time", ""},
+		{"for time capped", dto.RecordBlockResultDTO{BlockName: "Chipper", RoundsCompleted: intPtr(0), ExtraReps: intPtr(40)}, "for_time", ""},
+		{"for time over cap", dto.RecordBlockResultDTO{BlockName: "Chipper", FinishTimeSeconds: intPtr(901)}, "", errorcode_enum.CodeBadRequest},
+		{"tabata rounds", dto.RecordBlockResultDTO{BlockName: "Bike", RoundsCompleted: intPtr(8)}, "tabata", ""},
+		{"tabata too many rounds", dto.RecordBlockResultDTO{BlockName: "Bike", RoundsCompleted: intPtr(9)}, "", errorcode_enum.CodeBadRequest},
+		{"standard block", dto.RecordBlockResultDTO{BlockName: "Main", RoundsCompleted: intPtr(3)}, "", errorcode_enum.CodeBadRequest},
+		{"missing block name", dto.RecordBlockResultDTO{RoundsCompleted: intPtr(3)}, "", errorcode_enum.CodeBadRequest},
+	}
+	for _, c := range cases {
+		var savedMode string
+		svc := NewCustomMemberWorkoutService(&mockRepo{
+			GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+				return &dto.ResponseCustomMemberWorkoutDTO{ID: id, WorkoutInstanceID: "instance-1"}, nil
+			},
+			UpsertResultFn: func(gymID, blockMode string, d *dto.RecordBlockResultDTO) (*dto.BlockResultDTO, error) {
+				savedMode = blockMode
+				return &dto.BlockResultDTO{ID: "res-1", MemberWorkoutID: d.MemberWorkoutID, BlockName: d.BlockName, BlockMode: blockMode}, nil
+			},
+		}, nil, timings, nil, nil)
+		input := c.input
+		input.MemberWorkoutID = "mw-1"
+		res, err := svc.RecordBlockResult("gym", &input)
+		if c.wantErr != "" {
+			assert.Error(t, err, c.name)
+			assert.Equal(t, c.wantErr, err.(*apierror.APIError).Code, c.name)
+			continue
+		}
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.wantMode, savedMode, c.name)
+		assert.Equal(t, "res-1", res.ID, c.name)
+	}
+}
+
+func TestRecordBlockResult_MemberWorkoutNotFound(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) { return nil, sql.ErrNoRows },
+	}, nil, mockTimings{}, nil, nil)
+	_, err := svc.RecordBlockResult("gym", &dto.RecordBlockResultDTO{MemberWorkoutID: "missing", BlockName: "Finisher", RoundsCompleted: intPtr(1)})
+	assert.Error(t, err)
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+}
+
+func workoutWithStatus(status string) func(string, string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+	return func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+		return &dto.ResponseCustomMemberWorkoutDTO{ID: id, WorkoutInstanceID: "instance-1", Status: status}, nil
+	}
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestLogSet(t *testing.T) {
+	var checkedInstance string
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: workoutWithStatus("in_progress"),
+		InInstanceFn: func(gymID, instanceID, workoutExerciseID string) (bool, error) {
+			checkedInstance = instanceID
+			return workoutExerciseID == "we-1", nil
+		},
+		CreateSetFn: func(gymID string, d *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+			return &dto.SetLogDTO{ID: "set-1", MemberWorkoutID: d.MemberWorkoutID, WorkoutExerciseID: d.WorkoutExerciseID, SetNumber: 1, Reps: d.Reps, WeightKg: d.WeightKg, Completed: true}, nil
+		},
+	}, nil, nil, nil, nil)
+
+	set, err := svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: intPtr(8), WeightKg: floatPtr(82.5), RPE: floatPtr(8.5)})
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-1", checkedInstance)
+	assert.Equal(t, 1, set.SetNumber)
+	assert.Equal(t, 82.5, *set.WeightKg)
+
+	_, err = svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "other"})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
+
+func TestLogSet_Validation(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{}, nil, nil, nil, nil)
+	cases := []struct {
+		name  string
+		input dto.LogSetDTO
+	}{
+		{"missing exercise", dto.LogSetDTO{MemberWorkoutID: "mw-1"}},
+		{"zero set number", dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", SetNumber: intPtr(0)}},
+		{"negative reps", dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: intPtr(-1)}},
+		{"rpe out of range", dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", RPE: floatPtr(11)}},
+		{"negative rir", dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", RIR: intPtr(-2)}},
+	}
+	for _, c := range cases {
+		_, err := svc.LogSet("gym", &c.input)
+		assert.Error(t, err, c.name)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code, c.name)
+	}
+}
+
+func TestLogSet_AlreadyLogged(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn:    workoutWithStatus("in_progress"),
+		InInstanceFn: func(string, string, string) (bool, error) { return true, nil },
+		CreateSetFn:  func(string, *dto.LogSetDTO) (*dto.SetLogDTO, error) { return nil, sql.ErrNoRows },
+	}, nil, nil, nil, nil)
+	_, err := svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", SetNumber: intPtr(2)})
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+}
+
+func TestLogSet_CancelledWorkout(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{GetByIDFn: workoutWithStatus("cancelled")}, nil, nil, nil, nil)
+	_, err := svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: intPtr(5)})
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+}
+
+func TestUpdateSetLog(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: workoutWithStatus("completed"),
+		UpdateSetFn: func(gymID string, d *dto.UpdateSetLogDTO) (*dto.SetLogDTO, error) {
+			if d.ID != "set-1" {
+				return nil, sql.ErrNoRows
+			}
+			return &dto.SetLogDTO{ID: d.ID, MemberWorkoutID: d.MemberWorkoutID, Reps: d.Reps, Completed: true}, nil
+		},
+	}, nil, nil, nil, nil)
+
+	// Completed sessions can still be corrected
+	set, err := svc.UpdateSetLog("gym", &dto.UpdateSetLogDTO{ID: "set-1", MemberWorkoutID: "mw-1", Reps: intPtr(6)})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, *set.Reps)
+
+	_, err = svc.UpdateSetLog("gym", &dto.UpdateSetLogDTO{ID: "missing", MemberWorkoutID: "mw-1", Reps: intPtr(6)})
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+}
+
+func TestTransitionCustomMemberWorkout(t *testing.T) {
+	cases := []struct {
+		from       string
+		transition string
+		wantStatus string
+		wantErr    string
+	}{
+		{"scheduled", "start", "in_progress", ""},
+		{"scheduled", "skip", "skipped", ""},
+		{"scheduled", "cancel", "cancelled", ""},
+		{"scheduled", "complete", "", errorcode_enum.CodeInvalidTransition},
+		{"scheduled", "pause", "", errorcode_enum.CodeInvalidTransition},
+		{"in_progress", "pause", "paused", ""},
+		{"in_progress", "complete", "completed", ""},
+		{"in_progress", "start", "", errorcode_enum.CodeInvalidTransition},
+		{"in_progress", "skip", "", errorcode_enum.CodeInvalidTransition},
+		{"paused", "resume", "in_progress", ""},
+		{"paused", "complete", "completed", ""},
+		{"paused", "start", "", errorcode_enum.CodeInvalidTransition},
+		{"completed", "cancel", "", errorcode_enum.CodeInvalidTransition},
+		{"cancelled", "start", "", errorcode_enum.CodeInvalidTransition},
+		{"scheduled", "finish", "", errorcode_enum.CodeBadRequest},
+	}
+	for _, c := range cases {
+		name := c.from + " " + c.transition
+		status := c.from
+		var event *dto.TransitionMemberWorkoutDTO
+		svc := NewCustomMemberWorkoutService(&mockRepo{
+			GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+				return &dto.ResponseCustomMemberWorkoutDTO{ID: id, MemberID: "member-1", Status: status}, nil
+			},
+			TransitionFn: func(gymID string, w *dto.ResponseCustomMemberWorkoutDTO, toStatus string, d *dto.TransitionMemberWorkoutDTO) error {
+				event = d
+				status = toStatus
+				return nil
+			},
+		}, nil, nil, nil, nil)
+
+		res, err := svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: c.transition, ChangedBy: "member-1"})
+		if c.wantErr != "" {
+			assert.Error(t, err, name)
+			assert.Equal(t, c.wantErr, err.(*apierror.APIError).Code, name)
+			assert.Nil(t, event, name)
+			continue
+		}
+		assert.NoError(t, err, name)
+		assert.Equal(t, c.wantStatus, res.Status, name)
+		assert.Equal(t, c.transition, event.Transition, name)
+	}
+}
+
+func TestTransitionCustomMemberWorkout_Race(t *testing.T) {
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: workoutWithStatus("scheduled"),
+		TransitionFn: func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error {
+			return sql.ErrNoRows
+		},
+	}, nil, nil, nil, nil)
+	_, err := svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "start"})
+	assert.Equal(t, errorcode_enum.CodeInvalidTransition, err.(*apierror.APIError).Code)
+}
+
+func TestListGymEvents(t *testing.T) {
+	var gotAfter int64
+	var gotLimit int
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		EventsAfterFn: func(gymID string, after int64, limit int) ([]*dto.MemberWorkoutEventDTO, error) {
+			gotAfter, gotLimit = after, limit
+			return []*dto.MemberWorkoutEventDTO{}, nil
+		},
+	}, nil, nil, nil, nil)
+
+	_, err := svc.ListGymEvents("gym", 42, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), gotAfter)
+	assert.Equal(t, 100, gotLimit)
+
+	_, err = svc.ListGymEvents("gym", -1, 10)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	_, err = svc.ListGymEvents("gym", 0, 1000)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
+
+func TestLogSet_FlagsNewRecords(t *testing.T) {
+	setID, otherSetID := "set-1", "set-0"
+	records := &mockRecords{beaten: []*record_dto.PersonalRecordDTO{
+		{RecordType: "max_weight", Value: 100, SetLogID: &setID},
+		{RecordType: "max_reps", Value: 10, SetLogID: &otherSetID},
+		{RecordType: "max_volume", Value: 2400},
+	}}
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn:    workoutWithStatus("in_progress"),
+		InInstanceFn: func(string, string, string) (bool, error) { return true, nil },
+		CreateSetFn: func(gymID string, d *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+			return &dto.SetLogDTO{ID: setID, MemberWorkoutID: d.MemberWorkoutID, SetNumber: 1, Reps: d.Reps, WeightKg: d.WeightKg, Completed: true}, nil
+		},
+		TransitionFn: func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error {
+			return nil
+		},
+	}, nil, nil, records, nil)
+
+	set, err := svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: intPtr(3), WeightKg: floatPtr(100)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mw-1"}, records.updated)
+	if assert.Len(t, set.NewRecords, 1) {
+		assert.Equal(t, "max_weight", set.NewRecords[0].RecordType)
+	}
+
+	// The session gives every record it beat, and completing it recomputes them for its volume
+	res, err := svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "complete"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mw-1", "mw-1"}, records.updated)
+	assert.Len(t, res.NewRecords, 3)
+
+	_, err = svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "pause"})
+	assert.NoError(t, err)
+	assert.Len(t, records.updated, 2)
+}
+
+func TestLogSet_RecordFailureFailsTheWrite(t *testing.T) {
+	records := &mockRecords{err: apierror.New(errorcode_enum.CodeInternal, "Failed to save personal records", nil)}
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn:    workoutWithStatus("in_progress"),
+		InInstanceFn: func(string, string, string) (bool, error) { return true, nil },
+		CreateSetFn: func(gymID string, d *dto.LogSetDTO) (*dto.SetLogDTO, error) {
+			return &dto.SetLogDTO{ID: "set-1", MemberWorkoutID: d.MemberWorkoutID, SetNumber: 1, Completed: true}, nil
+		},
+		DeleteSetFn: func(string, string, string) error { return nil },
+		TransitionFn: func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error {
+			return nil
+		},
+	}, nil, nil, records, nil)
+
+	// The records run in the write's transaction, so their failure rolls the set back
+	_, err := svc.LogSet("gym", &dto.LogSetDTO{MemberWorkoutID: "mw-1", WorkoutExerciseID: "we-1", Reps: intPtr(3)})
+	assert.Equal(t, "Failed to save personal records", err.(*apierror.APIError).Message)
+	err = svc.DeleteSetLog("gym", "mw-1", "set-1")
+	assert.Equal(t, "Failed to save personal records", err.(*apierror.APIError).Message)
+	_, err = svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "complete"})
+	assert.Equal(t, "Failed to save personal records", err.(*apierror.APIError).Message)
+	assert.Len(t, records.updated, 3)
+}
+
+type mockEngagement struct {
+	tracked   []string
+	reason    *string
+	forgotten []string
+	err       error
+}
+
+func (m *mockEngagement) TrackTransition(tx *sql.Tx, gymID, memberID, transition string, reason *string) error {
+	m.tracked = append(m.tracked, memberID+" "+transition)
+	m.reason = reason
+	return m.err
+}
+func (m *mockEngagement) ForgetWorkout(tx *sql.Tx, gymID, memberID string) error {
+	m.forgotten = append(m.forgotten, memberID)
+	return m.err
+}
+
+func TestTransition_TracksEngagementWithSkipReason(t *testing.T) {
+	engagement := &mockEngagement{}
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+			return &dto.ResponseCustomMemberWorkoutDTO{ID: id, MemberID: "member-1", Status: "scheduled"}, nil
+		},
+		TransitionFn: func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error {
+			return nil
+		},
+	}, nil, nil, nil, engagement)
+
+	reason := "travel"
+	_, err := svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "skip", Reason: &reason})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"member-1 skip"}, engagement.tracked)
+	assert.Equal(t, "travel", *engagement.reason)
+
+	_, err = svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "start", Reason: &reason})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	unknown := "weather"
+	_, err = svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "skip", Reason: &unknown})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	assert.Len(t, engagement.tracked, 1)
+}
+
+func TestEngagementFailureFailsTheTransitionAndDelete(t *testing.T) {
+	engagement := &mockEngagement{err: apierror.New(errorcode_enum.CodeInternal, "Failed to update member engagement", nil)}
+	svc := NewCustomMemberWorkoutService(&mockRepo{
+		GetByIDFn: func(gymID, id string) (*dto.ResponseCustomMemberWorkoutDTO, error) {
+			return &dto.ResponseCustomMemberWorkoutDTO{ID: id, MemberID: "member-1", Status: "in_progress"}, nil
+		},
+		TransitionFn: func(string, *dto.ResponseCustomMemberWorkoutDTO, string, *dto.TransitionMemberWorkoutDTO) error {
+			return nil
+		},
+		DeleteFn: func(gymID, id string) error { return nil },
+	}, nil, nil, nil, engagement)
+
+	_, err := svc.TransitionCustomMemberWorkout("gym", &dto.TransitionMemberWorkoutDTO{MemberWorkoutID: "mw-1", Transition: "complete"})
+	assert.Equal(t, "Failed to update member engagement", err.(*apierror.APIError).Message)
+	err = svc.DeleteCustomMemberWorkout("gym", "mw-1")
+	assert.Equal(t, "Failed to update member engagement", err.(*apierror.APIError).Message)
+	assert.Equal(t, []string{"member-1"}, engagement.forgotten)
+}
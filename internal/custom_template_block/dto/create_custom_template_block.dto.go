@@ -1,5 +1,7 @@
 package dto
 
+import templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+
 // CreateCustomTemplateBlockDTO is used for creating a custom template block
 type CreateCustomTemplateBlockDTO struct {
 	TemplateID               string `json:"template_id" validate:"required"`
@@ -13,4 +15,5 @@ type CreateCustomTemplateBlockDTO struct {
 	Series                   *int   `json:"series,omitempty" validate:"omitempty,min=1"`
 	RestTimeSeconds          *int   `json:"rest_time_seconds,omitempty" validate:"omitempty,min=0"`
 	CreatedBy                string `json:"created_by" validate:"required"`
+	templateBlockDTO.BlockTiming
 }
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+)
 
 // ResponseCustomTemplateBlockDTO is used for returning a custom template block
 type ResponseCustomTemplateBlockDTO struct {
@@ -20,4 +24,5 @@ type ResponseCustomTemplateBlockDTO struct {
 	DeletedAt                *time.Time `json:"deleted_at,omitempty"`
 	IsActive                 bool       `json:"is_active"`
 	CreatedBy                string     `json:"created_by"`
+	templateBlockDTO.BlockTiming
 }
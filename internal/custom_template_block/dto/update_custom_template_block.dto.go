@@ -1,5 +1,7 @@
 package dto
 
+import templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+
 // UpdateCustomTemplateBlockDTO is used for updating a custom template block
 type UpdateCustomTemplateBlockDTO struct {
 	ID                       *string `json:"id"`
@@ -13,4 +15,6 @@ type UpdateCustomTemplateBlockDTO struct {
 	Series                   *int    `json:"series,omitempty" validate:"omitempty,min=1"`
 	RestTimeSeconds          *int    `json:"rest_time_seconds,omitempty" validate:"omitempty,min=0"`
 	IsActive                 *bool   `json:"is_active,omitempty"`
+	// The timing is replaced as a whole when block_mode is given
+	templateBlockDTO.BlockTiming
 }
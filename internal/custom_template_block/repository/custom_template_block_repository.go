@@ -22,8 +22,9 @@ func NewCustomTemplateBlockRepository(db *sql.DB) *CustomTemplateBlockRepository
 func (r *CustomTemplateBlockRepository) CreateCustomTemplateBlock(gymID string, block *dto.CreateCustomTemplateBlockDTO) (*string, error) {
 	query := `
 		INSERT INTO "%s".custom_template_block 
-			(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by,
+			block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id`
 
 	var id string
@@ -40,6 +41,11 @@ func (r *CustomTemplateBlockRepository) CreateCustomTemplateBlock(gymID string,
 		block.Series,
 		block.RestTimeSeconds,
 		block.CreatedBy,
+		string(block.Mode()),
+		block.WorkSeconds,
+		block.IntervalRestSeconds,
+		block.Rounds,
+		block.TimeCapSeconds,
 	).Scan(&id)
 
 	if err != nil {
@@ -100,6 +106,13 @@ func (r *CustomTemplateBlockRepository) UpdateCustomTemplateBlock(gymID, id stri
 		args = append(args, *update.RestTimeSeconds)
 		argIndex++
 	}
+	if update.BlockMode != nil {
+		// The timing is replaced as a whole so switching modes clears the parameters of the old one
+		setParts = append(setParts, fmt.Sprintf("block_mode = $%d, work_seconds = $%d, interval_rest_seconds = $%d, rounds = $%d, time_cap_seconds = $%d",
+			argIndex, argIndex+1, argIndex+2, argIndex+3, argIndex+4))
+		args = append(args, *update.BlockMode, update.WorkSeconds, update.IntervalRestSeconds, update.Rounds, update.TimeCapSeconds)
+		argIndex += 5
+	}
 	if update.IsActive != nil {
 		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argIndex))
 		args = append(args, *update.IsActive)
@@ -138,6 +151,7 @@ func (r *CustomTemplateBlockRepository) GetCustomTemplateBlockByID(gymID, id str
 	query := `
 		SELECT id, template_id, block_name, block_type, block_order, exercise_count, 
 			   estimated_duration_minutes, instructions, reps, series, rest_time_seconds, 
+			   block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds,
 			   created_at, updated_at, deleted_at, is_active, created_by
 		FROM "%s".custom_template_block 
 		WHERE id = $1 AND deleted_at IS NULL`
@@ -156,6 +170,11 @@ func (r *CustomTemplateBlockRepository) GetCustomTemplateBlockByID(gymID, id str
 		&res.Reps,
 		&res.Series,
 		&res.RestTimeSeconds,
+		&res.BlockMode,
+		&res.WorkSeconds,
+		&res.IntervalRestSeconds,
+		&res.Rounds,
+		&res.TimeCapSeconds,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -175,6 +194,7 @@ func (r *CustomTemplateBlockRepository) ListCustomTemplateBlocksByTemplateID(gym
 	query := `
 		SELECT id, template_id, block_name, block_type, block_order, exercise_count, 
 			   estimated_duration_minutes, instructions, reps, series, rest_time_seconds, 
+			   block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds,
 			   created_at, updated_at, deleted_at, is_active, created_by
 		FROM "%s".custom_template_block 
 		WHERE template_id = $1 AND deleted_at IS NULL 
@@ -201,6 +221,11 @@ func (r *CustomTemplateBlockRepository) ListCustomTemplateBlocksByTemplateID(gym
 			&res.Reps,
 			&res.Series,
 			&res.RestTimeSeconds,
+			&res.BlockMode,
+			&res.WorkSeconds,
+			&res.IntervalRestSeconds,
+			&res.Rounds,
+			&res.TimeCapSeconds,
 			&res.CreatedAt,
 			&res.UpdatedAt,
 			&res.DeletedAt,
@@ -224,6 +249,7 @@ func (r *CustomTemplateBlockRepository) ListCustomTemplateBlocks(gymID string) (
 	query := `
 		SELECT id, template_id, block_name, block_type, block_order, exercise_count, 
 			   estimated_duration_minutes, instructions, reps, series, rest_time_seconds, 
+			   block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds,
 			   created_at, updated_at, deleted_at, is_active, created_by
 		FROM "%s".custom_template_block 
 		WHERE deleted_at IS NULL
@@ -250,6 +276,11 @@ func (r *CustomTemplateBlockRepository) ListCustomTemplateBlocks(gymID string) (
 			&res.Reps,
 			&res.Series,
 			&res.RestTimeSeconds,
+			&res.BlockMode,
+			&res.WorkSeconds,
+			&res.IntervalRestSeconds,
+			&res.Rounds,
+			&res.TimeCapSeconds,
 			&res.CreatedAt,
 			&res.UpdatedAt,
 			&res.DeletedAt,
@@ -37,9 +37,10 @@ func TestCreateCustomTemplateBlock(t *testing.T) {
 		CreatedBy:                "user123",
 	}
 
-	expectedQuery := `INSERT INTO "gym123"\.custom_template_block \(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\) RETURNING id`
+	expectedQuery := `INSERT INTO "gym123"\.custom_template_block \(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13, \$14, \$15, \$16\) RETURNING id`
 	mock.ExpectQuery(expectedQuery).
-		WithArgs(block.TemplateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes, block.Instructions, block.Reps, block.Series, block.RestTimeSeconds, block.CreatedBy).
+		WithArgs(block.TemplateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes, block.Instructions, block.Reps, block.Series, block.RestTimeSeconds, block.CreatedBy,
+			"standard", block.WorkSeconds, block.IntervalRestSeconds, block.Rounds, block.TimeCapSeconds).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("block123"))
 
 	id, err := repo.CreateCustomTemplateBlock(gymID, block)
@@ -58,13 +59,13 @@ func TestGetByID(t *testing.T) {
 
 	gymID := "gym123"
 	blockID := "block123"
-	expectedQuery := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, updated_at, deleted_at, is_active, created_by FROM "gym123"\.custom_template_block WHERE id = \$1`
+	expectedQuery := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, updated_at, deleted_at, is_active, created_by FROM "gym123"\.custom_template_block WHERE id = \$1`
 
 	now := time.Now()
 	mock.ExpectQuery(expectedQuery).
 		WithArgs(blockID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "created_at", "updated_at", "deleted_at", "is_active", "created_by"}).
-			AddRow("block123", "template123", "Warm-up", "warmup", 1, 3, 10, "Start with light exercises", 15, 3, 60, now, now, nil, true, "user123"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds", "created_at", "updated_at", "deleted_at", "is_active", "created_by"}).
+			AddRow("block123", "template123", "Warm-up", "warmup", 1, 3, 10, "Start with light exercises", 15, 3, 60, "amrap", nil, nil, nil, 600, now, now, nil, true, "user123"))
 
 	result, err := repo.GetCustomTemplateBlockByID(gymID, blockID)
 	assert.NoError(t, err)
@@ -81,6 +82,8 @@ func TestGetByID(t *testing.T) {
 	assert.Equal(t, 3, *result.Series)
 	assert.Equal(t, 60, *result.RestTimeSeconds)
 	assert.Equal(t, "user123", result.CreatedBy)
+	assert.Equal(t, "amrap", *result.BlockMode)
+	assert.Equal(t, 600, *result.TimeCapSeconds)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -93,14 +96,14 @@ func TestGetByTemplateID(t *testing.T) {
 
 	gymID := "gym123"
 	templateID := "template123"
-	expectedQuery := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, updated_at, deleted_at, is_active, created_by FROM "gym123"\.custom_template_block WHERE template_id = \$1 AND deleted_at IS NULL ORDER BY block_order ASC`
+	expectedQuery := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, updated_at, deleted_at, is_active, created_by FROM "gym123"\.custom_template_block WHERE template_id = \$1 AND deleted_at IS NULL ORDER BY block_order ASC`
 
 	now := time.Now()
 	mock.ExpectQuery(expectedQuery).
 		WithArgs(templateID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "created_at", "updated_at", "deleted_at", "is_active", "created_by"}).
-			AddRow("block123", "template123", "Warm-up", "warmup", 1, 3, 10, "Start with light exercises", 15, 3, 60, now, now, nil, true, "user123").
-			AddRow("block124", "template123", "Main Set", "main", 2, 5, 20, "Focus on strength", 8, 4, 90, now, now, nil, true, "user123"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds", "created_at", "updated_at", "deleted_at", "is_active", "created_by"}).
+			AddRow("block123", "template123", "Warm-up", "warmup", 1, 3, 10, "Start with light exercises", 15, 3, 60, "standard", nil, nil, nil, nil, now, now, nil, true, "user123").
+			AddRow("block124", "template123", "Main Set", "main", 2, 5, 20, "Focus on strength", 8, 4, 90, "standard", nil, nil, nil, nil, now, now, nil, true, "user123"))
 
 	result, err := repo.ListCustomTemplateBlocksByTemplateID(gymID, templateID)
 	assert.NoError(t, err)
@@ -143,6 +146,31 @@ func TestUpdateCustomTemplateBlock(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateCustomTemplateBlockReplacesTiming(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCustomTemplateBlockRepository(db)
+
+	update := &dto.UpdateCustomTemplateBlockDTO{
+		BlockName: stringPtr("Finisher"),
+	}
+	update.BlockMode = stringPtr("emom")
+	update.WorkSeconds = intPtr(60)
+	update.Rounds = intPtr(10)
+
+	// Switching from an AMRAP clears its time cap
+	expectedQuery := `UPDATE "gym123"\.custom_template_block SET block_name = \$1, block_mode = \$2, work_seconds = \$3, interval_rest_seconds = \$4, rounds = \$5, time_cap_seconds = \$6, updated_at = CURRENT_TIMESTAMP WHERE id = \$7 AND deleted_at IS NULL`
+	mock.ExpectExec(expectedQuery).
+		WithArgs("Finisher", "emom", update.WorkSeconds, nil, update.Rounds, nil, "block123").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.UpdateCustomTemplateBlock("gym123", "block123", update)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteCustomTemplateBlock(t *testing.T) {
 	db, mock, err := setupMockDB()
 	assert.NoError(t, err)
@@ -22,6 +22,12 @@ func (s *CustomTemplateBlockService) CreateCustomTemplateBlock(gymID string, blo
 	if block == nil {
 		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Block payload is nil", nil)
 	}
+	if err := block.Normalize(); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, err.Error(), nil)
+	}
+	if block.EstimatedDurationMinutes == nil {
+		block.EstimatedDurationMinutes = block.DurationMinutes()
+	}
 
 	id, err := s.Repo.CreateCustomTemplateBlock(gymID, block)
 	if err != nil {
@@ -58,6 +64,16 @@ func (s *CustomTemplateBlockService) ListCustomTemplateBlocks(gymID string) ([]*
 }
 
 func (s *CustomTemplateBlockService) UpdateCustomTemplateBlock(gymID, id string, update *dto.UpdateCustomTemplateBlockDTO) (*dto.ResponseCustomTemplateBlockDTO, error) {
+	if update.BlockMode != nil {
+		if err := update.Normalize(); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, err.Error(), nil)
+		}
+		if update.EstimatedDurationMinutes == nil {
+			update.EstimatedDurationMinutes = update.DurationMinutes()
+		}
+	} else if update.HasTimingFields() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "block_mode is required to change the block timing", nil)
+	}
 	if err := s.Repo.UpdateCustomTemplateBlock(gymID, id, update); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apierror.New(errorcode_enum.CodeNotFound, "Custom template block not found", err)
@@ -230,6 +230,62 @@ func TestCustomTemplateBlockService_UpdateCustomTemplateBlock(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCustomTemplateBlockService_CreateCustomTemplateBlock_Intervals(t *testing.T) {
+	mockRepo := new(MockCustomTemplateBlockRepository)
+	service := NewCustomTemplateBlockService(mockRepo)
+
+	block := &dto.CreateCustomTemplateBlockDTO{
+		TemplateID:    "template123",
+		BlockName:     "Row intervals",
+		BlockType:     "cardio",
+		BlockOrder:    2,
+		ExerciseCount: 1,
+		CreatedBy:     "user123",
+	}
+	block.BlockMode = stringPtr("intervals")
+	block.WorkSeconds = intPtr(90)
+	block.IntervalRestSeconds = intPtr(30)
+	block.Rounds = intPtr(6)
+
+	expectedID := "block123"
+	mockRepo.On("CreateCustomTemplateBlock", "gym123", block).Return(&expectedID, nil)
+
+	_, err := service.CreateCustomTemplateBlock("gym123", block)
+	assert.NoError(t, err)
+	// 6 rounds of 90 seconds on and 30 off
+	assert.Equal(t, 12, *block.EstimatedDurationMinutes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomTemplateBlockService_CreateCustomTemplateBlock_TimingMismatch(t *testing.T) {
+	mockRepo := new(MockCustomTemplateBlockRepository)
+	service := NewCustomTemplateBlockService(mockRepo)
+
+	block := &dto.CreateCustomTemplateBlockDTO{TemplateID: "template123", BlockName: "Finisher", BlockType: "main", BlockOrder: 3, ExerciseCount: 3}
+	block.BlockMode = stringPtr("amrap")
+	block.Rounds = intPtr(5)
+
+	_, err := service.CreateCustomTemplateBlock("gym123", block)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, "BAD_REQUEST", apiErr.Code)
+	mockRepo.AssertNotCalled(t, "CreateCustomTemplateBlock")
+}
+
+func TestCustomTemplateBlockService_UpdateCustomTemplateBlock_TimingWithoutMode(t *testing.T) {
+	mockRepo := new(MockCustomTemplateBlockRepository)
+	service := NewCustomTemplateBlockService(mockRepo)
+
+	update := &dto.UpdateCustomTemplateBlockDTO{}
+	update.Rounds = intPtr(4)
+
+	_, err := service.UpdateCustomTemplateBlock("gym123", "block123", update)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, "BAD_REQUEST", apiErr.Code)
+	mockRepo.AssertNotCalled(t, "UpdateCustomTemplateBlock")
+}
+
 func TestCustomTemplateBlockService_DeleteCustomTemplateBlock(t *testing.T) {
 	mockRepo := new(MockCustomTemplateBlockRepository)
 	service := NewCustomTemplateBlockService(mockRepo)
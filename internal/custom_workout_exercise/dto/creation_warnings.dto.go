@@ -0,0 +1,28 @@
+package dto
+
+import (
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	inventory_dto "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+)
+
+// CreationWarnings are the non-blocking findings reported when an exercise is added to a workout instance
+type CreationWarnings struct {
+	Contraindications []*contraindication_dto.ContraindicationWarning
+	Equipment         []*inventory_dto.EquipmentWarning
+}
+
+func (w *CreationWarnings) Empty() bool {
+	return w == nil || (len(w.Contraindications) == 0 && len(w.Equipment) == 0)
+}
+
+// Details are the fields a 201 response carries next to the new id. Both lists are always present, empty when
+// nothing was found, so clients can rely on one shape.
+func (w *CreationWarnings) Details() map[string]any {
+	contraindications := []*contraindication_dto.ContraindicationWarning{}
+	equipment := []*inventory_dto.EquipmentWarning{}
+	if w != nil {
+		contraindications = append(contraindications, w.Contraindications...)
+		equipment = append(equipment, w.Equipment...)
+	}
+	return map[string]any{"warnings": contraindications, "equipment_warnings": equipment}
+}
@@ -0,0 +1,14 @@
+package dto
+
+// ExerciseGrouping puts an exercise in a superset, giant set or circuit. Exercises of a block sharing a group ID
+// are done one after the other, in exercise order, for the group's rounds.
+type ExerciseGrouping struct {
+	GroupID          *string `json:"group_id,omitempty"`           // e.g. "A" for A1/A2
+	GroupType        *string `json:"group_type,omitempty"`         // superset, giant_set or circuit; taken from the group when omitted
+	GroupRounds      *int    `json:"group_rounds,omitempty"`       // defaults to the most sets of the group's exercises
+	GroupRestSeconds *int    `json:"group_rest_seconds,omitempty"` // rest between rounds
+}
+
+func (g ExerciseGrouping) Grouped() bool {
+	return g.GroupID != nil && *g.GroupID != ""
+}
@@ -18,4 +18,5 @@ type ResponseCustomWorkoutExerciseDTO struct {
 	Notes             *string  `json:"notes,omitempty"`
 	CreatedAt         string   `json:"created_at"`
 	UpdatedAt         string   `json:"updated_at"`
+	ExerciseGrouping
 }
@@ -9,4 +9,5 @@ type UpdateCustomWorkoutExerciseDTO struct {
 	DurationSeconds *int     `json:"duration_seconds,omitempty" validate:"omitempty,min=1"`
 	RestSeconds     *int     `json:"rest_seconds,omitempty" validate:"omitempty,min=0"`
 	Notes           *string  `json:"notes,omitempty"`
+	ExerciseGrouping
 }
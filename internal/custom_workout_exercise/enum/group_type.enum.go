@@ -0,0 +1,26 @@
+package enum
+
+// GroupType is how the exercises sharing a group ID are performed
+type GroupType string
+
+const (
+	Superset GroupType = "superset"  // two exercises back to back
+	GiantSet GroupType = "giant_set" // three or more exercises back to back
+	Circuit  GroupType = "circuit"   // stations repeated for a number of rounds
+)
+
+func (e GroupType) IsValid() bool {
+	switch e {
+	case Superset, GiantSet, Circuit:
+		return true
+	}
+	return false
+}
+
+// MaxExercises is the most exercises a group of this type can hold, 0 when unlimited
+func (e GroupType) MaxExercises() int {
+	if e == Superset {
+		return 2
+	}
+	return 0
+}
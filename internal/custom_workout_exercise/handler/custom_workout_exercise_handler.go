@@ -32,7 +32,7 @@ func (h *CustomWorkoutExerciseHandler) Create(w http.ResponseWriter, r *http.Req
 
 	gymID := middleware.GetGymID(r)
 
-	id, err := h.Service.CreateCustomWorkoutExercise(gymID, &dtoReq)
+	id, warnings, err := h.Service.CreateCustomWorkoutExercise(gymID, &dtoReq)
 	if err != nil {
 		if apiErr, ok := err.(*apierror.APIError); ok {
 			response.WriteAPIError(w, apiErr)
@@ -42,7 +42,14 @@ func (h *CustomWorkoutExerciseHandler) Create(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	response.WriteAPICreated(w, "Workout exercise created successfully", id)
+	message := "Workout exercise created successfully"
+	switch {
+	case warnings != nil && len(warnings.Contraindications) > 0:
+		message = "Workout exercise created with contraindication warnings"
+	case !warnings.Empty():
+		message = "Workout exercise created with equipment warnings"
+	}
+	response.WriteAPICreatedWithDetails(w, message, id, warnings.Details())
 }
 
 func (h *CustomWorkoutExerciseHandler) GetByID(w http.ResponseWriter, r *http.Request) {
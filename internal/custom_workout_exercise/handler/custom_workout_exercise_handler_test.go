@@ -8,8 +8,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/handler"
+	inventory_dto "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 	"github.com/alejandro-albiol/athenai/pkg/middleware"
@@ -25,10 +27,12 @@ type mockService struct {
 	listByEquipmentIDFunc       func(gymID, equipmentID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error)
 	updateFunc                  func(gymID string, exercise *dto.UpdateCustomWorkoutExerciseDTO) error
 	deleteFunc                  func(gymID, id string) error
+	createWarnings              *dto.CreationWarnings
 }
 
-func (m *mockService) CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error) {
-	return m.createFunc(gymID, exercise)
+func (m *mockService) CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, *dto.CreationWarnings, error) {
+	id, err := m.createFunc(gymID, exercise)
+	return id, m.createWarnings, err
 }
 
 func (m *mockService) GetCustomWorkoutExerciseByID(gymID, id string) (*dto.ResponseCustomWorkoutExerciseDTO, error) {
@@ -99,6 +103,61 @@ func TestCreateCustomWorkoutExerciseHandler_Success(t *testing.T) {
 	assert.Equal(t, "Workout exercise created successfully", response["message"])
 }
 
+func TestCreateCustomWorkoutExerciseHandler_ContraindicationWarnings(t *testing.T) {
+	service := &mockService{
+		createFunc: func(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error) {
+			id := "exercise123"
+			return &id, nil
+		},
+		createWarnings: &dto.CreationWarnings{
+			Contraindications: []*contraindication_dto.ContraindicationWarning{
+				{MemberID: "member1", ExerciseSource: "public", ExerciseID: "exercise789", SpecialSituation: "pregnancy", BodyRegion: "abdomen", Severity: "caution"},
+			},
+			Equipment: []*inventory_dto.EquipmentWarning{
+				{ExerciseSource: "public", ExerciseID: "exercise789", EquipmentSource: "public", EquipmentID: "rack1", EquipmentName: "Squat Rack", Status: "in_maintenance"},
+			},
+		},
+	}
+
+	h := handler.NewCustomWorkoutExerciseHandler(service)
+
+	exercise := dto.CreateCustomWorkoutExerciseDTO{
+		CreatedBy:         "user123",
+		WorkoutInstanceID: "workout456",
+		ExerciseSource:    "public",
+		PublicExerciseID:  stringPtr("exercise789"),
+		BlockName:         "main",
+		ExerciseOrder:     1,
+	}
+
+	body, _ := json.Marshal(exercise)
+	req := httptest.NewRequest(http.MethodPost, "/custom-workout-exercises", bytes.NewBuffer(body))
+	req = req.WithContext(contextWithGymID(req.Context(), "gym123"))
+
+	w := httptest.NewRecorder()
+	h.Create(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data struct {
+			ID                string                                         `json:"id"`
+			Warnings          []contraindication_dto.ContraindicationWarning `json:"warnings"`
+			EquipmentWarnings []inventory_dto.EquipmentWarning               `json:"equipment_warnings"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "exercise123", response.Data.ID)
+	if assert.Len(t, response.Data.Warnings, 1) {
+		assert.Equal(t, "member1", response.Data.Warnings[0].MemberID)
+		assert.Equal(t, "caution", response.Data.Warnings[0].Severity)
+	}
+	if assert.Len(t, response.Data.EquipmentWarnings, 1) {
+		assert.Equal(t, "Squat Rack", response.Data.EquipmentWarnings[0].EquipmentName)
+	}
+}
+
 func TestCreateCustomWorkoutExerciseHandler_InvalidJSON(t *testing.T) {
 	service := &mockService{}
 	h := handler.NewCustomWorkoutExerciseHandler(service)
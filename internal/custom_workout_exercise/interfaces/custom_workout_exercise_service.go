@@ -1,9 +1,11 @@
 package interfaces
 
-import "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+import (
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+)
 
 type CustomWorkoutExerciseService interface {
-	CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error)
+	CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, *dto.CreationWarnings, error)
 	GetCustomWorkoutExerciseByID(gymID, id string) (*dto.ResponseCustomWorkoutExerciseDTO, error)
 	ListCustomWorkoutExercisesByWorkoutInstanceID(gymID, workoutInstanceID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error)
 	ListCustomWorkoutExercisesByMuscularGroupID(gymID, muscularGroupID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error)
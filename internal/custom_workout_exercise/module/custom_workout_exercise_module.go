@@ -3,17 +3,30 @@ package module
 import (
 	"database/sql"
 
+	contraindication_repository "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/repository"
+	contraindication_service "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/service"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/handler"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/repository"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/router"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/service"
+	inventory_module "github.com/alejandro-albiol/athenai/internal/equipment_inventory/module"
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
 
 	"net/http"
 )
 
 func NewCustomWorkoutExerciseModule(db *sql.DB) http.Handler {
-	repo := repository.NewCustomWorkoutExerciseRepository(db)
-	service := service.NewCustomWorkoutExerciseService(repo)
+	service := NewCustomWorkoutExerciseService(db)
 	handler := handler.NewCustomWorkoutExerciseHandler(service)
 	return router.NewCustomWorkoutExerciseRouter(handler)
 }
+
+// NewCustomWorkoutExerciseService builds the service other modules use to add exercises to workout instances
+func NewCustomWorkoutExerciseService(db *sql.DB) *service.CustomWorkoutExerciseService {
+	repo := repository.NewCustomWorkoutExerciseRepository(db)
+	checker := contraindication_service.NewContraindicationChecker(
+		contraindication_repository.NewCustomExerciseContraindicationRepository(db),
+		gym_repository.NewGymRepository(db),
+	)
+	return service.NewCustomWorkoutExerciseService(repo, checker, inventory_module.NewEquipmentInventoryService(db))
+}
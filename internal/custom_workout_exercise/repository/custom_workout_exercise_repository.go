@@ -17,8 +17,9 @@ func NewCustomWorkoutExerciseRepository(db *sql.DB) *CustomWorkoutExerciseReposi
 
 func (r *CustomWorkoutExerciseRepository) Create(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error) {
 	query := `INSERT INTO "%s".custom_workout_exercise 
-		(created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name, exercise_order, sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) 
+		(created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name, exercise_order, sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes,
+		group_id, group_type, group_rounds, group_rest_seconds) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) 
 		RETURNING id`
 
 	var id string
@@ -38,6 +39,10 @@ func (r *CustomWorkoutExerciseRepository) Create(gymID string, exercise *dto.Cre
 		exercise.DurationSeconds,
 		exercise.RestSeconds,
 		exercise.Notes,
+		exercise.GroupID,
+		exercise.GroupType,
+		exercise.GroupRounds,
+		exercise.GroupRestSeconds,
 	).Scan(&id)
 	if err != nil {
 		return nil, err
@@ -48,7 +53,7 @@ func (r *CustomWorkoutExerciseRepository) Create(gymID string, exercise *dto.Cre
 func (r *CustomWorkoutExerciseRepository) GetByID(gymID, id string) (*dto.ResponseCustomWorkoutExerciseDTO, error) {
 	query := `SELECT id, created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name, 
 		exercise_order, sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes, 
-		created_at, updated_at 
+		group_id, group_type, group_rounds, group_rest_seconds, created_at, updated_at 
 		FROM "%s".custom_workout_exercise WHERE id = $1`
 
 	row := r.DB.QueryRow(fmt.Sprintf(query, gymID), id)
@@ -72,6 +77,10 @@ func (r *CustomWorkoutExerciseRepository) GetByID(gymID, id string) (*dto.Respon
 		&res.DurationSeconds,
 		&res.RestSeconds,
 		&res.Notes,
+		&res.GroupID,
+		&res.GroupType,
+		&res.GroupRounds,
+		&res.GroupRestSeconds,
 		&createdAt,
 		&updatedAt,
 	)
@@ -92,7 +101,7 @@ func (r *CustomWorkoutExerciseRepository) GetByID(gymID, id string) (*dto.Respon
 func (r *CustomWorkoutExerciseRepository) ListByWorkoutInstanceID(gymID, workoutInstanceID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error) {
 	query := `SELECT id, created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name, 
 		exercise_order, sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes, 
-		created_at, updated_at 
+		group_id, group_type, group_rounds, group_rest_seconds, created_at, updated_at 
 		FROM "%s".custom_workout_exercise WHERE workout_instance_id = $1 ORDER BY block_name, exercise_order`
 
 	rows, err := r.DB.Query(fmt.Sprintf(query, gymID), workoutInstanceID)
@@ -122,6 +131,10 @@ func (r *CustomWorkoutExerciseRepository) ListByWorkoutInstanceID(gymID, workout
 			&res.DurationSeconds,
 			&res.RestSeconds,
 			&res.Notes,
+			&res.GroupID,
+			&res.GroupType,
+			&res.GroupRounds,
+			&res.GroupRestSeconds,
 			&createdAt,
 			&updatedAt,
 		)
@@ -145,7 +158,8 @@ func (r *CustomWorkoutExerciseRepository) ListByWorkoutInstanceID(gymID, workout
 func (r *CustomWorkoutExerciseRepository) ListByMuscularGroupID(gymID, muscularGroupID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error) {
 	query := `SELECT cwe.id, cwe.created_by, cwe.workout_instance_id, cwe.exercise_source, cwe.public_exercise_id, cwe.gym_exercise_id, 
 		cwe.block_name, cwe.exercise_order, cwe.sets, cwe.reps_min, cwe.reps_max, cwe.weight_kg, 
-		cwe.duration_seconds, cwe.rest_seconds, cwe.notes, cwe.created_at, cwe.updated_at 
+		cwe.duration_seconds, cwe.rest_seconds, cwe.notes, cwe.group_id, cwe.group_type, cwe.group_rounds, cwe.group_rest_seconds,
+		cwe.created_at, cwe.updated_at 
 		FROM "%s".custom_workout_exercise cwe
 		LEFT JOIN public.exercise_muscular_group emg ON cwe.public_exercise_id = emg.exercise_id
 		LEFT JOIN "%s".custom_exercise_muscular_group cemg ON cwe.gym_exercise_id = cemg.exercise_id
@@ -179,6 +193,10 @@ func (r *CustomWorkoutExerciseRepository) ListByMuscularGroupID(gymID, muscularG
 			&res.DurationSeconds,
 			&res.RestSeconds,
 			&res.Notes,
+			&res.GroupID,
+			&res.GroupType,
+			&res.GroupRounds,
+			&res.GroupRestSeconds,
 			&createdAt,
 			&updatedAt,
 		)
@@ -202,11 +220,12 @@ func (r *CustomWorkoutExerciseRepository) ListByMuscularGroupID(gymID, muscularG
 func (r *CustomWorkoutExerciseRepository) ListByEquipmentID(gymID, equipmentID string) ([]*dto.ResponseCustomWorkoutExerciseDTO, error) {
 	query := `SELECT cwe.id, cwe.created_by, cwe.workout_instance_id, cwe.exercise_source, cwe.public_exercise_id, cwe.gym_exercise_id, 
 		cwe.block_name, cwe.exercise_order, cwe.sets, cwe.reps_min, cwe.reps_max, cwe.weight_kg, 
-		cwe.duration_seconds, cwe.rest_seconds, cwe.notes, cwe.created_at, cwe.updated_at 
+		cwe.duration_seconds, cwe.rest_seconds, cwe.notes, cwe.group_id, cwe.group_type, cwe.group_rounds, cwe.group_rest_seconds,
+		cwe.created_at, cwe.updated_at 
 		FROM "%s".custom_workout_exercise cwe
-		LEFT JOIN public.exercise_equipment ee ON cwe.public_exercise_id = ee.exercise_id
-		LEFT JOIN "%s".custom_exercise_equipment cee ON cwe.gym_exercise_id = cee.exercise_id
-		WHERE ee.equipment_id = $1 OR cee.equipment_id = $1
+		WHERE EXISTS (SELECT 1 FROM public.exercise_equipment ee WHERE ee.exercise_id = cwe.public_exercise_id AND ee.equipment_id = $1)
+		OR EXISTS (SELECT 1 FROM "%s".custom_exercise_equipment cee WHERE cee.custom_exercise_id = cwe.gym_exercise_id
+			AND (cee.public_equipment_id = $1 OR cee.gym_equipment_id = $1))
 		ORDER BY cwe.block_name, cwe.exercise_order`
 
 	rows, err := r.DB.Query(fmt.Sprintf(query, gymID, gymID), equipmentID)
@@ -236,6 +255,10 @@ func (r *CustomWorkoutExerciseRepository) ListByEquipmentID(gymID, equipmentID s
 			&res.DurationSeconds,
 			&res.RestSeconds,
 			&res.Notes,
+			&res.GroupID,
+			&res.GroupType,
+			&res.GroupRounds,
+			&res.GroupRestSeconds,
 			&createdAt,
 			&updatedAt,
 		)
@@ -256,13 +279,21 @@ func (r *CustomWorkoutExerciseRepository) ListByEquipmentID(gymID, equipmentID s
 	return result, nil
 }
 
+// Update saves the exercise and gives the other exercises of its group the same type, rounds and rest in one transaction
 func (r *CustomWorkoutExerciseRepository) Update(gymID string, exercise *dto.UpdateCustomWorkoutExerciseDTO) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE "%s".custom_workout_exercise SET 
 		sets = $1, reps_min = $2, reps_max = $3, weight_kg = $4, 
-		duration_seconds = $5, rest_seconds = $6, notes = $7, updated_at = NOW()
-		WHERE id = $8`
+		duration_seconds = $5, rest_seconds = $6, notes = $7,
+		group_id = $8, group_type = $9, group_rounds = $10, group_rest_seconds = $11, updated_at = NOW()
+		WHERE id = $12`
 
-	_, err := r.DB.Exec(
+	_, err = tx.Exec(
 		fmt.Sprintf(query, gymID),
 		exercise.Sets,
 		exercise.RepsMin,
@@ -271,9 +302,28 @@ func (r *CustomWorkoutExerciseRepository) Update(gymID string, exercise *dto.Upd
 		exercise.DurationSeconds,
 		exercise.RestSeconds,
 		exercise.Notes,
+		exercise.GroupID,
+		exercise.GroupType,
+		exercise.GroupRounds,
+		exercise.GroupRestSeconds,
 		exercise.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if exercise.Grouped() {
+		query = `UPDATE "%[1]s".custom_workout_exercise SET
+			group_type = $1, group_rounds = $2, group_rest_seconds = $3, updated_at = NOW()
+			WHERE group_id = $4 AND id <> $5
+				AND workout_instance_id = (SELECT workout_instance_id FROM "%[1]s".custom_workout_exercise WHERE id = $5)
+				AND (group_type, group_rounds, group_rest_seconds) IS DISTINCT FROM ($1, $2, $3)`
+		if _, err := tx.Exec(fmt.Sprintf(query, gymID), exercise.GroupType, exercise.GroupRounds, exercise.GroupRestSeconds,
+			exercise.GroupID, exercise.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (r *CustomWorkoutExerciseRepository) Delete(gymID, id string) error {
@@ -51,6 +51,10 @@ func TestCreateCustomWorkoutExercise(t *testing.T) {
 			exercise.DurationSeconds,
 			exercise.RestSeconds,
 			exercise.Notes,
+			exercise.GroupID,
+			exercise.GroupType,
+			exercise.GroupRounds,
+			exercise.GroupRestSeconds,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exercise123"))
 
@@ -95,6 +99,10 @@ func TestCreateCustomWorkoutExerciseError(t *testing.T) {
 			exercise.DurationSeconds,
 			exercise.RestSeconds,
 			exercise.Notes,
+			exercise.GroupID,
+			exercise.GroupType,
+			exercise.GroupRounds,
+			exercise.GroupRestSeconds,
 		).
 		WillReturnError(errors.New("database error"))
 
@@ -118,11 +126,13 @@ func TestGetByID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg",
-		"duration_seconds", "rest_seconds", "notes", "created_at", "updated_at",
+		"duration_seconds", "rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds",
+		"created_at", "updated_at",
 	}).AddRow(
 		"exercise123", "user123", "workout456", "public", "exercise789", nil,
 		"main", 1, 3, 8, 12, 50.5,
-		nil, 60, "Test exercise", "2023-01-01 10:00:00", "2023-01-01 10:00:00",
+		nil, 60, "Test exercise", "A", "superset", 3, 90,
+		"2023-01-01 10:00:00", "2023-01-01 10:00:00",
 	)
 
 	mock.ExpectQuery(`SELECT id, created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name,`).
@@ -146,6 +156,10 @@ func TestGetByID(t *testing.T) {
 	assert.Equal(t, 50.5, *result.WeightKg)
 	assert.Equal(t, 60, *result.RestSeconds)
 	assert.Equal(t, "Test exercise", *result.Notes)
+	assert.Equal(t, "A", *result.GroupID)
+	assert.Equal(t, "superset", *result.GroupType)
+	assert.Equal(t, 3, *result.GroupRounds)
+	assert.Equal(t, 90, *result.GroupRestSeconds)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -161,10 +175,11 @@ func TestListByWorkoutInstanceID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg",
-		"duration_seconds", "rest_seconds", "notes", "created_at", "updated_at",
+		"duration_seconds", "rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds",
+		"created_at", "updated_at",
 	}).
-		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "warmup", 1, 2, 10, 15, 20.0, nil, 30, "Warmup", "2023-01-01 10:00:00", "2023-01-01 10:00:00").
-		AddRow("ex2", "user123", "workout456", "gym", nil, "gym_ex1", "main", 1, 3, 8, 12, 50.5, nil, 60, "Main exercise", "2023-01-01 10:05:00", "2023-01-01 10:05:00")
+		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "warmup", 1, 2, 10, 15, 20.0, nil, 30, "Warmup", nil, nil, nil, nil, "2023-01-01 10:00:00", "2023-01-01 10:00:00").
+		AddRow("ex2", "user123", "workout456", "gym", nil, "gym_ex1", "main", 1, 3, 8, 12, 50.5, nil, 60, "Main exercise", nil, nil, nil, nil, "2023-01-01 10:05:00", "2023-01-01 10:05:00")
 
 	mock.ExpectQuery(`SELECT id, created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name,`).
 		WithArgs(workoutInstanceID).
@@ -195,9 +210,10 @@ func TestListByMuscularGroupID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg",
-		"duration_seconds", "rest_seconds", "notes", "created_at", "updated_at",
+		"duration_seconds", "rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds",
+		"created_at", "updated_at",
 	}).
-		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "main", 1, 3, 8, 12, 50.5, nil, 60, "Chest exercise", "2023-01-01 10:00:00", "2023-01-01 10:00:00")
+		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "main", 1, 3, 8, 12, 50.5, nil, 60, "Chest exercise", nil, nil, nil, nil, "2023-01-01 10:00:00", "2023-01-01 10:00:00")
 
 	mock.ExpectQuery(`SELECT cwe.id, cwe.created_by, cwe.workout_instance_id, cwe.exercise_source, cwe.public_exercise_id, cwe.gym_exercise_id,`).
 		WithArgs(muscularGroupID).
@@ -224,9 +240,10 @@ func TestListByEquipmentID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg",
-		"duration_seconds", "rest_seconds", "notes", "created_at", "updated_at",
+		"duration_seconds", "rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds",
+		"created_at", "updated_at",
 	}).
-		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "main", 1, 3, 8, 12, 50.5, nil, 60, "Barbell exercise", "2023-01-01 10:00:00", "2023-01-01 10:00:00")
+		AddRow("ex1", "user123", "workout456", "public", "exercise789", nil, "main", 1, 3, 8, 12, 50.5, nil, 60, "Barbell exercise", nil, nil, nil, nil, "2023-01-01 10:00:00", "2023-01-01 10:00:00")
 
 	mock.ExpectQuery(`SELECT cwe.id, cwe.created_by, cwe.workout_instance_id, cwe.exercise_source, cwe.public_exercise_id, cwe.gym_exercise_id,`).
 		WithArgs(equipmentID).
@@ -260,6 +277,7 @@ func TestUpdateCustomWorkoutExercise(t *testing.T) {
 		Notes:           stringPtr("Updated exercise"),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE "gym123".custom_workout_exercise SET`).
 		WithArgs(
 			exercise.Sets,
@@ -269,9 +287,14 @@ func TestUpdateCustomWorkoutExercise(t *testing.T) {
 			exercise.DurationSeconds,
 			exercise.RestSeconds,
 			exercise.Notes,
+			exercise.GroupID,
+			exercise.GroupType,
+			exercise.GroupRounds,
+			exercise.GroupRestSeconds,
 			exercise.ID,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	err = repo.Update(gymID, exercise)
 
@@ -279,6 +302,31 @@ func TestUpdateCustomWorkoutExercise(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateCustomWorkoutExercise_RegroupsSiblings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomWorkoutExerciseRepository(db)
+	exercise := &dto.UpdateCustomWorkoutExerciseDTO{
+		ID:               "a1",
+		ExerciseGrouping: dto.ExerciseGrouping{GroupID: stringPtr("A"), GroupType: stringPtr("circuit"), GroupRounds: intPtr(4)},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "gym123".custom_workout_exercise SET`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "gym123".custom_workout_exercise SET\s+group_type = \$1, group_rounds = \$2, group_rest_seconds = \$3.*WHERE group_id = \$4 AND id <> \$5`).
+		WithArgs(exercise.GroupType, exercise.GroupRounds, nil, exercise.GroupID, "a1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.Update("gym123", exercise)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteCustomWorkoutExercise(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
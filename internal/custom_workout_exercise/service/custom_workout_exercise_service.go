@@ -4,99 +4,131 @@ import (
 	"database/sql"
 	"fmt"
 
+	contraindication_interfaces "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/enum"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/interfaces"
+	inventory_interfaces "github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 )
 
 type CustomWorkoutExerciseService struct {
-	Repo interfaces.CustomWorkoutExerciseRepository
+	Repo      interfaces.CustomWorkoutExerciseRepository
+	Checker   contraindication_interfaces.ContraindicationChecker
+	Equipment inventory_interfaces.EquipmentAvailabilityChecker
 }
 
-func NewCustomWorkoutExerciseService(repo interfaces.CustomWorkoutExerciseRepository) *CustomWorkoutExerciseService {
-	return &CustomWorkoutExerciseService{Repo: repo}
+func NewCustomWorkoutExerciseService(repo interfaces.CustomWorkoutExerciseRepository, checker contraindication_interfaces.ContraindicationChecker, equipment inventory_interfaces.EquipmentAvailabilityChecker) *CustomWorkoutExerciseService {
+	return &CustomWorkoutExerciseService{Repo: repo, Checker: checker, Equipment: equipment}
 }
 
-func (s *CustomWorkoutExerciseService) CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error) {
+func (s *CustomWorkoutExerciseService) CreateCustomWorkoutExercise(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, *dto.CreationWarnings, error) {
 	// Validate required fields
 	if exercise.CreatedBy == "" {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "CreatedBy is required", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "CreatedBy is required", nil)
 	}
 	if exercise.WorkoutInstanceID == "" {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "WorkoutInstanceID is required", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "WorkoutInstanceID is required", nil)
 	}
 	if exercise.BlockName == "" {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "BlockName is required", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "BlockName is required", nil)
 	}
 	if exercise.ExerciseOrder <= 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ExerciseOrder must be greater than 0", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "ExerciseOrder must be greater than 0", nil)
 	}
 
 	// Validate exercise source and IDs
 	if exercise.ExerciseSource != "public" && exercise.ExerciseSource != "gym" {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ExerciseSource must be 'public' or 'gym'", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "ExerciseSource must be 'public' or 'gym'", nil)
 	}
 
 	if exercise.ExerciseSource == "public" {
 		if exercise.PublicExerciseID == nil || *exercise.PublicExerciseID == "" {
-			return nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicExerciseID is required when ExerciseSource is 'public'", nil)
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicExerciseID is required when ExerciseSource is 'public'", nil)
 		}
 		if exercise.GymExerciseID != nil && *exercise.GymExerciseID != "" {
-			return nil, apierror.New(errorcode_enum.CodeBadRequest, "GymExerciseID must be empty when ExerciseSource is 'public'", nil)
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "GymExerciseID must be empty when ExerciseSource is 'public'", nil)
 		}
 	} else {
 		if exercise.GymExerciseID == nil || *exercise.GymExerciseID == "" {
-			return nil, apierror.New(errorcode_enum.CodeBadRequest, "GymExerciseID is required when ExerciseSource is 'gym'", nil)
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "GymExerciseID is required when ExerciseSource is 'gym'", nil)
 		}
 		if exercise.PublicExerciseID != nil && *exercise.PublicExerciseID != "" {
-			return nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicExerciseID must be empty when ExerciseSource is 'gym'", nil)
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "PublicExerciseID must be empty when ExerciseSource is 'gym'", nil)
 		}
 	}
 
 	// Validate numeric values
 	if exercise.Sets != nil && *exercise.Sets <= 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Sets must be greater than 0", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "Sets must be greater than 0", nil)
 	}
 	if exercise.RepsMin != nil && *exercise.RepsMin <= 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMin must be greater than 0", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMin must be greater than 0", nil)
 	}
 	if exercise.RepsMax != nil && *exercise.RepsMax <= 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMax must be greater than 0", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMax must be greater than 0", nil)
 	}
 	if exercise.RepsMin != nil && exercise.RepsMax != nil && *exercise.RepsMin > *exercise.RepsMax {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMin cannot be greater than RepsMax", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "RepsMin cannot be greater than RepsMax", nil)
 	}
 	if exercise.WeightKg != nil && *exercise.WeightKg < 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "WeightKg cannot be negative", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "WeightKg cannot be negative", nil)
 	}
 	if exercise.DurationSeconds != nil && *exercise.DurationSeconds <= 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "DurationSeconds must be greater than 0", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "DurationSeconds must be greater than 0", nil)
 	}
 	if exercise.RestSeconds != nil && *exercise.RestSeconds < 0 {
-		return nil, apierror.New(errorcode_enum.CodeBadRequest, "RestSeconds cannot be negative", nil)
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "RestSeconds cannot be negative", nil)
 	}
 
 	// Check for duplicate exercise order in the same block and workout instance
 	existingExercises, err := s.Repo.ListByWorkoutInstanceID(gymID, exercise.WorkoutInstanceID)
 	if err != nil && err != sql.ErrNoRows {
-		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check existing exercises", err)
+		return nil, nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check existing exercises", err)
 	}
 
 	for _, existing := range existingExercises {
 		if existing.BlockName == exercise.BlockName && existing.ExerciseOrder == exercise.ExerciseOrder {
-			return nil, apierror.New(errorcode_enum.CodeConflict,
+			return nil, nil, apierror.New(errorcode_enum.CodeConflict,
 				fmt.Sprintf("Exercise order %d already exists in block '%s'", exercise.ExerciseOrder, exercise.BlockName), nil)
 		}
 	}
 
+	// Join the exercise to its superset, giant set or circuit
+	if err := resolveGrouping(existingExercises, "", exercise.BlockName, &exercise.ExerciseGrouping, false); err != nil {
+		return nil, nil, err
+	}
+
+	exerciseID := exercise.PublicExerciseID
+	if exercise.ExerciseSource == "gym" {
+		exerciseID = exercise.GymExerciseID
+	}
+	warnings := &dto.CreationWarnings{}
+
+	// Check the exercise against members already scheduled on this instance
+	if s.Checker != nil {
+		warnings.Contraindications, err = s.Checker.CheckWorkoutExercise(gymID, exercise.WorkoutInstanceID, exercise.ExerciseSource, *exerciseID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Warn when the gym's inventory has the needed equipment out of service
+	if s.Equipment != nil {
+		warnings.Equipment, err = s.Equipment.CheckExerciseEquipment(gymID, exercise.ExerciseSource, *exerciseID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Create the exercise
 	id, err := s.Repo.Create(gymID, exercise)
 	if err != nil {
-		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create workout exercise", err)
+		return nil, nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create workout exercise", err)
 	}
 
-	return id, nil
+	return id, warnings, nil
 }
 
 func (s *CustomWorkoutExerciseService) GetCustomWorkoutExerciseByID(gymID, id string) (*dto.ResponseCustomWorkoutExerciseDTO, error) {
@@ -157,7 +189,7 @@ func (s *CustomWorkoutExerciseService) UpdateCustomWorkoutExercise(gymID string,
 	}
 
 	// Check if exercise exists before updating
-	_, err := s.Repo.GetByID(gymID, exercise.ID)
+	current, err := s.Repo.GetByID(gymID, exercise.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return apierror.New(errorcode_enum.CodeNotFound, "Workout exercise not found", err)
@@ -165,6 +197,18 @@ func (s *CustomWorkoutExerciseService) UpdateCustomWorkoutExercise(gymID string,
 		return apierror.New(errorcode_enum.CodeInternal, "Failed to check existing workout exercise", err)
 	}
 
+	// Updates replace the grouping too, so an exercise leaves its group when group_id is omitted, and a new
+	// type, rounds or rest applies to every exercise of the group
+	var siblings []*dto.ResponseCustomWorkoutExerciseDTO
+	if exercise.Grouped() {
+		if siblings, err = s.Repo.ListByWorkoutInstanceID(gymID, current.WorkoutInstanceID); err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Failed to check existing exercises", err)
+		}
+	}
+	if err := resolveGrouping(siblings, current.ID, current.BlockName, &exercise.ExerciseGrouping, true); err != nil {
+		return err
+	}
+
 	err = s.Repo.Update(gymID, exercise)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -226,3 +270,70 @@ func (s *CustomWorkoutExerciseService) ListCustomWorkoutExercisesByEquipmentID(g
 
 	return exercises, nil
 }
+
+// resolveGrouping checks an exercise's grouping against the other exercises of its group, filling the group type,
+// rounds and rest it leaves out from them. A group stays in one block, and a superset holds two exercises.
+// New exercises must match the group; an update given regroup changes the whole group instead.
+func resolveGrouping(exercises []*dto.ResponseCustomWorkoutExerciseDTO, exerciseID, blockName string, grouping *dto.ExerciseGrouping, regroup bool) error {
+	if !grouping.Grouped() {
+		if grouping.GroupType != nil || grouping.GroupRounds != nil || grouping.GroupRestSeconds != nil {
+			return apierror.New(errorcode_enum.CodeBadRequest, "GroupID is required to set a group type, rounds or rest", nil)
+		}
+		grouping.GroupID = nil
+		return nil
+	}
+	if grouping.GroupRounds != nil && *grouping.GroupRounds <= 0 {
+		return apierror.New(errorcode_enum.CodeBadRequest, "GroupRounds must be greater than 0", nil)
+	}
+	if grouping.GroupRestSeconds != nil && *grouping.GroupRestSeconds < 0 {
+		return apierror.New(errorcode_enum.CodeBadRequest, "GroupRestSeconds cannot be negative", nil)
+	}
+
+	members := 0
+	for _, other := range exercises {
+		if other.ID == exerciseID || !other.Grouped() || *other.GroupID != *grouping.GroupID {
+			continue
+		}
+		if other.BlockName != blockName {
+			return apierror.New(errorcode_enum.CodeConflict,
+				fmt.Sprintf("Group '%s' belongs to block '%s'", *grouping.GroupID, other.BlockName), nil)
+		}
+		members++
+		if members == 1 {
+			if grouping.GroupType == nil {
+				grouping.GroupType = other.GroupType
+			}
+			if grouping.GroupRounds == nil {
+				grouping.GroupRounds = other.GroupRounds
+			}
+			if grouping.GroupRestSeconds == nil {
+				grouping.GroupRestSeconds = other.GroupRestSeconds
+			}
+		}
+		if !regroup && (!sameValue(grouping.GroupType, other.GroupType) || !sameValue(grouping.GroupRounds, other.GroupRounds) ||
+			!sameValue(grouping.GroupRestSeconds, other.GroupRestSeconds)) {
+			return apierror.New(errorcode_enum.CodeConflict,
+				fmt.Sprintf("Group '%s' already has a different type, rounds or rest; update one of its exercises to change the group", *grouping.GroupID), nil)
+		}
+	}
+
+	if grouping.GroupType == nil {
+		return apierror.New(errorcode_enum.CodeBadRequest, "GroupType is required for a new group", nil)
+	}
+	groupType := enum.GroupType(*grouping.GroupType)
+	if !groupType.IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "GroupType must be 'superset', 'giant_set' or 'circuit'", nil)
+	}
+	if limit := groupType.MaxExercises(); limit > 0 && members >= limit {
+		return apierror.New(errorcode_enum.CodeConflict,
+			fmt.Sprintf("Group '%s' is a %s and already has %d exercises", *grouping.GroupID, groupType, limit), nil)
+	}
+	return nil
+}
+
+func sameValue[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"testing"
 
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/service"
+	inventory_dto "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 	"github.com/stretchr/testify/assert"
@@ -21,12 +23,15 @@ type mockRepository struct {
 	deleteErr                  error
 	exercises                  []*dto.ResponseCustomWorkoutExerciseDTO
 	lastCreatedID              string
+	lastCreated                *dto.CreateCustomWorkoutExerciseDTO
+	lastUpdated                *dto.UpdateCustomWorkoutExerciseDTO
 }
 
 func (m *mockRepository) Create(gymID string, exercise *dto.CreateCustomWorkoutExerciseDTO) (*string, error) {
 	if m.createErr != nil {
 		return nil, m.createErr
 	}
+	m.lastCreated = exercise
 	return &m.lastCreatedID, nil
 }
 
@@ -70,6 +75,7 @@ func (m *mockRepository) ListByEquipmentID(gymID, equipmentID string) ([]*dto.Re
 }
 
 func (m *mockRepository) Update(gymID string, exercise *dto.UpdateCustomWorkoutExerciseDTO) error {
+	m.lastUpdated = exercise
 	return m.updateErr
 }
 
@@ -77,12 +83,29 @@ func (m *mockRepository) Delete(gymID, id string) error {
 	return m.deleteErr
 }
 
+type mockChecker struct {
+	warnings      []*contraindication_dto.ContraindicationWarning
+	err           error
+	checkedSource string
+	checkedID     string
+}
+
+func (m *mockChecker) CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return m.warnings, m.err
+}
+
+func (m *mockChecker) CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	m.checkedSource = exerciseSource
+	m.checkedID = exerciseID
+	return m.warnings, m.err
+}
+
 func TestCreateCustomWorkoutExercise_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		lastCreatedID: "exercise123",
 		exercises:     []*dto.ResponseCustomWorkoutExerciseDTO{}, // Empty list for duplicate check
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	exercise := &dto.CreateCustomWorkoutExerciseDTO{
@@ -100,7 +123,7 @@ func TestCreateCustomWorkoutExercise_Success(t *testing.T) {
 		Notes:             stringPtr("Test exercise"),
 	}
 
-	id, err := svc.CreateCustomWorkoutExercise(gymID, exercise)
+	id, _, err := svc.CreateCustomWorkoutExercise(gymID, exercise)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, id)
@@ -225,10 +248,10 @@ func TestCreateCustomWorkoutExercise_ValidationErrors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockRepository{exercises: []*dto.ResponseCustomWorkoutExerciseDTO{}}
-			svc := service.NewCustomWorkoutExerciseService(mockRepo)
+			svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 			gymID := "gym123"
 
-			id, err := svc.CreateCustomWorkoutExercise(gymID, tt.exercise)
+			id, _, err := svc.CreateCustomWorkoutExercise(gymID, tt.exercise)
 
 			assert.Error(t, err)
 			assert.Nil(t, id)
@@ -248,7 +271,7 @@ func TestCreateCustomWorkoutExercise_DuplicateOrderConflict(t *testing.T) {
 			},
 		},
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	exercise := &dto.CreateCustomWorkoutExerciseDTO{
@@ -260,7 +283,7 @@ func TestCreateCustomWorkoutExercise_DuplicateOrderConflict(t *testing.T) {
 		ExerciseOrder:     1, // Duplicate order
 	}
 
-	id, err := svc.CreateCustomWorkoutExercise(gymID, exercise)
+	id, _, err := svc.CreateCustomWorkoutExercise(gymID, exercise)
 
 	assert.Error(t, err)
 	assert.Nil(t, id)
@@ -284,7 +307,7 @@ func TestGetCustomWorkoutExerciseByID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: []*dto.ResponseCustomWorkoutExerciseDTO{expectedExercise},
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	result, err := svc.GetCustomWorkoutExerciseByID(gymID, "exercise123")
@@ -297,7 +320,7 @@ func TestGetCustomWorkoutExerciseByID_NotFound(t *testing.T) {
 	mockRepo := &mockRepository{
 		getByIDErr: sql.ErrNoRows,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	result, err := svc.GetCustomWorkoutExerciseByID(gymID, "nonexistent")
@@ -328,7 +351,7 @@ func TestListCustomWorkoutExercisesByWorkoutInstanceID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: expectedExercises,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	result, err := svc.ListCustomWorkoutExercisesByWorkoutInstanceID(gymID, "workout456")
@@ -345,7 +368,7 @@ func TestListCustomWorkoutExercisesByMuscularGroupID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: expectedExercises,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	result, err := svc.ListCustomWorkoutExercisesByMuscularGroupID(gymID, "muscle123")
@@ -362,7 +385,7 @@ func TestListCustomWorkoutExercisesByEquipmentID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: expectedExercises,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	result, err := svc.ListCustomWorkoutExercisesByEquipmentID(gymID, "equipment123")
@@ -379,7 +402,7 @@ func TestUpdateCustomWorkoutExercise_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: []*dto.ResponseCustomWorkoutExerciseDTO{existingExercise},
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	updateDTO := &dto.UpdateCustomWorkoutExerciseDTO{
@@ -398,7 +421,7 @@ func TestUpdateCustomWorkoutExercise_NotFound(t *testing.T) {
 	mockRepo := &mockRepository{
 		getByIDErr: sql.ErrNoRows,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	updateDTO := &dto.UpdateCustomWorkoutExerciseDTO{
@@ -421,7 +444,7 @@ func TestDeleteCustomWorkoutExercise_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		exercises: []*dto.ResponseCustomWorkoutExerciseDTO{existingExercise},
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	err := svc.DeleteCustomWorkoutExercise(gymID, "exercise123")
@@ -433,7 +456,7 @@ func TestDeleteCustomWorkoutExercise_NotFound(t *testing.T) {
 	mockRepo := &mockRepository{
 		getByIDErr: sql.ErrNoRows,
 	}
-	svc := service.NewCustomWorkoutExerciseService(mockRepo)
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
 	gymID := "gym123"
 
 	err := svc.DeleteCustomWorkoutExercise(gymID, "nonexistent")
@@ -456,3 +479,222 @@ func intPtr(i int) *int {
 func floatPtr(f float64) *float64 {
 	return &f
 }
+
+type mockEquipmentChecker struct {
+	warnings      []*inventory_dto.EquipmentWarning
+	checkedSource string
+	checkedID     string
+}
+
+func (m *mockEquipmentChecker) ListAvailableEquipment(gymID string) ([]*inventory_dto.AvailableEquipment, error) {
+	return nil, nil
+}
+
+func (m *mockEquipmentChecker) CheckExerciseEquipment(gymID, exerciseSource, exerciseID string) ([]*inventory_dto.EquipmentWarning, error) {
+	m.checkedSource = exerciseSource
+	m.checkedID = exerciseID
+	return m.warnings, nil
+}
+
+func TestCreateCustomWorkoutExercise_ContraindicationWarnings(t *testing.T) {
+	mockRepo := &mockRepository{lastCreatedID: "exercise123"}
+	checker := &mockChecker{
+		warnings: []*contraindication_dto.ContraindicationWarning{
+			{MemberID: "member1", ExerciseSource: "gym", ExerciseID: "custom789", SpecialSituation: "pregnancy", BodyRegion: "abdomen", Severity: "caution"},
+		},
+	}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, checker, nil)
+
+	exercise := &dto.CreateCustomWorkoutExerciseDTO{
+		CreatedBy:         "user123",
+		WorkoutInstanceID: "workout456",
+		ExerciseSource:    "gym",
+		GymExerciseID:     stringPtr("custom789"),
+		BlockName:         "main",
+		ExerciseOrder:     1,
+	}
+
+	id, warnings, err := svc.CreateCustomWorkoutExercise("gym123", exercise)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "exercise123", *id)
+	assert.Len(t, warnings.Contraindications, 1)
+	assert.Equal(t, "gym", checker.checkedSource)
+	assert.Equal(t, "custom789", checker.checkedID)
+}
+
+func TestCreateCustomWorkoutExercise_ContraindicationBlocked(t *testing.T) {
+	mockRepo := &mockRepository{lastCreatedID: "exercise123", createErr: sql.ErrConnDone}
+	checker := &mockChecker{err: apierror.New(errorcode_enum.CodeConflict, "blocked", nil)}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, checker, nil)
+
+	exercise := &dto.CreateCustomWorkoutExerciseDTO{
+		CreatedBy:         "user123",
+		WorkoutInstanceID: "workout456",
+		ExerciseSource:    "public",
+		PublicExerciseID:  stringPtr("exercise789"),
+		BlockName:         "main",
+		ExerciseOrder:     1,
+	}
+
+	id, _, err := svc.CreateCustomWorkoutExercise("gym123", exercise)
+
+	// The checker error is returned before the repository is reached
+	assert.Nil(t, id)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, errorcode_enum.CodeConflict, apiErr.Code)
+}
+
+func TestCreateCustomWorkoutExercise_EquipmentWarnings(t *testing.T) {
+	mockRepo := &mockRepository{lastCreatedID: "exercise123"}
+	equipment := &mockEquipmentChecker{
+		warnings: []*inventory_dto.EquipmentWarning{
+			{ExerciseSource: "public", ExerciseID: "exercise789", EquipmentSource: "public", EquipmentID: "rack1", EquipmentName: "Squat Rack", Status: "out_of_order"},
+		},
+	}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, equipment)
+
+	exercise := &dto.CreateCustomWorkoutExerciseDTO{
+		CreatedBy:         "user123",
+		WorkoutInstanceID: "workout456",
+		ExerciseSource:    "public",
+		PublicExerciseID:  stringPtr("exercise789"),
+		BlockName:         "main",
+		ExerciseOrder:     1,
+	}
+
+	id, warnings, err := svc.CreateCustomWorkoutExercise("gym123", exercise)
+
+	// Broken equipment warns but never blocks the assignment
+	assert.NoError(t, err)
+	assert.Equal(t, "exercise123", *id)
+	assert.Empty(t, warnings.Contraindications)
+	if assert.Len(t, warnings.Equipment, 1) {
+		assert.Equal(t, "out_of_order", warnings.Equipment[0].Status)
+	}
+	assert.Equal(t, "public", equipment.checkedSource)
+	assert.Equal(t, "exercise789", equipment.checkedID)
+}
+
+func groupedExercise(id, block, groupID, groupType string, order, rounds int) *dto.ResponseCustomWorkoutExerciseDTO {
+	return &dto.ResponseCustomWorkoutExerciseDTO{
+		ID:                id,
+		WorkoutInstanceID: "workout456",
+		BlockName:         block,
+		ExerciseOrder:     order,
+		ExerciseGrouping:  dto.ExerciseGrouping{GroupID: stringPtr(groupID), GroupType: stringPtr(groupType), GroupRounds: intPtr(rounds)},
+	}
+}
+
+func TestCreateCustomWorkoutExercise_JoinsGroup(t *testing.T) {
+	mockRepo := &mockRepository{
+		lastCreatedID: "exercise123",
+		exercises:     []*dto.ResponseCustomWorkoutExerciseDTO{groupedExercise("a1", "main", "A", "circuit", 1, 3)},
+	}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
+
+	exercise := &dto.CreateCustomWorkoutExerciseDTO{
+		CreatedBy:         "user123",
+		WorkoutInstanceID: "workout456",
+		ExerciseSource:    "public",
+		PublicExerciseID:  stringPtr("exercise789"),
+		BlockName:         "main",
+		ExerciseOrder:     2,
+		ExerciseGrouping:  dto.ExerciseGrouping{GroupID: stringPtr("A")},
+	}
+
+	_, _, err := svc.CreateCustomWorkoutExercise("gym123", exercise)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "circuit", *mockRepo.lastCreated.GroupType)
+	assert.Equal(t, 3, *mockRepo.lastCreated.GroupRounds)
+}
+
+func TestCreateCustomWorkoutExercise_GroupErrors(t *testing.T) {
+	existing := []*dto.ResponseCustomWorkoutExerciseDTO{
+		groupedExercise("a1", "main", "A", "superset", 1, 3),
+		groupedExercise("a2", "main", "A", "superset", 2, 3),
+		groupedExercise("b1", "finisher", "B", "circuit", 1, 4),
+	}
+	tests := []struct {
+		name     string
+		grouping dto.ExerciseGrouping
+		code     string
+	}{
+		{"fields without group", dto.ExerciseGrouping{GroupRounds: intPtr(3)}, errorcode_enum.CodeBadRequest},
+		{"new group without type", dto.ExerciseGrouping{GroupID: stringPtr("C")}, errorcode_enum.CodeBadRequest},
+		{"unknown type", dto.ExerciseGrouping{GroupID: stringPtr("C"), GroupType: stringPtr("tabata")}, errorcode_enum.CodeBadRequest},
+		{"zero rounds", dto.ExerciseGrouping{GroupID: stringPtr("C"), GroupType: stringPtr("circuit"), GroupRounds: intPtr(0)}, errorcode_enum.CodeBadRequest},
+		{"full superset", dto.ExerciseGrouping{GroupID: stringPtr("A")}, errorcode_enum.CodeConflict},
+		{"group of another block", dto.ExerciseGrouping{GroupID: stringPtr("B")}, errorcode_enum.CodeConflict},
+		{"different rounds", dto.ExerciseGrouping{GroupID: stringPtr("A"), GroupRounds: intPtr(5)}, errorcode_enum.CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewCustomWorkoutExerciseService(&mockRepository{exercises: existing}, nil, nil)
+			exercise := &dto.CreateCustomWorkoutExerciseDTO{
+				CreatedBy:         "user123",
+				WorkoutInstanceID: "workout456",
+				ExerciseSource:    "public",
+				PublicExerciseID:  stringPtr("exercise789"),
+				BlockName:         "main",
+				ExerciseOrder:     5,
+				ExerciseGrouping:  tt.grouping,
+			}
+
+			_, _, err := svc.CreateCustomWorkoutExercise("gym123", exercise)
+
+			apiErr, ok := err.(*apierror.APIError)
+			if assert.True(t, ok) {
+				assert.Equal(t, tt.code, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestUpdateCustomWorkoutExercise_KeepsOwnGroupSlot(t *testing.T) {
+	mockRepo := &mockRepository{
+		exercises: []*dto.ResponseCustomWorkoutExerciseDTO{
+			groupedExercise("a1", "main", "A", "superset", 1, 3),
+			groupedExercise("a2", "main", "A", "superset", 2, 3),
+		},
+	}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
+
+	err := svc.UpdateCustomWorkoutExercise("gym123", &dto.UpdateCustomWorkoutExerciseDTO{
+		ID:               "a2",
+		Sets:             intPtr(4),
+		ExerciseGrouping: dto.ExerciseGrouping{GroupID: stringPtr("A")},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateCustomWorkoutExercise_ChangesTheWholeGroup(t *testing.T) {
+	mockRepo := &mockRepository{
+		exercises: []*dto.ResponseCustomWorkoutExerciseDTO{
+			groupedExercise("a1", "main", "A", "giant_set", 1, 3),
+			groupedExercise("a2", "main", "A", "giant_set", 2, 3),
+			groupedExercise("a3", "main", "A", "giant_set", 3, 3),
+		},
+	}
+	svc := service.NewCustomWorkoutExerciseService(mockRepo, nil, nil)
+
+	err := svc.UpdateCustomWorkoutExercise("gym123", &dto.UpdateCustomWorkoutExerciseDTO{
+		ID:               "a2",
+		ExerciseGrouping: dto.ExerciseGrouping{GroupID: stringPtr("A"), GroupRounds: intPtr(5)},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "giant_set", *mockRepo.lastUpdated.GroupType)
+	assert.Equal(t, 5, *mockRepo.lastUpdated.GroupRounds)
+
+	// Turning the group into a superset would leave it over its limit
+	err = svc.UpdateCustomWorkoutExercise("gym123", &dto.UpdateCustomWorkoutExerciseDTO{
+		ID:               "a2",
+		ExerciseGrouping: dto.ExerciseGrouping{GroupID: stringPtr("A"), GroupType: stringPtr("superset")},
+	})
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+}
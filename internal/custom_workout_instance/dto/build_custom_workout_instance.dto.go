@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+)
+
+// BuildCustomWorkoutInstanceDTO asks for a draft instance laid out from the template's blocks
+type BuildCustomWorkoutInstanceDTO struct {
+	CreateCustomWorkoutInstanceDTO
+	AutoPick bool `json:"auto_pick"` // pick exercises for every block, otherwise every slot is a placeholder
+}
+
+// WorkoutInstanceDraftDTO is a built instance for the trainer to review; nothing is saved until it is sent back
+type WorkoutInstanceDraftDTO struct {
+	CreateCustomWorkoutInstanceDTO
+	Blocks []DraftBlockDTO `json:"blocks"`
+}
+
+type DraftBlockDTO struct {
+	BlockName    string             `json:"block_name"`
+	BlockType    string             `json:"block_type"`
+	BlockOrder   int                `json:"block_order"`
+	Instructions *string            `json:"instructions,omitempty"`
+	Exercises    []DraftExerciseDTO `json:"exercises"`
+	templateBlockDTO.BlockTiming
+}
+
+// DraftExerciseDTO is one exercise slot of a block, prefilled with the block defaults
+type DraftExerciseDTO struct {
+	ExerciseOrder    int      `json:"exercise_order"`
+	ExerciseSource   *string  `json:"exercise_source,omitempty"` // empty for a placeholder
+	PublicExerciseID *string  `json:"public_exercise_id,omitempty"`
+	GymExerciseID    *string  `json:"gym_exercise_id,omitempty"`
+	ExerciseName     *string  `json:"exercise_name,omitempty"`
+	Sets             *int     `json:"sets,omitempty"`
+	RepsMin          *int     `json:"reps_min,omitempty"`
+	RepsMax          *int     `json:"reps_max,omitempty"`
+	WeightKg         *float64 `json:"weight_kg,omitempty"`
+	DurationSeconds  *int     `json:"duration_seconds,omitempty"`
+	RestSeconds      *int     `json:"rest_seconds,omitempty"`
+	Notes            *string  `json:"notes,omitempty"`
+	dto.ExerciseGrouping
+}
+
+func (e *DraftExerciseDTO) IsPlaceholder() bool {
+	return e.ExerciseSource == nil || *e.ExerciseSource == ""
+}
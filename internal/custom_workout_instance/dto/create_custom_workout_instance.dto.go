@@ -6,6 +6,7 @@ type CreateCustomWorkoutInstanceDTO struct {
 	TemplateSource   string  `json:"template_source" validate:"required,oneof=public gym"`
 	PublicTemplateID *string `json:"public_template_id,omitempty"`
 	GymTemplateID    *string `json:"gym_template_id,omitempty"`
+	TemplateVersion  *int    `json:"template_version,omitempty"` // published template version to pin, defaults to the latest
 	// Note: DifficultyLevel and EstimatedDurationMinutes will be calculated from exercises
 	// CreatedBy will be extracted from JWT token in the handler
 }
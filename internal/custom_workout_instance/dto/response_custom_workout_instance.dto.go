@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+import (
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+)
 
 type ResponseCustomWorkoutInstanceDTO struct {
 	// Basic Information
@@ -10,6 +13,7 @@ type ResponseCustomWorkoutInstanceDTO struct {
 	TemplateSource   string  `json:"template_source"`
 	PublicTemplateID *string `json:"public_template_id,omitempty"`
 	GymTemplateID    *string `json:"gym_template_id,omitempty"`
+	TemplateVersion  *int    `json:"template_version,omitempty"` // pinned template version, empty for instances created before versioning
 
 	// Calculated Fields (computed from exercises)
 	DifficultyLevel          string `json:"difficulty_level"`           // Calculated from average exercise difficulty
@@ -25,6 +29,12 @@ type ResponseCustomWorkoutInstanceDTO struct {
 	// Workout Statistics
 	WorkoutStats *WorkoutStatsDTO `json:"workout_stats,omitempty"`
 
+	// Supersets, giant sets and circuits of the workout
+	Groups []ExerciseGroupDTO `json:"groups,omitempty"`
+
+	// EMOM, AMRAP, Tabata, For-Time and interval blocks of the pinned template version
+	TimedBlocks []TimedBlockDTO `json:"timed_blocks,omitempty"`
+
 	// Optional: Include exercises for detailed view
 	Exercises []dto.ResponseCustomWorkoutExerciseDTO `json:"exercises,omitempty"`
 
@@ -39,4 +49,24 @@ type WorkoutStatsDTO struct {
 	TotalEstimatedWeight   float64        `json:"total_estimated_weight"`  // Sum of all weights
 	DifficultyBreakdown    map[string]int `json:"difficulty_breakdown"`    // {"beginner": 2, "intermediate": 3}
 	ExerciseTypeBreakdown  map[string]int `json:"exercise_type_breakdown"` // {"strength": 5, "cardio": 2}
+	// Weighted sets per muscular group: sets x activation weight of each exercise link
+	MuscularGroupVolume map[string]float64 `json:"muscular_group_volume"` // {"Chest": 4, "Triceps": 2}
+}
+
+// ExerciseGroupDTO is a superset, giant set or circuit: the exercises of a block sharing a group ID
+type ExerciseGroupDTO struct {
+	GroupID                  string   `json:"group_id"`
+	BlockName                string   `json:"block_name"`
+	GroupType                string   `json:"group_type"`
+	Rounds                   int      `json:"rounds"`
+	RestBetweenRoundsSeconds int      `json:"rest_between_rounds_seconds"`
+	ExerciseIDs              []string `json:"exercise_ids"`               // in exercise order
+	EstimatedDurationSeconds int      `json:"estimated_duration_seconds"` // rounds x (work + rest of each exercise) + rest between rounds
+}
+
+// TimedBlockDTO is a timed block of the workout with the timing of its template block
+type TimedBlockDTO struct {
+	BlockName string `json:"block_name"`
+	templateBlockDTO.BlockTiming
+	EstimatedDurationSeconds int `json:"estimated_duration_seconds"` // 0 for uncapped For-Time blocks, which are timed by their exercises
 }
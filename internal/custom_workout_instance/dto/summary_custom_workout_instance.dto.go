@@ -2,10 +2,11 @@ package dto
 
 type SummaryCustomWorkoutInstanceDTO struct {
 	// Basic Information
-	ID             string `json:"id"`
-	Name           string `json:"name"`
-	Description    string `json:"description"`
-	TemplateSource string `json:"template_source"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	TemplateSource  string `json:"template_source"`
+	TemplateVersion *int   `json:"template_version,omitempty"`
 
 	// Key Metrics
 	DifficultyLevel          string `json:"difficulty_level"`
@@ -6,5 +6,6 @@ type UpdateCustomWorkoutInstanceDTO struct {
 	TemplateSource   *string `json:"template_source,omitempty" validate:"omitempty,oneof=public gym"`
 	PublicTemplateID *string `json:"public_template_id,omitempty"`
 	GymTemplateID    *string `json:"gym_template_id,omitempty"`
+	TemplateVersion  *int    `json:"template_version,omitempty"` // repin to this published version of the template
 	// Note: DifficultyLevel and EstimatedDurationMinutes will be recalculated from exercises
 }
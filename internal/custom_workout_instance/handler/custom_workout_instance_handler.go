@@ -17,10 +17,11 @@ import (
 
 type CustomWorkoutInstanceHandler struct {
 	Service interfaces.CustomWorkoutInstanceService
+	Builder interfaces.WorkoutInstanceBuilderService
 }
 
-func NewCustomWorkoutInstanceHandler(service interfaces.CustomWorkoutInstanceService) *CustomWorkoutInstanceHandler {
-	return &CustomWorkoutInstanceHandler{Service: service}
+func NewCustomWorkoutInstanceHandler(service interfaces.CustomWorkoutInstanceService, builder interfaces.WorkoutInstanceBuilderService) *CustomWorkoutInstanceHandler {
+	return &CustomWorkoutInstanceHandler{Service: service, Builder: builder}
 }
 
 // API: POST /custom-workout-instance
@@ -218,3 +219,62 @@ func (h *CustomWorkoutInstanceHandler) Delete(w http.ResponseWriter, r *http.Req
 	}
 	response.WriteAPISuccess(w, "Workout instance deleted", nil)
 }
+
+// API: POST /custom-workout-instance/build/preview
+func (h *CustomWorkoutInstanceHandler) PreviewBuild(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "gymID")
+	var reqBody dto.BuildCustomWorkoutInstanceDTO
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Malformed JSON", err))
+		return
+	}
+
+	createdBy := middleware.GetUserID(r)
+	if createdBy == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeUnauthorized, "User ID not found in token", nil))
+		return
+	}
+
+	draft, err := h.Builder.PreviewBuild(gymID, createdBy, &reqBody)
+	if err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			response.WriteAPIError(w, apiErr)
+		} else {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeInternal, "Unknown error", err))
+		}
+		return
+	}
+	response.WriteAPISuccess(w, "Workout instance draft built", draft)
+}
+
+// API: POST /custom-workout-instance/build
+func (h *CustomWorkoutInstanceHandler) SaveBuild(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "gymID")
+	var reqBody dto.WorkoutInstanceDraftDTO
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Malformed JSON", err))
+		return
+	}
+
+	createdBy := middleware.GetUserID(r)
+	if createdBy == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeUnauthorized, "User ID not found in token", nil))
+		return
+	}
+
+	id, warnings, err := h.Builder.SaveBuild(gymID, createdBy, &reqBody)
+	if err != nil {
+		if apiErr, ok := err.(*apierror.APIError); ok {
+			response.WriteAPIError(w, apiErr)
+		} else {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeInternal, "Unknown error", err))
+		}
+		return
+	}
+
+	message := "Workout instance built"
+	if !warnings.Empty() {
+		message = "Workout instance built with warnings"
+	}
+	response.WriteAPICreatedWithDetails(w, message, id, warnings.Details())
+}
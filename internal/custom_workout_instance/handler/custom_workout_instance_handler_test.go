@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	workoutExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/handler"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
@@ -72,7 +73,7 @@ func (m *mockService) DeleteCustomWorkoutInstance(gymID, id string) error {
 
 func setupRouter(service *mockService) *chi.Mux {
 	router := chi.NewRouter()
-	handler := handler.NewCustomWorkoutInstanceHandler(service)
+	handler := handler.NewCustomWorkoutInstanceHandler(service, nil)
 
 	// Add URL parameters for testing
 	router.Route("/gym/{gymID}", func(r chi.Router) {
@@ -499,3 +500,83 @@ func TestDelete_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+type mockBuilder struct {
+	previewFunc func(gymID, createdBy string, build *dto.BuildCustomWorkoutInstanceDTO) (*dto.WorkoutInstanceDraftDTO, error)
+	saveFunc    func(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error)
+}
+
+func (m *mockBuilder) PreviewBuild(gymID, createdBy string, build *dto.BuildCustomWorkoutInstanceDTO) (*dto.WorkoutInstanceDraftDTO, error) {
+	return m.previewFunc(gymID, createdBy, build)
+}
+
+func (m *mockBuilder) SaveBuild(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+	return m.saveFunc(gymID, createdBy, draft)
+}
+
+func setupBuildRouter(builder *mockBuilder) *chi.Mux {
+	router := chi.NewRouter()
+	handler := handler.NewCustomWorkoutInstanceHandler(&mockService{}, builder)
+	router.Route("/gym/{gymID}", func(r chi.Router) {
+		r.Post("/custom-workout-instance/build/preview", handler.PreviewBuild)
+		r.Post("/custom-workout-instance/build", handler.SaveBuild)
+	})
+	return router
+}
+
+func TestPreviewBuild_Success(t *testing.T) {
+	builder := &mockBuilder{
+		previewFunc: func(gymID, createdBy string, build *dto.BuildCustomWorkoutInstanceDTO) (*dto.WorkoutInstanceDraftDTO, error) {
+			assert.Equal(t, "gym123", gymID)
+			assert.Equal(t, "user123", createdBy)
+			assert.True(t, build.AutoPick)
+			assert.Equal(t, "template123", *build.GymTemplateID)
+			return &dto.WorkoutInstanceDraftDTO{CreateCustomWorkoutInstanceDTO: build.CreateCustomWorkoutInstanceDTO}, nil
+		},
+	}
+
+	body := `{"name":"Leg Day","template_source":"gym","gym_template_id":"template123","auto_pick":true}`
+	req := httptest.NewRequest("POST", "/gym/gym123/custom-workout-instance/build/preview", bytes.NewReader([]byte(body)))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user123"))
+	w := httptest.NewRecorder()
+
+	setupBuildRouter(builder).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSaveBuild_Success(t *testing.T) {
+	createdID := "instance123"
+	builder := &mockBuilder{
+		saveFunc: func(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+			assert.Len(t, draft.Blocks, 1)
+			return &createdID, &workoutExerciseDTO.CreationWarnings{}, nil
+		},
+	}
+
+	body := `{"name":"Leg Day","template_source":"gym","gym_template_id":"template123","template_version":2,
+		"blocks":[{"block_name":"Main","exercises":[{"exercise_order":1,"exercise_source":"public","public_exercise_id":"squat"}]}]}`
+	req := httptest.NewRequest("POST", "/gym/gym123/custom-workout-instance/build", bytes.NewReader([]byte(body)))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user123"))
+	w := httptest.NewRecorder()
+
+	setupBuildRouter(builder).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestSaveBuild_PlaceholderRejected(t *testing.T) {
+	builder := &mockBuilder{
+		saveFunc: func(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+			return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "Choose an exercise for every placeholder or remove it before saving: Main #1", nil)
+		},
+	}
+
+	body := `{"name":"Leg Day","template_source":"gym","gym_template_id":"template123","blocks":[{"block_name":"Main","exercises":[{"exercise_order":1}]}]}`
+	req := httptest.NewRequest("POST", "/gym/gym123/custom-workout-instance/build", bytes.NewReader([]byte(body)))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "user123"))
+	w := httptest.NewRecorder()
+
+	setupBuildRouter(builder).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+
+// BlockTimingReader gives the timed blocks of a workout instance, keyed by block name,
+// as defined by the template version the instance is pinned to.
+type BlockTimingReader interface {
+	GetBlockTimings(gymID, instanceID string) (map[string]templateBlockDTO.BlockTiming, error)
+}
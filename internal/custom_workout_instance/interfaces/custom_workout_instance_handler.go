@@ -15,4 +15,6 @@ type CustomWorkoutInstanceHandler interface {
 	ListSummaries(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	PreviewBuild(w http.ResponseWriter, r *http.Request)
+	SaveBuild(w http.ResponseWriter, r *http.Request)
 }
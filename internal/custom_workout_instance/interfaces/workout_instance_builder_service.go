@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	workoutExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+)
+
+type WorkoutInstanceBuilderService interface {
+	PreviewBuild(gymID, createdBy string, build *dto.BuildCustomWorkoutInstanceDTO) (*dto.WorkoutInstanceDraftDTO, error)
+	SaveBuild(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error)
+}
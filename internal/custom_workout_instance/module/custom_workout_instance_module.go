@@ -4,15 +4,33 @@ import (
 	"database/sql"
 	"net/http"
 
+	customExerciseModule "github.com/alejandro-albiol/athenai/internal/custom_exercise/module"
+	workoutExerciseModule "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/module"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/handler"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/repository"
-	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/service"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/router"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/service"
+	inventoryModule "github.com/alejandro-albiol/athenai/internal/equipment_inventory/module"
+	exerciseModule "github.com/alejandro-albiol/athenai/internal/exercise/module"
+	templateVersionModule "github.com/alejandro-albiol/athenai/internal/template_version/module"
 )
 
 func NewCustomWorkoutInstanceModule(db *sql.DB) http.Handler {
-	repo := repository.NewCustomWorkoutInstanceRepository(db)
-	service := service.NewCustomWorkoutInstanceService(repo)
-	handler := handler.NewCustomWorkoutInstanceHandler(service)
+	builder := NewWorkoutInstanceBuilderService(db)
+	handler := handler.NewCustomWorkoutInstanceHandler(builder.Instances, builder)
 	return router.NewCustomWorkoutInstanceRouter(handler)
 }
+
+// NewWorkoutInstanceBuilderService builds the instance builder other modules use to generate workouts from templates
+func NewWorkoutInstanceBuilderService(db *sql.DB) *service.WorkoutInstanceBuilderService {
+	templates := templateVersionModule.NewTemplateVersionService(db)
+	instances := service.NewCustomWorkoutInstanceService(repository.NewCustomWorkoutInstanceRepository(db), templates)
+	return service.NewWorkoutInstanceBuilderService(
+		instances,
+		templates,
+		exerciseModule.NewExerciseService(db),
+		customExerciseModule.NewCustomExerciseService(db),
+		inventoryModule.NewEquipmentInventoryService(db),
+		workoutExerciseModule.NewCustomWorkoutExerciseService(db),
+	)
+}
@@ -2,11 +2,15 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	exerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
 	"github.com/lib/pq"
 )
 
@@ -22,8 +26,8 @@ func (r *CustomWorkoutInstanceRepositoryImpl) Create(gymID string, createdBy str
 	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf(`
 		INSERT INTO %s.custom_workout_instance (
-			created_by, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) 
+			created_by, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 		RETURNING id`, schema)
 
 	var id string
@@ -34,6 +38,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) Create(gymID string, createdBy str
 		instance.TemplateSource,
 		instance.PublicTemplateID,
 		instance.GymTemplateID,
+		instance.TemplateVersion,
 	).Scan(&id)
 
 	if err != nil {
@@ -54,9 +59,17 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetByID(gymID, id string) (*dto.Re
 	if err != nil {
 		return nil, err
 	}
+	involvement, err := r.getMuscularInvolvement(gymID, id, exercises)
+	if err != nil {
+		return nil, err
+	}
+	timings, err := r.getBlockTimings(gymID, instance)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate all the dynamic fields
-	r.calculateWorkoutStats(instance, exercises)
+	r.calculateWorkoutStats(instance, exercises, involvement, timings)
 	instance.Exercises = exercises
 
 	return instance, nil
@@ -74,9 +87,17 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetSummaryByID(gymID, id string) (
 	if err != nil {
 		return nil, err
 	}
+	involvement, err := r.getMuscularInvolvement(gymID, id, exercises)
+	if err != nil {
+		return nil, err
+	}
+	timings, err := r.getBlockTimings(gymID, instance)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate stats
-	r.calculateWorkoutStats(instance, exercises)
+	r.calculateWorkoutStats(instance, exercises, involvement, timings)
 
 	// Convert to summary
 	summary := &dto.SummaryCustomWorkoutInstanceDTO{
@@ -84,6 +105,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetSummaryByID(gymID, id string) (
 		Name:                     instance.Name,
 		Description:              instance.Description,
 		TemplateSource:           instance.TemplateSource,
+		TemplateVersion:          instance.TemplateVersion,
 		DifficultyLevel:          instance.DifficultyLevel,
 		EstimatedDurationMinutes: instance.EstimatedDurationMinutes,
 		TotalExercises:           instance.TotalExercises,
@@ -109,7 +131,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetSummaryByID(gymID, id string) (
 func (r *CustomWorkoutInstanceRepositoryImpl) getBasicWorkoutInstance(gymID, id string) (*dto.ResponseCustomWorkoutInstanceDTO, error) {
 	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf(`
-		SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at
+		SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at
 		FROM %s.custom_workout_instance 
 		WHERE id = $1`, schema)
 
@@ -123,6 +145,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getBasicWorkoutInstance(gymID, id
 		&instance.TemplateSource,
 		&instance.PublicTemplateID,
 		&instance.GymTemplateID,
+		&instance.TemplateVersion,
 		&createdAt,
 		&updatedAt,
 	)
@@ -145,7 +168,8 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getWorkoutExercises(gymID, workout
 			cwe.id, cwe.created_by, cwe.workout_instance_id, cwe.exercise_source,
 			cwe.public_exercise_id, cwe.gym_exercise_id, cwe.block_name, cwe.exercise_order,
 			cwe.sets, cwe.reps_min, cwe.reps_max, cwe.weight_kg, cwe.duration_seconds,
-			cwe.rest_seconds, cwe.notes, cwe.created_at, cwe.updated_at
+			cwe.rest_seconds, cwe.notes, cwe.group_id, cwe.group_type, cwe.group_rounds, cwe.group_rest_seconds,
+			cwe.created_at, cwe.updated_at
 		FROM %s.custom_workout_exercise cwe
 		WHERE cwe.workout_instance_id = $1
 		ORDER BY cwe.block_name, cwe.exercise_order`, schema)
@@ -177,6 +201,10 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getWorkoutExercises(gymID, workout
 			&exercise.DurationSeconds,
 			&exercise.RestSeconds,
 			&exercise.Notes,
+			&exercise.GroupID,
+			&exercise.GroupType,
+			&exercise.GroupRounds,
+			&exercise.GroupRestSeconds,
 			&createdAt,
 			&updatedAt,
 		)
@@ -192,8 +220,100 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getWorkoutExercises(gymID, workout
 	return exercises, nil
 }
 
+// muscularInvolvement is one muscular group worked by a workout exercise and the share of each set credited to it
+type muscularInvolvement struct {
+	MuscularGroup    string
+	Role             string
+	ActivationWeight float64
+}
+
+// Helper method to get the weighted muscular group links of every exercise in a workout, keyed by workout exercise ID.
+// Public exercises resolve through public.exercise_muscular_group and gym exercises through the tenant link table.
+func (r *CustomWorkoutInstanceRepositoryImpl) getMuscularInvolvement(gymID, workoutInstanceID string, exercises []exerciseDTO.ResponseCustomWorkoutExerciseDTO) (map[string][]muscularInvolvement, error) {
+	involvement := make(map[string][]muscularInvolvement)
+	if len(exercises) == 0 {
+		return involvement, nil
+	}
+
+	schema := pq.QuoteIdentifier(gymID)
+	query := fmt.Sprintf(`
+		SELECT cwe.id, mg.name, emg.role, emg.activation_weight
+		FROM %s.custom_workout_exercise cwe
+		JOIN public.exercise_muscular_group emg ON emg.exercise_id = cwe.public_exercise_id
+		JOIN public.muscular_group mg ON mg.id = emg.muscular_group_id
+		WHERE cwe.workout_instance_id = $1 AND cwe.exercise_source = 'public'
+		UNION ALL
+		SELECT cwe.id, mg.name, cemg.role, cemg.activation_weight
+		FROM %s.custom_workout_exercise cwe
+		JOIN %s.custom_exercise_muscular_group cemg ON cemg.custom_exercise_id = cwe.gym_exercise_id
+		JOIN public.muscular_group mg ON mg.id = cemg.muscular_group_id
+		WHERE cwe.workout_instance_id = $1 AND cwe.exercise_source = 'gym'`, schema, schema, schema)
+
+	rows, err := r.DB.Query(query, workoutInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutExerciseID string
+		var link muscularInvolvement
+		if err := rows.Scan(&workoutExerciseID, &link.MuscularGroup, &link.Role, &link.ActivationWeight); err != nil {
+			return nil, err
+		}
+		involvement[workoutExerciseID] = append(involvement[workoutExerciseID], link)
+	}
+
+	return involvement, rows.Err()
+}
+
+// GetBlockTimings gives the timed blocks of an instance so sessions can record their results
+func (r *CustomWorkoutInstanceRepositoryImpl) GetBlockTimings(gymID, instanceID string) (map[string]templateBlockDTO.BlockTiming, error) {
+	instance, err := r.getBasicWorkoutInstance(gymID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return r.getBlockTimings(gymID, instance)
+}
+
+// getBlockTimings reads the block timing from the template version the instance is pinned to.
+// Unpinned instances, and versions that no longer exist, have no timed blocks.
+func (r *CustomWorkoutInstanceRepositoryImpl) getBlockTimings(gymID string, instance *dto.ResponseCustomWorkoutInstanceDTO) (map[string]templateBlockDTO.BlockTiming, error) {
+	timings := make(map[string]templateBlockDTO.BlockTiming)
+	if instance.TemplateVersion == nil {
+		return timings, nil
+	}
+	table, templateID := "public.workout_template_version", instance.PublicTemplateID
+	if instance.TemplateSource == "gym" {
+		table, templateID = pq.QuoteIdentifier(gymID)+".custom_workout_template_version", instance.GymTemplateID
+	}
+	if templateID == nil {
+		return timings, nil
+	}
+
+	var content []byte
+	err := r.DB.QueryRow(fmt.Sprintf(`SELECT snapshot FROM %s WHERE template_id = $1 AND version_number = $2`, table),
+		*templateID, *instance.TemplateVersion).Scan(&content)
+	if err == sql.ErrNoRows {
+		return timings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot templateVersionDTO.TemplateSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, err
+	}
+	for _, block := range snapshot.Blocks {
+		if block.Mode().IsTimed() {
+			timings[block.BlockName] = block.BlockTiming
+		}
+	}
+	return timings, nil
+}
+
 // Helper method to calculate workout statistics and populate calculated fields
-func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dto.ResponseCustomWorkoutInstanceDTO, exercises []exerciseDTO.ResponseCustomWorkoutExerciseDTO) {
+func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dto.ResponseCustomWorkoutInstanceDTO, exercises []exerciseDTO.ResponseCustomWorkoutExerciseDTO, involvement map[string][]muscularInvolvement, timings map[string]templateBlockDTO.BlockTiming) {
 	if len(exercises) == 0 {
 		instance.DifficultyLevel = "beginner"
 		instance.EstimatedDurationMinutes = 0
@@ -205,6 +325,23 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 		return
 	}
 
+	// Timed blocks run for their format's duration whatever their exercises say; uncapped For-Time blocks
+	// are still timed by their exercises
+	instance.TimedBlocks = timedBlocks(exercises, timings)
+	timed := make(map[string]bool)
+	for _, block := range instance.TimedBlocks {
+		timed[block.BlockName] = block.EstimatedDurationSeconds > 0
+	}
+
+	// Supersets, giant sets and circuits are timed per group
+	instance.Groups = exerciseGroups(exercises)
+	grouped := make(map[string]*dto.ExerciseGroupDTO)
+	for i := range instance.Groups {
+		for _, id := range instance.Groups[i].ExerciseIDs {
+			grouped[id] = &instance.Groups[i]
+		}
+	}
+
 	// Calculate basic counts
 	instance.TotalExercises = len(exercises)
 	totalSets := 0
@@ -215,15 +352,18 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 
 	// Maps for unique values and counting
 	exerciseTypeMap := make(map[string]int)
-	muscularGroupMap := make(map[string]int)
+	muscularGroupVolume := make(map[string]float64)
 	equipmentMap := make(map[string]bool)
 	difficultyMap := make(map[string]int)
 
 	// Get exercise details from public/gym exercises and calculate stats
 	for _, exercise := range exercises {
-		// Count sets
+		// Count sets; each round of a group is a set of its exercises unless they set their own
+		group := grouped[exercise.ID]
 		if exercise.Sets != nil {
 			totalSets += *exercise.Sets
+		} else if group != nil {
+			totalSets += group.Rounds
 		}
 
 		// Calculate estimated weight
@@ -238,12 +378,24 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 			repCount++
 		}
 
-		// Calculate duration
-		if exercise.DurationSeconds != nil {
-			estimatedDuration += *exercise.DurationSeconds
+		// Credit each set to the worked muscular groups in proportion to their activation weight,
+		// so a bench press counts fully for chest but only partially for triceps
+		sets := 1
+		if exercise.Sets != nil && *exercise.Sets > 0 {
+			sets = *exercise.Sets
 		}
-		if exercise.RestSeconds != nil {
-			estimatedDuration += *exercise.RestSeconds
+		for _, link := range involvement[exercise.ID] {
+			muscularGroupVolume[link.MuscularGroup] += float64(sets) * link.ActivationWeight
+		}
+
+		// Calculate duration; grouped exercises are timed with their group and exercises of timed blocks with their block
+		if group == nil && !timed[exercise.BlockName] {
+			if exercise.DurationSeconds != nil {
+				estimatedDuration += *exercise.DurationSeconds
+			}
+			if exercise.RestSeconds != nil {
+				estimatedDuration += *exercise.RestSeconds
+			}
 		}
 
 		// TODO: Get exercise details from public.exercise or gym.custom_exercise
@@ -261,6 +413,14 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 	}
 
 	instance.TotalSets = totalSets
+	for _, group := range instance.Groups {
+		if !timed[group.BlockName] {
+			estimatedDuration += group.EstimatedDurationSeconds
+		}
+	}
+	for _, block := range instance.TimedBlocks {
+		estimatedDuration += block.EstimatedDurationSeconds
+	}
 
 	// Calculate difficulty level (most common difficulty)
 	maxCount := 0
@@ -281,10 +441,18 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 		instance.ExerciseTypes = append(instance.ExerciseTypes, exerciseType)
 	}
 
-	instance.MuscularGroups = make([]string, 0, len(muscularGroupMap))
-	for muscularGroup := range muscularGroupMap {
+	// Muscular groups ordered by weighted volume, most worked first
+	instance.MuscularGroups = make([]string, 0, len(muscularGroupVolume))
+	for muscularGroup := range muscularGroupVolume {
 		instance.MuscularGroups = append(instance.MuscularGroups, muscularGroup)
 	}
+	sort.Slice(instance.MuscularGroups, func(i, j int) bool {
+		vi, vj := muscularGroupVolume[instance.MuscularGroups[i]], muscularGroupVolume[instance.MuscularGroups[j]]
+		if vi != vj {
+			return vi > vj
+		}
+		return instance.MuscularGroups[i] < instance.MuscularGroups[j]
+	})
 
 	instance.EquipmentNeeded = make([]string, 0, len(equipmentMap))
 	for equipment := range equipmentMap {
@@ -296,6 +464,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 		TotalEstimatedWeight:   totalEstimatedWeight,
 		DifficultyBreakdown:    difficultyMap,
 		ExerciseTypeBreakdown:  exerciseTypeMap,
+		MuscularGroupVolume:    muscularGroupVolume,
 		AverageSetsPerExercise: float64(totalSets) / float64(len(exercises)),
 	}
 
@@ -304,10 +473,75 @@ func (r *CustomWorkoutInstanceRepositoryImpl) calculateWorkoutStats(instance *dt
 	}
 }
 
+// timedBlocks lists the timed blocks of the instance in exercise order
+func timedBlocks(exercises []exerciseDTO.ResponseCustomWorkoutExerciseDTO, timings map[string]templateBlockDTO.BlockTiming) []dto.TimedBlockDTO {
+	blocks := []dto.TimedBlockDTO{}
+	seen := make(map[string]bool)
+	for _, exercise := range exercises {
+		timing, ok := timings[exercise.BlockName]
+		if !ok || seen[exercise.BlockName] {
+			continue
+		}
+		seen[exercise.BlockName] = true
+		blocks = append(blocks, dto.TimedBlockDTO{
+			BlockName:                exercise.BlockName,
+			BlockTiming:              timing,
+			EstimatedDurationSeconds: timing.DurationSeconds(),
+		})
+	}
+	return blocks
+}
+
+// exerciseGroups collects the grouped exercises of each block in exercise order. A group runs for its rounds,
+// or the most sets of its exercises; a round goes through every exercise with its work and rest time, and the
+// group rest is taken between rounds.
+func exerciseGroups(exercises []exerciseDTO.ResponseCustomWorkoutExerciseDTO) []dto.ExerciseGroupDTO {
+	groups := []dto.ExerciseGroupDTO{}
+	positions := make(map[string]int)
+	roundSeconds := make(map[string]int)
+	for _, exercise := range exercises {
+		if !exercise.Grouped() {
+			continue
+		}
+		key := exercise.BlockName + "\x00" + *exercise.GroupID
+		i, ok := positions[key]
+		if !ok {
+			i = len(groups)
+			positions[key] = i
+			group := dto.ExerciseGroupDTO{GroupID: *exercise.GroupID, BlockName: exercise.BlockName, Rounds: 1}
+			if exercise.GroupType != nil {
+				group.GroupType = *exercise.GroupType
+			}
+			if exercise.GroupRestSeconds != nil {
+				group.RestBetweenRoundsSeconds = *exercise.GroupRestSeconds
+			}
+			groups = append(groups, group)
+		}
+		group := &groups[i]
+		group.ExerciseIDs = append(group.ExerciseIDs, exercise.ID)
+		if exercise.GroupRounds != nil {
+			group.Rounds = *exercise.GroupRounds
+		} else if exercise.Sets != nil && *exercise.Sets > group.Rounds {
+			group.Rounds = *exercise.Sets
+		}
+		if exercise.DurationSeconds != nil {
+			roundSeconds[key] += *exercise.DurationSeconds
+		}
+		if exercise.RestSeconds != nil {
+			roundSeconds[key] += *exercise.RestSeconds
+		}
+	}
+	for key, i := range positions {
+		group := &groups[i]
+		group.EstimatedDurationSeconds = group.Rounds*roundSeconds[key] + (group.Rounds-1)*group.RestBetweenRoundsSeconds
+	}
+	return groups
+}
+
 func (r *CustomWorkoutInstanceRepositoryImpl) GetByUserID(gymID, userID string) ([]*dto.ResponseCustomWorkoutInstanceDTO, error) {
 	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf(`
-		SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at
+		SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at
 		FROM %s.custom_workout_instance 
 		WHERE created_by = $1 
 		ORDER BY created_at DESC`, schema)
@@ -328,6 +562,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetSummariesByUserID(gymID, userID
 			Name:                     instance.Name,
 			Description:              instance.Description,
 			TemplateSource:           instance.TemplateSource,
+			TemplateVersion:          instance.TemplateVersion,
 			DifficultyLevel:          instance.DifficultyLevel,
 			EstimatedDurationMinutes: instance.EstimatedDurationMinutes,
 			TotalExercises:           instance.TotalExercises,
@@ -354,7 +589,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetSummariesByUserID(gymID, userID
 func (r *CustomWorkoutInstanceRepositoryImpl) GetLastsByUserID(gymID, userID string, numberOfWorkouts int) ([]*dto.ResponseCustomWorkoutInstanceDTO, error) {
 	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf(`
-		SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at
+		SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at
 		FROM %s.custom_workout_instance 
 		WHERE created_by = $1 
 		ORDER BY created_at DESC 
@@ -366,7 +601,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) GetLastsByUserID(gymID, userID str
 func (r *CustomWorkoutInstanceRepositoryImpl) List(gymID string) ([]*dto.ResponseCustomWorkoutInstanceDTO, error) {
 	schema := pq.QuoteIdentifier(gymID)
 	query := fmt.Sprintf(`
-		SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at
+		SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at
 		FROM %s.custom_workout_instance 
 		ORDER BY created_at DESC`, schema)
 
@@ -386,6 +621,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) ListSummaries(gymID string) ([]*dt
 			Name:                     instance.Name,
 			Description:              instance.Description,
 			TemplateSource:           instance.TemplateSource,
+			TemplateVersion:          instance.TemplateVersion,
 			DifficultyLevel:          instance.DifficultyLevel,
 			EstimatedDurationMinutes: instance.EstimatedDurationMinutes,
 			TotalExercises:           instance.TotalExercises,
@@ -429,6 +665,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getWorkoutInstanceList(gymID, quer
 			&instance.TemplateSource,
 			&instance.PublicTemplateID,
 			&instance.GymTemplateID,
+			&instance.TemplateVersion,
 			&createdAt,
 			&updatedAt,
 		)
@@ -444,8 +681,16 @@ func (r *CustomWorkoutInstanceRepositoryImpl) getWorkoutInstanceList(gymID, quer
 		if err != nil {
 			return nil, err
 		}
+		involvement, err := r.getMuscularInvolvement(gymID, instance.ID, exercises)
+		if err != nil {
+			return nil, err
+		}
+		timings, err := r.getBlockTimings(gymID, instance)
+		if err != nil {
+			return nil, err
+		}
 
-		r.calculateWorkoutStats(instance, exercises)
+		r.calculateWorkoutStats(instance, exercises, involvement, timings)
 		instances = append(instances, instance)
 	}
 
@@ -461,8 +706,9 @@ func (r *CustomWorkoutInstanceRepositoryImpl) Update(gymID, id string, instance
 			template_source = COALESCE($3, template_source),
 			public_template_id = COALESCE($4, public_template_id),
 			gym_template_id = COALESCE($5, gym_template_id),
+			template_version = COALESCE($6, template_version),
 			updated_at = NOW()
-		WHERE id = $6`, schema)
+		WHERE id = $7`, schema)
 
 	result, err := r.DB.Exec(query,
 		instance.Name,
@@ -470,6 +716,7 @@ func (r *CustomWorkoutInstanceRepositoryImpl) Update(gymID, id string, instance
 		instance.TemplateSource,
 		instance.PublicTemplateID,
 		instance.GymTemplateID,
+		instance.TemplateVersion,
 		id,
 	)
 	if err != nil {
@@ -1,7 +1,9 @@
 package repository_test
 
 import (
+	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
@@ -35,6 +37,7 @@ func TestCreateCustomWorkoutInstance(t *testing.T) {
 			instance.TemplateSource,
 			instance.PublicTemplateID,
 			instance.GymTemplateID,
+			instance.TemplateVersion,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("instance123"))
 
@@ -68,6 +71,7 @@ func TestCreateCustomWorkoutInstance_Error(t *testing.T) {
 			instance.TemplateSource,
 			instance.PublicTemplateID,
 			instance.GymTemplateID,
+			instance.TemplateVersion,
 		).
 		WillReturnError(errors.New("database error"))
 
@@ -90,14 +94,14 @@ func TestGetCustomWorkoutInstanceByID(t *testing.T) {
 	// Mock basic workout instance query
 	now := time.Now()
 	basicRows := sqlmock.NewRows([]string{
-		"id", "name", "description", "template_source", "public_template_id", "gym_template_id",
+		"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "template_version",
 		"created_at", "updated_at",
 	}).AddRow(
-		instanceID, "Test Workout", "Description", "gym", nil, "template456",
+		instanceID, "Test Workout", "Description", "gym", nil, "template456", 2,
 		now, now,
 	)
 
-	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at FROM "gym123".custom_workout_instance WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at FROM "gym123".custom_workout_instance WHERE id = \$1`).
 		WithArgs(instanceID).
 		WillReturnRows(basicRows)
 
@@ -106,17 +110,31 @@ func TestGetCustomWorkoutInstanceByID(t *testing.T) {
 	exerciseRows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
-		"rest_seconds", "notes", "created_at", "updated_at",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
 	}).AddRow(
 		"exercise1", "user123", instanceID, "public", "pub_ex1", nil,
 		"main", 1, 3, 8, 12, 50.5, 30,
-		60, "Test notes", now2, now2,
+		60, "Test notes", nil, nil, nil, nil, now2, now2,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_exercise cwe WHERE cwe.workout_instance_id = \$1`).
 		WithArgs(instanceID).
 		WillReturnRows(exerciseRows)
 
+	// Mock muscular involvement query: bench press works chest fully and triceps partially
+	involvementRows := sqlmock.NewRows([]string{"id", "name", "role", "activation_weight"}).
+		AddRow("exercise1", "Triceps", "secondary", 0.5).
+		AddRow("exercise1", "Chest", "primary", 1.0)
+
+	mock.ExpectQuery(`SELECT cwe.id, mg.name, emg.role, emg.activation_weight FROM "gym123".custom_workout_exercise cwe (.+) UNION ALL (.+) JOIN "gym123".custom_exercise_muscular_group cemg`).
+		WithArgs(instanceID).
+		WillReturnRows(involvementRows)
+
+	// The pinned version has no timed blocks
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT snapshot FROM "gym123".custom_workout_template_version WHERE template_id = $1 AND version_number = $2`)).
+		WithArgs("template456", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(`{"name": "Test Workout", "blocks": [{"block_name": "main", "block_mode": "standard"}]}`)))
+
 	instance, err := repo.GetByID(gymID, instanceID)
 
 	assert.NoError(t, err)
@@ -124,6 +142,70 @@ func TestGetCustomWorkoutInstanceByID(t *testing.T) {
 	assert.Equal(t, instanceID, instance.ID)
 	assert.Equal(t, "Test Workout", instance.Name)
 	assert.Equal(t, "gym", instance.TemplateSource)
+	assert.Equal(t, 2, *instance.TemplateVersion)
+	assert.Equal(t, []string{"Chest", "Triceps"}, instance.MuscularGroups, "muscular groups should be ordered by weighted volume")
+	assert.Equal(t, 3.0, instance.WorkoutStats.MuscularGroupVolume["Chest"])
+	assert.Equal(t, 1.5, instance.WorkoutStats.MuscularGroupVolume["Triceps"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCustomWorkoutInstanceByID_GroupedDuration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomWorkoutInstanceRepository(db)
+	gymID := "gym123"
+	instanceID := "instance123"
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_instance WHERE id = \$1`).
+		WithArgs(instanceID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "template_version",
+			"created_at", "updated_at",
+		}).AddRow(instanceID, "Circuit Day", "Description", "gym", nil, "template456", 1, now, now))
+
+	// A 3-round circuit of two timed exercises followed by a straight exercise
+	exerciseRows := sqlmock.NewRows([]string{
+		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
+		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
+	}).
+		AddRow("exercise1", "user123", instanceID, "public", "pub_ex1", nil,
+			"main", 1, nil, nil, nil, nil, 40,
+			15, nil, "A", "circuit", 3, 90, now, now).
+		AddRow("exercise2", "user123", instanceID, "public", "pub_ex2", nil,
+			"main", 2, nil, nil, nil, nil, 30,
+			15, nil, "A", "circuit", 3, 90, now, now).
+		AddRow("exercise3", "user123", instanceID, "public", "pub_ex3", nil,
+			"main", 3, 3, 8, 12, nil, 60,
+			60, nil, nil, nil, nil, nil, now, now)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_exercise cwe WHERE cwe.workout_instance_id = \$1`).
+		WithArgs(instanceID).
+		WillReturnRows(exerciseRows)
+	mock.ExpectQuery(`SELECT cwe.id, mg.name, emg.role, emg.activation_weight FROM "gym123".custom_workout_exercise cwe`).
+		WithArgs(instanceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "activation_weight"}))
+	mock.ExpectQuery(`SELECT snapshot FROM "gym123".custom_workout_template_version`).
+		WithArgs("template456", 1).
+		WillReturnError(sql.ErrNoRows)
+
+	instance, err := repo.GetByID(gymID, instanceID)
+
+	assert.NoError(t, err)
+	if assert.Len(t, instance.Groups, 1) {
+		group := instance.Groups[0]
+		assert.Equal(t, "A", group.GroupID)
+		assert.Equal(t, "circuit", group.GroupType)
+		assert.Equal(t, 3, group.Rounds)
+		assert.Equal(t, []string{"exercise1", "exercise2"}, group.ExerciseIDs)
+		// 3 rounds of (40+15) + (30+15) seconds with 90 seconds between rounds
+		assert.Equal(t, 480, group.EstimatedDurationSeconds)
+	}
+	assert.Equal(t, 9, instance.TotalSets, "each circuit round counts as a set of its exercises")
+	assert.Equal(t, 10, instance.EstimatedDurationMinutes)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -139,14 +221,14 @@ func TestGetCustomWorkoutInstanceSummaryByID(t *testing.T) {
 	// Mock basic workout instance query (same as GetByID)
 	now := time.Now()
 	basicRows := sqlmock.NewRows([]string{
-		"id", "name", "description", "template_source", "public_template_id", "gym_template_id",
+		"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "template_version",
 		"created_at", "updated_at",
 	}).AddRow(
-		instanceID, "Test Workout", "Description", "gym", nil, "template456",
+		instanceID, "Test Workout", "Description", "gym", nil, "template456", 2,
 		now, now,
 	)
 
-	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at FROM "gym123".custom_workout_instance WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at FROM "gym123".custom_workout_instance WHERE id = \$1`).
 		WithArgs(instanceID).
 		WillReturnRows(basicRows)
 
@@ -155,17 +237,28 @@ func TestGetCustomWorkoutInstanceSummaryByID(t *testing.T) {
 	exerciseRows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
-		"rest_seconds", "notes", "created_at", "updated_at",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
 	}).AddRow(
 		"exercise1", "user123", instanceID, "public", "pub_ex1", nil,
 		"main", 1, 3, 8, 12, 50.5, 30,
-		60, "Test notes", now2, now2,
+		60, "Test notes", nil, nil, nil, nil, now2, now2,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_exercise cwe WHERE cwe.workout_instance_id = \$1`).
 		WithArgs(instanceID).
 		WillReturnRows(exerciseRows)
 
+	mock.ExpectQuery(`SELECT cwe.id, mg.name, emg.role, emg.activation_weight FROM "gym123".custom_workout_exercise cwe`).
+		WithArgs(instanceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "activation_weight"}).
+			AddRow("exercise1", "Chest", "primary", 1.0))
+
+	// The pinned version made the main block a 10 minute AMRAP
+	mock.ExpectQuery(`SELECT snapshot FROM "gym123".custom_workout_template_version`).
+		WithArgs("template456", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).
+			AddRow([]byte(`{"name": "Test Workout", "blocks": [{"block_name": "main", "block_mode": "amrap", "time_cap_seconds": 600}]}`)))
+
 	summary, err := repo.GetSummaryByID(gymID, instanceID)
 
 	assert.NoError(t, err)
@@ -173,6 +266,8 @@ func TestGetCustomWorkoutInstanceSummaryByID(t *testing.T) {
 	assert.Equal(t, instanceID, summary.ID)
 	assert.Equal(t, "Test Workout", summary.Name)
 	assert.Equal(t, "gym", summary.TemplateSource)
+	assert.Equal(t, 10, summary.EstimatedDurationMinutes, "the AMRAP time cap replaces the exercise timing")
+	assert.Equal(t, []string{"Chest"}, summary.PrimaryMuscularGroups)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -187,17 +282,17 @@ func TestGetCustomWorkoutInstancesByUserID(t *testing.T) {
 
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "template_source", "public_template_id", "gym_template_id",
+		"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "template_version",
 		"created_at", "updated_at",
 	}).AddRow(
-		"instance1", "Workout 1", "Description 1", "gym", nil, "template1",
+		"instance1", "Workout 1", "Description 1", "gym", nil, "template1", 1,
 		now, now,
 	).AddRow(
-		"instance2", "Workout 2", "Description 2", "public", "template2", nil,
+		"instance2", "Workout 2", "Description 2", "public", "template2", nil, nil,
 		now, now,
 	)
 
-	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at FROM "gym123".custom_workout_instance WHERE created_by = \$1 ORDER BY created_at DESC`).
+	mock.ExpectQuery(`SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at FROM "gym123".custom_workout_instance WHERE created_by = \$1 ORDER BY created_at DESC`).
 		WithArgs(userID).
 		WillReturnRows(rows)
 
@@ -205,31 +300,45 @@ func TestGetCustomWorkoutInstancesByUserID(t *testing.T) {
 	exerciseRows1 := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
-		"rest_seconds", "notes", "created_at", "updated_at",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
 	}).AddRow(
 		"exercise1", "user123", "instance1", "public", "pub_ex1", nil,
 		"main", 1, 3, 8, 12, 50.5, 30,
-		60, "Test notes", now, now,
+		60, "Test notes", nil, nil, nil, nil, now, now,
 	)
 
 	exerciseRows2 := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
-		"rest_seconds", "notes", "created_at", "updated_at",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
 	}).AddRow(
 		"exercise2", "user123", "instance2", "public", "pub_ex2", nil,
 		"main", 1, 3, 8, 12, 50.5, 30,
-		60, "Test notes", now, now,
+		60, "Test notes", nil, nil, nil, nil, now, now,
 	)
 
+	involvementColumns := []string{"id", "name", "role", "activation_weight"}
+
 	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_exercise cwe WHERE cwe.workout_instance_id = \$1`).
 		WithArgs("instance1").
 		WillReturnRows(exerciseRows1)
 
+	mock.ExpectQuery(`SELECT cwe.id, mg.name, emg.role, emg.activation_weight FROM "gym123".custom_workout_exercise cwe`).
+		WithArgs("instance1").
+		WillReturnRows(sqlmock.NewRows(involvementColumns))
+
+	mock.ExpectQuery(`SELECT snapshot FROM "gym123".custom_workout_template_version`).
+		WithArgs("template1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(`{"name": "Workout 1", "blocks": []}`)))
+
 	mock.ExpectQuery(`SELECT (.+) FROM "gym123".custom_workout_exercise cwe WHERE cwe.workout_instance_id = \$1`).
 		WithArgs("instance2").
 		WillReturnRows(exerciseRows2)
 
+	mock.ExpectQuery(`SELECT cwe.id, mg.name, emg.role, emg.activation_weight FROM "gym123".custom_workout_exercise cwe`).
+		WithArgs("instance2").
+		WillReturnRows(sqlmock.NewRows(involvementColumns))
+
 	instances, err := repo.GetByUserID(gymID, userID)
 
 	assert.NoError(t, err)
@@ -251,12 +360,12 @@ func TestGetLastCustomWorkoutInstancesByUserID(t *testing.T) {
 
 	// Mock the main query - note the specific column order
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "created_at", "updated_at",
+		"id", "name", "description", "template_source", "public_template_id", "gym_template_id", "template_version", "created_at", "updated_at",
 	}).AddRow(
-		"instance1", "Workout 1", "Description 1", "gym", nil, "template1", time.Now(), time.Now(),
+		"instance1", "Workout 1", "Description 1", "gym", nil, "template1", nil, time.Now(), time.Now(),
 	)
 
-	expectedSQL := `SELECT id, name, description, template_source, public_template_id, gym_template_id, created_at, updated_at FROM "gym123"\.custom_workout_instance WHERE created_by = \$1 ORDER BY created_at DESC LIMIT \$2`
+	expectedSQL := `SELECT id, name, description, template_source, public_template_id, gym_template_id, template_version, created_at, updated_at FROM "gym123"\.custom_workout_instance WHERE created_by = \$1 ORDER BY created_at DESC LIMIT \$2`
 	mock.ExpectQuery(expectedSQL).
 		WithArgs(userID, count).
 		WillReturnRows(rows)
@@ -265,16 +374,20 @@ func TestGetLastCustomWorkoutInstancesByUserID(t *testing.T) {
 	exerciseRows := sqlmock.NewRows([]string{
 		"id", "created_by", "workout_instance_id", "exercise_source", "public_exercise_id", "gym_exercise_id",
 		"block_name", "exercise_order", "sets", "reps_min", "reps_max", "weight_kg", "duration_seconds",
-		"rest_seconds", "notes", "created_at", "updated_at",
+		"rest_seconds", "notes", "group_id", "group_type", "group_rounds", "group_rest_seconds", "created_at", "updated_at",
 	}).AddRow(
 		"exercise1", "user123", "instance1", "public", "public_ex1", nil,
-		"Block A", 1, 3, 8, 12, 50.0, nil, 60, "Test notes", time.Now(), time.Now(),
+		"Block A", 1, 3, 8, 12, 50.0, nil, 60, "Test notes", nil, nil, nil, nil, time.Now(), time.Now(),
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM "gym123"\.custom_workout_exercise cwe WHERE cwe\.workout_instance_id = \$1`).
 		WithArgs("instance1").
 		WillReturnRows(exerciseRows)
 
+	mock.ExpectQuery(`SELECT cwe\.id, mg\.name, emg\.role, emg\.activation_weight FROM "gym123"\.custom_workout_exercise cwe`).
+		WithArgs("instance1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "activation_weight"}))
+
 	instances, err := repo.GetLastsByUserID(gymID, userID, count)
 
 	assert.NoError(t, err)
@@ -298,8 +411,8 @@ func TestUpdateCustomWorkoutInstance(t *testing.T) {
 		Description: stringPtr("Updated description"),
 	}
 
-	mock.ExpectExec(`UPDATE "gym123"\.custom_workout_instance SET name = COALESCE\(\$1, name\), description = COALESCE\(\$2, description\), template_source = COALESCE\(\$3, template_source\), public_template_id = COALESCE\(\$4, public_template_id\), gym_template_id = COALESCE\(\$5, gym_template_id\), updated_at = NOW\(\) WHERE id = \$6`).
-		WithArgs("Updated Workout", "Updated description", (*string)(nil), (*string)(nil), (*string)(nil), instanceID).
+	mock.ExpectExec(`UPDATE "gym123"\.custom_workout_instance SET name = COALESCE\(\$1, name\), description = COALESCE\(\$2, description\), template_source = COALESCE\(\$3, template_source\), public_template_id = COALESCE\(\$4, public_template_id\), gym_template_id = COALESCE\(\$5, gym_template_id\), template_version = COALESCE\(\$6, template_version\), updated_at = NOW\(\) WHERE id = \$7`).
+		WithArgs("Updated Workout", "Updated description", (*string)(nil), (*string)(nil), (*string)(nil), (*int)(nil), instanceID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.Update(gymID, instanceID, updateData)
@@ -17,6 +17,10 @@ func NewCustomWorkoutInstanceRouter(handler interfaces.CustomWorkoutInstanceHand
 	router.Put("/custom-workout-instance/{id}", handler.Update)
 	router.Delete("/custom-workout-instance/{id}", handler.Delete)
 
+	// Build from template: preview a draft for review, then save the reviewed draft
+	router.Post("/custom-workout-instance/build/preview", handler.PreviewBuild)
+	router.Post("/custom-workout-instance/build", handler.SaveBuild)
+
 	// Summary operations
 	router.Get("/custom-workout-instance/{id}/summary", handler.GetSummaryByID)
 	router.Get("/custom-workout-instance/summaries", handler.ListSummaries)
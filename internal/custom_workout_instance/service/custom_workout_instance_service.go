@@ -5,16 +5,19 @@ import (
 
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
 	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	templateVersionIF "github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 )
 
 type CustomWorkoutInstanceService struct {
-	Repo interfaces.CustomWorkoutInstanceRepository
+	Repo   interfaces.CustomWorkoutInstanceRepository
+	pinner templateVersionIF.VersionPinner
 }
 
-func NewCustomWorkoutInstanceService(repo interfaces.CustomWorkoutInstanceRepository) *CustomWorkoutInstanceService {
-	return &CustomWorkoutInstanceService{Repo: repo}
+// NewCustomWorkoutInstanceService pins instances to template versions through pinner, which may be nil
+func NewCustomWorkoutInstanceService(repo interfaces.CustomWorkoutInstanceRepository, pinner templateVersionIF.VersionPinner) *CustomWorkoutInstanceService {
+	return &CustomWorkoutInstanceService{Repo: repo, pinner: pinner}
 }
 
 func (s *CustomWorkoutInstanceService) CreateCustomWorkoutInstance(gymID, createdBy string, instance *dto.CreateCustomWorkoutInstanceDTO) (*string, error) {
@@ -25,6 +28,13 @@ func (s *CustomWorkoutInstanceService) CreateCustomWorkoutInstance(gymID, create
 	if instance.TemplateSource == "gym" && instance.GymTemplateID == nil {
 		return nil, apierror.New(errorcode_enum.CodeBadRequest, "gym_template_id is required when template_source is 'gym'", nil)
 	}
+	if s.pinner != nil {
+		version, err := s.pinner.PinVersion(gymID, instance.TemplateSource, templateID(instance.TemplateSource, instance.PublicTemplateID, instance.GymTemplateID), instance.TemplateVersion, createdBy)
+		if err != nil {
+			return nil, err
+		}
+		instance.TemplateVersion = &version
+	}
 
 	id, err := s.Repo.Create(gymID, createdBy, instance)
 	if err != nil {
@@ -112,6 +122,9 @@ func (s *CustomWorkoutInstanceService) UpdateCustomWorkoutInstance(gymID, id str
 			return apierror.New(errorcode_enum.CodeBadRequest, "gym_template_id is required when template_source is 'gym'", nil)
 		}
 	}
+	if err := s.repin(gymID, id, instance); err != nil {
+		return err
+	}
 
 	err := s.Repo.Update(gymID, id, instance)
 	if err != nil {
@@ -133,3 +146,40 @@ func (s *CustomWorkoutInstanceService) DeleteCustomWorkoutInstance(gymID, id str
 	}
 	return nil
 }
+
+// repin resolves the template version when the update changes the template or asks for another version
+func (s *CustomWorkoutInstanceService) repin(gymID, id string, instance *dto.UpdateCustomWorkoutInstanceDTO) error {
+	if s.pinner == nil || (instance.TemplateSource == nil && instance.TemplateVersion == nil) {
+		return nil
+	}
+
+	var source, template string
+	if instance.TemplateSource != nil {
+		source = *instance.TemplateSource
+		template = templateID(source, instance.PublicTemplateID, instance.GymTemplateID)
+	} else {
+		existing, err := s.GetCustomWorkoutInstanceByID(gymID, id)
+		if err != nil {
+			return err
+		}
+		source = existing.TemplateSource
+		template = templateID(source, existing.PublicTemplateID, existing.GymTemplateID)
+	}
+
+	version, err := s.pinner.PinVersion(gymID, source, template, instance.TemplateVersion, "")
+	if err != nil {
+		return err
+	}
+	instance.TemplateVersion = &version
+	return nil
+}
+
+func templateID(source string, publicTemplateID, gymTemplateID *string) string {
+	if source == "public" && publicTemplateID != nil {
+		return *publicTemplateID
+	}
+	if gymTemplateID != nil {
+		return *gymTemplateID
+	}
+	return ""
+}
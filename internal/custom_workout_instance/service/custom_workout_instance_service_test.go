@@ -114,7 +114,7 @@ func TestCreateCustomWorkoutInstance_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		lastCreatedID: "instance123",
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	createDTO := &dto.CreateCustomWorkoutInstanceDTO{
 		Name:           "Test Workout",
@@ -131,7 +131,7 @@ func TestCreateCustomWorkoutInstance_Success(t *testing.T) {
 
 func TestCreateCustomWorkoutInstance_ValidationError_EmptyName(t *testing.T) {
 	mockRepo := &mockRepository{}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	createDTO := &dto.CreateCustomWorkoutInstanceDTO{
 		Name:           "", // Empty name should cause validation error
@@ -149,7 +149,7 @@ func TestCreateCustomWorkoutInstance_ValidationError_EmptyName(t *testing.T) {
 
 func TestCreateCustomWorkoutInstance_ValidationError_MissingPublicTemplateID(t *testing.T) {
 	mockRepo := &mockRepository{}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	createDTO := &dto.CreateCustomWorkoutInstanceDTO{
 		Name:           "Test Workout",
@@ -169,7 +169,7 @@ func TestCreateCustomWorkoutInstance_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
 		createErr: apierror.New(errorcode_enum.CodeInternal, "Database error", nil),
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	createDTO := &dto.CreateCustomWorkoutInstanceDTO{
 		Name:           "Test Workout",
@@ -198,7 +198,7 @@ func TestGetCustomWorkoutInstanceByID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		instances: []*dto.ResponseCustomWorkoutInstanceDTO{instance},
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.GetCustomWorkoutInstanceByID("gym123", "instance123")
 
@@ -210,7 +210,7 @@ func TestGetCustomWorkoutInstanceByID_NotFound(t *testing.T) {
 	mockRepo := &mockRepository{
 		getByIDErr: sql.ErrNoRows,
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.GetCustomWorkoutInstanceByID("gym123", "nonexistent")
 
@@ -235,7 +235,7 @@ func TestGetCustomWorkoutInstanceSummaryByID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		summaries: []*dto.SummaryCustomWorkoutInstanceDTO{summary},
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.GetCustomWorkoutInstanceSummaryByID("gym123", "instance123")
 
@@ -260,7 +260,7 @@ func TestGetCustomWorkoutInstancesByUserID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		instances: instances,
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.GetCustomWorkoutInstancesByUserID("gym123", "user123")
 
@@ -280,7 +280,7 @@ func TestGetLastCustomWorkoutInstancesByUserID_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		instances: instances,
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.GetLastCustomWorkoutInstancesByUserID("gym123", "user123", 5)
 
@@ -291,7 +291,7 @@ func TestGetLastCustomWorkoutInstancesByUserID_Success(t *testing.T) {
 
 func TestUpdateCustomWorkoutInstance_Success(t *testing.T) {
 	mockRepo := &mockRepository{}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	updateDTO := &dto.UpdateCustomWorkoutInstanceDTO{
 		Name:        stringPtr("Updated Workout"),
@@ -305,7 +305,7 @@ func TestUpdateCustomWorkoutInstance_Success(t *testing.T) {
 
 func TestUpdateCustomWorkoutInstance_ValidationError_MissingGymTemplateID(t *testing.T) {
 	mockRepo := &mockRepository{}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	updateDTO := &dto.UpdateCustomWorkoutInstanceDTO{
 		TemplateSource: stringPtr("gym"), // gym template source but no gym_template_id
@@ -321,7 +321,7 @@ func TestUpdateCustomWorkoutInstance_ValidationError_MissingGymTemplateID(t *tes
 
 func TestDeleteCustomWorkoutInstance_Success(t *testing.T) {
 	mockRepo := &mockRepository{}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	err := service.DeleteCustomWorkoutInstance("gym123", "instance123")
 
@@ -332,7 +332,7 @@ func TestDeleteCustomWorkoutInstance_RepositoryError(t *testing.T) {
 	mockRepo := &mockRepository{
 		deleteErr: apierror.New(errorcode_enum.CodeInternal, "Database error", nil),
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	err := service.DeleteCustomWorkoutInstance("gym123", "instance123")
 
@@ -356,7 +356,7 @@ func TestListCustomWorkoutInstances_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		instances: instances,
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.ListCustomWorkoutInstances("gym123")
 
@@ -381,7 +381,7 @@ func TestListCustomWorkoutInstanceSummaries_Success(t *testing.T) {
 	mockRepo := &mockRepository{
 		summaries: summaries,
 	}
-	service := service.NewCustomWorkoutInstanceService(mockRepo)
+	service := service.NewCustomWorkoutInstanceService(mockRepo, nil)
 
 	result, err := service.ListCustomWorkoutInstanceSummaries("gym123")
 
@@ -393,3 +393,80 @@ func TestListCustomWorkoutInstanceSummaries_Success(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+type mockPinner struct {
+	version   int
+	err       error
+	source    string
+	template  string
+	requested *int
+}
+
+func (m *mockPinner) PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error) {
+	m.source, m.template, m.requested = source, templateID, requested
+	return m.version, m.err
+}
+
+func TestCreateCustomWorkoutInstance_PinsTemplateVersion(t *testing.T) {
+	pinner := &mockPinner{version: 3}
+	service := service.NewCustomWorkoutInstanceService(&mockRepository{lastCreatedID: "instance123"}, pinner)
+
+	createDTO := &dto.CreateCustomWorkoutInstanceDTO{
+		Name:             "Test Workout",
+		TemplateSource:   "public",
+		PublicTemplateID: stringPtr("template123"),
+	}
+	_, err := service.CreateCustomWorkoutInstance("gym123", "user123", createDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "public", pinner.source)
+	assert.Equal(t, "template123", pinner.template)
+	assert.Equal(t, 3, *createDTO.TemplateVersion)
+}
+
+func TestCreateCustomWorkoutInstance_UnknownTemplateVersion(t *testing.T) {
+	pinner := &mockPinner{err: apierror.New(errorcode_enum.CodeBadRequest, "Template version 9 does not exist", nil)}
+	service := service.NewCustomWorkoutInstanceService(&mockRepository{}, pinner)
+
+	version := 9
+	_, err := service.CreateCustomWorkoutInstance("gym123", "user123", &dto.CreateCustomWorkoutInstanceDTO{
+		Name:            "Test Workout",
+		TemplateSource:  "gym",
+		GymTemplateID:   stringPtr("template123"),
+		TemplateVersion: &version,
+	})
+
+	assert.Error(t, err)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+}
+
+func TestUpdateCustomWorkoutInstance_RepinsExistingTemplate(t *testing.T) {
+	mockRepo := &mockRepository{
+		instances: []*dto.ResponseCustomWorkoutInstanceDTO{
+			{ID: "instance123", TemplateSource: "gym", GymTemplateID: stringPtr("template123")},
+		},
+	}
+	pinner := &mockPinner{version: 2}
+	service := service.NewCustomWorkoutInstanceService(mockRepo, pinner)
+
+	version := 2
+	updateDTO := &dto.UpdateCustomWorkoutInstanceDTO{TemplateVersion: &version}
+	err := service.UpdateCustomWorkoutInstance("gym123", "instance123", updateDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "gym", pinner.source)
+	assert.Equal(t, "template123", pinner.template)
+	assert.Equal(t, 2, *pinner.requested)
+}
+
+func TestUpdateCustomWorkoutInstance_NoTemplateChangeSkipsPinning(t *testing.T) {
+	pinner := &mockPinner{}
+	service := service.NewCustomWorkoutInstanceService(&mockRepository{}, pinner)
+
+	err := service.UpdateCustomWorkoutInstance("gym123", "instance123", &dto.UpdateCustomWorkoutInstanceDTO{Name: stringPtr("Renamed")})
+
+	assert.NoError(t, err)
+	assert.Empty(t, pinner.source)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	customExerciseIF "github.com/alejandro-albiol/athenai/internal/custom_exercise/interfaces"
+	workoutExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	workoutExerciseIF "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	inventoryIF "github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	templateVersionIF "github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+// blockExerciseTypes are the exercise types auto-picked for each block type; custom blocks take any type
+var blockExerciseTypes = map[string][]string{
+	"warmup":   {"flexibility", "functional", "cardio"},
+	"main":     {"strength", "functional"},
+	"core":     {"strength", "functional", "balance"},
+	"cardio":   {"cardio"},
+	"cooldown": {"flexibility", "balance"},
+}
+
+var difficultyRank = map[string]int{"beginner": 1, "intermediate": 2, "advanced": 3}
+
+// candidate is a public or gym exercise the builder may put in a slot
+type candidate struct {
+	source       string
+	id           string
+	name         string
+	exerciseType string
+	difficulty   string
+}
+
+// WorkoutInstanceBuilderService lays a workout instance out from a pinned template version.
+// Previews write nothing except the first version of a never-published template; saving creates the
+// instance and its exercises, and deletes the instance again when an exercise cannot be added.
+type WorkoutInstanceBuilderService struct {
+	Instances        interfaces.CustomWorkoutInstanceService
+	Templates        templateVersionIF.TemplateResolver
+	Exercises        exerciseIF.ExerciseService
+	CustomExercises  customExerciseIF.CustomExerciseService
+	Equipment        inventoryIF.EquipmentAvailabilityChecker
+	WorkoutExercises workoutExerciseIF.CustomWorkoutExerciseService
+}
+
+func NewWorkoutInstanceBuilderService(
+	instances interfaces.CustomWorkoutInstanceService,
+	templates templateVersionIF.TemplateResolver,
+	exercises exerciseIF.ExerciseService,
+	customExercises customExerciseIF.CustomExerciseService,
+	equipment inventoryIF.EquipmentAvailabilityChecker,
+	workoutExercises workoutExerciseIF.CustomWorkoutExerciseService,
+) *WorkoutInstanceBuilderService {
+	return &WorkoutInstanceBuilderService{
+		Instances:        instances,
+		Templates:        templates,
+		Exercises:        exercises,
+		CustomExercises:  customExercises,
+		Equipment:        equipment,
+		WorkoutExercises: workoutExercises,
+	}
+}
+
+func (s *WorkoutInstanceBuilderService) PreviewBuild(gymID, createdBy string, build *dto.BuildCustomWorkoutInstanceDTO) (*dto.WorkoutInstanceDraftDTO, error) {
+	source := build.TemplateSource
+	if source != "public" && source != "gym" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "template_source must be 'public' or 'gym'", nil)
+	}
+	template := templateID(source, build.PublicTemplateID, build.GymTemplateID)
+	if template == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("%s_template_id is required when template_source is '%s'", source, source), nil)
+	}
+
+	versionNumber, err := s.Templates.PinVersion(gymID, source, template, build.TemplateVersion, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.Templates.GetVersion(gymID, source, template, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []candidate
+	if build.AutoPick {
+		if candidates, err = s.candidates(gymID, version.Snapshot.DifficultyLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	draft := &dto.WorkoutInstanceDraftDTO{CreateCustomWorkoutInstanceDTO: build.CreateCustomWorkoutInstanceDTO}
+	draft.TemplateVersion = &versionNumber
+	if draft.Name == "" {
+		draft.Name = version.Snapshot.Name
+	}
+
+	blocks := append([]templateVersionDTO.BlockSnapshot{}, version.Snapshot.Blocks...)
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].BlockOrder < blocks[j].BlockOrder })
+	used := map[string]bool{}
+	for _, block := range blocks {
+		draftBlock := dto.DraftBlockDTO{
+			BlockName:    block.BlockName,
+			BlockType:    block.BlockType,
+			BlockOrder:   block.BlockOrder,
+			Instructions: block.Instructions,
+			Exercises:    []dto.DraftExerciseDTO{},
+			BlockTiming:  block.BlockTiming,
+		}
+		for order := 1; order <= block.ExerciseCount; order++ {
+			slot := dto.DraftExerciseDTO{
+				ExerciseOrder: order,
+				Sets:          block.Series,
+				RepsMin:       block.Reps,
+				RepsMax:       block.Reps,
+				RestSeconds:   block.RestTimeSeconds,
+			}
+			if build.AutoPick {
+				if err := s.pick(gymID, block.BlockType, candidates, used, &slot); err != nil {
+					return nil, err
+				}
+			}
+			draftBlock.Exercises = append(draftBlock.Exercises, slot)
+		}
+		draft.Blocks = append(draft.Blocks, draftBlock)
+	}
+	return draft, nil
+}
+
+func (s *WorkoutInstanceBuilderService) SaveBuild(gymID, createdBy string, draft *dto.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+	var placeholders []string
+	for _, block := range draft.Blocks {
+		for _, slot := range block.Exercises {
+			if slot.IsPlaceholder() {
+				placeholders = append(placeholders, fmt.Sprintf("%s #%d", block.BlockName, slot.ExerciseOrder))
+			}
+		}
+	}
+	if len(placeholders) > 0 {
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest,
+			"Choose an exercise for every placeholder or remove it before saving: "+strings.Join(placeholders, ", "), nil)
+	}
+
+	id, err := s.Instances.CreateCustomWorkoutInstance(gymID, createdBy, &draft.CreateCustomWorkoutInstanceDTO)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings := &workoutExerciseDTO.CreationWarnings{}
+	for _, block := range draft.Blocks {
+		for _, slot := range block.Exercises {
+			_, created, err := s.WorkoutExercises.CreateCustomWorkoutExercise(gymID, &workoutExerciseDTO.CreateCustomWorkoutExerciseDTO{
+				CreatedBy:         createdBy,
+				WorkoutInstanceID: *id,
+				ExerciseSource:    *slot.ExerciseSource,
+				PublicExerciseID:  slot.PublicExerciseID,
+				GymExerciseID:     slot.GymExerciseID,
+				BlockName:         block.BlockName,
+				ExerciseOrder:     slot.ExerciseOrder,
+				Sets:              slot.Sets,
+				RepsMin:           slot.RepsMin,
+				RepsMax:           slot.RepsMax,
+				WeightKg:          slot.WeightKg,
+				DurationSeconds:   slot.DurationSeconds,
+				RestSeconds:       slot.RestSeconds,
+				Notes:             slot.Notes,
+				ExerciseGrouping:  slot.ExerciseGrouping,
+			})
+			if err != nil {
+				// Exercises go with the instance through ON DELETE CASCADE
+				_ = s.Instances.DeleteCustomWorkoutInstance(gymID, *id)
+				return nil, nil, err
+			}
+			if created != nil {
+				warnings.Contraindications = append(warnings.Contraindications, created.Contraindications...)
+				warnings.Equipment = append(warnings.Equipment, created.Equipment...)
+			}
+		}
+	}
+	return id, warnings, nil
+}
+
+// candidates lists the active public exercises as the gym sees them and the gym's own active exercises,
+// no harder than the template, by name. An empty library leaves every slot a placeholder.
+func (s *WorkoutInstanceBuilderService) candidates(gymID, difficulty string) ([]candidate, error) {
+	exercises, err := s.Exercises.GetAllExercises()
+	if apiErr, ok := err.(*apierror.APIError); ok && apiErr.Code == errorcode_enum.CodeNotFound {
+		exercises, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if exercises, err = s.Exercises.MergeGymOverrides(gymID, exercises); err != nil {
+		return nil, err
+	}
+	customExercises, err := s.CustomExercises.ListCustomExercises(gymID)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []candidate
+	for _, exercise := range exercises {
+		all = append(all, candidate{source: "public", id: exercise.ID, name: exercise.Name, exerciseType: exercise.ExerciseType, difficulty: exercise.DifficultyLevel})
+	}
+	for _, exercise := range customExercises {
+		if exercise.IsActive {
+			all = append(all, candidate{source: "gym", id: exercise.ID, name: exercise.Name, exerciseType: exercise.ExerciseType, difficulty: exercise.DifficultyLevel})
+		}
+	}
+
+	maxRank, limited := difficultyRank[difficulty]
+	var candidates []candidate
+	for _, exercise := range all {
+		if limited && difficultyRank[exercise.difficulty] > maxRank {
+			continue
+		}
+		candidates = append(candidates, exercise)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].name < candidates[j].name })
+	return candidates, nil
+}
+
+// pick fills slot with the first unused candidate that suits the block and whose equipment is in service.
+// The slot stays a placeholder when none is left.
+func (s *WorkoutInstanceBuilderService) pick(gymID, blockType string, candidates []candidate, used map[string]bool, slot *dto.DraftExerciseDTO) error {
+	types, restricted := blockExerciseTypes[blockType]
+	for _, exercise := range candidates {
+		key := exercise.source + ":" + exercise.id
+		if used[key] || (restricted && !slices.Contains(types, exercise.exerciseType)) {
+			continue
+		}
+		if s.Equipment != nil {
+			warnings, err := s.Equipment.CheckExerciseEquipment(gymID, exercise.source, exercise.id)
+			if err != nil {
+				return err
+			}
+			if len(warnings) > 0 {
+				continue
+			}
+		}
+
+		used[key] = true
+		source, id, name := exercise.source, exercise.id, exercise.name
+		slot.ExerciseSource, slot.ExerciseName = &source, &name
+		if source == "gym" {
+			slot.GymExerciseID = &id
+		} else {
+			slot.PublicExerciseID = &id
+		}
+		return nil
+	}
+	return nil
+}
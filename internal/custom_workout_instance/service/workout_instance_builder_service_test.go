@@ -0,0 +1,280 @@
+package service_test
+
+import (
+	"testing"
+
+	customExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_exercise/dto"
+	customExerciseIF "github.com/alejandro-albiol/athenai/internal/custom_exercise/interfaces"
+	workoutExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	workoutExerciseIF "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/custom_workout_instance/service"
+	inventoryDTO "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	exerciseDTO "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResolver struct {
+	snapshot templateVersionDTO.TemplateSnapshot
+}
+
+func (m *mockResolver) PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error) {
+	if requested != nil {
+		return *requested, nil
+	}
+	return 2, nil
+}
+
+func (m *mockResolver) GetVersion(gymID, source, templateID string, versionNumber int) (*templateVersionDTO.TemplateVersionDTO, error) {
+	return &templateVersionDTO.TemplateVersionDTO{TemplateSource: source, TemplateID: templateID, VersionNumber: versionNumber, Snapshot: m.snapshot}, nil
+}
+
+type mockCatalog struct {
+	exerciseIF.ExerciseService
+	exercises []*exerciseDTO.ExerciseResponseDTO
+}
+
+func (m *mockCatalog) GetAllExercises() ([]*exerciseDTO.ExerciseResponseDTO, error) {
+	if len(m.exercises) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "No exercises found", nil)
+	}
+	return m.exercises, nil
+}
+
+func (m *mockCatalog) MergeGymOverrides(gymID string, exercises []*exerciseDTO.ExerciseResponseDTO) ([]*exerciseDTO.ExerciseResponseDTO, error) {
+	return exercises, nil
+}
+
+type mockCustomExercises struct {
+	customExerciseIF.CustomExerciseService
+	exercises []*customExerciseDTO.CustomExerciseResponseDTO
+}
+
+func (m *mockCustomExercises) ListCustomExercises(gymID string) ([]*customExerciseDTO.CustomExerciseResponseDTO, error) {
+	return m.exercises, nil
+}
+
+type mockEquipment struct {
+	outOfService map[string]bool
+}
+
+func (m *mockEquipment) ListAvailableEquipment(gymID string) ([]*inventoryDTO.AvailableEquipment, error) {
+	return nil, nil
+}
+
+func (m *mockEquipment) CheckExerciseEquipment(gymID, exerciseSource, exerciseID string) ([]*inventoryDTO.EquipmentWarning, error) {
+	if m.outOfService[exerciseID] {
+		return []*inventoryDTO.EquipmentWarning{{ExerciseID: exerciseID, Status: "out_of_order"}}, nil
+	}
+	return nil, nil
+}
+
+type mockInstances struct {
+	interfaces.CustomWorkoutInstanceService
+	created *dto.CreateCustomWorkoutInstanceDTO
+	deleted string
+}
+
+func (m *mockInstances) CreateCustomWorkoutInstance(gymID, createdBy string, instance *dto.CreateCustomWorkoutInstanceDTO) (*string, error) {
+	m.created = instance
+	id := "instance123"
+	return &id, nil
+}
+
+func (m *mockInstances) DeleteCustomWorkoutInstance(gymID, id string) error {
+	m.deleted = id
+	return nil
+}
+
+type mockWorkoutExercises struct {
+	workoutExerciseIF.CustomWorkoutExerciseService
+	created []*workoutExerciseDTO.CreateCustomWorkoutExerciseDTO
+	failOn  string
+}
+
+func (m *mockWorkoutExercises) CreateCustomWorkoutExercise(gymID string, exercise *workoutExerciseDTO.CreateCustomWorkoutExerciseDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+	if exercise.PublicExerciseID != nil && *exercise.PublicExerciseID == m.failOn {
+		return nil, nil, apierror.New(errorcode_enum.CodeConflict, "Exercise order already exists", nil)
+	}
+	m.created = append(m.created, exercise)
+	id := "exercise"
+	return &id, &workoutExerciseDTO.CreationWarnings{}, nil
+}
+
+func intPtr(value int) *int { return &value }
+
+func newBuilder(resolver *mockResolver, equipment *mockEquipment, instances *mockInstances, exercises *mockWorkoutExercises) *service.WorkoutInstanceBuilderService {
+	return service.NewWorkoutInstanceBuilderService(instances, resolver, publicLibrary(), &mockCustomExercises{}, equipment, exercises)
+}
+
+func publicLibrary() *mockCatalog {
+	return &mockCatalog{exercises: []*exerciseDTO.ExerciseResponseDTO{
+		{ID: "squat", Name: "Back Squat", ExerciseType: "strength", DifficultyLevel: "intermediate"},
+		{ID: "deadlift", Name: "Deadlift", ExerciseType: "strength", DifficultyLevel: "advanced"},
+		{ID: "lunge", Name: "Lunge", ExerciseType: "functional", DifficultyLevel: "beginner"},
+		{ID: "stretch", Name: "Hamstring Stretch", ExerciseType: "flexibility", DifficultyLevel: "beginner"},
+		{ID: "press", Name: "Leg Press", ExerciseType: "strength", DifficultyLevel: "beginner"},
+	}}
+}
+
+func legDay() *mockResolver {
+	return &mockResolver{snapshot: templateVersionDTO.TemplateSnapshot{
+		Name:            "Leg Day",
+		DifficultyLevel: "intermediate",
+		Blocks: []templateVersionDTO.BlockSnapshot{
+			{BlockName: "Main", BlockType: "main", BlockOrder: 2, ExerciseCount: 4, Reps: intPtr(8), Series: intPtr(4), RestTimeSeconds: intPtr(90)},
+			{BlockName: "Warmup", BlockType: "warmup", BlockOrder: 1, ExerciseCount: 1},
+		},
+	}}
+}
+
+func TestPreviewBuild_AutoPicksByBlock(t *testing.T) {
+	builder := newBuilder(legDay(), &mockEquipment{outOfService: map[string]bool{"press": true}}, &mockInstances{}, &mockWorkoutExercises{})
+
+	draft, err := builder.PreviewBuild("gym123", "trainer1", &dto.BuildCustomWorkoutInstanceDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{TemplateSource: "public", PublicTemplateID: stringPtr("template123")},
+		AutoPick:                       true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Leg Day", draft.Name)
+	assert.Equal(t, 2, *draft.TemplateVersion)
+	require.Len(t, draft.Blocks, 2)
+	assert.Equal(t, "Warmup", draft.Blocks[0].BlockName)
+	assert.Equal(t, "stretch", *draft.Blocks[0].Exercises[0].PublicExerciseID)
+
+	main := draft.Blocks[1].Exercises
+	require.Len(t, main, 4)
+	// Deadlift is harder than the template and the leg press is out of order
+	assert.Equal(t, "squat", *main[0].PublicExerciseID)
+	assert.Equal(t, "lunge", *main[1].PublicExerciseID)
+	assert.True(t, main[2].IsPlaceholder())
+	assert.True(t, main[3].IsPlaceholder())
+	assert.Equal(t, 4, *main[3].Sets)
+	assert.Equal(t, 8, *main[3].RepsMin)
+	assert.Equal(t, 90, *main[3].RestSeconds)
+}
+
+func TestPreviewBuild_AutoPicksGymExercises(t *testing.T) {
+	custom := &mockCustomExercises{exercises: []*customExerciseDTO.CustomExerciseResponseDTO{
+		{ID: "sled", Name: "Anchor Sled Push", ExerciseType: "strength", DifficultyLevel: "beginner", IsActive: true},
+		{ID: "retired", Name: "Arm Wrestle", ExerciseType: "strength", DifficultyLevel: "beginner", IsActive: false},
+	}}
+	builder := service.NewWorkoutInstanceBuilderService(&mockInstances{}, legDay(), publicLibrary(), custom, &mockEquipment{}, &mockWorkoutExercises{})
+
+	draft, err := builder.PreviewBuild("gym123", "trainer1", &dto.BuildCustomWorkoutInstanceDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{TemplateSource: "public", PublicTemplateID: stringPtr("template123")},
+		AutoPick:                       true,
+	})
+
+	require.NoError(t, err)
+	first := draft.Blocks[1].Exercises[0]
+	assert.Equal(t, "gym", *first.ExerciseSource)
+	assert.Equal(t, "sled", *first.GymExerciseID)
+	assert.Nil(t, first.PublicExerciseID)
+	assert.Equal(t, "squat", *draft.Blocks[1].Exercises[1].PublicExerciseID)
+}
+
+func TestPreviewBuild_EmptyLibraryLeavesPlaceholders(t *testing.T) {
+	builder := service.NewWorkoutInstanceBuilderService(&mockInstances{}, legDay(), &mockCatalog{}, &mockCustomExercises{}, &mockEquipment{}, &mockWorkoutExercises{})
+
+	draft, err := builder.PreviewBuild("gym123", "trainer1", &dto.BuildCustomWorkoutInstanceDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{TemplateSource: "public", PublicTemplateID: stringPtr("template123")},
+		AutoPick:                       true,
+	})
+
+	require.NoError(t, err)
+	for _, block := range draft.Blocks {
+		for _, slot := range block.Exercises {
+			assert.True(t, slot.IsPlaceholder())
+		}
+	}
+}
+
+func TestPreviewBuild_PlaceholdersWithoutAutoPick(t *testing.T) {
+	builder := newBuilder(legDay(), &mockEquipment{}, &mockInstances{}, &mockWorkoutExercises{})
+
+	draft, err := builder.PreviewBuild("gym123", "trainer1", &dto.BuildCustomWorkoutInstanceDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{Name: "Monday", TemplateSource: "gym", GymTemplateID: stringPtr("template123"), TemplateVersion: intPtr(1)},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Monday", draft.Name)
+	assert.Equal(t, 1, *draft.TemplateVersion)
+	for _, block := range draft.Blocks {
+		for _, slot := range block.Exercises {
+			assert.True(t, slot.IsPlaceholder())
+		}
+	}
+}
+
+func TestPreviewBuild_RequiresTemplate(t *testing.T) {
+	builder := newBuilder(legDay(), &mockEquipment{}, &mockInstances{}, &mockWorkoutExercises{})
+
+	_, err := builder.PreviewBuild("gym123", "trainer1", &dto.BuildCustomWorkoutInstanceDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{TemplateSource: "gym"},
+	})
+
+	assert.Error(t, err)
+	apiErr, ok := err.(*apierror.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+}
+
+func reviewedDraft() *dto.WorkoutInstanceDraftDTO {
+	public := "public"
+	return &dto.WorkoutInstanceDraftDTO{
+		CreateCustomWorkoutInstanceDTO: dto.CreateCustomWorkoutInstanceDTO{Name: "Leg Day", TemplateSource: "public", PublicTemplateID: stringPtr("template123"), TemplateVersion: intPtr(2)},
+		Blocks: []dto.DraftBlockDTO{
+			{BlockName: "Main", Exercises: []dto.DraftExerciseDTO{
+				{ExerciseOrder: 1, ExerciseSource: &public, PublicExerciseID: stringPtr("squat"), Sets: intPtr(4)},
+				{ExerciseOrder: 2, ExerciseSource: &public, PublicExerciseID: stringPtr("lunge")},
+			}},
+		},
+	}
+}
+
+func TestSaveBuild_CreatesInstanceAndExercises(t *testing.T) {
+	instances, exercises := &mockInstances{}, &mockWorkoutExercises{}
+	builder := newBuilder(legDay(), &mockEquipment{}, instances, exercises)
+
+	id, warnings, err := builder.SaveBuild("gym123", "trainer1", reviewedDraft())
+
+	require.NoError(t, err)
+	assert.Equal(t, "instance123", *id)
+	assert.True(t, warnings.Empty())
+	assert.Equal(t, 2, *instances.created.TemplateVersion)
+	require.Len(t, exercises.created, 2)
+	assert.Equal(t, "instance123", exercises.created[0].WorkoutInstanceID)
+	assert.Equal(t, "Main", exercises.created[0].BlockName)
+	assert.Equal(t, "trainer1", exercises.created[1].CreatedBy)
+}
+
+func TestSaveBuild_RejectsPlaceholders(t *testing.T) {
+	instances := &mockInstances{}
+	builder := newBuilder(legDay(), &mockEquipment{}, instances, &mockWorkoutExercises{})
+	draft := reviewedDraft()
+	draft.Blocks[0].Exercises = append(draft.Blocks[0].Exercises, dto.DraftExerciseDTO{ExerciseOrder: 3})
+
+	_, _, err := builder.SaveBuild("gym123", "trainer1", draft)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Main #3")
+	assert.Nil(t, instances.created)
+}
+
+func TestSaveBuild_DeletesInstanceWhenAnExerciseFails(t *testing.T) {
+	instances := &mockInstances{}
+	builder := newBuilder(legDay(), &mockEquipment{}, instances, &mockWorkoutExercises{failOn: "lunge"})
+
+	_, _, err := builder.SaveBuild("gym123", "trainer1", reviewedDraft())
+
+	assert.Error(t, err)
+	assert.Equal(t, "instance123", instances.deleted)
+}
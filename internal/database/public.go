@@ -33,6 +33,7 @@ func CreatePublicTables(db *sql.DB) error {
 		   address TEXT NOT NULL,
 		   phone TEXT NOT NULL,
 		   is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		   contraindication_policy TEXT NOT NULL DEFAULT 'warn' CHECK (contraindication_policy IN ('warn', 'block')),
 		   deleted_at TIMESTAMP WITH TIME ZONE,
 		   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
 		   updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
@@ -42,6 +43,24 @@ func CreatePublicTables(db *sql.DB) error {
 	}
 	fmt.Println("Gym table created successfully")
 
+	// Add contraindication policy to existing gym table if it doesn't exist
+	_, err = db.Exec(`
+		ALTER TABLE public.gym
+		ADD COLUMN IF NOT EXISTS contraindication_policy TEXT NOT NULL DEFAULT 'warn' CHECK (contraindication_policy IN ('warn', 'block'))
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add contraindication_policy column to gym table: %w", err)
+	}
+
+	// Add the IANA timezone schedules of the gym are expressed in
+	_, err = db.Exec(`
+		ALTER TABLE public.gym
+		ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add timezone column to gym table: %w", err)
+	}
+
 	// 3. Muscular group table
 	_, err = db.Exec(`
 	   CREATE TABLE IF NOT EXISTS public.muscular_group (
@@ -100,6 +119,8 @@ func CreatePublicTables(db *sql.DB) error {
 		  CREATE TABLE IF NOT EXISTS public.exercise_muscular_group (
 				  exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
 				  muscular_group_id UUID NOT NULL REFERENCES public.muscular_group(id) ON DELETE RESTRICT,
+				  role TEXT NOT NULL DEFAULT 'primary' CHECK (role IN ('primary', 'secondary', 'stabilizer')),
+				  activation_weight NUMERIC(3,2) NOT NULL DEFAULT 1.00 CHECK (activation_weight > 0 AND activation_weight <= 1),
 				  PRIMARY KEY (exercise_id, muscular_group_id)
 		  )`)
 	if err != nil {
@@ -107,6 +128,70 @@ func CreatePublicTables(db *sql.DB) error {
 	}
 	fmt.Println("Exercise_muscular_group table created successfully")
 
+	// Add involvement columns to existing exercise_muscular_group table if they don't exist
+	_, err = db.Exec(`
+		ALTER TABLE public.exercise_muscular_group
+		ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'primary' CHECK (role IN ('primary', 'secondary', 'stabilizer')),
+		ADD COLUMN IF NOT EXISTS activation_weight NUMERIC(3,2) NOT NULL DEFAULT 1.00 CHECK (activation_weight > 0 AND activation_weight <= 1)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add involvement columns to exercise_muscular_group table: %w", err)
+	}
+
+	// Contraindication and caution tags for exercise <-> special situation
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.exercise_contraindication (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
+				  special_situation TEXT NOT NULL CHECK (special_situation IN ('pregnancy', 'post_partum', 'injury_recovery', 'chronic_condition', 'elderly_population', 'physical_limitation')),
+				  body_region TEXT NOT NULL DEFAULT 'general' CHECK (body_region IN ('general', 'neck', 'shoulder', 'elbow', 'wrist', 'upper_back', 'lower_back', 'abdomen', 'pelvic_floor', 'hip', 'knee', 'ankle')),
+				  severity TEXT NOT NULL CHECK (severity IN ('contraindicated', 'caution')),
+				  notes TEXT,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  UNIQUE (exercise_id, special_situation, body_region)
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create exercise_contraindication table: %w", err)
+	}
+	fmt.Println("Exercise_contraindication table created successfully")
+
+	// Uploaded images and videos of public exercises, the files live in the blob store
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.exercise_media (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
+				  kind TEXT NOT NULL CHECK (kind IN ('image', 'video')),
+				  storage_key TEXT NOT NULL UNIQUE,
+				  thumbnail_key TEXT,
+				  content_type TEXT NOT NULL,
+				  size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+				  original_filename TEXT,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create exercise_media table: %w", err)
+	}
+	fmt.Println("Exercise_media table created successfully")
+
+	// Translations of catalog text; the catalog columns themselves hold the default language (en)
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.translation (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  entity_type TEXT NOT NULL CHECK (entity_type IN ('exercise', 'muscular_group', 'equipment')),
+				  entity_id UUID NOT NULL,
+				  field TEXT NOT NULL,
+				  locale TEXT NOT NULL,
+				  value TEXT NOT NULL,
+				  updated_by UUID,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  UNIQUE (entity_type, entity_id, field, locale)
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create translation table: %w", err)
+	}
+	fmt.Println("Translation table created successfully")
+
 	// 7. Join table for exercise <-> equipment
 	_, err = db.Exec(`
 		  CREATE TABLE IF NOT EXISTS public.exercise_equipment (
@@ -176,6 +261,20 @@ func CreatePublicTables(db *sql.DB) error {
 		return fmt.Errorf("failed to add new columns to template_block table: %w", err)
 	}
 
+	// Timed block formats; work_seconds, interval_rest_seconds, rounds and time_cap_seconds are checked against the mode by the service
+	_, err = db.Exec(`
+		ALTER TABLE public.template_block
+		ADD COLUMN IF NOT EXISTS block_mode TEXT NOT NULL DEFAULT 'standard'
+			CHECK (block_mode IN ('standard', 'emom', 'amrap', 'tabata', 'for_time', 'intervals')),
+		ADD COLUMN IF NOT EXISTS work_seconds INTEGER CHECK (work_seconds > 0),
+		ADD COLUMN IF NOT EXISTS interval_rest_seconds INTEGER CHECK (interval_rest_seconds >= 0),
+		ADD COLUMN IF NOT EXISTS rounds INTEGER CHECK (rounds > 0),
+		ADD COLUMN IF NOT EXISTS time_cap_seconds INTEGER CHECK (time_cap_seconds > 0)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add timing columns to template_block table: %w", err)
+	}
+
 	// 10. Refresh tokens table for JWT refresh token management
 	_, err = db.Exec(`
 	   CREATE TABLE IF NOT EXISTS public.refresh_token (
@@ -195,6 +294,116 @@ func CreatePublicTables(db *sql.DB) error {
 	}
 	fmt.Println("Refresh token table created successfully")
 
+	// Append-only revision history of public catalog rows; snapshots hold the editable fields as JSON
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.revision (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  entity_type TEXT NOT NULL CHECK (entity_type IN ('exercise', 'workout_template', 'template_block')),
+				  entity_id UUID NOT NULL,
+				  revision_number INTEGER NOT NULL CHECK (revision_number > 0),
+				  action TEXT NOT NULL CHECK (action IN ('baseline', 'update', 'restore')),
+				  snapshot JSONB NOT NULL,
+				  changed_fields TEXT[] NOT NULL DEFAULT '{}',
+				  author_id UUID,
+				  restored_from INTEGER,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  UNIQUE (entity_type, entity_id, revision_number)
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create revision table: %w", err)
+	}
+	fmt.Println("Revision table created successfully")
+
+	// Published template versions; each snapshot freezes the template and its blocks and is never changed
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.workout_template_version (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  template_id UUID NOT NULL REFERENCES public.workout_template(id) ON DELETE CASCADE,
+				  version_number INTEGER NOT NULL CHECK (version_number > 0),
+				  snapshot JSONB NOT NULL,
+				  notes TEXT,
+				  published_by UUID,
+				  published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  UNIQUE (template_id, version_number)
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create workout_template_version table: %w", err)
+	}
+	fmt.Println("Workout template version table created successfully")
+
+	// Marketplace listings of gym templates; the snapshot and bundled exercises are copied so imports never read the publishing gym's schema
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.template_listing (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  source_gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+				  source_template_id UUID NOT NULL, -- custom_workout_template in the publishing gym's schema
+				  source_version INTEGER NOT NULL, -- custom_workout_template_version the snapshot was taken from
+				  published_by UUID NOT NULL,
+				  title TEXT NOT NULL,
+				  summary TEXT,
+				  snapshot JSONB NOT NULL,
+				  exercises JSONB NOT NULL DEFAULT '[]', -- sample workout bundled with the template, custom exercises copied in full
+				  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn', 'superseded')),
+				  review_notes TEXT,
+				  reviewed_by UUID REFERENCES public.admin(id),
+				  reviewed_at TIMESTAMP WITH TIME ZONE,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create template_listing table: %w", err)
+	}
+	fmt.Println("Template listing table created successfully")
+
+	// Imports of marketplace listings, one row per imported copy
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.template_listing_import (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  listing_id UUID NOT NULL REFERENCES public.template_listing(id) ON DELETE CASCADE,
+				  gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+				  template_id UUID NOT NULL, -- custom_workout_template created in the importing gym's schema
+				  imported_by UUID NOT NULL,
+				  imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create template_listing_import table: %w", err)
+	}
+	fmt.Println("Template listing import table created successfully")
+
+	// Ratings of marketplace listings, one per gym that imported the listing
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.template_listing_rating (
+				  listing_id UUID NOT NULL REFERENCES public.template_listing(id) ON DELETE CASCADE,
+				  gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+				  rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+				  review TEXT,
+				  rated_by UUID NOT NULL,
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  PRIMARY KEY (listing_id, gym_id)
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create template_listing_rating table: %w", err)
+	}
+	fmt.Println("Template listing rating table created successfully")
+
+	// Calendar feed tokens; the feed URL is the credential, so only its SHA-256 hash is stored
+	_, err = db.Exec(`
+		  CREATE TABLE IF NOT EXISTS public.calendar_feed_token (
+				  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				  token_hash TEXT NOT NULL UNIQUE,
+				  gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+				  user_id UUID NOT NULL, -- user in the gym's schema
+				  scope TEXT NOT NULL CHECK (scope IN ('member', 'trainer')),
+				  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				  last_used_at TIMESTAMP WITH TIME ZONE,
+				  revoked_at TIMESTAMP WITH TIME ZONE
+		  )`)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar_feed_token table: %w", err)
+	}
+	fmt.Println("Calendar feed token table created successfully")
+
 	// 11. Create indexes for refresh_token and template tables
 	_, err = db.Exec(`
 		   CREATE INDEX IF NOT EXISTS idx_refresh_token_token ON public.refresh_token(token);
@@ -205,6 +414,12 @@ func CreatePublicTables(db *sql.DB) error {
 		   CREATE INDEX IF NOT EXISTS idx_workout_template_difficulty ON public.workout_template(difficulty_level);
 		   CREATE INDEX IF NOT EXISTS idx_template_block_template ON public.template_block(template_id);
 		   CREATE INDEX IF NOT EXISTS idx_template_block_order ON public.template_block(template_id, block_order);
+		   CREATE INDEX IF NOT EXISTS idx_exercise_media_exercise ON public.exercise_media(exercise_id);
+		   CREATE INDEX IF NOT EXISTS idx_translation_locale ON public.translation(locale, entity_type);
+		   CREATE INDEX IF NOT EXISTS idx_template_listing_status ON public.template_listing(status);
+		   CREATE INDEX IF NOT EXISTS idx_template_listing_source ON public.template_listing(source_gym_id, source_template_id);
+		   CREATE INDEX IF NOT EXISTS idx_template_listing_import_listing ON public.template_listing_import(listing_id, gym_id);
+		   CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feed_token_active ON public.calendar_feed_token(gym_id, user_id, scope) WHERE revoked_at IS NULL;
 	   `)
 	if err != nil {
 		return fmt.Errorf("failed to create template indexes: %w", err)
@@ -8,6 +8,8 @@ CREATE TABLE IF NOT EXISTS public.gym (
     address TEXT NOT NULL,
     phone TEXT NOT NULL,
     is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    contraindication_policy TEXT NOT NULL DEFAULT 'warn' CHECK (contraindication_policy IN ('warn', 'block')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
     deleted_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
@@ -62,9 +64,34 @@ CREATE TABLE IF NOT EXISTS public.equipment (
 CREATE TABLE IF NOT EXISTS public.exercise_muscular_group (
     exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
     muscular_group_id UUID NOT NULL REFERENCES public.muscular_group(id) ON DELETE RESTRICT,
+    role TEXT NOT NULL DEFAULT 'primary' CHECK (role IN ('primary', 'secondary', 'stabilizer')),
+    activation_weight NUMERIC(3,2) NOT NULL DEFAULT 1.00 CHECK (activation_weight > 0 AND activation_weight <= 1),
     PRIMARY KEY (exercise_id, muscular_group_id)
 );
 
+CREATE TABLE IF NOT EXISTS public.exercise_contraindication (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
+    special_situation TEXT NOT NULL CHECK (special_situation IN ('pregnancy', 'post_partum', 'injury_recovery', 'chronic_condition', 'elderly_population', 'physical_limitation')),
+    body_region TEXT NOT NULL DEFAULT 'general' CHECK (body_region IN ('general', 'neck', 'shoulder', 'elbow', 'wrist', 'upper_back', 'lower_back', 'abdomen', 'pelvic_floor', 'hip', 'knee', 'ankle')),
+    severity TEXT NOT NULL CHECK (severity IN ('contraindicated', 'caution')),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (exercise_id, special_situation, body_region)
+);
+
+CREATE TABLE IF NOT EXISTS public.exercise_media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('image', 'video')),
+    storage_key TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    original_filename TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
 CREATE TABLE IF NOT EXISTS public.exercise_equipment (
     exercise_id UUID NOT NULL REFERENCES public.exercise(id) ON DELETE CASCADE,
     equipment_id UUID NOT NULL REFERENCES public.equipment(id) ON DELETE RESTRICT,
@@ -107,10 +134,102 @@ CREATE TABLE IF NOT EXISTS public.template_block (
     exercise_count INTEGER NOT NULL, -- Number of exercises for this block (e.g., 3 warmup exercises, 5 main exercises)
     estimated_duration_minutes INTEGER, -- Optional estimated time for this block
     instructions TEXT, -- Special instructions for this block type
+    block_mode TEXT NOT NULL DEFAULT 'standard' CHECK (block_mode IN ('standard', 'emom', 'amrap', 'tabata', 'for_time', 'intervals')),
+    work_seconds INTEGER CHECK (work_seconds > 0),
+    interval_rest_seconds INTEGER CHECK (interval_rest_seconds >= 0),
+    rounds INTEGER CHECK (rounds > 0),
+    time_cap_seconds INTEGER CHECK (time_cap_seconds > 0),
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     UNIQUE(template_id, block_order)
 );
 
+CREATE TABLE IF NOT EXISTS public.translation (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('exercise', 'muscular_group', 'equipment')),
+    entity_id UUID NOT NULL,
+    field TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (entity_type, entity_id, field, locale)
+);
+
+CREATE TABLE IF NOT EXISTS public.revision (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('exercise', 'workout_template', 'template_block')),
+    entity_id UUID NOT NULL,
+    revision_number INTEGER NOT NULL CHECK (revision_number > 0),
+    action TEXT NOT NULL CHECK (action IN ('baseline', 'update', 'restore')),
+    snapshot JSONB NOT NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    author_id UUID,
+    restored_from INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (entity_type, entity_id, revision_number)
+);
+
+CREATE TABLE IF NOT EXISTS public.workout_template_version (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES public.workout_template(id) ON DELETE CASCADE,
+    version_number INTEGER NOT NULL CHECK (version_number > 0),
+    snapshot JSONB NOT NULL,
+    notes TEXT,
+    published_by UUID,
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (template_id, version_number)
+);
+
+CREATE TABLE IF NOT EXISTS public.template_listing (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+    source_template_id UUID NOT NULL, -- custom_workout_template in the publishing gym's schema
+    source_version INTEGER NOT NULL, -- custom_workout_template_version the snapshot was taken from
+    published_by UUID NOT NULL,
+    title TEXT NOT NULL,
+    summary TEXT,
+    snapshot JSONB NOT NULL,
+    exercises JSONB NOT NULL DEFAULT '[]', -- sample workout bundled with the template, custom exercises copied in full
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn', 'superseded')),
+    review_notes TEXT,
+    reviewed_by UUID REFERENCES public.admin(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.template_listing_import (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES public.template_listing(id) ON DELETE CASCADE,
+    gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+    template_id UUID NOT NULL, -- custom_workout_template created in the importing gym's schema
+    imported_by UUID NOT NULL,
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.template_listing_rating (
+    listing_id UUID NOT NULL REFERENCES public.template_listing(id) ON DELETE CASCADE,
+    gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT,
+    rated_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (listing_id, gym_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.calendar_feed_token (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash TEXT NOT NULL UNIQUE,
+    gym_id UUID NOT NULL REFERENCES public.gym(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL, -- user in the gym's schema
+    scope TEXT NOT NULL CHECK (scope IN ('member', 'trainer')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
 -- Indexes for refresh_token
 CREATE INDEX IF NOT EXISTS idx_refresh_token_token ON public.refresh_token(token);
 CREATE INDEX IF NOT EXISTS idx_refresh_token_user ON public.refresh_token(user_id, user_type);
@@ -120,3 +239,9 @@ CREATE INDEX IF NOT EXISTS idx_workout_template_public ON public.workout_templat
 CREATE INDEX IF NOT EXISTS idx_workout_template_difficulty ON public.workout_template(difficulty_level);
 CREATE INDEX IF NOT EXISTS idx_template_block_template ON public.template_block(template_id);
 CREATE INDEX IF NOT EXISTS idx_template_block_order ON public.template_block(template_id, block_order);
+CREATE INDEX IF NOT EXISTS idx_exercise_media_exercise ON public.exercise_media(exercise_id);
+CREATE INDEX IF NOT EXISTS idx_translation_locale ON public.translation(locale, entity_type);
+CREATE INDEX IF NOT EXISTS idx_template_listing_status ON public.template_listing(status);
+CREATE INDEX IF NOT EXISTS idx_template_listing_source ON public.template_listing(source_gym_id, source_template_id);
+CREATE INDEX IF NOT EXISTS idx_template_listing_import_listing ON public.template_listing_import(listing_id, gym_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feed_token_active ON public.calendar_feed_token(gym_id, user_id, scope) WHERE revoked_at IS NULL;
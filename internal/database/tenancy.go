@@ -33,6 +33,7 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			training_phase TEXT CHECK (training_phase IN ('weight_loss', 'muscle_gain', 'cardio_improve', 'maintenance')),
 			motivation TEXT CHECK (motivation IN ('medical_recommendation', 'self_improvement', 'competition', 'rehabilitation', 'wellbeing')),
 			special_situation TEXT CHECK (special_situation IN ('pregnancy', 'post_partum', 'injury_recovery', 'chronic_condition', 'elderly_population', 'physical_limitation', 'none')),
+			preferred_locale TEXT CHECK (preferred_locale IN ('en', 'es')),
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
 		)
@@ -41,6 +42,15 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create user table: %w", err)
 	}
 
+	// Add locale preference to existing user tables if it doesn't exist
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.user
+		ADD COLUMN IF NOT EXISTS preferred_locale TEXT CHECK (preferred_locale IN ('en', 'es'))
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add preferred_locale column to user table: %w", err)
+	}
+
 	// Add more table creation as needed
 
 	// Create custom_exercise table for gym-specific exercises
@@ -70,6 +80,8 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			   CREATE TABLE IF NOT EXISTS %s.custom_exercise_muscular_group (
 					   custom_exercise_id UUID NOT NULL REFERENCES %s.custom_exercise(id) ON DELETE CASCADE,
 					   muscular_group_id UUID NOT NULL REFERENCES public.muscular_group(id) ON DELETE RESTRICT,
+					   role TEXT NOT NULL DEFAULT 'primary' CHECK (role IN ('primary', 'secondary', 'stabilizer')),
+					   activation_weight NUMERIC(3,2) NOT NULL DEFAULT 1.00 CHECK (activation_weight > 0 AND activation_weight <= 1),
 					   PRIMARY KEY (custom_exercise_id, muscular_group_id)
 			   )
 	   `, schema, schema))
@@ -77,16 +89,69 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_exercise_muscular_group table: %w", err)
 	}
 
-	// Create join table for custom_exercise and equipment
+	// Add involvement columns to existing custom_exercise_muscular_group tables if they don't exist
 	_, err = db.Exec(fmt.Sprintf(`
-			   CREATE TABLE IF NOT EXISTS %s.custom_exercise_equipment (
+		ALTER TABLE %s.custom_exercise_muscular_group
+		ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'primary' CHECK (role IN ('primary', 'secondary', 'stabilizer')),
+		ADD COLUMN IF NOT EXISTS activation_weight NUMERIC(3,2) NOT NULL DEFAULT 1.00 CHECK (activation_weight > 0 AND activation_weight <= 1)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add involvement columns to custom_exercise_muscular_group table: %w", err)
+	}
+
+	// Create contraindication tags for custom_exercise and special situations
+	_, err = db.Exec(fmt.Sprintf(`
+			   CREATE TABLE IF NOT EXISTS %s.custom_exercise_contraindication (
+					   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 					   custom_exercise_id UUID NOT NULL REFERENCES %s.custom_exercise(id) ON DELETE CASCADE,
-					   equipment_id UUID NOT NULL REFERENCES public.equipment(id) ON DELETE RESTRICT,
-					   PRIMARY KEY (custom_exercise_id, equipment_id)
+					   special_situation TEXT NOT NULL CHECK (special_situation IN ('pregnancy', 'post_partum', 'injury_recovery', 'chronic_condition', 'elderly_population', 'physical_limitation')),
+					   body_region TEXT NOT NULL DEFAULT 'general' CHECK (body_region IN ('general', 'neck', 'shoulder', 'elbow', 'wrist', 'upper_back', 'lower_back', 'abdomen', 'pelvic_floor', 'hip', 'knee', 'ankle')),
+					   severity TEXT NOT NULL CHECK (severity IN ('contraindicated', 'caution')),
+					   notes TEXT,
+					   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+					   UNIQUE (custom_exercise_id, special_situation, body_region)
 			   )
 	   `, schema, schema))
 	if err != nil {
-		return fmt.Errorf("failed to create custom_exercise_equipment table: %w", err)
+		return fmt.Errorf("failed to create custom_exercise_contraindication table: %w", err)
+	}
+
+	// Create media table for images and videos uploaded to custom_exercise
+	_, err = db.Exec(fmt.Sprintf(`
+			   CREATE TABLE IF NOT EXISTS %s.custom_exercise_media (
+					   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+					   custom_exercise_id UUID NOT NULL REFERENCES %s.custom_exercise(id) ON DELETE CASCADE,
+					   kind TEXT NOT NULL CHECK (kind IN ('image', 'video')),
+					   storage_key TEXT NOT NULL UNIQUE,
+					   thumbnail_key TEXT,
+					   content_type TEXT NOT NULL,
+					   size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+					   original_filename TEXT,
+					   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			   )
+	   `, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_exercise_media table: %w", err)
+	}
+
+	// Create exercise_override table for gym-side changes layered over public.exercise
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.exercise_override (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			public_exercise_id UUID NOT NULL UNIQUE REFERENCES public.exercise(id) ON DELETE CASCADE,
+			instructions TEXT,
+			video_url TEXT,
+			image_url TEXT,
+			difficulty_level TEXT CHECK (difficulty_level IN ('beginner', 'intermediate', 'advanced')),
+			is_hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			upstream_updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_by UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create exercise_override table: %w", err)
 	}
 
 	// Create custom_equipment table for gym-specific equipment
@@ -106,6 +171,84 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_equipment table: %w", err)
 	}
 
+	// Create join table for custom_exercise and equipment from either catalog
+	_, err = db.Exec(fmt.Sprintf(`
+			   CREATE TABLE IF NOT EXISTS %s.custom_exercise_equipment (
+					   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+					   custom_exercise_id UUID NOT NULL REFERENCES %s.custom_exercise(id) ON DELETE CASCADE,
+					   equipment_source TEXT NOT NULL CHECK (equipment_source IN ('public', 'gym')),
+					   public_equipment_id UUID REFERENCES public.equipment(id) ON DELETE RESTRICT,
+					   gym_equipment_id UUID REFERENCES %s.custom_equipment(id) ON DELETE RESTRICT,
+					   created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+					   CHECK (
+							   (equipment_source = 'public' AND public_equipment_id IS NOT NULL AND gym_equipment_id IS NULL) OR
+							   (equipment_source = 'gym' AND gym_equipment_id IS NOT NULL AND public_equipment_id IS NULL)
+					   ),
+					   UNIQUE (custom_exercise_id, public_equipment_id),
+					   UNIQUE (custom_exercise_id, gym_equipment_id)
+			   )
+	   `, schema, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_exercise_equipment table: %w", err)
+	}
+
+	// Create equipment_inventory table for the equipment the gym physically has
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.equipment_inventory (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			equipment_source TEXT NOT NULL CHECK (equipment_source IN ('public', 'gym')),
+			public_equipment_id UUID REFERENCES public.equipment(id) ON DELETE RESTRICT,
+			gym_equipment_id UUID REFERENCES %s.custom_equipment(id) ON DELETE RESTRICT,
+			quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+			zone TEXT,
+			purchase_date DATE,
+			status TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'out_of_order', 'in_maintenance')),
+			notes TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			CHECK (
+				(equipment_source = 'public' AND public_equipment_id IS NOT NULL AND gym_equipment_id IS NULL) OR
+				(equipment_source = 'gym' AND gym_equipment_id IS NOT NULL AND public_equipment_id IS NULL)
+			)
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create equipment_inventory table: %w", err)
+	}
+
+	// Create maintenance tickets for inventory items and their status history
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.equipment_maintenance_ticket (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			inventory_item_id UUID NOT NULL REFERENCES %s.equipment_inventory(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			description TEXT,
+			status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_progress', 'resolved')),
+			item_status TEXT NOT NULL CHECK (item_status IN ('out_of_order', 'in_maintenance')),
+			opened_by UUID NOT NULL,
+			opened_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMP WITH TIME ZONE
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create equipment_maintenance_ticket table: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.equipment_maintenance_event (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			ticket_id UUID NOT NULL REFERENCES %s.equipment_maintenance_ticket(id) ON DELETE CASCADE,
+			from_status TEXT,
+			to_status TEXT NOT NULL,
+			note TEXT,
+			changed_by UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create equipment_maintenance_event table: %w", err)
+	}
+
 	// Create custom_workout_template table for gym-specific templates
 	_, err = db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.custom_workout_template (
@@ -116,6 +259,9 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			difficulty_level TEXT NOT NULL CHECK (difficulty_level IN ('beginner', 'intermediate', 'advanced')),
 			estimated_duration_minutes INTEGER,
 			target_audience TEXT NOT NULL CHECK (target_audience IN ('weight_loss', 'muscle_building', 'endurance', 'strength', 'flexibility', 'general_fitness', 'rehabilitation')),
+			source_template_id UUID REFERENCES public.workout_template(id) ON DELETE SET NULL, -- public template this one was cloned from
+			source_version INTEGER, -- public template version the clone was last synced with
+			source_listing_id UUID REFERENCES public.template_listing(id) ON DELETE SET NULL, -- marketplace listing the template was imported from
 			is_active BOOLEAN NOT NULL DEFAULT TRUE,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
@@ -125,6 +271,17 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_workout_template table: %w", err)
 	}
 
+	// Record the upstream of templates cloned from public ones or imported from the marketplace; existing templates have none
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_workout_template
+		ADD COLUMN IF NOT EXISTS source_template_id UUID REFERENCES public.workout_template(id) ON DELETE SET NULL,
+		ADD COLUMN IF NOT EXISTS source_version INTEGER,
+		ADD COLUMN IF NOT EXISTS source_listing_id UUID REFERENCES public.template_listing(id) ON DELETE SET NULL
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add source columns to custom_workout_template table: %w", err)
+	}
+
 	// Create custom_template_block table for gym-specific templates
 	_, err = db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.custom_template_block (
@@ -140,6 +297,7 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			reps INTEGER,
 			series INTEGER,
 			rest_time_seconds INTEGER,
+			source_block_name TEXT, -- upstream block name for blocks of cloned templates
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			deleted_at TIMESTAMP,
@@ -150,6 +308,45 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to add new columns to custom_template_block table: %w", err)
 	}
 
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_template_block
+		ADD COLUMN IF NOT EXISTS source_block_name TEXT
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add source_block_name column to custom_template_block table: %w", err)
+	}
+
+	// Timed block formats, as on public.template_block
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_template_block
+		ADD COLUMN IF NOT EXISTS block_mode TEXT NOT NULL DEFAULT 'standard'
+			CHECK (block_mode IN ('standard', 'emom', 'amrap', 'tabata', 'for_time', 'intervals')),
+		ADD COLUMN IF NOT EXISTS work_seconds INTEGER CHECK (work_seconds > 0),
+		ADD COLUMN IF NOT EXISTS interval_rest_seconds INTEGER CHECK (interval_rest_seconds >= 0),
+		ADD COLUMN IF NOT EXISTS rounds INTEGER CHECK (rounds > 0),
+		ADD COLUMN IF NOT EXISTS time_cap_seconds INTEGER CHECK (time_cap_seconds > 0)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add timing columns to custom_template_block table: %w", err)
+	}
+
+	// Create custom_workout_template_version table for immutable snapshots of published gym templates
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.custom_workout_template_version (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			template_id UUID NOT NULL REFERENCES %s.custom_workout_template(id) ON DELETE CASCADE,
+			version_number INTEGER NOT NULL CHECK (version_number > 0),
+			snapshot JSONB NOT NULL,
+			notes TEXT,
+			published_by UUID,
+			published_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (template_id, version_number)
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_workout_template_version table: %w", err)
+	}
+
 	// Create custom_workout_instance table for actual workouts created from templates
 	_, err = db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.custom_workout_instance (
@@ -160,6 +357,7 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			template_source TEXT NOT NULL CHECK (template_source IN ('public', 'gym')),
 			public_template_id UUID,
 			gym_template_id UUID,
+			template_version INTEGER,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			CHECK (
@@ -172,6 +370,15 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_workout_instance table: %w", err)
 	}
 
+	// Pin existing workout instances to template versions; instances created before versioning stay unpinned
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_workout_instance
+		ADD COLUMN IF NOT EXISTS template_version INTEGER
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add template_version column to custom_workout_instance table: %w", err)
+	}
+
 	// Create custom_workout_exercise table for exercises assigned to workout instances
 	_, err = db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.custom_workout_exercise (
@@ -203,6 +410,18 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_workout_exercise table: %w", err)
 	}
 
+	// Group workout exercises of a block into supersets, giant sets and circuits done in rounds
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_workout_exercise
+		ADD COLUMN IF NOT EXISTS group_id TEXT,
+		ADD COLUMN IF NOT EXISTS group_type TEXT CHECK (group_type IN ('superset', 'giant_set', 'circuit')),
+		ADD COLUMN IF NOT EXISTS group_rounds INTEGER CHECK (group_rounds > 0),
+		ADD COLUMN IF NOT EXISTS group_rest_seconds INTEGER CHECK (group_rest_seconds >= 0) -- rest between rounds
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add group columns to custom_workout_exercise table: %w", err)
+	}
+
 	// Create custom_member_workout table for member workout sessions
 	_, err = db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.custom_member_workout (
@@ -214,7 +433,7 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 			scheduled_date DATE,
 			started_at TIMESTAMP WITH TIME ZONE,
 			completed_at TIMESTAMP WITH TIME ZONE,
-			status TEXT NOT NULL CHECK (status IN ('scheduled', 'in_progress', 'completed', 'skipped', 'cancelled')),
+			status TEXT NOT NULL CHECK (status IN ('scheduled', 'in_progress', 'paused', 'completed', 'skipped', 'cancelled')),
             
 			notes TEXT,
 			rating INTEGER CHECK (rating >= 1 AND rating <= 5),
@@ -226,16 +445,357 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		return fmt.Errorf("failed to create custom_member_workout table: %w", err)
 	}
 
+	// Create training_program tables for multi-week programs built from workout instances and templates
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.training_program (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			created_by UUID NOT NULL,
+			name TEXT NOT NULL,
+			description TEXT,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create training_program table: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.training_program_week (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			program_id UUID NOT NULL REFERENCES %s.training_program(id) ON DELETE CASCADE,
+			week_number INTEGER NOT NULL CHECK (week_number > 0),
+			is_deload BOOLEAN NOT NULL DEFAULT FALSE,
+			load_change_percent NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (load_change_percent > -100), -- weight change against the base workouts
+			volume_change_percent NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (volume_change_percent > -100), -- sets change against the base workouts
+			notes TEXT,
+			UNIQUE (program_id, week_number)
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create training_program_week table: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.training_program_day (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			program_id UUID NOT NULL REFERENCES %s.training_program(id) ON DELETE CASCADE,
+			week_number INTEGER NOT NULL,
+			day_number INTEGER NOT NULL CHECK (day_number BETWEEN 1 AND 7), -- day of the program week
+			name TEXT NOT NULL,
+			workout_instance_id UUID REFERENCES %s.custom_workout_instance(id) ON DELETE RESTRICT,
+			template_source TEXT CHECK (template_source IN ('public', 'gym')),
+			public_template_id UUID,
+			gym_template_id UUID,
+			template_version INTEGER,
+			CHECK (
+				(workout_instance_id IS NOT NULL AND template_source IS NULL) OR
+				(workout_instance_id IS NULL AND template_source = 'public' AND public_template_id IS NOT NULL AND gym_template_id IS NULL) OR
+				(workout_instance_id IS NULL AND template_source = 'gym' AND gym_template_id IS NOT NULL AND public_template_id IS NULL)
+			),
+			UNIQUE (program_id, week_number, day_number)
+		)
+	`, schema, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create training_program_day table: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.training_program_enrollment (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			program_id UUID NOT NULL REFERENCES %s.training_program(id) ON DELETE RESTRICT,
+			member_id UUID NOT NULL,
+			start_date DATE NOT NULL,
+			status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled')),
+			created_by UUID NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create training_program_enrollment table: %w", err)
+	}
+
+	// Tie member workouts generated by an enrollment to their program week and day
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_member_workout
+		ADD COLUMN IF NOT EXISTS program_enrollment_id UUID REFERENCES %s.training_program_enrollment(id) ON DELETE SET NULL,
+		ADD COLUMN IF NOT EXISTS program_week INTEGER,
+		ADD COLUMN IF NOT EXISTS program_day INTEGER
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add program columns to custom_member_workout table: %w", err)
+	}
+
+	// Allow paused member workouts and track the time spent paused
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_member_workout
+		DROP CONSTRAINT IF EXISTS custom_member_workout_status_check,
+		ADD CONSTRAINT custom_member_workout_status_check CHECK (status IN ('scheduled', 'in_progress', 'paused', 'completed', 'skipped', 'cancelled')),
+		ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP WITH TIME ZONE,
+		ADD COLUMN IF NOT EXISTS paused_seconds INTEGER NOT NULL DEFAULT 0
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add pause columns to custom_member_workout table: %w", err)
+	}
+
+	// Create custom_member_workout_block_result table for the results of timed blocks in a session
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.custom_member_workout_block_result (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			member_workout_id UUID NOT NULL REFERENCES %s.custom_member_workout(id) ON DELETE CASCADE,
+			block_name TEXT NOT NULL,
+			block_mode TEXT NOT NULL CHECK (block_mode IN ('emom', 'amrap', 'tabata', 'for_time', 'intervals')),
+			rounds_completed INTEGER CHECK (rounds_completed >= 0),
+			extra_reps INTEGER CHECK (extra_reps >= 0),
+			finish_time_seconds INTEGER CHECK (finish_time_seconds > 0),
+			notes TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (member_workout_id, block_name)
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_member_workout_block_result table: %w", err)
+	}
+
+	// Create custom_member_workout_set_log table for what members actually did in each set
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.custom_member_workout_set_log (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			member_workout_id UUID NOT NULL REFERENCES %s.custom_member_workout(id) ON DELETE CASCADE,
+			workout_exercise_id UUID NOT NULL REFERENCES %s.custom_workout_exercise(id) ON DELETE CASCADE,
+			set_number INTEGER NOT NULL CHECK (set_number > 0),
+			reps INTEGER CHECK (reps >= 0),
+			weight_kg DECIMAL(6,2) CHECK (weight_kg >= 0),
+			duration_seconds INTEGER CHECK (duration_seconds >= 0),
+			distance_meters DECIMAL(9,2) CHECK (distance_meters >= 0),
+			rpe DECIMAL(3,1) CHECK (rpe BETWEEN 1 AND 10),
+			rir INTEGER CHECK (rir >= 0),
+			completed BOOLEAN NOT NULL DEFAULT TRUE,
+			notes TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (member_workout_id, workout_exercise_id, set_number)
+		)
+	`, schema, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_member_workout_set_log table: %w", err)
+	}
+
+	// Create custom_member_workout_event table, the status transitions of member workouts read by analytics and webhooks
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.custom_member_workout_event (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			seq BIGSERIAL NOT NULL UNIQUE,
+			member_workout_id UUID NOT NULL REFERENCES %s.custom_member_workout(id) ON DELETE CASCADE,
+			member_id UUID NOT NULL,
+			transition TEXT NOT NULL CHECK (transition IN ('start', 'pause', 'resume', 'complete', 'skip', 'cancel')),
+			from_status TEXT NOT NULL,
+			to_status TEXT NOT NULL,
+			note TEXT,
+			changed_by UUID,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create custom_member_workout_event table: %w", err)
+	}
+
+	// Add the skip reason to existing custom_member_workout_event tables
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_member_workout_event
+		ADD COLUMN IF NOT EXISTS reason TEXT CHECK (reason IN ('illness', 'injury', 'fatigue', 'schedule_conflict', 'travel', 'motivation', 'other'))
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add reason column to custom_member_workout_event table: %w", err)
+	}
+
+	// Create workout_schedule tables for recurring member workouts; the rule is expanded into custom_member_workout rows
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.workout_schedule (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			member_id UUID NOT NULL,
+			workout_instance_id UUID NOT NULL REFERENCES %s.custom_workout_instance(id),
+			rrule TEXT NOT NULL,
+			start_date DATE NOT NULL,
+			start_time TIME NOT NULL,
+			timezone TEXT NOT NULL,
+			notes TEXT,
+			status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'ended')),
+			parent_schedule_id UUID REFERENCES %s.workout_schedule(id) ON DELETE SET NULL,
+			created_by UUID NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create workout_schedule table: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.workout_schedule_exception (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			schedule_id UUID NOT NULL REFERENCES %s.workout_schedule(id) ON DELETE CASCADE,
+			occurrence_date DATE NOT NULL,
+			kind TEXT NOT NULL CHECK (kind IN ('cancelled', 'modified')),
+			created_by UUID NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (schedule_id, occurrence_date)
+		)
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create workout_schedule_exception table: %w", err)
+	}
+
+	// Tie member workouts generated by a schedule to the rule date they were expanded from and their start time
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s.custom_member_workout
+		ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES %s.workout_schedule(id) ON DELETE SET NULL,
+		ADD COLUMN IF NOT EXISTS occurrence_date DATE,
+		ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP WITH TIME ZONE
+	`, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to add schedule columns to custom_member_workout table: %w", err)
+	}
+
+	// Create personal_record table, the history of personal records each member set per exercise; a row is kept
+	// for every record set, its first one included, and the current record is the latest of its kind
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.personal_record (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			member_id UUID NOT NULL,
+			exercise_source TEXT NOT NULL CHECK (exercise_source IN ('public', 'gym')),
+			exercise_id UUID NOT NULL, -- public.exercise or custom_exercise depending on exercise_source
+			record_type TEXT NOT NULL CHECK (record_type IN ('max_weight', 'max_reps', 'estimated_1rm', 'max_volume')),
+			value DECIMAL(10,2) NOT NULL,
+			previous_value DECIMAL(10,2),
+			weight_kg DECIMAL(6,2), -- weight of the set; max_reps records are kept per weight
+			reps INTEGER,
+			member_workout_id UUID NOT NULL REFERENCES %s.custom_member_workout(id) ON DELETE CASCADE,
+			set_log_id UUID REFERENCES %s.custom_member_workout_set_log(id) ON DELETE CASCADE, -- NULL for max_volume
+			achieved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema, schema, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create personal_record table: %w", err)
+	}
+
+	// Create progression_rule table, the gym's progressive overload rules per exercise type; types without a row
+	// use the built-in defaults
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.progression_rule (
+			exercise_type TEXT PRIMARY KEY CHECK (exercise_type IN ('strength', 'cardio', 'flexibility', 'balance', 'functional')),
+			strategy TEXT NOT NULL CHECK (strategy IN ('double_progression', 'percentage')),
+			increment_percent DECIMAL(5,2) NOT NULL CHECK (increment_percent > 0),
+			weight_step_kg DECIMAL(5,2) NOT NULL CHECK (weight_step_kg > 0), -- loads are rounded to it and grow by at least it
+			max_rpe DECIMAL(3,1) NOT NULL CHECK (max_rpe BETWEEN 1 AND 10), -- harder sessions hold the load
+			deload_after_misses INTEGER NOT NULL CHECK (deload_after_misses > 0),
+			deload_percent DECIMAL(5,2) NOT NULL CHECK (deload_percent > 0 AND deload_percent < 100),
+			updated_by UUID NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create progression_rule table: %w", err)
+	}
+
+	// Create member_engagement table, the running summary of each member's workouts kept by their status
+	// transitions so adherence and churn risk never scan workout history
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.member_engagement (
+			member_id UUID PRIMARY KEY,
+			completed_count INTEGER NOT NULL DEFAULT 0,
+			skipped_count INTEGER NOT NULL DEFAULT 0,
+			current_streak INTEGER NOT NULL DEFAULT 0,
+			longest_streak INTEGER NOT NULL DEFAULT 0,
+			current_week_streak INTEGER NOT NULL DEFAULT 0,
+			longest_week_streak INTEGER NOT NULL DEFAULT 0,
+			last_completed_week DATE, -- Monday of the latest week with a completed workout, in the gym's timezone
+			skip_reasons JSONB NOT NULL DEFAULT '{}', -- skips per reason
+			last_completed_at TIMESTAMP WITH TIME ZONE,
+			last_activity_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create member_engagement table: %w", err)
+	}
+
+	// Create dashboard aggregates, member workouts by day so the gym dashboard never scans workout history.
+	// Workouts count on their scheduled day, or the day they were created in the gym's timezone when they have
+	// none; created_at is written by NOW() in the session timezone, and the gym's is read on each refresh. The
+	// views are refreshed concurrently by the dashboard, which records when in dashboard_refresh.
+	workoutDay := fmt.Sprintf(`COALESCE(w.scheduled_date, (w.created_at::timestamptz AT TIME ZONE (
+			SELECT COALESCE(NULLIF(g.timezone, ''), 'UTC') FROM public.gym g WHERE g.id = %s))::date)`, pq.QuoteLiteral(*schemaName))
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE MATERIALIZED VIEW IF NOT EXISTS %[1]s.dashboard_workout_daily AS
+		SELECT %[2]s AS day,
+			w.status,
+			w.created_by,
+			i.template_source,
+			COALESCE(i.public_template_id, i.gym_template_id) AS template_id,
+			COUNT(*) AS workouts,
+			COALESCE(SUM(w.rating) FILTER (WHERE w.status = 'completed'), 0) AS rating_sum,
+			COUNT(w.rating) FILTER (WHERE w.status = 'completed') AS rating_count
+		FROM %[1]s.custom_member_workout w
+		JOIN %[1]s.custom_workout_instance i ON i.id = w.workout_instance_id
+		GROUP BY 1, 2, 3, 4, 5
+	`, schema, workoutDay))
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard_workout_daily view: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE MATERIALIZED VIEW IF NOT EXISTS %[1]s.dashboard_exercise_daily AS
+		SELECT %[2]s AS day,
+			e.exercise_source,
+			COALESCE(e.public_exercise_id, e.gym_exercise_id) AS exercise_id,
+			COUNT(DISTINCT w.id) AS workouts,
+			COUNT(s.id) FILTER (WHERE s.completed) AS completed_sets
+		FROM %[1]s.custom_member_workout w
+		JOIN %[1]s.custom_workout_exercise e ON e.workout_instance_id = w.workout_instance_id
+		LEFT JOIN %[1]s.custom_member_workout_set_log s ON s.member_workout_id = w.id AND s.workout_exercise_id = e.id
+		WHERE w.status <> 'cancelled'
+		GROUP BY 1, 2, 3
+	`, schema, workoutDay))
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard_exercise_daily view: %w", err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.dashboard_refresh (
+			id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id), -- a single row
+			refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)
+	`, schema))
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard_refresh table: %w", err)
+	}
+
 	// Create indexes for better performance
 	quoteIdx := func(name string) string { return pq.QuoteIdentifier(name) }
 	indexStmts := []string{
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(is_active);", quoteIdx("idx_"+*schemaName+"_custom_exercise_active"), qt("custom_exercise")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(exercise_type);", quoteIdx("idx_"+*schemaName+"_custom_exercise_type"), qt("custom_exercise")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(difficulty_level);", quoteIdx("idx_"+*schemaName+"_custom_exercise_difficulty"), qt("custom_exercise")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(special_situation);", quoteIdx("idx_"+*schemaName+"_custom_exercise_contraindication_situation"), qt("custom_exercise_contraindication")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(custom_exercise_id);", quoteIdx("idx_"+*schemaName+"_custom_exercise_media_exercise"), qt("custom_exercise_media")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(is_hidden);", quoteIdx("idx_"+*schemaName+"_exercise_override_hidden"), qt("exercise_override")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(is_active);", quoteIdx("idx_"+*schemaName+"_custom_equipment_active"), qt("custom_equipment")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(category);", quoteIdx("idx_"+*schemaName+"_custom_equipment_category"), qt("custom_equipment")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(public_equipment_id);", quoteIdx("idx_"+*schemaName+"_custom_exercise_equipment_public"), qt("custom_exercise_equipment")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(gym_equipment_id);", quoteIdx("idx_"+*schemaName+"_custom_exercise_equipment_gym"), qt("custom_exercise_equipment")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(public_equipment_id);", quoteIdx("idx_"+*schemaName+"_equipment_inventory_public"), qt("equipment_inventory")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(gym_equipment_id);", quoteIdx("idx_"+*schemaName+"_equipment_inventory_gym"), qt("equipment_inventory")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(status);", quoteIdx("idx_"+*schemaName+"_equipment_inventory_status"), qt("equipment_inventory")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(inventory_item_id, status);", quoteIdx("idx_"+*schemaName+"_equipment_maintenance_ticket_item"), qt("equipment_maintenance_ticket")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(ticket_id);", quoteIdx("idx_"+*schemaName+"_equipment_maintenance_event_ticket"), qt("equipment_maintenance_event")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(is_active);", quoteIdx("idx_"+*schemaName+"_custom_workout_template_active"), qt("custom_workout_template")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(difficulty_level);", quoteIdx("idx_"+*schemaName+"_custom_workout_template_difficulty"), qt("custom_workout_template")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(source_template_id);", quoteIdx("idx_"+*schemaName+"_custom_workout_template_source"), qt("custom_workout_template")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(template_id);", quoteIdx("idx_"+*schemaName+"_custom_template_block_template"), qt("custom_template_block")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(template_id, block_order);", quoteIdx("idx_"+*schemaName+"_custom_template_block_order"), qt("custom_template_block")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(created_by);", quoteIdx("idx_"+*schemaName+"_custom_workout_instance_creator"), qt("custom_workout_instance")),
@@ -251,6 +811,22 @@ func CreateTenantSchema(db *sql.DB, schemaName *string) error {
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(workout_instance_id);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_instance"), qt("custom_member_workout")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(status);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_status"), qt("custom_member_workout")),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(scheduled_date);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_date"), qt("custom_member_workout")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(program_enrollment_id);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_enrollment"), qt("custom_member_workout")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(workout_exercise_id);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_set_log_exercise"), qt("custom_member_workout_set_log")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_workout_id);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_event_workout"), qt("custom_member_workout_event")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(created_at);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_event_created"), qt("custom_member_workout_event")),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(schedule_id, occurrence_date);", quoteIdx("idx_"+*schemaName+"_custom_member_workout_occurrence"), qt("custom_member_workout")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(program_id);", quoteIdx("idx_"+*schemaName+"_training_program_enrollment_program"), qt("training_program_enrollment")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_id);", quoteIdx("idx_"+*schemaName+"_training_program_enrollment_member"), qt("training_program_enrollment")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_id);", quoteIdx("idx_"+*schemaName+"_workout_schedule_member"), qt("workout_schedule")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_id, exercise_source, exercise_id);", quoteIdx("idx_"+*schemaName+"_personal_record_exercise"), qt("personal_record")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_workout_id);", quoteIdx("idx_"+*schemaName+"_personal_record_workout"), qt("personal_record")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(member_id, scheduled_date) WHERE status = 'scheduled';", quoteIdx("idx_"+*schemaName+"_custom_member_workout_due"), qt("custom_member_workout")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(last_completed_at);", quoteIdx("idx_"+*schemaName+"_member_engagement_last_completed"), qt("member_engagement")),
+		// Unique indexes let the dashboard views be refreshed concurrently
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(day, status, created_by, template_source, template_id);", quoteIdx("idx_"+*schemaName+"_dashboard_workout_daily_key"), qt("dashboard_workout_daily")),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(day, exercise_source, exercise_id);", quoteIdx("idx_"+*schemaName+"_dashboard_exercise_daily_key"), qt("dashboard_exercise_daily")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(created_at) WHERE role = 'member';", quoteIdx("idx_"+*schemaName+"_user_member_created"), qt("user")),
 	}
 	for _, stmt := range indexStmts {
 		fmt.Printf("[DEBUG] Executing index SQL: %s\n", stmt)
@@ -6,19 +6,24 @@ import (
 
 	"github.com/alejandro-albiol/athenai/internal/equipment/dto"
 	"github.com/alejandro-albiol/athenai/internal/equipment/interfaces"
+	translation_enum "github.com/alejandro-albiol/athenai/internal/translation/enum"
+	translationIF "github.com/alejandro-albiol/athenai/internal/translation/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 	"github.com/go-chi/chi/v5"
 )
 
 type EquipmentHandler struct {
-	service interfaces.EquipmentService
+	service   interfaces.EquipmentService
+	localizer translationIF.Localizer
 }
 
-func NewEquipmentHandler(service interfaces.EquipmentService) *EquipmentHandler {
+func NewEquipmentHandler(service interfaces.EquipmentService, localizer translationIF.Localizer) *EquipmentHandler {
 	return &EquipmentHandler{
-		service: service,
+		service:   service,
+		localizer: localizer,
 	}
 }
 
@@ -54,6 +59,9 @@ func (h *EquipmentHandler) GetEquipment(w http.ResponseWriter, r *http.Request)
 	}
 
 	equipment, err := h.service.GetEquipmentByID(id)
+	if err == nil {
+		err = h.localize(w, r, []*dto.EquipmentResponseDTO{equipment})
+	}
 	if err != nil {
 		response.WriteAPIError(w, err.(*apierror.APIError))
 		return
@@ -64,6 +72,9 @@ func (h *EquipmentHandler) GetEquipment(w http.ResponseWriter, r *http.Request)
 
 func (h *EquipmentHandler) ListEquipment(w http.ResponseWriter, r *http.Request) {
 	equipment, err := h.service.GetAllEquipment()
+	if err == nil {
+		err = h.localize(w, r, equipment)
+	}
 	if err != nil {
 		response.WriteAPIError(w, err.(*apierror.APIError))
 		return
@@ -121,3 +132,25 @@ func (h *EquipmentHandler) DeleteEquipment(w http.ResponseWriter, r *http.Reques
 
 	response.WriteAPISuccess(w, "Equipment deleted successfully", nil)
 }
+
+// localize translates equipment names and descriptions into the caller's language
+func (h *EquipmentHandler) localize(w http.ResponseWriter, r *http.Request, items []*dto.EquipmentResponseDTO) error {
+	if h.localizer == nil {
+		return nil
+	}
+	locale := h.localizer.ResolveLocale(middleware.GetGymID(r), middleware.GetUserID(r), r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", string(locale))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	translations, err := h.localizer.Lookup(locale, translation_enum.Equipment, ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		translations.Apply(item.ID, "name", &item.Name)
+		translations.Apply(item.ID, "description", &item.Description)
+	}
+	return nil
+}
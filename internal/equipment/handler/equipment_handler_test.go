@@ -90,7 +90,7 @@ func TestCreateEquipment(t *testing.T) {
 			mockService := new(MockEquipmentService)
 			tc.setupMock(mockService)
 
-			h := handler.NewEquipmentHandler(mockService)
+			h := handler.NewEquipmentHandler(mockService, nil)
 
 			body, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest(http.MethodPost, "/equipment", bytes.NewBuffer(body))
@@ -153,7 +153,7 @@ func TestGetEquipment(t *testing.T) {
 			mockService := new(MockEquipmentService)
 			tc.setupMock(mockService)
 
-			h := handler.NewEquipmentHandler(mockService)
+			h := handler.NewEquipmentHandler(mockService, nil)
 
 			// Use chi router to set URL param for id
 			router := chi.NewRouter()
@@ -227,7 +227,7 @@ func TestListEquipment(t *testing.T) {
 			mockService := new(MockEquipmentService)
 			tc.setupMock(mockService)
 
-			h := handler.NewEquipmentHandler(mockService)
+			h := handler.NewEquipmentHandler(mockService, nil)
 
 			req := httptest.NewRequest(http.MethodGet, "/equipment", nil)
 			w := httptest.NewRecorder()
@@ -292,7 +292,7 @@ func TestUpdateEquipment(t *testing.T) {
 			mockService := new(MockEquipmentService)
 			tc.setupMock(mockService)
 
-			h := handler.NewEquipmentHandler(mockService)
+			h := handler.NewEquipmentHandler(mockService, nil)
 
 			// Use chi router to set URL param for id
 			router := chi.NewRouter()
@@ -351,7 +351,7 @@ func TestDeleteEquipment(t *testing.T) {
 			mockService := new(MockEquipmentService)
 			tc.setupMock(mockService)
 
-			h := handler.NewEquipmentHandler(mockService)
+			h := handler.NewEquipmentHandler(mockService, nil)
 
 			// Use chi router to set URL param for id
 			router := chi.NewRouter()
@@ -8,11 +8,12 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/equipment/repository"
 	"github.com/alejandro-albiol/athenai/internal/equipment/router"
 	"github.com/alejandro-albiol/athenai/internal/equipment/service"
+	translationModule "github.com/alejandro-albiol/athenai/internal/translation/module"
 )
 
 func NewEquipmentModule(db *sql.DB) http.Handler {
 	repo := repository.NewEquipmentRepository(db)
 	service := service.NewEquipmentService(repo)
-	handler := handler.NewEquipmentHandler(service)
+	handler := handler.NewEquipmentHandler(service, translationModule.NewTranslationService(db))
 	return router.NewEquipmentRouter(handler)
 }
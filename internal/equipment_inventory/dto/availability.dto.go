@@ -0,0 +1,22 @@
+package dto
+
+// AvailableEquipment is a piece of equipment the gym can use right now, summed over its inventory items
+type AvailableEquipment struct {
+	EquipmentSource   string `json:"equipment_source"`
+	EquipmentID       string `json:"equipment_id"`
+	EquipmentName     string `json:"equipment_name"`
+	EquipmentCategory string `json:"equipment_category"`
+	AvailableQuantity int    `json:"available_quantity"`
+	TotalQuantity     int    `json:"total_quantity"`
+}
+
+// EquipmentWarning reports equipment an exercise needs that is tracked in the inventory but not usable.
+// Equipment the gym has not inventoried is not reported, so gyms that skip the inventory get no noise.
+type EquipmentWarning struct {
+	ExerciseSource  string `json:"exercise_source"` // public or gym
+	ExerciseID      string `json:"exercise_id"`
+	EquipmentSource string `json:"equipment_source"`
+	EquipmentID     string `json:"equipment_id"`
+	EquipmentName   string `json:"equipment_name"`
+	Status          string `json:"status"` // out_of_order or in_maintenance
+}
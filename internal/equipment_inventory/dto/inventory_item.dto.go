@@ -0,0 +1,45 @@
+package dto
+
+// InventoryItemCreationDTO registers equipment from the public or gym catalog in the gym's inventory
+type InventoryItemCreationDTO struct {
+	EquipmentSource   string  `json:"equipment_source"` // public or gym
+	PublicEquipmentID *string `json:"public_equipment_id,omitempty"`
+	GymEquipmentID    *string `json:"gym_equipment_id,omitempty"`
+	Quantity          int     `json:"quantity"`
+	Zone              *string `json:"zone,omitempty"`
+	PurchaseDate      *string `json:"purchase_date,omitempty"` // YYYY-MM-DD
+	Status            string  `json:"status,omitempty"`        // defaults to available
+	Notes             *string `json:"notes,omitempty"`
+}
+
+type InventoryItemUpdateDTO struct {
+	ID           string  `json:"id"`
+	Quantity     *int    `json:"quantity,omitempty"`
+	Zone         *string `json:"zone,omitempty"`
+	PurchaseDate *string `json:"purchase_date,omitempty"`
+	Status       *string `json:"status,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+type InventoryItemResponseDTO struct {
+	ID                string  `json:"id"`
+	EquipmentSource   string  `json:"equipment_source"`
+	PublicEquipmentID *string `json:"public_equipment_id,omitempty"`
+	GymEquipmentID    *string `json:"gym_equipment_id,omitempty"`
+	EquipmentName     string  `json:"equipment_name"`
+	EquipmentCategory string  `json:"equipment_category"`
+	Quantity          int     `json:"quantity"`
+	Zone              *string `json:"zone,omitempty"`
+	PurchaseDate      *string `json:"purchase_date,omitempty"`
+	Status            string  `json:"status"`
+	Notes             *string `json:"notes,omitempty"`
+	OpenTickets       int     `json:"open_tickets"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+// InventoryFilter narrows an inventory listing; empty fields match everything
+type InventoryFilter struct {
+	Status string
+	Zone   string
+}
@@ -0,0 +1,40 @@
+package dto
+
+type MaintenanceTicketCreationDTO struct {
+	InventoryItemID string  `json:"-"`
+	Title           string  `json:"title"`
+	Description     *string `json:"description,omitempty"`
+	ItemStatus      string  `json:"item_status,omitempty"` // out_of_order (default) or in_maintenance
+	OpenedBy        string  `json:"-"`
+}
+
+// MaintenanceTicketTransitionDTO moves a ticket to a new status and records it in the ticket history
+type MaintenanceTicketTransitionDTO struct {
+	Status    string  `json:"status"`
+	Note      *string `json:"note,omitempty"`
+	ChangedBy string  `json:"-"`
+}
+
+type MaintenanceTicketResponseDTO struct {
+	ID              string                 `json:"id"`
+	InventoryItemID string                 `json:"inventory_item_id"`
+	Title           string                 `json:"title"`
+	Description     *string                `json:"description,omitempty"`
+	Status          string                 `json:"status"`
+	ItemStatus      string                 `json:"item_status"`
+	OpenedBy        string                 `json:"opened_by"`
+	OpenedAt        string                 `json:"opened_at"`
+	ResolvedAt      *string                `json:"resolved_at,omitempty"`
+	Events          []*MaintenanceEventDTO `json:"events"`
+}
+
+// MaintenanceEventDTO is one entry of a ticket's history
+type MaintenanceEventDTO struct {
+	ID         string  `json:"id"`
+	TicketID   string  `json:"ticket_id"`
+	FromStatus *string `json:"from_status,omitempty"`
+	ToStatus   string  `json:"to_status"`
+	Note       *string `json:"note,omitempty"`
+	ChangedBy  string  `json:"changed_by"`
+	CreatedAt  string  `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package enum
+
+// InventoryStatus is the operational state of an inventory item
+type InventoryStatus string
+
+const (
+	StatusAvailable     InventoryStatus = "available"
+	StatusOutOfOrder    InventoryStatus = "out_of_order"
+	StatusInMaintenance InventoryStatus = "in_maintenance"
+)
+
+func (s InventoryStatus) IsValid() bool {
+	switch s {
+	case StatusAvailable, StatusOutOfOrder, StatusInMaintenance:
+		return true
+	}
+	return false
+}
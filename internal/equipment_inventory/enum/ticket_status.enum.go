@@ -0,0 +1,29 @@
+package enum
+
+// TicketStatus is the lifecycle state of a maintenance ticket
+type TicketStatus string
+
+const (
+	TicketOpen       TicketStatus = "open"
+	TicketInProgress TicketStatus = "in_progress"
+	TicketResolved   TicketStatus = "resolved"
+)
+
+func (s TicketStatus) IsValid() bool {
+	switch s {
+	case TicketOpen, TicketInProgress, TicketResolved:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a ticket may move from s to next. Resolved tickets are final.
+func (s TicketStatus) CanTransitionTo(next TicketStatus) bool {
+	switch s {
+	case TicketOpen:
+		return next == TicketInProgress || next == TicketResolved
+	case TicketInProgress:
+		return next == TicketResolved
+	}
+	return false
+}
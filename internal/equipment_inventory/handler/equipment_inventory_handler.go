@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type EquipmentInventoryHandler struct {
+	service interfaces.EquipmentInventoryService
+}
+
+func NewEquipmentInventoryHandler(service interfaces.EquipmentInventoryService) *EquipmentInventoryHandler {
+	return &EquipmentInventoryHandler{service: service}
+}
+
+func (h *EquipmentInventoryHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage the equipment inventory", nil))
+		return
+	}
+	var item dto.InventoryItemCreationDTO
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+
+	id, err := h.service.CreateItem(middleware.GetGymID(r), &item)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to create inventory item")
+		return
+	}
+	response.WriteAPICreated(w, "Inventory item created successfully", id)
+}
+
+func (h *EquipmentInventoryHandler) GetItemByID(w http.ResponseWriter, r *http.Request) {
+	item, err := h.service.GetItemByID(middleware.GetGymID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get inventory item")
+		return
+	}
+	response.WriteAPISuccess(w, "Inventory item retrieved successfully", item)
+}
+
+func (h *EquipmentInventoryHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	filter := dto.InventoryFilter{
+		Status: r.URL.Query().Get("status"),
+		Zone:   r.URL.Query().Get("zone"),
+	}
+	items, err := h.service.ListItems(middleware.GetGymID(r), filter)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list inventory items")
+		return
+	}
+	response.WriteAPISuccess(w, "Inventory items retrieved successfully", items)
+}
+
+func (h *EquipmentInventoryHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage the equipment inventory", nil))
+		return
+	}
+	var item dto.InventoryItemUpdateDTO
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	item.ID = chi.URLParam(r, "id")
+
+	if err := h.service.UpdateItem(middleware.GetGymID(r), &item); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update inventory item")
+		return
+	}
+	response.WriteAPISuccess(w, "Inventory item updated successfully", nil)
+}
+
+func (h *EquipmentInventoryHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage the equipment inventory", nil))
+		return
+	}
+	if err := h.service.DeleteItem(middleware.GetGymID(r), chi.URLParam(r, "id")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete inventory item")
+		return
+	}
+	response.WriteAPISuccess(w, "Inventory item deleted successfully", nil)
+}
+
+func (h *EquipmentInventoryHandler) ListAvailableEquipment(w http.ResponseWriter, r *http.Request) {
+	equipment, err := h.service.ListAvailableEquipment(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list available equipment")
+		return
+	}
+	response.WriteAPISuccess(w, "Available equipment retrieved successfully", equipment)
+}
+
+func (h *EquipmentInventoryHandler) CheckWorkoutInstance(w http.ResponseWriter, r *http.Request) {
+	warnings, err := h.service.CheckWorkoutInstance(middleware.GetGymID(r), chi.URLParam(r, "workoutInstanceID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to check equipment availability")
+		return
+	}
+	response.WriteAPISuccess(w, "Equipment availability checked successfully", warnings)
+}
+
+func (h *EquipmentInventoryHandler) OpenTicket(w http.ResponseWriter, r *http.Request) {
+	if !canReportMaintenance(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators and trainers can open maintenance tickets", nil))
+		return
+	}
+	var ticket dto.MaintenanceTicketCreationDTO
+	if err := json.NewDecoder(r.Body).Decode(&ticket); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	ticket.InventoryItemID = chi.URLParam(r, "id")
+	ticket.OpenedBy = middleware.GetUserID(r)
+
+	id, err := h.service.OpenTicket(middleware.GetGymID(r), &ticket)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to open maintenance ticket")
+		return
+	}
+	response.WriteAPICreated(w, "Maintenance ticket opened successfully", id)
+}
+
+func (h *EquipmentInventoryHandler) ListTickets(w http.ResponseWriter, r *http.Request) {
+	tickets, err := h.service.ListTicketsByItemID(middleware.GetGymID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list maintenance tickets")
+		return
+	}
+	response.WriteAPISuccess(w, "Maintenance tickets retrieved successfully", tickets)
+}
+
+func (h *EquipmentInventoryHandler) GetTicketByID(w http.ResponseWriter, r *http.Request) {
+	ticket, err := h.service.GetTicketByID(middleware.GetGymID(r), chi.URLParam(r, "ticketID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get maintenance ticket")
+		return
+	}
+	response.WriteAPISuccess(w, "Maintenance ticket retrieved successfully", ticket)
+}
+
+func (h *EquipmentInventoryHandler) TransitionTicket(w http.ResponseWriter, r *http.Request) {
+	if !canReportMaintenance(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators and trainers can update maintenance tickets", nil))
+		return
+	}
+	var transition dto.MaintenanceTicketTransitionDTO
+	if err := json.NewDecoder(r.Body).Decode(&transition); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	transition.ChangedBy = middleware.GetUserID(r)
+
+	if err := h.service.TransitionTicket(middleware.GetGymID(r), chi.URLParam(r, "ticketID"), &transition); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update maintenance ticket")
+		return
+	}
+	response.WriteAPISuccess(w, "Maintenance ticket updated successfully", nil)
+}
+
+func canReportMaintenance(r *http.Request) bool {
+	return middleware.IsGymAdmin(r) || middleware.GetUserRole(r) == string(user_enum.Trainer)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/router"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.EquipmentInventoryService
+	createdItem *dto.InventoryItemCreationDTO
+	ticket      *dto.MaintenanceTicketCreationDTO
+	transition  *dto.MaintenanceTicketTransitionDTO
+	filter      dto.InventoryFilter
+	err         error
+}
+
+func (m *mockService) CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error) {
+	m.createdItem = item
+	id := "item-1"
+	return &id, m.err
+}
+func (m *mockService) ListItems(gymID string, filter dto.InventoryFilter) ([]*dto.InventoryItemResponseDTO, error) {
+	m.filter = filter
+	return []*dto.InventoryItemResponseDTO{}, m.err
+}
+func (m *mockService) ListAvailableEquipment(gymID string) ([]*dto.AvailableEquipment, error) {
+	return []*dto.AvailableEquipment{{EquipmentName: "Rower", AvailableQuantity: 4, TotalQuantity: 5}}, m.err
+}
+func (m *mockService) OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error) {
+	m.ticket = ticket
+	id := "ticket-1"
+	return &id, m.err
+}
+func (m *mockService) TransitionTicket(gymID, ticketID string, transition *dto.MaintenanceTicketTransitionDTO) error {
+	m.transition = transition
+	return m.err
+}
+
+func serve(svc *mockService, method, target, body, userType, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewEquipmentInventoryRouter(NewEquipmentInventoryHandler(svc)),
+		testutil.Caller{UserType: userType, Role: role, UserID: "user-1", GymID: "gym1"}, method, target, body)
+}
+
+func TestCreateItemRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+	body := `{"equipment_source":"public","public_equipment_id":"eq-1","quantity":3,"zone":"Main floor"}`
+
+	w := serve(svc, http.MethodPost, "/", body, "tenant_user", "trainer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.createdItem)
+
+	w = serve(svc, http.MethodPost, "/", body, "tenant_user", "admin")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if assert.NotNil(t, svc.createdItem) {
+		assert.Equal(t, 3, svc.createdItem.Quantity)
+		assert.Equal(t, "Main floor", *svc.createdItem.Zone)
+	}
+}
+
+func TestListItemsPassesFilters(t *testing.T) {
+	svc := &mockService{}
+	w := serve(svc, http.MethodGet, "/?status=out_of_order&zone=Cardio", "", "tenant_user", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, dto.InventoryFilter{Status: "out_of_order", Zone: "Cardio"}, svc.filter)
+}
+
+func TestListAvailableEquipment(t *testing.T) {
+	w := serve(&mockService{}, http.MethodGet, "/available", "", "tenant_user", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data []dto.AvailableEquipment `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	if assert.Len(t, body.Data, 1) {
+		assert.Equal(t, "Rower", body.Data[0].EquipmentName)
+	}
+}
+
+func TestOpenTicketUsesRouteAndCaller(t *testing.T) {
+	svc := &mockService{}
+	w := serve(svc, http.MethodPost, "/item-9/tickets", `{"title":"Loose seat"}`, "tenant_user", "trainer")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if assert.NotNil(t, svc.ticket) {
+		assert.Equal(t, "item-9", svc.ticket.InventoryItemID)
+		assert.Equal(t, "user-1", svc.ticket.OpenedBy)
+	}
+
+	w = serve(&mockService{}, http.MethodPost, "/item-9/tickets", `{"title":"Loose seat"}`, "tenant_user", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTransitionTicketConflict(t *testing.T) {
+	svc := &mockService{err: apierror.New(errorcode_enum.CodeConflict, "Ticket cannot move from 'resolved' to 'open'", nil)}
+	w := serve(svc, http.MethodPatch, "/tickets/ticket-1/status", `{"status":"open"}`, "tenant_user", "admin")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	if assert.NotNil(t, svc.transition) {
+		assert.Equal(t, "user-1", svc.transition.ChangedBy)
+	}
+}
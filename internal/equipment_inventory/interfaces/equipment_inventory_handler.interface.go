@@ -0,0 +1,18 @@
+package interfaces
+
+import "net/http"
+
+type EquipmentInventoryHandler interface {
+	CreateItem(w http.ResponseWriter, r *http.Request)
+	GetItemByID(w http.ResponseWriter, r *http.Request)
+	ListItems(w http.ResponseWriter, r *http.Request)
+	UpdateItem(w http.ResponseWriter, r *http.Request)
+	DeleteItem(w http.ResponseWriter, r *http.Request)
+	ListAvailableEquipment(w http.ResponseWriter, r *http.Request)
+	CheckWorkoutInstance(w http.ResponseWriter, r *http.Request)
+
+	OpenTicket(w http.ResponseWriter, r *http.Request)
+	ListTickets(w http.ResponseWriter, r *http.Request)
+	GetTicketByID(w http.ResponseWriter, r *http.Request)
+	TransitionTicket(w http.ResponseWriter, r *http.Request)
+}
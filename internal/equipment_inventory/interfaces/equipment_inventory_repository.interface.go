@@ -0,0 +1,23 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+
+type EquipmentInventoryRepository interface {
+	CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error)
+	FindItemByID(gymID, id string) (*dto.InventoryItemResponseDTO, error)
+	ListItems(gymID string, filter dto.InventoryFilter) ([]*dto.InventoryItemResponseDTO, error)
+	UpdateItem(gymID string, item *dto.InventoryItemUpdateDTO) error
+	DeleteItem(gymID, id string) error
+	EquipmentExists(gymID, equipmentSource, equipmentID string) (bool, error)
+
+	OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error)
+	TransitionTicket(gymID string, ticket *dto.MaintenanceTicketResponseDTO, transition *dto.MaintenanceTicketTransitionDTO, itemStatus *string) error
+	FindTicketByID(gymID, id string) (*dto.MaintenanceTicketResponseDTO, error)
+	ListTicketsByItemID(gymID, itemID string) ([]*dto.MaintenanceTicketResponseDTO, error)
+	CountOpenTickets(gymID, itemID, excludeTicketID string) (int, error)
+
+	// Availability lookups used while building and generating workouts
+	ListAvailableEquipment(gymID string) ([]*dto.AvailableEquipment, error)
+	FindUnavailableEquipmentForExercise(gymID, exerciseSource, exerciseID string) ([]*dto.EquipmentWarning, error)
+	FindUnavailableEquipmentForWorkoutInstance(gymID, workoutInstanceID string) ([]*dto.EquipmentWarning, error)
+}
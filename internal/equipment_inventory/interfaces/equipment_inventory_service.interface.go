@@ -0,0 +1,26 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+
+type EquipmentInventoryService interface {
+	EquipmentAvailabilityChecker
+
+	CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error)
+	GetItemByID(gymID, id string) (*dto.InventoryItemResponseDTO, error)
+	ListItems(gymID string, filter dto.InventoryFilter) ([]*dto.InventoryItemResponseDTO, error)
+	UpdateItem(gymID string, item *dto.InventoryItemUpdateDTO) error
+	DeleteItem(gymID, id string) error
+
+	OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error)
+	TransitionTicket(gymID, ticketID string, transition *dto.MaintenanceTicketTransitionDTO) error
+	GetTicketByID(gymID, id string) (*dto.MaintenanceTicketResponseDTO, error)
+	ListTicketsByItemID(gymID, itemID string) ([]*dto.MaintenanceTicketResponseDTO, error)
+	CheckWorkoutInstance(gymID, workoutInstanceID string) ([]*dto.EquipmentWarning, error)
+}
+
+// EquipmentAvailabilityChecker lets workout building and generation ask what the gym can use right now.
+// Warnings only cover equipment the gym has inventoried, so gyms without an inventory get none.
+type EquipmentAvailabilityChecker interface {
+	ListAvailableEquipment(gymID string) ([]*dto.AvailableEquipment, error)
+	CheckExerciseEquipment(gymID, exerciseSource, exerciseID string) ([]*dto.EquipmentWarning, error)
+}
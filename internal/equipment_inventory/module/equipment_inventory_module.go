@@ -0,0 +1,21 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/handler"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/repository"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/router"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/service"
+)
+
+func NewEquipmentInventoryModule(db *sql.DB) http.Handler {
+	handler := handler.NewEquipmentInventoryHandler(NewEquipmentInventoryService(db))
+	return router.NewEquipmentInventoryRouter(handler)
+}
+
+// NewEquipmentInventoryService is also the availability checker used by workout building and generation
+func NewEquipmentInventoryService(db *sql.DB) *service.EquipmentInventoryService {
+	return service.NewEquipmentInventoryService(repository.NewEquipmentInventoryRepository(db))
+}
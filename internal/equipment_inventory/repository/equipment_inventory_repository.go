@@ -0,0 +1,409 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/enum"
+	"github.com/lib/pq"
+)
+
+type EquipmentInventoryRepository struct {
+	db *sql.DB
+}
+
+func NewEquipmentInventoryRepository(db *sql.DB) *EquipmentInventoryRepository {
+	return &EquipmentInventoryRepository{db: db}
+}
+
+// selectItems resolves the equipment name and category from whichever catalog the item points to
+const selectItems = `SELECT i.id, i.equipment_source, i.public_equipment_id, i.gym_equipment_id,
+		COALESCE(e.name, ce.name), COALESCE(e.category, ce.category),
+		i.quantity, i.zone, TO_CHAR(i.purchase_date, 'YYYY-MM-DD'), i.status, i.notes,
+		(SELECT COUNT(*) FROM %[1]s.equipment_maintenance_ticket t WHERE t.inventory_item_id = i.id AND t.status <> 'resolved'),
+		i.created_at, i.updated_at
+		FROM %[1]s.equipment_inventory i
+		LEFT JOIN public.equipment e ON e.id = i.public_equipment_id
+		LEFT JOIN %[1]s.custom_equipment ce ON ce.id = i.gym_equipment_id`
+
+const selectTickets = `SELECT id, inventory_item_id, title, description, status, item_status, opened_by, opened_at, resolved_at
+		FROM %s.equipment_maintenance_ticket`
+
+// unavailableEquipment keeps the equipment in the needed CTE that the gym has inventoried
+// but cannot use: no item with that equipment is available
+const unavailableEquipment = `
+		SELECT n.exercise_source, n.exercise_id, n.equipment_source, n.equipment_id,
+			COALESCE(e.name, ce.name), BOOL_OR(i.status = 'out_of_order')
+		FROM needed n
+		JOIN %[1]s.equipment_inventory i ON i.equipment_source = n.equipment_source
+			AND COALESCE(i.public_equipment_id, i.gym_equipment_id) = n.equipment_id
+		LEFT JOIN public.equipment e ON n.equipment_source = 'public' AND e.id = n.equipment_id
+		LEFT JOIN %[1]s.custom_equipment ce ON n.equipment_source = 'gym' AND ce.id = n.equipment_id
+		GROUP BY n.exercise_source, n.exercise_id, n.equipment_source, n.equipment_id, e.name, ce.name
+		HAVING NOT BOOL_OR(i.status = 'available')
+		ORDER BY 5`
+
+func (r *EquipmentInventoryRepository) CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s.equipment_inventory
+		(equipment_source, public_equipment_id, gym_equipment_id, quantity, zone, purchase_date, status, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, pq.QuoteIdentifier(gymID))
+	var id string
+	err := r.db.QueryRow(query, item.EquipmentSource, item.PublicEquipmentID, item.GymEquipmentID,
+		item.Quantity, item.Zone, item.PurchaseDate, item.Status, item.Notes).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *EquipmentInventoryRepository) FindItemByID(gymID, id string) (*dto.InventoryItemResponseDTO, error) {
+	query := fmt.Sprintf(selectItems+" WHERE i.id = $1", pq.QuoteIdentifier(gymID))
+	return scanItem(r.db.QueryRow(query, id))
+}
+
+func (r *EquipmentInventoryRepository) ListItems(gymID string, filter dto.InventoryFilter) ([]*dto.InventoryItemResponseDTO, error) {
+	var conditions []string
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("i.status = $%d", len(args)))
+	}
+	if filter.Zone != "" {
+		args = append(args, filter.Zone)
+		conditions = append(conditions, fmt.Sprintf("LOWER(i.zone) = LOWER($%d)", len(args)))
+	}
+	query := selectItems
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query = fmt.Sprintf(query+" ORDER BY 5, i.zone", pq.QuoteIdentifier(gymID))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*dto.InventoryItemResponseDTO
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// UpdateItem only overwrites the fields that are set
+func (r *EquipmentInventoryRepository) UpdateItem(gymID string, item *dto.InventoryItemUpdateDTO) error {
+	query := fmt.Sprintf(`UPDATE %s.equipment_inventory SET
+		quantity = COALESCE($2, quantity),
+		zone = COALESCE($3, zone),
+		purchase_date = COALESCE($4::date, purchase_date),
+		status = COALESCE($5, status),
+		notes = COALESCE($6, notes),
+		updated_at = NOW()
+		WHERE id = $1`, pq.QuoteIdentifier(gymID))
+	result, err := r.db.Exec(query, item.ID, item.Quantity, item.Zone, item.PurchaseDate, item.Status, item.Notes)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+// DeleteItem removes the item together with its tickets and their history
+func (r *EquipmentInventoryRepository) DeleteItem(gymID, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s.equipment_inventory WHERE id = $1", pq.QuoteIdentifier(gymID))
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+// EquipmentExists checks the equipment is active in the catalog the source points to
+func (r *EquipmentInventoryRepository) EquipmentExists(gymID, equipmentSource, equipmentID string) (bool, error) {
+	query := "SELECT EXISTS (SELECT 1 FROM public.equipment WHERE id = $1 AND is_active = TRUE)"
+	if equipmentSource == "gym" {
+		query = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s.custom_equipment WHERE id = $1 AND is_active = TRUE)", pq.QuoteIdentifier(gymID))
+	}
+	var exists bool
+	err := r.db.QueryRow(query, equipmentID).Scan(&exists)
+	return exists, err
+}
+
+// OpenTicket creates the ticket, records its first history entry and takes the item out of service
+func (r *EquipmentInventoryRepository) OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id string
+	query := fmt.Sprintf(`INSERT INTO %s.equipment_maintenance_ticket (inventory_item_id, title, description, status, item_status, opened_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, schema)
+	err = tx.QueryRow(query, ticket.InventoryItemID, ticket.Title, ticket.Description, enum.TicketOpen, ticket.ItemStatus, ticket.OpenedBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf(`INSERT INTO %s.equipment_maintenance_event (ticket_id, from_status, to_status, note, changed_by)
+		VALUES ($1, NULL, $2, $3, $4)`, schema)
+	if _, err := tx.Exec(query, id, enum.TicketOpen, ticket.Description, ticket.OpenedBy); err != nil {
+		return nil, err
+	}
+	if err := setItemStatus(tx, schema, ticket.InventoryItemID, ticket.ItemStatus); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// TransitionTicket moves the ticket to a new status and records the change.
+// When itemStatus is set the inventory item is moved to it in the same transaction.
+func (r *EquipmentInventoryRepository) TransitionTicket(gymID string, ticket *dto.MaintenanceTicketResponseDTO, transition *dto.MaintenanceTicketTransitionDTO, itemStatus *string) error {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`UPDATE %s.equipment_maintenance_ticket
+		SET status = $2, resolved_at = CASE WHEN $2 = 'resolved' THEN NOW() ELSE NULL END
+		WHERE id = $1 AND status = $3`, schema)
+	result, err := tx.Exec(query, ticket.ID, transition.Status, ticket.Status)
+	if err != nil {
+		return err
+	}
+	// Another request moved the ticket first
+	if err := expectRow(result); err != nil {
+		return err
+	}
+	query = fmt.Sprintf(`INSERT INTO %s.equipment_maintenance_event (ticket_id, from_status, to_status, note, changed_by)
+		VALUES ($1, $2, $3, $4, $5)`, schema)
+	if _, err := tx.Exec(query, ticket.ID, ticket.Status, transition.Status, transition.Note, transition.ChangedBy); err != nil {
+		return err
+	}
+	if itemStatus != nil {
+		if err := setItemStatus(tx, schema, ticket.InventoryItemID, *itemStatus); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *EquipmentInventoryRepository) FindTicketByID(gymID, id string) (*dto.MaintenanceTicketResponseDTO, error) {
+	query := fmt.Sprintf(selectTickets+" WHERE id = $1", pq.QuoteIdentifier(gymID))
+	ticket, err := scanTicket(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachEvents(gymID, []*dto.MaintenanceTicketResponseDTO{ticket}); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// ListTicketsByItemID returns the item's maintenance history, newest ticket first
+func (r *EquipmentInventoryRepository) ListTicketsByItemID(gymID, itemID string) ([]*dto.MaintenanceTicketResponseDTO, error) {
+	query := fmt.Sprintf(selectTickets+" WHERE inventory_item_id = $1 ORDER BY opened_at DESC", pq.QuoteIdentifier(gymID))
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []*dto.MaintenanceTicketResponseDTO
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.attachEvents(gymID, tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountOpenTickets counts the unresolved tickets of an item, leaving out excludeTicketID
+func (r *EquipmentInventoryRepository) CountOpenTickets(gymID, itemID, excludeTicketID string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s.equipment_maintenance_ticket
+		WHERE inventory_item_id = $1 AND status <> 'resolved' AND id::text <> $2`, pq.QuoteIdentifier(gymID))
+	var count int
+	err := r.db.QueryRow(query, itemID, excludeTicketID).Scan(&count)
+	return count, err
+}
+
+func (r *EquipmentInventoryRepository) ListAvailableEquipment(gymID string) ([]*dto.AvailableEquipment, error) {
+	query := fmt.Sprintf(`SELECT i.equipment_source, COALESCE(i.public_equipment_id, i.gym_equipment_id),
+		COALESCE(e.name, ce.name), COALESCE(e.category, ce.category),
+		SUM(i.quantity) FILTER (WHERE i.status = 'available'), SUM(i.quantity)
+		FROM %[1]s.equipment_inventory i
+		LEFT JOIN public.equipment e ON e.id = i.public_equipment_id
+		LEFT JOIN %[1]s.custom_equipment ce ON ce.id = i.gym_equipment_id
+		GROUP BY 1, 2, 3, 4
+		HAVING BOOL_OR(i.status = 'available')
+		ORDER BY 3`, pq.QuoteIdentifier(gymID))
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var equipment []*dto.AvailableEquipment
+	for rows.Next() {
+		var eq dto.AvailableEquipment
+		var name, category sql.NullString
+		if err := rows.Scan(&eq.EquipmentSource, &eq.EquipmentID, &name, &category, &eq.AvailableQuantity, &eq.TotalQuantity); err != nil {
+			return nil, err
+		}
+		eq.EquipmentName = name.String
+		eq.EquipmentCategory = category.String
+		equipment = append(equipment, &eq)
+	}
+	return equipment, rows.Err()
+}
+
+func (r *EquipmentInventoryRepository) FindUnavailableEquipmentForExercise(gymID, exerciseSource, exerciseID string) ([]*dto.EquipmentWarning, error) {
+	query := fmt.Sprintf(`WITH needed AS (
+			SELECT 'public' AS exercise_source, ee.exercise_id::text AS exercise_id, 'public' AS equipment_source, ee.equipment_id
+			FROM public.exercise_equipment ee
+			WHERE $1 = 'public' AND ee.exercise_id::text = $2
+			UNION
+			SELECT 'gym', cee.custom_exercise_id::text, cee.equipment_source, COALESCE(cee.public_equipment_id, cee.gym_equipment_id)
+			FROM %[1]s.custom_exercise_equipment cee
+			WHERE $1 = 'gym' AND cee.custom_exercise_id::text = $2
+		)`+unavailableEquipment, pq.QuoteIdentifier(gymID))
+	return r.queryWarnings(query, exerciseSource, exerciseID)
+}
+
+func (r *EquipmentInventoryRepository) FindUnavailableEquipmentForWorkoutInstance(gymID, workoutInstanceID string) ([]*dto.EquipmentWarning, error) {
+	query := fmt.Sprintf(`WITH needed AS (
+			SELECT 'public' AS exercise_source, ee.exercise_id::text AS exercise_id, 'public' AS equipment_source, ee.equipment_id
+			FROM %[1]s.custom_workout_exercise cwe
+			JOIN public.exercise_equipment ee ON ee.exercise_id = cwe.public_exercise_id
+			WHERE cwe.workout_instance_id = $1
+			UNION
+			SELECT 'gym', cee.custom_exercise_id::text, cee.equipment_source, COALESCE(cee.public_equipment_id, cee.gym_equipment_id)
+			FROM %[1]s.custom_workout_exercise cwe
+			JOIN %[1]s.custom_exercise_equipment cee ON cee.custom_exercise_id = cwe.gym_exercise_id
+			WHERE cwe.workout_instance_id = $1
+		)`+unavailableEquipment, pq.QuoteIdentifier(gymID))
+	return r.queryWarnings(query, workoutInstanceID)
+}
+
+func (r *EquipmentInventoryRepository) queryWarnings(query string, args ...any) ([]*dto.EquipmentWarning, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []*dto.EquipmentWarning
+	for rows.Next() {
+		var warning dto.EquipmentWarning
+		var name sql.NullString
+		var outOfOrder bool
+		if err := rows.Scan(&warning.ExerciseSource, &warning.ExerciseID, &warning.EquipmentSource, &warning.EquipmentID, &name, &outOfOrder); err != nil {
+			return nil, err
+		}
+		warning.EquipmentName = name.String
+		warning.Status = string(enum.StatusInMaintenance)
+		if outOfOrder {
+			warning.Status = string(enum.StatusOutOfOrder)
+		}
+		warnings = append(warnings, &warning)
+	}
+	return warnings, rows.Err()
+}
+
+// attachEvents loads the history of the given tickets in one query
+func (r *EquipmentInventoryRepository) attachEvents(gymID string, tickets []*dto.MaintenanceTicketResponseDTO) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	byID := make(map[string]*dto.MaintenanceTicketResponseDTO, len(tickets))
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ticket.Events = []*dto.MaintenanceEventDTO{}
+		byID[ticket.ID] = ticket
+		ids = append(ids, ticket.ID)
+	}
+
+	query := fmt.Sprintf(`SELECT id, ticket_id, from_status, to_status, note, changed_by, created_at
+		FROM %s.equipment_maintenance_event WHERE ticket_id::text = ANY($1) ORDER BY created_at, id`, pq.QuoteIdentifier(gymID))
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event dto.MaintenanceEventDTO
+		if err := rows.Scan(&event.ID, &event.TicketID, &event.FromStatus, &event.ToStatus, &event.Note, &event.ChangedBy, &event.CreatedAt); err != nil {
+			return err
+		}
+		if ticket, ok := byID[event.TicketID]; ok {
+			ticket.Events = append(ticket.Events, &event)
+		}
+	}
+	return rows.Err()
+}
+
+func setItemStatus(tx *sql.Tx, schema, itemID, status string) error {
+	query := fmt.Sprintf("UPDATE %s.equipment_inventory SET status = $2, updated_at = NOW() WHERE id = $1", schema)
+	_, err := tx.Exec(query, itemID, status)
+	return err
+}
+
+func expectRow(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row rowScanner) (*dto.InventoryItemResponseDTO, error) {
+	var item dto.InventoryItemResponseDTO
+	var name, category sql.NullString
+	err := row.Scan(&item.ID, &item.EquipmentSource, &item.PublicEquipmentID, &item.GymEquipmentID, &name, &category,
+		&item.Quantity, &item.Zone, &item.PurchaseDate, &item.Status, &item.Notes, &item.OpenTickets, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	item.EquipmentName = name.String
+	item.EquipmentCategory = category.String
+	return &item, nil
+}
+
+func scanTicket(row rowScanner) (*dto.MaintenanceTicketResponseDTO, error) {
+	var ticket dto.MaintenanceTicketResponseDTO
+	err := row.Scan(&ticket.ID, &ticket.InventoryItemID, &ticket.Title, &ticket.Description, &ticket.Status,
+		&ticket.ItemStatus, &ticket.OpenedBy, &ticket.OpenedAt, &ticket.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
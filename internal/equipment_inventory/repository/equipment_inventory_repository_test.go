@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var itemColumns = []string{"id", "equipment_source", "public_equipment_id", "gym_equipment_id", "name", "category",
+	"quantity", "zone", "purchase_date", "status", "notes", "open_tickets", "created_at", "updated_at"}
+
+func TestListItemsFilters(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM "tenant1".equipment_inventory i (.+) WHERE i.status = \$1 AND LOWER\(i.zone\) = LOWER\(\$2\) ORDER BY 5`).
+		WithArgs("out_of_order", "cardio room").
+		WillReturnRows(sqlmock.NewRows(itemColumns).
+			AddRow("item-1", "public", "eq-1", nil, "Treadmill", "cardio", 3, "Cardio Room", "2023-05-01", "out_of_order", nil, 1, now, now))
+
+	items, err := repo.ListItems("tenant1", dto.InventoryFilter{Status: "out_of_order", Zone: "cardio room"})
+	require.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "Treadmill", items[0].EquipmentName)
+		assert.Equal(t, 3, items[0].Quantity)
+		assert.Equal(t, "2023-05-01", *items[0].PurchaseDate)
+		assert.Equal(t, 1, items[0].OpenTickets)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpenTicket(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	ticket := &dto.MaintenanceTicketCreationDTO{InventoryItemID: "item-1", Title: "Belt slipping", ItemStatus: "out_of_order", OpenedBy: "user-1"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tenant1".equipment_maintenance_ticket`)).
+		WithArgs("item-1", "Belt slipping", nil, "open", "out_of_order", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ticket-1"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tenant1".equipment_maintenance_event`)).
+		WithArgs("ticket-1", "open", nil, "user-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tenant1".equipment_inventory SET status = $2`)).
+		WithArgs("item-1", "out_of_order").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.OpenTicket("tenant1", ticket)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket-1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransitionTicketLostRace(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	ticket := &dto.MaintenanceTicketResponseDTO{ID: "ticket-1", InventoryItemID: "item-1", Status: "open"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tenant1".equipment_maintenance_ticket`)).
+		WithArgs("ticket-1", "resolved", "open").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.TransitionTicket("tenant1", ticket, &dto.MaintenanceTicketTransitionDTO{Status: "resolved", ChangedBy: "user-1"}, nil)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindTicketByIDWithHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM "tenant1".equipment_maintenance_ticket WHERE id = \$1`).
+		WithArgs("ticket-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inventory_item_id", "title", "description", "status", "item_status", "opened_by", "opened_at", "resolved_at"}).
+			AddRow("ticket-1", "item-1", "Belt slipping", nil, "in_progress", "out_of_order", "user-1", now, nil))
+	mock.ExpectQuery(`FROM "tenant1".equipment_maintenance_event WHERE ticket_id::text = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticket_id", "from_status", "to_status", "note", "changed_by", "created_at"}).
+			AddRow("event-1", "ticket-1", nil, "open", nil, "user-1", now).
+			AddRow("event-2", "ticket-1", "open", "in_progress", "Parts ordered", "user-2", now))
+
+	ticket, err := repo.FindTicketByID("tenant1", "ticket-1")
+	require.NoError(t, err)
+	if assert.Len(t, ticket.Events, 2) {
+		assert.Nil(t, ticket.Events[0].FromStatus)
+		assert.Equal(t, "in_progress", ticket.Events[1].ToStatus)
+		assert.Equal(t, "Parts ordered", *ticket.Events[1].Note)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindUnavailableEquipmentForExercise(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	mock.ExpectQuery(`WITH needed AS (.+) FROM "tenant1".custom_exercise_equipment cee (.+) HAVING NOT BOOL_OR\(i.status = 'available'\)`).
+		WithArgs("gym", "custom-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_source", "exercise_id", "equipment_source", "equipment_id", "name", "out_of_order"}).
+			AddRow("gym", "custom-1", "gym", "sled-1", "Sled", false).
+			AddRow("gym", "custom-1", "public", "rack-1", "Squat Rack", true))
+
+	warnings, err := repo.FindUnavailableEquipmentForExercise("tenant1", "gym", "custom-1")
+	require.NoError(t, err)
+	if assert.Len(t, warnings, 2) {
+		assert.Equal(t, "in_maintenance", warnings[0].Status)
+		assert.Equal(t, "out_of_order", warnings[1].Status)
+		assert.Equal(t, "Squat Rack", warnings[1].EquipmentName)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListAvailableEquipment(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewEquipmentInventoryRepository(db)
+	mock.ExpectQuery(`FILTER \(WHERE i.status = 'available'\)(.+)HAVING BOOL_OR\(i.status = 'available'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"equipment_source", "equipment_id", "name", "category", "available", "total"}).
+			AddRow("public", "db-1", "Dumbbells", "free_weights", 10, 12))
+
+	equipment, err := repo.ListAvailableEquipment("tenant1")
+	require.NoError(t, err)
+	if assert.Len(t, equipment, 1) {
+		assert.Equal(t, 10, equipment[0].AvailableQuantity)
+		assert.Equal(t, 12, equipment[0].TotalQuantity)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
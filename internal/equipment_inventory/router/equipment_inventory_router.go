@@ -0,0 +1,26 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewEquipmentInventoryRouter(handler interfaces.EquipmentInventoryHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/", handler.CreateItem)                                                       // POST /equipment-inventory
+	r.Get("/", handler.ListItems)                                                         // GET /equipment-inventory?status=&zone=
+	r.Get("/available", handler.ListAvailableEquipment)                                   // GET /equipment-inventory/available
+	r.Get("/workout-instance/{workoutInstanceID}/warnings", handler.CheckWorkoutInstance) // GET /equipment-inventory/workout-instance/{workoutInstanceID}/warnings
+	r.Get("/tickets/{ticketID}", handler.GetTicketByID)                                   // GET /equipment-inventory/tickets/{ticketID}
+	r.Patch("/tickets/{ticketID}/status", handler.TransitionTicket)                       // PATCH /equipment-inventory/tickets/{ticketID}/status
+	r.Get("/{id}", handler.GetItemByID)                                                   // GET /equipment-inventory/{id}
+	r.Put("/{id}", handler.UpdateItem)                                                    // PUT /equipment-inventory/{id}
+	r.Delete("/{id}", handler.DeleteItem)                                                 // DELETE /equipment-inventory/{id}
+	r.Post("/{id}/tickets", handler.OpenTicket)                                           // POST /equipment-inventory/{id}/tickets
+	r.Get("/{id}/tickets", handler.ListTickets)                                           // GET /equipment-inventory/{id}/tickets
+
+	return r
+}
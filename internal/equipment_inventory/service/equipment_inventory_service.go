@@ -0,0 +1,288 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	source_enum "github.com/alejandro-albiol/athenai/internal/custom_exercise_equipment/enum"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/enum"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type EquipmentInventoryService struct {
+	repo interfaces.EquipmentInventoryRepository
+}
+
+func NewEquipmentInventoryService(repo interfaces.EquipmentInventoryRepository) *EquipmentInventoryService {
+	return &EquipmentInventoryService{repo: repo}
+}
+
+func (s *EquipmentInventoryService) CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error) {
+	if err := validateEquipmentReference(item); err != nil {
+		return nil, err
+	}
+	if item.Quantity < 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Quantity must be greater than 0", nil)
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	if item.Status == "" {
+		item.Status = string(enum.StatusAvailable)
+	}
+	if !enum.InventoryStatus(item.Status).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Status must be 'available', 'out_of_order' or 'in_maintenance'", nil)
+	}
+	if err := validatePurchaseDate(item.PurchaseDate); err != nil {
+		return nil, err
+	}
+
+	equipmentID := item.PublicEquipmentID
+	if item.EquipmentSource == string(source_enum.SourceGym) {
+		equipmentID = item.GymEquipmentID
+	}
+	exists, err := s.repo.EquipmentExists(gymID, item.EquipmentSource, *equipmentID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check equipment", err)
+	}
+	if !exists {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("Equipment not found in the %s catalog", item.EquipmentSource), nil)
+	}
+
+	id, err := s.repo.CreateItem(gymID, item)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create inventory item", err)
+	}
+	return id, nil
+}
+
+func (s *EquipmentInventoryService) GetItemByID(gymID, id string) (*dto.InventoryItemResponseDTO, error) {
+	if id == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
+	}
+	item, err := s.repo.FindItemByID(gymID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Inventory item not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get inventory item", err)
+	}
+	return item, nil
+}
+
+func (s *EquipmentInventoryService) ListItems(gymID string, filter dto.InventoryFilter) ([]*dto.InventoryItemResponseDTO, error) {
+	if filter.Status != "" && !enum.InventoryStatus(filter.Status).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Status must be 'available', 'out_of_order' or 'in_maintenance'", nil)
+	}
+	items, err := s.repo.ListItems(gymID, filter)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list inventory items", err)
+	}
+	return items, nil
+}
+
+func (s *EquipmentInventoryService) UpdateItem(gymID string, item *dto.InventoryItemUpdateDTO) error {
+	if item.ID == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
+	}
+	if item.Quantity != nil && *item.Quantity <= 0 {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Quantity must be greater than 0", nil)
+	}
+	if item.Status != nil && !enum.InventoryStatus(*item.Status).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Status must be 'available', 'out_of_order' or 'in_maintenance'", nil)
+	}
+	if err := validatePurchaseDate(item.PurchaseDate); err != nil {
+		return err
+	}
+
+	existing, err := s.GetItemByID(gymID, item.ID)
+	if err != nil {
+		return err
+	}
+	// Open tickets own the status, resolving them puts the item back in service
+	if item.Status != nil && *item.Status == string(enum.StatusAvailable) && existing.OpenTickets > 0 {
+		return apierror.New(errorcode_enum.CodeConflict, "Resolve the open maintenance tickets before marking the item available", nil)
+	}
+
+	if err := s.repo.UpdateItem(gymID, item); err != nil {
+		if err == sql.ErrNoRows {
+			return apierror.New(errorcode_enum.CodeNotFound, "Inventory item not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to update inventory item", err)
+	}
+	return nil
+}
+
+func (s *EquipmentInventoryService) DeleteItem(gymID, id string) error {
+	if id == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, "ID is required", nil)
+	}
+	if err := s.repo.DeleteItem(gymID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return apierror.New(errorcode_enum.CodeNotFound, "Inventory item not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete inventory item", err)
+	}
+	return nil
+}
+
+func (s *EquipmentInventoryService) OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error) {
+	ticket.Title = strings.TrimSpace(ticket.Title)
+	if ticket.Title == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Title is required", nil)
+	}
+	if ticket.OpenedBy == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "OpenedBy is required", nil)
+	}
+	if ticket.ItemStatus == "" {
+		ticket.ItemStatus = string(enum.StatusOutOfOrder)
+	}
+	if ticket.ItemStatus != string(enum.StatusOutOfOrder) && ticket.ItemStatus != string(enum.StatusInMaintenance) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "ItemStatus must be 'out_of_order' or 'in_maintenance'", nil)
+	}
+	if _, err := s.GetItemByID(gymID, ticket.InventoryItemID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.OpenTicket(gymID, ticket)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to open maintenance ticket", err)
+	}
+	return id, nil
+}
+
+func (s *EquipmentInventoryService) TransitionTicket(gymID, ticketID string, transition *dto.MaintenanceTicketTransitionDTO) error {
+	next := enum.TicketStatus(transition.Status)
+	if !next.IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Status must be 'open', 'in_progress' or 'resolved'", nil)
+	}
+	ticket, err := s.GetTicketByID(gymID, ticketID)
+	if err != nil {
+		return err
+	}
+	if !enum.TicketStatus(ticket.Status).CanTransitionTo(next) {
+		return apierror.New(errorcode_enum.CodeConflict, fmt.Sprintf("Ticket cannot move from '%s' to '%s'", ticket.Status, next), nil)
+	}
+
+	var itemStatus *string
+	switch next {
+	case enum.TicketInProgress:
+		status := string(enum.StatusInMaintenance)
+		itemStatus = &status
+	case enum.TicketResolved:
+		// The item stays out of service while any other ticket is still open
+		open, err := s.repo.CountOpenTickets(gymID, ticket.InventoryItemID, ticket.ID)
+		if err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Failed to check open maintenance tickets", err)
+		}
+		if open == 0 {
+			status := string(enum.StatusAvailable)
+			itemStatus = &status
+		}
+	}
+
+	if err := s.repo.TransitionTicket(gymID, ticket, transition, itemStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return apierror.New(errorcode_enum.CodeConflict, "Ticket was updated by someone else, reload it and try again", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to update maintenance ticket", err)
+	}
+	return nil
+}
+
+func (s *EquipmentInventoryService) GetTicketByID(gymID, id string) (*dto.MaintenanceTicketResponseDTO, error) {
+	if id == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Ticket ID is required", nil)
+	}
+	ticket, err := s.repo.FindTicketByID(gymID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Maintenance ticket not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get maintenance ticket", err)
+	}
+	return ticket, nil
+}
+
+func (s *EquipmentInventoryService) ListTicketsByItemID(gymID, itemID string) ([]*dto.MaintenanceTicketResponseDTO, error) {
+	if _, err := s.GetItemByID(gymID, itemID); err != nil {
+		return nil, err
+	}
+	tickets, err := s.repo.ListTicketsByItemID(gymID, itemID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list maintenance tickets", err)
+	}
+	return tickets, nil
+}
+
+func (s *EquipmentInventoryService) ListAvailableEquipment(gymID string) ([]*dto.AvailableEquipment, error) {
+	equipment, err := s.repo.ListAvailableEquipment(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list available equipment", err)
+	}
+	return equipment, nil
+}
+
+func (s *EquipmentInventoryService) CheckExerciseEquipment(gymID, exerciseSource, exerciseID string) ([]*dto.EquipmentWarning, error) {
+	warnings, err := s.repo.FindUnavailableEquipmentForExercise(gymID, exerciseSource, exerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check equipment availability", err)
+	}
+	return warnings, nil
+}
+
+func (s *EquipmentInventoryService) CheckWorkoutInstance(gymID, workoutInstanceID string) ([]*dto.EquipmentWarning, error) {
+	if workoutInstanceID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "WorkoutInstanceID is required", nil)
+	}
+	warnings, err := s.repo.FindUnavailableEquipmentForWorkoutInstance(gymID, workoutInstanceID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check equipment availability", err)
+	}
+	return warnings, nil
+}
+
+func validateEquipmentReference(item *dto.InventoryItemCreationDTO) error {
+	if !source_enum.EquipmentSource(item.EquipmentSource).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "EquipmentSource must be 'public' or 'gym'", nil)
+	}
+	hasPublic := item.PublicEquipmentID != nil && *item.PublicEquipmentID != ""
+	hasGym := item.GymEquipmentID != nil && *item.GymEquipmentID != ""
+	if item.EquipmentSource == string(source_enum.SourcePublic) {
+		if !hasPublic {
+			return apierror.New(errorcode_enum.CodeBadRequest, "PublicEquipmentID is required when EquipmentSource is 'public'", nil)
+		}
+		if hasGym {
+			return apierror.New(errorcode_enum.CodeBadRequest, "GymEquipmentID must be empty when EquipmentSource is 'public'", nil)
+		}
+		item.GymEquipmentID = nil
+		return nil
+	}
+	if !hasGym {
+		return apierror.New(errorcode_enum.CodeBadRequest, "GymEquipmentID is required when EquipmentSource is 'gym'", nil)
+	}
+	if hasPublic {
+		return apierror.New(errorcode_enum.CodeBadRequest, "PublicEquipmentID must be empty when EquipmentSource is 'gym'", nil)
+	}
+	item.PublicEquipmentID = nil
+	return nil
+}
+
+func validatePurchaseDate(date *string) error {
+	if date == nil {
+		return nil
+	}
+	parsed, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeBadRequest, "PurchaseDate must be formatted as YYYY-MM-DD", err)
+	}
+	if parsed.After(time.Now()) {
+		return apierror.New(errorcode_enum.CodeBadRequest, "PurchaseDate cannot be in the future", nil)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	"github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepository keeps items and tickets in memory and applies status changes like the SQL transactions do
+type mockRepository struct {
+	interfaces.EquipmentInventoryRepository
+	equipmentExists bool
+	created         *dto.InventoryItemCreationDTO
+	items           map[string]*dto.InventoryItemResponseDTO
+	tickets         map[string]*dto.MaintenanceTicketResponseDTO
+	updated         *dto.InventoryItemUpdateDTO
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		equipmentExists: true,
+		items:           map[string]*dto.InventoryItemResponseDTO{"item-1": {ID: "item-1", Status: "available", Quantity: 2}},
+		tickets:         map[string]*dto.MaintenanceTicketResponseDTO{},
+	}
+}
+
+func (m *mockRepository) EquipmentExists(gymID, equipmentSource, equipmentID string) (bool, error) {
+	return m.equipmentExists, nil
+}
+func (m *mockRepository) CreateItem(gymID string, item *dto.InventoryItemCreationDTO) (*string, error) {
+	m.created = item
+	id := "item-2"
+	return &id, nil
+}
+func (m *mockRepository) FindItemByID(gymID, id string) (*dto.InventoryItemResponseDTO, error) {
+	item, ok := m.items[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	item.OpenTickets, _ = m.CountOpenTickets(gymID, id, "")
+	return item, nil
+}
+func (m *mockRepository) UpdateItem(gymID string, item *dto.InventoryItemUpdateDTO) error {
+	m.updated = item
+	return nil
+}
+func (m *mockRepository) OpenTicket(gymID string, ticket *dto.MaintenanceTicketCreationDTO) (*string, error) {
+	id := "ticket-" + string(rune('a'+len(m.tickets)))
+	m.tickets[id] = &dto.MaintenanceTicketResponseDTO{ID: id, InventoryItemID: ticket.InventoryItemID, Status: "open", ItemStatus: ticket.ItemStatus}
+	m.items[ticket.InventoryItemID].Status = ticket.ItemStatus
+	return &id, nil
+}
+func (m *mockRepository) TransitionTicket(gymID string, ticket *dto.MaintenanceTicketResponseDTO, transition *dto.MaintenanceTicketTransitionDTO, itemStatus *string) error {
+	m.tickets[ticket.ID].Status = transition.Status
+	if itemStatus != nil {
+		m.items[ticket.InventoryItemID].Status = *itemStatus
+	}
+	return nil
+}
+func (m *mockRepository) FindTicketByID(gymID, id string) (*dto.MaintenanceTicketResponseDTO, error) {
+	ticket, ok := m.tickets[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *ticket
+	return &copied, nil
+}
+func (m *mockRepository) CountOpenTickets(gymID, itemID, excludeTicketID string) (int, error) {
+	count := 0
+	for _, ticket := range m.tickets {
+		if ticket.InventoryItemID == itemID && ticket.ID != excludeTicketID && ticket.Status != "resolved" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestCreateItem(t *testing.T) {
+	t.Run("defaults quantity and status", func(t *testing.T) {
+		repo := newMockRepository()
+		svc := NewEquipmentInventoryService(repo)
+		id, err := svc.CreateItem("gym1", &dto.InventoryItemCreationDTO{EquipmentSource: "gym", GymEquipmentID: strPtr("sled-1"), Zone: strPtr("Turf")})
+		require.NoError(t, err)
+		assert.Equal(t, "item-2", *id)
+		assert.Equal(t, 1, repo.created.Quantity)
+		assert.Equal(t, "available", repo.created.Status)
+		assert.Nil(t, repo.created.PublicEquipmentID)
+	})
+
+	tests := []struct {
+		name string
+		item *dto.InventoryItemCreationDTO
+		code string
+	}{
+		{"invalid source", &dto.InventoryItemCreationDTO{EquipmentSource: "shop"}, errorcode_enum.CodeBadRequest},
+		{"public source without id", &dto.InventoryItemCreationDTO{EquipmentSource: "public"}, errorcode_enum.CodeBadRequest},
+		{"both ids", &dto.InventoryItemCreationDTO{EquipmentSource: "gym", GymEquipmentID: strPtr("a"), PublicEquipmentID: strPtr("b")}, errorcode_enum.CodeBadRequest},
+		{"negative quantity", &dto.InventoryItemCreationDTO{EquipmentSource: "public", PublicEquipmentID: strPtr("a"), Quantity: -1}, errorcode_enum.CodeBadRequest},
+		{"unknown status", &dto.InventoryItemCreationDTO{EquipmentSource: "public", PublicEquipmentID: strPtr("a"), Status: "lost"}, errorcode_enum.CodeBadRequest},
+		{"bad purchase date", &dto.InventoryItemCreationDTO{EquipmentSource: "public", PublicEquipmentID: strPtr("a"), PurchaseDate: strPtr("03/2024")}, errorcode_enum.CodeBadRequest},
+		{"future purchase date", &dto.InventoryItemCreationDTO{EquipmentSource: "public", PublicEquipmentID: strPtr("a"), PurchaseDate: strPtr("2999-01-01")}, errorcode_enum.CodeBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewEquipmentInventoryService(newMockRepository()).CreateItem("gym1", tc.item)
+			testutil.AssertCode(t, err, tc.code)
+		})
+	}
+
+	t.Run("equipment missing from the catalog", func(t *testing.T) {
+		repo := newMockRepository()
+		repo.equipmentExists = false
+		_, err := NewEquipmentInventoryService(repo).CreateItem("gym1", &dto.InventoryItemCreationDTO{EquipmentSource: "public", PublicEquipmentID: strPtr("a")})
+		testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	})
+}
+
+func TestTicketLifecycle(t *testing.T) {
+	repo := newMockRepository()
+	svc := NewEquipmentInventoryService(repo)
+
+	first, err := svc.OpenTicket("gym1", &dto.MaintenanceTicketCreationDTO{InventoryItemID: "item-1", Title: "Torn cable", OpenedBy: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "out_of_order", repo.items["item-1"].Status)
+
+	second, err := svc.OpenTicket("gym1", &dto.MaintenanceTicketCreationDTO{InventoryItemID: "item-1", Title: "Squeaky pulley", ItemStatus: "in_maintenance", OpenedBy: "user-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.TransitionTicket("gym1", *first, &dto.MaintenanceTicketTransitionDTO{Status: "in_progress", ChangedBy: "user-2"}))
+	assert.Equal(t, "in_maintenance", repo.items["item-1"].Status)
+
+	// Marking the item available by hand is refused while tickets are open
+	available := "available"
+	testutil.AssertCode(t, svc.UpdateItem("gym1", &dto.InventoryItemUpdateDTO{ID: "item-1", Status: &available}), errorcode_enum.CodeConflict)
+
+	// Resolving one ticket keeps the item out of service while the other is open
+	require.NoError(t, svc.TransitionTicket("gym1", *first, &dto.MaintenanceTicketTransitionDTO{Status: "resolved", ChangedBy: "user-2"}))
+	assert.Equal(t, "in_maintenance", repo.items["item-1"].Status)
+
+	require.NoError(t, svc.TransitionTicket("gym1", *second, &dto.MaintenanceTicketTransitionDTO{Status: "resolved", ChangedBy: "user-2"}))
+	assert.Equal(t, "available", repo.items["item-1"].Status)
+
+	// Resolved tickets are final
+	testutil.AssertCode(t, svc.TransitionTicket("gym1", *second, &dto.MaintenanceTicketTransitionDTO{Status: "open"}), errorcode_enum.CodeConflict)
+	testutil.AssertCode(t, svc.TransitionTicket("gym1", *second, &dto.MaintenanceTicketTransitionDTO{Status: "closed"}), errorcode_enum.CodeBadRequest)
+	testutil.AssertCode(t, svc.TransitionTicket("gym1", "missing", &dto.MaintenanceTicketTransitionDTO{Status: "resolved"}), errorcode_enum.CodeNotFound)
+}
+
+func TestOpenTicketValidation(t *testing.T) {
+	svc := NewEquipmentInventoryService(newMockRepository())
+
+	_, err := svc.OpenTicket("gym1", &dto.MaintenanceTicketCreationDTO{InventoryItemID: "item-1", Title: "  ", OpenedBy: "user-1"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	_, err = svc.OpenTicket("gym1", &dto.MaintenanceTicketCreationDTO{InventoryItemID: "item-1", Title: "Broken", ItemStatus: "available", OpenedBy: "user-1"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	_, err = svc.OpenTicket("gym1", &dto.MaintenanceTicketCreationDTO{InventoryItemID: "missing", Title: "Broken", OpenedBy: "user-1"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "encoding/json"
+
+// ExerciseMuscleLinkDTO is a muscular group the exercise works. An empty role is primary and a zero
+// activation weight the role's default.
+type ExerciseMuscleLinkDTO struct {
+	MuscularGroupID  string  `json:"muscular_group_id" validate:"required"`
+	Role             string  `json:"role"`
+	ActivationWeight float64 `json:"activation_weight"`
+}
+
+// UnmarshalJSON also accepts a bare muscular group ID, which links the muscle with the defaults
+func (l *ExerciseMuscleLinkDTO) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*l = ExerciseMuscleLinkDTO{MuscularGroupID: id}
+		return nil
+	}
+	type link ExerciseMuscleLinkDTO
+	return json.Unmarshal(data, (*link)(l))
+}
@@ -0,0 +1,15 @@
+package dto
+
+// ExerciseRevisionSnapshot is the editable state of a public exercise kept in its revision history.
+// Muscle links keep their role and activation weight so a restore brings them back unchanged.
+type ExerciseRevisionSnapshot struct {
+	Name            string                  `json:"name"`
+	Synonyms        []string                `json:"synonyms"`
+	DifficultyLevel string                  `json:"difficulty_level"`
+	ExerciseType    string                  `json:"exercise_type"`
+	Instructions    string                  `json:"instructions"`
+	VideoURL        *string                 `json:"video_url"`
+	ImageURL        *string                 `json:"image_url"`
+	EquipmentIDs    []string                `json:"equipment_ids"`
+	MuscularGroups  []ExerciseMuscleLinkDTO `json:"muscular_groups"`
+}
@@ -3,14 +3,16 @@ package dto
 import "github.com/alejandro-albiol/athenai/internal/exercise/enum"
 
 type ExerciseUpdateDTO struct {
-	Name            *string              `json:"name"`
-	Synonyms        []string             `json:"synonyms"`
-	MuscularGroups  []string             `json:"muscular_groups"`
-	Equipment       []string             `json:"equipment"`
-	DifficultyLevel enum.DifficultyLevel `json:"difficulty_level" validate:"omitempty,oneof=beginner intermediate advanced"`
-	ExerciseType    enum.ExerciseType    `json:"exercise_type" validate:"omitempty,oneof=strength cardio flexibility balance functional"`
-	Instructions    *string              `json:"instructions"`
-	VideoURL        *string              `json:"video_url"`
-	ImageURL        *string              `json:"image_url"`
-	IsActive        *bool                `json:"is_active"`
+	Name            *string                 `json:"name"`
+	Synonyms        []string                `json:"synonyms"`
+	MuscularGroups  []ExerciseMuscleLinkDTO `json:"muscular_groups"` // nil keeps the current links
+	Equipment       []string                `json:"equipment"`       // nil keeps the current links
+	DifficultyLevel enum.DifficultyLevel    `json:"difficulty_level" validate:"omitempty,oneof=beginner intermediate advanced"`
+	ExerciseType    enum.ExerciseType       `json:"exercise_type" validate:"omitempty,oneof=strength cardio flexibility balance functional"`
+	Instructions    *string                 `json:"instructions"`
+	VideoURL        *string                 `json:"video_url"`
+	ImageURL        *string                 `json:"image_url"`
+	IsActive        *bool                   `json:"is_active"`
+	UpdatedBy       string                  `json:"-"` // author of the revision, set from the caller's token
+	ReplaceMedia    bool                    `json:"-"` // a nil VideoURL or ImageURL clears the stored URL, set by restores
 }
@@ -7,18 +7,22 @@ import (
 
 	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
 	"github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
+	translation_enum "github.com/alejandro-albiol/athenai/internal/translation/enum"
+	translationIF "github.com/alejandro-albiol/athenai/internal/translation/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 	"github.com/go-chi/chi/v5"
 )
 
 type ExerciseHandler struct {
-	service interfaces.ExerciseService
+	service   interfaces.ExerciseService
+	localizer translationIF.Localizer
 }
 
-func NewExerciseHandler(service interfaces.ExerciseService) *ExerciseHandler {
-	return &ExerciseHandler{service: service}
+func NewExerciseHandler(service interfaces.ExerciseService, localizer translationIF.Localizer) *ExerciseHandler {
+	return &ExerciseHandler{service: service, localizer: localizer}
 }
 
 func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +55,9 @@ func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request)
 func (h *ExerciseHandler) GetExerciseByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	exercise, err := h.service.GetExerciseByID(id)
+	if err == nil {
+		exercise, err = h.presentOne(w, r, exercise)
+	}
 	if err != nil {
 		var apiErr *apierror.APIError
 		if errors.As(err, &apiErr) {
@@ -70,6 +77,9 @@ func (h *ExerciseHandler) GetExerciseByID(w http.ResponseWriter, r *http.Request
 func (h *ExerciseHandler) GetExerciseByName(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	exercise, err := h.service.GetExerciseByName(name)
+	if err == nil {
+		exercise, err = h.presentOne(w, r, exercise)
+	}
 	if err != nil {
 		var apiErr *apierror.APIError
 		if errors.As(err, &apiErr) {
@@ -89,6 +99,9 @@ func (h *ExerciseHandler) GetExerciseByName(w http.ResponseWriter, r *http.Reque
 func (h *ExerciseHandler) GetExerciseByEquipment(w http.ResponseWriter, r *http.Request) {
 	equipment := r.URL.Query()["equipment"]
 	exercises, err := h.service.GetExercisesByEquipment(equipment)
+	if err == nil {
+		exercises, err = h.present(w, r, exercises)
+	}
 	if err != nil {
 		var apiErr *apierror.APIError
 		if errors.As(err, &apiErr) {
@@ -108,6 +121,9 @@ func (h *ExerciseHandler) GetExerciseByEquipment(w http.ResponseWriter, r *http.
 func (h *ExerciseHandler) GetExerciseByMuscularGroup(w http.ResponseWriter, r *http.Request) {
 	groups := r.URL.Query()["group"]
 	exercises, err := h.service.GetExercisesByMuscularGroup(groups)
+	if err == nil {
+		exercises, err = h.present(w, r, exercises)
+	}
 	if err != nil {
 		var apiErr *apierror.APIError
 		if errors.As(err, &apiErr) {
@@ -126,6 +142,9 @@ func (h *ExerciseHandler) GetExerciseByMuscularGroup(w http.ResponseWriter, r *h
 
 func (h *ExerciseHandler) GetAllExercises(w http.ResponseWriter, r *http.Request) {
 	exercises, err := h.service.GetAllExercises()
+	if err == nil {
+		exercises, err = h.present(w, r, exercises)
+	}
 	if err != nil {
 		var apiErr *apierror.APIError
 		if errors.As(err, &apiErr) {
@@ -153,6 +172,7 @@ func (h *ExerciseHandler) UpdateExercise(w http.ResponseWriter, r *http.Request)
 		))
 		return
 	}
+	updateDTO.UpdatedBy = middleware.GetUserID(r)
 
 	updatedExercise, err := h.service.UpdateExercise(id, updateDTO)
 	if err != nil {
@@ -205,6 +225,9 @@ func (h *ExerciseHandler) GetExercisesByFilters(w http.ResponseWriter, r *http.R
 	} else {
 		exercises, err = h.service.GetAllExercises()
 	}
+	if err == nil {
+		exercises, err = h.present(w, r, exercises)
+	}
 
 	if err != nil {
 		var apiErr *apierror.APIError
@@ -221,3 +244,44 @@ func (h *ExerciseHandler) GetExercisesByFilters(w http.ResponseWriter, r *http.R
 	}
 	response.WriteAPISuccess(w, "Exercises retrieved successfully", exercises)
 }
+
+// present localizes exercises for the caller, then applies their gym's overrides so gym text wins over translations
+func (h *ExerciseHandler) present(w http.ResponseWriter, r *http.Request, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+	if err := h.localize(w, r, exercises); err != nil {
+		return nil, err
+	}
+	return h.service.MergeGymOverrides(middleware.GetGymID(r), exercises)
+}
+
+// presentOne is present for a single exercise; exercises the gym hid are reported as not found
+func (h *ExerciseHandler) presentOne(w http.ResponseWriter, r *http.Request, exercise *dto.ExerciseResponseDTO) (*dto.ExerciseResponseDTO, error) {
+	merged, err := h.present(w, r, []*dto.ExerciseResponseDTO{exercise})
+	if err != nil {
+		return nil, err
+	}
+	if len(merged) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise not found", nil)
+	}
+	return merged[0], nil
+}
+
+func (h *ExerciseHandler) localize(w http.ResponseWriter, r *http.Request, exercises []*dto.ExerciseResponseDTO) error {
+	if h.localizer == nil {
+		return nil
+	}
+	locale := h.localizer.ResolveLocale(middleware.GetGymID(r), middleware.GetUserID(r), r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", string(locale))
+	ids := make([]string, 0, len(exercises))
+	for _, exercise := range exercises {
+		ids = append(ids, exercise.ID)
+	}
+	translations, err := h.localizer.Lookup(locale, translation_enum.Exercise, ids)
+	if err != nil {
+		return err
+	}
+	for _, exercise := range exercises {
+		translations.Apply(exercise.ID, "name", &exercise.Name)
+		translations.Apply(exercise.ID, "instructions", &exercise.Instructions)
+	}
+	return nil
+}
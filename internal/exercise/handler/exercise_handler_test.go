@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	translationDTO "github.com/alejandro-albiol/athenai/internal/translation/dto"
+	translation_enum "github.com/alejandro-albiol/athenai/internal/translation/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -23,6 +26,7 @@ type mockService struct {
 	GetExercisesByEquipmentFunc                 func(equipment []string) ([]*dto.ExerciseResponseDTO, error)
 	GetExercisesByMuscularGroupAndEquipmentFunc func(muscularGroups []string, equipment []string) ([]*dto.ExerciseResponseDTO, error)
 	DeleteExerciseFunc                          func(id string) error
+	MergeGymOverridesFunc                       func(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error)
 }
 
 func (m *mockService) CreateExercise(ex *dto.ExerciseCreationDTO) (*string, error) {
@@ -52,6 +56,12 @@ func (m *mockService) UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO)
 func (m *mockService) DeleteExercise(id string) error {
 	return m.DeleteExerciseFunc(id)
 }
+func (m *mockService) MergeGymOverrides(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+	if m.MergeGymOverridesFunc != nil {
+		return m.MergeGymOverridesFunc(gymID, exercises)
+	}
+	return exercises, nil
+}
 
 func TestExerciseHandler_CreateExercise(t *testing.T) {
 	ts := []struct {
@@ -66,6 +76,18 @@ func TestExerciseHandler_CreateExercise(t *testing.T) {
 			mockFunc:   func(ex *dto.ExerciseCreationDTO) (*string, error) { id := "id1"; return &id, nil },
 			wantStatus: 201,
 		},
+		{
+			name:  "success with link objects",
+			input: `{"name":"Pushup","difficulty_level":"beginner","exercise_type":"strength","synonyms":["press-up"],"muscular_groups":[{"muscular_group_id":"mg1"},{"muscular_group_id":"mg2","role":"secondary","activation_weight":0.4}],"instructions":"Do a pushup","created_by":"tester"}`,
+			mockFunc: func(ex *dto.ExerciseCreationDTO) (*string, error) {
+				if len(ex.MuscularGroups) != 2 || ex.MuscularGroups[1].Role != "secondary" || ex.MuscularGroups[1].ActivationWeight != 0.4 {
+					return nil, errors.New("links not decoded")
+				}
+				id := "id1"
+				return &id, nil
+			},
+			wantStatus: 201,
+		},
 		{
 			name:       "bad request",
 			input:      `{"name":`,
@@ -115,6 +137,74 @@ func TestExerciseHandler_GetExerciseByID(t *testing.T) {
 	}
 }
 
+func TestExerciseHandler_GetExerciseByIDHiddenByGym(t *testing.T) {
+	service := &mockService{
+		GetExerciseByIDFunc: func(id string) (*dto.ExerciseResponseDTO, error) {
+			return &dto.ExerciseResponseDTO{ID: id, Name: "Pushup"}, nil
+		},
+		MergeGymOverridesFunc: func(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+			if gymID != "gym1" {
+				t.Errorf("expected gym1, got %q", gymID)
+			}
+			return []*dto.ExerciseResponseDTO{}, nil
+		},
+	}
+	h := &ExerciseHandler{service: service}
+	req := httptest.NewRequest("GET", "/exercises/id1", nil)
+	req = muxSetParam(req, "id", "id1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.GymIDKey, "gym1"))
+	rw := httptest.NewRecorder()
+	h.GetExerciseByID(rw, req)
+	if rw.Code != 404 {
+		t.Errorf("expected status 404 for an exercise the gym hid, got %d", rw.Code)
+	}
+}
+
+type mockLocalizer struct {
+	acceptLanguage string
+}
+
+func (m *mockLocalizer) ResolveLocale(gymID, userID, acceptLanguage string) translation_enum.Locale {
+	m.acceptLanguage = acceptLanguage
+	return translation_enum.Spanish
+}
+func (m *mockLocalizer) Lookup(locale translation_enum.Locale, entityType translation_enum.EntityType, entityIDs []string) (translationDTO.TranslationSet, error) {
+	return translationDTO.TranslationSet{"id1": {"name": "Flexiones", "instructions": "Baja el pecho"}}, nil
+}
+
+func TestExerciseHandler_GetAllExercisesLocalized(t *testing.T) {
+	service := &mockService{
+		GetAllExercisesFunc: func() ([]*dto.ExerciseResponseDTO, error) {
+			return []*dto.ExerciseResponseDTO{{ID: "id1", Name: "Pushup", Instructions: "Lower your chest"}}, nil
+		},
+		MergeGymOverridesFunc: func(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+			// Gym overrides are applied to the translated exercise
+			if exercises[0].Name != "Flexiones" {
+				t.Errorf("expected translated exercise before the gym overlay, got %q", exercises[0].Name)
+			}
+			copied := *exercises[0]
+			copied.Instructions = "Gym cue"
+			return []*dto.ExerciseResponseDTO{&copied}, nil
+		},
+	}
+	localizer := &mockLocalizer{}
+	h := NewExerciseHandler(service, localizer)
+	req := httptest.NewRequest("GET", "/exercises", nil)
+	req.Header.Set("Accept-Language", "es-ES")
+	rw := httptest.NewRecorder()
+	h.GetAllExercises(rw, req)
+	if rw.Code != 200 {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+	if localizer.acceptLanguage != "es-ES" || rw.Header().Get("Content-Language") != "es" {
+		t.Errorf("expected Spanish from Accept-Language, got header %q", rw.Header().Get("Content-Language"))
+	}
+	body := rw.Body.String()
+	if !strings.Contains(body, "Flexiones") || !strings.Contains(body, "Gym cue") {
+		t.Errorf("expected translated name and gym instructions, got %s", body)
+	}
+}
+
 func TestExerciseHandler_DeleteExercise(t *testing.T) {
 	service := &mockService{
 		DeleteExerciseFunc: func(id string) error {
@@ -1,6 +1,10 @@
 package interfaces
 
-import "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
+)
 
 type ExerciseRepository interface {
 	// Exercise management
@@ -10,6 +14,10 @@ type ExerciseRepository interface {
 	GetExercisesByMuscularGroup(muscularGroups []string) ([]*dto.ExerciseResponseDTO, error)
 	GetExercisesByEquipment(equipment []string) ([]*dto.ExerciseResponseDTO, error)
 	GetAllExercises() ([]*dto.ExerciseResponseDTO, error)
-	UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error)
+	// UpdateExercise writes the row and the provided link lists in one transaction; record, when set, receives the
+	// exercise's snapshots from before and after the change inside that transaction
+	UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error)
+	// GetRevisionSnapshot reads the exercise's versioned state, links included, inside tx
+	GetRevisionSnapshot(tx *sql.Tx, id string) (*dto.ExerciseRevisionSnapshot, error)
 	DeleteExercise(id string) error
 }
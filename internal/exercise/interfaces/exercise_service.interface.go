@@ -13,4 +13,7 @@ type ExerciseService interface {
 	UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error)
 	DeleteExercise(id string) error
 	GetExercisesByMuscularGroupAndEquipment(muscularGroups []string, equipment []string) ([]*dto.ExerciseResponseDTO, error)
+
+	// Gym view of public exercises
+	MergeGymOverrides(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error)
 }
@@ -8,19 +8,31 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/exercise/repository"
 	"github.com/alejandro-albiol/athenai/internal/exercise/router"
 	"github.com/alejandro-albiol/athenai/internal/exercise/service"
-	exerciseMuscularGroupRepository "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/repository"
 	exerciseEquipmentRepository "github.com/alejandro-albiol/athenai/internal/exercise_equipment/repository"
-	exerciseMuscularGroupService "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/service"
 	exerciseEquipmentService "github.com/alejandro-albiol/athenai/internal/exercise_equipment/service"
+	exerciseMediaModule "github.com/alejandro-albiol/athenai/internal/exercise_media/module"
+	exerciseMuscularGroupRepository "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/repository"
+	exerciseMuscularGroupService "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/service"
+	exerciseOverrideModule "github.com/alejandro-albiol/athenai/internal/exercise_override/module"
+	revisionRepository "github.com/alejandro-albiol/athenai/internal/revision/repository"
+	revisionService "github.com/alejandro-albiol/athenai/internal/revision/service"
+	translationModule "github.com/alejandro-albiol/athenai/internal/translation/module"
 )
 
 func NewExerciseModule(db *sql.DB) http.Handler {
+	service := NewExerciseService(db)
+	handler := handler.NewExerciseHandler(service, translationModule.NewTranslationService(db))
+	return router.NewExerciseRouter(handler)
+}
+
+// NewExerciseService builds the service with its link, media, override and revision collaborators
+func NewExerciseService(db *sql.DB) *service.ExerciseService {
 	repo := repository.NewExerciseRepository(db)
 	exerciseMuscularGroupRepository := exerciseMuscularGroupRepository.NewExerciseMuscularGroupRepository(db)
 	exerciseEquipmentRepository := exerciseEquipmentRepository.NewExerciseEquipmentRepository(db)
 	exerciseEquipmentService := exerciseEquipmentService.NewExerciseEquipmentService(exerciseEquipmentRepository)
 	exerciseMuscularGroupService := exerciseMuscularGroupService.NewExerciseMuscularGroupService(exerciseMuscularGroupRepository)
-	service := service.NewExerciseService(repo, exerciseEquipmentService, exerciseMuscularGroupService)
-	handler := handler.NewExerciseHandler(service)
-	return router.NewExerciseRouter(handler)
+	exerciseMediaService := exerciseMediaModule.NewExerciseMediaService(db)
+	recorder := revisionService.NewRevisionService(revisionRepository.NewRevisionRepository(db))
+	return service.NewExerciseService(repo, exerciseEquipmentService, exerciseMuscularGroupService, exerciseMediaService, exerciseOverrideModule.NewExerciseOverrideService(db), recorder)
 }
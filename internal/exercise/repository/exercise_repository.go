@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
 	"github.com/lib/pq"
@@ -15,6 +16,48 @@ func NewExerciseRepository(db *sql.DB) *ExerciseRepository {
 	return &ExerciseRepository{db: db}
 }
 
+// exerciseColumns reads exercise e with its muscle links, primary first, as a JSON array and its equipment IDs
+const exerciseColumns = `e.id, e.name, e.synonyms,
+	       COALESCE((
+		       SELECT json_agg(json_build_object('muscular_group_id', emg.muscular_group_id, 'role', emg.role, 'activation_weight', emg.activation_weight)
+			       ORDER BY emg.role, emg.activation_weight DESC, emg.muscular_group_id)
+		       FROM public.exercise_muscular_group emg WHERE emg.exercise_id = e.id
+	       ), '[]'),
+	       ARRAY(SELECT ee.equipment_id FROM public.exercise_equipment ee WHERE ee.exercise_id = e.id ORDER BY ee.equipment_id),
+	       e.difficulty_level, e.exercise_type, e.instructions, e.video_url, e.image_url, e.created_by, e.is_active, e.created_at, e.updated_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExercise(row scanner) (*dto.ExerciseResponseDTO, error) {
+	exercise := &dto.ExerciseResponseDTO{}
+	var links []byte
+	err := row.Scan(
+		&exercise.ID,
+		&exercise.Name,
+		pq.Array(&exercise.Synonyms),
+		&links,
+		pq.Array(&exercise.EquipmentNeeded),
+		&exercise.DifficultyLevel,
+		&exercise.ExerciseType,
+		&exercise.Instructions,
+		&exercise.VideoURL,
+		&exercise.ImageURL,
+		&exercise.CreatedBy,
+		&exercise.IsActive,
+		&exercise.CreatedAt,
+		&exercise.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(links, &exercise.MuscularGroups); err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
 func (r *ExerciseRepository) CreateExercise(exercise *dto.ExerciseCreationDTO) (*string, error) {
 	query := `
 	       INSERT INTO public.exercise (
@@ -40,93 +83,36 @@ func (r *ExerciseRepository) CreateExercise(exercise *dto.ExerciseCreationDTO) (
 }
 
 func (r *ExerciseRepository) GetExerciseByID(id string) (*dto.ExerciseResponseDTO, error) {
-	query := `SELECT id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at FROM public.exercise WHERE id = $1 AND is_active = TRUE`
-	var exercise dto.ExerciseResponseDTO
-	err := r.db.QueryRow(query, id).Scan(
-		&exercise.ID,
-		&exercise.Name,
-		pq.Array(&exercise.Synonyms),
-		pq.Array(&exercise.MuscularGroups),
-		pq.Array(&exercise.EquipmentNeeded),
-		&exercise.DifficultyLevel,
-		&exercise.ExerciseType,
-		&exercise.Instructions,
-		&exercise.VideoURL,
-		&exercise.ImageURL,
-		&exercise.CreatedBy,
-		&exercise.IsActive,
-		&exercise.CreatedAt,
-		&exercise.UpdatedAt,
-	)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, sql.ErrNoRows
-		}
-		return nil, err
-	}
-	return &exercise, nil
+	query := `SELECT ` + exerciseColumns + ` FROM public.exercise e WHERE e.id = $1 AND e.is_active = TRUE`
+	return scanExercise(r.db.QueryRow(query, id))
 }
 
 func (r *ExerciseRepository) GetExerciseByName(name string) (*dto.ExerciseResponseDTO, error) {
-	exercise := &dto.ExerciseResponseDTO{}
-	query := `SELECT id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at FROM public.exercise WHERE name = $1 AND is_active = TRUE`
-	err := r.db.QueryRow(query, name).Scan(
-		&exercise.ID,
-		&exercise.Name,
-		pq.Array(&exercise.Synonyms),
-		pq.Array(&exercise.MuscularGroups),
-		pq.Array(&exercise.EquipmentNeeded),
-		&exercise.DifficultyLevel,
-		&exercise.ExerciseType,
-		&exercise.Instructions,
-		&exercise.VideoURL,
-		&exercise.ImageURL,
-		&exercise.CreatedBy,
-		&exercise.IsActive,
-		&exercise.CreatedAt,
-		&exercise.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return exercise, nil
+	query := `SELECT ` + exerciseColumns + ` FROM public.exercise e WHERE e.name = $1 AND e.is_active = TRUE`
+	return scanExercise(r.db.QueryRow(query, name))
 }
 
 func (r *ExerciseRepository) GetAllExercises() ([]*dto.ExerciseResponseDTO, error) {
-	query := `SELECT id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at FROM public.exercise WHERE is_active = TRUE`
-	rows, err := r.db.Query(query)
+	query := `SELECT ` + exerciseColumns + ` FROM public.exercise e WHERE e.is_active = TRUE`
+	return r.queryExercises(query)
+}
+
+// UpdateExercise updates the row and replaces each provided link list in one transaction, so a failed link or
+// revision write leaves the exercise as it was. A nil list keeps the current links.
+func (r *ExerciseRepository) UpdateExercise(id string, update *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	var exercises []*dto.ExerciseResponseDTO
-	for rows.Next() {
-		exercise := &dto.ExerciseResponseDTO{}
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.Name,
-			pq.Array(&exercise.Synonyms),
-			pq.Array(&exercise.MuscularGroups),
-			pq.Array(&exercise.EquipmentNeeded),
-			&exercise.DifficultyLevel,
-			&exercise.ExerciseType,
-			&exercise.Instructions,
-			&exercise.VideoURL,
-			&exercise.ImageURL,
-			&exercise.CreatedBy,
-			&exercise.IsActive,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
-		if err != nil {
+	defer tx.Rollback()
+
+	var before *dto.ExerciseRevisionSnapshot
+	if record != nil {
+		if before, err = r.GetRevisionSnapshot(tx, id); err != nil {
 			return nil, err
 		}
-		exercises = append(exercises, exercise)
 	}
-	return exercises, nil
-}
 
-func (r *ExerciseRepository) UpdateExercise(id string, update *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error) {
 	query := `
 	       UPDATE public.exercise SET
 		       name = COALESCE($2, name),
@@ -134,14 +120,13 @@ func (r *ExerciseRepository) UpdateExercise(id string, update *dto.ExerciseUpdat
 		       difficulty_level = COALESCE($4, difficulty_level),
 		       exercise_type = COALESCE($5, exercise_type),
 		       instructions = COALESCE($6, instructions),
-		       video_url = COALESCE($7, video_url),
-		       image_url = COALESCE($8, image_url),
+		       video_url = CASE WHEN $10 THEN $7 ELSE COALESCE($7, video_url) END,
+		       image_url = CASE WHEN $10 THEN $8 ELSE COALESCE($8, image_url) END,
 		       is_active = COALESCE($9, is_active),
 		       updated_at = NOW()
 	       WHERE id = $1
-	       RETURNING id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at`
-	exercise := &dto.ExerciseResponseDTO{}
-	err := r.db.QueryRow(query,
+	       RETURNING id`
+	err = tx.QueryRow(query,
 		id,
 		update.Name,
 		pq.Array(update.Synonyms),
@@ -151,104 +136,145 @@ func (r *ExerciseRepository) UpdateExercise(id string, update *dto.ExerciseUpdat
 		update.VideoURL,
 		update.ImageURL,
 		update.IsActive,
-	).Scan(
-		&exercise.ID,
-		&exercise.Name,
-		pq.Array(&exercise.Synonyms),
-		pq.Array(&exercise.MuscularGroups),
-		pq.Array(&exercise.EquipmentNeeded),
-		&exercise.DifficultyLevel,
-		&exercise.ExerciseType,
-		&exercise.Instructions,
-		&exercise.VideoURL,
-		&exercise.ImageURL,
-		&exercise.CreatedBy,
-		&exercise.IsActive,
-		&exercise.CreatedAt,
-		&exercise.UpdatedAt,
-	)
+		update.ReplaceMedia,
+	).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
+
+	if update.Equipment != nil {
+		if _, err := tx.Exec(`DELETE FROM public.exercise_equipment WHERE exercise_id = $1`, id); err != nil {
+			return nil, err
+		}
+		for _, equipmentID := range update.Equipment {
+			if _, err := tx.Exec(`INSERT INTO public.exercise_equipment (exercise_id, equipment_id) VALUES ($1, $2)`, id, equipmentID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if update.MuscularGroups != nil {
+		if _, err := tx.Exec(`DELETE FROM public.exercise_muscular_group WHERE exercise_id = $1`, id); err != nil {
+			return nil, err
+		}
+		for _, link := range update.MuscularGroups {
+			_, err := tx.Exec(`INSERT INTO public.exercise_muscular_group (exercise_id, muscular_group_id, role, activation_weight) VALUES ($1, $2, $3, $4)`,
+				id, link.MuscularGroupID, link.Role, link.ActivationWeight)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Read back once the links are written, so the response carries the new ones
+	exercise, err := scanExercise(tx.QueryRow(`SELECT `+exerciseColumns+` FROM public.exercise e WHERE e.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if record != nil {
+		after, err := r.GetRevisionSnapshot(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := record(tx, before, after); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return exercise, nil
 }
 
-func (r *ExerciseRepository) DeleteExercise(id string) error {
-	query := `UPDATE public.exercise SET is_active = FALSE, updated_at = NOW() WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
-}
+// GetRevisionSnapshot reads the exercise's editable fields with its equipment and muscular group links inside tx,
+// so a revision stores exactly what the transaction wrote. Links are ordered by ID to keep snapshots comparable.
+func (r *ExerciseRepository) GetRevisionSnapshot(tx *sql.Tx, id string) (*dto.ExerciseRevisionSnapshot, error) {
+	snapshot := &dto.ExerciseRevisionSnapshot{EquipmentIDs: []string{}, MuscularGroups: []dto.ExerciseMuscleLinkDTO{}}
+	query := `SELECT name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url FROM public.exercise WHERE id = $1`
+	err := tx.QueryRow(query, id).Scan(
+		&snapshot.Name,
+		pq.Array(&snapshot.Synonyms),
+		&snapshot.DifficultyLevel,
+		&snapshot.ExerciseType,
+		&snapshot.Instructions,
+		&snapshot.VideoURL,
+		&snapshot.ImageURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Synonyms == nil {
+		snapshot.Synonyms = []string{}
+	}
 
-func (r *ExerciseRepository) GetExercisesByMuscularGroup(muscularGroups []string) ([]*dto.ExerciseResponseDTO, error) {
-	query := `SELECT e.id, e.name, e.synonyms, e.muscular_groups, e.equipment_needed, e.difficulty_level, e.exercise_type, e.instructions, e.video_url, e.image_url, e.created_by, e.is_active, e.created_at, e.updated_at
-	       FROM public.exercise e
-	       JOIN public.exercise_muscular_group emg ON e.id = emg.exercise_id
-	       WHERE e.is_active = TRUE AND emg.muscular_group_id = ANY($1)`
-	rows, err := r.db.Query(query, pq.Array(muscularGroups))
+	rows, err := tx.Query(`SELECT equipment_id FROM public.exercise_equipment WHERE exercise_id = $1 ORDER BY equipment_id`, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var exercises []*dto.ExerciseResponseDTO
 	for rows.Next() {
-		exercise := &dto.ExerciseResponseDTO{}
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.Name,
-			pq.Array(&exercise.Synonyms),
-			pq.Array(&exercise.MuscularGroups),
-			pq.Array(&exercise.EquipmentNeeded),
-			&exercise.DifficultyLevel,
-			&exercise.ExerciseType,
-			&exercise.Instructions,
-			&exercise.VideoURL,
-			&exercise.ImageURL,
-			&exercise.CreatedBy,
-			&exercise.IsActive,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
-		if err != nil {
+		var equipmentID string
+		if err := rows.Scan(&equipmentID); err != nil {
 			return nil, err
 		}
-		exercises = append(exercises, exercise)
+		snapshot.EquipmentIDs = append(snapshot.EquipmentIDs, equipmentID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	linkRows, err := tx.Query(`SELECT muscular_group_id, role, activation_weight FROM public.exercise_muscular_group WHERE exercise_id = $1 ORDER BY muscular_group_id`, id)
+	if err != nil {
+		return nil, err
 	}
-	return exercises, nil
+	defer linkRows.Close()
+	for linkRows.Next() {
+		var link dto.ExerciseMuscleLinkDTO
+		if err := linkRows.Scan(&link.MuscularGroupID, &link.Role, &link.ActivationWeight); err != nil {
+			return nil, err
+		}
+		snapshot.MuscularGroups = append(snapshot.MuscularGroups, link)
+	}
+	return snapshot, linkRows.Err()
+}
+
+func (r *ExerciseRepository) DeleteExercise(id string) error {
+	query := `UPDATE public.exercise SET is_active = FALSE, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *ExerciseRepository) GetExercisesByMuscularGroup(muscularGroups []string) ([]*dto.ExerciseResponseDTO, error) {
+	query := `SELECT ` + exerciseColumns + `
+	       FROM public.exercise e
+	       WHERE e.is_active = TRUE AND EXISTS (
+		       SELECT 1 FROM public.exercise_muscular_group emg WHERE emg.exercise_id = e.id AND emg.muscular_group_id = ANY($1)
+	       )`
+	return r.queryExercises(query, pq.Array(muscularGroups))
 }
 
 func (r *ExerciseRepository) GetExercisesByEquipment(equipment []string) ([]*dto.ExerciseResponseDTO, error) {
-	query := `SELECT e.id, e.name, e.synonyms, e.muscular_groups, e.equipment_needed, e.difficulty_level, e.exercise_type, e.instructions, e.video_url, e.image_url, e.created_by, e.is_active, e.created_at, e.updated_at
+	query := `SELECT ` + exerciseColumns + `
 	       FROM public.exercise e
-	       JOIN public.exercise_equipment ee ON e.id = ee.exercise_id
-	       WHERE e.is_active = TRUE AND ee.equipment_id = ANY($1)`
-	rows, err := r.db.Query(query, pq.Array(equipment))
+	       WHERE e.is_active = TRUE AND EXISTS (
+		       SELECT 1 FROM public.exercise_equipment ee WHERE ee.exercise_id = e.id AND ee.equipment_id = ANY($1)
+	       )`
+	return r.queryExercises(query, pq.Array(equipment))
+}
+
+func (r *ExerciseRepository) queryExercises(query string, args ...any) ([]*dto.ExerciseResponseDTO, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var exercises []*dto.ExerciseResponseDTO
 	for rows.Next() {
-		exercise := &dto.ExerciseResponseDTO{}
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.Name,
-			pq.Array(&exercise.Synonyms),
-			pq.Array(&exercise.MuscularGroups),
-			pq.Array(&exercise.EquipmentNeeded),
-			&exercise.DifficultyLevel,
-			&exercise.ExerciseType,
-			&exercise.Instructions,
-			&exercise.VideoURL,
-			&exercise.ImageURL,
-			&exercise.CreatedBy,
-			&exercise.IsActive,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
+		exercise, err := scanExercise(rows)
 		if err != nil {
 			return nil, err
 		}
 		exercises = append(exercises, exercise)
 	}
-	return exercises, nil
+	return exercises, rows.Err()
 }
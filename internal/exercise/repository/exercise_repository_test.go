@@ -21,6 +21,12 @@ func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *ExerciseRepository) {
 	return db, mock, repo
 }
 
+var exerciseRow = []string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type",
+	"instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}
+
+var pushUpLinks = []byte(`[{"muscular_group_id": "chest", "role": "primary", "activation_weight": 1.00},
+	{"muscular_group_id": "triceps", "role": "secondary", "activation_weight": 0.50}]`)
+
 func TestCreateExercise(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
@@ -28,7 +34,7 @@ func TestCreateExercise(t *testing.T) {
 	ex := &dto.ExerciseCreationDTO{
 		Name:            "Push Up",
 		Synonyms:        []string{"Press Up"},
-		MuscularGroups:  []string{"chest", "triceps"},
+		MuscularGroups:  []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "chest"}, {MuscularGroupID: "triceps", Role: "secondary"}},
 		Equipment:       []string{},
 		DifficultyLevel: "beginner",
 		ExerciseType:    "strength",
@@ -62,14 +68,18 @@ func TestGetExerciseByID(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 	now := time.Now()
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at FROM public.exercise WHERE id = $1 AND is_active = TRUE`)).
+	mock.ExpectQuery(`FROM public.exercise_muscular_group emg WHERE emg.exercise_id = e.id .* FROM public.exercise e WHERE e.id = \$1 AND e.is_active = TRUE`).
 		WithArgs("exercise-uuid").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}).
-			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pq.StringArray{"chest", "triceps"}, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
+		WillReturnRows(sqlmock.NewRows(exerciseRow).
+			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pushUpLinks, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
 
 	ex, err := repo.GetExerciseByID("exercise-uuid")
 	assert.NoError(t, err)
 	assert.Equal(t, "Push Up", ex.Name)
+	assert.Equal(t, []dto.ExerciseMuscleLinkDTO{
+		{MuscularGroupID: "chest", Role: "primary", ActivationWeight: 1},
+		{MuscularGroupID: "triceps", Role: "secondary", ActivationWeight: 0.5},
+	}, ex.MuscularGroups)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -77,9 +87,9 @@ func TestGetAllExercises(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 	now := time.Now()
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, synonyms, muscular_groups, equipment_needed, difficulty_level, exercise_type, instructions, video_url, image_url, created_by, is_active, created_at, updated_at FROM public.exercise WHERE is_active = TRUE`)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}).
-			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pq.StringArray{"chest", "triceps"}, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
+	mock.ExpectQuery(`FROM public.exercise e WHERE e.is_active = TRUE$`).
+		WillReturnRows(sqlmock.NewRows(exerciseRow).
+			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pushUpLinks, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
 
 	exs, err := repo.GetAllExercises()
 	assert.NoError(t, err)
@@ -95,7 +105,7 @@ func TestUpdateExercise(t *testing.T) {
 	update := &dto.ExerciseUpdateDTO{
 		Name:            ptrString("Push Up Updated"),
 		Synonyms:        []string{"Press Up"},
-		MuscularGroups:  []string{"chest", "triceps"},
+		MuscularGroups:  []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "chest", Role: "primary", ActivationWeight: 1}, {MuscularGroupID: "triceps", Role: "secondary", ActivationWeight: 0.5}},
 		DifficultyLevel: "beginner",
 		ExerciseType:    "strength",
 		Instructions:    ptrString("Do a push up better."),
@@ -104,6 +114,7 @@ func TestUpdateExercise(t *testing.T) {
 		IsActive:        ptrBool(true),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE public.exercise SET`)).
 		WithArgs(
 			"exercise-uuid",
@@ -115,13 +126,92 @@ func TestUpdateExercise(t *testing.T) {
 			update.VideoURL,
 			update.ImageURL,
 			update.IsActive,
+			false,
 		).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}).
-			AddRow("exercise-uuid", "Push Up Updated", pq.StringArray{"Press Up"}, pq.StringArray{"chest", "triceps"}, pq.StringArray{}, "beginner", "strength", "Do a push up better.", nil, nil, "admin-uuid", true, now, now))
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exercise-uuid"))
+	// Only the provided muscle links are replaced; equipment was omitted and keeps its links
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM public.exercise_muscular_group WHERE exercise_id = $1`)).
+		WithArgs("exercise-uuid").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO public.exercise_muscular_group`)).
+		WithArgs("exercise-uuid", "chest", "primary", 1.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO public.exercise_muscular_group`)).
+		WithArgs("exercise-uuid", "triceps", "secondary", 0.5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The response is read after the links are written
+	mock.ExpectQuery(`FROM public.exercise e WHERE e.id = \$1$`).
+		WithArgs("exercise-uuid").
+		WillReturnRows(sqlmock.NewRows(exerciseRow).
+			AddRow("exercise-uuid", "Push Up Updated", pq.StringArray{"Press Up"}, pushUpLinks, pq.StringArray{}, "beginner", "strength", "Do a push up better.", nil, nil, "admin-uuid", true, now, now))
+	mock.ExpectCommit()
 
-	ex, err := repo.UpdateExercise("exercise-uuid", update)
+	ex, err := repo.UpdateExercise("exercise-uuid", update, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, "Push Up Updated", ex.Name)
+	assert.Equal(t, "triceps", ex.MuscularGroups[1].MuscularGroupID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectRevisionSnapshot(mock sqlmock.Sqlmock, name string, videoURL any, role string, weight float64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url FROM public.exercise WHERE id = $1`)).
+		WithArgs("exercise-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "synonyms", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url"}).
+			AddRow(name, pq.StringArray{}, "beginner", "strength", "Keep the core tight.", videoURL, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT equipment_id FROM public.exercise_equipment`)).
+		WithArgs("exercise-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"equipment_id"}).AddRow("mat"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT muscular_group_id, role, activation_weight FROM public.exercise_muscular_group`)).
+		WithArgs("exercise-uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"muscular_group_id", "role", "activation_weight"}).AddRow("chest", role, weight))
+}
+
+func TestUpdateExerciseRecordsRevisionInTransaction(t *testing.T) {
+	db, mock, repo := setupMockDB(t)
+	defer db.Close()
+	now := time.Now()
+	update := &dto.ExerciseUpdateDTO{
+		MuscularGroups: []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "chest", Role: "secondary", ActivationWeight: 0.6}},
+		ReplaceMedia:   true,
+	}
+
+	expectUpdate := func() {
+		mock.ExpectBegin()
+		expectRevisionSnapshot(mock, "Push Up", "https://cdn.example.com/push-up.mp4", "primary", 1)
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE public.exercise SET`)).
+			WithArgs("exercise-uuid", nil, sqlmock.AnyArg(), "", "", nil, nil, nil, nil, true).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exercise-uuid"))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM public.exercise_muscular_group`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO public.exercise_muscular_group`)).
+			WithArgs("exercise-uuid", "chest", "secondary", 0.6).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`FROM public.exercise e WHERE e.id = \$1$`).
+			WithArgs("exercise-uuid").
+			WillReturnRows(sqlmock.NewRows(exerciseRow).
+				AddRow("exercise-uuid", "Push Up", pq.StringArray{}, []byte(`[{"muscular_group_id": "chest", "role": "secondary", "activation_weight": 0.60}]`), pq.StringArray{}, "beginner", "strength", "Keep the core tight.", nil, nil, "admin-uuid", true, now, now))
+		expectRevisionSnapshot(mock, "Push Up", nil, "secondary", 0.6)
+	}
+
+	expectUpdate()
+	mock.ExpectCommit()
+	var before, after *dto.ExerciseRevisionSnapshot
+	_, err := repo.UpdateExercise("exercise-uuid", update, func(tx *sql.Tx, b, a *dto.ExerciseRevisionSnapshot) error {
+		before, after = b, a
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/push-up.mp4", *before.VideoURL)
+	assert.Nil(t, after.VideoURL)
+	assert.Equal(t, []string{"mat"}, after.EquipmentIDs)
+	assert.Equal(t, []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "chest", Role: "secondary", ActivationWeight: 0.6}}, after.MuscularGroups)
+
+	// A failing revision rolls the whole update back
+	expectUpdate()
+	mock.ExpectRollback()
+	_, err = repo.UpdateExercise("exercise-uuid", update, func(tx *sql.Tx, b, a *dto.ExerciseRevisionSnapshot) error {
+		return sql.ErrConnDone
+	})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -143,13 +233,10 @@ func TestGetExercisesByMuscularGroup(t *testing.T) {
 	defer db.Close()
 	now := time.Now()
 	groups := []string{"chest", "triceps"}
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT e.id, e.name, e.synonyms, e.muscular_groups, e.equipment_needed, e.difficulty_level, e.exercise_type, e.instructions, e.video_url, e.image_url, e.created_by, e.is_active, e.created_at, e.updated_at
-	   FROM public.exercise e
-	   JOIN public.exercise_muscular_group emg ON e.id = emg.exercise_id
-	   WHERE e.is_active = TRUE AND emg.muscular_group_id = ANY($1)`)).
+	mock.ExpectQuery(`WHERE e.is_active = TRUE AND EXISTS \(\s+SELECT 1 FROM public.exercise_muscular_group emg WHERE emg.exercise_id = e.id AND emg.muscular_group_id = ANY\(\$1\)`).
 		WithArgs(pq.Array(groups)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}).
-			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pq.StringArray{"chest", "triceps"}, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
+		WillReturnRows(sqlmock.NewRows(exerciseRow).
+			AddRow("exercise-uuid", "Push Up", pq.StringArray{"Press Up"}, pushUpLinks, pq.StringArray{}, "beginner", "strength", "Do a push up.", nil, nil, "admin-uuid", true, now, now))
 
 	exs, err := repo.GetExercisesByMuscularGroup(groups)
 	assert.NoError(t, err)
@@ -163,13 +250,10 @@ func TestGetExercisesByEquipment(t *testing.T) {
 	defer db.Close()
 	now := time.Now()
 	equip := []string{"barbell"}
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT e.id, e.name, e.synonyms, e.muscular_groups, e.equipment_needed, e.difficulty_level, e.exercise_type, e.instructions, e.video_url, e.image_url, e.created_by, e.is_active, e.created_at, e.updated_at
-	   FROM public.exercise e
-	   JOIN public.exercise_equipment ee ON e.id = ee.exercise_id
-	   WHERE e.is_active = TRUE AND ee.equipment_id = ANY($1)`)).
+	mock.ExpectQuery(`WHERE e.is_active = TRUE AND EXISTS \(\s+SELECT 1 FROM public.exercise_equipment ee WHERE ee.exercise_id = e.id AND ee.equipment_id = ANY\(\$1\)`).
 		WithArgs(pq.Array(equip)).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "muscular_groups", "equipment_needed", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "created_by", "is_active", "created_at", "updated_at"}).
-			AddRow("exercise-uuid", "Barbell Curl", pq.StringArray{"Curl"}, pq.StringArray{"biceps"}, pq.StringArray{"barbell"}, "beginner", "strength", "Do a curl.", nil, nil, "admin-uuid", true, now, now))
+		WillReturnRows(sqlmock.NewRows(exerciseRow).
+			AddRow("exercise-uuid", "Barbell Curl", pq.StringArray{"Curl"}, []byte(`[{"muscular_group_id": "biceps", "role": "primary", "activation_weight": 1.00}]`), pq.StringArray{"barbell"}, "beginner", "strength", "Do a curl.", nil, nil, "admin-uuid", true, now, now))
 
 	exs, err := repo.GetExercisesByEquipment(equip)
 	assert.NoError(t, err)
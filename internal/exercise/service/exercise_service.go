@@ -2,14 +2,21 @@ package service
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 
 	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise/enum"
 	"github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
 	exerciseEquipmentDTO "github.com/alejandro-albiol/athenai/internal/exercise_equipment/dto"
 	equipmentIF "github.com/alejandro-albiol/athenai/internal/exercise_equipment/interfaces"
+	mediaIF "github.com/alejandro-albiol/athenai/internal/exercise_media/interfaces"
 	exerciseMuscularGroupDTO "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/dto"
+	involvementEnum "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/enum"
 	muscularGroupIF "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/interfaces"
+	overrideIF "github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
+	revisionIF "github.com/alejandro-albiol/athenai/internal/revision/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
 )
@@ -18,13 +25,19 @@ type ExerciseService struct {
 	repository                   interfaces.ExerciseRepository
 	exerciseEquipmentService     equipmentIF.ExerciseEquipmentService
 	exerciseMuscularGroupService muscularGroupIF.ExerciseMuscularGroupService
+	mediaCleaner                 mediaIF.MediaCleaner
+	overlay                      overrideIF.ExerciseOverlay
+	recorder                     revisionIF.Recorder
 }
 
-func NewExerciseService(repo interfaces.ExerciseRepository, equipmentService equipmentIF.ExerciseEquipmentService, muscularGroupService muscularGroupIF.ExerciseMuscularGroupService) *ExerciseService {
+func NewExerciseService(repo interfaces.ExerciseRepository, equipmentService equipmentIF.ExerciseEquipmentService, muscularGroupService muscularGroupIF.ExerciseMuscularGroupService, mediaCleaner mediaIF.MediaCleaner, overlay overrideIF.ExerciseOverlay, recorder revisionIF.Recorder) *ExerciseService {
 	return &ExerciseService{
 		repository:                   repo,
 		exerciseEquipmentService:     equipmentService,
 		exerciseMuscularGroupService: muscularGroupService,
+		mediaCleaner:                 mediaCleaner,
+		overlay:                      overlay,
+		recorder:                     recorder,
 	}
 
 }
@@ -51,6 +64,9 @@ func (s *ExerciseService) CreateExercise(exercise *dto.ExerciseCreationDTO) (*st
 		}
 		seen[syn] = struct{}{}
 	}
+	if err := normalizeMuscleLinks(exercise.MuscularGroups); err != nil {
+		return nil, err
+	}
 	existingExercise, err := s.repository.GetExerciseByName(exercise.Name)
 	if err == nil && existingExercise.ID != "" {
 		return nil, apierror.New(errorcode_enum.CodeConflict, "Exercise with this name already exists", err)
@@ -74,12 +90,8 @@ func (s *ExerciseService) CreateExercise(exercise *dto.ExerciseCreationDTO) (*st
 		}
 	}
 	if s.exerciseMuscularGroupService != nil && len(exercise.MuscularGroups) > 0 {
-		for _, mgID := range exercise.MuscularGroups {
-			link := &exerciseMuscularGroupDTO.ExerciseMuscularGroup{
-				ExerciseID:      *id,
-				MuscularGroupID: mgID,
-			}
-			_, err := s.exerciseMuscularGroupService.CreateLink(link)
+		for _, mg := range exercise.MuscularGroups {
+			_, err := s.exerciseMuscularGroupService.CreateLink(muscleLink(*id, mg))
 			if err != nil {
 				return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to link muscular group to exercise", err)
 			}
@@ -143,7 +155,19 @@ func (s *ExerciseService) GetAllExercises() ([]*dto.ExerciseResponseDTO, error)
 	return exercises, nil
 }
 
+// UpdateExercise applies the update and, when revisions are tracked, records the new state with its author
+// in the same transaction
 func (s *ExerciseService) UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error) {
+	var record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error
+	if s.recorder != nil {
+		record = func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error {
+			return s.recorder.Record(tx, revisionEnum.Exercise, id, before, after, exercise.UpdatedBy)
+		}
+	}
+	return s.applyUpdate(id, exercise, record)
+}
+
+func (s *ExerciseService) applyUpdate(id string, exercise *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
 	// Check existence first
 	_, err := s.repository.GetExerciseByID(id)
 	if err != nil {
@@ -169,42 +193,81 @@ func (s *ExerciseService) UpdateExercise(id string, exercise *dto.ExerciseUpdate
 			seen[syn] = struct{}{}
 		}
 	}
-	updatedExercise, err := s.repository.UpdateExercise(id, exercise)
+	if err := normalizeMuscleLinks(exercise.MuscularGroups); err != nil {
+		return nil, err
+	}
+	// The row and each provided link list are written together; an omitted list keeps the current links
+	updatedExercise, err := s.repository.UpdateExercise(id, exercise, record)
 	if err != nil {
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update exercise", err)
 	}
-	// Update join tables: remove all links, then add new ones if provided
-	if s.exerciseEquipmentService != nil {
-		_ = s.exerciseEquipmentService.RemoveAllLinksForExercise(id)
-		if exercise.Equipment != nil {
-			for _, eqID := range exercise.Equipment {
-				link := &exerciseEquipmentDTO.ExerciseEquipment{
-					ExerciseID:  id,
-					EquipmentID: eqID,
-				}
-				_, err := s.exerciseEquipmentService.CreateLink(link)
-				if err != nil {
-					return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update equipment links", err)
-				}
-			}
+	return updatedExercise, nil
+}
+
+// normalizeMuscleLinks defaults and validates the role and weight of each link before anything is written
+func normalizeMuscleLinks(links []dto.ExerciseMuscleLinkDTO) error {
+	seen := make(map[string]struct{}, len(links))
+	for i := range links {
+		link := &links[i]
+		if link.MuscularGroupID == "" {
+			return apierror.New(errorcode_enum.CodeBadRequest, "Muscular group links need a muscular_group_id", nil)
 		}
-	}
-	if s.exerciseMuscularGroupService != nil {
-		_ = s.exerciseMuscularGroupService.RemoveAllLinksForExercise(id)
-		if exercise.MuscularGroups != nil {
-			for _, mgID := range exercise.MuscularGroups {
-				link := &exerciseMuscularGroupDTO.ExerciseMuscularGroup{
-					ExerciseID:      id,
-					MuscularGroupID: mgID,
-				}
-				_, err := s.exerciseMuscularGroupService.CreateLink(link)
-				if err != nil {
-					return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update muscular group links", err)
-				}
-			}
+		if _, exists := seen[link.MuscularGroupID]; exists {
+			return apierror.New(errorcode_enum.CodeBadRequest, "Muscular groups must be unique", nil)
+		}
+		seen[link.MuscularGroupID] = struct{}{}
+		var msg string
+		if link.Role, link.ActivationWeight, msg = involvementEnum.NormalizeInvolvement(link.Role, link.ActivationWeight); msg != "" {
+			return apierror.New(errorcode_enum.CodeBadRequest, msg, nil)
 		}
 	}
-	return updatedExercise, nil
+	return nil
+}
+
+func muscleLink(exerciseID string, link dto.ExerciseMuscleLinkDTO) *exerciseMuscularGroupDTO.ExerciseMuscularGroup {
+	return &exerciseMuscularGroupDTO.ExerciseMuscularGroup{
+		ExerciseID:       exerciseID,
+		MuscularGroupID:  link.MuscularGroupID,
+		Role:             link.Role,
+		ActivationWeight: link.ActivationWeight,
+	}
+}
+
+// RestoreSnapshot writes a revision snapshot back to the exercise, media URLs and muscle link roles and weights
+// included; record stores the restore in the same transaction
+func (s *ExerciseService) RestoreSnapshot(id string, snapshot json.RawMessage, record func(tx *sql.Tx, before, after any) error) error {
+	var restored dto.ExerciseRevisionSnapshot
+	if err := json.Unmarshal(snapshot, &restored); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Stored exercise revision is unreadable", err)
+	}
+	muscularGroups := restored.MuscularGroups
+	if muscularGroups == nil {
+		muscularGroups = []dto.ExerciseMuscleLinkDTO{}
+	}
+	update := &dto.ExerciseUpdateDTO{
+		Name:            &restored.Name,
+		Synonyms:        nonNil(restored.Synonyms),
+		Equipment:       nonNil(restored.EquipmentIDs),
+		MuscularGroups:  muscularGroups,
+		DifficultyLevel: enum.DifficultyLevel(restored.DifficultyLevel),
+		ExerciseType:    enum.ExerciseType(restored.ExerciseType),
+		Instructions:    &restored.Instructions,
+		VideoURL:        restored.VideoURL,
+		ImageURL:        restored.ImageURL,
+		ReplaceMedia:    true,
+	}
+	_, err := s.applyUpdate(id, update, func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error {
+		return record(tx, before, after)
+	})
+	return err
+}
+
+// nonNil keeps an empty list from reading as "not provided" in updates
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
 }
 
 func (s *ExerciseService) DeleteExercise(id string) error {
@@ -224,9 +287,24 @@ func (s *ExerciseService) DeleteExercise(id string) error {
 	if s.exerciseMuscularGroupService != nil {
 		_ = s.exerciseMuscularGroupService.RemoveAllLinksForExercise(id)
 	}
+	// Uploaded images and videos are removed from storage, not just hidden
+	if s.mediaCleaner != nil {
+		if err := s.mediaCleaner.CleanupExerciseMedia("", id); err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Exercise deleted but its media could not be removed", err)
+		}
+	}
 	return nil
 }
 
+// MergeGymOverrides returns the exercises as the gym sees them: overridden fields replaced and hidden exercises dropped.
+// Without a gym the upstream exercises are returned unchanged.
+func (s *ExerciseService) MergeGymOverrides(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+	if s.overlay == nil || gymID == "" {
+		return exercises, nil
+	}
+	return s.overlay.ApplyOverrides(gymID, exercises)
+}
+
 func (s *ExerciseService) GetExercisesByMuscularGroupAndEquipment(muscularGroups []string, equipment []string) ([]*dto.ExerciseResponseDTO, error) {
 	if len(muscularGroups) == 0 && len(equipment) == 0 {
 		return s.GetAllExercises()
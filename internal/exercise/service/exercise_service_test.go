@@ -1,10 +1,13 @@
 package service
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
 
 	exerciseEquipmentDTO "github.com/alejandro-albiol/athenai/internal/exercise_equipment/dto"
 
@@ -19,7 +22,8 @@ type mockRepository struct {
 	GetExercisesByMuscularGroupFunc func(muscularGroups []string) ([]*dto.ExerciseResponseDTO, error)
 	GetExercisesByEquipmentFunc     func(equipment []string) ([]*dto.ExerciseResponseDTO, error)
 	GetAllExercisesFunc             func() ([]*dto.ExerciseResponseDTO, error)
-	UpdateExerciseFunc              func(id string, exercise *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error)
+	UpdateExerciseFunc              func(id string, exercise *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error)
+	GetRevisionSnapshotFunc         func(id string) (*dto.ExerciseRevisionSnapshot, error)
 }
 
 func (m *mockRepository) CreateExercise(ex *dto.ExerciseCreationDTO) (*string, error) {
@@ -58,9 +62,15 @@ func (m *mockRepository) GetAllExercises() ([]*dto.ExerciseResponseDTO, error) {
 	}
 	return nil, nil
 }
-func (m *mockRepository) UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error) {
+func (m *mockRepository) UpdateExercise(id string, exercise *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
 	if m.UpdateExerciseFunc != nil {
-		return m.UpdateExerciseFunc(id, exercise)
+		return m.UpdateExerciseFunc(id, exercise, record)
+	}
+	return nil, nil
+}
+func (m *mockRepository) GetRevisionSnapshot(tx *sql.Tx, id string) (*dto.ExerciseRevisionSnapshot, error) {
+	if m.GetRevisionSnapshotFunc != nil {
+		return m.GetRevisionSnapshotFunc(id)
 	}
 	return nil, nil
 }
@@ -175,7 +185,7 @@ func TestExerciseService_CreateExercise(t *testing.T) {
 			return &id, nil
 		},
 	}
-	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc)
+	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc, nil, nil, nil)
 
 	ex := &dto.ExerciseCreationDTO{
 		Name:            "Pushup",
@@ -183,7 +193,7 @@ func TestExerciseService_CreateExercise(t *testing.T) {
 		ExerciseType:    "strength",
 		Synonyms:        []string{"press-up"},
 		Equipment:       []string{"eq1"},
-		MuscularGroups:  []string{"mg1"},
+		MuscularGroups:  []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "mg1"}},
 		Instructions:    "Do a pushup",
 		CreatedBy:       "tester",
 	}
@@ -255,7 +265,7 @@ func TestExerciseService_DeleteExercise(t *testing.T) {
 			return errors.New("mg remove error")
 		},
 	}
-	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc)
+	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc, nil, nil, nil)
 
 	// Success
 	err := service.DeleteExercise("ok")
@@ -287,7 +297,7 @@ func TestExerciseService_GetExerciseByID(t *testing.T) {
 	}
 	equipmentSvc := &mockEquipmentService{}
 	muscularGroupSvc := &mockMuscularGroupService{}
-	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc)
+	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc, nil, nil, nil)
 
 	// Success
 	res, err := service.GetExerciseByID("id1")
@@ -310,7 +320,7 @@ func TestExerciseService_GetExerciseByID(t *testing.T) {
 
 func TestExerciseService_UpdateExercise(t *testing.T) {
 	repo := &mockRepository{
-		UpdateExerciseFunc: func(id string, ex *dto.ExerciseUpdateDTO) (*dto.ExerciseResponseDTO, error) {
+		UpdateExerciseFunc: func(id string, ex *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
 			if id == "fail" {
 				return nil, errors.New("update error")
 			}
@@ -319,7 +329,7 @@ func TestExerciseService_UpdateExercise(t *testing.T) {
 	}
 	equipmentSvc := &mockEquipmentService{}
 	muscularGroupSvc := &mockMuscularGroupService{}
-	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc)
+	service := NewExerciseService(repo, equipmentSvc, muscularGroupSvc, nil, nil, nil)
 
 	// Success
 	name := "Updated"
@@ -339,13 +349,70 @@ func TestExerciseService_UpdateExercise(t *testing.T) {
 	}
 }
 
+func TestExerciseService_UpdateExercise_MuscleLinks(t *testing.T) {
+	var written *dto.ExerciseUpdateDTO
+	repo := &mockRepository{
+		UpdateExerciseFunc: func(id string, ex *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
+			written = ex
+			return &dto.ExerciseResponseDTO{ID: id}, nil
+		},
+	}
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, nil)
+
+	// Omitted links reach the repository as nil, which keeps them
+	name := "Updated"
+	if _, err := service.UpdateExercise("id1", &dto.ExerciseUpdateDTO{Name: &name}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if written.MuscularGroups != nil || written.Equipment != nil {
+		t.Error("expected links to be kept when muscular_groups is omitted")
+	}
+
+	// Provided links replace the current ones with their role and weight
+	_, err := service.UpdateExercise("id1", &dto.ExerciseUpdateDTO{MuscularGroups: []dto.ExerciseMuscleLinkDTO{
+		{MuscularGroupID: "mg1"},
+		{MuscularGroupID: "mg2", Role: "secondary", ActivationWeight: 0.4},
+		{MuscularGroupID: "mg3", Role: "stabilizer"},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	links := written.MuscularGroups
+	if len(links) != 3 {
+		t.Fatalf("expected links to be replaced, got %v", links)
+	}
+	if links[0].Role != "primary" || links[0].ActivationWeight != 1.0 {
+		t.Errorf("expected primary/1.0 defaults, got %s/%v", links[0].Role, links[0].ActivationWeight)
+	}
+	if links[1].Role != "secondary" || links[1].ActivationWeight != 0.4 {
+		t.Errorf("expected secondary/0.4, got %s/%v", links[1].Role, links[1].ActivationWeight)
+	}
+	if links[2].ActivationWeight != 0.25 {
+		t.Errorf("expected stabilizer default weight 0.25, got %v", links[2].ActivationWeight)
+	}
+
+	// Invalid links are rejected before the exercise is written
+	repo.UpdateExerciseFunc = func(id string, ex *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
+		t.Fatal("repository should not be called for invalid links")
+		return nil, nil
+	}
+	_, err = service.UpdateExercise("id1", &dto.ExerciseUpdateDTO{MuscularGroups: []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "mg1", ActivationWeight: 0.001}}})
+	if err == nil {
+		t.Error("expected error for weight below 0.01, got nil")
+	}
+	_, err = service.UpdateExercise("id1", &dto.ExerciseUpdateDTO{MuscularGroups: []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "mg1"}, {MuscularGroupID: "mg1"}}})
+	if err == nil {
+		t.Error("expected error for duplicate muscular group, got nil")
+	}
+}
+
 func TestExerciseService_GetAllExercises(t *testing.T) {
 	repo := &mockRepository{
 		GetAllExercisesFunc: func() ([]*dto.ExerciseResponseDTO, error) {
 			return []*dto.ExerciseResponseDTO{{ID: "id1"}}, nil
 		},
 	}
-	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{})
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, nil)
 	res, err := service.GetAllExercises()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -364,7 +431,7 @@ func TestExerciseService_GetExercisesByMuscularGroup(t *testing.T) {
 			return []*dto.ExerciseResponseDTO{{ID: "id1"}}, nil
 		},
 	}
-	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{})
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, nil)
 	res, err := service.GetExercisesByMuscularGroup([]string{"mg1"})
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -387,7 +454,7 @@ func TestExerciseService_GetExercisesByEquipment(t *testing.T) {
 			return []*dto.ExerciseResponseDTO{{ID: "id1"}}, nil
 		},
 	}
-	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{})
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, nil)
 	res, err := service.GetExercisesByEquipment([]string{"eq1"})
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -419,7 +486,7 @@ func TestExerciseService_GetExercisesByMuscularGroupAndEquipment(t *testing.T) {
 			return []*dto.ExerciseResponseDTO{{ID: "id1"}}, nil
 		},
 	}
-	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{})
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, nil)
 
 	// Both filters
 	res, err := service.GetExercisesByMuscularGroupAndEquipment([]string{"mg1"}, []string{"eq1"})
@@ -469,3 +536,191 @@ func TestExerciseService_GetExercisesByMuscularGroupAndEquipment(t *testing.T) {
 		t.Error("expected error for eq error, got nil")
 	}
 }
+
+type mockMediaCleaner struct {
+	calls []string
+	err   error
+}
+
+func (m *mockMediaCleaner) CleanupExerciseMedia(gymID, exerciseID string) error {
+	m.calls = append(m.calls, gymID+"/"+exerciseID)
+	return m.err
+}
+
+func TestExerciseService_DeleteExerciseCleansUpMedia(t *testing.T) {
+	repo := &mockRepository{
+		DeleteExerciseFunc: func(id string) error { return nil },
+		GetExerciseByIDFunc: func(id string) (*dto.ExerciseResponseDTO, error) {
+			return &dto.ExerciseResponseDTO{ID: id, Name: "Pushup"}, nil
+		},
+	}
+	cleaner := &mockMediaCleaner{}
+	service := NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, cleaner, nil, nil)
+
+	if err := service.DeleteExercise("ex-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cleaner.calls) != 1 || cleaner.calls[0] != "/ex-1" {
+		t.Errorf("expected public media cleanup for ex-1, got %v", cleaner.calls)
+	}
+
+	cleaner.err = errors.New("storage down")
+	if err := service.DeleteExercise("ex-2"); err == nil {
+		t.Error("expected cleanup error, got nil")
+	}
+}
+
+type mockOverlay struct {
+	gymID string
+}
+
+func (m *mockOverlay) ApplyOverrides(gymID string, exercises []*dto.ExerciseResponseDTO) ([]*dto.ExerciseResponseDTO, error) {
+	m.gymID = gymID
+	return exercises[1:], nil
+}
+
+func TestExerciseService_MergeGymOverrides(t *testing.T) {
+	exercises := []*dto.ExerciseResponseDTO{{ID: "hidden"}, {ID: "id1"}}
+	overlay := &mockOverlay{}
+	service := NewExerciseService(&mockRepository{}, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, overlay, nil)
+
+	// Platform callers have no gym and see upstream untouched
+	res, err := service.MergeGymOverrides("", exercises)
+	if err != nil || len(res) != 2 || overlay.gymID != "" {
+		t.Errorf("expected upstream exercises without calling the overlay, got %v (%v)", res, err)
+	}
+
+	res, err = service.MergeGymOverrides("gym1", exercises)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if overlay.gymID != "gym1" || len(res) != 1 || res[0].ID != "id1" {
+		t.Errorf("expected the gym view from the overlay, got %v", res)
+	}
+}
+
+type mockRecorder struct {
+	before, after any
+	authorID      string
+	err           error
+}
+
+func (m *mockRecorder) Record(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.before, m.after, m.authorID = before, after, authorID
+	return nil
+}
+
+// newRevisionedService keeps one exercise in memory; like the repository's transaction, an update is only kept
+// when record succeeds
+func newRevisionedService(recorder *mockRecorder) (*ExerciseService, *dto.ExerciseRevisionSnapshot) {
+	video := "https://cdn.example.com/squat.mp4"
+	current := &dto.ExerciseRevisionSnapshot{
+		Name:            "Squat",
+		Synonyms:        []string{},
+		DifficultyLevel: "beginner",
+		ExerciseType:    "strength",
+		VideoURL:        &video,
+		EquipmentIDs:    []string{"rack"},
+		MuscularGroups:  []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "quads", Role: "primary", ActivationWeight: 1}},
+	}
+	repo := &mockRepository{
+		GetExerciseByIDFunc: func(id string) (*dto.ExerciseResponseDTO, error) {
+			return &dto.ExerciseResponseDTO{ID: id, Name: current.Name}, nil
+		},
+		UpdateExerciseFunc: func(id string, ex *dto.ExerciseUpdateDTO, record func(tx *sql.Tx, before, after *dto.ExerciseRevisionSnapshot) error) (*dto.ExerciseResponseDTO, error) {
+			before := *current
+			after := *current
+			if ex.Name != nil {
+				after.Name = *ex.Name
+			}
+			if ex.VideoURL != nil || ex.ReplaceMedia {
+				after.VideoURL = ex.VideoURL
+			}
+			if ex.Equipment != nil {
+				after.EquipmentIDs = ex.Equipment
+			}
+			if ex.MuscularGroups != nil {
+				after.MuscularGroups = ex.MuscularGroups
+			}
+			if record != nil {
+				if err := record(nil, &before, &after); err != nil {
+					return nil, err
+				}
+			}
+			*current = after
+			return &dto.ExerciseResponseDTO{ID: id, Name: after.Name}, nil
+		},
+	}
+	return NewExerciseService(repo, &mockEquipmentService{}, &mockMuscularGroupService{}, nil, nil, recorder), current
+}
+
+func TestExerciseService_UpdateExerciseRecordsRevision(t *testing.T) {
+	recorder := &mockRecorder{}
+	service, current := newRevisionedService(recorder)
+	name := "Back Squat"
+
+	_, err := service.UpdateExercise("squat", &dto.ExerciseUpdateDTO{Name: &name, Equipment: []string{"bar", "rack"}, UpdatedBy: "admin-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	before := recorder.before.(*dto.ExerciseRevisionSnapshot)
+	after := recorder.after.(*dto.ExerciseRevisionSnapshot)
+	if before.Name != "Squat" || after.Name != "Back Squat" {
+		t.Errorf("expected the name change in the snapshots, got %q -> %q", before.Name, after.Name)
+	}
+	if len(after.EquipmentIDs) != 2 || after.MuscularGroups[0].ActivationWeight != 1 {
+		t.Errorf("expected the links in the snapshot, got %v and %v", after.EquipmentIDs, after.MuscularGroups)
+	}
+	if recorder.authorID != "admin-1" {
+		t.Errorf("expected author admin-1, got %q", recorder.authorID)
+	}
+
+	// A revision that cannot be stored rolls the update back
+	recorder.err = errors.New("revision insert failed")
+	other := "Box Squat"
+	if _, err := service.UpdateExercise("squat", &dto.ExerciseUpdateDTO{Name: &other}); err == nil {
+		t.Fatal("expected error when the revision is not recorded, got nil")
+	}
+	if current.Name != "Back Squat" {
+		t.Errorf("expected the update rolled back, got %q", current.Name)
+	}
+}
+
+func TestExerciseService_RestoreSnapshot(t *testing.T) {
+	recorder := &mockRecorder{}
+	service, current := newRevisionedService(recorder)
+	snapshot, _ := json.Marshal(dto.ExerciseRevisionSnapshot{
+		Name:            "Front Squat",
+		DifficultyLevel: "beginner",
+		ExerciseType:    "strength",
+		EquipmentIDs:    []string{},
+		MuscularGroups:  []dto.ExerciseMuscleLinkDTO{{MuscularGroupID: "quads", Role: "secondary", ActivationWeight: 0.6}},
+	})
+
+	var before, after any
+	err := service.RestoreSnapshot("squat", snapshot, func(tx *sql.Tx, b, a any) error {
+		before, after = b, a
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if current.Name != "Front Squat" || len(current.EquipmentIDs) != 0 {
+		t.Errorf("expected the snapshot written back, got %q with equipment %v", current.Name, current.EquipmentIDs)
+	}
+	if current.VideoURL != nil {
+		t.Errorf("expected the video URL cleared like in the snapshot, got %v", *current.VideoURL)
+	}
+	if link := current.MuscularGroups[0]; link.Role != "secondary" || link.ActivationWeight != 0.6 {
+		t.Errorf("expected the link's role and weight restored, got %s/%v", link.Role, link.ActivationWeight)
+	}
+	if before.(*dto.ExerciseRevisionSnapshot).Name != "Squat" || after.(*dto.ExerciseRevisionSnapshot).Name != "Front Squat" {
+		t.Errorf("unexpected snapshots %v -> %v", before, after)
+	}
+	if recorder.after != nil {
+		t.Error("restoring must leave recording to the revision service")
+	}
+}
@@ -0,0 +1,11 @@
+package dto
+
+type ExerciseContraindication struct {
+	ID               string  `json:"id"`
+	ExerciseID       string  `json:"exercise_id"`
+	SpecialSituation string  `json:"special_situation"`
+	BodyRegion       string  `json:"body_region"` // defaults to general
+	Severity         string  `json:"severity"`    // contraindicated or caution
+	Notes            *string `json:"notes,omitempty"`
+	CreatedAt        string  `json:"created_at,omitempty"`
+}
@@ -0,0 +1,26 @@
+package enum
+
+type BodyRegion string
+
+const (
+	General     BodyRegion = "general"
+	Neck        BodyRegion = "neck"
+	Shoulder    BodyRegion = "shoulder"
+	Elbow       BodyRegion = "elbow"
+	Wrist       BodyRegion = "wrist"
+	UpperBack   BodyRegion = "upper_back"
+	LowerBack   BodyRegion = "lower_back"
+	Abdomen     BodyRegion = "abdomen"
+	PelvicFloor BodyRegion = "pelvic_floor"
+	Hip         BodyRegion = "hip"
+	Knee        BodyRegion = "knee"
+	Ankle       BodyRegion = "ankle"
+)
+
+func (b BodyRegion) IsValid() bool {
+	switch b {
+	case General, Neck, Shoulder, Elbow, Wrist, UpperBack, LowerBack, Abdomen, PelvicFloor, Hip, Knee, Ankle:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package enum
+
+type ContraindicationSeverity string
+
+const (
+	// Contraindicated exercises can be blocked by gym policy
+	Contraindicated ContraindicationSeverity = "contraindicated"
+	// Caution exercises only ever produce a warning
+	Caution ContraindicationSeverity = "caution"
+)
+
+func (s ContraindicationSeverity) IsValid() bool {
+	switch s {
+	case Contraindicated, Caution:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type ExerciseContraindicationHandler struct {
+	service interfaces.ExerciseContraindicationService
+}
+
+func NewExerciseContraindicationHandler(service interfaces.ExerciseContraindicationService) *ExerciseContraindicationHandler {
+	return &ExerciseContraindicationHandler{service: service}
+}
+
+func (h *ExerciseContraindicationHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	// Public exercise tags are shared by every gym, so only platform admins maintain them
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can tag public exercises",
+			nil,
+		))
+		return
+	}
+	var tag dto.ExerciseContraindication
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeBadRequest,
+			"Invalid request payload",
+			err,
+		))
+		return
+	}
+	tagID, err := h.service.CreateTag(&tag)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to create contraindication")
+		return
+	}
+	response.WriteAPICreated(w, "Contraindication created successfully", *tagID)
+}
+
+func (h *ExerciseContraindicationHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can tag public exercises",
+			nil,
+		))
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if err := h.service.DeleteTag(id); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete contraindication")
+		return
+	}
+	response.WriteAPISuccess(w, "Contraindication deleted successfully", nil)
+}
+
+func (h *ExerciseContraindicationHandler) GetTagByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	tag, err := h.service.GetTagByID(id)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to retrieve contraindication")
+		return
+	}
+	response.WriteAPISuccess(w, "Contraindication retrieved successfully", tag)
+}
+
+func (h *ExerciseContraindicationHandler) GetTagsByExerciseID(w http.ResponseWriter, r *http.Request) {
+	exerciseID := chi.URLParam(r, "exerciseID")
+	tags, err := h.service.GetTagsByExerciseID(exerciseID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to retrieve contraindications")
+		return
+	}
+	response.WriteAPISuccess(w, "Contraindications retrieved successfully", tags)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	CreateTagFunc func(tag *dto.ExerciseContraindication) (*string, error)
+}
+
+func (m *mockService) CreateTag(tag *dto.ExerciseContraindication) (*string, error) {
+	return m.CreateTagFunc(tag)
+}
+func (m *mockService) DeleteTag(id string) error { return nil }
+func (m *mockService) GetTagByID(id string) (*dto.ExerciseContraindication, error) {
+	return nil, nil
+}
+func (m *mockService) GetTagsByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error) {
+	return nil, nil
+}
+
+func TestExerciseContraindicationHandler_CreateTag(t *testing.T) {
+	body, _ := json.Marshal(&dto.ExerciseContraindication{ExerciseID: "ex1", SpecialSituation: "pregnancy", Severity: "caution"})
+
+	t.Run("platform admin", func(t *testing.T) {
+		h := NewExerciseContraindicationHandler(&mockService{
+			CreateTagFunc: func(tag *dto.ExerciseContraindication) (*string, error) {
+				id := "tag1"
+				return &id, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/tag", bytes.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserTypeKey, "platform_admin"))
+		w := httptest.NewRecorder()
+		h.CreateTag(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("gym user is forbidden", func(t *testing.T) {
+		h := NewExerciseContraindicationHandler(&mockService{})
+		req := httptest.NewRequest(http.MethodPost, "/tag", bytes.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserTypeKey, "tenant_user"))
+		w := httptest.NewRecorder()
+		h.CreateTag(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type ExerciseContraindicationHandler interface {
+	CreateTag(w http.ResponseWriter, r *http.Request)
+	DeleteTag(w http.ResponseWriter, r *http.Request)
+	GetTagByID(w http.ResponseWriter, r *http.Request)
+	GetTagsByExerciseID(w http.ResponseWriter, r *http.Request)
+}
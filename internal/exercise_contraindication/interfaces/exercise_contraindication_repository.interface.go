@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+
+type ExerciseContraindicationRepository interface {
+	CreateTag(tag *dto.ExerciseContraindication) (*string, error)
+	DeleteTag(id string) error
+	FindByID(id string) (*dto.ExerciseContraindication, error)
+	FindByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+
+type ExerciseContraindicationService interface {
+	CreateTag(tag *dto.ExerciseContraindication) (*string, error)
+	DeleteTag(id string) error
+	GetTagByID(id string) (*dto.ExerciseContraindication, error)
+	GetTagsByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error)
+}
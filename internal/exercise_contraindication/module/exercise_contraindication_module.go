@@ -0,0 +1,18 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/handler"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/router"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/service"
+)
+
+func NewExerciseContraindicationModule(db *sql.DB) http.Handler {
+	repo := repository.NewExerciseContraindicationRepository(db)
+	service := service.NewExerciseContraindicationService(repo)
+	handler := handler.NewExerciseContraindicationHandler(service)
+	return router.NewExerciseContraindicationRouter(handler)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+)
+
+type ExerciseContraindicationRepository struct {
+	db *sql.DB
+}
+
+func NewExerciseContraindicationRepository(db *sql.DB) *ExerciseContraindicationRepository {
+	return &ExerciseContraindicationRepository{db: db}
+}
+
+func (r *ExerciseContraindicationRepository) CreateTag(tag *dto.ExerciseContraindication) (*string, error) {
+	query := `INSERT INTO public.exercise_contraindication (exercise_id, special_situation, body_region, severity, notes) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	var id string
+	err := r.db.QueryRow(query, tag.ExerciseID, tag.SpecialSituation, tag.BodyRegion, tag.Severity, tag.Notes).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *ExerciseContraindicationRepository) DeleteTag(id string) error {
+	query := `DELETE FROM public.exercise_contraindication WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ExerciseContraindicationRepository) FindByID(id string) (*dto.ExerciseContraindication, error) {
+	query := `SELECT id, exercise_id, special_situation, body_region, severity, notes, created_at FROM public.exercise_contraindication WHERE id = $1`
+	var tag dto.ExerciseContraindication
+	err := r.db.QueryRow(query, id).Scan(&tag.ID, &tag.ExerciseID, &tag.SpecialSituation, &tag.BodyRegion, &tag.Severity, &tag.Notes, &tag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *ExerciseContraindicationRepository) FindByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error) {
+	query := `SELECT id, exercise_id, special_situation, body_region, severity, notes, created_at FROM public.exercise_contraindication WHERE exercise_id = $1 ORDER BY special_situation, body_region`
+	rows, err := r.db.Query(query, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tags []*dto.ExerciseContraindication
+	for rows.Next() {
+		tag := &dto.ExerciseContraindication{}
+		err := rows.Scan(&tag.ID, &tag.ExerciseID, &tag.SpecialSituation, &tag.BodyRegion, &tag.Severity, &tag.Notes, &tag.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseContraindicationRepository_CreateTag(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseContraindicationRepository(db)
+	tag := &dto.ExerciseContraindication{ExerciseID: "ex1", SpecialSituation: "injury_recovery", BodyRegion: "knee", Severity: "contraindicated"}
+	mock.ExpectQuery(`INSERT INTO public.exercise_contraindication`).
+		WithArgs("ex1", "injury_recovery", "knee", "contraindicated", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("tag1"))
+	id, err := repo.CreateTag(tag)
+	assert.NoError(t, err)
+	assert.Equal(t, "tag1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseContraindicationRepository_FindByExerciseID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseContraindicationRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "exercise_id", "special_situation", "body_region", "severity", "notes", "created_at"}).
+		AddRow("tag1", "ex1", "pregnancy", "abdomen", "contraindicated", "Avoid lying supine", "2025-01-01T00:00:00Z")
+	mock.ExpectQuery(`SELECT (.+) FROM public.exercise_contraindication WHERE exercise_id = \$1`).
+		WithArgs("ex1").
+		WillReturnRows(rows)
+	tags, err := repo.FindByExerciseID("ex1")
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "abdomen", tags[0].BodyRegion)
+		assert.Equal(t, "Avoid lying supine", *tags[0].Notes)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseContraindicationRepository_DeleteTagNotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseContraindicationRepository(db)
+	mock.ExpectExec(`DELETE FROM public.exercise_contraindication WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err := repo.DeleteTag("missing")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
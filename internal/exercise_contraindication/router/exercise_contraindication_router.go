@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewExerciseContraindicationRouter(handler interfaces.ExerciseContraindicationHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/tag", handler.CreateTag)                                 // POST /exercise-contraindication/tag
+	r.Get("/tag/{id}", handler.GetTagByID)                            // GET /exercise-contraindication/tag/{id}
+	r.Delete("/tag/{id}", handler.DeleteTag)                          // DELETE /exercise-contraindication/tag/{id}
+	r.Get("/exercise/{exerciseID}/tags", handler.GetTagsByExerciseID) // GET /exercise-contraindication/exercise/{exerciseID}/tags
+
+	return r
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type ExerciseContraindicationService struct {
+	repository interfaces.ExerciseContraindicationRepository
+}
+
+func NewExerciseContraindicationService(repository interfaces.ExerciseContraindicationRepository) *ExerciseContraindicationService {
+	return &ExerciseContraindicationService{repository: repository}
+}
+
+func (s *ExerciseContraindicationService) CreateTag(tag *dto.ExerciseContraindication) (*string, error) {
+	if tag.ExerciseID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Exercise ID is required", nil)
+	}
+	if err := ValidateTag(&tag.SpecialSituation, &tag.BodyRegion, tag.Severity); err != nil {
+		return nil, err
+	}
+	existing, err := s.repository.FindByExerciseID(tag.ExerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check existing contraindications", err)
+	}
+	for _, e := range existing {
+		if e.SpecialSituation == tag.SpecialSituation && e.BodyRegion == tag.BodyRegion {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "Exercise is already tagged for this situation and body region", nil)
+		}
+	}
+	id, err := s.repository.CreateTag(tag)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create exercise contraindication", err)
+	}
+	return id, nil
+}
+
+func (s *ExerciseContraindicationService) DeleteTag(id string) error {
+	err := s.repository.DeleteTag(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Exercise contraindication not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete exercise contraindication", err)
+	}
+	return nil
+}
+
+func (s *ExerciseContraindicationService) GetTagByID(id string) (*dto.ExerciseContraindication, error) {
+	tag, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise contraindication not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to retrieve exercise contraindication", err)
+	}
+	return tag, nil
+}
+
+func (s *ExerciseContraindicationService) GetTagsByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error) {
+	tags, err := s.repository.FindByExerciseID(exerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get contraindications by exercise ID", err)
+	}
+	return tags, nil
+}
+
+// ValidateTag defaults the body region to general and checks the situation, region and severity.
+// It is shared with the gym-level tags so both tables accept the same values.
+func ValidateTag(specialSituation, bodyRegion *string, severity string) error {
+	situation := user_enum.SpecialSituation(*specialSituation)
+	if !situation.IsValid() || situation == user_enum.None {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Invalid special situation", nil)
+	}
+	if *bodyRegion == "" {
+		*bodyRegion = string(enum.General)
+	}
+	if !enum.BodyRegion(*bodyRegion).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Invalid body region", nil)
+	}
+	if !enum.ContraindicationSeverity(severity).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Invalid severity, must be contraindicated or caution", nil)
+	}
+	return nil
+}
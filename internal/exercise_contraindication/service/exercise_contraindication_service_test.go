@@ -0,0 +1,79 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_contraindication/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepo struct {
+	CreateTagFunc        func(tag *dto.ExerciseContraindication) (*string, error)
+	DeleteTagFunc        func(id string) error
+	FindByIDFunc         func(id string) (*dto.ExerciseContraindication, error)
+	FindByExerciseIDFunc func(exerciseID string) ([]*dto.ExerciseContraindication, error)
+}
+
+func (m *mockRepo) CreateTag(tag *dto.ExerciseContraindication) (*string, error) {
+	return m.CreateTagFunc(tag)
+}
+func (m *mockRepo) DeleteTag(id string) error { return m.DeleteTagFunc(id) }
+func (m *mockRepo) FindByID(id string) (*dto.ExerciseContraindication, error) {
+	return m.FindByIDFunc(id)
+}
+func (m *mockRepo) FindByExerciseID(exerciseID string) ([]*dto.ExerciseContraindication, error) {
+	return m.FindByExerciseIDFunc(exerciseID)
+}
+
+func TestExerciseContraindicationService_CreateTag(t *testing.T) {
+	t.Run("defaults body region", func(t *testing.T) {
+		repo := &mockRepo{
+			FindByExerciseIDFunc: func(exerciseID string) ([]*dto.ExerciseContraindication, error) { return nil, nil },
+			CreateTagFunc: func(tag *dto.ExerciseContraindication) (*string, error) {
+				assert.Equal(t, "general", tag.BodyRegion)
+				id := "tag1"
+				return &id, nil
+			},
+		}
+		svc := NewExerciseContraindicationService(repo)
+		id, err := svc.CreateTag(&dto.ExerciseContraindication{ExerciseID: "ex1", SpecialSituation: "elderly_population", Severity: "caution"})
+		assert.NoError(t, err)
+		assert.Equal(t, "tag1", *id)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		svc := NewExerciseContraindicationService(&mockRepo{})
+		tags := []*dto.ExerciseContraindication{
+			{SpecialSituation: "pregnancy", Severity: "caution"},
+			{ExerciseID: "ex1", SpecialSituation: "tired", Severity: "caution"},
+			{ExerciseID: "ex1", SpecialSituation: "pregnancy", BodyRegion: "tail", Severity: "caution"},
+			{ExerciseID: "ex1", SpecialSituation: "pregnancy", Severity: "maybe"},
+		}
+		for _, tag := range tags {
+			_, err := svc.CreateTag(tag)
+			assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+		}
+	})
+
+	t.Run("rejects duplicate situation and region", func(t *testing.T) {
+		repo := &mockRepo{
+			FindByExerciseIDFunc: func(exerciseID string) ([]*dto.ExerciseContraindication, error) {
+				return []*dto.ExerciseContraindication{{SpecialSituation: "pregnancy", BodyRegion: "abdomen"}}, nil
+			},
+		}
+		svc := NewExerciseContraindicationService(repo)
+		_, err := svc.CreateTag(&dto.ExerciseContraindication{ExerciseID: "ex1", SpecialSituation: "pregnancy", BodyRegion: "abdomen", Severity: "contraindicated"})
+		assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+	})
+}
+
+func TestExerciseContraindicationService_DeleteTagNotFound(t *testing.T) {
+	svc := NewExerciseContraindicationService(&mockRepo{
+		DeleteTagFunc: func(id string) error { return sql.ErrNoRows },
+	})
+	err := svc.DeleteTag("missing")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+}
@@ -0,0 +1,65 @@
+package dto
+
+// LibraryDocument is the interchange format shared by the importer and the exporter.
+// Exercises reference muscular groups and equipment by name, so a document can be moved between environments.
+type LibraryDocument struct {
+	MuscularGroups []MuscularGroupRecord `json:"muscular_groups,omitempty"`
+	Equipment      []EquipmentRecord     `json:"equipment,omitempty"`
+	Exercises      []ExerciseRecord      `json:"exercises"`
+}
+
+type MuscularGroupRecord struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	BodyPart    string  `json:"body_part"` // 'upper_body', 'lower_body', 'core', 'full_body'
+}
+
+type EquipmentRecord struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Category    string  `json:"category"`
+}
+
+type ExerciseRecord struct {
+	Name            string             `json:"name"`
+	Synonyms        []string           `json:"synonyms"`
+	DifficultyLevel string             `json:"difficulty_level"`
+	ExerciseType    string             `json:"exercise_type"`
+	Instructions    string             `json:"instructions"`
+	VideoURL        *string            `json:"video_url,omitempty"`
+	ImageURL        *string            `json:"image_url,omitempty"`
+	MuscularGroups  []MuscleLinkRecord `json:"muscular_groups"`
+	Equipment       []string           `json:"equipment"`
+}
+
+type MuscleLinkRecord struct {
+	Name             string  `json:"name"`
+	Role             string  `json:"role"`              // defaults to primary
+	ActivationWeight float64 `json:"activation_weight"` // defaults to the role weight
+}
+
+// ImportReport is the diff between a document and the stored library.
+// Nothing is written when DryRun is set or when Errors is not empty.
+type ImportReport struct {
+	DryRun    bool          `json:"dry_run"`
+	Applied   bool          `json:"applied"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Changes   []ChangeEntry `json:"changes"`
+	Errors    []RowError    `json:"errors"`
+}
+
+type ChangeEntry struct {
+	Entity string   `json:"entity"` // exercise, muscular_group or equipment
+	Name   string   `json:"name"`
+	Action string   `json:"action"`           // create, update or unchanged
+	Fields []string `json:"fields,omitempty"` // changed fields for updates
+}
+
+type RowError struct {
+	Entity  string `json:"entity"`
+	Row     int    `json:"row"` // 1-based position within the entity list
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,17 @@
+package enum
+
+type ImportAction string
+
+const (
+	Create    ImportAction = "create"
+	Update    ImportAction = "update"
+	Unchanged ImportAction = "unchanged"
+)
+
+func (a ImportAction) IsValid() bool {
+	switch a {
+	case Create, Update, Unchanged:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package enum
+
+type LibraryFormat string
+
+const (
+	JSON LibraryFormat = "json"
+	CSV  LibraryFormat = "csv"
+)
+
+func (f LibraryFormat) IsValid() bool {
+	switch f {
+	case JSON, CSV:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the media type used when the library is served in this format
+func (f LibraryFormat) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
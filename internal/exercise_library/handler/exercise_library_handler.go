@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/service"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+)
+
+type ExerciseLibraryHandler struct {
+	service interfaces.ExerciseLibraryService
+}
+
+func NewExerciseLibraryHandler(service interfaces.ExerciseLibraryService) *ExerciseLibraryHandler {
+	return &ExerciseLibraryHandler{service: service}
+}
+
+func (h *ExerciseLibraryHandler) ExportPublicLibrary(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can export the public exercise library",
+			nil,
+		))
+		return
+	}
+	format, err := parseFormat(r)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid format")
+		return
+	}
+	doc, err := h.service.ExportPublicLibrary()
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to export public exercise library")
+		return
+	}
+	writeDocument(w, format, "exercise-library", doc)
+}
+
+func (h *ExerciseLibraryHandler) ImportPublicLibrary(w http.ResponseWriter, r *http.Request) {
+	// The public library is shared by every gym, so only platform admins can import into it
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can import the public exercise library",
+			nil,
+		))
+		return
+	}
+	format, err := parseFormat(r)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid format")
+		return
+	}
+	doc, err := service.DecodeLibrary(format, r.Body)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid library document")
+		return
+	}
+	var createdBy *string
+	if userID := middleware.GetUserID(r); userID != "" {
+		createdBy = &userID
+	}
+	report, err := h.service.ImportPublicLibrary(doc, createdBy, isDryRun(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to import public exercise library")
+		return
+	}
+	writeReport(w, report)
+}
+
+func (h *ExerciseLibraryHandler) ExportGymLibrary(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only gym administrators can export the gym exercise library",
+			nil,
+		))
+		return
+	}
+	format, err := parseFormat(r)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid format")
+		return
+	}
+	doc, err := h.service.ExportGymLibrary(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to export gym exercise library")
+		return
+	}
+	writeDocument(w, format, "custom-exercise-library", doc)
+}
+
+func (h *ExerciseLibraryHandler) ImportGymLibrary(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only gym administrators can import the gym exercise library",
+			nil,
+		))
+		return
+	}
+	format, err := parseFormat(r)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid format")
+		return
+	}
+	doc, err := service.DecodeLibrary(format, r.Body)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Invalid library document")
+		return
+	}
+	report, err := h.service.ImportGymLibrary(middleware.GetGymID(r), middleware.GetUserID(r), doc, isDryRun(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to import gym exercise library")
+		return
+	}
+	writeReport(w, report)
+}
+
+// parseFormat reads the format query parameter, defaulting to json
+func parseFormat(r *http.Request) (enum.LibraryFormat, error) {
+	format := enum.LibraryFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		return enum.JSON, nil
+	}
+	if !format.IsValid() {
+		return "", apierror.New(errorcode_enum.CodeBadRequest, "Invalid format, must be json or csv", nil)
+	}
+	return format, nil
+}
+
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dry_run") == "true"
+}
+
+// writeDocument serves the document as a downloadable file so it can be fed back to the importer unchanged
+func writeDocument(w http.ResponseWriter, format enum.LibraryFormat, filename string, doc *dto.LibraryDocument) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+string(format)))
+	w.WriteHeader(http.StatusOK)
+	service.EncodeLibrary(format, w, doc)
+}
+
+func writeReport(w http.ResponseWriter, report *dto.ImportReport) {
+	switch {
+	case len(report.Errors) > 0:
+		response.WriteAPISuccess(w, "Library document has validation errors, nothing was imported", report)
+	case report.DryRun:
+		response.WriteAPISuccess(w, "Dry run completed, nothing was imported", report)
+	default:
+		response.WriteAPISuccess(w, "Exercise library imported successfully", report)
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	ExportPublicLibraryFunc func() (*dto.LibraryDocument, error)
+	ImportPublicLibraryFunc func(doc *dto.LibraryDocument, createdBy *string, dryRun bool) (*dto.ImportReport, error)
+}
+
+func (m *mockService) ExportPublicLibrary() (*dto.LibraryDocument, error) {
+	return m.ExportPublicLibraryFunc()
+}
+func (m *mockService) ExportGymLibrary(gymID string) (*dto.LibraryDocument, error) { return nil, nil }
+func (m *mockService) ImportPublicLibrary(doc *dto.LibraryDocument, createdBy *string, dryRun bool) (*dto.ImportReport, error) {
+	return m.ImportPublicLibraryFunc(doc, createdBy, dryRun)
+}
+func (m *mockService) ImportGymLibrary(gymID, createdBy string, doc *dto.LibraryDocument, dryRun bool) (*dto.ImportReport, error) {
+	return nil, nil
+}
+
+func asPlatformAdmin(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserTypeKey, "platform_admin"))
+}
+
+func TestExerciseLibraryHandler_ImportPublicLibrary(t *testing.T) {
+	t.Run("dry run csv", func(t *testing.T) {
+		h := NewExerciseLibraryHandler(&mockService{
+			ImportPublicLibraryFunc: func(doc *dto.LibraryDocument, createdBy *string, dryRun bool) (*dto.ImportReport, error) {
+				assert.True(t, dryRun)
+				assert.Equal(t, "Plank", doc.Exercises[0].Name)
+				return &dto.ImportReport{DryRun: true, Created: 1}, nil
+			},
+		})
+		body := strings.NewReader("name,difficulty_level,exercise_type,instructions\nPlank,beginner,strength,Hold\n")
+		req := asPlatformAdmin(httptest.NewRequest(http.MethodPost, "/public/import?format=csv&dry_run=true", body))
+		w := httptest.NewRecorder()
+		h.ImportPublicLibrary(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Dry run completed")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		h := NewExerciseLibraryHandler(&mockService{})
+		req := asPlatformAdmin(httptest.NewRequest(http.MethodPost, "/public/import?format=xml", strings.NewReader("")))
+		w := httptest.NewRecorder()
+		h.ImportPublicLibrary(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("gym user is forbidden", func(t *testing.T) {
+		h := NewExerciseLibraryHandler(&mockService{})
+		req := httptest.NewRequest(http.MethodPost, "/public/import", strings.NewReader(`{"exercises":[]}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserTypeKey, "tenant_user"))
+		w := httptest.NewRecorder()
+		h.ImportPublicLibrary(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestExerciseLibraryHandler_ExportPublicLibrary(t *testing.T) {
+	h := NewExerciseLibraryHandler(&mockService{
+		ExportPublicLibraryFunc: func() (*dto.LibraryDocument, error) {
+			return &dto.LibraryDocument{Exercises: []dto.ExerciseRecord{{Name: "Plank", Synonyms: []string{}, MuscularGroups: []dto.MuscleLinkRecord{}, Equipment: []string{}}}}, nil
+		},
+	})
+	req := asPlatformAdmin(httptest.NewRequest(http.MethodGet, "/public/export?format=csv", nil))
+	w := httptest.NewRecorder()
+	h.ExportPublicLibrary(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="exercise-library.csv"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "name,synonyms,difficulty_level"))
+}
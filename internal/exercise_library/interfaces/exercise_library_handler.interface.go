@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type ExerciseLibraryHandler interface {
+	ExportPublicLibrary(w http.ResponseWriter, r *http.Request)
+	ImportPublicLibrary(w http.ResponseWriter, r *http.Request)
+	ExportGymLibrary(w http.ResponseWriter, r *http.Request)
+	ImportGymLibrary(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"database/sql"
+
+	exerciseDTO "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+)
+
+type ExerciseLibraryRepository interface {
+	LoadPublicLibrary() (*dto.LibraryDocument, error)
+	LoadGymLibrary(gymID string) (*dto.LibraryDocument, error)
+	// ApplyPublicImport passes the write of every existing exercise to track, when set, inside the import transaction
+	ApplyPublicImport(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error
+	ApplyGymImport(gymID, createdBy string, doc *dto.LibraryDocument) error
+}
+
+// ExerciseSnapshotReader reads the versioned state of a public exercise for its revisions
+type ExerciseSnapshotReader interface {
+	GetRevisionSnapshot(tx *sql.Tx, id string) (*exerciseDTO.ExerciseRevisionSnapshot, error)
+}
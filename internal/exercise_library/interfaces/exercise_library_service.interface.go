@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+
+type ExerciseLibraryService interface {
+	ExportPublicLibrary() (*dto.LibraryDocument, error)
+	ExportGymLibrary(gymID string) (*dto.LibraryDocument, error)
+	ImportPublicLibrary(doc *dto.LibraryDocument, createdBy *string, dryRun bool) (*dto.ImportReport, error)
+	ImportGymLibrary(gymID, createdBy string, doc *dto.LibraryDocument, dryRun bool) (*dto.ImportReport, error)
+}
@@ -0,0 +1,26 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	exerciseRepository "github.com/alejandro-albiol/athenai/internal/exercise/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/handler"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/router"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/service"
+	revisionRepository "github.com/alejandro-albiol/athenai/internal/revision/repository"
+	revisionService "github.com/alejandro-albiol/athenai/internal/revision/service"
+)
+
+func NewExerciseLibraryModule(db *sql.DB) http.Handler {
+	handler := handler.NewExerciseLibraryHandler(NewExerciseLibraryService(db))
+	return router.NewExerciseLibraryRouter(handler)
+}
+
+// NewExerciseLibraryService builds the service with the exercise revision recorder used by public imports
+func NewExerciseLibraryService(db *sql.DB) *service.ExerciseLibraryService {
+	repo := repository.NewExerciseLibraryRepository(db)
+	recorder := revisionService.NewRevisionService(revisionRepository.NewRevisionRepository(db))
+	return service.NewExerciseLibraryService(repo, exerciseRepository.NewExerciseRepository(db), recorder)
+}
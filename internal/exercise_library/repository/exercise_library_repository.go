@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/lib/pq"
+)
+
+type ExerciseLibraryRepository struct {
+	db *sql.DB
+}
+
+func NewExerciseLibraryRepository(db *sql.DB) *ExerciseLibraryRepository {
+	return &ExerciseLibraryRepository{db: db}
+}
+
+// libraryTables names the exercise table and its join tables for one library scope.
+// Muscular groups always live in the public schema; gym libraries may also use the gym's own equipment.
+type libraryTables struct {
+	exercise      string
+	muscleLink    string
+	equipmentLink string
+	linkColumn    string // exercise id column on both join tables
+	liveFilter    string // extra condition that hides deleted exercises
+
+	equipmentCatalog    string // name, description, category of the equipment the library may reference
+	equipmentLinks      string // exercise id and equipment name of every link
+	insertEquipmentLink string // links exercise $1 to the equipment named $2
+}
+
+func publicTables() libraryTables {
+	return libraryTables{
+		exercise:      "public.exercise",
+		muscleLink:    "public.exercise_muscular_group",
+		equipmentLink: "public.exercise_equipment",
+		linkColumn:    "exercise_id",
+		liveFilter:    "TRUE",
+
+		equipmentCatalog:    `SELECT name, description, category FROM public.equipment ORDER BY name`,
+		equipmentLinks:      `SELECT l.exercise_id, e.name FROM public.exercise_equipment l JOIN public.equipment e ON e.id = l.equipment_id ORDER BY e.name`,
+		insertEquipmentLink: `INSERT INTO public.exercise_equipment (exercise_id, equipment_id) SELECT $1, id FROM public.equipment WHERE name = $2`,
+	}
+}
+
+func gymTables(gymID string) libraryTables {
+	schema := pq.QuoteIdentifier(gymID)
+	return libraryTables{
+		exercise:      schema + ".custom_exercise",
+		muscleLink:    schema + ".custom_exercise_muscular_group",
+		equipmentLink: schema + ".custom_exercise_equipment",
+		linkColumn:    "custom_exercise_id",
+		liveFilter:    "deleted_at IS NULL",
+
+		// Public equipment wins when the gym reuses one of its names
+		equipmentCatalog: fmt.Sprintf(`SELECT name, description, category FROM public.equipment
+			UNION ALL
+			SELECT name, description, category FROM %[1]s.custom_equipment
+			WHERE is_active = TRUE AND name NOT IN (SELECT name FROM public.equipment)
+			ORDER BY name`, schema),
+		equipmentLinks: fmt.Sprintf(`SELECT l.custom_exercise_id, COALESCE(e.name, ce.name) AS name FROM %[1]s.custom_exercise_equipment l
+			LEFT JOIN public.equipment e ON e.id = l.public_equipment_id
+			LEFT JOIN %[1]s.custom_equipment ce ON ce.id = l.gym_equipment_id
+			ORDER BY name`, schema),
+		insertEquipmentLink: fmt.Sprintf(`INSERT INTO %[1]s.custom_exercise_equipment (custom_exercise_id, equipment_source, public_equipment_id, gym_equipment_id)
+			SELECT $1::uuid, 'public', id, NULL FROM public.equipment WHERE name = $2::text
+			UNION ALL
+			(SELECT $1::uuid, 'gym', NULL, id FROM %[1]s.custom_equipment
+			WHERE name = $2::text AND is_active = TRUE AND NOT EXISTS (SELECT 1 FROM public.equipment WHERE name = $2::text) LIMIT 1)`, schema),
+	}
+}
+
+func (r *ExerciseLibraryRepository) LoadPublicLibrary() (*dto.LibraryDocument, error) {
+	return r.loadLibrary(publicTables())
+}
+
+func (r *ExerciseLibraryRepository) LoadGymLibrary(gymID string) (*dto.LibraryDocument, error) {
+	return r.loadLibrary(gymTables(gymID))
+}
+
+// ApplyPublicImport upserts the catalog entries and exercises of the document in a single transaction.
+// Link sets of every exercise in the document are replaced by the ones in the document.
+// When track is set, the update of each existing exercise runs through it inside the transaction.
+func (r *ExerciseLibraryRepository) ApplyPublicImport(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+	return r.applyImport(publicTables(), doc, createdBy, true, track)
+}
+
+// ApplyGymImport upserts the gym's custom exercises in a single transaction.
+// The public catalog is never written from a gym import.
+func (r *ExerciseLibraryRepository) ApplyGymImport(gymID, createdBy string, doc *dto.LibraryDocument) error {
+	return r.applyImport(gymTables(gymID), doc, &createdBy, false, nil)
+}
+
+func (r *ExerciseLibraryRepository) loadLibrary(t libraryTables) (*dto.LibraryDocument, error) {
+	doc := &dto.LibraryDocument{
+		MuscularGroups: []dto.MuscularGroupRecord{},
+		Equipment:      []dto.EquipmentRecord{},
+		Exercises:      []dto.ExerciseRecord{},
+	}
+
+	mgRows, err := r.db.Query(`SELECT name, description, body_part FROM public.muscular_group ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer mgRows.Close()
+	for mgRows.Next() {
+		var mg dto.MuscularGroupRecord
+		if err := mgRows.Scan(&mg.Name, &mg.Description, &mg.BodyPart); err != nil {
+			return nil, err
+		}
+		doc.MuscularGroups = append(doc.MuscularGroups, mg)
+	}
+
+	eqRows, err := r.db.Query(t.equipmentCatalog)
+	if err != nil {
+		return nil, err
+	}
+	defer eqRows.Close()
+	for eqRows.Next() {
+		var eq dto.EquipmentRecord
+		if err := eqRows.Scan(&eq.Name, &eq.Description, &eq.Category); err != nil {
+			return nil, err
+		}
+		doc.Equipment = append(doc.Equipment, eq)
+	}
+
+	query := fmt.Sprintf(`SELECT id, name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url FROM %s WHERE %s ORDER BY name`, t.exercise, t.liveFilter)
+	exRows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer exRows.Close()
+	positions := map[string]int{}
+	for exRows.Next() {
+		var id string
+		ex := dto.ExerciseRecord{MuscularGroups: []dto.MuscleLinkRecord{}, Equipment: []string{}}
+		if err := exRows.Scan(&id, &ex.Name, pq.Array(&ex.Synonyms), &ex.DifficultyLevel, &ex.ExerciseType, &ex.Instructions, &ex.VideoURL, &ex.ImageURL); err != nil {
+			return nil, err
+		}
+		positions[id] = len(doc.Exercises)
+		doc.Exercises = append(doc.Exercises, ex)
+	}
+
+	query = fmt.Sprintf(`SELECT l.%s, mg.name, l.role, l.activation_weight FROM %s l JOIN public.muscular_group mg ON mg.id = l.muscular_group_id ORDER BY mg.name`, t.linkColumn, t.muscleLink)
+	linkRows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer linkRows.Close()
+	for linkRows.Next() {
+		var exerciseID string
+		var link dto.MuscleLinkRecord
+		if err := linkRows.Scan(&exerciseID, &link.Name, &link.Role, &link.ActivationWeight); err != nil {
+			return nil, err
+		}
+		if pos, ok := positions[exerciseID]; ok {
+			doc.Exercises[pos].MuscularGroups = append(doc.Exercises[pos].MuscularGroups, link)
+		}
+	}
+
+	eqLinkRows, err := r.db.Query(t.equipmentLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer eqLinkRows.Close()
+	for eqLinkRows.Next() {
+		var exerciseID, name string
+		if err := eqLinkRows.Scan(&exerciseID, &name); err != nil {
+			return nil, err
+		}
+		if pos, ok := positions[exerciseID]; ok {
+			doc.Exercises[pos].Equipment = append(doc.Exercises[pos].Equipment, name)
+		}
+	}
+
+	return doc, nil
+}
+
+func (r *ExerciseLibraryRepository) applyImport(t libraryTables, doc *dto.LibraryDocument, createdBy *string, withCatalog bool, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if withCatalog {
+		for _, mg := range doc.MuscularGroups {
+			_, err := tx.Exec(`INSERT INTO public.muscular_group (name, description, body_part) VALUES ($1, $2, $3)
+				ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description, body_part = EXCLUDED.body_part, updated_at = NOW()`,
+				mg.Name, mg.Description, mg.BodyPart)
+			if err != nil {
+				return fmt.Errorf("failed to upsert muscular group %q: %w", mg.Name, err)
+			}
+		}
+		for _, eq := range doc.Equipment {
+			_, err := tx.Exec(`INSERT INTO public.equipment (name, description, category) VALUES ($1, $2, $3)
+				ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description, category = EXCLUDED.category, updated_at = NOW()`,
+				eq.Name, eq.Description, eq.Category)
+			if err != nil {
+				return fmt.Errorf("failed to upsert equipment %q: %w", eq.Name, err)
+			}
+		}
+	}
+
+	for i := range doc.Exercises {
+		if err := upsertExercise(tx, t, &doc.Exercises[i], createdBy, track); err != nil {
+			return fmt.Errorf("failed to upsert exercise %q: %w", doc.Exercises[i].Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// upsertExercise matches the exercise by case-insensitive name and replaces its muscle and equipment links.
+// The write of an existing exercise goes through track when it is set.
+func upsertExercise(tx *sql.Tx, t libraryTables, ex *dto.ExerciseRecord, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+	var id string
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE LOWER(name) = LOWER($1) AND %s LIMIT 1`, t.exercise, t.liveFilter)
+	err := tx.QueryRow(query, ex.Name).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		query = fmt.Sprintf(`INSERT INTO %s (name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url, created_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, t.exercise)
+		if err := tx.QueryRow(query, ex.Name, pq.Array(ex.Synonyms), ex.DifficultyLevel, ex.ExerciseType, ex.Instructions, ex.VideoURL, ex.ImageURL, createdBy).Scan(&id); err != nil {
+			return err
+		}
+		return replaceLinks(tx, t, id, ex)
+	case err != nil:
+		return err
+	}
+
+	update := func() error {
+		query := fmt.Sprintf(`UPDATE %s SET name = $2, synonyms = $3, difficulty_level = $4, exercise_type = $5, instructions = $6, video_url = $7, image_url = $8, updated_at = NOW() WHERE id = $1`, t.exercise)
+		if _, err := tx.Exec(query, id, ex.Name, pq.Array(ex.Synonyms), ex.DifficultyLevel, ex.ExerciseType, ex.Instructions, ex.VideoURL, ex.ImageURL); err != nil {
+			return err
+		}
+		return replaceLinks(tx, t, id, ex)
+	}
+	if track == nil {
+		return update()
+	}
+	return track(tx, id, update)
+}
+
+func replaceLinks(tx *sql.Tx, t libraryTables, id string, ex *dto.ExerciseRecord) error {
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, t.muscleLink, t.linkColumn), id); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s, muscular_group_id, role, activation_weight) SELECT $1, id, $3, $4 FROM public.muscular_group WHERE name = $2`, t.muscleLink, t.linkColumn)
+	for _, link := range ex.MuscularGroups {
+		if _, err := tx.Exec(query, id, link.Name, link.Role, link.ActivationWeight); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, t.equipmentLink, t.linkColumn), id); err != nil {
+		return err
+	}
+	for _, name := range ex.Equipment {
+		if _, err := tx.Exec(t.insertEquipmentLink, id, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
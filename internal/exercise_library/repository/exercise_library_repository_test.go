@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseLibraryRepository_LoadGymLibrary(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseLibraryRepository(db)
+
+	mock.ExpectQuery(`SELECT name, description, body_part FROM public.muscular_group`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "body_part"}).AddRow("chest", nil, "upper_body"))
+	mock.ExpectQuery(`SELECT name, description, category FROM public.equipment UNION ALL SELECT name, description, category FROM "gym1".custom_equipment`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "category"}).AddRow("bench", nil, "accessories").AddRow("sled", nil, "custom"))
+	mock.ExpectQuery(`SELECT (.+) FROM "gym1".custom_exercise WHERE deleted_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "synonyms", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url"}).
+			AddRow("ex1", "Floor Press", "{floor bench}", "beginner", "strength", "Press from the floor", nil, nil))
+	mock.ExpectQuery(`SELECT l.custom_exercise_id, mg.name, l.role, l.activation_weight FROM "gym1".custom_exercise_muscular_group l`).
+		WillReturnRows(sqlmock.NewRows([]string{"custom_exercise_id", "name", "role", "activation_weight"}).AddRow("ex1", "chest", "primary", 1.0))
+	mock.ExpectQuery(`SELECT l.custom_exercise_id, COALESCE\(e.name, ce.name\) AS name FROM "gym1".custom_exercise_equipment l`).
+		WillReturnRows(sqlmock.NewRows([]string{"custom_exercise_id", "name"}).AddRow("ex1", "sled"))
+
+	doc, err := repo.LoadGymLibrary("gym1")
+	assert.NoError(t, err)
+	if assert.Len(t, doc.Exercises, 1) {
+		ex := doc.Exercises[0]
+		assert.Equal(t, []string{"floor bench"}, ex.Synonyms)
+		assert.Equal(t, []dto.MuscleLinkRecord{{Name: "chest", Role: "primary", ActivationWeight: 1}}, ex.MuscularGroups)
+		assert.Equal(t, []string{"sled"}, ex.Equipment)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseLibraryRepository_ApplyPublicImport(t *testing.T) {
+	doc := &dto.LibraryDocument{
+		MuscularGroups: []dto.MuscularGroupRecord{{Name: "chest", BodyPart: "upper_body"}},
+		Exercises: []dto.ExerciseRecord{{
+			Name: "Push-up", Synonyms: []string{}, DifficultyLevel: "beginner", ExerciseType: "strength", Instructions: "Push",
+			MuscularGroups: []dto.MuscleLinkRecord{{Name: "chest", Role: "primary", ActivationWeight: 1}},
+			Equipment:      []string{},
+		}},
+	}
+
+	t.Run("inserts new exercise and replaces links in one transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		repo := NewExerciseLibraryRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO public.muscular_group (.+) ON CONFLICT \(name\) DO UPDATE`).
+			WithArgs("chest", nil, "upper_body").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM public.exercise WHERE LOWER\(name\) = LOWER\(\$1\)`).
+			WithArgs("Push-up").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(`INSERT INTO public.exercise`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ex1"))
+		mock.ExpectExec(`DELETE FROM public.exercise_muscular_group WHERE exercise_id = \$1`).
+			WithArgs("ex1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO public.exercise_muscular_group (.+) SELECT \$1, id, \$3, \$4 FROM public.muscular_group WHERE name = \$2`).
+			WithArgs("ex1", "chest", "primary", 1.0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM public.exercise_equipment WHERE exercise_id = \$1`).
+			WithArgs("ex1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ApplyPublicImport(doc, nil, nil))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("runs the update of an existing exercise through track", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		repo := NewExerciseLibraryRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO public.muscular_group`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM public.exercise`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ex1"))
+		mock.ExpectExec(`INSERT INTO public.revision`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE public.exercise SET`).
+			WithArgs("ex1", "Push-up", sqlmock.AnyArg(), "beginner", "strength", "Push", nil, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM public.exercise_muscular_group`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO public.exercise_muscular_group`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM public.exercise_equipment`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var tracked []string
+		err := repo.ApplyPublicImport(doc, nil, func(tx *sql.Tx, exerciseID string, update func() error) error {
+			tracked = append(tracked, exerciseID)
+			// Writes made by track share the import transaction
+			if _, err := tx.Exec(`INSERT INTO public.revision DEFAULT VALUES`); err != nil {
+				return err
+			}
+			return update()
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ex1"}, tracked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+		repo := NewExerciseLibraryRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO public.muscular_group`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM public.exercise`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ex1"))
+		mock.ExpectExec(`UPDATE public.exercise SET`).
+			WillReturnError(errors.New("check constraint violated"))
+		mock.ExpectRollback()
+
+		err := repo.ApplyPublicImport(doc, nil, nil)
+		assert.ErrorContains(t, err, `failed to upsert exercise "Push-up"`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewExerciseLibraryRouter(handler interfaces.ExerciseLibraryHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/public/export", handler.ExportPublicLibrary)  // GET /exercise-library/public/export?format=json|csv
+	r.Post("/public/import", handler.ImportPublicLibrary) // POST /exercise-library/public/import?format=json|csv&dry_run=true
+	r.Get("/custom/export", handler.ExportGymLibrary)     // GET /exercise-library/custom/export?format=json|csv
+	r.Post("/custom/import", handler.ImportGymLibrary)    // POST /exercise-library/custom/import?format=json|csv&dry_run=true
+
+	return r
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+	"slices"
+	"strings"
+
+	equipment_enum "github.com/alejandro-albiol/athenai/internal/equipment/enum"
+	exercise_enum "github.com/alejandro-albiol/athenai/internal/exercise/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/interfaces"
+	involvement_enum "github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/enum"
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
+	revisionIF "github.com/alejandro-albiol/athenai/internal/revision/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+const (
+	entityExercise      = "exercise"
+	entityMuscularGroup = "muscular_group"
+	entityEquipment     = "equipment"
+)
+
+var validBodyParts = map[string]bool{"upper_body": true, "lower_body": true, "core": true, "full_body": true}
+
+type ExerciseLibraryService struct {
+	repository interfaces.ExerciseLibraryRepository
+	exercises  interfaces.ExerciseSnapshotReader
+	recorder   revisionIF.Recorder
+}
+
+func NewExerciseLibraryService(repository interfaces.ExerciseLibraryRepository, exercises interfaces.ExerciseSnapshotReader, recorder revisionIF.Recorder) *ExerciseLibraryService {
+	return &ExerciseLibraryService{repository: repository, exercises: exercises, recorder: recorder}
+}
+
+func (s *ExerciseLibraryService) ExportPublicLibrary() (*dto.LibraryDocument, error) {
+	doc, err := s.repository.LoadPublicLibrary()
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load public exercise library", err)
+	}
+	return doc, nil
+}
+
+// ExportGymLibrary exports the gym's custom exercises together with the public and gym catalog entries they reference
+func (s *ExerciseLibraryService) ExportGymLibrary(gymID string) (*dto.LibraryDocument, error) {
+	doc, err := s.repository.LoadGymLibrary(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load gym exercise library", err)
+	}
+	usedGroups := map[string]bool{}
+	usedEquipment := map[string]bool{}
+	for _, ex := range doc.Exercises {
+		for _, link := range ex.MuscularGroups {
+			usedGroups[link.Name] = true
+		}
+		for _, name := range ex.Equipment {
+			usedEquipment[name] = true
+		}
+	}
+	doc.MuscularGroups = slices.DeleteFunc(doc.MuscularGroups, func(mg dto.MuscularGroupRecord) bool { return !usedGroups[mg.Name] })
+	doc.Equipment = slices.DeleteFunc(doc.Equipment, func(eq dto.EquipmentRecord) bool { return !usedEquipment[eq.Name] })
+	return doc, nil
+}
+
+func (s *ExerciseLibraryService) ImportPublicLibrary(doc *dto.LibraryDocument, createdBy *string, dryRun bool) (*dto.ImportReport, error) {
+	if isEmptyDocument(doc) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Library document is empty", nil)
+	}
+	current, err := s.repository.LoadPublicLibrary()
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load public exercise library", err)
+	}
+	report, pending := planImport(doc, current, true)
+	report.DryRun = dryRun
+	if dryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+	var author string
+	if createdBy != nil {
+		author = *createdBy
+	}
+	if err := s.repository.ApplyPublicImport(pending, createdBy, s.trackRevision(author)); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to import public exercise library", err)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// trackRevision records a revision of each public exercise the import updates, in the import transaction.
+// Gym exercises are not versioned, so gym imports are never tracked.
+func (s *ExerciseLibraryService) trackRevision(authorID string) func(tx *sql.Tx, exerciseID string, update func() error) error {
+	if s.recorder == nil || s.exercises == nil {
+		return nil
+	}
+	return func(tx *sql.Tx, exerciseID string, update func() error) error {
+		before, err := s.exercises.GetRevisionSnapshot(tx, exerciseID)
+		if err != nil {
+			return err
+		}
+		if err := update(); err != nil {
+			return err
+		}
+		after, err := s.exercises.GetRevisionSnapshot(tx, exerciseID)
+		if err != nil {
+			return err
+		}
+		return s.recorder.Record(tx, revisionEnum.Exercise, exerciseID, before, after, authorID)
+	}
+}
+
+func (s *ExerciseLibraryService) ImportGymLibrary(gymID, createdBy string, doc *dto.LibraryDocument, dryRun bool) (*dto.ImportReport, error) {
+	if isEmptyDocument(doc) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Library document is empty", nil)
+	}
+	if createdBy == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Creator is required for custom exercises", nil)
+	}
+	current, err := s.repository.LoadGymLibrary(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load gym exercise library", err)
+	}
+	report, pending := planImport(doc, current, false)
+	report.DryRun = dryRun
+	if dryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+	if err := s.repository.ApplyGymImport(gymID, createdBy, pending); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to import gym exercise library", err)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+func isEmptyDocument(doc *dto.LibraryDocument) bool {
+	return doc == nil || (len(doc.Exercises) == 0 && len(doc.MuscularGroups) == 0 && len(doc.Equipment) == 0)
+}
+
+// planImport diffs the document against the stored library by natural key.
+// It returns the report and a document holding only the entries that must be written.
+// When withCatalog is false the catalog sections are only checked against the stored catalog.
+func planImport(doc, current *dto.LibraryDocument, withCatalog bool) (*dto.ImportReport, *dto.LibraryDocument) {
+	report := &dto.ImportReport{Changes: []dto.ChangeEntry{}, Errors: []dto.RowError{}}
+	pending := &dto.LibraryDocument{Exercises: []dto.ExerciseRecord{}}
+
+	groups := map[string]dto.MuscularGroupRecord{}
+	for _, mg := range current.MuscularGroups {
+		groups[naturalKey(mg.Name)] = mg
+	}
+	equipment := map[string]dto.EquipmentRecord{}
+	for _, eq := range current.Equipment {
+		equipment[naturalKey(eq.Name)] = eq
+	}
+	exercises := map[string]dto.ExerciseRecord{}
+	for _, ex := range current.Exercises {
+		exercises[naturalKey(ex.Name)] = ex
+	}
+
+	seen := map[string]bool{}
+	for i, mg := range doc.MuscularGroups {
+		mg.Name = strings.TrimSpace(mg.Name)
+		mg.Description = trimOptional(mg.Description)
+		key := naturalKey(mg.Name)
+		existing, found := groups[key]
+		msg := ""
+		switch {
+		case mg.Name == "":
+			msg = "Name is required"
+		case seen[key]:
+			msg = "Duplicate muscular group in document"
+		case !withCatalog && !found:
+			msg = "Muscular group not found in the public catalog"
+		case withCatalog && !validBodyParts[mg.BodyPart]:
+			msg = "Invalid body part, must be upper_body, lower_body, core or full_body"
+		}
+		if msg != "" {
+			report.Errors = append(report.Errors, dto.RowError{Entity: entityMuscularGroup, Row: i + 1, Name: mg.Name, Message: msg})
+			continue
+		}
+		seen[key] = true
+		if !withCatalog {
+			continue
+		}
+		var fields []string
+		if found {
+			mg.Name = existing.Name
+			if !equalOptional(mg.Description, existing.Description) {
+				fields = append(fields, "description")
+			}
+			if mg.BodyPart != existing.BodyPart {
+				fields = append(fields, "body_part")
+			}
+		}
+		if record(report, entityMuscularGroup, mg.Name, found, fields) {
+			pending.MuscularGroups = append(pending.MuscularGroups, mg)
+		}
+		groups[key] = mg
+	}
+
+	seen = map[string]bool{}
+	for i, eq := range doc.Equipment {
+		eq.Name = strings.TrimSpace(eq.Name)
+		eq.Description = trimOptional(eq.Description)
+		key := naturalKey(eq.Name)
+		existing, found := equipment[key]
+		msg := ""
+		switch {
+		case eq.Name == "":
+			msg = "Name is required"
+		case seen[key]:
+			msg = "Duplicate equipment in document"
+		case !withCatalog && !found:
+			msg = "Equipment not found in the public or gym catalog"
+		case withCatalog && !equipment_enum.EquipmentCategory(eq.Category).IsValid():
+			msg = "Invalid equipment category"
+		}
+		if msg != "" {
+			report.Errors = append(report.Errors, dto.RowError{Entity: entityEquipment, Row: i + 1, Name: eq.Name, Message: msg})
+			continue
+		}
+		seen[key] = true
+		if !withCatalog {
+			continue
+		}
+		var fields []string
+		if found {
+			eq.Name = existing.Name
+			if !equalOptional(eq.Description, existing.Description) {
+				fields = append(fields, "description")
+			}
+			if eq.Category != existing.Category {
+				fields = append(fields, "category")
+			}
+		}
+		if record(report, entityEquipment, eq.Name, found, fields) {
+			pending.Equipment = append(pending.Equipment, eq)
+		}
+		equipment[key] = eq
+	}
+
+	seen = map[string]bool{}
+	for i, ex := range doc.Exercises {
+		key := naturalKey(ex.Name)
+		msg := normalizeExercise(&ex, groups, equipment)
+		if msg == "" && seen[key] {
+			msg = "Duplicate exercise in document"
+		}
+		if msg != "" {
+			report.Errors = append(report.Errors, dto.RowError{Entity: entityExercise, Row: i + 1, Name: ex.Name, Message: msg})
+			continue
+		}
+		seen[key] = true
+		existing, found := exercises[key]
+		var fields []string
+		if found {
+			fields = diffExercise(&ex, &existing)
+		}
+		if record(report, entityExercise, ex.Name, found, fields) {
+			pending.Exercises = append(pending.Exercises, ex)
+		}
+	}
+
+	return report, pending
+}
+
+// record adds the change entry and reports whether the entry has to be written
+func record(report *dto.ImportReport, entity, name string, found bool, fields []string) bool {
+	action := enum.Create
+	switch {
+	case found && len(fields) == 0:
+		action = enum.Unchanged
+		report.Unchanged++
+	case found:
+		action = enum.Update
+		report.Updated++
+	default:
+		report.Created++
+	}
+	report.Changes = append(report.Changes, dto.ChangeEntry{Entity: entity, Name: name, Action: string(action), Fields: fields})
+	return action != enum.Unchanged
+}
+
+// normalizeExercise trims and defaults the record, resolves catalog names to their stored spelling
+// and returns a validation message, or an empty string when the record is valid
+func normalizeExercise(ex *dto.ExerciseRecord, groups map[string]dto.MuscularGroupRecord, equipment map[string]dto.EquipmentRecord) string {
+	ex.Name = strings.TrimSpace(ex.Name)
+	ex.Instructions = strings.TrimSpace(ex.Instructions)
+	ex.VideoURL = trimOptional(ex.VideoURL)
+	ex.ImageURL = trimOptional(ex.ImageURL)
+	if ex.Name == "" {
+		return "Name is required"
+	}
+	if !exercise_enum.DifficultyLevel(ex.DifficultyLevel).IsValid() {
+		return "Invalid difficulty level"
+	}
+	if !exercise_enum.ExerciseType(ex.ExerciseType).IsValid() {
+		return "Invalid exercise type"
+	}
+	if ex.Instructions == "" {
+		return "Instructions are required"
+	}
+
+	synonyms := []string{}
+	for _, synonym := range ex.Synonyms {
+		synonym = strings.TrimSpace(synonym)
+		if synonym != "" && !slices.Contains(synonyms, synonym) {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	ex.Synonyms = synonyms
+
+	links := []dto.MuscleLinkRecord{}
+	for _, link := range ex.MuscularGroups {
+		mg, ok := groups[naturalKey(link.Name)]
+		if !ok {
+			return "Unknown muscular group: " + link.Name
+		}
+		link.Name = mg.Name
+		if slices.ContainsFunc(links, func(l dto.MuscleLinkRecord) bool { return l.Name == link.Name }) {
+			return "Duplicate muscular group link: " + link.Name
+		}
+		var msg string
+		if link.Role, link.ActivationWeight, msg = involvement_enum.NormalizeInvolvement(link.Role, link.ActivationWeight); msg != "" {
+			return msg
+		}
+		links = append(links, link)
+	}
+	slices.SortFunc(links, func(a, b dto.MuscleLinkRecord) int { return strings.Compare(a.Name, b.Name) })
+	ex.MuscularGroups = links
+
+	names := []string{}
+	for _, name := range ex.Equipment {
+		eq, ok := equipment[naturalKey(name)]
+		if !ok {
+			return "Unknown equipment: " + name
+		}
+		if !slices.Contains(names, eq.Name) {
+			names = append(names, eq.Name)
+		}
+	}
+	slices.Sort(names)
+	ex.Equipment = names
+	return ""
+}
+
+// diffExercise lists the fields of a normalized record that differ from the stored exercise
+func diffExercise(ex, existing *dto.ExerciseRecord) []string {
+	var fields []string
+	if ex.Name != existing.Name {
+		fields = append(fields, "name")
+	}
+	if !slices.Equal(ex.Synonyms, existing.Synonyms) {
+		fields = append(fields, "synonyms")
+	}
+	if ex.DifficultyLevel != existing.DifficultyLevel {
+		fields = append(fields, "difficulty_level")
+	}
+	if ex.ExerciseType != existing.ExerciseType {
+		fields = append(fields, "exercise_type")
+	}
+	if ex.Instructions != existing.Instructions {
+		fields = append(fields, "instructions")
+	}
+	if !equalOptional(ex.VideoURL, existing.VideoURL) {
+		fields = append(fields, "video_url")
+	}
+	if !equalOptional(ex.ImageURL, existing.ImageURL) {
+		fields = append(fields, "image_url")
+	}
+
+	storedLinks := slices.Clone(existing.MuscularGroups)
+	slices.SortFunc(storedLinks, func(a, b dto.MuscleLinkRecord) int { return strings.Compare(a.Name, b.Name) })
+	if !slices.EqualFunc(ex.MuscularGroups, storedLinks, func(a, b dto.MuscleLinkRecord) bool {
+		return a.Name == b.Name && a.Role == b.Role && math.Abs(a.ActivationWeight-b.ActivationWeight) < 0.005
+	}) {
+		fields = append(fields, "muscular_groups")
+	}
+
+	storedEquipment := slices.Clone(existing.Equipment)
+	slices.Sort(storedEquipment)
+	if !slices.Equal(ex.Equipment, storedEquipment) {
+		fields = append(fields, "equipment")
+	}
+	return fields
+}
+
+func naturalKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func equalOptional(a, b *string) bool {
+	a, b = trimOptional(a), trimOptional(b)
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
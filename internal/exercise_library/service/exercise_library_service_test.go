@@ -0,0 +1,253 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	exerciseDTO "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepo struct {
+	LoadPublicLibraryFunc func() (*dto.LibraryDocument, error)
+	LoadGymLibraryFunc    func(gymID string) (*dto.LibraryDocument, error)
+	ApplyPublicImportFunc func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error
+	ApplyGymImportFunc    func(gymID, createdBy string, doc *dto.LibraryDocument) error
+}
+
+func (m *mockRepo) LoadPublicLibrary() (*dto.LibraryDocument, error) {
+	return m.LoadPublicLibraryFunc()
+}
+func (m *mockRepo) LoadGymLibrary(gymID string) (*dto.LibraryDocument, error) {
+	return m.LoadGymLibraryFunc(gymID)
+}
+func (m *mockRepo) ApplyPublicImport(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+	return m.ApplyPublicImportFunc(doc, createdBy, track)
+}
+func (m *mockRepo) ApplyGymImport(gymID, createdBy string, doc *dto.LibraryDocument) error {
+	return m.ApplyGymImportFunc(gymID, createdBy, doc)
+}
+
+type mockSnapshots struct {
+	GetRevisionSnapshotFunc func(tx *sql.Tx, id string) (*exerciseDTO.ExerciseRevisionSnapshot, error)
+}
+
+func (m *mockSnapshots) GetRevisionSnapshot(tx *sql.Tx, id string) (*exerciseDTO.ExerciseRevisionSnapshot, error) {
+	return m.GetRevisionSnapshotFunc(tx, id)
+}
+
+type mockRecorder struct {
+	RecordFunc func(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error
+}
+
+func (m *mockRecorder) Record(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+	return m.RecordFunc(tx, entityType, entityID, before, after, authorID)
+}
+
+func storedLibrary() *dto.LibraryDocument {
+	return &dto.LibraryDocument{
+		MuscularGroups: []dto.MuscularGroupRecord{{Name: "chest", BodyPart: "upper_body"}, {Name: "triceps", BodyPart: "upper_body"}},
+		Equipment:      []dto.EquipmentRecord{{Name: "barbell", Category: "free_weights"}, {Name: "bench", Category: "accessories"}},
+		Exercises: []dto.ExerciseRecord{{
+			Name:            "Bench Press",
+			Synonyms:        []string{"flat bench"},
+			DifficultyLevel: "intermediate",
+			ExerciseType:    "strength",
+			Instructions:    "Press the bar",
+			MuscularGroups:  []dto.MuscleLinkRecord{{Name: "chest", Role: "primary", ActivationWeight: 1}, {Name: "triceps", Role: "secondary", ActivationWeight: 0.5}},
+			Equipment:       []string{"barbell", "bench"},
+		}},
+	}
+}
+
+func TestExerciseLibraryService_ImportPublicLibrary(t *testing.T) {
+	t.Run("dry run reports the diff without writing", func(t *testing.T) {
+		repo := &mockRepo{LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil }}
+		svc := NewExerciseLibraryService(repo, nil, nil)
+		doc := &dto.LibraryDocument{
+			MuscularGroups: []dto.MuscularGroupRecord{{Name: "front delts", BodyPart: "upper_body"}},
+			Exercises: []dto.ExerciseRecord{
+				{Name: "bench press", Synonyms: []string{"flat bench"}, DifficultyLevel: "intermediate", ExerciseType: "strength", Instructions: "Press the bar",
+					MuscularGroups: []dto.MuscleLinkRecord{{Name: "Triceps", Role: "secondary"}, {Name: "chest"}}, Equipment: []string{"bench", "Barbell"}},
+				{Name: "Incline Press", DifficultyLevel: "intermediate", ExerciseType: "strength", Instructions: "Press on an incline",
+					MuscularGroups: []dto.MuscleLinkRecord{{Name: "front delts", Role: "secondary", ActivationWeight: 0.6}}},
+			},
+		}
+		report, err := svc.ImportPublicLibrary(doc, nil, true)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Applied)
+		assert.Empty(t, report.Errors)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 0, report.Unchanged)
+		// Only the casing of the name changed, links resolve to the stored spelling
+		assert.Equal(t, dto.ChangeEntry{Entity: "exercise", Name: "bench press", Action: "update", Fields: []string{"name"}}, report.Changes[1])
+	})
+
+	t.Run("applies only created and updated entries", func(t *testing.T) {
+		var applied *dto.LibraryDocument
+		repo := &mockRepo{
+			LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil },
+			ApplyPublicImportFunc: func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+				applied = doc
+				return nil
+			},
+		}
+		svc := NewExerciseLibraryService(repo, nil, nil)
+		doc := storedLibrary()
+		doc.Exercises = append(doc.Exercises, dto.ExerciseRecord{Name: "Dips", DifficultyLevel: "intermediate", ExerciseType: "strength", Instructions: "Lower and press",
+			MuscularGroups: []dto.MuscleLinkRecord{{Name: "triceps", Role: "stabilizer"}}})
+		report, err := svc.ImportPublicLibrary(doc, nil, false)
+		assert.NoError(t, err)
+		assert.True(t, report.Applied)
+		assert.Equal(t, 5, report.Unchanged)
+		assert.Equal(t, 1, report.Created)
+		if assert.Len(t, applied.Exercises, 1) {
+			assert.Equal(t, "Dips", applied.Exercises[0].Name)
+			assert.Equal(t, 0.25, applied.Exercises[0].MuscularGroups[0].ActivationWeight)
+			assert.Equal(t, []string{}, applied.Exercises[0].Synonyms)
+		}
+		assert.Empty(t, applied.MuscularGroups)
+		assert.Empty(t, applied.Equipment)
+	})
+
+	t.Run("records a revision of each updated exercise", func(t *testing.T) {
+		var steps []string
+		snapshots := &mockSnapshots{GetRevisionSnapshotFunc: func(tx *sql.Tx, id string) (*exerciseDTO.ExerciseRevisionSnapshot, error) {
+			steps = append(steps, "snapshot "+id)
+			return &exerciseDTO.ExerciseRevisionSnapshot{Name: "Bench Press"}, nil
+		}}
+		recorder := &mockRecorder{RecordFunc: func(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+			steps = append(steps, "record "+entityID)
+			assert.Equal(t, revisionEnum.Exercise, entityType)
+			assert.Equal(t, "user1", authorID)
+			return nil
+		}}
+		repo := &mockRepo{
+			LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil },
+			ApplyPublicImportFunc: func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+				return track(nil, "ex1", func() error {
+					steps = append(steps, "update ex1")
+					return nil
+				})
+			},
+		}
+		svc := NewExerciseLibraryService(repo, snapshots, recorder)
+		doc := storedLibrary()
+		doc.Exercises[0].Instructions = "Press the bar to lockout"
+		createdBy := "user1"
+		_, err := svc.ImportPublicLibrary(doc, &createdBy, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"snapshot ex1", "update ex1", "snapshot ex1", "record ex1"}, steps)
+	})
+
+	t.Run("revision failure fails the import", func(t *testing.T) {
+		snapshots := &mockSnapshots{GetRevisionSnapshotFunc: func(tx *sql.Tx, id string) (*exerciseDTO.ExerciseRevisionSnapshot, error) {
+			return &exerciseDTO.ExerciseRevisionSnapshot{}, nil
+		}}
+		recorder := &mockRecorder{RecordFunc: func(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+			return errors.New("insert revision failed")
+		}}
+		repo := &mockRepo{
+			LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil },
+			ApplyPublicImportFunc: func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+				return track(nil, "ex1", func() error { return nil })
+			},
+		}
+		svc := NewExerciseLibraryService(repo, snapshots, recorder)
+		doc := storedLibrary()
+		doc.Exercises[0].Instructions = "Press the bar to lockout"
+		_, err := svc.ImportPublicLibrary(doc, nil, false)
+		assert.Equal(t, errorcode_enum.CodeInternal, err.(*apierror.APIError).Code)
+	})
+
+	t.Run("validation errors block the whole import", func(t *testing.T) {
+		repo := &mockRepo{
+			LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil },
+			ApplyPublicImportFunc: func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+				t.Fatal("import with errors must not be applied")
+				return nil
+			},
+		}
+		svc := NewExerciseLibraryService(repo, nil, nil)
+		doc := &dto.LibraryDocument{
+			Equipment: []dto.EquipmentRecord{{Name: "sled", Category: "vehicles"}},
+			Exercises: []dto.ExerciseRecord{
+				{Name: "Push-up", DifficultyLevel: "beginner", ExerciseType: "strength", Instructions: "Push", MuscularGroups: []dto.MuscleLinkRecord{{Name: "pecs"}}},
+				{Name: "Squat", DifficultyLevel: "expert", ExerciseType: "strength", Instructions: "Sit"},
+				{Name: "Row", DifficultyLevel: "beginner", ExerciseType: "strength", Instructions: "Pull"},
+				{Name: "row", DifficultyLevel: "beginner", ExerciseType: "strength", Instructions: "Pull"},
+			},
+		}
+		report, err := svc.ImportPublicLibrary(doc, nil, false)
+		assert.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Equal(t, []dto.RowError{
+			{Entity: "equipment", Row: 1, Name: "sled", Message: "Invalid equipment category"},
+			{Entity: "exercise", Row: 1, Name: "Push-up", Message: "Unknown muscular group: pecs"},
+			{Entity: "exercise", Row: 2, Name: "Squat", Message: "Invalid difficulty level"},
+			{Entity: "exercise", Row: 4, Name: "row", Message: "Duplicate exercise in document"},
+		}, report.Errors)
+	})
+
+	t.Run("rejects empty document", func(t *testing.T) {
+		svc := NewExerciseLibraryService(&mockRepo{}, nil, nil)
+		_, err := svc.ImportPublicLibrary(&dto.LibraryDocument{}, nil, false)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := &mockRepo{
+			LoadPublicLibraryFunc: func() (*dto.LibraryDocument, error) { return storedLibrary(), nil },
+			ApplyPublicImportFunc: func(doc *dto.LibraryDocument, createdBy *string, track func(tx *sql.Tx, exerciseID string, update func() error) error) error {
+				return errors.New("tx aborted")
+			},
+		}
+		svc := NewExerciseLibraryService(repo, nil, nil)
+		doc := storedLibrary()
+		doc.Exercises[0].Instructions = "Press the bar to lockout"
+		_, err := svc.ImportPublicLibrary(doc, nil, false)
+		assert.Equal(t, errorcode_enum.CodeInternal, err.(*apierror.APIError).Code)
+	})
+}
+
+func TestExerciseLibraryService_ImportGymLibrary(t *testing.T) {
+	repo := &mockRepo{LoadGymLibraryFunc: func(gymID string) (*dto.LibraryDocument, error) {
+		doc := storedLibrary()
+		doc.Exercises = nil
+		return doc, nil
+	}}
+	svc := NewExerciseLibraryService(repo, nil, nil)
+	doc := &dto.LibraryDocument{
+		MuscularGroups: []dto.MuscularGroupRecord{{Name: "chest", BodyPart: "lower_body"}, {Name: "glutes", BodyPart: "lower_body"}},
+		Exercises:      []dto.ExerciseRecord{{Name: "Sled Push", DifficultyLevel: "advanced", ExerciseType: "functional", Instructions: "Push the sled"}},
+	}
+	report, err := svc.ImportGymLibrary("gym1", "user1", doc, true)
+	assert.NoError(t, err)
+	// Gym imports never change the public catalog, unknown entries are errors
+	assert.Equal(t, []dto.RowError{{Entity: "muscular_group", Row: 2, Name: "glutes", Message: "Muscular group not found in the public catalog"}}, report.Errors)
+	assert.Equal(t, 1, report.Created)
+
+	_, err = svc.ImportGymLibrary("gym1", "", doc, true)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
+
+func TestExerciseLibraryService_ExportGymLibrary(t *testing.T) {
+	repo := &mockRepo{LoadGymLibraryFunc: func(gymID string) (*dto.LibraryDocument, error) {
+		doc := storedLibrary()
+		doc.Exercises[0].MuscularGroups = doc.Exercises[0].MuscularGroups[:1]
+		doc.Exercises[0].Equipment = []string{"bench"}
+		return doc, nil
+	}}
+	svc := NewExerciseLibraryService(repo, nil, nil)
+	doc, err := svc.ExportGymLibrary("gym1")
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.MuscularGroupRecord{{Name: "chest", BodyPart: "upper_body"}}, doc.MuscularGroups)
+	assert.Equal(t, []dto.EquipmentRecord{{Name: "bench", Category: "accessories"}}, doc.Equipment)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+// CSV documents carry one exercise per row. List cells are separated by "|" and
+// muscular group cells are written as name:role:activation_weight.
+// The catalog sections are JSON only, so CSV imports link to catalog entries that already exist.
+var csvHeader = []string{"name", "synonyms", "difficulty_level", "exercise_type", "instructions", "video_url", "image_url", "muscular_groups", "equipment"}
+
+const (
+	csvListSeparator = "|"
+	csvLinkSeparator = ":"
+)
+
+// DecodeLibrary reads a library document in the given format
+func DecodeLibrary(format enum.LibraryFormat, r io.Reader) (*dto.LibraryDocument, error) {
+	switch format {
+	case enum.JSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		var doc dto.LibraryDocument
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid JSON library document", err)
+		}
+		return &doc, nil
+	case enum.CSV:
+		return decodeCSV(r)
+	}
+	return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid format, must be json or csv", nil)
+}
+
+// EncodeLibrary writes a library document in the given format
+func EncodeLibrary(format enum.LibraryFormat, w io.Writer, doc *dto.LibraryDocument) error {
+	switch format {
+	case enum.JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case enum.CSV:
+		return encodeCSV(w, doc)
+	}
+	return apierror.New(errorcode_enum.CodeBadRequest, "Invalid format, must be json or csv", nil)
+}
+
+func decodeCSV(r io.Reader) (*dto.LibraryDocument, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid CSV library document", err)
+	}
+	if len(rows) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "CSV library document has no header", nil)
+	}
+
+	columns := map[string]int{}
+	for i, column := range rows[0] {
+		column = strings.TrimSpace(column)
+		if !slices.Contains(csvHeader, column) {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Unknown CSV column %q", column), nil)
+		}
+		columns[column] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "CSV library document must have a name column", nil)
+	}
+
+	doc := &dto.LibraryDocument{Exercises: []dto.ExerciseRecord{}}
+	for line, row := range rows[1:] {
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		ex := dto.ExerciseRecord{
+			Name:            cell("name"),
+			Synonyms:        splitList(cell("synonyms")),
+			DifficultyLevel: cell("difficulty_level"),
+			ExerciseType:    cell("exercise_type"),
+			Instructions:    cell("instructions"),
+			VideoURL:        optionalCell(cell("video_url")),
+			ImageURL:        optionalCell(cell("image_url")),
+			MuscularGroups:  []dto.MuscleLinkRecord{},
+			Equipment:       splitList(cell("equipment")),
+		}
+		for _, item := range splitList(cell("muscular_groups")) {
+			parts := strings.Split(item, csvLinkSeparator)
+			link := dto.MuscleLinkRecord{Name: strings.TrimSpace(parts[0])}
+			if len(parts) > 1 {
+				link.Role = strings.TrimSpace(parts[1])
+			}
+			if len(parts) > 2 {
+				weight, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+				if err != nil {
+					// line is zero-based over data rows, the header is row 1
+					return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Invalid activation weight on CSV row %d", line+2), err)
+				}
+				link.ActivationWeight = weight
+			}
+			ex.MuscularGroups = append(ex.MuscularGroups, link)
+		}
+		doc.Exercises = append(doc.Exercises, ex)
+	}
+	return doc, nil
+}
+
+func encodeCSV(w io.Writer, doc *dto.LibraryDocument) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, ex := range doc.Exercises {
+		links := make([]string, 0, len(ex.MuscularGroups))
+		for _, link := range ex.MuscularGroups {
+			links = append(links, strings.Join([]string{link.Name, link.Role, strconv.FormatFloat(link.ActivationWeight, 'f', -1, 64)}, csvLinkSeparator))
+		}
+		row := []string{
+			ex.Name,
+			strings.Join(ex.Synonyms, csvListSeparator),
+			ex.DifficultyLevel,
+			ex.ExerciseType,
+			ex.Instructions,
+			valueOrEmpty(ex.VideoURL),
+			valueOrEmpty(ex.ImageURL),
+			strings.Join(links, csvListSeparator),
+			strings.Join(ex.Equipment, csvListSeparator),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func optionalCell(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_library/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibraryCodec_RoundTrip(t *testing.T) {
+	video := "https://example.com/bench.mp4"
+	original := storedLibrary()
+	original.Exercises[0].VideoURL = &video
+
+	for _, format := range []enum.LibraryFormat{enum.JSON, enum.CSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, EncodeLibrary(format, &buf, original))
+			decoded, err := DecodeLibrary(format, &buf)
+			assert.NoError(t, err)
+			assert.Equal(t, original.Exercises, decoded.Exercises)
+			if format == enum.JSON {
+				assert.Equal(t, original.MuscularGroups, decoded.MuscularGroups)
+			}
+		})
+	}
+}
+
+func TestLibraryCodec_DecodeCSV(t *testing.T) {
+	input := "name,instructions,muscular_groups,equipment\n" +
+		"Goblet Squat,Hold the bell at the chest,quadriceps|glutes:secondary|core:stabilizer:0.3,kettlebell\n"
+	doc, err := DecodeLibrary(enum.CSV, strings.NewReader(input))
+	assert.NoError(t, err)
+	if assert.Len(t, doc.Exercises, 1) {
+		ex := doc.Exercises[0]
+		assert.Equal(t, "Goblet Squat", ex.Name)
+		assert.Nil(t, ex.VideoURL)
+		assert.Equal(t, []string{"kettlebell"}, ex.Equipment)
+		assert.Equal(t, "", ex.MuscularGroups[0].Role)
+		assert.Equal(t, "secondary", ex.MuscularGroups[1].Role)
+		assert.Equal(t, 0.3, ex.MuscularGroups[2].ActivationWeight)
+	}
+
+	_, err = DecodeLibrary(enum.CSV, strings.NewReader("name,muscles\nSquat,quads\n"))
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+
+	_, err = DecodeLibrary(enum.CSV, strings.NewReader("name,muscular_groups\nSquat,quads:primary:heavy\n"))
+	assert.Equal(t, "Invalid activation weight on CSV row 2", err.(*apierror.APIError).Message)
+}
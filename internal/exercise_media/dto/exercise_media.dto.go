@@ -0,0 +1,18 @@
+package dto
+
+// MediaAsset is an uploaded image or video of a public or custom exercise.
+// Storage keys never leave the API; clients get signed, time-limited URLs instead.
+type MediaAsset struct {
+	ID               string  `json:"id"`
+	ExerciseID       string  `json:"exercise_id"`
+	Kind             string  `json:"kind"` // image or video
+	ContentType      string  `json:"content_type"`
+	SizeBytes        int64   `json:"size_bytes"`
+	OriginalFilename *string `json:"original_filename,omitempty"`
+	StorageKey       string  `json:"-"`
+	ThumbnailKey     *string `json:"-"`
+	URL              string  `json:"url,omitempty"`
+	ThumbnailURL     *string `json:"thumbnail_url,omitempty"`
+	URLExpiresAt     string  `json:"url_expires_at,omitempty"`
+	CreatedAt        string  `json:"created_at,omitempty"`
+}
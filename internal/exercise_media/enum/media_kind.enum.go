@@ -0,0 +1,16 @@
+package enum
+
+type MediaKind string
+
+const (
+	Image MediaKind = "image"
+	Video MediaKind = "video"
+)
+
+func (k MediaKind) IsValid() bool {
+	switch k {
+	case Image, Video:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// uploadField is the multipart form field that carries the file
+const uploadField = "file"
+
+type ExerciseMediaHandler struct {
+	service interfaces.ExerciseMediaService
+}
+
+func NewExerciseMediaHandler(service interfaces.ExerciseMediaService) *ExerciseMediaHandler {
+	return &ExerciseMediaHandler{service: service}
+}
+
+func (h *ExerciseMediaHandler) UploadExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can upload media for public exercises",
+			nil,
+		))
+		return
+	}
+	h.upload(w, r, "")
+}
+
+func (h *ExerciseMediaHandler) GetExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "")
+}
+
+func (h *ExerciseMediaHandler) DeleteExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsPlatformAdmin(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only platform administrators can delete media of public exercises",
+			nil,
+		))
+		return
+	}
+	h.delete(w, r, "")
+}
+
+func (h *ExerciseMediaHandler) UploadCustomExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	if !canManageCustomMedia(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only gym administrators and trainers can upload exercise media",
+			nil,
+		))
+		return
+	}
+	h.upload(w, r, middleware.GetGymID(r))
+}
+
+func (h *ExerciseMediaHandler) GetCustomExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, middleware.GetGymID(r))
+}
+
+func (h *ExerciseMediaHandler) DeleteCustomExerciseMedia(w http.ResponseWriter, r *http.Request) {
+	if !canManageCustomMedia(r) {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeForbidden,
+			"Access denied: Only gym administrators and trainers can delete exercise media",
+			nil,
+		))
+		return
+	}
+	h.delete(w, r, middleware.GetGymID(r))
+}
+
+// ServeFile streams a file behind a signed URL. It is mounted outside the auth middleware
+// so the links work in <img> and <video> tags; the signature is the credential.
+func (h *ExerciseMediaHandler) ServeFile(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	file, contentType, err := h.service.OpenFile(key, r.URL.Query().Get("expires"), r.URL.Query().Get("signature"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to read media")
+		return
+	}
+	defer file.Close()
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+func (h *ExerciseMediaHandler) upload(w http.ResponseWriter, r *http.Request, gymID string) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		response.WriteAPIError(w, apierror.New(
+			errorcode_enum.CodeBadRequest,
+			"Expected a multipart/form-data upload",
+			err,
+		))
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Missing file field", nil))
+			return
+		}
+		if err != nil {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid multipart payload", err))
+			return
+		}
+		if part.FormName() != uploadField {
+			part.Close()
+			continue
+		}
+		asset, err := h.service.UploadMedia(gymID, chi.URLParam(r, "exerciseID"), part.FileName(), part)
+		part.Close()
+		if err != nil {
+			response.WriteAPIErrorOr(w, err, "Failed to upload media")
+			return
+		}
+		response.WriteAPISuccess(w, "Media uploaded successfully", asset)
+		return
+	}
+}
+
+func (h *ExerciseMediaHandler) list(w http.ResponseWriter, r *http.Request, gymID string) {
+	assets, err := h.service.GetMediaByExerciseID(gymID, chi.URLParam(r, "exerciseID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get exercise media")
+		return
+	}
+	response.WriteAPISuccess(w, "Exercise media retrieved successfully", assets)
+}
+
+func (h *ExerciseMediaHandler) delete(w http.ResponseWriter, r *http.Request, gymID string) {
+	if err := h.service.DeleteMedia(gymID, chi.URLParam(r, "id")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete media")
+		return
+	}
+	response.WriteAPISuccess(w, "Media deleted successfully", nil)
+}
+
+func canManageCustomMedia(r *http.Request) bool {
+	return middleware.IsGymAdmin(r) || middleware.GetUserRole(r) == string(user_enum.Trainer)
+}
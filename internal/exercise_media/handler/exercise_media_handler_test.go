@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	UploadMediaFunc func(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error)
+	OpenFileFunc    func(key, expires, signature string) (io.ReadCloser, string, error)
+}
+
+func (m *mockService) UploadMedia(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error) {
+	return m.UploadMediaFunc(gymID, exerciseID, filename, body)
+}
+func (m *mockService) GetMediaByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error) {
+	return nil, nil
+}
+func (m *mockService) DeleteMedia(gymID, id string) error { return nil }
+func (m *mockService) OpenFile(key, expires, signature string) (io.ReadCloser, string, error) {
+	return m.OpenFileFunc(key, expires, signature)
+}
+func (m *mockService) CleanupExerciseMedia(gymID, exerciseID string) error { return nil }
+
+func multipartRequest(t *testing.T, field, filename string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("caption", "ignored")
+	part, err := writer.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	part.Write(content)
+	writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/custom-exercise/ex1", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func withUser(req *http.Request, userType, role, gymID, exerciseID string) *http.Request {
+	ctx := context.WithValue(req.Context(), middleware.UserTypeKey, userType)
+	ctx = context.WithValue(ctx, middleware.UserRoleKey, role)
+	ctx = context.WithValue(ctx, middleware.GymIDKey, gymID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("exerciseID", exerciseID)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func TestExerciseMediaHandler_UploadCustomExerciseMedia(t *testing.T) {
+	t.Run("trainer uploads into their gym", func(t *testing.T) {
+		h := NewExerciseMediaHandler(&mockService{
+			UploadMediaFunc: func(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error) {
+				assert.Equal(t, "gym1", gymID)
+				assert.Equal(t, "ex1", exerciseID)
+				assert.Equal(t, "squat.png", filename)
+				content, _ := io.ReadAll(body)
+				assert.Equal(t, "png-bytes", string(content))
+				return &dto.MediaAsset{ID: "media-1", Kind: "image"}, nil
+			},
+		})
+		req := withUser(multipartRequest(t, "file", "squat.png", []byte("png-bytes")), "tenant_user", "trainer", "gym1", "ex1")
+		w := httptest.NewRecorder()
+		h.UploadCustomExerciseMedia(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "media-1")
+	})
+
+	t.Run("member is forbidden", func(t *testing.T) {
+		h := NewExerciseMediaHandler(&mockService{})
+		req := withUser(multipartRequest(t, "file", "squat.png", []byte("x")), "tenant_user", "member", "gym1", "ex1")
+		w := httptest.NewRecorder()
+		h.UploadCustomExerciseMedia(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("missing file field", func(t *testing.T) {
+		h := NewExerciseMediaHandler(&mockService{})
+		req := withUser(multipartRequest(t, "upload", "squat.png", []byte("x")), "tenant_user", "admin", "gym1", "ex1")
+		w := httptest.NewRecorder()
+		h.UploadCustomExerciseMedia(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service rejection is passed through", func(t *testing.T) {
+		h := NewExerciseMediaHandler(&mockService{
+			UploadMediaFunc: func(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error) {
+				return nil, apierror.New(errorcode_enum.CodeBadRequest, "Unsupported content type", nil)
+			},
+		})
+		req := withUser(multipartRequest(t, "file", "notes.txt", []byte("text")), "tenant_user", "admin", "gym1", "ex1")
+		w := httptest.NewRecorder()
+		h.UploadCustomExerciseMedia(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExerciseMediaHandler_UploadExerciseMedia_RequiresPlatformAdmin(t *testing.T) {
+	h := NewExerciseMediaHandler(&mockService{})
+	req := withUser(multipartRequest(t, "file", "squat.png", []byte("x")), "tenant_user", "admin", "gym1", "ex1")
+	w := httptest.NewRecorder()
+	h.UploadExerciseMedia(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestExerciseMediaHandler_ServeFile(t *testing.T) {
+	h := NewExerciseMediaHandler(&mockService{
+		OpenFileFunc: func(key, expires, signature string) (io.ReadCloser, string, error) {
+			if signature != "good" {
+				return nil, "", apierror.New(errorcode_enum.CodeForbidden, "Media link is invalid or has expired", nil)
+			}
+			assert.Equal(t, "gym1/exercises/ex1/a.png", key)
+			return io.NopCloser(strings.NewReader("png-bytes")), "image/png", nil
+		},
+	})
+	r := chi.NewRouter()
+	r.Get("/*", h.ServeFile)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gym1/exercises/ex1/a.png?expires=1&signature=good", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "png-bytes", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gym1/exercises/ex1/a.png?expires=1&signature=bad", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "net/http"
+
+type ExerciseMediaHandler interface {
+	UploadExerciseMedia(w http.ResponseWriter, r *http.Request)
+	GetExerciseMedia(w http.ResponseWriter, r *http.Request)
+	DeleteExerciseMedia(w http.ResponseWriter, r *http.Request)
+	UploadCustomExerciseMedia(w http.ResponseWriter, r *http.Request)
+	GetCustomExerciseMedia(w http.ResponseWriter, r *http.Request)
+	DeleteCustomExerciseMedia(w http.ResponseWriter, r *http.Request)
+	ServeFile(w http.ResponseWriter, r *http.Request)
+}
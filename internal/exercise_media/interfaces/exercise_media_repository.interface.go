@@ -0,0 +1,14 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+
+// ExerciseMediaRepository stores media metadata. An empty gymID targets public exercises,
+// otherwise the gym's custom exercises.
+type ExerciseMediaRepository interface {
+	ExerciseExists(gymID, exerciseID string) (bool, error)
+	CreateAsset(gymID string, asset *dto.MediaAsset) (*string, error)
+	FindByID(gymID, id string) (*dto.MediaAsset, error)
+	FindByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error)
+	DeleteAsset(gymID, id string) error
+	DeleteByExerciseID(gymID, exerciseID string) error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"io"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+)
+
+type ExerciseMediaService interface {
+	UploadMedia(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error)
+	GetMediaByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error)
+	DeleteMedia(gymID, id string) error
+	OpenFile(key, expires, signature string) (io.ReadCloser, string, error)
+	MediaCleaner
+}
+
+// MediaCleaner removes every file of an exercise. Exercise services call it when an exercise is deleted.
+type MediaCleaner interface {
+	CleanupExerciseMedia(gymID, exerciseID string) error
+}
@@ -0,0 +1,39 @@
+package module
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/handler"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/router"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/service"
+	"github.com/alejandro-albiol/athenai/pkg/storage"
+)
+
+// ExerciseMediaModule holds the authenticated media router and the public router for signed links
+type ExerciseMediaModule struct {
+	Router     http.Handler
+	FileRouter http.Handler
+}
+
+func NewExerciseMediaModule(db *sql.DB) *ExerciseMediaModule {
+	handler := handler.NewExerciseMediaHandler(NewExerciseMediaService(db))
+	return &ExerciseMediaModule{
+		Router:     router.NewExerciseMediaRouter(handler),
+		FileRouter: router.NewMediaFileRouter(handler),
+	}
+}
+
+// NewExerciseMediaService wires the service from the media environment variables.
+// Exercise modules use it to clean up files when an exercise is deleted.
+func NewExerciseMediaService(db *sql.DB) *service.ExerciseMediaService {
+	store, err := storage.NewBlobStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure media storage: %v", err)
+	}
+	maxImageSize, maxVideoSize := storage.UploadLimitsFromEnv()
+	repo := repository.NewExerciseMediaRepository(db)
+	return service.NewExerciseMediaService(repo, store, storage.NewURLSignerFromEnv(), maxImageSize, maxVideoSize)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+	"github.com/lib/pq"
+)
+
+type ExerciseMediaRepository struct {
+	db *sql.DB
+}
+
+func NewExerciseMediaRepository(db *sql.DB) *ExerciseMediaRepository {
+	return &ExerciseMediaRepository{db: db}
+}
+
+// mediaTables returns the media table, its exercise column and the live-exercise query for the scope
+func mediaTables(gymID string) (table, exerciseColumn, existsQuery string) {
+	if gymID == "" {
+		return "public.exercise_media", "exercise_id",
+			`SELECT EXISTS (SELECT 1 FROM public.exercise WHERE id = $1 AND is_active = TRUE)`
+	}
+	schema := pq.QuoteIdentifier(gymID)
+	return schema + ".custom_exercise_media", "custom_exercise_id",
+		fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.custom_exercise WHERE id = $1 AND deleted_at IS NULL)`, schema)
+}
+
+func (r *ExerciseMediaRepository) ExerciseExists(gymID, exerciseID string) (bool, error) {
+	_, _, query := mediaTables(gymID)
+	var exists bool
+	err := r.db.QueryRow(query, exerciseID).Scan(&exists)
+	return exists, err
+}
+
+func (r *ExerciseMediaRepository) CreateAsset(gymID string, asset *dto.MediaAsset) (*string, error) {
+	table, column, _ := mediaTables(gymID)
+	query := fmt.Sprintf(`INSERT INTO %s (%s, kind, storage_key, thumbnail_key, content_type, size_bytes, original_filename) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`, table, column)
+	var id string
+	err := r.db.QueryRow(query, asset.ExerciseID, asset.Kind, asset.StorageKey, asset.ThumbnailKey, asset.ContentType, asset.SizeBytes, asset.OriginalFilename).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *ExerciseMediaRepository) FindByID(gymID, id string) (*dto.MediaAsset, error) {
+	table, column, _ := mediaTables(gymID)
+	query := fmt.Sprintf(`SELECT id, %s, kind, storage_key, thumbnail_key, content_type, size_bytes, original_filename, created_at FROM %s WHERE id = $1`, column, table)
+	var asset dto.MediaAsset
+	err := r.db.QueryRow(query, id).Scan(&asset.ID, &asset.ExerciseID, &asset.Kind, &asset.StorageKey, &asset.ThumbnailKey, &asset.ContentType, &asset.SizeBytes, &asset.OriginalFilename, &asset.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (r *ExerciseMediaRepository) FindByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error) {
+	table, column, _ := mediaTables(gymID)
+	query := fmt.Sprintf(`SELECT id, %s, kind, storage_key, thumbnail_key, content_type, size_bytes, original_filename, created_at FROM %s WHERE %s = $1 ORDER BY created_at`, column, table, column)
+	rows, err := r.db.Query(query, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var assets []*dto.MediaAsset
+	for rows.Next() {
+		asset := &dto.MediaAsset{}
+		err := rows.Scan(&asset.ID, &asset.ExerciseID, &asset.Kind, &asset.StorageKey, &asset.ThumbnailKey, &asset.ContentType, &asset.SizeBytes, &asset.OriginalFilename, &asset.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+func (r *ExerciseMediaRepository) DeleteAsset(gymID, id string) error {
+	table, _, _ := mediaTables(gymID)
+	result, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ExerciseMediaRepository) DeleteByExerciseID(gymID, exerciseID string) error {
+	table, column, _ := mediaTables(gymID)
+	_, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, table, column), exerciseID)
+	return err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseMediaRepository_ExerciseExists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseMediaRepository(db)
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM public.exercise WHERE id = \$1 AND is_active = TRUE\)`).
+		WithArgs("ex1").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM "gym1".custom_exercise WHERE id = \$1 AND deleted_at IS NULL\)`).
+		WithArgs("ex2").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	exists, err := repo.ExerciseExists("", "ex1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.ExerciseExists("gym1", "ex2")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseMediaRepository_CreateAsset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseMediaRepository(db)
+
+	thumbnail := "gym1/exercises/ex1/abc_thumb.jpg"
+	asset := &dto.MediaAsset{ExerciseID: "ex1", Kind: "image", ContentType: "image/png", SizeBytes: 42, StorageKey: "gym1/exercises/ex1/abc.png", ThumbnailKey: &thumbnail}
+	mock.ExpectQuery(`INSERT INTO "gym1".custom_exercise_media \(custom_exercise_id, kind, storage_key`).
+		WithArgs("ex1", "image", asset.StorageKey, &thumbnail, "image/png", int64(42), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("media-1"))
+
+	id, err := repo.CreateAsset("gym1", asset)
+	assert.NoError(t, err)
+	assert.Equal(t, "media-1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseMediaRepository_FindByExerciseID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseMediaRepository(db)
+
+	columns := []string{"id", "exercise_id", "kind", "storage_key", "thumbnail_key", "content_type", "size_bytes", "original_filename", "created_at"}
+	mock.ExpectQuery(`SELECT id, exercise_id, (.+) FROM public.exercise_media WHERE exercise_id = \$1`).
+		WithArgs("ex1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "ex1", "video", "public/exercises/ex1/a.mp4", nil, "video/mp4", 1024, "clip.mp4", time.Now()))
+
+	assets, err := repo.FindByExerciseID("", "ex1")
+	assert.NoError(t, err)
+	if assert.Len(t, assets, 1) {
+		assert.Equal(t, "video", assets[0].Kind)
+		assert.Nil(t, assets[0].ThumbnailKey)
+		assert.Equal(t, "clip.mp4", *assets[0].OriginalFilename)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseMediaRepository_DeleteAsset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewExerciseMediaRepository(db)
+
+	mock.ExpectExec(`DELETE FROM "gym1".custom_exercise_media WHERE id = \$1`).
+		WithArgs("missing").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteAsset("gym1", "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewExerciseMediaRouter(handler interfaces.ExerciseMediaHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/exercise/{exerciseID}", handler.UploadExerciseMedia)              // POST /media/exercise/{exerciseID}
+	r.Get("/exercise/{exerciseID}", handler.GetExerciseMedia)                  // GET /media/exercise/{exerciseID}
+	r.Delete("/exercise/asset/{id}", handler.DeleteExerciseMedia)              // DELETE /media/exercise/asset/{id}
+	r.Post("/custom-exercise/{exerciseID}", handler.UploadCustomExerciseMedia) // POST /media/custom-exercise/{exerciseID}
+	r.Get("/custom-exercise/{exerciseID}", handler.GetCustomExerciseMedia)     // GET /media/custom-exercise/{exerciseID}
+	r.Delete("/custom-exercise/asset/{id}", handler.DeleteCustomExerciseMedia) // DELETE /media/custom-exercise/asset/{id}
+
+	return r
+}
+
+// NewMediaFileRouter serves signed links and must be mounted without the auth middleware
+func NewMediaFileRouter(handler interfaces.ExerciseMediaHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/*", handler.ServeFile) // GET /media/file/{key}?expires=...&signature=...
+
+	return r
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/enum"
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/storage"
+)
+
+// publicScope is the key prefix of public exercise media, gym media is prefixed with the gym ID
+const publicScope = "public"
+
+type mediaType struct {
+	kind      enum.MediaKind
+	extension string
+}
+
+// allowedTypes maps sniffed content types to the kind and file extension they are stored with
+var allowedTypes = map[string]mediaType{
+	"image/jpeg": {enum.Image, ".jpg"},
+	"image/png":  {enum.Image, ".png"},
+	"image/gif":  {enum.Image, ".gif"},
+	"image/webp": {enum.Image, ".webp"},
+	"video/mp4":  {enum.Video, ".mp4"},
+	"video/webm": {enum.Video, ".webm"},
+}
+
+var errTooLarge = errors.New("upload exceeds the size limit")
+
+type ExerciseMediaService struct {
+	repository   interfaces.ExerciseMediaRepository
+	store        storage.BlobStore
+	signer       *storage.URLSigner
+	maxImageSize int64
+	maxVideoSize int64
+}
+
+func NewExerciseMediaService(repository interfaces.ExerciseMediaRepository, store storage.BlobStore, signer *storage.URLSigner, maxImageSize, maxVideoSize int64) *ExerciseMediaService {
+	return &ExerciseMediaService{
+		repository:   repository,
+		store:        store,
+		signer:       signer,
+		maxImageSize: maxImageSize,
+		maxVideoSize: maxVideoSize,
+	}
+}
+
+// UploadMedia validates the file by its content, stores it under the owning tenant and
+// records it against the exercise. Images also get a JPEG thumbnail.
+func (s *ExerciseMediaService) UploadMedia(gymID, exerciseID, filename string, body io.Reader) (*dto.MediaAsset, error) {
+	exists, err := s.repository.ExerciseExists(gymID, exerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check exercise", err)
+	}
+	if !exists {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise not found", nil)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Failed to read upload", err)
+	}
+	if n == 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "File is empty", nil)
+	}
+	contentType := http.DetectContentType(head[:n])
+	media, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Unsupported content type "+contentType+", must be a JPEG, PNG, GIF or WebP image or an MP4 or WebM video", nil)
+	}
+	limit := s.maxImageSize
+	if media.kind == enum.Video {
+		limit = s.maxVideoSize
+	}
+	content := &limitedReader{r: io.MultiReader(bytes.NewReader(head[:n]), body), remaining: limit}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to name upload", err)
+	}
+	prefix := exercisePrefix(gymID, exerciseID)
+	asset := &dto.MediaAsset{
+		ExerciseID:  exerciseID,
+		Kind:        string(media.kind),
+		ContentType: contentType,
+		StorageKey:  prefix + name + media.extension,
+	}
+	if base := path.Base(strings.ReplaceAll(filename, "\\", "/")); base != "" && base != "." && base != "/" {
+		asset.OriginalFilename = &base
+	}
+
+	if media.kind == enum.Image {
+		data, err := io.ReadAll(content)
+		if errors.Is(err, errTooLarge) {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Image exceeds the maximum upload size", err)
+		}
+		if err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Failed to read upload", err)
+		}
+		thumbnail, err := makeThumbnail(data)
+		if err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid image file", err)
+		}
+		if err := s.store.Put(asset.StorageKey, contentType, bytes.NewReader(data)); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to store media", err)
+		}
+		if thumbnail != nil {
+			thumbnailKey := prefix + name + "_thumb.jpg"
+			if err := s.store.Put(thumbnailKey, "image/jpeg", bytes.NewReader(thumbnail)); err != nil {
+				s.store.Delete(asset.StorageKey)
+				return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to store thumbnail", err)
+			}
+			asset.ThumbnailKey = &thumbnailKey
+		}
+		asset.SizeBytes = int64(len(data))
+	} else {
+		if err := s.store.Put(asset.StorageKey, contentType, content); err != nil {
+			s.store.Delete(asset.StorageKey)
+			if errors.Is(err, errTooLarge) {
+				return nil, apierror.New(errorcode_enum.CodeBadRequest, "Video exceeds the maximum upload size", err)
+			}
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to store media", err)
+		}
+		asset.SizeBytes = limit - content.remaining
+	}
+
+	id, err := s.repository.CreateAsset(gymID, asset)
+	if err != nil {
+		s.deleteFiles(asset)
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to save media", err)
+	}
+	asset.ID = *id
+	s.sign(asset)
+	return asset, nil
+}
+
+func (s *ExerciseMediaService) GetMediaByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error) {
+	assets, err := s.repository.FindByExerciseID(gymID, exerciseID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get exercise media", err)
+	}
+	for _, asset := range assets {
+		s.sign(asset)
+	}
+	return assets, nil
+}
+
+func (s *ExerciseMediaService) DeleteMedia(gymID, id string) error {
+	asset, err := s.repository.FindByID(gymID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Media not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to retrieve media", err)
+	}
+	if err := s.repository.DeleteAsset(gymID, id); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete media", err)
+	}
+	if err := s.deleteFiles(asset); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete media files", err)
+	}
+	return nil
+}
+
+// CleanupExerciseMedia removes all files and records of an exercise
+func (s *ExerciseMediaService) CleanupExerciseMedia(gymID, exerciseID string) error {
+	if err := s.repository.DeleteByExerciseID(gymID, exerciseID); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete exercise media", err)
+	}
+	if err := s.store.DeletePrefix(exercisePrefix(gymID, exerciseID)); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete exercise media files", err)
+	}
+	return nil
+}
+
+// OpenFile returns the stored file behind a signed URL
+func (s *ExerciseMediaService) OpenFile(key, expires, signature string) (io.ReadCloser, string, error) {
+	if !s.signer.Verify(key, expires, signature) {
+		return nil, "", apierror.New(errorcode_enum.CodeForbidden, "Media link is invalid or has expired", nil)
+	}
+	file, contentType, err := s.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", apierror.New(errorcode_enum.CodeNotFound, "Media not found", err)
+		}
+		return nil, "", apierror.New(errorcode_enum.CodeInternal, "Failed to read media", err)
+	}
+	return file, contentType, nil
+}
+
+func (s *ExerciseMediaService) sign(asset *dto.MediaAsset) {
+	url, expiresAt := s.signer.SignedURL(asset.StorageKey)
+	asset.URL = url
+	asset.URLExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	if asset.ThumbnailKey != nil {
+		thumbnailURL, _ := s.signer.SignedURL(*asset.ThumbnailKey)
+		asset.ThumbnailURL = &thumbnailURL
+	}
+}
+
+func (s *ExerciseMediaService) deleteFiles(asset *dto.MediaAsset) error {
+	if err := s.store.Delete(asset.StorageKey); err != nil {
+		return err
+	}
+	if asset.ThumbnailKey != nil {
+		return s.store.Delete(*asset.ThumbnailKey)
+	}
+	return nil
+}
+
+// exercisePrefix scopes the files of an exercise to its tenant: "{gym_id|public}/exercises/{exercise_id}/"
+func exercisePrefix(gymID, exerciseID string) string {
+	scope := gymID
+	if scope == "" {
+		scope = publicScope
+	}
+	return scope + "/exercises/" + exerciseID + "/"
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// limitedReader fails with errTooLarge instead of silently truncating like io.LimitReader
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_media/dto"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/storage"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	exists  bool
+	assets  map[string]*dto.MediaAsset
+	deleted []string
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{exists: true, assets: map[string]*dto.MediaAsset{}}
+}
+
+func (m *mockRepository) ExerciseExists(gymID, exerciseID string) (bool, error) {
+	return m.exists, nil
+}
+func (m *mockRepository) CreateAsset(gymID string, asset *dto.MediaAsset) (*string, error) {
+	id := "media-1"
+	copied := *asset
+	copied.ID = id
+	m.assets[id] = &copied
+	return &id, nil
+}
+func (m *mockRepository) FindByID(gymID, id string) (*dto.MediaAsset, error) {
+	asset, ok := m.assets[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return asset, nil
+}
+func (m *mockRepository) FindByExerciseID(gymID, exerciseID string) ([]*dto.MediaAsset, error) {
+	var assets []*dto.MediaAsset
+	for _, asset := range m.assets {
+		if asset.ExerciseID == exerciseID {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+func (m *mockRepository) DeleteAsset(gymID, id string) error {
+	delete(m.assets, id)
+	return nil
+}
+func (m *mockRepository) DeleteByExerciseID(gymID, exerciseID string) error {
+	m.deleted = append(m.deleted, exerciseID)
+	return nil
+}
+
+func newTestService(t *testing.T, repo *mockRepository) (*ExerciseMediaService, storage.BlobStore) {
+	store := storage.NewLocalStore(t.TempDir())
+	signer := storage.NewURLSigner("test-secret", time.Minute, "/api/v1/media/file")
+	return NewExerciseMediaService(repo, store, signer, 1<<20, 2<<20), store
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 40, B: 40, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// signedParts splits a signed URL into the storage key, expiry and signature the file route receives
+func signedParts(t *testing.T, link string) (string, string, string) {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	key := strings.TrimPrefix(parsed.Path, "/api/v1/media/file/")
+	return key, parsed.Query().Get("expires"), parsed.Query().Get("signature")
+}
+
+func TestExerciseMediaService_UploadImage(t *testing.T) {
+	repo := newMockRepository()
+	svc, store := newTestService(t, repo)
+
+	asset, err := svc.UploadMedia("gym1", "ex1", "C:\\photos\\squat.png", bytes.NewReader(pngImage(t, 640, 480)))
+	require.NoError(t, err)
+	assert.Equal(t, "image", asset.Kind)
+	assert.Equal(t, "image/png", asset.ContentType)
+	assert.Equal(t, "squat.png", *asset.OriginalFilename)
+	assert.True(t, strings.HasPrefix(asset.StorageKey, "gym1/exercises/ex1/"))
+	require.NotNil(t, asset.ThumbnailKey)
+	require.NotNil(t, asset.ThumbnailURL)
+	assert.NotEmpty(t, asset.URLExpiresAt)
+
+	file, contentType, err := svc.OpenFile(signedParts(t, *asset.ThumbnailURL))
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, "image/jpeg", contentType)
+	thumbnail, _, err := image.DecodeConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, 320, thumbnail.Width)
+	assert.Equal(t, 240, thumbnail.Height)
+
+	stored, _, err := store.Get(asset.StorageKey)
+	require.NoError(t, err)
+	stored.Close()
+}
+
+func TestExerciseMediaService_UploadRejections(t *testing.T) {
+	t.Run("unsupported content type", func(t *testing.T) {
+		svc, _ := newTestService(t, newMockRepository())
+		_, err := svc.UploadMedia("", "ex1", "notes.txt", strings.NewReader("just some text"))
+		testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	})
+
+	t.Run("image over the size limit", func(t *testing.T) {
+		svc, _ := newTestService(t, newMockRepository())
+		svc.maxImageSize = 64
+		_, err := svc.UploadMedia("", "ex1", "big.png", bytes.NewReader(pngImage(t, 64, 64)))
+		testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	})
+
+	t.Run("video over the size limit leaves no file behind", func(t *testing.T) {
+		repo := newMockRepository()
+		svc, store := newTestService(t, repo)
+		svc.maxVideoSize = 1024
+		video := append([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), make([]byte, 4096)...)
+		_, err := svc.UploadMedia("gym1", "ex1", "clip.mp4", bytes.NewReader(video))
+		testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+		assert.Empty(t, repo.assets)
+		assert.NoError(t, store.DeletePrefix("gym1/exercises/ex1/"))
+	})
+
+	t.Run("unknown exercise", func(t *testing.T) {
+		repo := newMockRepository()
+		repo.exists = false
+		svc, _ := newTestService(t, repo)
+		_, err := svc.UploadMedia("gym1", "missing", "a.png", bytes.NewReader(pngImage(t, 4, 4)))
+		testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	})
+}
+
+func TestExerciseMediaService_OpenFile(t *testing.T) {
+	svc, _ := newTestService(t, newMockRepository())
+	asset, err := svc.UploadMedia("gym1", "ex1", "a.png", bytes.NewReader(pngImage(t, 8, 8)))
+	require.NoError(t, err)
+	key, expires, signature := signedParts(t, asset.URL)
+
+	file, _, err := svc.OpenFile(key, expires, signature)
+	require.NoError(t, err)
+	file.Close()
+
+	_, _, err = svc.OpenFile("gym2/exercises/ex1/other.png", expires, signature)
+	testutil.AssertCode(t, err, errorcode_enum.CodeForbidden)
+
+	_, _, err = svc.OpenFile(key, "1", signature)
+	testutil.AssertCode(t, err, errorcode_enum.CodeForbidden)
+}
+
+func TestExerciseMediaService_DeleteAndCleanup(t *testing.T) {
+	repo := newMockRepository()
+	svc, store := newTestService(t, repo)
+	asset, err := svc.UploadMedia("gym1", "ex1", "a.png", bytes.NewReader(pngImage(t, 8, 8)))
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteMedia("gym1", asset.ID))
+	_, _, err = store.Get(asset.StorageKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	testutil.AssertCode(t, svc.DeleteMedia("gym1", asset.ID), errorcode_enum.CodeNotFound)
+
+	asset, err = svc.UploadMedia("gym1", "ex1", "b.png", bytes.NewReader(pngImage(t, 8, 8)))
+	require.NoError(t, err)
+	require.NoError(t, svc.CleanupExerciseMedia("gym1", "ex1"))
+	assert.Equal(t, []string{"ex1"}, repo.deleted)
+	_, _, err = store.Get(*asset.ThumbnailKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -1,7 +1,9 @@
 package dto
 
 type ExerciseMuscularGroup struct {
-	ID              string `json:"id"`
-	ExerciseID      string `json:"exercise_id"`
-	MuscularGroupID string `json:"muscular_group_id"`
+	ID               string  `json:"id"`
+	ExerciseID       string  `json:"exercise_id"`
+	MuscularGroupID  string  `json:"muscular_group_id"`
+	Role             string  `json:"role"`              // primary, secondary or stabilizer
+	ActivationWeight float64 `json:"activation_weight"` // 0.01 <= weight <= 1, share of a set credited to this muscle
 }
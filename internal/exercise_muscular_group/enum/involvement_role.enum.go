@@ -0,0 +1,53 @@
+package enum
+
+import "math"
+
+type InvolvementRole string
+
+const (
+	Primary    InvolvementRole = "primary"
+	Secondary  InvolvementRole = "secondary"
+	Stabilizer InvolvementRole = "stabilizer"
+)
+
+func (r InvolvementRole) IsValid() bool {
+	switch r {
+	case Primary, Secondary, Stabilizer:
+		return true
+	}
+	return false
+}
+
+// DefaultActivationWeight returns the weight used when a link is created without an explicit one
+func (r InvolvementRole) DefaultActivationWeight() float64 {
+	switch r {
+	case Secondary:
+		return 0.5
+	case Stabilizer:
+		return 0.25
+	}
+	return 1.0
+}
+
+// MinActivationWeight is the smallest weight activation_weight NUMERIC(3,2) keeps; anything lower rounds to 0.00
+const MinActivationWeight = 0.01
+
+// NormalizeInvolvement defaults an empty role to primary and a zero weight to the role default, rounds the weight
+// to the two decimals it is stored with, and returns why the link is invalid, or an empty string when it is valid.
+// Every path that writes muscle links uses it.
+func NormalizeInvolvement(role string, weight float64) (string, float64, string) {
+	if role == "" {
+		role = string(Primary)
+	}
+	r := InvolvementRole(role)
+	if !r.IsValid() {
+		return role, weight, "Invalid role, must be primary, secondary or stabilizer"
+	}
+	if weight == 0 {
+		weight = r.DefaultActivationWeight()
+	}
+	if weight < MinActivationWeight || weight > 1 {
+		return role, weight, "Activation weight must be between 0.01 and 1"
+	}
+	return role, math.Round(weight*100) / 100, ""
+}
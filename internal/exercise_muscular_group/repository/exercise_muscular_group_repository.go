@@ -15,9 +15,9 @@ func NewExerciseMuscularGroupRepository(db *sql.DB) *ExerciseMuscularGroupReposi
 }
 
 func (r *ExerciseMuscularGroupRepository) CreateLink(link *dto.ExerciseMuscularGroup) (*string, error) {
-	query := `INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id) VALUES ($1, $2) RETURNING exercise_id`
+	query := `INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id, role, activation_weight) VALUES ($1, $2, $3, $4) RETURNING exercise_id`
 	var id string
-	err := r.db.QueryRow(query, link.ExerciseID, link.MuscularGroupID).Scan(&id)
+	err := r.db.QueryRow(query, link.ExerciseID, link.MuscularGroupID, link.Role, link.ActivationWeight).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +37,9 @@ func (r *ExerciseMuscularGroupRepository) RemoveAllLinksForExercise(exerciseID s
 }
 
 func (r *ExerciseMuscularGroupRepository) FindByID(id string) (*dto.ExerciseMuscularGroup, error) {
-	query := `SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`
+	query := `SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`
 	var link dto.ExerciseMuscularGroup
-	err := r.db.QueryRow(query, id).Scan(&link.ExerciseID, &link.MuscularGroupID)
+	err := r.db.QueryRow(query, id).Scan(&link.ExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func (r *ExerciseMuscularGroupRepository) FindByID(id string) (*dto.ExerciseMusc
 }
 
 func (r *ExerciseMuscularGroupRepository) FindByExerciseID(exerciseID string) ([]*dto.ExerciseMuscularGroup, error) {
-	query := `SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`
+	query := `SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`
 	rows, err := r.db.Query(query, exerciseID)
 	if err != nil {
 		return nil, err
@@ -56,7 +56,7 @@ func (r *ExerciseMuscularGroupRepository) FindByExerciseID(exerciseID string) ([
 	var links []*dto.ExerciseMuscularGroup
 	for rows.Next() {
 		link := &dto.ExerciseMuscularGroup{}
-		err := rows.Scan(&link.ExerciseID, &link.MuscularGroupID)
+		err := rows.Scan(&link.ExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight)
 		if err != nil {
 			return nil, err
 		}
@@ -66,7 +66,7 @@ func (r *ExerciseMuscularGroupRepository) FindByExerciseID(exerciseID string) ([
 }
 
 func (r *ExerciseMuscularGroupRepository) FindByMuscularGroupID(muscularGroupID string) ([]*dto.ExerciseMuscularGroup, error) {
-	query := `SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE muscular_group_id = $1`
+	query := `SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE muscular_group_id = $1`
 	rows, err := r.db.Query(query, muscularGroupID)
 	if err != nil {
 		return nil, err
@@ -75,7 +75,7 @@ func (r *ExerciseMuscularGroupRepository) FindByMuscularGroupID(muscularGroupID
 	var links []*dto.ExerciseMuscularGroup
 	for rows.Next() {
 		link := &dto.ExerciseMuscularGroup{}
-		err := rows.Scan(&link.ExerciseID, &link.MuscularGroupID)
+		err := rows.Scan(&link.ExerciseID, &link.MuscularGroupID, &link.Role, &link.ActivationWeight)
 		if err != nil {
 			return nil, err
 		}
@@ -24,11 +24,11 @@ func TestExerciseMuscularGroupRepository_FindByExerciseID(t *testing.T) {
 	defer db.Close()
 
 	// Success case: multiple rows
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex1").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}).
-			AddRow("ex1", "mg1").
-			AddRow("ex1", "mg2"))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}).
+			AddRow("ex1", "mg1", "primary", 1.0).
+			AddRow("ex1", "mg2", "primary", 1.0))
 
 	links, err := repo.FindByExerciseID("ex1")
 	if err != nil {
@@ -39,9 +39,9 @@ func TestExerciseMuscularGroupRepository_FindByExerciseID(t *testing.T) {
 	}
 
 	// Not found case: no rows
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex2").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}))
 
 	links, err = repo.FindByExerciseID("ex2")
 	if err != nil {
@@ -52,7 +52,7 @@ func TestExerciseMuscularGroupRepository_FindByExerciseID(t *testing.T) {
 	}
 
 	// DB error case
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex3").
 		WillReturnError(errors.New("db error"))
 
@@ -70,11 +70,11 @@ func TestExerciseMuscularGroupRepository_FindByMuscularGroupID(t *testing.T) {
 	defer db.Close()
 
 	// Success case: multiple rows
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
 		WithArgs("mg1").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}).
-			AddRow("ex1", "mg1").
-			AddRow("ex2", "mg1"))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}).
+			AddRow("ex1", "mg1", "primary", 1.0).
+			AddRow("ex2", "mg1", "primary", 1.0))
 
 	links, err := repo.FindByMuscularGroupID("mg1")
 	if err != nil {
@@ -85,9 +85,9 @@ func TestExerciseMuscularGroupRepository_FindByMuscularGroupID(t *testing.T) {
 	}
 
 	// Not found case: no rows
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
 		WithArgs("mg2").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}))
 
 	links, err = repo.FindByMuscularGroupID("mg2")
 	if err != nil {
@@ -98,7 +98,7 @@ func TestExerciseMuscularGroupRepository_FindByMuscularGroupID(t *testing.T) {
 	}
 
 	// DB error case
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE muscular_group_id = $1`)).
 		WithArgs("mg3").
 		WillReturnError(errors.New("db error"))
 
@@ -115,11 +115,11 @@ func TestExerciseMuscularGroupRepository_CreateLink(t *testing.T) {
 	db, mock, repo := setupMockDB(t)
 	defer db.Close()
 
-	link := &dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg1"}
+	link := &dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg1", Role: "secondary", ActivationWeight: 0.5}
 
 	// Success case
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id) VALUES ($1, $2) RETURNING exercise_id`)).
-		WithArgs(link.ExerciseID, link.MuscularGroupID).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id, role, activation_weight) VALUES ($1, $2, $3, $4) RETURNING exercise_id`)).
+		WithArgs(link.ExerciseID, link.MuscularGroupID, link.Role, link.ActivationWeight).
 		WillReturnRows(sqlmock.NewRows([]string{"exercise_id"}).AddRow("ex1"))
 
 	res, err := repo.CreateLink(link)
@@ -131,8 +131,8 @@ func TestExerciseMuscularGroupRepository_CreateLink(t *testing.T) {
 	}
 
 	// Error case
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id) VALUES ($1, $2) RETURNING exercise_id`)).
-		WithArgs(link.ExerciseID, link.MuscularGroupID).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO exercise_muscular_group (exercise_id, muscular_group_id, role, activation_weight) VALUES ($1, $2, $3, $4) RETURNING exercise_id`)).
+		WithArgs(link.ExerciseID, link.MuscularGroupID, link.Role, link.ActivationWeight).
 		WillReturnError(errors.New("insert error"))
 
 	res, err = repo.CreateLink(link)
@@ -174,22 +174,22 @@ func TestExerciseMuscularGroupRepository_FindByID(t *testing.T) {
 	defer db.Close()
 
 	// Success case
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex1").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}).AddRow("ex1", "mg1"))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}).AddRow("ex1", "mg1", "primary", 1.0))
 
 	link, err := repo.FindByID("ex1")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
-	if link == nil || link.ExerciseID != "ex1" || link.MuscularGroupID != "mg1" {
+	if link == nil || link.ExerciseID != "ex1" || link.MuscularGroupID != "mg1" || link.Role != "primary" {
 		t.Errorf("unexpected link result: %+v", link)
 	}
 
 	// Not found case
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex2").
-		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id"}))
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "muscular_group_id", "role", "activation_weight"}))
 
 	link, err = repo.FindByID("ex2")
 	if err == nil {
@@ -200,7 +200,7 @@ func TestExerciseMuscularGroupRepository_FindByID(t *testing.T) {
 	}
 
 	// DB error case
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id FROM exercise_muscular_group WHERE exercise_id = $1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT exercise_id, muscular_group_id, role, activation_weight FROM exercise_muscular_group WHERE exercise_id = $1`)).
 		WithArgs("ex3").
 		WillReturnError(errors.New("db error"))
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/enum"
 	"github.com/alejandro-albiol/athenai/internal/exercise_muscular_group/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
@@ -19,6 +20,9 @@ func NewExerciseMuscularGroupService(repository interfaces.ExerciseMuscularGroup
 }
 
 func (s *ExerciseMuscularGroupService) CreateLink(link *dto.ExerciseMuscularGroup) (*string, error) {
+	if err := normalizeInvolvement(link); err != nil {
+		return nil, err
+	}
 	linkID, err := s.repository.CreateLink(link)
 	if err != nil {
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create exercise-muscular group link", err)
@@ -68,3 +72,13 @@ func (s *ExerciseMuscularGroupService) GetLinksByMuscularGroupID(muscularGroupID
 	}
 	return links, nil
 }
+
+// normalizeInvolvement defaults the role to primary and the weight to the role default, then validates both
+func normalizeInvolvement(link *dto.ExerciseMuscularGroup) error {
+	var msg string
+	link.Role, link.ActivationWeight, msg = enum.NormalizeInvolvement(link.Role, link.ActivationWeight)
+	if msg != "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, msg, nil)
+	}
+	return nil
+}
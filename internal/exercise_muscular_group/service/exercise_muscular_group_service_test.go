@@ -75,6 +75,62 @@ func TestExerciseMuscularGroupService_CreateLink(t *testing.T) {
 	}
 }
 
+func TestExerciseMuscularGroupService_CreateLinkInvolvement(t *testing.T) {
+	var stored *dto.ExerciseMuscularGroup
+	mockRepo := &mockRepository{
+		CreateLinkFunc: func(link *dto.ExerciseMuscularGroup) (*string, error) {
+			stored = link
+			id := link.ExerciseID
+			return &id, nil
+		},
+	}
+	service := NewExerciseMuscularGroupService(mockRepo)
+
+	// Defaults: missing role becomes primary with full weight
+	_, err := service.CreateLink(&dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg1"})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if stored.Role != "primary" || stored.ActivationWeight != 1.0 {
+		t.Errorf("expected primary/1.0 defaults, got %s/%v", stored.Role, stored.ActivationWeight)
+	}
+
+	// Role default weight
+	_, err = service.CreateLink(&dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg2", Role: "stabilizer"})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if stored.ActivationWeight != 0.25 {
+		t.Errorf("expected stabilizer default weight 0.25, got %v", stored.ActivationWeight)
+	}
+
+	// Invalid role
+	stored = nil
+	_, err = service.CreateLink(&dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg3", Role: "assistant"})
+	if err == nil {
+		t.Error("expected error for invalid role, got nil")
+	}
+	if stored != nil {
+		t.Error("expected repository not to be called for invalid role")
+	}
+
+	// Weight out of range
+	_, err = service.CreateLink(&dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg3", Role: "secondary", ActivationWeight: 1.5})
+	if err == nil {
+		t.Error("expected error for out of range weight, got nil")
+	}
+
+	// Weight that rounds to 0.00 in the NUMERIC(3,2) column
+	stored = nil
+	_, err = service.CreateLink(&dto.ExerciseMuscularGroup{ExerciseID: "ex1", MuscularGroupID: "mg3", Role: "secondary", ActivationWeight: 0.004})
+	if err == nil {
+		t.Error("expected error for weight below 0.01, got nil")
+	}
+	if stored != nil {
+		t.Error("expected repository not to be called for weight below 0.01")
+	}
+}
+
 func TestExerciseMuscularGroupService_DeleteLink(t *testing.T) {
 	mockRepo := &mockRepository{
 		DeleteLinkFunc: func(id string) error {
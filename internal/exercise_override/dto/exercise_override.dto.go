@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// ExerciseOverrideDTO replaces a gym's override of a public exercise. Nil fields follow upstream.
+type ExerciseOverrideDTO struct {
+	Instructions    *string `json:"instructions,omitempty"`
+	VideoURL        *string `json:"video_url,omitempty"`
+	ImageURL        *string `json:"image_url,omitempty"`
+	DifficultyLevel *string `json:"difficulty_level,omitempty"`
+	IsHidden        bool    `json:"is_hidden"`
+	CreatedBy       string  `json:"-"`
+}
+
+// ExerciseOverride is a gym's stored override of a public exercise
+type ExerciseOverride struct {
+	ID                string    `json:"id"`
+	PublicExerciseID  string    `json:"public_exercise_id"`
+	Instructions      *string   `json:"instructions,omitempty"`
+	VideoURL          *string   `json:"video_url,omitempty"`
+	ImageURL          *string   `json:"image_url,omitempty"`
+	DifficultyLevel   *string   `json:"difficulty_level,omitempty"`
+	IsHidden          bool      `json:"is_hidden"`
+	UpstreamUpdatedAt time.Time `json:"upstream_updated_at"` // public exercise's updated_at when the override was saved
+	CreatedBy         string    `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
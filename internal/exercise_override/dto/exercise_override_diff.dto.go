@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// ExerciseOverrideDiff compares a gym's override with the current public exercise
+type ExerciseOverrideDiff struct {
+	PublicExerciseID string      `json:"public_exercise_id"`
+	ExerciseName     string      `json:"exercise_name"`
+	IsHidden         bool        `json:"is_hidden"`
+	Fields           []FieldDiff `json:"fields"`
+	// UpstreamChanged is set when the public exercise was edited after the override was saved
+	UpstreamChanged   bool      `json:"upstream_changed"`
+	UpstreamUpdatedAt time.Time `json:"upstream_updated_at"`
+	OverrideUpdatedAt time.Time `json:"override_updated_at"`
+}
+
+// FieldDiff is one overridden field with the upstream and gym values
+type FieldDiff struct {
+	Field    string  `json:"field"`
+	Upstream *string `json:"upstream"`
+	Override *string `json:"override"`
+	Differs  bool    `json:"differs"`
+}
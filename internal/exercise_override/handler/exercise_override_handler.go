@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type ExerciseOverrideHandler struct {
+	service interfaces.ExerciseOverrideService
+}
+
+func NewExerciseOverrideHandler(service interfaces.ExerciseOverrideService) *ExerciseOverrideHandler {
+	return &ExerciseOverrideHandler{service: service}
+}
+
+func (h *ExerciseOverrideHandler) SaveOverride(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var override dto.ExerciseOverrideDTO
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	override.CreatedBy = middleware.GetUserID(r)
+
+	id, err := h.service.SaveOverride(middleware.GetGymID(r), chi.URLParam(r, "exerciseID"), &override)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to save exercise override")
+		return
+	}
+	response.WriteAPISuccess(w, "Exercise override saved successfully", id)
+}
+
+func (h *ExerciseOverrideHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.DeleteOverride(middleware.GetGymID(r), chi.URLParam(r, "exerciseID")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete exercise override")
+		return
+	}
+	response.WriteAPISuccess(w, "Exercise override deleted, the exercise follows upstream again", nil)
+}
+
+func (h *ExerciseOverrideHandler) GetDiff(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	diff, err := h.service.GetDiff(middleware.GetGymID(r), chi.URLParam(r, "exerciseID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get exercise override")
+		return
+	}
+	response.WriteAPISuccess(w, "Exercise override diff retrieved successfully", diff)
+}
+
+func (h *ExerciseOverrideHandler) ListDiffs(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	diffs, err := h.service.ListDiffs(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list exercise overrides")
+		return
+	}
+	response.WriteAPISuccess(w, "Exercise override diffs retrieved successfully", diffs)
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym the overrides belong to
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage exercise overrides", nil))
+	return false
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/router"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.ExerciseOverrideService
+	exerciseID string
+	saved      *dto.ExerciseOverrideDTO
+	err        error
+}
+
+func (m *mockService) SaveOverride(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error) {
+	m.exerciseID = publicExerciseID
+	m.saved = override
+	id := "override-1"
+	return &id, m.err
+}
+func (m *mockService) DeleteOverride(gymID, publicExerciseID string) error {
+	m.exerciseID = publicExerciseID
+	return m.err
+}
+
+func serve(svc *mockService, method, target, body, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewExerciseOverrideRouter(NewExerciseOverrideHandler(svc)),
+		testutil.Caller{Role: role, UserID: "user-1", GymID: "gym1"}, method, target, body)
+}
+
+func TestSaveOverrideRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+	body := `{"instructions":"Box squat to parallel","is_hidden":false}`
+
+	w := serve(svc, http.MethodPut, "/squat", body, "trainer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.saved)
+
+	w = serve(svc, http.MethodPut, "/squat", body, "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "squat", svc.exerciseID)
+	if assert.NotNil(t, svc.saved) {
+		assert.Equal(t, "user-1", svc.saved.CreatedBy)
+		assert.Equal(t, "Box squat to parallel", *svc.saved.Instructions)
+	}
+}
+
+func TestDeleteOverrideNotFound(t *testing.T) {
+	svc := &mockService{err: apierror.New(errorcode_enum.CodeNotFound, "Exercise override not found", nil)}
+	w := serve(svc, http.MethodDelete, "/squat", "", "admin")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "squat", svc.exerciseID)
+}
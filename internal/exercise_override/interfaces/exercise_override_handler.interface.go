@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type ExerciseOverrideHandler interface {
+	SaveOverride(w http.ResponseWriter, r *http.Request)
+	DeleteOverride(w http.ResponseWriter, r *http.Request)
+	GetDiff(w http.ResponseWriter, r *http.Request)
+	ListDiffs(w http.ResponseWriter, r *http.Request)
+}
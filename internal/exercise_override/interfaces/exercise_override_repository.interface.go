@@ -0,0 +1,11 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+
+type ExerciseOverrideRepository interface {
+	Upsert(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error)
+	FindByExerciseID(gymID, publicExerciseID string) (*dto.ExerciseOverride, error)
+	FindByExerciseIDs(gymID string, publicExerciseIDs []string) ([]*dto.ExerciseOverride, error)
+	FindAll(gymID string) ([]*dto.ExerciseOverride, error)
+	Delete(gymID, publicExerciseID string) error
+}
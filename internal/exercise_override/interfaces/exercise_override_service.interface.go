@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	exercisedto "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+)
+
+type ExerciseOverrideService interface {
+	ExerciseOverlay
+
+	SaveOverride(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error)
+	DeleteOverride(gymID, publicExerciseID string) error
+	GetDiff(gymID, publicExerciseID string) (*dto.ExerciseOverrideDiff, error)
+	ListDiffs(gymID string) ([]*dto.ExerciseOverrideDiff, error)
+}
+
+// ExerciseOverlay merges a gym's overrides into public exercises.
+// Hidden exercises are dropped, so callers get exactly what the gym should see.
+type ExerciseOverlay interface {
+	ApplyOverrides(gymID string, exercises []*exercisedto.ExerciseResponseDTO) ([]*exercisedto.ExerciseResponseDTO, error)
+}
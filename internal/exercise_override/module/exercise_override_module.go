@@ -0,0 +1,25 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	exerciseRepository "github.com/alejandro-albiol/athenai/internal/exercise/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/handler"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/repository"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/router"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/service"
+)
+
+func NewExerciseOverrideModule(db *sql.DB) http.Handler {
+	handler := handler.NewExerciseOverrideHandler(NewExerciseOverrideService(db))
+	return router.NewExerciseOverrideRouter(handler)
+}
+
+// NewExerciseOverrideService is also the overlay the exercise module merges into gym reads
+func NewExerciseOverrideService(db *sql.DB) *service.ExerciseOverrideService {
+	return service.NewExerciseOverrideService(
+		repository.NewExerciseOverrideRepository(db),
+		exerciseRepository.NewExerciseRepository(db),
+	)
+}
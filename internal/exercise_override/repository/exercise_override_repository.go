@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/lib/pq"
+)
+
+type ExerciseOverrideRepository struct {
+	db *sql.DB
+}
+
+func NewExerciseOverrideRepository(db *sql.DB) *ExerciseOverrideRepository {
+	return &ExerciseOverrideRepository{db: db}
+}
+
+const selectOverrides = `SELECT id, public_exercise_id, instructions, video_url, image_url, difficulty_level, is_hidden,
+		upstream_updated_at, created_by, created_at, updated_at
+		FROM %s.exercise_override`
+
+// Upsert replaces the whole override and snapshots the public exercise's updated_at,
+// which the diff view uses to tell when upstream moved on
+func (r *ExerciseOverrideRepository) Upsert(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error) {
+	query := fmt.Sprintf(`INSERT INTO %s.exercise_override
+		(public_exercise_id, instructions, video_url, image_url, difficulty_level, is_hidden, upstream_updated_at, created_by)
+		SELECT e.id, $2, $3, $4, $5, $6, e.updated_at, $7 FROM public.exercise e WHERE e.id = $1
+		ON CONFLICT (public_exercise_id) DO UPDATE SET
+			instructions = EXCLUDED.instructions,
+			video_url = EXCLUDED.video_url,
+			image_url = EXCLUDED.image_url,
+			difficulty_level = EXCLUDED.difficulty_level,
+			is_hidden = EXCLUDED.is_hidden,
+			upstream_updated_at = EXCLUDED.upstream_updated_at,
+			updated_at = NOW()
+		RETURNING id`, pq.QuoteIdentifier(gymID))
+	var id string
+	err := r.db.QueryRow(query, publicExerciseID, override.Instructions, override.VideoURL, override.ImageURL,
+		override.DifficultyLevel, override.IsHidden, override.CreatedBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *ExerciseOverrideRepository) FindByExerciseID(gymID, publicExerciseID string) (*dto.ExerciseOverride, error) {
+	query := fmt.Sprintf(selectOverrides+" WHERE public_exercise_id = $1", pq.QuoteIdentifier(gymID))
+	return scanOverride(r.db.QueryRow(query, publicExerciseID))
+}
+
+func (r *ExerciseOverrideRepository) FindByExerciseIDs(gymID string, publicExerciseIDs []string) ([]*dto.ExerciseOverride, error) {
+	query := fmt.Sprintf(selectOverrides+" WHERE public_exercise_id::text = ANY($1)", pq.QuoteIdentifier(gymID))
+	return r.queryOverrides(query, pq.Array(publicExerciseIDs))
+}
+
+func (r *ExerciseOverrideRepository) FindAll(gymID string) ([]*dto.ExerciseOverride, error) {
+	query := fmt.Sprintf(selectOverrides+" ORDER BY updated_at DESC", pq.QuoteIdentifier(gymID))
+	return r.queryOverrides(query)
+}
+
+func (r *ExerciseOverrideRepository) Delete(gymID, publicExerciseID string) error {
+	query := fmt.Sprintf("DELETE FROM %s.exercise_override WHERE public_exercise_id = $1", pq.QuoteIdentifier(gymID))
+	result, err := r.db.Exec(query, publicExerciseID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ExerciseOverrideRepository) queryOverrides(query string, args ...any) ([]*dto.ExerciseOverride, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overrides []*dto.ExerciseOverride
+	for rows.Next() {
+		override, err := scanOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOverride(row rowScanner) (*dto.ExerciseOverride, error) {
+	var o dto.ExerciseOverride
+	err := row.Scan(&o.ID, &o.PublicExerciseID, &o.Instructions, &o.VideoURL, &o.ImageURL, &o.DifficultyLevel,
+		&o.IsHidden, &o.UpstreamUpdatedAt, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var overrideColumns = []string{"id", "public_exercise_id", "instructions", "video_url", "image_url", "difficulty_level", "is_hidden",
+	"upstream_updated_at", "created_by", "created_at", "updated_at"}
+
+func TestUpsertSnapshotsUpstream(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewExerciseOverrideRepository(db)
+	instructions := "Box squat to parallel"
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tenant1".exercise_override`)+`(.+)e.updated_at, \$7 FROM public.exercise e WHERE e.id = \$1(.+)ON CONFLICT \(public_exercise_id\) DO UPDATE`).
+		WithArgs("squat", &instructions, nil, nil, nil, false, "admin-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("override-1"))
+
+	id, err := repo.Upsert("tenant1", "squat", &dto.ExerciseOverrideDTO{Instructions: &instructions, CreatedBy: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "override-1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindByExerciseIDs(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewExerciseOverrideRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM "tenant1".exercise_override WHERE public_exercise_id::text = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows(overrideColumns).
+			AddRow("override-1", "squat", nil, nil, nil, "beginner", false, now, "admin-1", now, now).
+			AddRow("override-2", "burpee", nil, nil, nil, nil, true, now, "admin-1", now, now))
+
+	overrides, err := repo.FindByExerciseIDs("tenant1", []string{"squat", "burpee", "lunge"})
+	require.NoError(t, err)
+	if assert.Len(t, overrides, 2) {
+		assert.Equal(t, "beginner", *overrides[0].DifficultyLevel)
+		assert.Nil(t, overrides[0].Instructions)
+		assert.True(t, overrides[1].IsHidden)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMissingOverride(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewExerciseOverrideRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tenant1".exercise_override WHERE public_exercise_id = $1`)).
+		WithArgs("squat").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.Error(t, repo.Delete("tenant1", "squat"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewExerciseOverrideRouter(handler interfaces.ExerciseOverrideHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/", handler.ListDiffs)                     // GET /exercise-override
+	r.Get("/{exerciseID}", handler.GetDiff)           // GET /exercise-override/{exerciseID}
+	r.Put("/{exerciseID}", handler.SaveOverride)      // PUT /exercise-override/{exerciseID}
+	r.Delete("/{exerciseID}", handler.DeleteOverride) // DELETE /exercise-override/{exerciseID}
+
+	return r
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	exercisedto "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	exercise_enum "github.com/alejandro-albiol/athenai/internal/exercise/enum"
+	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type ExerciseOverrideService struct {
+	repo      interfaces.ExerciseOverrideRepository
+	exercises exerciseIF.ExerciseRepository
+}
+
+func NewExerciseOverrideService(repo interfaces.ExerciseOverrideRepository, exercises exerciseIF.ExerciseRepository) *ExerciseOverrideService {
+	return &ExerciseOverrideService{repo: repo, exercises: exercises}
+}
+
+func (s *ExerciseOverrideService) SaveOverride(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error) {
+	if override.CreatedBy == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "CreatedBy is required", nil)
+	}
+	if override.Instructions != nil && strings.TrimSpace(*override.Instructions) == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Instructions cannot be empty, leave them out to follow upstream", nil)
+	}
+	if override.DifficultyLevel != nil && !exercise_enum.DifficultyLevel(*override.DifficultyLevel).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid difficulty level", nil)
+	}
+	if override.Instructions == nil && override.VideoURL == nil && override.ImageURL == nil && override.DifficultyLevel == nil && !override.IsHidden {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Override changes nothing, delete it to follow upstream", nil)
+	}
+	if _, err := s.upstream(publicExerciseID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Upsert(gymID, publicExerciseID, override)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to save exercise override", err)
+	}
+	return id, nil
+}
+
+func (s *ExerciseOverrideService) DeleteOverride(gymID, publicExerciseID string) error {
+	if err := s.repo.Delete(gymID, publicExerciseID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Exercise override not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete exercise override", err)
+	}
+	return nil
+}
+
+func (s *ExerciseOverrideService) GetDiff(gymID, publicExerciseID string) (*dto.ExerciseOverrideDiff, error) {
+	override, err := s.repo.FindByExerciseID(gymID, publicExerciseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise override not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get exercise override", err)
+	}
+	exercise, err := s.upstream(publicExerciseID)
+	if err != nil {
+		return nil, err
+	}
+	return diff(exercise, override), nil
+}
+
+// ListDiffs skips overrides whose public exercise was retired, they no longer apply to anything
+func (s *ExerciseOverrideService) ListDiffs(gymID string) ([]*dto.ExerciseOverrideDiff, error) {
+	overrides, err := s.repo.FindAll(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list exercise overrides", err)
+	}
+	diffs := []*dto.ExerciseOverrideDiff{}
+	for _, override := range overrides {
+		exercise, err := s.exercises.GetExerciseByID(override.PublicExerciseID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get exercise", err)
+		}
+		diffs = append(diffs, diff(exercise, override))
+	}
+	return diffs, nil
+}
+
+func (s *ExerciseOverrideService) ApplyOverrides(gymID string, exercises []*exercisedto.ExerciseResponseDTO) ([]*exercisedto.ExerciseResponseDTO, error) {
+	if gymID == "" || len(exercises) == 0 {
+		return exercises, nil
+	}
+	ids := make([]string, 0, len(exercises))
+	for _, exercise := range exercises {
+		ids = append(ids, exercise.ID)
+	}
+	overrides, err := s.repo.FindByExerciseIDs(gymID, ids)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to apply exercise overrides", err)
+	}
+	if len(overrides) == 0 {
+		return exercises, nil
+	}
+	byExercise := make(map[string]*dto.ExerciseOverride, len(overrides))
+	for _, override := range overrides {
+		byExercise[override.PublicExerciseID] = override
+	}
+
+	merged := make([]*exercisedto.ExerciseResponseDTO, 0, len(exercises))
+	for _, exercise := range exercises {
+		override, ok := byExercise[exercise.ID]
+		if !ok {
+			merged = append(merged, exercise)
+			continue
+		}
+		if override.IsHidden {
+			continue
+		}
+		copied := *exercise
+		if override.Instructions != nil {
+			copied.Instructions = *override.Instructions
+		}
+		if override.VideoURL != nil {
+			copied.VideoURL = override.VideoURL
+		}
+		if override.ImageURL != nil {
+			copied.ImageURL = override.ImageURL
+		}
+		if override.DifficultyLevel != nil {
+			copied.DifficultyLevel = *override.DifficultyLevel
+		}
+		copied.GymOverride = true
+		merged = append(merged, &copied)
+	}
+	return merged, nil
+}
+
+func (s *ExerciseOverrideService) upstream(publicExerciseID string) (*exercisedto.ExerciseResponseDTO, error) {
+	exercise, err := s.exercises.GetExerciseByID(publicExerciseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Exercise not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get exercise", err)
+	}
+	return exercise, nil
+}
+
+// diff lists the fields the override sets, next to what upstream has now
+func diff(exercise *exercisedto.ExerciseResponseDTO, override *dto.ExerciseOverride) *dto.ExerciseOverrideDiff {
+	result := &dto.ExerciseOverrideDiff{
+		PublicExerciseID:  exercise.ID,
+		ExerciseName:      exercise.Name,
+		IsHidden:          override.IsHidden,
+		Fields:            []dto.FieldDiff{},
+		UpstreamChanged:   exercise.UpdatedAt.After(override.UpstreamUpdatedAt),
+		UpstreamUpdatedAt: exercise.UpdatedAt,
+		OverrideUpdatedAt: override.UpdatedAt,
+	}
+	instructions, difficulty := exercise.Instructions, exercise.DifficultyLevel
+	add := func(field string, upstream, value *string) {
+		if value == nil {
+			return
+		}
+		differs := upstream == nil || *upstream != *value
+		result.Fields = append(result.Fields, dto.FieldDiff{Field: field, Upstream: upstream, Override: value, Differs: differs})
+	}
+	add("instructions", &instructions, override.Instructions)
+	add("video_url", exercise.VideoURL, override.VideoURL)
+	add("image_url", exercise.ImageURL, override.ImageURL)
+	add("difficulty_level", &difficulty, override.DifficultyLevel)
+	return result
+}
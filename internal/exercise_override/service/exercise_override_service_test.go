@@ -0,0 +1,163 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	exercisedto "github.com/alejandro-albiol/athenai/internal/exercise/dto"
+	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/dto"
+	"github.com/alejandro-albiol/athenai/internal/exercise_override/interfaces"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.ExerciseOverrideRepository
+	overrides map[string]*dto.ExerciseOverride
+	saved     *dto.ExerciseOverrideDTO
+}
+
+func (m *mockRepository) Upsert(gymID, publicExerciseID string, override *dto.ExerciseOverrideDTO) (*string, error) {
+	m.saved = override
+	id := "override-1"
+	return &id, nil
+}
+func (m *mockRepository) FindByExerciseID(gymID, publicExerciseID string) (*dto.ExerciseOverride, error) {
+	override, ok := m.overrides[publicExerciseID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return override, nil
+}
+func (m *mockRepository) FindByExerciseIDs(gymID string, publicExerciseIDs []string) ([]*dto.ExerciseOverride, error) {
+	var found []*dto.ExerciseOverride
+	for _, id := range publicExerciseIDs {
+		if override, ok := m.overrides[id]; ok {
+			found = append(found, override)
+		}
+	}
+	return found, nil
+}
+func (m *mockRepository) FindAll(gymID string) ([]*dto.ExerciseOverride, error) {
+	var all []*dto.ExerciseOverride
+	for _, override := range m.overrides {
+		all = append(all, override)
+	}
+	return all, nil
+}
+
+type mockExerciseRepository struct {
+	exerciseIF.ExerciseRepository
+	exercises map[string]*exercisedto.ExerciseResponseDTO
+}
+
+func (m *mockExerciseRepository) GetExerciseByID(id string) (*exercisedto.ExerciseResponseDTO, error) {
+	exercise, ok := m.exercises[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return exercise, nil
+}
+
+var upstreamEdit = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func newFixture() (*mockRepository, *mockExerciseRepository) {
+	video := "https://cdn.example.com/squat.mp4"
+	return &mockRepository{overrides: map[string]*dto.ExerciseOverride{}},
+		&mockExerciseRepository{exercises: map[string]*exercisedto.ExerciseResponseDTO{
+			"squat":  {ID: "squat", Name: "Back Squat", Instructions: "Squat down", DifficultyLevel: "intermediate", VideoURL: &video, UpdatedAt: upstreamEdit},
+			"lunge":  {ID: "lunge", Name: "Lunge", Instructions: "Step forward", DifficultyLevel: "beginner", UpdatedAt: upstreamEdit},
+			"burpee": {ID: "burpee", Name: "Burpee", Instructions: "Jump", DifficultyLevel: "beginner", UpdatedAt: upstreamEdit},
+		}}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestSaveOverrideValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		exercise string
+		override *dto.ExerciseOverrideDTO
+		code     string
+	}{
+		{"missing creator", "squat", &dto.ExerciseOverrideDTO{IsHidden: true}, errorcode_enum.CodeBadRequest},
+		{"blank instructions", "squat", &dto.ExerciseOverrideDTO{Instructions: strPtr(" "), CreatedBy: "admin-1"}, errorcode_enum.CodeBadRequest},
+		{"unknown difficulty", "squat", &dto.ExerciseOverrideDTO{DifficultyLevel: strPtr("elite"), CreatedBy: "admin-1"}, errorcode_enum.CodeBadRequest},
+		{"changes nothing", "squat", &dto.ExerciseOverrideDTO{CreatedBy: "admin-1"}, errorcode_enum.CodeBadRequest},
+		{"unknown exercise", "deadlift", &dto.ExerciseOverrideDTO{IsHidden: true, CreatedBy: "admin-1"}, errorcode_enum.CodeNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, exercises := newFixture()
+			_, err := NewExerciseOverrideService(repo, exercises).SaveOverride("gym1", tc.exercise, tc.override)
+			testutil.AssertCode(t, err, tc.code)
+			assert.Nil(t, repo.saved)
+		})
+	}
+
+	repo, exercises := newFixture()
+	id, err := NewExerciseOverrideService(repo, exercises).SaveOverride("gym1", "squat", &dto.ExerciseOverrideDTO{DifficultyLevel: strPtr("advanced"), CreatedBy: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "override-1", *id)
+	assert.Equal(t, "advanced", *repo.saved.DifficultyLevel)
+}
+
+func TestApplyOverrides(t *testing.T) {
+	repo, exercises := newFixture()
+	repo.overrides["squat"] = &dto.ExerciseOverride{PublicExerciseID: "squat", Instructions: strPtr("Box squat to parallel"), DifficultyLevel: strPtr("beginner")}
+	repo.overrides["burpee"] = &dto.ExerciseOverride{PublicExerciseID: "burpee", IsHidden: true}
+	svc := NewExerciseOverrideService(repo, exercises)
+	upstream := []*exercisedto.ExerciseResponseDTO{exercises.exercises["squat"], exercises.exercises["lunge"], exercises.exercises["burpee"]}
+
+	merged, err := svc.ApplyOverrides("gym1", upstream)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "Box squat to parallel", merged[0].Instructions)
+	assert.Equal(t, "beginner", merged[0].DifficultyLevel)
+	assert.Equal(t, "https://cdn.example.com/squat.mp4", *merged[0].VideoURL)
+	assert.True(t, merged[0].GymOverride)
+	assert.Same(t, exercises.exercises["lunge"], merged[1])
+
+	// The shared upstream rows are never mutated
+	assert.Equal(t, "Squat down", exercises.exercises["squat"].Instructions)
+
+	unchanged, err := svc.ApplyOverrides("", upstream)
+	require.NoError(t, err)
+	assert.Len(t, unchanged, 3)
+}
+
+func TestDiffAgainstUpstream(t *testing.T) {
+	repo, exercises := newFixture()
+	repo.overrides["squat"] = &dto.ExerciseOverride{
+		PublicExerciseID:  "squat",
+		Instructions:      strPtr("Box squat to parallel"),
+		DifficultyLevel:   strPtr("intermediate"),
+		UpstreamUpdatedAt: upstreamEdit.Add(-time.Hour),
+	}
+	svc := NewExerciseOverrideService(repo, exercises)
+
+	diff, err := svc.GetDiff("gym1", "squat")
+	require.NoError(t, err)
+	assert.True(t, diff.UpstreamChanged)
+	require.Len(t, diff.Fields, 2)
+	assert.Equal(t, "instructions", diff.Fields[0].Field)
+	assert.Equal(t, "Squat down", *diff.Fields[0].Upstream)
+	assert.True(t, diff.Fields[0].Differs)
+	assert.Equal(t, "difficulty_level", diff.Fields[1].Field)
+	assert.False(t, diff.Fields[1].Differs)
+
+	_, err = svc.GetDiff("gym1", "lunge")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+
+	// Overrides of retired exercises are left out of the listing
+	repo.overrides["retired"] = &dto.ExerciseOverride{PublicExerciseID: "retired", IsHidden: true}
+	diffs, err := svc.ListDiffs("gym1")
+	require.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, "Back Squat", diffs[0].ExerciseName)
+	}
+}
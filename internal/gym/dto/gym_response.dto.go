@@ -5,13 +5,16 @@ import (
 )
 
 type GymResponseDTO struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	Address   string     `json:"address"`
-	Phone     string     `json:"phone"`
-	IsActive  bool       `json:"is_active"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Address  string `json:"address"`
+	Phone    string `json:"phone"`
+	IsActive bool   `json:"is_active"`
+	// ContraindicationPolicy is either "warn" or "block"
+	ContraindicationPolicy string     `json:"contraindication_policy"`
+	Timezone               string     `json:"timezone"` // IANA name, such as Europe/Madrid, workout schedules are expressed in
+	DeletedAt              *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
@@ -1,8 +1,10 @@
 package dto
 
 type GymUpdateDTO struct {
-	Name    *string `json:"name,omitempty" validate:"omitempty"`
-	Email   *string `json:"email,omitempty" validate:"omitempty,email"`
-	Address *string `json:"address,omitempty" validate:"omitempty"`
-	Phone   *string `json:"phone,omitempty" validate:"omitempty"`
+	Name                   *string `json:"name,omitempty" validate:"omitempty"`
+	Email                  *string `json:"email,omitempty" validate:"omitempty,email"`
+	Address                *string `json:"address,omitempty" validate:"omitempty"`
+	Phone                  *string `json:"phone,omitempty" validate:"omitempty"`
+	ContraindicationPolicy *string `json:"contraindication_policy,omitempty" validate:"omitempty,oneof=warn block"`
+	Timezone               *string `json:"timezone,omitempty" validate:"omitempty"`
 }
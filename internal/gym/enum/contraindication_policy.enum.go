@@ -0,0 +1,18 @@
+package enum
+
+// ContraindicationPolicy decides what happens when a trainer assigns an exercise
+// tagged as contraindicated for a member's special situation
+type ContraindicationPolicy string
+
+const (
+	ContraindicationWarn  ContraindicationPolicy = "warn"
+	ContraindicationBlock ContraindicationPolicy = "block"
+)
+
+func (p ContraindicationPolicy) IsValid() bool {
+	switch p {
+	case ContraindicationWarn, ContraindicationBlock:
+		return true
+	}
+	return false
+}
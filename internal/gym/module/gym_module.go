@@ -8,11 +8,12 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/gym/repository"
 	"github.com/alejandro-albiol/athenai/internal/gym/router"
 	"github.com/alejandro-albiol/athenai/internal/gym/service"
+	dashboard_module "github.com/alejandro-albiol/athenai/internal/gym_dashboard/module"
 )
 
 func NewGymModule(db *sql.DB) http.Handler {
 	repo := repository.NewGymRepository(db)
 	service := service.NewGymService(repo)
 	handler := handler.NewGymHandler(service)
-	return router.NewGymRouter(handler)
+	return router.NewGymRouter(handler, dashboard_module.NewGymDashboardModule(db))
 }
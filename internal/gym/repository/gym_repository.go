@@ -40,7 +40,7 @@ func (r *GymRepository) CreateGym(gym *dto.GymCreationDTO) (*string, error) {
 
 func (r *GymRepository) GetGymByID(id string) (*dto.GymResponseDTO, error) {
 	query := `
-		SELECT id, name, email, address, phone, is_active, created_at, updated_at
+		SELECT id, name, email, address, phone, is_active, contraindication_policy, timezone, created_at, updated_at
 		FROM gym 
 		WHERE id = $1 AND deleted_at IS NULL`
 
@@ -53,6 +53,8 @@ func (r *GymRepository) GetGymByID(id string) (*dto.GymResponseDTO, error) {
 		&gym.Address,
 		&gym.Phone,
 		&gym.IsActive,
+		&gym.ContraindicationPolicy,
+		&gym.Timezone,
 		&gym.CreatedAt,
 		&gym.UpdatedAt,
 	)
@@ -68,7 +70,7 @@ func (r *GymRepository) GetGymByID(id string) (*dto.GymResponseDTO, error) {
 
 func (r *GymRepository) GetGymByName(name string) (*dto.GymResponseDTO, error) {
 	query := `
-		SELECT id, name, email, address, phone, is_active, created_at, updated_at
+		SELECT id, name, email, address, phone, is_active, contraindication_policy, timezone, created_at, updated_at
 		FROM gym 
 		WHERE name = $1 AND deleted_at IS NULL`
 
@@ -80,6 +82,8 @@ func (r *GymRepository) GetGymByName(name string) (*dto.GymResponseDTO, error) {
 		&gym.Address,
 		&gym.Phone,
 		&gym.IsActive,
+		&gym.ContraindicationPolicy,
+		&gym.Timezone,
 		&gym.CreatedAt,
 		&gym.UpdatedAt,
 	)
@@ -93,7 +97,7 @@ func (r *GymRepository) GetGymByName(name string) (*dto.GymResponseDTO, error) {
 
 func (r *GymRepository) GetAllGyms() ([]*dto.GymResponseDTO, error) {
 	query := `
-		SELECT id, name, email, address, phone, is_active, created_at, updated_at, deleted_at
+		SELECT id, name, email, address, phone, is_active, contraindication_policy, timezone, created_at, updated_at, deleted_at
 		FROM gym 
 		ORDER BY 
 			CASE WHEN deleted_at IS NULL THEN 0 ELSE 1 END,
@@ -116,6 +120,8 @@ func (r *GymRepository) GetAllGyms() ([]*dto.GymResponseDTO, error) {
 			&gym.Address,
 			&gym.Phone,
 			&gym.IsActive,
+			&gym.ContraindicationPolicy,
+			&gym.Timezone,
 			&gym.CreatedAt,
 			&gym.UpdatedAt,
 			&gym.DeletedAt,
@@ -137,9 +143,10 @@ func (r *GymRepository) GetAllGyms() ([]*dto.GymResponseDTO, error) {
 func (r *GymRepository) UpdateGym(id string, gym *dto.GymUpdateDTO) (*dto.GymResponseDTO, error) {
 	query := `
 		UPDATE gym 
-		SET name = $1, email = $2, address = $3, phone = $4, updated_at = $5
-		WHERE id = $6 AND deleted_at IS NULL
-		RETURNING id, name, email, address, phone, is_active, created_at, updated_at`
+		SET name = $1, email = $2, address = $3, phone = $4,
+			contraindication_policy = COALESCE($5, contraindication_policy), timezone = COALESCE($6, timezone), updated_at = $7
+		WHERE id = $8 AND deleted_at IS NULL
+		RETURNING id, name, email, address, phone, is_active, contraindication_policy, timezone, created_at, updated_at`
 
 	var updatedGym dto.GymResponseDTO
 	err := r.db.QueryRow(query,
@@ -147,6 +154,8 @@ func (r *GymRepository) UpdateGym(id string, gym *dto.GymUpdateDTO) (*dto.GymRes
 		gym.Email,
 		gym.Address,
 		gym.Phone,
+		gym.ContraindicationPolicy,
+		gym.Timezone,
 		time.Now(),
 		id,
 	).Scan(
@@ -156,6 +165,8 @@ func (r *GymRepository) UpdateGym(id string, gym *dto.GymUpdateDTO) (*dto.GymRes
 		&updatedGym.Address,
 		&updatedGym.Phone,
 		&updatedGym.IsActive,
+		&updatedGym.ContraindicationPolicy,
+		&updatedGym.Timezone,
 		&updatedGym.CreatedAt,
 		&updatedGym.UpdatedAt,
 	)
@@ -58,10 +58,10 @@ func TestGetGymByID(t *testing.T) {
 
 	// Test successful retrieval
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "address", "phone", "is_active", "created_at", "updated_at",
+		"id", "name", "email", "address", "phone", "is_active", "contraindication_policy", "timezone", "created_at", "updated_at",
 	}).AddRow(
 		"gym123", "Test Gym", "test@gym.com", "123 Test St",
-		"+1234567890", true, now, now)
+		"+1234567890", true, "warn", "UTC", now, now)
 
 	mock.ExpectQuery("SELECT (.+) FROM gym WHERE id").
 		WithArgs("gym123").
@@ -82,13 +82,13 @@ func TestGetAllGyms(t *testing.T) {
 
 	// Test successful retrieval
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "address", "phone", "is_active", "created_at", "updated_at",
+		"id", "name", "email", "address", "phone", "is_active", "contraindication_policy", "timezone", "created_at", "updated_at", "deleted_at",
 	}).AddRow(
 		"gym123", "Test Gym 1", "test1@gym.com", "123 Test St",
-		"+1234567890", true, now, now,
+		"+1234567890", true, "warn", "UTC", now, now, nil,
 	).AddRow(
 		"gym456", "Test Gym 2", "test2@gym.com", "456 Test St",
-		"+0987654321", true, now, now,
+		"+0987654321", true, "block", "Europe/Madrid", now, now, nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM gym").
@@ -120,10 +120,10 @@ func TestUpdateGym(t *testing.T) {
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "email", "address", "phone", "is_active", "created_at", "updated_at",
+		"id", "name", "email", "address", "phone", "is_active", "contraindication_policy", "timezone", "created_at", "updated_at",
 	}).AddRow(
 		"gym123", updateDTO.Name, updateDTO.Email, updateDTO.Address,
-		updateDTO.Phone, true, time.Now(), time.Now(),
+		updateDTO.Phone, true, "warn", "UTC", time.Now(), time.Now(),
 	)
 
 	mock.ExpectQuery("UPDATE gym").WithArgs(
@@ -131,6 +131,8 @@ func TestUpdateGym(t *testing.T) {
 		updateDTO.Email,
 		updateDTO.Address,
 		updateDTO.Phone,
+		updateDTO.ContraindicationPolicy,
+		updateDTO.Timezone,
 		sqlmock.AnyArg(), // updated_at
 		"gym123",         // id
 	).WillReturnRows(rows)
@@ -7,7 +7,7 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func NewGymRouter(handler gyminterfaces.GymHandler) http.Handler {
+func NewGymRouter(handler gyminterfaces.GymHandler, dashboard http.Handler) http.Handler {
 	r := chi.NewRouter()
 
 	// Auth middleware is applied globally at the API level
@@ -24,5 +24,8 @@ func NewGymRouter(handler gyminterfaces.GymHandler) http.Handler {
 	r.Put("/{id}/deactivate", handler.SetGymActive) // PUT /gym/{id}/deactivate
 	r.Delete("/{id}", handler.DeleteGym)            // DELETE /gym/{id}
 
+	// Aggregated figures for gym administrators
+	r.Mount("/{id}/dashboard", dashboard) // GET /gym/{id}/dashboard
+
 	return r
 }
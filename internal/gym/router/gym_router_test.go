@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/alejandro-albiol/athenai/internal/gym/router"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -41,7 +42,10 @@ func TestGymRouter_Routes(t *testing.T) {
 	}
 
 	mockHandler := new(MockGymHandler)
-	r := router.NewGymRouter(mockHandler)
+	dashboard := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(chi.URLParam(r, "id") + " " + r.URL.Path))
+	})
+	r := router.NewGymRouter(mockHandler, dashboard)
 
 	for _, route := range routes {
 		t.Run(route.name, func(t *testing.T) {
@@ -58,6 +62,14 @@ func TestGymRouter_Routes(t *testing.T) {
 		})
 	}
 
+	t.Run("dashboard is mounted under the gym", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/gym123/dashboard/refresh", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gym123 /gym123/dashboard/refresh", w.Body.String())
+	})
+
 	t.Run("unknown route returns 404", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/unknown/fakerouter/invent", nil)
 		w := httptest.NewRecorder()
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"errors"
 	"os"
+	"time"
 
 	"github.com/alejandro-albiol/athenai/internal/database"
 	"github.com/alejandro-albiol/athenai/internal/gym/dto"
+	"github.com/alejandro-albiol/athenai/internal/gym/enum"
 	"github.com/alejandro-albiol/athenai/internal/gym/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
@@ -85,6 +87,14 @@ func (s *GymService) GetAllGyms() ([]*dto.GymResponseDTO, error) {
 }
 
 func (s *GymService) UpdateGym(id string, updateDTO *dto.GymUpdateDTO) (*dto.GymResponseDTO, error) {
+	if updateDTO.ContraindicationPolicy != nil && !enum.ContraindicationPolicy(*updateDTO.ContraindicationPolicy).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid contraindication policy, must be warn or block", nil)
+	}
+	if updateDTO.Timezone != nil {
+		if _, err := time.LoadLocation(*updateDTO.Timezone); err != nil || *updateDTO.Timezone == "" || *updateDTO.Timezone == "Local" {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid timezone, must be an IANA name such as Europe/Madrid", err)
+		}
+	}
 
 	// Check if gym exists before updating
 	existingGym, err := s.repository.GetGymByID(id)
@@ -189,6 +189,22 @@ func TestUpdateGym(t *testing.T) {
 		assert.ErrorAs(t, err, &apiErr)
 		assert.Equal(t, errorcode_enum.CodeNotFound, apiErr.Code)
 	})
+
+	t.Run("invalid contraindication policy", func(t *testing.T) {
+		policy := "ignore"
+		_, err := svc.UpdateGym("gym123", &dto.GymUpdateDTO{ContraindicationPolicy: &policy})
+		var apiErr *apierror.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		timezone := "Mars/Olympus_Mons"
+		_, err := svc.UpdateGym("gym123", &dto.GymUpdateDTO{Timezone: &timezone})
+		var apiErr *apierror.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+	})
 }
 
 func TestDeleteGym(t *testing.T) {
@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+// DashboardQuery is the range of a dashboard, YYYY-MM-DD days in the gym's timezone; empty ones cover the current week
+type DashboardQuery struct {
+	From string
+	To   string
+}
+
+// DashboardDTO is the overview of a gym over a range of days. Workouts count on their scheduled day, or the
+// day they were created when they have none, and come from aggregates refreshed every few minutes.
+type DashboardDTO struct {
+	GymID       string    `json:"gym_id"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Timezone    string    `json:"timezone"`
+	RefreshedAt time.Time `json:"refreshed_at"` // when the workout aggregates were last refreshed
+	// ActiveMembers counts active member accounts now; NewSignups the members who joined in the range
+	ActiveMembers int              `json:"active_members"`
+	NewSignups    int              `json:"new_signups"`
+	Workouts      WorkoutCountsDTO `json:"workouts"`
+	// AverageRating is the average rating of the completed sessions rated in the range
+	AverageRating   *float64              `json:"average_rating"`
+	RatedSessions   int                   `json:"rated_sessions"`
+	TopTemplates    []*TemplateUsageDTO   `json:"top_templates"`
+	TopExercises    []*ExerciseUsageDTO   `json:"top_exercises"`
+	TrainerWorkload []*TrainerWorkloadDTO `json:"trainer_workload"`
+}
+
+// WorkoutCountsDTO counts member workouts by status; Scheduled counts every workout planned in the range that
+// was not cancelled and Pending those not yet completed, skipped or cancelled
+type WorkoutCountsDTO struct {
+	Scheduled int `json:"scheduled"`
+	Completed int `json:"completed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+	Pending   int `json:"pending"`
+}
+
+// TemplateUsageDTO is a workout template with the member workouts built from it in the range
+type TemplateUsageDTO struct {
+	TemplateSource string `json:"template_source"` // public or gym
+	TemplateID     string `json:"template_id"`
+	Name           string `json:"name"`
+	Workouts       int    `json:"workouts"`
+	Completed      int    `json:"completed"`
+}
+
+// ExerciseUsageDTO is an exercise with the member workouts that include it in the range, cancelled ones aside
+type ExerciseUsageDTO struct {
+	ExerciseSource string `json:"exercise_source"` // public or gym
+	ExerciseID     string `json:"exercise_id"`
+	Name           string `json:"name"`
+	Workouts       int    `json:"workouts"`
+	CompletedSets  int    `json:"completed_sets"`
+}
+
+// TrainerWorkloadDTO is an active trainer or administrator with the member workouts they assigned in the range
+type TrainerWorkloadDTO struct {
+	UserID        string   `json:"user_id"`
+	Username      string   `json:"username"`
+	Role          string   `json:"role"`
+	Assigned      int      `json:"assigned"`
+	Completed     int      `json:"completed"`
+	Skipped       int      `json:"skipped"`
+	AverageRating *float64 `json:"average_rating"`
+	RatingSum     int      `json:"-"`
+	RatedSessions int      `json:"-"`
+}
+
+// StatusCount is the aggregate of the workouts of a status in a range
+type StatusCount struct {
+	Status      string
+	Workouts    int
+	RatingSum   int
+	RatingCount int
+}
+
+// MemberCounts are the active members of a gym and those who joined in a range
+type MemberCounts struct {
+	Active     int
+	NewSignups int
+}
+
+// RefreshResultDTO tells when the dashboard aggregates were refreshed
+type RefreshResultDTO struct {
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
@@ -0,0 +1,16 @@
+package enum
+
+type ExportFormat string
+
+const (
+	JSON ExportFormat = "json"
+	CSV  ExportFormat = "csv"
+)
+
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case JSON, CSV:
+		return true
+	}
+	return false
+}
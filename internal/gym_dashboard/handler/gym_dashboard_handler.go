@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/enum"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/service"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type GymDashboardHandler struct {
+	service interfaces.GymDashboardService
+}
+
+func NewGymDashboardHandler(service interfaces.GymDashboardService) *GymDashboardHandler {
+	return &GymDashboardHandler{service: service}
+}
+
+func (h *GymDashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	gymID, ok := requireGymAdmin(w, r)
+	if !ok {
+		return
+	}
+	format := enum.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = enum.JSON
+	}
+	if !format.IsValid() {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid format, must be json or csv", nil))
+		return
+	}
+	dashboard, err := h.service.GetDashboard(gymID, dto.DashboardQuery{
+		From: r.URL.Query().Get("from"),
+		To:   r.URL.Query().Get("to"),
+	})
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get gym dashboard")
+		return
+	}
+	if format == enum.CSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "dashboard-"+dashboard.From+"-"+dashboard.To+".csv"))
+		w.WriteHeader(http.StatusOK)
+		// The status is already sent, so a failed write can only be logged
+		if err := service.EncodeDashboardCSV(w, dashboard); err != nil {
+			log.Printf("Failed to write gym dashboard CSV for gym %s: %v", gymID, err)
+		}
+		return
+	}
+	response.WriteAPISuccess(w, "Gym dashboard retrieved successfully", dashboard)
+}
+
+func (h *GymDashboardHandler) RefreshDashboard(w http.ResponseWriter, r *http.Request) {
+	gymID, ok := requireGymAdmin(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.service.RefreshDashboard(gymID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to refresh gym dashboard")
+		return
+	}
+	response.WriteAPISuccess(w, "Gym dashboard refreshed successfully", result)
+}
+
+// requireGymAdmin lets platform administrators and the administrators of the gym in the path through
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) (string, bool) {
+	gymID := chi.URLParam(r, "id")
+	if !middleware.ValidateGymAccess(r, gymID) || !middleware.IsGymAdmin(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can see the gym dashboard", nil))
+		return "", false
+	}
+	return gymID, true
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.GymDashboardService
+	gymID     string
+	query     dto.DashboardQuery
+	refreshed bool
+}
+
+func (m *mockService) GetDashboard(gymID string, query dto.DashboardQuery) (*dto.DashboardDTO, error) {
+	m.gymID, m.query = gymID, query
+	return &dto.DashboardDTO{GymID: gymID, From: "2026-10-19", To: "2026-10-25", ActiveMembers: 40}, nil
+}
+func (m *mockService) RefreshDashboard(gymID string) (*dto.RefreshResultDTO, error) {
+	m.gymID, m.refreshed = gymID, true
+	return &dto.RefreshResultDTO{}, nil
+}
+
+// serve mounts the dashboard the way the gym router does, so the gym comes from the path
+func serve(svc *mockService, method, target, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(testutil.Mount("/{id}/dashboard", router.NewGymDashboardRouter(NewGymDashboardHandler(svc))),
+		testutil.Caller{Role: role, UserID: "user-1", GymID: "gym-1"}, method, target, "")
+}
+
+func TestOnlyAdminsOfTheGymSeeTheDashboard(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/gym-1/dashboard?from=2026-10-01&to=2026-10-31", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gym-1", svc.gymID)
+	assert.Equal(t, dto.DashboardQuery{From: "2026-10-01", To: "2026-10-31"}, svc.query)
+
+	w = serve(svc, http.MethodGet, "/gym-1/dashboard", "trainer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	svc.gymID = ""
+	w = serve(svc, http.MethodGet, "/gym-2/dashboard", "admin")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, svc.gymID)
+}
+
+func TestDashboardExportsCSV(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/gym-1/dashboard?format=csv", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="dashboard-2026-10-19-2026-10-25.csv"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "metric,key,name,value\n"))
+	assert.Contains(t, w.Body.String(), "active_members,,,40")
+
+	w = serve(svc, http.MethodGet, "/gym-1/dashboard?format=xlsx", "admin")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminsCanRefreshTheDashboard(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/gym-1/dashboard/refresh", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, svc.refreshed)
+
+	w = serve(svc, http.MethodPost, "/gym-1/dashboard/refresh", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, svc.refreshed)
+}
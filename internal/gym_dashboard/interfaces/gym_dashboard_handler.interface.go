@@ -0,0 +1,8 @@
+package interfaces
+
+import "net/http"
+
+type GymDashboardHandler interface {
+	GetDashboard(w http.ResponseWriter, r *http.Request)
+	RefreshDashboard(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+)
+
+// GymDashboardRepository reads the dashboard aggregates of a gym. Workout figures come from materialized views
+// by day; from and to are inclusive days.
+type GymDashboardRepository interface {
+	// FindRefreshedAt gives when the aggregates were last refreshed, nil if never
+	FindRefreshedAt(gymID string) (*time.Time, error)
+	// Refresh recomputes the aggregates and gives when
+	Refresh(gymID string) (time.Time, error)
+
+	// CountMembers counts active members and the members created in [start, end)
+	CountMembers(gymID string, start, end time.Time) (*dto.MemberCounts, error)
+	FindStatusCounts(gymID, from, to string) ([]dto.StatusCount, error)
+	FindTopTemplates(gymID, from, to string, limit int) ([]*dto.TemplateUsageDTO, error)
+	FindTopExercises(gymID, from, to string, limit int) ([]*dto.ExerciseUsageDTO, error)
+	// FindTrainerWorkload lists every active trainer and administrator, with the workouts they assigned
+	FindTrainerWorkload(gymID, from, to string) ([]*dto.TrainerWorkloadDTO, error)
+}
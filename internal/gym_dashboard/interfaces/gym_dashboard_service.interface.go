@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+
+type GymDashboardService interface {
+	GetDashboard(gymID string, query dto.DashboardQuery) (*dto.DashboardDTO, error)
+	RefreshDashboard(gymID string) (*dto.RefreshResultDTO, error)
+}
@@ -0,0 +1,18 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/handler"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/repository"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/router"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/service"
+)
+
+func NewGymDashboardModule(db *sql.DB) http.Handler {
+	service := service.NewGymDashboardService(repository.NewGymDashboardRepository(db), gym_repository.NewGymRepository(db))
+	handler := handler.NewGymDashboardHandler(service)
+	return router.NewGymDashboardRouter(handler)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+	"github.com/lib/pq"
+)
+
+type GymDashboardRepository struct {
+	db *sql.DB
+}
+
+func NewGymDashboardRepository(db *sql.DB) *GymDashboardRepository {
+	return &GymDashboardRepository{db: db}
+}
+
+func (r *GymDashboardRepository) FindRefreshedAt(gymID string) (*time.Time, error) {
+	var refreshedAt time.Time
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT refreshed_at FROM %s.dashboard_refresh`, pq.QuoteIdentifier(gymID))).Scan(&refreshedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &refreshedAt, nil
+}
+
+// Refresh rebuilds the views concurrently, so dashboards keep reading the previous aggregates meanwhile
+func (r *GymDashboardRepository) Refresh(gymID string) (time.Time, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	for _, view := range []string{"dashboard_workout_daily", "dashboard_exercise_daily"} {
+		if _, err := r.db.Exec(`REFRESH MATERIALIZED VIEW CONCURRENTLY ` + schema + `.` + view); err != nil {
+			return time.Time{}, err
+		}
+	}
+	var refreshedAt time.Time
+	err := r.db.QueryRow(`INSERT INTO ` + schema + `.dashboard_refresh (refreshed_at) VALUES (NOW())
+		ON CONFLICT (id) DO UPDATE SET refreshed_at = EXCLUDED.refreshed_at
+		RETURNING refreshed_at`).Scan(&refreshedAt)
+	return refreshedAt, err
+}
+
+func (r *GymDashboardRepository) CountMembers(gymID string, start, end time.Time) (*dto.MemberCounts, error) {
+	var counts dto.MemberCounts
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FILTER (WHERE is_active),
+			COUNT(*) FILTER (WHERE created_at >= $1 AND created_at < $2)
+		FROM %s.user WHERE role = 'member'`, pq.QuoteIdentifier(gymID)), start, end).
+		Scan(&counts.Active, &counts.NewSignups)
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *GymDashboardRepository) FindStatusCounts(gymID, from, to string) ([]dto.StatusCount, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT status, SUM(workouts), SUM(rating_sum), SUM(rating_count)
+		FROM %s.dashboard_workout_daily
+		WHERE day BETWEEN $1 AND $2
+		GROUP BY status ORDER BY status`, pq.QuoteIdentifier(gymID)), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := []dto.StatusCount{}
+	for rows.Next() {
+		var count dto.StatusCount
+		if err := rows.Scan(&count.Status, &count.Workouts, &count.RatingSum, &count.RatingCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// FindTopTemplates ranks templates by the member workouts built from them, cancelled ones aside
+func (r *GymDashboardRepository) FindTopTemplates(gymID, from, to string, limit int) ([]*dto.TemplateUsageDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT d.template_source, d.template_id, COALESCE(pt.name, gt.name, ''),
+			SUM(d.workouts) AS used, COALESCE(SUM(d.workouts) FILTER (WHERE d.status = 'completed'), 0)
+		FROM %[1]s.dashboard_workout_daily d
+		LEFT JOIN public.workout_template pt ON d.template_source = 'public' AND pt.id = d.template_id
+		LEFT JOIN %[1]s.custom_workout_template gt ON d.template_source = 'gym' AND gt.id = d.template_id
+		WHERE d.day BETWEEN $1 AND $2 AND d.status <> 'cancelled'
+		GROUP BY d.template_source, d.template_id, pt.name, gt.name
+		ORDER BY used DESC, 3, d.template_id
+		LIMIT $3`, pq.QuoteIdentifier(gymID)), from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	templates := []*dto.TemplateUsageDTO{}
+	for rows.Next() {
+		var template dto.TemplateUsageDTO
+		if err := rows.Scan(&template.TemplateSource, &template.TemplateID, &template.Name, &template.Workouts, &template.Completed); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *GymDashboardRepository) FindTopExercises(gymID, from, to string, limit int) ([]*dto.ExerciseUsageDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT d.exercise_source, d.exercise_id, COALESCE(pe.name, ge.name, ''),
+			SUM(d.workouts) AS used, SUM(d.completed_sets)
+		FROM %[1]s.dashboard_exercise_daily d
+		LEFT JOIN public.exercise pe ON d.exercise_source = 'public' AND pe.id = d.exercise_id
+		LEFT JOIN %[1]s.custom_exercise ge ON d.exercise_source = 'gym' AND ge.id = d.exercise_id
+		WHERE d.day BETWEEN $1 AND $2
+		GROUP BY d.exercise_source, d.exercise_id, pe.name, ge.name
+		ORDER BY used DESC, 3, d.exercise_id
+		LIMIT $3`, pq.QuoteIdentifier(gymID)), from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	exercises := []*dto.ExerciseUsageDTO{}
+	for rows.Next() {
+		var exercise dto.ExerciseUsageDTO
+		if err := rows.Scan(&exercise.ExerciseSource, &exercise.ExerciseID, &exercise.Name, &exercise.Workouts, &exercise.CompletedSets); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, &exercise)
+	}
+	return exercises, rows.Err()
+}
+
+// FindTrainerWorkload counts the workouts each staff member assigned, cancelled ones aside. Administrators are
+// stored as admin or gym_admin depending on how their account was created.
+func (r *GymDashboardRepository) FindTrainerWorkload(gymID, from, to string) ([]*dto.TrainerWorkloadDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT u.id, u.username, u.role,
+			COALESCE(a.assigned, 0), COALESCE(a.completed, 0), COALESCE(a.skipped, 0),
+			COALESCE(a.rating_sum, 0), COALESCE(a.rating_count, 0)
+		FROM %[1]s.user u
+		LEFT JOIN (
+			SELECT created_by, SUM(workouts) AS assigned,
+				SUM(workouts) FILTER (WHERE status = 'completed') AS completed,
+				SUM(workouts) FILTER (WHERE status = 'skipped') AS skipped,
+				SUM(rating_sum) AS rating_sum, SUM(rating_count) AS rating_count
+			FROM %[1]s.dashboard_workout_daily
+			WHERE day BETWEEN $1 AND $2 AND status <> 'cancelled'
+			GROUP BY created_by
+		) a ON a.created_by = u.id
+		WHERE u.role IN ('admin', 'gym_admin', 'trainer') AND u.is_active
+		ORDER BY COALESCE(a.assigned, 0) DESC, u.username`, pq.QuoteIdentifier(gymID)), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	workload := []*dto.TrainerWorkloadDTO{}
+	for rows.Next() {
+		var trainer dto.TrainerWorkloadDTO
+		if err := rows.Scan(&trainer.UserID, &trainer.Username, &trainer.Role, &trainer.Assigned, &trainer.Completed,
+			&trainer.Skipped, &trainer.RatingSum, &trainer.RatedSessions); err != nil {
+			return nil, err
+		}
+		workload = append(workload, &trainer)
+	}
+	return workload, rows.Err()
+}
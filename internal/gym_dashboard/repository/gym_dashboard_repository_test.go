@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRefreshedAtBeforeFirstRefresh(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewGymDashboardRepository(db)
+
+	mock.ExpectQuery(`SELECT refreshed_at FROM "gym-1".dashboard_refresh`).WillReturnError(sql.ErrNoRows)
+	refreshedAt, err := repo.FindRefreshedAt("gym-1")
+	require.NoError(t, err)
+	assert.Nil(t, refreshedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshRebuildsViewsConcurrently(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewGymDashboardRepository(db)
+	at := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`REFRESH MATERIALIZED VIEW CONCURRENTLY "gym-1".dashboard_workout_daily`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`REFRESH MATERIALIZED VIEW CONCURRENTLY "gym-1".dashboard_exercise_daily`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO "gym-1".dashboard_refresh \(refreshed_at\) VALUES \(NOW\(\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"refreshed_at"}).AddRow(at))
+
+	refreshedAt, err := repo.Refresh("gym-1")
+	require.NoError(t, err)
+	assert.Equal(t, at, refreshedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindStatusCountsReadsTheDailyView(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewGymDashboardRepository(db)
+
+	mock.ExpectQuery(`FROM "gym-1".dashboard_workout_daily\s+WHERE day BETWEEN \$1 AND \$2\s+GROUP BY status`).
+		WithArgs("2026-10-19", "2026-10-25").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "workouts", "rating_sum", "rating_count"}).
+			AddRow("completed", 4, 15, 4).
+			AddRow("scheduled", 2, 0, 0))
+
+	counts, err := repo.FindStatusCounts("gym-1", "2026-10-19", "2026-10-25")
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, "completed", counts[0].Status)
+	assert.Equal(t, 15, counts[0].RatingSum)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindTopTemplatesNamesPublicAndGymTemplates(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewGymDashboardRepository(db)
+
+	mock.ExpectQuery(`LEFT JOIN public.workout_template pt .* LEFT JOIN "gym-1".custom_workout_template gt .* LIMIT \$3`).
+		WithArgs("2026-10-19", "2026-10-25", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"template_source", "template_id", "name", "used", "completed"}).
+			AddRow("gym", "tpl-1", "Full body", 5, 3))
+
+	templates, err := repo.FindTopTemplates("gym-1", "2026-10-19", "2026-10-25", 10)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "Full body", templates[0].Name)
+	assert.Equal(t, 3, templates[0].Completed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindTrainerWorkloadListsStaffWithoutWorkouts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewGymDashboardRepository(db)
+
+	mock.ExpectQuery(`WHERE u.role IN \('admin', 'gym_admin', 'trainer'\) AND u.is_active`).
+		WithArgs("2026-10-19", "2026-10-25").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "role", "assigned", "completed", "skipped", "rating_sum", "rating_count"}).
+			AddRow("coach-1", "ana", "trainer", 6, 4, 1, 9, 2).
+			AddRow("coach-2", "luis", "trainer", 0, 0, 0, 0, 0))
+
+	workload, err := repo.FindTrainerWorkload("gym-1", "2026-10-19", "2026-10-25")
+	require.NoError(t, err)
+	require.Len(t, workload, 2)
+	assert.Equal(t, 6, workload[0].Assigned)
+	assert.Equal(t, 2, workload[0].RatedSessions)
+	assert.Equal(t, 0, workload[1].Assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
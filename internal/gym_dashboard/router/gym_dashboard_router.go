@@ -0,0 +1,15 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewGymDashboardRouter(handler interfaces.GymDashboardHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", handler.GetDashboard)             // GET /gym/{id}/dashboard?from=&to=&format=json|csv
+	r.Post("/refresh", handler.RefreshDashboard) // POST /gym/{id}/dashboard/refresh
+	return r
+}
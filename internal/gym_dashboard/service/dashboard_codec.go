@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+)
+
+// CSV exports carry one figure per row so spreadsheets can pivot them. Key identifies the template, exercise
+// or trainer a figure belongs to, as source:id for templates and exercises, and name is its display name.
+var csvHeader = []string{"metric", "key", "name", "value"}
+
+// EncodeDashboardCSV writes the dashboard as CSV rows
+func EncodeDashboardCSV(w io.Writer, dashboard *dto.DashboardDTO) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		csvHeader,
+		{"from", "", "", dashboard.From},
+		{"to", "", "", dashboard.To},
+		{"timezone", "", "", dashboard.Timezone},
+		{"refreshed_at", "", "", dashboard.RefreshedAt.UTC().Format(time.RFC3339)},
+		{"active_members", "", "", strconv.Itoa(dashboard.ActiveMembers)},
+		{"new_signups", "", "", strconv.Itoa(dashboard.NewSignups)},
+		{"workouts_scheduled", "", "", strconv.Itoa(dashboard.Workouts.Scheduled)},
+		{"workouts_completed", "", "", strconv.Itoa(dashboard.Workouts.Completed)},
+		{"workouts_skipped", "", "", strconv.Itoa(dashboard.Workouts.Skipped)},
+		{"workouts_cancelled", "", "", strconv.Itoa(dashboard.Workouts.Cancelled)},
+		{"workouts_pending", "", "", strconv.Itoa(dashboard.Workouts.Pending)},
+		{"average_rating", "", "", formatRating(dashboard.AverageRating)},
+		{"rated_sessions", "", "", strconv.Itoa(dashboard.RatedSessions)},
+	}
+	for _, template := range dashboard.TopTemplates {
+		key := template.TemplateSource + ":" + template.TemplateID
+		rows = append(rows,
+			[]string{"template_workouts", key, template.Name, strconv.Itoa(template.Workouts)},
+			[]string{"template_completed", key, template.Name, strconv.Itoa(template.Completed)},
+		)
+	}
+	for _, exercise := range dashboard.TopExercises {
+		key := exercise.ExerciseSource + ":" + exercise.ExerciseID
+		rows = append(rows,
+			[]string{"exercise_workouts", key, exercise.Name, strconv.Itoa(exercise.Workouts)},
+			[]string{"exercise_completed_sets", key, exercise.Name, strconv.Itoa(exercise.CompletedSets)},
+		)
+	}
+	for _, trainer := range dashboard.TrainerWorkload {
+		rows = append(rows,
+			[]string{"trainer_assigned", trainer.UserID, trainer.Username, strconv.Itoa(trainer.Assigned)},
+			[]string{"trainer_completed", trainer.UserID, trainer.Username, strconv.Itoa(trainer.Completed)},
+			[]string{"trainer_skipped", trainer.UserID, trainer.Username, strconv.Itoa(trainer.Skipped)},
+			[]string{"trainer_average_rating", trainer.UserID, trainer.Username, formatRating(trainer.AverageRating)},
+		)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// formatRating leaves unrated figures empty rather than zero
+func formatRating(rating *float64) string {
+	if rating == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*rating, 'f', -1, 64)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	workout_enum "github.com/alejandro-albiol/athenai/internal/custom_member_workout/enum"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// maxRangeDays caps the span of a request
+	maxRangeDays = 366
+	// Aggregates older than staleAfter are refreshed before they are read
+	staleAfter = 15 * time.Minute
+	// topLimit is how many templates and exercises the dashboard ranks
+	topLimit = 10
+)
+
+type GymDashboardService struct {
+	repository interfaces.GymDashboardRepository
+	gyms       gymIF.GymRepository
+	now        func() time.Time
+}
+
+func NewGymDashboardService(repo interfaces.GymDashboardRepository, gyms gymIF.GymRepository) *GymDashboardService {
+	return &GymDashboardService{repository: repo, gyms: gyms, now: time.Now}
+}
+
+// GetDashboard aggregates the days between from and to, both inclusive, defaulting to the current week from
+// Monday to Sunday in the gym's timezone. Workout figures are read from the cached aggregates, refreshed first
+// when they are stale; member figures are always current.
+func (s *GymDashboardService) GetDashboard(gymID string, query dto.DashboardQuery) (*dto.DashboardDTO, error) {
+	gym, err := s.gyms.GetGymByID(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym", err)
+	}
+	timezone := gym.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Invalid gym timezone", err)
+	}
+
+	from := weekStart(civilDate(s.now().In(loc)))
+	to := from.AddDate(0, 0, 6)
+	if query.From != "" {
+		if from, err = time.Parse(dateLayout, query.From); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "From must be a date in YYYY-MM-DD format", err)
+		}
+		if query.To == "" {
+			to = from.AddDate(0, 0, 6)
+		}
+	}
+	if query.To != "" {
+		if to, err = time.Parse(dateLayout, query.To); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "To must be a date in YYYY-MM-DD format", err)
+		}
+		if query.From == "" {
+			from = to.AddDate(0, 0, -6)
+		}
+	}
+	if from.After(to) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "From must not be after to", nil)
+	}
+	if to.Sub(from).Hours()/24 >= maxRangeDays {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "The date range can span at most a year", nil)
+	}
+
+	refreshedAt, err := s.refreshedAt(gymID)
+	if err != nil {
+		return nil, err
+	}
+	fromDay, toDay := from.Format(dateLayout), to.Format(dateLayout)
+	members, err := s.repository.CountMembers(gymID, inLocation(from, loc), inLocation(to.AddDate(0, 0, 1), loc))
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to count members", err)
+	}
+	statuses, err := s.repository.FindStatusCounts(gymID, fromDay, toDay)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to count workouts", err)
+	}
+	templates, err := s.repository.FindTopTemplates(gymID, fromDay, toDay, topLimit)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get most used templates", err)
+	}
+	exercises, err := s.repository.FindTopExercises(gymID, fromDay, toDay, topLimit)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get most used exercises", err)
+	}
+	workload, err := s.repository.FindTrainerWorkload(gymID, fromDay, toDay)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get trainer workload", err)
+	}
+	for _, trainer := range workload {
+		trainer.AverageRating = average(trainer.RatingSum, trainer.RatedSessions)
+	}
+
+	dashboard := &dto.DashboardDTO{
+		GymID:           gymID,
+		From:            fromDay,
+		To:              toDay,
+		Timezone:        timezone,
+		RefreshedAt:     refreshedAt,
+		ActiveMembers:   members.Active,
+		NewSignups:      members.NewSignups,
+		TopTemplates:    templates,
+		TopExercises:    exercises,
+		TrainerWorkload: workload,
+	}
+	ratingSum := 0
+	for _, status := range statuses {
+		if workout_enum.CustomMemberWorkoutStatus(status.Status) != workout_enum.StatusCancelled {
+			dashboard.Workouts.Scheduled += status.Workouts
+		}
+		switch workout_enum.CustomMemberWorkoutStatus(status.Status) {
+		case workout_enum.StatusCompleted:
+			dashboard.Workouts.Completed += status.Workouts
+		case workout_enum.StatusSkipped:
+			dashboard.Workouts.Skipped += status.Workouts
+		case workout_enum.StatusCancelled:
+			dashboard.Workouts.Cancelled += status.Workouts
+		default:
+			dashboard.Workouts.Pending += status.Workouts
+		}
+		ratingSum += status.RatingSum
+		dashboard.RatedSessions += status.RatingCount
+	}
+	dashboard.AverageRating = average(ratingSum, dashboard.RatedSessions)
+	return dashboard, nil
+}
+
+// RefreshDashboard recomputes the aggregates now, for admins who cannot wait for them to go stale
+func (s *GymDashboardService) RefreshDashboard(gymID string) (*dto.RefreshResultDTO, error) {
+	refreshedAt, err := s.repository.Refresh(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to refresh dashboard", err)
+	}
+	return &dto.RefreshResultDTO{RefreshedAt: refreshedAt}, nil
+}
+
+func (s *GymDashboardService) refreshedAt(gymID string) (time.Time, error) {
+	refreshedAt, err := s.repository.FindRefreshedAt(gymID)
+	if err != nil {
+		return time.Time{}, apierror.New(errorcode_enum.CodeInternal, "Failed to get dashboard freshness", err)
+	}
+	if refreshedAt != nil && s.now().Sub(*refreshedAt) < staleAfter {
+		return *refreshedAt, nil
+	}
+	result, err := s.RefreshDashboard(gymID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return result.RefreshedAt, nil
+}
+
+// average is the mean rating rounded to two decimals, unset when nothing was rated
+func average(sum, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	value := math.Round(float64(sum)/float64(count)*100) / 100
+	return &value
+}
+
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// civilDate keeps the calendar day of a time, as a UTC midnight so days can be compared and added safely
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// inLocation is the midnight a calendar day starts at in loc
+func inLocation(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	gymDTO "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/gym_dashboard/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	refreshedAt *time.Time
+	refreshes   int
+	start, end  time.Time
+	from, to    string
+	statuses    []dto.StatusCount
+	workload    []*dto.TrainerWorkloadDTO
+}
+
+func (m *mockRepo) FindRefreshedAt(gymID string) (*time.Time, error) {
+	return m.refreshedAt, nil
+}
+func (m *mockRepo) Refresh(gymID string) (time.Time, error) {
+	m.refreshes++
+	return now, nil
+}
+func (m *mockRepo) CountMembers(gymID string, start, end time.Time) (*dto.MemberCounts, error) {
+	m.start, m.end = start, end
+	return &dto.MemberCounts{Active: 40, NewSignups: 3}, nil
+}
+func (m *mockRepo) FindStatusCounts(gymID, from, to string) ([]dto.StatusCount, error) {
+	m.from, m.to = from, to
+	return m.statuses, nil
+}
+func (m *mockRepo) FindTopTemplates(gymID, from, to string, limit int) ([]*dto.TemplateUsageDTO, error) {
+	return []*dto.TemplateUsageDTO{{TemplateSource: "gym", TemplateID: "tpl-1", Name: "Full body", Workouts: 5, Completed: 3}}, nil
+}
+func (m *mockRepo) FindTopExercises(gymID, from, to string, limit int) ([]*dto.ExerciseUsageDTO, error) {
+	return []*dto.ExerciseUsageDTO{{ExerciseSource: "public", ExerciseID: "ex-1", Name: "Squat, back", Workouts: 4, CompletedSets: 12}}, nil
+}
+func (m *mockRepo) FindTrainerWorkload(gymID, from, to string) ([]*dto.TrainerWorkloadDTO, error) {
+	return m.workload, nil
+}
+
+type mockGyms struct {
+	gymIF.GymRepository
+}
+
+func (m *mockGyms) GetGymByID(id string) (*gymDTO.GymResponseDTO, error) {
+	return &gymDTO.GymResponseDTO{ID: id, Timezone: "Europe/Madrid"}, nil
+}
+
+// Wednesday 22 October 2026, just before midnight in Madrid
+var now = time.Date(2026, 10, 22, 21, 30, 0, 0, time.UTC)
+
+func newService(repo *mockRepo) *GymDashboardService {
+	s := NewGymDashboardService(repo, &mockGyms{})
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestGetDashboardDefaultsToTheCurrentWeek(t *testing.T) {
+	fresh := now.Add(-5 * time.Minute)
+	repo := &mockRepo{refreshedAt: &fresh}
+
+	dashboard, err := newService(repo).GetDashboard("gym-1", dto.DashboardQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-19", dashboard.From)
+	assert.Equal(t, "2026-10-25", dashboard.To)
+	assert.Equal(t, "2026-10-19", repo.from)
+	assert.Equal(t, "2026-10-25", repo.to)
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, madrid), repo.start)
+	assert.Equal(t, time.Date(2026, 10, 26, 0, 0, 0, 0, madrid), repo.end)
+	assert.Equal(t, 0, repo.refreshes)
+	assert.Equal(t, fresh, dashboard.RefreshedAt)
+}
+
+func TestGetDashboardRefreshesStaleAggregates(t *testing.T) {
+	stale := now.Add(-time.Hour)
+	repo := &mockRepo{refreshedAt: &stale}
+	dashboard, err := newService(repo).GetDashboard("gym-1", dto.DashboardQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.refreshes)
+	assert.Equal(t, now, dashboard.RefreshedAt)
+
+	repo = &mockRepo{}
+	_, err = newService(repo).GetDashboard("gym-1", dto.DashboardQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.refreshes, "aggregates never refreshed are refreshed")
+}
+
+func TestGetDashboardAddsUpWorkoutsAndRatings(t *testing.T) {
+	repo := &mockRepo{
+		statuses: []dto.StatusCount{
+			{Status: "cancelled", Workouts: 1},
+			{Status: "completed", Workouts: 4, RatingSum: 17, RatingCount: 4},
+			{Status: "in_progress", Workouts: 1},
+			{Status: "scheduled", Workouts: 3},
+			{Status: "skipped", Workouts: 2},
+		},
+		workload: []*dto.TrainerWorkloadDTO{
+			{UserID: "coach-1", Assigned: 6, RatingSum: 9, RatedSessions: 2},
+			{UserID: "coach-2"},
+		},
+	}
+	dashboard, err := newService(repo).GetDashboard("gym-1", dto.DashboardQuery{From: "2026-10-01", To: "2026-10-31"})
+	require.NoError(t, err)
+	assert.Equal(t, dto.WorkoutCountsDTO{Scheduled: 10, Completed: 4, Skipped: 2, Cancelled: 1, Pending: 4}, dashboard.Workouts)
+	assert.Equal(t, 40, dashboard.ActiveMembers)
+	assert.Equal(t, 3, dashboard.NewSignups)
+	require.NotNil(t, dashboard.AverageRating)
+	assert.Equal(t, 4.25, *dashboard.AverageRating)
+	assert.Equal(t, 4, dashboard.RatedSessions)
+	require.NotNil(t, dashboard.TrainerWorkload[0].AverageRating)
+	assert.Equal(t, 4.5, *dashboard.TrainerWorkload[0].AverageRating)
+	assert.Nil(t, dashboard.TrainerWorkload[1].AverageRating)
+}
+
+func TestGetDashboardValidatesTheRange(t *testing.T) {
+	cases := []dto.DashboardQuery{
+		{From: "19/10/2026"},
+		{To: "2026-13-01"},
+		{From: "2026-10-25", To: "2026-10-19"},
+		{From: "2025-01-01", To: "2026-10-19"},
+	}
+	for _, query := range cases {
+		_, err := newService(&mockRepo{}).GetDashboard("gym-1", query)
+		var apiErr *apierror.APIError
+		require.ErrorAs(t, err, &apiErr, "%+v", query)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+	}
+
+	repo := &mockRepo{}
+	dashboard, err := newService(repo).GetDashboard("gym-1", dto.DashboardQuery{From: "2026-09-01"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09-07", dashboard.To, "a lone start date gives a week")
+}
+
+func TestEncodeDashboardCSV(t *testing.T) {
+	rating := 4.25
+	dashboard := &dto.DashboardDTO{
+		From: "2026-10-19", To: "2026-10-25", Timezone: "Europe/Madrid", RefreshedAt: now,
+		ActiveMembers: 40, AverageRating: &rating,
+		TopExercises:    []*dto.ExerciseUsageDTO{{ExerciseSource: "public", ExerciseID: "ex-1", Name: "Squat, back", Workouts: 4, CompletedSets: 12}},
+		TrainerWorkload: []*dto.TrainerWorkloadDTO{{UserID: "coach-2", Username: "luis"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, EncodeDashboardCSV(&buf, dashboard))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "metric,key,name,value", lines[0])
+	assert.Contains(t, lines, "refreshed_at,,,2026-10-22T21:30:00Z")
+	assert.Contains(t, lines, "active_members,,,40")
+	assert.Contains(t, lines, "average_rating,,,4.25")
+	assert.Contains(t, lines, `exercise_completed_sets,public:ex-1,"Squat, back",12`)
+	assert.Contains(t, lines, "trainer_average_rating,coach-2,luis,")
+}
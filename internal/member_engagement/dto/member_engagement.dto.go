@@ -0,0 +1,83 @@
+package dto
+
+import "time"
+
+// MemberEngagementDTO summarizes how a member follows their plan. Missed workouts are scheduled ones whose day
+// passed without being done; adherence is completed over completed, skipped and missed, and is unset before
+// any workout is due.
+type MemberEngagementDTO struct {
+	MemberID  string   `json:"member_id"`
+	Completed int      `json:"completed"`
+	Skipped   int      `json:"skipped"`
+	Missed    int      `json:"missed"`
+	Adherence *float64 `json:"adherence"`
+	// CurrentStreak counts workouts completed in a row, broken by a skip or a missed workout; week streaks
+	// count consecutive weeks with at least one completed workout
+	CurrentStreak     int            `json:"current_streak"`
+	LongestStreak     int            `json:"longest_streak"`
+	CurrentWeekStreak int            `json:"current_week_streak"`
+	LongestWeekStreak int            `json:"longest_week_streak"`
+	SkipReasons       map[string]int `json:"skip_reasons"`
+	LastCompletedAt   *time.Time     `json:"last_completed_at"`
+	LastActivityAt    *time.Time     `json:"last_activity_at"` // last start, pause, resume, completion or skip
+}
+
+// ChurnRiskDTO is an active member without a completed workout in the requested number of days
+type ChurnRiskDTO struct {
+	MemberID        string     `json:"member_id"`
+	Username        string     `json:"username"`
+	LastCompletedAt *time.Time `json:"last_completed_at"`
+	LastActivityAt  *time.Time `json:"last_activity_at"`
+	// DaysInactive counts from the last completed workout, or from when the member joined if they never completed one
+	DaysInactive  int       `json:"days_inactive"`
+	Completed     int       `json:"completed"`
+	Skipped       int       `json:"skipped"`
+	Missed        int       `json:"missed"`
+	Adherence     *float64  `json:"adherence"`
+	InactiveSince time.Time `json:"-"`
+}
+
+// RebuildResultDTO tells how many members had their engagement recomputed from their workout history
+type RebuildResultDTO struct {
+	Members int `json:"members"`
+}
+
+// Engagement is the stored running summary of a member, updated as each of their workouts changes status
+type Engagement struct {
+	MemberID          string
+	Completed         int
+	Skipped           int
+	CurrentStreak     int
+	LongestStreak     int
+	CurrentWeekStreak int
+	LongestWeekStreak int
+	// LastCompletedWeek is the Monday of the latest week with a completed workout, in the gym's timezone
+	LastCompletedWeek *time.Time
+	SkipReasons       map[string]int
+	LastCompletedAt   *time.Time
+	LastActivityAt    *time.Time
+}
+
+// Transition is a status change of one of a member's workouts
+type Transition struct {
+	MemberID   string
+	Transition string
+	At         time.Time
+	Reason     *string
+}
+
+// WorkoutHistory is a member workout as read to rebuild engagement from
+type WorkoutHistory struct {
+	MemberID  string
+	Status    string
+	StartedAt *time.Time
+	// EndedAt is when the workout was completed or skipped
+	EndedAt    *time.Time
+	SkipReason *string
+}
+
+// MissedWorkouts counts a member's scheduled workouts whose day passed; Latest is the day of the last one
+type MissedWorkouts struct {
+	Count  int
+	Latest *string
+}
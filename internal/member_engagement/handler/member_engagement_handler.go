@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type MemberEngagementHandler struct {
+	service interfaces.MemberEngagementService
+}
+
+func NewMemberEngagementHandler(service interfaces.MemberEngagementService) *MemberEngagementHandler {
+	return &MemberEngagementHandler{service: service}
+}
+
+func (h *MemberEngagementHandler) GetMemberEngagement(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	memberID := chi.URLParam(r, "memberID")
+	if memberID != middleware.GetUserID(r) && !canCoach(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Members can only see their own engagement", nil))
+		return
+	}
+	engagement, err := h.service.GetMemberEngagement(middleware.GetGymID(r), memberID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get member engagement")
+		return
+	}
+	response.WriteAPISuccess(w, "Member engagement retrieved successfully", engagement)
+}
+
+func (h *MemberEngagementHandler) ListChurnRisks(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	if !canCoach(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators and trainers can see members at churn risk", nil))
+		return
+	}
+	inactiveDays := 0
+	if value := r.URL.Query().Get("inactive_days"); value != "" {
+		var err error
+		if inactiveDays, err = strconv.Atoi(value); err != nil {
+			response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "inactive_days must be a number", err))
+			return
+		}
+	}
+	risks, err := h.service.ListChurnRisks(middleware.GetGymID(r), inactiveDays)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list members at churn risk")
+		return
+	}
+	response.WriteAPISuccess(w, "Members at churn risk retrieved successfully", risks)
+}
+
+func (h *MemberEngagementHandler) RebuildEngagement(w http.ResponseWriter, r *http.Request) {
+	if !middleware.IsGymAdmin(r) || middleware.GetGymID(r) == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can rebuild member engagement", nil))
+		return
+	}
+	result, err := h.service.RebuildEngagement(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to rebuild member engagement")
+		return
+	}
+	response.WriteAPISuccess(w, "Member engagement rebuilt successfully", result)
+}
+
+func requireGymUser(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Member engagement belongs to a gym", nil))
+	return false
+}
+
+func canCoach(r *http.Request) bool {
+	return middleware.IsGymAdmin(r) || middleware.GetUserRole(r) == string(user_enum.Trainer)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.MemberEngagementService
+	memberID     string
+	inactiveDays int
+	rebuilt      bool
+}
+
+func (m *mockService) GetMemberEngagement(gymID, memberID string) (*dto.MemberEngagementDTO, error) {
+	m.memberID = memberID
+	return &dto.MemberEngagementDTO{MemberID: memberID, SkipReasons: map[string]int{}}, nil
+}
+func (m *mockService) ListChurnRisks(gymID string, inactiveDays int) ([]*dto.ChurnRiskDTO, error) {
+	m.inactiveDays = inactiveDays
+	return []*dto.ChurnRiskDTO{}, nil
+}
+func (m *mockService) RebuildEngagement(gymID string) (*dto.RebuildResultDTO, error) {
+	m.rebuilt = true
+	return &dto.RebuildResultDTO{Members: 3}, nil
+}
+
+func serve(svc *mockService, method, target, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewMemberEngagementRouter(NewMemberEngagementHandler(svc)),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, method, target, "")
+}
+
+func TestMembersOnlySeeTheirOwnEngagement(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/member/member-1", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-1", svc.memberID)
+
+	w = serve(svc, http.MethodGet, "/member/member-2", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodGet, "/member/member-2", "coach-1", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-2", svc.memberID)
+}
+
+func TestChurnRiskIsForStaff(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/churn-risk?inactive_days=30", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodGet, "/churn-risk?inactive_days=30", "coach-1", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 30, svc.inactiveDays)
+
+	w = serve(svc, http.MethodGet, "/churn-risk?inactive_days=month", "coach-1", "trainer")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Only gym administrators rebuild
+	w = serve(svc, http.MethodPost, "/rebuild", "coach-1", "trainer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, svc.rebuilt)
+	w = serve(svc, http.MethodPost, "/rebuild", "owner-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, svc.rebuilt)
+}
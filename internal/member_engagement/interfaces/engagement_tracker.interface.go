@@ -0,0 +1,12 @@
+package interfaces
+
+import "database/sql"
+
+// EngagementTracker keeps the engagement summary of a member up to date as their workouts change status, in the
+// transaction that changes them
+type EngagementTracker interface {
+	// TrackTransition applies a transition of one of the member's workouts; reason is the skip reason, if any
+	TrackTransition(tx *sql.Tx, gymID, memberID, transition string, reason *string) error
+	// ForgetWorkout recomputes the member's summary from their remaining workouts once one of them is deleted
+	ForgetWorkout(tx *sql.Tx, gymID, memberID string) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "net/http"
+
+type MemberEngagementHandler interface {
+	GetMemberEngagement(w http.ResponseWriter, r *http.Request)
+	ListChurnRisks(w http.ResponseWriter, r *http.Request)
+	RebuildEngagement(w http.ResponseWriter, r *http.Request)
+}
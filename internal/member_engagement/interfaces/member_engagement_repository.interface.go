@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+)
+
+type MemberEngagementRepository interface {
+	// UpdateEngagement locks the member's summary, creating it when missing, and saves it after apply changed it
+	UpdateEngagement(tx *sql.Tx, gymID, memberID string, apply func(*dto.Engagement)) error
+	// RebuildEngagement locks the member's summary and replaces it with what rebuild makes of their workout history,
+	// removing it when rebuild returns nil
+	RebuildEngagement(tx *sql.Tx, gymID, memberID string, rebuild func([]dto.WorkoutHistory) *dto.Engagement) error
+	// FindEngagement gives the member's summary; sql.ErrNoRows when none of their workouts changed status yet
+	FindEngagement(gymID, memberID string) (*dto.Engagement, error)
+	// FindMissed counts the member's workouts still scheduled for a day before today
+	FindMissed(gymID, memberID, today string) (*dto.MissedWorkouts, error)
+	// FindChurnRisks lists active members whose last completed workout, or sign up if they have none, is
+	// before inactiveSince, longest inactive first, with their missed workouts before today
+	FindChurnRisks(gymID string, inactiveSince time.Time, today string) ([]*dto.ChurnRiskDTO, error)
+	// RebuildEngagements locks the gym's summaries and replaces them all with what rebuild makes of the workout
+	// history of the gym's members, in a single transaction
+	RebuildEngagements(gymID string, rebuild func([]dto.WorkoutHistory) []*dto.Engagement) error
+}
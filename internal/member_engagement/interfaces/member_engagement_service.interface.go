@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+
+type MemberEngagementService interface {
+	EngagementTracker
+	GetMemberEngagement(gymID, memberID string) (*dto.MemberEngagementDTO, error)
+	ListChurnRisks(gymID string, inactiveDays int) ([]*dto.ChurnRiskDTO, error)
+	RebuildEngagement(gymID string) (*dto.RebuildResultDTO, error)
+}
@@ -0,0 +1,18 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/handler"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/repository"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/router"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/service"
+)
+
+func NewMemberEngagementModule(db *sql.DB) http.Handler {
+	service := service.NewMemberEngagementService(repository.NewMemberEngagementRepository(db), gym_repository.NewGymRepository(db))
+	handler := handler.NewMemberEngagementHandler(service)
+	return router.NewMemberEngagementRouter(handler)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+	"github.com/lib/pq"
+)
+
+type MemberEngagementRepository struct {
+	db *sql.DB
+}
+
+func NewMemberEngagementRepository(db *sql.DB) *MemberEngagementRepository {
+	return &MemberEngagementRepository{db: db}
+}
+
+const engagementColumns = `member_id, completed_count, skipped_count, current_streak, longest_streak,
+	current_week_streak, longest_week_streak, last_completed_week, skip_reasons, last_completed_at, last_activity_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEngagement(row scanner) (*dto.Engagement, error) {
+	var engagement dto.Engagement
+	var reasons []byte
+	if err := row.Scan(&engagement.MemberID, &engagement.Completed, &engagement.Skipped, &engagement.CurrentStreak,
+		&engagement.LongestStreak, &engagement.CurrentWeekStreak, &engagement.LongestWeekStreak, &engagement.LastCompletedWeek,
+		&reasons, &engagement.LastCompletedAt, &engagement.LastActivityAt); err != nil {
+		return nil, err
+	}
+	engagement.SkipReasons = map[string]int{}
+	if err := json.Unmarshal(reasons, &engagement.SkipReasons); err != nil {
+		return nil, err
+	}
+	return &engagement, nil
+}
+
+func (r *MemberEngagementRepository) UpdateEngagement(tx *sql.Tx, gymID, memberID string, apply func(*dto.Engagement)) error {
+	table := pq.QuoteIdentifier(gymID) + ".member_engagement"
+	engagement, err := lockEngagement(tx, table, memberID)
+	if err != nil {
+		return err
+	}
+	apply(engagement)
+	return saveEngagement(tx, table, engagement)
+}
+
+// RebuildEngagement takes the summary lock before reading the history, so a transition committing meanwhile
+// applies on top of the rebuilt summary instead of being lost
+func (r *MemberEngagementRepository) RebuildEngagement(tx *sql.Tx, gymID, memberID string, rebuild func([]dto.WorkoutHistory) *dto.Engagement) error {
+	table := pq.QuoteIdentifier(gymID) + ".member_engagement"
+	if _, err := lockEngagement(tx, table, memberID); err != nil {
+		return err
+	}
+	history, err := findWorkoutHistory(tx, gymID, `AND w.member_id = $1`, memberID)
+	if err != nil {
+		return err
+	}
+	engagement := rebuild(history)
+	if engagement == nil {
+		_, err := tx.Exec(`DELETE FROM `+table+` WHERE member_id = $1`, memberID)
+		return err
+	}
+	return saveEngagement(tx, table, engagement)
+}
+
+// lockEngagement creates the member's summary when missing and locks it until the transaction ends
+func lockEngagement(tx *sql.Tx, table, memberID string) (*dto.Engagement, error) {
+	if _, err := tx.Exec(`INSERT INTO `+table+` (member_id) VALUES ($1) ON CONFLICT (member_id) DO NOTHING`, memberID); err != nil {
+		return nil, err
+	}
+	return scanEngagement(tx.QueryRow(`SELECT `+engagementColumns+` FROM `+table+` WHERE member_id = $1 FOR UPDATE`, memberID))
+}
+
+func saveEngagement(tx *sql.Tx, table string, engagement *dto.Engagement) error {
+	reasons, err := json.Marshal(engagement.SkipReasons)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO `+table+` (`+engagementColumns+`, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		ON CONFLICT (member_id) DO UPDATE SET
+			completed_count = EXCLUDED.completed_count,
+			skipped_count = EXCLUDED.skipped_count,
+			current_streak = EXCLUDED.current_streak,
+			longest_streak = EXCLUDED.longest_streak,
+			current_week_streak = EXCLUDED.current_week_streak,
+			longest_week_streak = EXCLUDED.longest_week_streak,
+			last_completed_week = EXCLUDED.last_completed_week,
+			skip_reasons = EXCLUDED.skip_reasons,
+			last_completed_at = EXCLUDED.last_completed_at,
+			last_activity_at = EXCLUDED.last_activity_at,
+			updated_at = NOW()`,
+		engagement.MemberID, engagement.Completed, engagement.Skipped, engagement.CurrentStreak, engagement.LongestStreak,
+		engagement.CurrentWeekStreak, engagement.LongestWeekStreak, engagement.LastCompletedWeek, string(reasons),
+		engagement.LastCompletedAt, engagement.LastActivityAt)
+	return err
+}
+
+func (r *MemberEngagementRepository) FindEngagement(gymID, memberID string) (*dto.Engagement, error) {
+	return scanEngagement(r.db.QueryRow(fmt.Sprintf(`SELECT `+engagementColumns+` FROM %s.member_engagement WHERE member_id = $1`,
+		pq.QuoteIdentifier(gymID)), memberID))
+}
+
+func (r *MemberEngagementRepository) FindMissed(gymID, memberID, today string) (*dto.MissedWorkouts, error) {
+	var missed dto.MissedWorkouts
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*), to_char(MAX(scheduled_date), 'YYYY-MM-DD')
+		FROM %s.custom_member_workout
+		WHERE member_id = $1 AND status = 'scheduled' AND scheduled_date < $2`, pq.QuoteIdentifier(gymID)), memberID, today).
+		Scan(&missed.Count, &missed.Latest)
+	if err != nil {
+		return nil, err
+	}
+	return &missed, nil
+}
+
+func (r *MemberEngagementRepository) FindChurnRisks(gymID string, inactiveSince time.Time, today string) ([]*dto.ChurnRiskDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT u.id, u.username, e.last_completed_at, e.last_activity_at,
+			COALESCE(e.completed_count, 0), COALESCE(e.skipped_count, 0), COALESCE(m.missed, 0),
+			COALESCE(e.last_completed_at, u.created_at)
+		FROM %[1]s.user u
+		LEFT JOIN %[1]s.member_engagement e ON e.member_id = u.id
+		LEFT JOIN (
+			SELECT member_id, COUNT(*) AS missed FROM %[1]s.custom_member_workout
+			WHERE status = 'scheduled' AND scheduled_date < $2 GROUP BY member_id
+		) m ON m.member_id = u.id
+		WHERE u.role = 'member' AND u.is_active AND COALESCE(e.last_completed_at, u.created_at) < $1
+		ORDER BY COALESCE(e.last_completed_at, u.created_at), u.username`, pq.QuoteIdentifier(gymID)), inactiveSince, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	risks := []*dto.ChurnRiskDTO{}
+	for rows.Next() {
+		var risk dto.ChurnRiskDTO
+		if err := rows.Scan(&risk.MemberID, &risk.Username, &risk.LastCompletedAt, &risk.LastActivityAt,
+			&risk.Completed, &risk.Skipped, &risk.Missed, &risk.InactiveSince); err != nil {
+			return nil, err
+		}
+		risks = append(risks, &risk)
+	}
+	return risks, rows.Err()
+}
+
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// findWorkoutHistory reads skip reasons from the skip event; workouts skipped before events were recorded
+// fall back to their last update for when they ended
+func findWorkoutHistory(q querier, gymID, filter string, args ...any) ([]dto.WorkoutHistory, error) {
+	rows, err := q.Query(fmt.Sprintf(`SELECT w.member_id, w.status, w.started_at,
+			CASE w.status WHEN 'completed' THEN w.completed_at WHEN 'skipped' THEN COALESCE(e.created_at, w.updated_at) END,
+			e.reason
+		FROM %[1]s.custom_member_workout w
+		LEFT JOIN LATERAL (
+			SELECT ev.created_at, ev.reason FROM %[1]s.custom_member_workout_event ev
+			WHERE ev.member_workout_id = w.id AND ev.transition = 'skip'
+			ORDER BY ev.created_at DESC LIMIT 1
+		) e ON w.status = 'skipped'
+		WHERE w.status IN ('in_progress', 'paused', 'completed', 'skipped') %[2]s
+		ORDER BY w.member_id`, pq.QuoteIdentifier(gymID), filter), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	history := []dto.WorkoutHistory{}
+	for rows.Next() {
+		var workout dto.WorkoutHistory
+		if err := rows.Scan(&workout.MemberID, &workout.Status, &workout.StartedAt, &workout.EndedAt, &workout.SkipReason); err != nil {
+			return nil, err
+		}
+		history = append(history, workout)
+	}
+	return history, rows.Err()
+}
+
+// RebuildEngagements locks the summary table before reading the history. Transitions wait on the lock when they
+// update a summary, so none commits between the read and the replacement and gets overwritten.
+func (r *MemberEngagementRepository) RebuildEngagements(gymID string, rebuild func([]dto.WorkoutHistory) []*dto.Engagement) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	table := pq.QuoteIdentifier(gymID) + ".member_engagement"
+	if _, err := tx.Exec(`LOCK TABLE ` + table + ` IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+	history, err := findWorkoutHistory(tx, gymID, "")
+	if err != nil {
+		return err
+	}
+	engagements := rebuild(history)
+	if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+		return err
+	}
+	for _, engagement := range engagements {
+		if err := saveEngagement(tx, table, engagement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var engagementRow = []string{"member_id", "completed_count", "skipped_count", "current_streak", "longest_streak",
+	"current_week_streak", "longest_week_streak", "last_completed_week", "skip_reasons", "last_completed_at", "last_activity_at"}
+
+func TestUpdateEngagementLocksTheSummary(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewMemberEngagementRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "gym-1".member_engagement \(member_id\) VALUES \(\$1\) ON CONFLICT \(member_id\) DO NOTHING`).
+		WithArgs("member-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "gym-1".member_engagement WHERE member_id = \$1 FOR UPDATE`).
+		WithArgs("member-1").
+		WillReturnRows(sqlmock.NewRows(engagementRow).AddRow("member-1", 2, 1, 0, 2, 1, 1, nil, []byte(`{"illness":1}`), nil, nil))
+	mock.ExpectExec(`ON CONFLICT \(member_id\) DO UPDATE SET`).
+		WithArgs("member-1", 3, 1, 1, 2, 1, 1, nil, `{"illness":1}`, nil, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	err = repo.UpdateEngagement(tx, "gym-1", "member-1", func(engagement *dto.Engagement) {
+		assert.Equal(t, map[string]int{"illness": 1}, engagement.SkipReasons)
+		engagement.Completed++
+		engagement.CurrentStreak++
+	})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRebuildEngagementLocksBeforeReadingHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewMemberEngagementRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "gym-1".member_engagement \(member_id\) VALUES \(\$1\) ON CONFLICT \(member_id\) DO NOTHING`).
+		WithArgs("member-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "gym-1".member_engagement WHERE member_id = \$1 FOR UPDATE`).
+		WithArgs("member-1").
+		WillReturnRows(sqlmock.NewRows(engagementRow).AddRow("member-1", 1, 0, 1, 1, 1, 1, nil, []byte(`{}`), nil, nil))
+	mock.ExpectQuery(`FROM "gym-1".custom_member_workout w .* AND w.member_id = \$1`).
+		WithArgs("member-1").
+		WillReturnRows(sqlmock.NewRows([]string{"member_id", "status", "started_at", "ended_at", "reason"}))
+	mock.ExpectExec(`DELETE FROM "gym-1".member_engagement WHERE member_id = \$1`).
+		WithArgs("member-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	err = repo.RebuildEngagement(tx, "gym-1", "member-1", func(history []dto.WorkoutHistory) *dto.Engagement {
+		assert.Empty(t, history)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRebuildEngagementsLocksTheTableBeforeReadingHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewMemberEngagementRepository(db)
+	completed := time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`LOCK TABLE "gym-1".member_engagement IN SHARE ROW EXCLUSIVE MODE`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "gym-1".custom_member_workout w`).
+		WillReturnRows(sqlmock.NewRows([]string{"member_id", "status", "started_at", "ended_at", "reason"}).
+			AddRow("member-1", "completed", nil, completed, nil))
+	mock.ExpectExec(`DELETE FROM "gym-1".member_engagement$`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`ON CONFLICT \(member_id\) DO UPDATE SET`).
+		WithArgs("member-1", 1, 0, 1, 1, 1, 1, nil, `{}`, completed, completed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.RebuildEngagements("gym-1", func(history []dto.WorkoutHistory) []*dto.Engagement {
+		require.Len(t, history, 1)
+		return []*dto.Engagement{{MemberID: "member-1", Completed: 1, CurrentStreak: 1, LongestStreak: 1, CurrentWeekStreak: 1,
+			LongestWeekStreak: 1, SkipReasons: map[string]int{}, LastCompletedAt: &completed, LastActivityAt: &completed}}
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindEngagementWithoutSummary(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewMemberEngagementRepository(db)
+
+	mock.ExpectQuery(`FROM "gym-1".member_engagement WHERE member_id = \$1`).WithArgs("member-1").WillReturnError(sql.ErrNoRows)
+	_, err := repo.FindEngagement("gym-1", "member-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	mock.ExpectQuery(`FROM "gym-1".custom_member_workout\s+WHERE member_id = \$1 AND status = 'scheduled' AND scheduled_date < \$2`).
+		WithArgs("member-1", "2026-10-22").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "latest"}).AddRow(0, nil))
+	missed, err := repo.FindMissed("gym-1", "member-1", "2026-10-22")
+	require.NoError(t, err)
+	assert.Equal(t, 0, missed.Count)
+	assert.Nil(t, missed.Latest)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindChurnRisksIncludesMembersWhoNeverTrained(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewMemberEngagementRepository(db)
+	since := time.Date(2026, 10, 8, 10, 0, 0, 0, time.UTC)
+	joined := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`LEFT JOIN "gym-1".member_engagement e ON e.member_id = u.id .* WHERE u.role = 'member' AND u.is_active AND COALESCE\(e.last_completed_at, u.created_at\) < \$1`).
+		WithArgs(since, "2026-10-22").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "last_completed_at", "last_activity_at", "completed", "skipped", "missed", "inactive_since"}).
+			AddRow("member-1", "alice", nil, nil, 0, 0, 2, joined))
+	risks, err := repo.FindChurnRisks("gym-1", since, "2026-10-22")
+	require.NoError(t, err)
+	require.Len(t, risks, 1)
+	assert.Equal(t, 2, risks[0].Missed)
+	assert.Equal(t, joined, risks[0].InactiveSince)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewMemberEngagementRouter(handler interfaces.MemberEngagementHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/member/{memberID}", handler.GetMemberEngagement) // GET /engagement/member/{memberID}
+	r.Get("/churn-risk", handler.ListChurnRisks)             // GET /engagement/churn-risk?inactive_days=
+	r.Post("/rebuild", handler.RebuildEngagement)            // POST /engagement/rebuild
+	return r
+}
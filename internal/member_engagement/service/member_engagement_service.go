@@ -0,0 +1,267 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	workout_enum "github.com/alejandro-albiol/athenai/internal/custom_member_workout/enum"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// Members are at churn risk after defaultInactiveDays without a completed workout unless asked otherwise
+	defaultInactiveDays = 14
+	maxInactiveDays     = 365
+)
+
+type MemberEngagementService struct {
+	repository interfaces.MemberEngagementRepository
+	gyms       gymIF.GymRepository
+	now        func() time.Time
+}
+
+func NewMemberEngagementService(repo interfaces.MemberEngagementRepository, gyms gymIF.GymRepository) *MemberEngagementService {
+	return &MemberEngagementService{repository: repo, gyms: gyms, now: time.Now}
+}
+
+// TrackTransition updates the member's summary with the transition alone, so reading engagement never goes
+// through their workout history
+func (s *MemberEngagementService) TrackTransition(tx *sql.Tx, gymID, memberID, transition string, reason *string) error {
+	loc, err := s.location(gymID)
+	if err != nil {
+		return err
+	}
+	event := dto.Transition{MemberID: memberID, Transition: transition, At: s.now(), Reason: reason}
+	if err := s.repository.UpdateEngagement(tx, gymID, memberID, func(engagement *dto.Engagement) {
+		Apply(engagement, event, loc)
+	}); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to update member engagement", err)
+	}
+	return nil
+}
+
+// ForgetWorkout replays the member's remaining workouts, since a streak cannot be taken back one workout at a time
+func (s *MemberEngagementService) ForgetWorkout(tx *sql.Tx, gymID, memberID string) error {
+	loc, err := s.location(gymID)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.RebuildEngagement(tx, gymID, memberID, func(history []dto.WorkoutHistory) *dto.Engagement {
+		if engagements := Replay(history, loc); len(engagements) > 0 {
+			return engagements[0]
+		}
+		return nil
+	}); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to update member engagement", err)
+	}
+	return nil
+}
+
+// GetMemberEngagement completes the stored summary with the workouts missed so far, which need no transition
+// to happen: a missed workout after the last completed one breaks the current streak, and a week streak ends
+// once a whole week passes without a completed workout
+func (s *MemberEngagementService) GetMemberEngagement(gymID, memberID string) (*dto.MemberEngagementDTO, error) {
+	loc, err := s.location(gymID)
+	if err != nil {
+		return nil, err
+	}
+	engagement, err := s.repository.FindEngagement(gymID, memberID)
+	if err == sql.ErrNoRows {
+		engagement, err = &dto.Engagement{MemberID: memberID, SkipReasons: map[string]int{}}, nil
+	}
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get member engagement", err)
+	}
+	today := civilDate(s.now().In(loc))
+	missed, err := s.repository.FindMissed(gymID, memberID, today.Format(dateLayout))
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to count missed workouts", err)
+	}
+
+	res := &dto.MemberEngagementDTO{
+		MemberID:          memberID,
+		Completed:         engagement.Completed,
+		Skipped:           engagement.Skipped,
+		Missed:            missed.Count,
+		Adherence:         adherence(engagement.Completed, engagement.Skipped, missed.Count),
+		CurrentStreak:     engagement.CurrentStreak,
+		LongestStreak:     engagement.LongestStreak,
+		CurrentWeekStreak: engagement.CurrentWeekStreak,
+		LongestWeekStreak: engagement.LongestWeekStreak,
+		SkipReasons:       engagement.SkipReasons,
+		LastCompletedAt:   engagement.LastCompletedAt,
+		LastActivityAt:    engagement.LastActivityAt,
+	}
+	if missed.Latest != nil && (engagement.LastCompletedAt == nil ||
+		*missed.Latest > civilDate(engagement.LastCompletedAt.In(loc)).Format(dateLayout)) {
+		res.CurrentStreak = 0
+	}
+	if engagement.LastCompletedWeek != nil && engagement.LastCompletedWeek.Before(weekStart(today).AddDate(0, 0, -7)) {
+		res.CurrentWeekStreak = 0
+	}
+	return res, nil
+}
+
+func (s *MemberEngagementService) ListChurnRisks(gymID string, inactiveDays int) ([]*dto.ChurnRiskDTO, error) {
+	if inactiveDays == 0 {
+		inactiveDays = defaultInactiveDays
+	}
+	if inactiveDays < 1 || inactiveDays > maxInactiveDays {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("inactive_days must be between 1 and %d", maxInactiveDays), nil)
+	}
+	loc, err := s.location(gymID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	risks, err := s.repository.FindChurnRisks(gymID, now.AddDate(0, 0, -inactiveDays), civilDate(now.In(loc)).Format(dateLayout))
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list members at churn risk", err)
+	}
+	for _, risk := range risks {
+		risk.DaysInactive = int(now.Sub(risk.InactiveSince).Hours() / 24)
+		risk.Adherence = adherence(risk.Completed, risk.Skipped, risk.Missed)
+	}
+	return risks, nil
+}
+
+// RebuildEngagement recomputes every summary of the gym from its workouts, replaying them in the order they
+// happened. Summaries are only kept by transitions and deletes, so this backfills them for earlier workouts.
+func (s *MemberEngagementService) RebuildEngagement(gymID string) (*dto.RebuildResultDTO, error) {
+	loc, err := s.location(gymID)
+	if err != nil {
+		return nil, err
+	}
+	members := 0
+	if err := s.repository.RebuildEngagements(gymID, func(history []dto.WorkoutHistory) []*dto.Engagement {
+		engagements := Replay(history, loc)
+		members = len(engagements)
+		return engagements
+	}); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to rebuild member engagement", err)
+	}
+	return &dto.RebuildResultDTO{Members: members}, nil
+}
+
+// Replay builds the summary of each member from their workouts, ordered by member
+func Replay(history []dto.WorkoutHistory, loc *time.Location) []*dto.Engagement {
+	byMember := map[string][]dto.Transition{}
+	members := []string{}
+	for _, workout := range history {
+		if _, ok := byMember[workout.MemberID]; !ok {
+			members = append(members, workout.MemberID)
+		}
+		events := byMember[workout.MemberID]
+		if workout.StartedAt != nil {
+			events = append(events, dto.Transition{Transition: string(workout_enum.TransitionStart), At: *workout.StartedAt})
+		}
+		if workout.EndedAt != nil {
+			switch workout_enum.CustomMemberWorkoutStatus(workout.Status) {
+			case workout_enum.StatusCompleted:
+				events = append(events, dto.Transition{Transition: string(workout_enum.TransitionComplete), At: *workout.EndedAt})
+			case workout_enum.StatusSkipped:
+				events = append(events, dto.Transition{Transition: string(workout_enum.TransitionSkip), At: *workout.EndedAt, Reason: workout.SkipReason})
+			}
+		}
+		byMember[workout.MemberID] = events
+	}
+	sort.Strings(members)
+
+	engagements := make([]*dto.Engagement, 0, len(members))
+	for _, memberID := range members {
+		events := byMember[memberID]
+		sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+		engagement := &dto.Engagement{MemberID: memberID, SkipReasons: map[string]int{}}
+		for _, event := range events {
+			Apply(engagement, event, loc)
+		}
+		engagements = append(engagements, engagement)
+	}
+	return engagements
+}
+
+// Apply adds one transition to a summary. Completing extends the streak and skipping resets it; starts, pauses
+// and resumes only count as activity. Cancelled workouts were called off, often by staff, so they are ignored.
+func Apply(engagement *dto.Engagement, event dto.Transition, loc *time.Location) {
+	if workout_enum.WorkoutTransition(event.Transition) == workout_enum.TransitionCancel {
+		return
+	}
+	at := event.At
+	if engagement.LastActivityAt == nil || at.After(*engagement.LastActivityAt) {
+		engagement.LastActivityAt = &at
+	}
+	switch workout_enum.WorkoutTransition(event.Transition) {
+	case workout_enum.TransitionComplete:
+		engagement.Completed++
+		engagement.CurrentStreak++
+		engagement.LongestStreak = max(engagement.LongestStreak, engagement.CurrentStreak)
+		if engagement.LastCompletedAt == nil || at.After(*engagement.LastCompletedAt) {
+			engagement.LastCompletedAt = &at
+		}
+
+		week := weekStart(civilDate(at.In(loc)))
+		last := engagement.LastCompletedWeek
+		switch {
+		case last == nil || week.After(last.AddDate(0, 0, 7)):
+			engagement.CurrentWeekStreak = 1
+		case week.Equal(last.AddDate(0, 0, 7)):
+			engagement.CurrentWeekStreak++
+		}
+		if last == nil || week.After(*last) {
+			engagement.LastCompletedWeek = &week
+		}
+		engagement.LongestWeekStreak = max(engagement.LongestWeekStreak, engagement.CurrentWeekStreak)
+	case workout_enum.TransitionSkip:
+		engagement.Skipped++
+		engagement.CurrentStreak = 0
+		if event.Reason != nil {
+			if engagement.SkipReasons == nil {
+				engagement.SkipReasons = map[string]int{}
+			}
+			engagement.SkipReasons[*event.Reason]++
+		}
+	}
+}
+
+func (s *MemberEngagementService) location(gymID string) (*time.Location, error) {
+	gym, err := s.gyms.GetGymByID(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym", err)
+	}
+	timezone := gym.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Invalid gym timezone", err)
+	}
+	return loc, nil
+}
+
+// adherence is the share of due workouts that were done, unset when none is due yet
+func adherence(completed, skipped, missed int) *float64 {
+	due := completed + skipped + missed
+	if due == 0 {
+		return nil
+	}
+	value := math.Round(float64(completed)/float64(due)*100) / 100
+	return &value
+}
+
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// civilDate keeps the calendar day of a time, as a UTC midnight so days can be compared and added safely
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	gymDTO "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/member_engagement/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	engagement    *dto.Engagement
+	missed        dto.MissedWorkouts
+	missedToday   string
+	risks         []*dto.ChurnRiskDTO
+	inactiveSince time.Time
+	history       []dto.WorkoutHistory
+	replaced      []*dto.Engagement
+}
+
+func (m *mockRepo) UpdateEngagement(tx *sql.Tx, gymID, memberID string, apply func(*dto.Engagement)) error {
+	if m.engagement == nil {
+		m.engagement = &dto.Engagement{MemberID: memberID, SkipReasons: map[string]int{}}
+	}
+	apply(m.engagement)
+	return nil
+}
+func (m *mockRepo) RebuildEngagement(tx *sql.Tx, gymID, memberID string, rebuild func([]dto.WorkoutHistory) *dto.Engagement) error {
+	m.engagement = rebuild(m.history)
+	return nil
+}
+func (m *mockRepo) FindEngagement(gymID, memberID string) (*dto.Engagement, error) {
+	if m.engagement == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.engagement, nil
+}
+func (m *mockRepo) FindMissed(gymID, memberID, today string) (*dto.MissedWorkouts, error) {
+	m.missedToday = today
+	return &m.missed, nil
+}
+func (m *mockRepo) FindChurnRisks(gymID string, inactiveSince time.Time, today string) ([]*dto.ChurnRiskDTO, error) {
+	m.inactiveSince = inactiveSince
+	return m.risks, nil
+}
+func (m *mockRepo) RebuildEngagements(gymID string, rebuild func([]dto.WorkoutHistory) []*dto.Engagement) error {
+	m.replaced = rebuild(m.history)
+	return nil
+}
+
+type mockGyms struct {
+	gymIF.GymRepository
+}
+
+func (m *mockGyms) GetGymByID(id string) (*gymDTO.GymResponseDTO, error) {
+	return &gymDTO.GymResponseDTO{ID: id, Timezone: "Europe/Madrid"}, nil
+}
+
+var now = time.Date(2026, 10, 22, 10, 0, 0, 0, time.UTC)
+
+func newService(repo *mockRepo) *MemberEngagementService {
+	s := NewMemberEngagementService(repo, &mockGyms{})
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func at(month time.Month, day, hour, minute int) *time.Time {
+	return ptr(time.Date(2026, month, day, hour, minute, 0, 0, time.UTC))
+}
+
+func TestRebuildReplaysWorkoutsInOrder(t *testing.T) {
+	repo := &mockRepo{history: []dto.WorkoutHistory{
+		{MemberID: "member-1", Status: "completed", StartedAt: at(10, 20, 17, 0), EndedAt: at(10, 20, 18, 0)},
+		{MemberID: "member-1", Status: "completed", StartedAt: at(9, 29, 17, 0), EndedAt: at(9, 29, 18, 0)},
+		{MemberID: "member-1", Status: "completed", StartedAt: at(10, 1, 17, 0), EndedAt: at(10, 1, 18, 0)},
+		{MemberID: "member-1", Status: "skipped", EndedAt: at(10, 5, 8, 0), SkipReason: ptr("illness")},
+		{MemberID: "member-1", Status: "completed", StartedAt: at(10, 7, 17, 0), EndedAt: at(10, 7, 18, 0)},
+		// Sunday night in UTC is already Monday in Madrid, so this starts a new week of the streak
+		{MemberID: "member-1", Status: "completed", StartedAt: at(10, 11, 21, 30), EndedAt: at(10, 11, 22, 30)},
+		{MemberID: "member-2", Status: "in_progress", StartedAt: at(10, 21, 9, 0)},
+	}}
+	result, err := newService(repo).RebuildEngagement("gym-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Members)
+	require.Len(t, repo.replaced, 2)
+
+	first := repo.replaced[0]
+	assert.Equal(t, "member-1", first.MemberID)
+	assert.Equal(t, 5, first.Completed)
+	assert.Equal(t, 1, first.Skipped)
+	assert.Equal(t, map[string]int{"illness": 1}, first.SkipReasons)
+	assert.Equal(t, 3, first.CurrentStreak) // the skip broke the first two
+	assert.Equal(t, 3, first.LongestStreak)
+	assert.Equal(t, 4, first.CurrentWeekStreak)
+	assert.Equal(t, 4, first.LongestWeekStreak)
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), *first.LastCompletedWeek)
+	assert.Equal(t, *at(10, 20, 18, 0), *first.LastCompletedAt)
+
+	second := repo.replaced[1]
+	assert.Equal(t, 0, second.Completed)
+	assert.Nil(t, second.LastCompletedAt)
+	assert.Equal(t, *at(10, 21, 9, 0), *second.LastActivityAt)
+}
+
+func TestWeekStreakRestartsAfterAnEmptyWeek(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	engagement := &dto.Engagement{}
+	Apply(engagement, dto.Transition{Transition: "complete", At: *at(10, 1, 18, 0)}, madrid)
+	Apply(engagement, dto.Transition{Transition: "complete", At: *at(10, 6, 18, 0)}, madrid)
+	Apply(engagement, dto.Transition{Transition: "cancel", At: *at(10, 8, 18, 0)}, madrid)
+	Apply(engagement, dto.Transition{Transition: "complete", At: *at(10, 20, 18, 0)}, madrid)
+
+	assert.Equal(t, 1, engagement.CurrentWeekStreak)
+	assert.Equal(t, 2, engagement.LongestWeekStreak)
+	assert.Equal(t, 3, engagement.CurrentStreak) // cancelled workouts do not break streaks
+	assert.Equal(t, *at(10, 20, 18, 0), *engagement.LastActivityAt)
+}
+
+func TestTrackTransitionUpdatesTheSummary(t *testing.T) {
+	repo := &mockRepo{}
+	s := newService(repo)
+	require.NoError(t, s.TrackTransition(nil, "gym-1", "member-1", "skip", ptr("travel")))
+	require.NoError(t, s.TrackTransition(nil, "gym-1", "member-1", "start", nil))
+
+	assert.Equal(t, 1, repo.engagement.Skipped)
+	assert.Equal(t, map[string]int{"travel": 1}, repo.engagement.SkipReasons)
+	assert.Equal(t, now, *repo.engagement.LastActivityAt)
+	assert.Nil(t, repo.engagement.LastCompletedAt)
+}
+
+func TestForgetWorkoutReplaysTheRemainingWorkouts(t *testing.T) {
+	repo := &mockRepo{
+		engagement: &dto.Engagement{MemberID: "member-1", Completed: 2, Skipped: 1, SkipReasons: map[string]int{"travel": 1}},
+		history: []dto.WorkoutHistory{
+			{MemberID: "member-1", Status: "completed", StartedAt: at(10, 20, 17, 0), EndedAt: at(10, 20, 18, 0)},
+			{MemberID: "member-1", Status: "completed", StartedAt: at(10, 13, 17, 0), EndedAt: at(10, 13, 18, 0)},
+		},
+	}
+	s := newService(repo)
+	require.NoError(t, s.ForgetWorkout(nil, "gym-1", "member-1"))
+	assert.Equal(t, 2, repo.engagement.CurrentStreak) // the deleted skip no longer breaks it
+	assert.Equal(t, 0, repo.engagement.Skipped)
+	assert.Empty(t, repo.engagement.SkipReasons)
+
+	repo.history = nil
+	require.NoError(t, s.ForgetWorkout(nil, "gym-1", "member-1"))
+	assert.Nil(t, repo.engagement)
+}
+
+func TestMissedWorkoutsBreakTheCurrentStreak(t *testing.T) {
+	repo := &mockRepo{
+		engagement: &dto.Engagement{
+			MemberID: "member-1", Completed: 5, Skipped: 1, CurrentStreak: 3, LongestStreak: 3,
+			CurrentWeekStreak: 4, LongestWeekStreak: 4, LastCompletedWeek: ptr(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)),
+			LastCompletedAt: at(10, 20, 18, 0), SkipReasons: map[string]int{"illness": 1},
+		},
+		missed: dto.MissedWorkouts{Count: 1, Latest: ptr("2026-10-21")},
+	}
+	engagement, err := newService(repo).GetMemberEngagement("gym-1", "member-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-22", repo.missedToday)
+	assert.Equal(t, 1, engagement.Missed)
+	assert.Equal(t, 0.71, *engagement.Adherence) // 5 of 7 due
+	assert.Equal(t, 0, engagement.CurrentStreak)
+	assert.Equal(t, 3, engagement.LongestStreak)
+	assert.Equal(t, 4, engagement.CurrentWeekStreak)
+
+	// A missed workout before the last completed one no longer matters, but a whole week without training does
+	repo.missed.Latest = ptr("2026-10-19")
+	repo.engagement.LastCompletedWeek = ptr(time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC))
+	engagement, err = newService(repo).GetMemberEngagement("gym-1", "member-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, engagement.CurrentStreak)
+	assert.Equal(t, 0, engagement.CurrentWeekStreak)
+}
+
+func TestMembersWithoutWorkoutsHaveNoAdherence(t *testing.T) {
+	engagement, err := newService(&mockRepo{}).GetMemberEngagement("gym-1", "member-1")
+	require.NoError(t, err)
+	assert.Nil(t, engagement.Adherence)
+	assert.Equal(t, map[string]int{}, engagement.SkipReasons)
+}
+
+func TestListChurnRisks(t *testing.T) {
+	repo := &mockRepo{risks: []*dto.ChurnRiskDTO{
+		{MemberID: "member-1", Completed: 3, Skipped: 1, InactiveSince: now.Add(-20*24*time.Hour - time.Hour)},
+	}}
+	s := newService(repo)
+	risks, err := s.ListChurnRisks("gym-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -14), repo.inactiveSince)
+	assert.Equal(t, 20, risks[0].DaysInactive)
+	assert.Equal(t, 0.75, *risks[0].Adherence)
+
+	_, err = s.ListChurnRisks("gym-1", 400)
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+}
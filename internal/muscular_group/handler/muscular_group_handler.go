@@ -6,19 +6,24 @@ import (
 
 	"github.com/alejandro-albiol/athenai/internal/muscular_group/dto"
 	"github.com/alejandro-albiol/athenai/internal/muscular_group/interfaces"
+	translation_enum "github.com/alejandro-albiol/athenai/internal/translation/enum"
+	translationIF "github.com/alejandro-albiol/athenai/internal/translation/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 	"github.com/go-chi/chi/v5"
 )
 
 type MuscularGroupHandler struct {
-	service interfaces.MuscularGroupService
+	service   interfaces.MuscularGroupService
+	localizer translationIF.Localizer
 }
 
-func NewMuscularGroupHandler(service interfaces.MuscularGroupService) *MuscularGroupHandler {
+func NewMuscularGroupHandler(service interfaces.MuscularGroupService, localizer translationIF.Localizer) *MuscularGroupHandler {
 	return &MuscularGroupHandler{
-		service: service,
+		service:   service,
+		localizer: localizer,
 	}
 }
 
@@ -54,6 +59,9 @@ func (h *MuscularGroupHandler) GetMuscularGroup(w http.ResponseWriter, r *http.R
 	}
 
 	muscularGroup, err := h.service.GetMuscularGroupByID(id)
+	if err == nil {
+		err = h.localize(w, r, []*dto.MuscularGroupResponseDTO{muscularGroup})
+	}
 	if err != nil {
 		response.WriteAPIError(w, err.(*apierror.APIError))
 		return
@@ -64,6 +72,9 @@ func (h *MuscularGroupHandler) GetMuscularGroup(w http.ResponseWriter, r *http.R
 
 func (h *MuscularGroupHandler) ListMuscularGroups(w http.ResponseWriter, r *http.Request) {
 	muscularGroups, err := h.service.GetAllMuscularGroups()
+	if err == nil {
+		err = h.localize(w, r, muscularGroups)
+	}
 	if err != nil {
 		response.WriteAPIError(w, err.(*apierror.APIError))
 		return
@@ -121,3 +132,25 @@ func (h *MuscularGroupHandler) DeleteMuscularGroup(w http.ResponseWriter, r *htt
 
 	response.WriteAPISuccess(w, "Muscular group deleted successfully", nil)
 }
+
+// localize translates muscular group names and descriptions into the caller's language
+func (h *MuscularGroupHandler) localize(w http.ResponseWriter, r *http.Request, items []*dto.MuscularGroupResponseDTO) error {
+	if h.localizer == nil {
+		return nil
+	}
+	locale := h.localizer.ResolveLocale(middleware.GetGymID(r), middleware.GetUserID(r), r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", string(locale))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	translations, err := h.localizer.Lookup(locale, translation_enum.MuscularGroup, ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		translations.Apply(item.ID, "name", &item.Name)
+		translations.Apply(item.ID, "description", &item.Description)
+	}
+	return nil
+}
@@ -49,7 +49,7 @@ func TestCreateMuscularGroupHandler(t *testing.T) {
 			return &id, nil
 		},
 	}
-	h := handler.NewMuscularGroupHandler(service)
+	h := handler.NewMuscularGroupHandler(service, nil)
 	body, _ := json.Marshal(dto.CreateMuscularGroupDTO{Name: "Chest"})
 	req := httptest.NewRequest(http.MethodPost, "/muscular-groups", bytes.NewReader(body))
 	w := httptest.NewRecorder()
@@ -79,7 +79,7 @@ func TestGetMuscularGroupHandler(t *testing.T) {
 			return &dto.MuscularGroupResponseDTO{ID: id, Name: "Chest"}, nil
 		},
 	}
-	h := handler.NewMuscularGroupHandler(service)
+	h := handler.NewMuscularGroupHandler(service, nil)
 	r := chi.NewRouter()
 	r.Get("/muscular-groups/{id}", h.GetMuscularGroup)
 
@@ -107,7 +107,7 @@ func TestListMuscularGroupsHandler(t *testing.T) {
 			return []*dto.MuscularGroupResponseDTO{{ID: "1", Name: "Chest"}}, nil
 		},
 	}
-	h := handler.NewMuscularGroupHandler(service)
+	h := handler.NewMuscularGroupHandler(service, nil)
 	req := httptest.NewRequest(http.MethodGet, "/muscular-groups", nil)
 	w := httptest.NewRecorder()
 	h.ListMuscularGroups(w, req)
@@ -138,7 +138,7 @@ func TestUpdateMuscularGroupHandler(t *testing.T) {
 			return &dto.MuscularGroupResponseDTO{ID: id, Name: "Updated"}, nil
 		},
 	}
-	h := handler.NewMuscularGroupHandler(service)
+	h := handler.NewMuscularGroupHandler(service, nil)
 	r := chi.NewRouter()
 	r.Put("/muscular-groups/{id}", h.UpdateMuscularGroup)
 
@@ -198,7 +198,7 @@ func TestDeleteMuscularGroupHandler(t *testing.T) {
 			return nil
 		},
 	}
-	h := handler.NewMuscularGroupHandler(service)
+	h := handler.NewMuscularGroupHandler(service, nil)
 	r := chi.NewRouter()
 	r.Delete("/muscular-groups/{id}", h.DeleteMuscularGroup)
 
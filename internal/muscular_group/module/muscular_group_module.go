@@ -8,11 +8,12 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/muscular_group/repository"
 	"github.com/alejandro-albiol/athenai/internal/muscular_group/router"
 	"github.com/alejandro-albiol/athenai/internal/muscular_group/service"
+	translationModule "github.com/alejandro-albiol/athenai/internal/translation/module"
 )
 
 func NewMuscularGroupModule(db *sql.DB) http.Handler {
 	repo := repository.NewMuscularGroupRepository(db)
 	service := service.NewMuscularGroupService(repo)
-	handler := handler.NewMuscularGroupHandler(service)
+	handler := handler.NewMuscularGroupHandler(service, translationModule.NewTranslationService(db))
 	return router.NewMuscularGroupRouter(handler)
 }
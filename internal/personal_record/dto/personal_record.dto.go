@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// PersonalRecordDTO is a record a member set for an exercise. PreviousValue is the record it beat; the first
+// value logged for an exercise is kept as a baseline without one, and is not flagged as new.
+type PersonalRecordDTO struct {
+	ID              string    `json:"id"`
+	MemberID        string    `json:"member_id"`
+	ExerciseSource  string    `json:"exercise_source"` // public or gym
+	ExerciseID      string    `json:"exercise_id"`
+	ExerciseName    string    `json:"exercise_name,omitempty"`
+	RecordType      string    `json:"record_type"`
+	Value           float64   `json:"value"`
+	PreviousValue   *float64  `json:"previous_value,omitempty"`
+	WeightKg        *float64  `json:"weight_kg,omitempty"`
+	Reps            *int      `json:"reps,omitempty"`
+	MemberWorkoutID string    `json:"member_workout_id"`
+	SetLogID        *string   `json:"set_log_id,omitempty"` // not set for max_volume, which sums the workout's sets
+	AchievedAt      time.Time `json:"achieved_at"`
+}
+
+// ExerciseRecordsDTO gathers the current records of a member for one exercise
+type ExerciseRecordsDTO struct {
+	ExerciseSource string             `json:"exercise_source"`
+	ExerciseID     string             `json:"exercise_id"`
+	ExerciseName   string             `json:"exercise_name"`
+	MaxWeight      *PersonalRecordDTO `json:"max_weight,omitempty"`
+	// Estimated1RM is estimated with the Epley formula; Estimated1RMBrzycki gives the Brzycki estimate of the same set
+	Estimated1RM        *PersonalRecordDTO   `json:"estimated_1rm,omitempty"`
+	Estimated1RMBrzycki *float64             `json:"estimated_1rm_brzycki,omitempty"`
+	MaxVolume           *PersonalRecordDTO   `json:"max_volume,omitempty"`
+	RepsAtWeight        []*PersonalRecordDTO `json:"reps_at_weight"` // max_reps records, heaviest weight first
+}
+
+// LoggedSet is a completed set of a member, with the exercise and workout it belongs to
+type LoggedSet struct {
+	SetLogID        string
+	MemberWorkoutID string
+	ExerciseSource  string
+	ExerciseID      string
+	Reps            *int
+	WeightKg        *float64
+	LoggedAt        time.Time
+	// WorkoutCompletedAt is set once the workout is completed, when its volume counts
+	WorkoutCompletedAt *time.Time
+}
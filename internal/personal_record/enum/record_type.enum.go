@@ -0,0 +1,19 @@
+package enum
+
+// RecordType is what a personal record measures
+type RecordType string
+
+const (
+	MaxWeight    RecordType = "max_weight"    // heaviest weight lifted for at least one rep
+	MaxReps      RecordType = "max_reps"      // most reps done at a given weight
+	Estimated1RM RecordType = "estimated_1rm" // best one-rep max estimated from a set
+	MaxVolume    RecordType = "max_volume"    // most weight x reps done in one completed workout
+)
+
+func (t RecordType) IsValid() bool {
+	switch t {
+	case MaxWeight, MaxReps, Estimated1RM, MaxVolume:
+		return true
+	}
+	return false
+}
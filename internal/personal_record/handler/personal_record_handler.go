@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PersonalRecordHandler struct {
+	service interfaces.PersonalRecordService
+}
+
+func NewPersonalRecordHandler(service interfaces.PersonalRecordService) *PersonalRecordHandler {
+	return &PersonalRecordHandler{service: service}
+}
+
+func (h *PersonalRecordHandler) ListUserRecords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireRecordAccess(w, r)
+	if !ok {
+		return
+	}
+	records, err := h.service.GetMemberRecords(middleware.GetGymID(r), userID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get personal records")
+		return
+	}
+	response.WriteAPISuccess(w, "Personal records retrieved successfully", records)
+}
+
+func (h *PersonalRecordHandler) GetRecordHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireRecordAccess(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	records, err := h.service.GetRecordHistory(middleware.GetGymID(r), userID, query.Get("exercise_id"), query.Get("record_type"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get personal record history")
+		return
+	}
+	response.WriteAPISuccess(w, "Personal record history retrieved successfully", records)
+}
+
+// requireRecordAccess gives the user of the path when the caller may see their records: members see their own,
+// gym administrators and trainers anyone's in the gym
+func requireRecordAccess(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if middleware.GetGymID(r) == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Personal records belong to a gym user", nil))
+		return "", false
+	}
+	userID := chi.URLParam(r, "id")
+	if userID != middleware.GetUserID(r) && !middleware.IsGymAdmin(r) && middleware.GetUserRole(r) != string(user_enum.Trainer) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: You can only see your own personal records", nil))
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.PersonalRecordService
+	memberID string
+	history  []string
+}
+
+func (m *mockService) GetMemberRecords(gymID, memberID string) ([]*dto.ExerciseRecordsDTO, error) {
+	m.memberID = memberID
+	return []*dto.ExerciseRecordsDTO{{ExerciseID: "squat", ExerciseName: "Squat", RepsAtWeight: []*dto.PersonalRecordDTO{}}}, nil
+}
+func (m *mockService) GetRecordHistory(gymID, memberID, exerciseID, recordType string) ([]*dto.PersonalRecordDTO, error) {
+	m.history = []string{memberID, exerciseID, recordType}
+	return []*dto.PersonalRecordDTO{}, nil
+}
+
+// serve routes the request the way the user router mounts records
+func serve(svc *mockService, target, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(testutil.Mount("/{id}/records", router.NewPersonalRecordRouter(NewPersonalRecordHandler(svc))),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, http.MethodGet, target, "")
+}
+
+func TestMembersOnlySeeTheirOwnRecords(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, "/member-1/records", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-1", svc.memberID)
+	assert.Contains(t, w.Body.String(), `"exercise_name":"Squat"`)
+
+	w = serve(svc, "/member-2/records", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, "/member-2/records", "coach-1", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-2", svc.memberID)
+}
+
+func TestRecordHistoryReadsTheQuery(t *testing.T) {
+	svc := &mockService{}
+	w := serve(svc, "/member-1/records/history?exercise_id=squat&record_type=estimated_1rm", "admin-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"member-1", "squat", "estimated_1rm"}, svc.history)
+}
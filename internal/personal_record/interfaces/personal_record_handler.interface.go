@@ -0,0 +1,8 @@
+package interfaces
+
+import "net/http"
+
+type PersonalRecordHandler interface {
+	ListUserRecords(w http.ResponseWriter, r *http.Request)
+	GetRecordHistory(w http.ResponseWriter, r *http.Request)
+}
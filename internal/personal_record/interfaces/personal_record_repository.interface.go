@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+)
+
+// PersonalRecordRepository reads and replaces records inside the transaction that changed the logged sets,
+// so the sets and the records computed from them commit together
+type PersonalRecordRepository interface {
+	// FindWorkoutExercises gives the member of a member workout and the exercise IDs of its workout instance;
+	// sql.ErrNoRows when there is no such workout
+	FindWorkoutExercises(tx *sql.Tx, gymID, memberWorkoutID string) (string, []string, error)
+	// LockMemberRecords holds the member's records until tx ends
+	LockMemberRecords(tx *sql.Tx, gymID, memberID string) error
+	// FindLoggedSets lists the completed sets the member logged for the exercises in workouts that were not
+	// skipped or cancelled, in the order they were done
+	FindLoggedSets(tx *sql.Tx, gymID, memberID string, exerciseIDs []string) ([]dto.LoggedSet, error)
+	// ReplaceRecords replaces the member's records of the exercises
+	ReplaceRecords(tx *sql.Tx, gymID, memberID string, exerciseIDs []string, records []*dto.PersonalRecordDTO) error
+
+	// FindCurrentByMember lists the latest record of each kind, and of each weight for max_reps, per exercise
+	FindCurrentByMember(gymID, memberID string) ([]*dto.PersonalRecordDTO, error)
+	// FindHistory lists the records of an exercise oldest first, of one kind unless recordType is empty
+	FindHistory(gymID, memberID, exerciseID, recordType string) ([]*dto.PersonalRecordDTO, error)
+	// FindNewByWorkout lists the records a member workout beat
+	FindNewByWorkout(gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+
+type PersonalRecordService interface {
+	RecordTracker
+	GetMemberRecords(gymID, memberID string) ([]*dto.ExerciseRecordsDTO, error)
+	GetRecordHistory(gymID, memberID, exerciseID, recordType string) ([]*dto.PersonalRecordDTO, error)
+}
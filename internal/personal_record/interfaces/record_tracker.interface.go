@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+)
+
+// RecordTracker keeps the personal records of a member up to date as the sets of their workouts are logged
+type RecordTracker interface {
+	// UpdateWorkoutRecords recomputes the member's records for the exercises of the workout inside tx, the
+	// transaction that changed its sets or status, and returns the records the workout beat
+	UpdateWorkoutRecords(tx *sql.Tx, gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error)
+	// GetWorkoutRecords returns the records the workout beat
+	GetWorkoutRecords(gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error)
+}
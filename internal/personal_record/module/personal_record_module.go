@@ -0,0 +1,18 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/handler"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/repository"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/router"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/service"
+)
+
+// NewPersonalRecordModule returns the records router, mounted by the user module under /user/{id}/records
+func NewPersonalRecordModule(db *sql.DB) http.Handler {
+	service := service.NewPersonalRecordService(repository.NewPersonalRecordRepository(db))
+	handler := handler.NewPersonalRecordHandler(service)
+	return router.NewPersonalRecordRouter(handler)
+}
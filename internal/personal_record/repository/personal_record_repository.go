@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	"github.com/lib/pq"
+)
+
+type PersonalRecordRepository struct {
+	db *sql.DB
+}
+
+func NewPersonalRecordRepository(db *sql.DB) *PersonalRecordRepository {
+	return &PersonalRecordRepository{db: db}
+}
+
+func (r *PersonalRecordRepository) FindWorkoutExercises(tx *sql.Tx, gymID, memberWorkoutID string) (string, []string, error) {
+	var memberID string
+	var exerciseIDs []string
+	err := tx.QueryRow(fmt.Sprintf(`SELECT w.member_id, ARRAY(
+			SELECT DISTINCT COALESCE(e.public_exercise_id, e.gym_exercise_id) FROM %[1]s.custom_workout_exercise e
+			WHERE e.workout_instance_id = w.workout_instance_id AND COALESCE(e.public_exercise_id, e.gym_exercise_id) IS NOT NULL
+		)
+		FROM %[1]s.custom_member_workout w WHERE w.id = $1`, pq.QuoteIdentifier(gymID)), memberWorkoutID).
+		Scan(&memberID, pq.Array(&exerciseIDs))
+	if err != nil {
+		return "", nil, err
+	}
+	return memberID, exerciseIDs, nil
+}
+
+// LockMemberRecords serializes record updates of the member until tx ends, so each one reads the sets
+// committed by the previous one
+func (r *PersonalRecordRepository) LockMemberRecords(tx *sql.Tx, gymID, memberID string) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1 || '.personal_record.' || $2))`, gymID, memberID)
+	return err
+}
+
+func (r *PersonalRecordRepository) FindLoggedSets(tx *sql.Tx, gymID, memberID string, exerciseIDs []string) ([]dto.LoggedSet, error) {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT s.id, s.member_workout_id, e.exercise_source,
+			COALESCE(e.public_exercise_id, e.gym_exercise_id), s.reps, s.weight_kg, s.created_at,
+			CASE WHEN w.status = 'completed' THEN w.completed_at END
+		FROM %[1]s.custom_member_workout_set_log s
+		JOIN %[1]s.custom_member_workout w ON w.id = s.member_workout_id
+		JOIN %[1]s.custom_workout_exercise e ON e.id = s.workout_exercise_id
+		WHERE w.member_id = $1 AND COALESCE(e.public_exercise_id, e.gym_exercise_id) = ANY($2)
+			AND s.completed AND w.status NOT IN ('skipped', 'cancelled')
+		ORDER BY COALESCE(w.started_at, w.created_at), w.id, s.created_at, s.set_number`, pq.QuoteIdentifier(gymID)),
+		memberID, pq.Array(exerciseIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sets := []dto.LoggedSet{}
+	for rows.Next() {
+		var set dto.LoggedSet
+		if err := rows.Scan(&set.SetLogID, &set.MemberWorkoutID, &set.ExerciseSource, &set.ExerciseID, &set.Reps,
+			&set.WeightKg, &set.LoggedAt, &set.WorkoutCompletedAt); err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	return sets, rows.Err()
+}
+
+func (r *PersonalRecordRepository) ReplaceRecords(tx *sql.Tx, gymID, memberID string, exerciseIDs []string, records []*dto.PersonalRecordDTO) error {
+	table := pq.QuoteIdentifier(gymID) + ".personal_record"
+	if _, err := tx.Exec(`DELETE FROM `+table+` WHERE member_id = $1 AND exercise_id = ANY($2)`,
+		memberID, pq.Array(exerciseIDs)); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := tx.QueryRow(`INSERT INTO `+table+` (member_id, exercise_source, exercise_id, record_type, value,
+				previous_value, weight_kg, reps, member_workout_id, set_log_id, achieved_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+			memberID, record.ExerciseSource, record.ExerciseID, record.RecordType, record.Value, record.PreviousValue,
+			record.WeightKg, record.Reps, record.MemberWorkoutID, record.SetLogID, record.AchievedAt).Scan(&record.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	recordColumns = `p.id, p.member_id, p.exercise_source, p.exercise_id, COALESCE(pe.name, ce.name, ''), p.record_type,
+	p.value, p.previous_value, p.weight_kg, p.reps, p.member_workout_id, p.set_log_id, p.achieved_at`
+	recordTables = `FROM %[1]s.personal_record p
+	LEFT JOIN public.exercise pe ON p.exercise_source = 'public' AND pe.id = p.exercise_id
+	LEFT JOIN %[1]s.custom_exercise ce ON p.exercise_source = 'gym' AND ce.id = p.exercise_id`
+	selectRecords = `SELECT ` + recordColumns + ` ` + recordTables
+)
+
+// maxRepsWeight keys max_reps records by their weight, as each weight has its own record
+const maxRepsWeight = `CASE WHEN p.record_type = 'max_reps' THEN p.weight_kg END`
+
+func (r *PersonalRecordRepository) FindCurrentByMember(gymID, memberID string) ([]*dto.PersonalRecordDTO, error) {
+	// Records only ever grow, so the latest of each kind is the current one
+	return r.queryRecords(fmt.Sprintf(`SELECT DISTINCT ON (p.exercise_id, p.record_type, `+maxRepsWeight+`) `+recordColumns+`
+		`+recordTables+` WHERE p.member_id = $1
+		ORDER BY p.exercise_id, p.record_type, `+maxRepsWeight+`, p.achieved_at DESC, p.value DESC`,
+		pq.QuoteIdentifier(gymID)), memberID)
+}
+
+func (r *PersonalRecordRepository) FindHistory(gymID, memberID, exerciseID, recordType string) ([]*dto.PersonalRecordDTO, error) {
+	return r.queryRecords(fmt.Sprintf(selectRecords+` WHERE p.member_id = $1 AND p.exercise_id = $2
+		AND ($3 = '' OR p.record_type = $3) ORDER BY p.achieved_at, p.record_type, p.weight_kg`,
+		pq.QuoteIdentifier(gymID)), memberID, exerciseID, recordType)
+}
+
+func (r *PersonalRecordRepository) FindNewByWorkout(gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error) {
+	return r.queryRecords(fmt.Sprintf(selectRecords+` WHERE p.member_workout_id = $1 AND p.previous_value IS NOT NULL
+		ORDER BY p.achieved_at, p.record_type`, pq.QuoteIdentifier(gymID)), memberWorkoutID)
+}
+
+func (r *PersonalRecordRepository) queryRecords(query string, args ...any) ([]*dto.PersonalRecordDTO, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := []*dto.PersonalRecordDTO{}
+	for rows.Next() {
+		var record dto.PersonalRecordDTO
+		if err := rows.Scan(&record.ID, &record.MemberID, &record.ExerciseSource, &record.ExerciseID, &record.ExerciseName,
+			&record.RecordType, &record.Value, &record.PreviousValue, &record.WeightKg, &record.Reps, &record.MemberWorkoutID,
+			&record.SetLogID, &record.AchievedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceRecordsRewritesTheExercisesInTheCallersTransaction(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewPersonalRecordRepository(db)
+	now := time.Now()
+	weight, reps, setID := 100.0, 5, "set-1"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "gym-1".personal_record WHERE member_id = \$1 AND exercise_id = ANY\(\$2\)`).
+		WithArgs("member-1", pq.Array([]string{"squat"})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery(`INSERT INTO "gym-1".personal_record`).
+		WithArgs("member-1", "public", "squat", "max_weight", 100.0, nil, &weight, &reps, "workout-1", &setID, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("record-1"))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	records := []*dto.PersonalRecordDTO{{ExerciseSource: "public", ExerciseID: "squat", RecordType: "max_weight", Value: 100,
+		WeightKg: &weight, Reps: &reps, MemberWorkoutID: "workout-1", SetLogID: &setID, AchievedAt: now}}
+	require.NoError(t, repo.ReplaceRecords(tx, "gym-1", "member-1", []string{"squat"}, records))
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, "record-1", records[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindLoggedSetsSkipsDroppedWorkouts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewPersonalRecordRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	mock.ExpectQuery(`SELECT w.member_id, ARRAY\(`).
+		WithArgs("workout-1").
+		WillReturnRows(sqlmock.NewRows([]string{"member_id", "exercises"}).AddRow("member-1", "{squat,bench}"))
+	memberID, exerciseIDs, err := repo.FindWorkoutExercises(tx, "gym-1", "workout-1")
+	require.NoError(t, err)
+	assert.Equal(t, "member-1", memberID)
+	assert.Equal(t, []string{"squat", "bench"}, exerciseIDs)
+
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("gym-1", "member-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, repo.LockMemberRecords(tx, "gym-1", "member-1"))
+
+	mock.ExpectQuery(`AND s.completed AND w.status NOT IN \('skipped', 'cancelled'\)\s+ORDER BY COALESCE\(w.started_at, w.created_at\)`).
+		WithArgs("member-1", pq.Array([]string{"squat"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "member_workout_id", "exercise_source", "exercise_id", "reps", "weight_kg",
+			"created_at", "completed_at"}).
+			AddRow("set-1", "workout-1", "public", "squat", 5, 100.0, now, nil))
+	sets, err := repo.FindLoggedSets(tx, "gym-1", "member-1", []string{"squat"})
+	require.NoError(t, err)
+	require.Len(t, sets, 1)
+	assert.Equal(t, 5, *sets[0].Reps)
+	assert.Nil(t, sets[0].WorkoutCompletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindCurrentByMemberKeepsMaxRepsPerWeight(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewPersonalRecordRepository(db)
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(p.exercise_id, p.record_type, CASE WHEN p.record_type = 'max_reps' THEN p.weight_kg END\)`).
+		WithArgs("member-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "member_id", "exercise_source", "exercise_id", "name", "record_type", "value",
+			"previous_value", "weight_kg", "reps", "member_workout_id", "set_log_id", "achieved_at"}).
+			AddRow("record-1", "member-1", "gym", "sled", "Sled Push", "max_weight", 180.0, 160.0, 180.0, 1, "workout-1", "set-1", time.Now()))
+	records, err := repo.FindCurrentByMember("gym-1", "member-1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Sled Push", records[0].ExerciseName)
+	assert.Equal(t, 160.0, *records[0].PreviousValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
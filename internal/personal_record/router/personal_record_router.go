@@ -0,0 +1,16 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+// NewPersonalRecordRouter is mounted under /user/{id}/records
+func NewPersonalRecordRouter(handler interfaces.PersonalRecordHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", handler.ListUserRecords)         // GET /user/{id}/records
+	r.Get("/history", handler.GetRecordHistory) // GET /user/{id}/records/history?exercise_id=&record_type=
+	return r
+}
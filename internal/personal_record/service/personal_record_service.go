@@ -0,0 +1,265 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/enum"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+// maxEstimateReps is the most reps a set can have for its one-rep max estimate to count; past it the
+// formulas drift too far from a real single
+const maxEstimateReps = 12
+
+type PersonalRecordService struct {
+	repository interfaces.PersonalRecordRepository
+}
+
+func NewPersonalRecordService(repo interfaces.PersonalRecordRepository) *PersonalRecordService {
+	return &PersonalRecordService{repository: repo}
+}
+
+// UpdateWorkoutRecords replays every completed set of the member for the exercises of the workout, so logging,
+// correcting or deleting a set, even of an older workout, leaves the record history as if it was logged right
+func (s *PersonalRecordService) UpdateWorkoutRecords(tx *sql.Tx, gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error) {
+	memberID, exerciseIDs, err := s.repository.FindWorkoutExercises(tx, gymID, memberWorkoutID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Custom member workout not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout exercises", err)
+	}
+	if len(exerciseIDs) == 0 {
+		return []*dto.PersonalRecordDTO{}, nil
+	}
+	if err := s.repository.LockMemberRecords(tx, gymID, memberID); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to lock personal records", err)
+	}
+	sets, err := s.repository.FindLoggedSets(tx, gymID, memberID, exerciseIDs)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get logged sets", err)
+	}
+	records := ComputeRecords(sets)
+	for _, record := range records {
+		record.MemberID = memberID
+	}
+	if err := s.repository.ReplaceRecords(tx, gymID, memberID, exerciseIDs, records); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to save personal records", err)
+	}
+
+	beaten := []*dto.PersonalRecordDTO{}
+	for _, record := range records {
+		if record.MemberWorkoutID == memberWorkoutID && record.PreviousValue != nil {
+			beaten = append(beaten, record)
+		}
+	}
+	return beaten, nil
+}
+
+func (s *PersonalRecordService) GetWorkoutRecords(gymID, memberWorkoutID string) ([]*dto.PersonalRecordDTO, error) {
+	records, err := s.repository.FindNewByWorkout(gymID, memberWorkoutID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout records", err)
+	}
+	return records, nil
+}
+
+// GetMemberRecords gives the current records of the member grouped by exercise, sorted by exercise name
+func (s *PersonalRecordService) GetMemberRecords(gymID, memberID string) ([]*dto.ExerciseRecordsDTO, error) {
+	if memberID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "User ID is required", nil)
+	}
+	records, err := s.repository.FindCurrentByMember(gymID, memberID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get personal records", err)
+	}
+
+	byExercise := map[string]*dto.ExerciseRecordsDTO{}
+	exercises := []*dto.ExerciseRecordsDTO{}
+	for _, record := range records {
+		exercise, ok := byExercise[record.ExerciseID]
+		if !ok {
+			exercise = &dto.ExerciseRecordsDTO{
+				ExerciseSource: record.ExerciseSource,
+				ExerciseID:     record.ExerciseID,
+				ExerciseName:   record.ExerciseName,
+				RepsAtWeight:   []*dto.PersonalRecordDTO{},
+			}
+			byExercise[record.ExerciseID] = exercise
+			exercises = append(exercises, exercise)
+		}
+		switch enum.RecordType(record.RecordType) {
+		case enum.MaxWeight:
+			exercise.MaxWeight = record
+		case enum.Estimated1RM:
+			exercise.Estimated1RM = record
+			if record.WeightKg != nil && record.Reps != nil {
+				brzycki := round(Brzycki(*record.WeightKg, *record.Reps))
+				exercise.Estimated1RMBrzycki = &brzycki
+			}
+		case enum.MaxVolume:
+			exercise.MaxVolume = record
+		case enum.MaxReps:
+			exercise.RepsAtWeight = append(exercise.RepsAtWeight, record)
+		}
+	}
+	for _, exercise := range exercises {
+		sort.SliceStable(exercise.RepsAtWeight, func(i, j int) bool {
+			return weightOf(exercise.RepsAtWeight[i].WeightKg) > weightOf(exercise.RepsAtWeight[j].WeightKg)
+		})
+	}
+	sort.SliceStable(exercises, func(i, j int) bool {
+		if exercises[i].ExerciseName != exercises[j].ExerciseName {
+			return exercises[i].ExerciseName < exercises[j].ExerciseName
+		}
+		return exercises[i].ExerciseID < exercises[j].ExerciseID
+	})
+	return exercises, nil
+}
+
+func (s *PersonalRecordService) GetRecordHistory(gymID, memberID, exerciseID, recordType string) ([]*dto.PersonalRecordDTO, error) {
+	if memberID == "" || exerciseID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "User ID and exercise_id are required", nil)
+	}
+	if recordType != "" && !enum.RecordType(recordType).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "record_type must be one of max_weight, max_reps, estimated_1rm or max_volume", nil)
+	}
+	records, err := s.repository.FindHistory(gymID, memberID, exerciseID, recordType)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get personal record history", err)
+	}
+	return records, nil
+}
+
+// Epley estimates the one-rep max of a set as weight x (1 + reps / 30); a single is its own max
+func Epley(weightKg float64, reps int) float64 {
+	if reps <= 1 {
+		return weightKg
+	}
+	return weightKg * (1 + float64(reps)/30)
+}
+
+// Brzycki estimates the one-rep max of a set as weight x 36 / (37 - reps)
+func Brzycki(weightKg float64, reps int) float64 {
+	return weightKg * 36 / (37 - float64(reps))
+}
+
+// ComputeRecords replays a member's completed sets, in the order they were done, and returns every record they
+// set: the first value of each kind is a baseline, and each later one that beats it is a new record. A workout's
+// volume counts once it is completed.
+func ComputeRecords(sets []dto.LoggedSet) []*dto.PersonalRecordDTO {
+	type best struct {
+		maxWeight, estimated1RM, maxVolume *float64
+		repsAtWeight                       map[float64]float64
+	}
+	bests := map[string]*best{}
+	bestOf := func(exerciseID string) *best {
+		if bests[exerciseID] == nil {
+			bests[exerciseID] = &best{repsAtWeight: map[float64]float64{}}
+		}
+		return bests[exerciseID]
+	}
+
+	records := []*dto.PersonalRecordDTO{}
+	// beat records value when it is the first or beats current, which it then replaces
+	beat := func(current *float64, value float64, record dto.PersonalRecordDTO) *float64 {
+		if current != nil && value <= *current {
+			return current
+		}
+		record.Value = value
+		record.PreviousValue = current
+		records = append(records, &record)
+		return &value
+	}
+
+	// Volume is summed per workout and exercise and recorded when the workout's sets are over
+	type volumeKey struct{ workoutID, exerciseID string }
+	volumes := map[volumeKey]float64{}
+	volumeOrder := []volumeKey{}
+	lastSet := map[volumeKey]dto.LoggedSet{}
+	flushVolumes := func() {
+		for _, key := range volumeOrder {
+			set := lastSet[key]
+			if set.WorkoutCompletedAt == nil || volumes[key] <= 0 {
+				continue
+			}
+			b := bestOf(key.exerciseID)
+			b.maxVolume = beat(b.maxVolume, round(volumes[key]), dto.PersonalRecordDTO{
+				ExerciseSource:  set.ExerciseSource,
+				ExerciseID:      set.ExerciseID,
+				RecordType:      string(enum.MaxVolume),
+				MemberWorkoutID: set.MemberWorkoutID,
+				AchievedAt:      *set.WorkoutCompletedAt,
+			})
+		}
+		volumes, volumeOrder, lastSet = map[volumeKey]float64{}, nil, map[volumeKey]dto.LoggedSet{}
+	}
+
+	currentWorkout := ""
+	for _, set := range sets {
+		if set.MemberWorkoutID != currentWorkout {
+			flushVolumes()
+			currentWorkout = set.MemberWorkoutID
+		}
+		if set.Reps == nil || *set.Reps < 1 {
+			continue
+		}
+		reps, weight := *set.Reps, weightOf(set.WeightKg)
+		setLogID := set.SetLogID
+		record := dto.PersonalRecordDTO{
+			ExerciseSource:  set.ExerciseSource,
+			ExerciseID:      set.ExerciseID,
+			WeightKg:        set.WeightKg,
+			Reps:            set.Reps,
+			MemberWorkoutID: set.MemberWorkoutID,
+			SetLogID:        &setLogID,
+			AchievedAt:      set.LoggedAt,
+		}
+		b := bestOf(set.ExerciseID)
+
+		previousReps, ok := b.repsAtWeight[weight]
+		var current *float64
+		if ok {
+			current = &previousReps
+		}
+		record.RecordType = string(enum.MaxReps)
+		if next := beat(current, float64(reps), record); next != current {
+			b.repsAtWeight[weight] = *next
+		}
+
+		if weight > 0 {
+			record.RecordType = string(enum.MaxWeight)
+			b.maxWeight = beat(b.maxWeight, weight, record)
+			if reps <= maxEstimateReps {
+				record.RecordType = string(enum.Estimated1RM)
+				b.estimated1RM = beat(b.estimated1RM, round(Epley(weight, reps)), record)
+			}
+
+			key := volumeKey{set.MemberWorkoutID, set.ExerciseID}
+			if _, ok := volumes[key]; !ok {
+				volumeOrder = append(volumeOrder, key)
+			}
+			volumes[key] += weight * float64(reps)
+			lastSet[key] = set
+		}
+	}
+	flushVolumes()
+	return records
+}
+
+func weightOf(weightKg *float64) float64 {
+	if weightKg == nil {
+		return 0
+	}
+	return *weightKg
+}
+
+// round keeps two decimals, as the records are stored
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/personal_record/dto"
+	"github.com/alejandro-albiol/athenai/internal/personal_record/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	interfaces.PersonalRecordRepository
+	sets     []dto.LoggedSet
+	replaced []*dto.PersonalRecordDTO
+	current  []*dto.PersonalRecordDTO
+	locked   []string
+}
+
+func (m *mockRepo) FindWorkoutExercises(tx *sql.Tx, gymID, memberWorkoutID string) (string, []string, error) {
+	return "member-1", []string{"squat", "pullup"}, nil
+}
+func (m *mockRepo) LockMemberRecords(tx *sql.Tx, gymID, memberID string) error {
+	m.locked = append(m.locked, memberID)
+	return nil
+}
+func (m *mockRepo) FindLoggedSets(tx *sql.Tx, gymID, memberID string, exerciseIDs []string) ([]dto.LoggedSet, error) {
+	return m.sets, nil
+}
+func (m *mockRepo) ReplaceRecords(tx *sql.Tx, gymID, memberID string, exerciseIDs []string, records []*dto.PersonalRecordDTO) error {
+	m.replaced = records
+	return nil
+}
+func (m *mockRepo) FindCurrentByMember(gymID, memberID string) ([]*dto.PersonalRecordDTO, error) {
+	return m.current, nil
+}
+
+var day1 = time.Date(2026, 10, 12, 18, 0, 0, 0, time.UTC)
+
+func set(id, workout, exercise string, reps int, weight float64, completedAt *time.Time) dto.LoggedSet {
+	s := dto.LoggedSet{SetLogID: id, MemberWorkoutID: workout, ExerciseSource: "public", ExerciseID: exercise, Reps: &reps,
+		LoggedAt: day1, WorkoutCompletedAt: completedAt}
+	if weight > 0 {
+		s.WeightKg = &weight
+	}
+	return s
+}
+
+func recordsOf(records []*dto.PersonalRecordDTO, recordType string) []*dto.PersonalRecordDTO {
+	found := []*dto.PersonalRecordDTO{}
+	for _, record := range records {
+		if record.RecordType == recordType {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	assert.Equal(t, 100.0, Epley(100, 1))
+	assert.InDelta(t, 116.67, Epley(100, 5), 0.01)
+	assert.Equal(t, 100.0, Brzycki(100, 1))
+	assert.InDelta(t, 112.5, Brzycki(100, 5), 0.01)
+}
+
+func TestComputeRecordsKeepsBaselinesAndImprovements(t *testing.T) {
+	done1, done2 := day1.Add(time.Hour), day1.Add(49*time.Hour)
+	records := ComputeRecords([]dto.LoggedSet{
+		set("s1", "w1", "squat", 5, 100, &done1),
+		set("s2", "w1", "squat", 8, 90, &done1),
+		set("s3", "w1", "pullup", 10, 0, &done1),
+		set("s4", "w2", "squat", 6, 100, &done2),
+		set("s5", "w2", "squat", 3, 105, &done2),
+		set("s6", "w2", "squat", 15, 60, &done2),
+		set("s7", "w2", "pullup", 9, 0, &done2),
+		set("s8", "w3", "squat", 10, 100, nil), // workout still in progress, so no volume yet
+	})
+
+	maxWeight := recordsOf(records, "max_weight")
+	require.Len(t, maxWeight, 2)
+	assert.Equal(t, 100.0, maxWeight[0].Value)
+	assert.Nil(t, maxWeight[0].PreviousValue)
+	assert.Equal(t, 105.0, maxWeight[1].Value)
+	assert.Equal(t, 100.0, *maxWeight[1].PreviousValue)
+	assert.Equal(t, "s5", *maxWeight[1].SetLogID)
+
+	// 5 x 100 = 116.67, 8 x 90 = 114, 6 x 100 = 120, 3 x 105 = 115.5; 15 reps is past the estimate limit
+	estimates := recordsOf(records, "estimated_1rm")
+	require.Len(t, estimates, 3)
+	assert.Equal(t, 116.67, estimates[0].Value)
+	assert.Equal(t, 120.0, estimates[1].Value)
+	assert.Equal(t, "s4", *estimates[1].SetLogID)
+	assert.Equal(t, 133.33, estimates[2].Value)
+	assert.Equal(t, "s8", *estimates[2].SetLogID)
+
+	// Reps are kept per weight, bodyweight included
+	var pullups []float64
+	var squatsAt100 []float64
+	for _, record := range recordsOf(records, "max_reps") {
+		if record.ExerciseID == "pullup" {
+			pullups = append(pullups, record.Value)
+		} else if *record.WeightKg == 100 {
+			squatsAt100 = append(squatsAt100, record.Value)
+		}
+	}
+	assert.Equal(t, []float64{10}, pullups)
+	assert.Equal(t, []float64{5, 6, 10}, squatsAt100)
+
+	// 500 + 720 = 1220, then 600 + 315 + 900 = 1815
+	volumes := recordsOf(records, "max_volume")
+	require.Len(t, volumes, 2)
+	assert.Equal(t, 1220.0, volumes[0].Value)
+	assert.Equal(t, 1815.0, volumes[1].Value)
+	assert.Equal(t, done2, volumes[1].AchievedAt)
+	assert.Nil(t, volumes[1].SetLogID)
+}
+
+func TestUpdateWorkoutRecordsReturnsTheRecordsTheWorkoutBeat(t *testing.T) {
+	done := day1.Add(time.Hour)
+	repo := &mockRepo{sets: []dto.LoggedSet{
+		set("s1", "w1", "squat", 5, 100, &done),
+		set("s2", "w2", "squat", 5, 102.5, nil),
+	}}
+	svc := NewPersonalRecordService(repo)
+
+	beaten, err := svc.UpdateWorkoutRecords(nil, "gym", "w2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"member-1"}, repo.locked, "the member's records are locked before the sets are read")
+	// the four baselines of w1, the max weight and 1RM beaten by w2, and the reps at its new weight as a baseline
+	assert.Len(t, repo.replaced, 7)
+	assert.Equal(t, "member-1", repo.replaced[0].MemberID)
+	require.Len(t, beaten, 2)
+	for _, record := range beaten {
+		assert.Equal(t, "w2", record.MemberWorkoutID)
+		assert.NotNil(t, record.PreviousValue)
+	}
+
+	// The first workout only sets baselines
+	beaten, err = svc.UpdateWorkoutRecords(nil, "gym", "w1")
+	require.NoError(t, err)
+	assert.Empty(t, beaten)
+}
+
+func TestGetMemberRecordsGroupsByExercise(t *testing.T) {
+	weight, reps := 100.0, 5
+	light, heavy := 60.0, 80.0
+	svc := NewPersonalRecordService(&mockRepo{current: []*dto.PersonalRecordDTO{
+		{ExerciseID: "squat", ExerciseName: "Squat", RecordType: "estimated_1rm", Value: 116.67, WeightKg: &weight, Reps: &reps},
+		{ExerciseID: "squat", ExerciseName: "Squat", RecordType: "max_reps", Value: 15, WeightKg: &light},
+		{ExerciseID: "squat", ExerciseName: "Squat", RecordType: "max_reps", Value: 8, WeightKg: &heavy},
+		{ExerciseID: "bench", ExerciseName: "Bench Press", RecordType: "max_weight", Value: 80},
+	}})
+
+	exercises, err := svc.GetMemberRecords("gym", "member-1")
+	require.NoError(t, err)
+	require.Len(t, exercises, 2)
+	assert.Equal(t, "Bench Press", exercises[0].ExerciseName)
+	assert.Equal(t, 80.0, exercises[0].MaxWeight.Value)
+	squat := exercises[1]
+	assert.Equal(t, 116.67, squat.Estimated1RM.Value)
+	assert.Equal(t, 112.5, *squat.Estimated1RMBrzycki)
+	require.Len(t, squat.RepsAtWeight, 2)
+	assert.Equal(t, 80.0, *squat.RepsAtWeight[0].WeightKg)
+}
+
+func TestGetRecordHistoryValidation(t *testing.T) {
+	svc := NewPersonalRecordService(&mockRepo{})
+	_, err := svc.GetRecordHistory("gym", "member-1", "", "")
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	_, err = svc.GetRecordHistory("gym", "member-1", "squat", "best_set")
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
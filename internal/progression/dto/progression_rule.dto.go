@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// ProgressionRuleDTO is the progression rule of an exercise type; IsDefault marks the built-in rules of types
+// the gym did not configure
+type ProgressionRuleDTO struct {
+	ExerciseType      string     `json:"exercise_type"`
+	Strategy          string     `json:"strategy"`
+	IncrementPercent  float64    `json:"increment_percent"`
+	WeightStepKg      float64    `json:"weight_step_kg"` // loads are rounded to it and grow by at least it
+	MaxRPE            float64    `json:"max_rpe"`        // sessions harder than this hold the load
+	DeloadAfterMisses int        `json:"deload_after_misses"`
+	DeloadPercent     float64    `json:"deload_percent"`
+	IsDefault         bool       `json:"is_default"`
+	UpdatedBy         *string    `json:"updated_by,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateProgressionRuleDTO replaces the rule of an exercise type
+type UpdateProgressionRuleDTO struct {
+	ExerciseType      string  `json:"-"`
+	Strategy          string  `json:"strategy"`
+	IncrementPercent  float64 `json:"increment_percent"`
+	WeightStepKg      float64 `json:"weight_step_kg"`
+	MaxRPE            float64 `json:"max_rpe"`
+	DeloadAfterMisses int     `json:"deload_after_misses"`
+	DeloadPercent     float64 `json:"deload_percent"`
+	UpdatedBy         string  `json:"-"`
+}
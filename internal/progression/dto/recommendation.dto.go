@@ -0,0 +1,88 @@
+package dto
+
+import "time"
+
+// Prescription is what a workout exercise asks for
+type Prescription struct {
+	Sets     *int     `json:"sets,omitempty"`
+	RepsMin  *int     `json:"reps_min,omitempty"`
+	RepsMax  *int     `json:"reps_max,omitempty"`
+	WeightKg *float64 `json:"weight_kg,omitempty"`
+}
+
+// ExerciseRecommendationDTO proposes the next-session targets of an exercise of the member's next workout
+type ExerciseRecommendationDTO struct {
+	WorkoutExerciseID string       `json:"workout_exercise_id"`
+	ExerciseSource    string       `json:"exercise_source"`
+	ExerciseID        string       `json:"exercise_id"`
+	ExerciseName      string       `json:"exercise_name"`
+	ExerciseType      string       `json:"exercise_type"`
+	Strategy          string       `json:"strategy"`
+	Action            string       `json:"action"`
+	Reason            string       `json:"reason"`
+	Current           Prescription `json:"current"`
+	Proposed          Prescription `json:"proposed"`
+	// TargetReps is the reps to aim for on every set when double progression adds reps at the same load
+	TargetReps         *int `json:"target_reps,omitempty"`
+	SessionsConsidered int  `json:"sessions_considered"`
+	ConsecutiveMisses  int  `json:"consecutive_misses"`
+}
+
+// RecommendationsDTO gathers the recommendations for the member's next scheduled workout
+type RecommendationsDTO struct {
+	MemberID          string                       `json:"member_id"`
+	MemberWorkoutID   string                       `json:"member_workout_id"`
+	WorkoutInstanceID string                       `json:"workout_instance_id"`
+	ScheduledDate     string                       `json:"scheduled_date"`
+	Exercises         []*ExerciseRecommendationDTO `json:"exercises"`
+}
+
+// AcceptRecommendationsDTO applies the proposals to the member's next scheduled workout. MemberWorkoutID, when
+// given, must still be the next workout; WorkoutExerciseIDs limits the proposals applied.
+type AcceptRecommendationsDTO struct {
+	MemberWorkoutID    string   `json:"member_workout_id,omitempty"`
+	WorkoutExerciseIDs []string `json:"workout_exercise_ids,omitempty"`
+	AcceptedBy         string   `json:"-"`
+}
+
+// AcceptResultDTO reports the proposals applied. When the workout instance is shared with other workouts,
+// schedules or programs, the next workout gets its own copy of it and CopiedInstance is set.
+type AcceptResultDTO struct {
+	MemberWorkoutID   string                       `json:"member_workout_id"`
+	WorkoutInstanceID string                       `json:"workout_instance_id"`
+	CopiedInstance    bool                         `json:"copied_instance"`
+	Applied           []*ExerciseRecommendationDTO `json:"applied"`
+}
+
+// NextWorkout is the member's next scheduled workout
+type NextWorkout struct {
+	ID                string
+	WorkoutInstanceID string
+	ScheduledDate     string
+}
+
+// WorkoutExercise is an exercise of the next workout's instance
+type WorkoutExercise struct {
+	ID             string
+	ExerciseSource string
+	ExerciseID     string
+	ExerciseName   string
+	ExerciseType   string
+	Prescription
+}
+
+// Session is what the member did of an exercise in a completed workout, with what the workout prescribed
+type Session struct {
+	MemberWorkoutID string
+	ExerciseID      string
+	CompletedAt     time.Time
+	Prescribed      Prescription
+	Sets            []SetPerformance
+}
+
+type SetPerformance struct {
+	Reps      *int
+	WeightKg  *float64
+	RPE       *float64
+	Completed bool
+}
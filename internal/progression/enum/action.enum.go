@@ -0,0 +1,12 @@
+package enum
+
+// Action is what a recommendation proposes for the next session of an exercise
+type Action string
+
+const (
+	IncreaseWeight Action = "increase_weight"
+	IncreaseReps   Action = "increase_reps"
+	Hold           Action = "hold"
+	Deload         Action = "deload"
+	NoHistory      Action = "no_history" // the member has no completed session of the exercise yet
+)
@@ -0,0 +1,20 @@
+package enum
+
+// Strategy is how a progression rule moves the load up between sessions
+type Strategy string
+
+const (
+	// DoubleProgression adds reps within the prescribed range at the same load, and adds load once every set
+	// reaches the top of the range
+	DoubleProgression Strategy = "double_progression"
+	// Percentage adds load by the rule's percentage every session the prescribed reps are done
+	Percentage Strategy = "percentage"
+)
+
+func (s Strategy) IsValid() bool {
+	switch s {
+	case DoubleProgression, Percentage:
+		return true
+	}
+	return false
+}
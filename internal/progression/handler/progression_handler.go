@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/alejandro-albiol/athenai/internal/progression/interfaces"
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type ProgressionHandler struct {
+	service interfaces.ProgressionService
+}
+
+func NewProgressionHandler(service interfaces.ProgressionService) *ProgressionHandler {
+	return &ProgressionHandler{service: service}
+}
+
+func (h *ProgressionHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	rules, err := h.service.ListRules(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list progression rules")
+		return
+	}
+	response.WriteAPISuccess(w, "Progression rules retrieved successfully", rules)
+}
+
+func (h *ProgressionHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var rule dto.UpdateProgressionRuleDTO
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	rule.ExerciseType = chi.URLParam(r, "exerciseType")
+	rule.UpdatedBy = middleware.GetUserID(r)
+	saved, err := h.service.UpdateRule(middleware.GetGymID(r), &rule)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to save progression rule")
+		return
+	}
+	response.WriteAPISuccess(w, "Progression rule saved successfully", saved)
+}
+
+func (h *ProgressionHandler) ResetRule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.ResetRule(middleware.GetGymID(r), chi.URLParam(r, "exerciseType")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to reset progression rule")
+		return
+	}
+	response.WriteAPISuccess(w, "Progression rule reset to the default", nil)
+}
+
+func (h *ProgressionHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	memberID := chi.URLParam(r, "memberID")
+	if memberID != middleware.GetUserID(r) && !canCoach(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Members can only see their own recommendations", nil))
+		return
+	}
+	recommendations, err := h.service.GetRecommendations(middleware.GetGymID(r), memberID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get recommendations")
+		return
+	}
+	response.WriteAPISuccess(w, "Recommendations retrieved successfully", recommendations)
+}
+
+func (h *ProgressionHandler) AcceptRecommendations(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	if !canCoach(r) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators and trainers can accept recommendations", nil))
+		return
+	}
+	var accept dto.AcceptRecommendationsDTO
+	if err := json.NewDecoder(r.Body).Decode(&accept); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	accept.AcceptedBy = middleware.GetUserID(r)
+	result, err := h.service.AcceptRecommendations(middleware.GetGymID(r), chi.URLParam(r, "memberID"), &accept)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to accept recommendations")
+		return
+	}
+	response.WriteAPISuccess(w, "Recommendations applied to the next workout", result)
+}
+
+func requireGymUser(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Progression rules belong to a gym", nil))
+	return false
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym the rules belong to
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage progression rules", nil))
+	return false
+}
+
+func canCoach(r *http.Request) bool {
+	return middleware.IsGymAdmin(r) || middleware.GetUserRole(r) == string(user_enum.Trainer)
+}
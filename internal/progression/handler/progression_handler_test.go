@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/alejandro-albiol/athenai/internal/progression/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/progression/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.ProgressionService
+	accepted *dto.AcceptRecommendationsDTO
+	rule     *dto.UpdateProgressionRuleDTO
+}
+
+func (m *mockService) GetRecommendations(gymID, memberID string) (*dto.RecommendationsDTO, error) {
+	return &dto.RecommendationsDTO{MemberID: memberID, Exercises: []*dto.ExerciseRecommendationDTO{}}, nil
+}
+func (m *mockService) AcceptRecommendations(gymID, memberID string, accept *dto.AcceptRecommendationsDTO) (*dto.AcceptResultDTO, error) {
+	m.accepted = accept
+	return &dto.AcceptResultDTO{Applied: []*dto.ExerciseRecommendationDTO{}}, nil
+}
+func (m *mockService) UpdateRule(gymID string, rule *dto.UpdateProgressionRuleDTO) (*dto.ProgressionRuleDTO, error) {
+	m.rule = rule
+	return &dto.ProgressionRuleDTO{ExerciseType: rule.ExerciseType}, nil
+}
+
+func serve(svc *mockService, method, target, body, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewProgressionRouter(NewProgressionHandler(svc)),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, method, target, body)
+}
+
+func TestOnlyCoachesAcceptRecommendations(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/member/member-1/recommendations", "", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(svc, http.MethodGet, "/member/member-2/recommendations", "", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodPost, "/member/member-1/recommendations/accept", "", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.accepted)
+
+	w = serve(svc, http.MethodPost, "/member/member-1/recommendations/accept", `{"member_workout_id":"mw-9"}`, "coach-1", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "mw-9", svc.accepted.MemberWorkoutID)
+	assert.Equal(t, "coach-1", svc.accepted.AcceptedBy)
+}
+
+func TestOnlyAdminsChangeRules(t *testing.T) {
+	svc := &mockService{}
+	body := `{"strategy":"percentage","increment_percent":5,"weight_step_kg":2.5,"max_rpe":9,"deload_after_misses":3,"deload_percent":10}`
+
+	w := serve(svc, http.MethodPut, "/rules/strength", body, "coach-1", "trainer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodPut, "/rules/strength", body, "admin-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "strength", svc.rule.ExerciseType)
+	assert.Equal(t, "admin-1", svc.rule.UpdatedBy)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "net/http"
+
+type ProgressionHandler interface {
+	ListRules(w http.ResponseWriter, r *http.Request)
+	UpdateRule(w http.ResponseWriter, r *http.Request)
+	ResetRule(w http.ResponseWriter, r *http.Request)
+	GetRecommendations(w http.ResponseWriter, r *http.Request)
+	AcceptRecommendations(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/progression/dto"
+
+type ProgressionRepository interface {
+	FindRules(gymID string) ([]*dto.ProgressionRuleDTO, error)
+	UpsertRule(gymID string, rule *dto.UpdateProgressionRuleDTO) (*dto.ProgressionRuleDTO, error)
+	// DeleteRule returns sql.ErrNoRows when the gym did not configure the exercise type
+	DeleteRule(gymID, exerciseType string) error
+
+	// FindNextWorkout finds the member's next workout still scheduled from today; sql.ErrNoRows when there is none
+	FindNextWorkout(gymID, memberID string) (*dto.NextWorkout, error)
+	FindWorkoutExercises(gymID, instanceID string) ([]dto.WorkoutExercise, error)
+	// FindRecentSessions lists, newest first, up to perExercise completed workouts of the member for each exercise
+	FindRecentSessions(gymID, memberID string, exerciseIDs []string, perExercise int) ([]dto.Session, error)
+	// ApplyPrescriptions sets the prescriptions, keyed by workout exercise ID, on the member workout's instance in a
+	// single transaction. A shared instance is copied for the member workout first; the instance changed is returned.
+	// sql.ErrNoRows when the member workout is no longer scheduled.
+	ApplyPrescriptions(gymID, memberWorkoutID, instanceID string, prescriptions map[string]dto.Prescription, acceptedBy string) (string, bool, error)
+}
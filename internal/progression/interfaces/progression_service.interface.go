@@ -0,0 +1,11 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/progression/dto"
+
+type ProgressionService interface {
+	ListRules(gymID string) ([]*dto.ProgressionRuleDTO, error)
+	UpdateRule(gymID string, rule *dto.UpdateProgressionRuleDTO) (*dto.ProgressionRuleDTO, error)
+	ResetRule(gymID, exerciseType string) error
+	GetRecommendations(gymID, memberID string) (*dto.RecommendationsDTO, error)
+	AcceptRecommendations(gymID, memberID string, accept *dto.AcceptRecommendationsDTO) (*dto.AcceptResultDTO, error)
+}
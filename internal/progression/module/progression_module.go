@@ -0,0 +1,17 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/handler"
+	"github.com/alejandro-albiol/athenai/internal/progression/repository"
+	"github.com/alejandro-albiol/athenai/internal/progression/router"
+	"github.com/alejandro-albiol/athenai/internal/progression/service"
+)
+
+func NewProgressionModule(db *sql.DB) http.Handler {
+	service := service.NewProgressionService(repository.NewProgressionRepository(db))
+	handler := handler.NewProgressionHandler(service)
+	return router.NewProgressionRouter(handler)
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/lib/pq"
+)
+
+type ProgressionRepository struct {
+	db *sql.DB
+}
+
+func NewProgressionRepository(db *sql.DB) *ProgressionRepository {
+	return &ProgressionRepository{db: db}
+}
+
+const ruleColumns = `exercise_type, strategy, increment_percent::float8, weight_step_kg::float8, max_rpe::float8,
+	deload_after_misses, deload_percent::float8, updated_by, updated_at`
+
+func (r *ProgressionRepository) FindRules(gymID string) ([]*dto.ProgressionRuleDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT `+ruleColumns+` FROM %s.progression_rule ORDER BY exercise_type`,
+		pq.QuoteIdentifier(gymID)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := []*dto.ProgressionRuleDTO{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *ProgressionRepository) UpsertRule(gymID string, rule *dto.UpdateProgressionRuleDTO) (*dto.ProgressionRuleDTO, error) {
+	return scanRule(r.db.QueryRow(fmt.Sprintf(`INSERT INTO %s.progression_rule (exercise_type, strategy, increment_percent,
+			weight_step_kg, max_rpe, deload_after_misses, deload_percent, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (exercise_type) DO UPDATE SET strategy = EXCLUDED.strategy, increment_percent = EXCLUDED.increment_percent,
+			weight_step_kg = EXCLUDED.weight_step_kg, max_rpe = EXCLUDED.max_rpe, deload_after_misses = EXCLUDED.deload_after_misses,
+			deload_percent = EXCLUDED.deload_percent, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING `+ruleColumns, pq.QuoteIdentifier(gymID)),
+		rule.ExerciseType, rule.Strategy, rule.IncrementPercent, rule.WeightStepKg, rule.MaxRPE, rule.DeloadAfterMisses,
+		rule.DeloadPercent, rule.UpdatedBy))
+}
+
+func scanRule(row interface{ Scan(...any) error }) (*dto.ProgressionRuleDTO, error) {
+	var rule dto.ProgressionRuleDTO
+	var updatedAt sql.NullTime
+	if err := row.Scan(&rule.ExerciseType, &rule.Strategy, &rule.IncrementPercent, &rule.WeightStepKg, &rule.MaxRPE,
+		&rule.DeloadAfterMisses, &rule.DeloadPercent, &rule.UpdatedBy, &updatedAt); err != nil {
+		return nil, err
+	}
+	if updatedAt.Valid {
+		rule.UpdatedAt = &updatedAt.Time
+	}
+	return &rule, nil
+}
+
+func (r *ProgressionRepository) DeleteRule(gymID, exerciseType string) error {
+	result, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s.progression_rule WHERE exercise_type = $1`,
+		pq.QuoteIdentifier(gymID)), exerciseType)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *ProgressionRepository) FindNextWorkout(gymID, memberID string) (*dto.NextWorkout, error) {
+	var next dto.NextWorkout
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT id, workout_instance_id, to_char(scheduled_date, 'YYYY-MM-DD')
+		FROM %s.custom_member_workout
+		WHERE member_id = $1 AND status = 'scheduled' AND scheduled_date >= CURRENT_DATE
+		ORDER BY scheduled_date, scheduled_at NULLS LAST, created_at LIMIT 1`, pq.QuoteIdentifier(gymID)), memberID).
+		Scan(&next.ID, &next.WorkoutInstanceID, &next.ScheduledDate)
+	if err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+func (r *ProgressionRepository) FindWorkoutExercises(gymID, instanceID string) ([]dto.WorkoutExercise, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT e.id, e.exercise_source, COALESCE(e.public_exercise_id, e.gym_exercise_id),
+			COALESCE(pe.name, ce.name, ''), COALESCE(pe.exercise_type, ce.exercise_type, 'strength'),
+			e.sets, e.reps_min, e.reps_max, e.weight_kg::float8
+		FROM %[1]s.custom_workout_exercise e
+		LEFT JOIN public.exercise pe ON e.exercise_source = 'public' AND pe.id = e.public_exercise_id
+		LEFT JOIN %[1]s.custom_exercise ce ON e.exercise_source = 'gym' AND ce.id = e.gym_exercise_id
+		WHERE e.workout_instance_id = $1
+		ORDER BY e.created_at, e.block_name, e.exercise_order`, pq.QuoteIdentifier(gymID)), instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	exercises := []dto.WorkoutExercise{}
+	for rows.Next() {
+		var exercise dto.WorkoutExercise
+		if err := rows.Scan(&exercise.ID, &exercise.ExerciseSource, &exercise.ExerciseID, &exercise.ExerciseName,
+			&exercise.ExerciseType, &exercise.Sets, &exercise.RepsMin, &exercise.RepsMax, &exercise.WeightKg); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, rows.Err()
+}
+
+func (r *ProgressionRepository) FindRecentSessions(gymID, memberID string, exerciseIDs []string, perExercise int) ([]dto.Session, error) {
+	// Sessions are ranked per exercise so each exercise gets its own recent workouts
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT member_workout_id, exercise_id, completed_at, sets, reps_min, reps_max,
+			prescribed_weight, reps, weight_kg, rpe, completed
+		FROM (
+			SELECT w.id AS member_workout_id, COALESCE(e.public_exercise_id, e.gym_exercise_id) AS exercise_id, w.completed_at,
+				e.sets, e.reps_min, e.reps_max, e.weight_kg::float8 AS prescribed_weight,
+				s.reps, s.weight_kg::float8 AS weight_kg, s.rpe::float8 AS rpe, s.completed, s.set_number,
+				DENSE_RANK() OVER (PARTITION BY COALESCE(e.public_exercise_id, e.gym_exercise_id) ORDER BY w.completed_at DESC, w.id) AS session_rank
+			FROM %[1]s.custom_member_workout_set_log s
+			JOIN %[1]s.custom_member_workout w ON w.id = s.member_workout_id
+			JOIN %[1]s.custom_workout_exercise e ON e.id = s.workout_exercise_id
+			WHERE w.member_id = $1 AND w.status = 'completed' AND COALESCE(e.public_exercise_id, e.gym_exercise_id) = ANY($2)
+		) ranked
+		WHERE session_rank <= $3
+		ORDER BY exercise_id, completed_at DESC, member_workout_id, set_number`, pq.QuoteIdentifier(gymID)),
+		memberID, pq.Array(exerciseIDs), perExercise)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := []dto.Session{}
+	for rows.Next() {
+		var session dto.Session
+		var set dto.SetPerformance
+		if err := rows.Scan(&session.MemberWorkoutID, &session.ExerciseID, &session.CompletedAt, &session.Prescribed.Sets,
+			&session.Prescribed.RepsMin, &session.Prescribed.RepsMax, &session.Prescribed.WeightKg,
+			&set.Reps, &set.WeightKg, &set.RPE, &set.Completed); err != nil {
+			return nil, err
+		}
+		// An exercise done twice in a workout is one session
+		if last := len(sessions) - 1; last >= 0 && sessions[last].MemberWorkoutID == session.MemberWorkoutID &&
+			sessions[last].ExerciseID == session.ExerciseID {
+			sessions[last].Sets = append(sessions[last].Sets, set)
+			continue
+		}
+		session.Sets = []dto.SetPerformance{set}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *ProgressionRepository) ApplyPrescriptions(gymID, memberWorkoutID, instanceID string, prescriptions map[string]dto.Prescription, acceptedBy string) (string, bool, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback()
+
+	var shared bool
+	if err := tx.QueryRow(fmt.Sprintf(`SELECT
+			EXISTS (SELECT 1 FROM %[1]s.custom_member_workout WHERE workout_instance_id = $1 AND id <> $2)
+			OR EXISTS (SELECT 1 FROM %[1]s.workout_schedule WHERE workout_instance_id = $1)
+			OR EXISTS (SELECT 1 FROM %[1]s.training_program_day WHERE workout_instance_id = $1)`, schema),
+		instanceID, memberWorkoutID).Scan(&shared); err != nil {
+		return "", false, err
+	}
+
+	target := instanceID
+	if shared {
+		if target, err = copyInstance(tx, schema, instanceID, acceptedBy); err != nil {
+			return "", false, err
+		}
+		result, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_member_workout SET workout_instance_id = $2, updated_at = NOW()
+			WHERE id = $1 AND status = 'scheduled'`, schema), memberWorkoutID, target)
+		if err != nil {
+			return "", false, err
+		}
+		if err := requireAffected(result); err != nil {
+			return "", false, err
+		}
+		// A schedule occurrence with its own instance is an edited occurrence, kept when the schedule is edited
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %[1]s.workout_schedule_exception (schedule_id, occurrence_date, kind, created_by)
+			SELECT schedule_id, occurrence_date, 'modified', $2 FROM %[1]s.custom_member_workout
+			WHERE id = $1 AND schedule_id IS NOT NULL AND occurrence_date IS NOT NULL
+			ON CONFLICT (schedule_id, occurrence_date) DO UPDATE SET kind = EXCLUDED.kind, created_by = EXCLUDED.created_by, created_at = NOW()`, schema),
+			memberWorkoutID, acceptedBy); err != nil {
+			return "", false, err
+		}
+	} else {
+		var scheduled bool
+		if err := tx.QueryRow(fmt.Sprintf(`SELECT status = 'scheduled' FROM %s.custom_member_workout WHERE id = $1 FOR UPDATE`, schema),
+			memberWorkoutID).Scan(&scheduled); err != nil {
+			return "", false, err
+		}
+		if !scheduled {
+			return "", false, sql.ErrNoRows
+		}
+	}
+
+	// The slot of the original exercise is found by its block and order, which a copy keeps
+	for workoutExerciseID, prescription := range prescriptions {
+		result, err := tx.Exec(fmt.Sprintf(`UPDATE %[1]s.custom_workout_exercise e
+			SET sets = $3, reps_min = $4, reps_max = $5, weight_kg = $6, updated_at = NOW()
+			FROM %[1]s.custom_workout_exercise original
+			WHERE original.id = $1 AND e.workout_instance_id = $2
+				AND e.block_name = original.block_name AND e.exercise_order = original.exercise_order`, schema),
+			workoutExerciseID, target, prescription.Sets, prescription.RepsMin, prescription.RepsMax, prescription.WeightKg)
+		if err != nil {
+			return "", false, err
+		}
+		if err := requireAffected(result); err != nil {
+			return "", false, err
+		}
+	}
+	return target, shared, tx.Commit()
+}
+
+// copyInstance copies a workout instance and its exercises, in the order they were added, returning the copy's ID
+func copyInstance(tx *sql.Tx, schema, instanceID, createdBy string) (string, error) {
+	var id string
+	if err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %[1]s.custom_workout_instance (created_by, name, description, template_source,
+			public_template_id, gym_template_id, template_version)
+		SELECT $2, name, description, template_source, public_template_id, gym_template_id, template_version
+		FROM %[1]s.custom_workout_instance WHERE id = $1
+		RETURNING id`, schema), instanceID, createdBy).Scan(&id); err != nil {
+		return "", err
+	}
+	_, err := tx.Exec(fmt.Sprintf(`INSERT INTO %[1]s.custom_workout_exercise (created_by, workout_instance_id, exercise_source,
+			public_exercise_id, gym_exercise_id, block_name, exercise_order, sets, reps_min, reps_max, weight_kg, duration_seconds,
+			rest_seconds, notes, group_id, group_type, group_rounds, group_rest_seconds, created_at)
+		SELECT $3, $2, exercise_source, public_exercise_id, gym_exercise_id, block_name, exercise_order, sets, reps_min, reps_max,
+			weight_kg, duration_seconds, rest_seconds, notes, group_id, group_type, group_rounds, group_rest_seconds, created_at
+		FROM %[1]s.custom_workout_exercise WHERE workout_instance_id = $1`, schema), instanceID, id, createdBy)
+	return id, err
+}
+
+func requireAffected(result sql.Result) error {
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPrescriptionsCopiesSharedInstances(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewProgressionRepository(db)
+	weight := 102.5
+	prescription := dto.Prescription{WeightKg: &weight}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`EXISTS \(SELECT 1 FROM "gym-1".custom_member_workout WHERE workout_instance_id = \$1 AND id <> \$2\)`).
+		WithArgs("instance-1", "mw-9").
+		WillReturnRows(sqlmock.NewRows([]string{"shared"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO "gym-1".custom_workout_instance`).
+		WithArgs("instance-1", "coach-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("instance-2"))
+	mock.ExpectExec(`INSERT INTO "gym-1".custom_workout_exercise`).
+		WithArgs("instance-1", "instance-2", "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`UPDATE "gym-1".custom_member_workout SET workout_instance_id = \$2`).
+		WithArgs("mw-9", "instance-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "gym-1".workout_schedule_exception`).
+		WithArgs("mw-9", "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "gym-1".custom_workout_exercise e`).
+		WithArgs("we-1", "instance-2", nil, nil, nil, &weight).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	instanceID, copied, err := repo.ApplyPrescriptions("gym-1", "mw-9", "instance-1", map[string]dto.Prescription{"we-1": prescription}, "coach-1")
+	require.NoError(t, err)
+	assert.Equal(t, "instance-2", instanceID)
+	assert.True(t, copied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyPrescriptionsNeedsAScheduledWorkout(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewProgressionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT\s+EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"shared"}).AddRow(false))
+	mock.ExpectQuery(`SELECT status = 'scheduled' FROM "gym-1".custom_member_workout WHERE id = \$1 FOR UPDATE`).
+		WithArgs("mw-9").
+		WillReturnRows(sqlmock.NewRows([]string{"scheduled"}).AddRow(false))
+	mock.ExpectRollback()
+
+	_, _, err := repo.ApplyPrescriptions("gym-1", "mw-9", "instance-1", map[string]dto.Prescription{"we-1": {}}, "coach-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindRecentSessionsGroupsSetsPerWorkout(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewProgressionRepository(db)
+	now := time.Now()
+	columns := []string{"member_workout_id", "exercise_id", "completed_at", "sets", "reps_min", "reps_max", "prescribed_weight",
+		"reps", "weight_kg", "rpe", "completed"}
+
+	mock.ExpectQuery(`WHERE session_rank <= \$3`).
+		WithArgs("member-1", pq.Array([]string{"squat"}), 6).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("mw-2", "squat", now, 3, 8, 12, 100.0, 10, 100.0, 8.0, true).
+			AddRow("mw-2", "squat", now, 3, 8, 12, 100.0, 9, 100.0, nil, true).
+			AddRow("mw-1", "squat", now.Add(-72*time.Hour), 3, 8, 12, 97.5, 12, 97.5, nil, true))
+	sessions, err := repo.FindRecentSessions("gym-1", "member-1", []string{"squat"}, 6)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Len(t, sessions[0].Sets, 2)
+	assert.Equal(t, 8.0, *sessions[0].Sets[0].RPE)
+	assert.Equal(t, 97.5, *sessions[1].Prescribed.WeightKg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
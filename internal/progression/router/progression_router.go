@@ -0,0 +1,18 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewProgressionRouter(handler interfaces.ProgressionHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/rules", handler.ListRules)                                                 // GET /progression/rules
+	r.Put("/rules/{exerciseType}", handler.UpdateRule)                                 // PUT /progression/rules/{exerciseType}
+	r.Delete("/rules/{exerciseType}", handler.ResetRule)                               // DELETE /progression/rules/{exerciseType}, back to the default
+	r.Get("/member/{memberID}/recommendations", handler.GetRecommendations)            // GET /progression/member/{memberID}/recommendations
+	r.Post("/member/{memberID}/recommendations/accept", handler.AcceptRecommendations) // POST /progression/member/{memberID}/recommendations/accept
+	return r
+}
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	exercise_enum "github.com/alejandro-albiol/athenai/internal/exercise/enum"
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/alejandro-albiol/athenai/internal/progression/enum"
+	"github.com/alejandro-albiol/athenai/internal/progression/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+// maxLookback is how many recent sessions of an exercise are read, and so the most misses a rule can wait for
+const maxLookback = 6
+
+// exerciseTypes lists the exercise types in the order rules are listed
+var exerciseTypes = []exercise_enum.ExerciseType{
+	exercise_enum.Strength, exercise_enum.Functional, exercise_enum.Cardio, exercise_enum.Flexibility, exercise_enum.Balance,
+}
+
+// DefaultRule is the rule of an exercise type the gym did not configure: double progression for strength work,
+// small percentage steps for the rest
+func DefaultRule(exerciseType string) *dto.ProgressionRuleDTO {
+	rule := &dto.ProgressionRuleDTO{
+		ExerciseType:      exerciseType,
+		Strategy:          string(enum.Percentage),
+		IncrementPercent:  5,
+		WeightStepKg:      1,
+		MaxRPE:            8,
+		DeloadAfterMisses: 3,
+		DeloadPercent:     10,
+		IsDefault:         true,
+	}
+	switch exercise_enum.ExerciseType(exerciseType) {
+	case exercise_enum.Strength:
+		rule.Strategy, rule.IncrementPercent, rule.WeightStepKg, rule.MaxRPE = string(enum.DoubleProgression), 2.5, 2.5, 9
+	case exercise_enum.Functional:
+		rule.WeightStepKg, rule.MaxRPE = 2, 8.5
+	}
+	return rule
+}
+
+type ProgressionService struct {
+	repository interfaces.ProgressionRepository
+}
+
+func NewProgressionService(repo interfaces.ProgressionRepository) *ProgressionService {
+	return &ProgressionService{repository: repo}
+}
+
+// ListRules lists the rule of every exercise type, the built-in default where the gym has none
+func (s *ProgressionService) ListRules(gymID string) ([]*dto.ProgressionRuleDTO, error) {
+	rules, err := s.rulesByType(gymID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*dto.ProgressionRuleDTO, 0, len(exerciseTypes))
+	for _, exerciseType := range exerciseTypes {
+		list = append(list, rules(string(exerciseType)))
+	}
+	return list, nil
+}
+
+func (s *ProgressionService) rulesByType(gymID string) (func(string) *dto.ProgressionRuleDTO, error) {
+	configured, err := s.repository.FindRules(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get progression rules", err)
+	}
+	return func(exerciseType string) *dto.ProgressionRuleDTO {
+		for _, rule := range configured {
+			if rule.ExerciseType == exerciseType {
+				return rule
+			}
+		}
+		return DefaultRule(exerciseType)
+	}, nil
+}
+
+func (s *ProgressionService) UpdateRule(gymID string, rule *dto.UpdateProgressionRuleDTO) (*dto.ProgressionRuleDTO, error) {
+	badRequest := func(msg string) error { return apierror.New(errorcode_enum.CodeBadRequest, msg, nil) }
+	switch {
+	case !exercise_enum.ExerciseType(rule.ExerciseType).IsValid():
+		return nil, badRequest("Exercise type must be one of strength, cardio, flexibility, balance or functional")
+	case !enum.Strategy(rule.Strategy).IsValid():
+		return nil, badRequest("strategy must be double_progression or percentage")
+	case rule.IncrementPercent <= 0 || rule.IncrementPercent > 50:
+		return nil, badRequest("increment_percent must be greater than 0 and at most 50")
+	case rule.WeightStepKg <= 0 || rule.WeightStepKg > 20:
+		return nil, badRequest("weight_step_kg must be greater than 0 and at most 20")
+	case rule.MaxRPE < 1 || rule.MaxRPE > 10:
+		return nil, badRequest("max_rpe must be between 1 and 10")
+	case rule.DeloadAfterMisses < 1 || rule.DeloadAfterMisses > maxLookback:
+		return nil, badRequest(fmt.Sprintf("deload_after_misses must be between 1 and %d", maxLookback))
+	case rule.DeloadPercent <= 0 || rule.DeloadPercent >= 100:
+		return nil, badRequest("deload_percent must be greater than 0 and less than 100")
+	}
+	saved, err := s.repository.UpsertRule(gymID, rule)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to save progression rule", err)
+	}
+	return saved, nil
+}
+
+// ResetRule goes back to the built-in rule of the exercise type
+func (s *ProgressionService) ResetRule(gymID, exerciseType string) error {
+	if err := s.repository.DeleteRule(gymID, exerciseType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "The gym has no progression rule for this exercise type", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to reset progression rule", err)
+	}
+	return nil
+}
+
+// GetRecommendations proposes targets for each exercise of the member's next scheduled workout from the
+// member's recent completed sessions of it
+func (s *ProgressionService) GetRecommendations(gymID, memberID string) (*dto.RecommendationsDTO, error) {
+	next, err := s.repository.FindNextWorkout(gymID, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "The member has no scheduled workout", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get next workout", err)
+	}
+	exercises, err := s.repository.FindWorkoutExercises(gymID, next.WorkoutInstanceID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout exercises", err)
+	}
+	rules, err := s.rulesByType(gymID)
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseIDs := []string{}
+	for _, exercise := range exercises {
+		if !slices.Contains(exerciseIDs, exercise.ExerciseID) {
+			exerciseIDs = append(exerciseIDs, exercise.ExerciseID)
+		}
+	}
+	sessions := []dto.Session{}
+	if len(exerciseIDs) > 0 {
+		if sessions, err = s.repository.FindRecentSessions(gymID, memberID, exerciseIDs, maxLookback); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get recent sessions", err)
+		}
+	}
+
+	recommendations := &dto.RecommendationsDTO{
+		MemberID:          memberID,
+		MemberWorkoutID:   next.ID,
+		WorkoutInstanceID: next.WorkoutInstanceID,
+		ScheduledDate:     next.ScheduledDate,
+		Exercises:         []*dto.ExerciseRecommendationDTO{},
+	}
+	for _, exercise := range exercises {
+		history := []dto.Session{}
+		for _, session := range sessions {
+			if session.ExerciseID == exercise.ExerciseID {
+				history = append(history, session)
+			}
+		}
+		recommendations.Exercises = append(recommendations.Exercises, Recommend(exercise, history, rules(exercise.ExerciseType)))
+	}
+	return recommendations, nil
+}
+
+// AcceptRecommendations writes the proposals that change something into the member's next scheduled workout
+func (s *ProgressionService) AcceptRecommendations(gymID, memberID string, accept *dto.AcceptRecommendationsDTO) (*dto.AcceptResultDTO, error) {
+	recommendations, err := s.GetRecommendations(gymID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if accept.MemberWorkoutID != "" && accept.MemberWorkoutID != recommendations.MemberWorkoutID {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "The member's next workout changed, reload the recommendations", nil)
+	}
+	for _, id := range accept.WorkoutExerciseIDs {
+		if !slices.ContainsFunc(recommendations.Exercises, func(r *dto.ExerciseRecommendationDTO) bool { return r.WorkoutExerciseID == id }) {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Exercise %s is not part of the next workout", id), nil)
+		}
+	}
+
+	result := &dto.AcceptResultDTO{
+		MemberWorkoutID:   recommendations.MemberWorkoutID,
+		WorkoutInstanceID: recommendations.WorkoutInstanceID,
+		Applied:           []*dto.ExerciseRecommendationDTO{},
+	}
+	prescriptions := map[string]dto.Prescription{}
+	for _, recommendation := range recommendations.Exercises {
+		if len(accept.WorkoutExerciseIDs) > 0 && !slices.Contains(accept.WorkoutExerciseIDs, recommendation.WorkoutExerciseID) {
+			continue
+		}
+		if samePrescription(recommendation.Current, recommendation.Proposed) {
+			continue
+		}
+		prescriptions[recommendation.WorkoutExerciseID] = recommendation.Proposed
+		result.Applied = append(result.Applied, recommendation)
+	}
+	if len(prescriptions) == 0 {
+		return result, nil
+	}
+
+	result.WorkoutInstanceID, result.CopiedInstance, err = s.repository.ApplyPrescriptions(gymID, recommendations.MemberWorkoutID,
+		recommendations.WorkoutInstanceID, prescriptions, accept.AcceptedBy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "The member's next workout changed, reload the recommendations", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to apply recommendations", err)
+	}
+	return result, nil
+}
+
+// Recommend proposes the next-session targets of an exercise from its sessions, newest first. A session misses
+// when fewer sets than prescribed reach the bottom of the rep range; after the rule's misses in a row the load
+// goes down by its deload percentage. Otherwise a session that hit its reps without going past the rule's RPE
+// moves the load up: at once for percentage rules, once every set reaches the top of the range for double
+// progression. Until then double progression raises the bottom of the range to the reps to aim for, and the
+// load increase lowers it back to where the sessions started. Exercises done without weight move the reps up
+// instead.
+func Recommend(exercise dto.WorkoutExercise, sessions []dto.Session, rule *dto.ProgressionRuleDTO) *dto.ExerciseRecommendationDTO {
+	recommendation := &dto.ExerciseRecommendationDTO{
+		WorkoutExerciseID:  exercise.ID,
+		ExerciseSource:     exercise.ExerciseSource,
+		ExerciseID:         exercise.ExerciseID,
+		ExerciseName:       exercise.ExerciseName,
+		ExerciseType:       exercise.ExerciseType,
+		Strategy:           rule.Strategy,
+		Current:            exercise.Prescription,
+		Proposed:           exercise.Prescription,
+		SessionsConsidered: len(sessions),
+	}
+	outcomes := []sessionOutcome{}
+	for _, session := range sessions {
+		if outcome, ok := evaluate(session); ok {
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	if len(outcomes) == 0 {
+		recommendation.Action = string(enum.NoHistory)
+		recommendation.Reason = "No completed session of this exercise yet; keep the prescription"
+		return recommendation
+	}
+	for _, outcome := range outcomes {
+		if outcome.hit {
+			break
+		}
+		recommendation.ConsecutiveMisses++
+	}
+
+	last := outcomes[0]
+	weight := last.weight
+	if weight == 0 && exercise.WeightKg != nil {
+		weight = *exercise.WeightKg
+	}
+	proposed := &recommendation.Proposed
+	if weight > 0 {
+		proposed.WeightKg = &weight
+	}
+
+	switch {
+	case recommendation.ConsecutiveMisses >= rule.DeloadAfterMisses:
+		recommendation.Action = string(enum.Deload)
+		recommendation.Reason = fmt.Sprintf("Missed the target reps in the last %d sessions; deload by %g%%",
+			recommendation.ConsecutiveMisses, rule.DeloadPercent)
+		if weight > 0 {
+			deloaded := roundToStep(weight*(1-rule.DeloadPercent/100), rule.WeightStepKg)
+			proposed.WeightKg = &deloaded
+		} else {
+			proposed.RepsMin = scaleReps(proposed.RepsMin, 1-rule.DeloadPercent/100)
+			proposed.RepsMax = scaleReps(proposed.RepsMax, 1-rule.DeloadPercent/100)
+		}
+	case !last.hit:
+		recommendation.Action = string(enum.Hold)
+		recommendation.Reason = "Missed the target reps last session; hold the load"
+	case last.maxRPE != nil && *last.maxRPE > rule.MaxRPE:
+		recommendation.Action = string(enum.Hold)
+		recommendation.Reason = fmt.Sprintf("Last session reached RPE %g, above %g; hold the load", *last.maxRPE, rule.MaxRPE)
+	case weight == 0:
+		if proposed.RepsMin == nil && proposed.RepsMax == nil {
+			recommendation.Action = string(enum.Hold)
+			recommendation.Reason = "No load or reps to progress; keep the prescription"
+			break
+		}
+		recommendation.Action = string(enum.IncreaseReps)
+		recommendation.Reason = "Hit the target reps without weight; add a rep"
+		proposed.RepsMin = addRep(proposed.RepsMin)
+		proposed.RepsMax = addRep(proposed.RepsMax)
+	case enum.Strategy(rule.Strategy) == enum.DoubleProgression && !last.top:
+		target := last.minReps + 1
+		if last.ceiling > 0 {
+			target = min(target, last.ceiling)
+		}
+		recommendation.Action = string(enum.IncreaseReps)
+		recommendation.TargetReps = &target
+		if proposed.RepsMin == nil || *proposed.RepsMin < target {
+			proposed.RepsMin = &target
+		}
+		recommendation.Reason = fmt.Sprintf("Hit the range at %g kg; add reps at the same load until every set reaches %d", weight, last.ceiling)
+	default:
+		increment := max(rule.WeightStepKg, roundToStep(weight*rule.IncrementPercent/100, rule.WeightStepKg))
+		increased := round(weight + increment)
+		proposed.WeightKg = &increased
+		recommendation.Action = string(enum.IncreaseWeight)
+		if enum.Strategy(rule.Strategy) == enum.DoubleProgression && last.ceiling > 0 {
+			recommendation.Reason = fmt.Sprintf("Every set reached %d reps; add %g kg and work back up the range", last.ceiling, increment)
+			bottom := last.floor
+			for _, outcome := range outcomes {
+				bottom = min(bottom, outcome.floor)
+			}
+			if proposed.RepsMin != nil && bottom < *proposed.RepsMin {
+				proposed.RepsMin = &bottom
+			}
+		} else {
+			recommendation.Reason = fmt.Sprintf("Hit the target reps; add %g kg", increment)
+		}
+	}
+	return recommendation
+}
+
+type sessionOutcome struct {
+	hit     bool // enough sets reached the bottom of the rep range
+	top     bool // enough sets reached the top of the rep range
+	weight  float64
+	minReps int
+	floor   int // bottom of the prescribed rep range
+	ceiling int
+	maxRPE  *float64
+}
+
+// evaluate sums up a session against what it prescribed; sessions without reps logged are left out
+func evaluate(session dto.Session) (sessionOutcome, bool) {
+	floor, ceiling := 1, 0
+	if session.Prescribed.RepsMin != nil {
+		floor = *session.Prescribed.RepsMin
+	} else if session.Prescribed.RepsMax != nil {
+		floor = *session.Prescribed.RepsMax
+	}
+	if session.Prescribed.RepsMax != nil {
+		ceiling = *session.Prescribed.RepsMax
+	}
+
+	outcome := sessionOutcome{floor: floor, ceiling: ceiling}
+	logged, done, atFloor, atCeiling := 0, 0, 0, 0
+	for _, set := range session.Sets {
+		if set.RPE != nil && (outcome.maxRPE == nil || *set.RPE > *outcome.maxRPE) {
+			rpe := *set.RPE
+			outcome.maxRPE = &rpe
+		}
+		if set.Reps == nil {
+			continue
+		}
+		logged++
+		if !set.Completed {
+			continue
+		}
+		reps := *set.Reps
+		if done++; done == 1 || reps < outcome.minReps {
+			outcome.minReps = reps
+		}
+		if set.WeightKg != nil && *set.WeightKg > outcome.weight {
+			outcome.weight = *set.WeightKg
+		}
+		if reps >= floor {
+			atFloor++
+		}
+		if ceiling == 0 || reps >= ceiling {
+			atCeiling++
+		}
+	}
+	if logged == 0 {
+		return outcome, false
+	}
+	required := logged
+	if session.Prescribed.Sets != nil && *session.Prescribed.Sets > 0 {
+		required = *session.Prescribed.Sets
+	}
+	outcome.hit = atFloor >= required
+	outcome.top = outcome.hit && atCeiling >= required
+	return outcome, true
+}
+
+func samePrescription(a, b dto.Prescription) bool {
+	sameInt := func(x, y *int) bool { return (x == nil && y == nil) || (x != nil && y != nil && *x == *y) }
+	sameWeight := (a.WeightKg == nil && b.WeightKg == nil) || (a.WeightKg != nil && b.WeightKg != nil && *a.WeightKg == *b.WeightKg)
+	return sameInt(a.Sets, b.Sets) && sameInt(a.RepsMin, b.RepsMin) && sameInt(a.RepsMax, b.RepsMax) && sameWeight
+}
+
+// scaleReps scales reps by factor, keeping at least one
+func scaleReps(reps *int, factor float64) *int {
+	if reps == nil {
+		return nil
+	}
+	scaled := max(1, int(math.Round(float64(*reps)*factor)))
+	return &scaled
+}
+
+func addRep(reps *int) *int {
+	if reps == nil {
+		return nil
+	}
+	added := *reps + 1
+	return &added
+}
+
+// roundToStep rounds a load to the nearest step, as plates come in steps
+func roundToStep(weight, step float64) float64 {
+	return round(math.Round(weight/step) * step)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/progression/dto"
+	"github.com/alejandro-albiol/athenai/internal/progression/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func prescription(sets, repsMin, repsMax int, weight float64) dto.Prescription {
+	return dto.Prescription{Sets: intPtr(sets), RepsMin: intPtr(repsMin), RepsMax: intPtr(repsMax), WeightKg: floatPtr(weight)}
+}
+
+// session logs sets of the given reps at a weight, with an optional RPE for every set
+func session(id string, prescribed dto.Prescription, weight float64, rpe float64, reps ...int) dto.Session {
+	s := dto.Session{MemberWorkoutID: id, ExerciseID: "squat", Prescribed: prescribed}
+	for _, r := range reps {
+		set := dto.SetPerformance{Reps: intPtr(r), WeightKg: floatPtr(weight), Completed: true}
+		if rpe > 0 {
+			set.RPE = floatPtr(rpe)
+		}
+		s.Sets = append(s.Sets, set)
+	}
+	return s
+}
+
+var squat = dto.WorkoutExercise{ID: "we-1", ExerciseSource: "public", ExerciseID: "squat", ExerciseType: "strength",
+	Prescription: prescription(3, 8, 12, 100)}
+
+func TestDoubleProgressionAddsRepsThenLoad(t *testing.T) {
+	rule := DefaultRule("strength")
+
+	rec := Recommend(squat, []dto.Session{session("w1", squat.Prescription, 100, 8, 10, 9, 9)}, rule)
+	assert.Equal(t, "increase_reps", rec.Action)
+	assert.Equal(t, 10, *rec.TargetReps)
+	assert.Equal(t, 10, *rec.Proposed.RepsMin)
+	assert.Equal(t, 100.0, *rec.Proposed.WeightKg)
+
+	rec = Recommend(squat, []dto.Session{session("w1", squat.Prescription, 100, 8, 12, 12, 12)}, rule)
+	assert.Equal(t, "increase_weight", rec.Action)
+	assert.Equal(t, 102.5, *rec.Proposed.WeightKg) // 2.5% of 100, rounded to the 2.5 kg step
+	assert.Equal(t, 8, *rec.Proposed.RepsMin)
+
+	// A grinding session holds the load even at the top of the range
+	rec = Recommend(squat, []dto.Session{session("w1", squat.Prescription, 100, 9.5, 12, 12, 12)}, rule)
+	assert.Equal(t, "hold", rec.Action)
+}
+
+func TestPercentageRuleRoundsToTheStep(t *testing.T) {
+	rule := DefaultRule("strength")
+	rule.Strategy, rule.IncrementPercent = "percentage", 5
+	press := dto.WorkoutExercise{ID: "we-2", ExerciseID: "press", Prescription: prescription(5, 5, 5, 61)}
+
+	rec := Recommend(press, []dto.Session{session("w1", press.Prescription, 61, 0, 5, 5, 5, 5, 5)}, rule)
+	assert.Equal(t, "increase_weight", rec.Action)
+	assert.Equal(t, 63.5, *rec.Proposed.WeightKg) // 61 + 3.05 rounded to 2.5 kg
+}
+
+func TestDeloadAfterRepeatedMisses(t *testing.T) {
+	rule := DefaultRule("strength")
+	missed := []dto.Session{
+		session("w3", squat.Prescription, 110, 9, 8, 7, 6),
+		session("w2", squat.Prescription, 110, 9, 8, 8), // only two of three sets
+		session("w1", squat.Prescription, 110, 9, 7, 7, 7),
+		session("w0", squat.Prescription, 107.5, 8, 12, 12, 12),
+	}
+
+	rec := Recommend(squat, missed[1:], rule)
+	assert.Equal(t, "hold", rec.Action)
+	assert.Equal(t, 2, rec.ConsecutiveMisses)
+	assert.Equal(t, 110.0, *rec.Proposed.WeightKg)
+
+	rec = Recommend(squat, missed, rule)
+	assert.Equal(t, "deload", rec.Action)
+	assert.Equal(t, 3, rec.ConsecutiveMisses)
+	assert.Equal(t, 100.0, *rec.Proposed.WeightKg) // 110 less 10%, rounded to 2.5 kg
+}
+
+func TestBodyweightExercisesProgressReps(t *testing.T) {
+	pullup := dto.WorkoutExercise{ID: "we-3", ExerciseID: "pullup", Prescription: dto.Prescription{Sets: intPtr(3), RepsMin: intPtr(6), RepsMax: intPtr(8)}}
+	s := dto.Session{MemberWorkoutID: "w1", ExerciseID: "pullup", Prescribed: pullup.Prescription}
+	for range 3 {
+		s.Sets = append(s.Sets, dto.SetPerformance{Reps: intPtr(8), Completed: true})
+	}
+
+	rec := Recommend(pullup, []dto.Session{s}, DefaultRule("strength"))
+	assert.Equal(t, "increase_reps", rec.Action)
+	assert.Equal(t, 7, *rec.Proposed.RepsMin)
+	assert.Equal(t, 9, *rec.Proposed.RepsMax)
+	assert.Nil(t, rec.Proposed.WeightKg)
+
+	rec = Recommend(pullup, nil, DefaultRule("strength"))
+	assert.Equal(t, "no_history", rec.Action)
+	assert.Equal(t, rec.Current, rec.Proposed)
+}
+
+type mockRepo struct {
+	interfaces.ProgressionRepository
+	rules     []*dto.ProgressionRuleDTO
+	sessions  []dto.Session
+	exercises []dto.WorkoutExercise
+	applied   map[string]dto.Prescription
+}
+
+func (m *mockRepo) FindRules(gymID string) ([]*dto.ProgressionRuleDTO, error) { return m.rules, nil }
+func (m *mockRepo) FindNextWorkout(gymID, memberID string) (*dto.NextWorkout, error) {
+	if memberID != "member-1" {
+		return nil, sql.ErrNoRows
+	}
+	return &dto.NextWorkout{ID: "mw-9", WorkoutInstanceID: "instance-1", ScheduledDate: "2026-10-21"}, nil
+}
+func (m *mockRepo) FindWorkoutExercises(gymID, instanceID string) ([]dto.WorkoutExercise, error) {
+	if m.exercises != nil {
+		return m.exercises, nil
+	}
+	bench := dto.WorkoutExercise{ID: "we-2", ExerciseID: "bench", ExerciseType: "strength", Prescription: prescription(3, 8, 12, 60)}
+	return []dto.WorkoutExercise{squat, bench}, nil
+}
+func (m *mockRepo) FindRecentSessions(gymID, memberID string, exerciseIDs []string, perExercise int) ([]dto.Session, error) {
+	return m.sessions, nil
+}
+func (m *mockRepo) ApplyPrescriptions(gymID, memberWorkoutID, instanceID string, prescriptions map[string]dto.Prescription, acceptedBy string) (string, bool, error) {
+	m.applied = prescriptions
+	return "instance-2", true, nil
+}
+
+func TestAcceptAppliesOnlyTheChangedProposals(t *testing.T) {
+	repo := &mockRepo{sessions: []dto.Session{session("w1", squat.Prescription, 100, 8, 12, 12, 12)}}
+	svc := NewProgressionService(repo)
+
+	result, err := svc.AcceptRecommendations("gym", "member-1", &dto.AcceptRecommendationsDTO{MemberWorkoutID: "mw-9", AcceptedBy: "coach-1"})
+	require.NoError(t, err)
+	assert.True(t, result.CopiedInstance)
+	assert.Equal(t, "instance-2", result.WorkoutInstanceID)
+	require.Len(t, result.Applied, 1) // bench has no history
+	assert.Equal(t, 102.5, *repo.applied["we-1"].WeightKg)
+
+	_, err = svc.AcceptRecommendations("gym", "member-1", &dto.AcceptRecommendationsDTO{MemberWorkoutID: "mw-1"})
+	assert.Equal(t, errorcode_enum.CodeConflict, err.(*apierror.APIError).Code)
+	_, err = svc.AcceptRecommendations("gym", "member-1", &dto.AcceptRecommendationsDTO{WorkoutExerciseIDs: []string{"we-7"}})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+	_, err = svc.GetRecommendations("gym", "member-2")
+	assert.Equal(t, errorcode_enum.CodeNotFound, err.(*apierror.APIError).Code)
+}
+
+func TestAcceptAppliesADoubleProgressionRepIncrease(t *testing.T) {
+	raised := prescription(3, 11, 12, 100)
+	repo := &mockRepo{sessions: []dto.Session{
+		session("w2", raised, 100, 8, 11, 11, 11),
+		session("w1", squat.Prescription, 100, 8, 11, 10, 10),
+	}}
+	svc := NewProgressionService(repo)
+
+	result, err := svc.AcceptRecommendations("gym", "member-1", &dto.AcceptRecommendationsDTO{WorkoutExerciseIDs: []string{"we-1"}})
+	require.NoError(t, err)
+	require.Len(t, result.Applied, 1)
+	assert.Equal(t, "increase_reps", result.Applied[0].Action)
+	assert.Equal(t, 12, *repo.applied["we-1"].RepsMin)
+	assert.Equal(t, 12, *repo.applied["we-1"].RepsMax)
+	assert.Equal(t, 100.0, *repo.applied["we-1"].WeightKg)
+
+	// Reaching the top adds load and goes back to the bottom the sessions started from
+	top := prescription(3, 12, 12, 100)
+	repo.exercises = []dto.WorkoutExercise{{ID: "we-1", ExerciseID: "squat", ExerciseType: "strength", Prescription: top}}
+	repo.sessions = append([]dto.Session{session("w3", top, 100, 8, 12, 12, 12)}, repo.sessions...)
+	_, err = svc.AcceptRecommendations("gym", "member-1", &dto.AcceptRecommendationsDTO{WorkoutExerciseIDs: []string{"we-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, 8, *repo.applied["we-1"].RepsMin)
+	assert.Equal(t, 102.5, *repo.applied["we-1"].WeightKg)
+}
+
+func TestRulesFallBackToDefaults(t *testing.T) {
+	svc := NewProgressionService(&mockRepo{rules: []*dto.ProgressionRuleDTO{{ExerciseType: "functional", Strategy: "double_progression"}}})
+	rules, err := svc.ListRules("gym")
+	require.NoError(t, err)
+	require.Len(t, rules, 5)
+	assert.Equal(t, "strength", rules[0].ExerciseType)
+	assert.True(t, rules[0].IsDefault)
+	assert.Equal(t, "double_progression", rules[1].Strategy)
+	assert.False(t, rules[1].IsDefault)
+
+	_, err = svc.UpdateRule("gym", &dto.UpdateProgressionRuleDTO{ExerciseType: "strength", Strategy: "percentage", IncrementPercent: 5,
+		WeightStepKg: 2.5, MaxRPE: 9, DeloadAfterMisses: 10, DeloadPercent: 10})
+	assert.Equal(t, errorcode_enum.CodeBadRequest, err.(*apierror.APIError).Code)
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RevisionCreationDTO is one revision to append to an entity's history
+type RevisionCreationDTO struct {
+	EntityType    string
+	EntityID      string
+	Action        string
+	Snapshot      json.RawMessage
+	ChangedFields []string
+	AuthorID      *string
+	RestoredFrom  *int
+}
+
+type RevisionDTO struct {
+	ID             string          `json:"id"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       string          `json:"entity_id"`
+	RevisionNumber int             `json:"revision_number"`
+	Action         string          `json:"action"`
+	Snapshot       json.RawMessage `json:"snapshot"`
+	ChangedFields  []string        `json:"changed_fields"`
+	AuthorID       *string         `json:"author_id,omitempty"`
+	RestoredFrom   *int            `json:"restored_from,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// RevisionDiff lists the snapshot fields that differ between two revisions of an entity
+type RevisionDiff struct {
+	EntityType string        `json:"entity_type"`
+	EntityID   string        `json:"entity_id"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
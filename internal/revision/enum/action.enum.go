@@ -0,0 +1,19 @@
+package enum
+
+// Action records how a revision came to be
+type Action string
+
+const (
+	// Baseline is the state before the first tracked update, so it can be restored too
+	Baseline Action = "baseline"
+	Update   Action = "update"
+	Restore  Action = "restore"
+)
+
+func (a Action) IsValid() bool {
+	switch a {
+	case Baseline, Update, Restore:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package enum
+
+type EntityType string
+
+const (
+	Exercise        EntityType = "exercise"
+	WorkoutTemplate EntityType = "workout_template"
+	TemplateBlock   EntityType = "template_block"
+)
+
+func (e EntityType) IsValid() bool {
+	switch e {
+	case Exercise, WorkoutTemplate, TemplateBlock:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type RevisionHandler struct {
+	service interfaces.RevisionService
+}
+
+func NewRevisionHandler(service interfaces.RevisionService) *RevisionHandler {
+	return &RevisionHandler{service: service}
+}
+
+func (h *RevisionHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	revisions, err := h.service.ListRevisions(chi.URLParam(r, "entityType"), chi.URLParam(r, "entityID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get revisions")
+		return
+	}
+	response.WriteAPISuccess(w, "Revisions retrieved successfully", revisions)
+}
+
+func (h *RevisionHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	number, ok := revisionNumber(w, chi.URLParam(r, "revision"))
+	if !ok {
+		return
+	}
+	revision, err := h.service.GetRevision(chi.URLParam(r, "entityType"), chi.URLParam(r, "entityID"), number)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get revision")
+		return
+	}
+	response.WriteAPISuccess(w, "Revision retrieved successfully", revision)
+}
+
+func (h *RevisionHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	from, ok := revisionNumber(w, query.Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := revisionNumber(w, query.Get("to"))
+	if !ok {
+		return
+	}
+	diff, err := h.service.DiffRevisions(chi.URLParam(r, "entityType"), chi.URLParam(r, "entityID"), from, to)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to compare revisions")
+		return
+	}
+	response.WriteAPISuccess(w, "Revisions compared successfully", diff)
+}
+
+func (h *RevisionHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	number, ok := revisionNumber(w, chi.URLParam(r, "revision"))
+	if !ok {
+		return
+	}
+	revision, err := h.service.RestoreRevision(chi.URLParam(r, "entityType"), chi.URLParam(r, "entityID"), number, middleware.GetUserID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to restore revision")
+		return
+	}
+	response.WriteAPISuccess(w, "Revision restored successfully", revision)
+}
+
+// revisionNumber writes a 400 unless value is a positive revision number
+func revisionNumber(w http.ResponseWriter, value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Revision must be a positive number", err))
+		return 0, false
+	}
+	return number, true
+}
+
+// requirePlatformAdmin writes a 403 unless the caller administers the public catalog
+func requirePlatformAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsPlatformAdmin(r) {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only platform administrators can manage revisions", nil))
+	return false
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/alejandro-albiol/athenai/internal/revision/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/revision/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.RevisionService
+	diffed   []int
+	restored string
+}
+
+func (m *mockService) DiffRevisions(entityType, entityID string, from, to int) (*dto.RevisionDiff, error) {
+	m.diffed = []int{from, to}
+	return &dto.RevisionDiff{EntityType: entityType, EntityID: entityID, From: from, To: to, Changes: []dto.FieldChange{}}, nil
+}
+func (m *mockService) RestoreRevision(entityType, entityID string, revisionNumber int, authorID string) (*dto.RevisionDTO, error) {
+	m.restored = authorID
+	return &dto.RevisionDTO{EntityType: entityType, EntityID: entityID, RevisionNumber: revisionNumber + 1}, nil
+}
+
+func serve(svc *mockService, method, target, userType string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewRevisionRouter(NewRevisionHandler(svc)),
+		testutil.Caller{UserType: userType, Role: "admin", UserID: "admin-1"}, method, target, "")
+}
+
+func TestRestoreRevisionRequiresPlatformAdmin(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/exercise/squat/2/restore", "tenant_user")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, svc.restored)
+
+	w = serve(svc, http.MethodPost, "/exercise/squat/2/restore", "platform_admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin-1", svc.restored)
+}
+
+func TestDiffRevisionsParsesRange(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/workout_template/t1/diff?from=1&to=3", "platform_admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int{1, 3}, svc.diffed)
+
+	svc.diffed = nil
+	w = serve(svc, http.MethodGet, "/workout_template/t1/diff?from=0&to=x", "platform_admin")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, svc.diffed)
+}
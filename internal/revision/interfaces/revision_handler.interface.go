@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type RevisionHandler interface {
+	ListRevisions(w http.ResponseWriter, r *http.Request)
+	GetRevision(w http.ResponseWriter, r *http.Request)
+	DiffRevisions(w http.ResponseWriter, r *http.Request)
+	RestoreRevision(w http.ResponseWriter, r *http.Request)
+}
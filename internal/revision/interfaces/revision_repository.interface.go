@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+)
+
+// RevisionRepository only appends; revisions are never updated or deleted.
+// Create and FindLatest run in the transaction that changed the entity.
+type RevisionRepository interface {
+	Create(tx *sql.Tx, revision *dto.RevisionCreationDTO) (*dto.RevisionDTO, error)
+	FindByEntity(entityType, entityID string) ([]*dto.RevisionDTO, error)
+	FindByNumber(entityType, entityID string, revisionNumber int) (*dto.RevisionDTO, error)
+	FindLatest(tx *sql.Tx, entityType, entityID string) (*dto.RevisionDTO, error)
+}
@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/alejandro-albiol/athenai/internal/revision/enum"
+)
+
+type RevisionService interface {
+	Recorder
+
+	// RegisterRestorer makes revisions of the entity type restorable
+	RegisterRestorer(entityType enum.EntityType, restorer Restorer)
+	ListRevisions(entityType, entityID string) ([]*dto.RevisionDTO, error)
+	GetRevision(entityType, entityID string, revisionNumber int) (*dto.RevisionDTO, error)
+	DiffRevisions(entityType, entityID string, from, to int) (*dto.RevisionDiff, error)
+	RestoreRevision(entityType, entityID string, revisionNumber int, authorID string) (*dto.RevisionDTO, error)
+}
+
+// Recorder appends a revision inside the transaction that updated a versioned entity, so the change and its
+// revision commit or roll back together. before and after are the entity's snapshots; before is only stored
+// for the first tracked update.
+type Recorder interface {
+	Record(tx *sql.Tx, entityType enum.EntityType, entityID string, before, after any, authorID string) error
+}
+
+// Restorer writes a stored snapshot back to its entity without recording a revision itself. It calls record
+// inside the restore's transaction with the entity's snapshots from before and after the restore.
+type Restorer interface {
+	RestoreSnapshot(entityID string, snapshot json.RawMessage, record func(tx *sql.Tx, before, after any) error) error
+}
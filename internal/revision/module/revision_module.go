@@ -0,0 +1,25 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	exerciseModule "github.com/alejandro-albiol/athenai/internal/exercise/module"
+	"github.com/alejandro-albiol/athenai/internal/revision/enum"
+	"github.com/alejandro-albiol/athenai/internal/revision/handler"
+	"github.com/alejandro-albiol/athenai/internal/revision/repository"
+	"github.com/alejandro-albiol/athenai/internal/revision/router"
+	"github.com/alejandro-albiol/athenai/internal/revision/service"
+	templateBlockModule "github.com/alejandro-albiol/athenai/internal/template_block/module"
+	workoutTemplateModule "github.com/alejandro-albiol/athenai/internal/workout_template/module"
+)
+
+func NewRevisionModule(db *sql.DB) http.Handler {
+	repo := repository.NewRevisionRepository(db)
+	service := service.NewRevisionService(repo)
+	service.RegisterRestorer(enum.Exercise, exerciseModule.NewExerciseService(db))
+	service.RegisterRestorer(enum.WorkoutTemplate, workoutTemplateModule.NewWorkoutTemplateService(db))
+	service.RegisterRestorer(enum.TemplateBlock, templateBlockModule.NewTemplateBlockService(db))
+	handler := handler.NewRevisionHandler(service)
+	return router.NewRevisionRouter(handler)
+}
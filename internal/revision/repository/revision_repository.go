@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/lib/pq"
+)
+
+type RevisionRepository struct {
+	db *sql.DB
+}
+
+func NewRevisionRepository(db *sql.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+const selectRevisions = `SELECT id, entity_type, entity_id, revision_number, action, snapshot, changed_fields,
+		author_id, restored_from, created_at
+		FROM public.revision`
+
+// Create numbers the revision after the entity's latest one; the unique constraint rejects a concurrent writer
+func (r *RevisionRepository) Create(tx *sql.Tx, revision *dto.RevisionCreationDTO) (*dto.RevisionDTO, error) {
+	query := `INSERT INTO public.revision
+		(entity_type, entity_id, revision_number, action, snapshot, changed_fields, author_id, restored_from)
+		SELECT $1, $2, COALESCE(MAX(revision_number), 0) + 1, $3, $4, $5, $6, $7
+		FROM public.revision WHERE entity_type = $1 AND entity_id = $2
+		RETURNING ` + returningColumns
+	changed := revision.ChangedFields
+	if changed == nil {
+		changed = []string{}
+	}
+	return scanRevision(tx.QueryRow(query, revision.EntityType, revision.EntityID, revision.Action,
+		[]byte(revision.Snapshot), pq.Array(changed), revision.AuthorID, revision.RestoredFrom))
+}
+
+const returningColumns = `id, entity_type, entity_id, revision_number, action, snapshot, changed_fields, author_id, restored_from, created_at`
+
+func (r *RevisionRepository) FindByEntity(entityType, entityID string) ([]*dto.RevisionDTO, error) {
+	rows, err := r.db.Query(selectRevisions+" WHERE entity_type = $1 AND entity_id = $2 ORDER BY revision_number DESC", entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*dto.RevisionDTO
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *RevisionRepository) FindByNumber(entityType, entityID string, revisionNumber int) (*dto.RevisionDTO, error) {
+	return scanRevision(r.db.QueryRow(selectRevisions+" WHERE entity_type = $1 AND entity_id = $2 AND revision_number = $3",
+		entityType, entityID, revisionNumber))
+}
+
+// FindLatest returns nil without error when the entity has no history yet
+func (r *RevisionRepository) FindLatest(tx *sql.Tx, entityType, entityID string) (*dto.RevisionDTO, error) {
+	revision, err := scanRevision(tx.QueryRow(selectRevisions+" WHERE entity_type = $1 AND entity_id = $2 ORDER BY revision_number DESC LIMIT 1",
+		entityType, entityID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return revision, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRevision(row rowScanner) (*dto.RevisionDTO, error) {
+	var revision dto.RevisionDTO
+	var snapshot []byte
+	var restoredFrom sql.NullInt64
+	err := row.Scan(&revision.ID, &revision.EntityType, &revision.EntityID, &revision.RevisionNumber, &revision.Action,
+		&snapshot, pq.Array(&revision.ChangedFields), &revision.AuthorID, &restoredFrom, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.Snapshot = snapshot
+	if restoredFrom.Valid {
+		from := int(restoredFrom.Int64)
+		revision.RestoredFrom = &from
+	}
+	if revision.ChangedFields == nil {
+		revision.ChangedFields = []string{}
+	}
+	return &revision, nil
+}
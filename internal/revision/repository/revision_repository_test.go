@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var revisionColumns = []string{"id", "entity_type", "entity_id", "revision_number", "action", "snapshot", "changed_fields", "author_id", "restored_from", "created_at"}
+
+func TestCreateNumbersAfterLatest(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewRevisionRepository(db)
+	author := "admin-1"
+	from := 2
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT $1, $2, COALESCE(MAX(revision_number), 0) + 1`)).
+		WithArgs("exercise", "squat", "restore", []byte(`{"name":"Squat"}`), sqlmock.AnyArg(), &author, &from).
+		WillReturnRows(sqlmock.NewRows(revisionColumns).
+			AddRow("revision-4", "exercise", "squat", 4, "restore", []byte(`{"name": "Squat"}`), "{name}", author, 2, time.Now()))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	revision, err := repo.Create(tx, &dto.RevisionCreationDTO{
+		EntityType:    "exercise",
+		EntityID:      "squat",
+		Action:        "restore",
+		Snapshot:      json.RawMessage(`{"name":"Squat"}`),
+		ChangedFields: []string{"name"},
+		AuthorID:      &author,
+		RestoredFrom:  &from,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, revision.RevisionNumber)
+	assert.Equal(t, []string{"name"}, revision.ChangedFields)
+	assert.Equal(t, 2, *revision.RestoredFrom)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindLatestWithoutHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewRevisionRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM public.revision WHERE entity_type = \$1 AND entity_id = \$2 ORDER BY revision_number DESC LIMIT 1`).
+		WithArgs("template_block", "b1").
+		WillReturnError(sql.ErrNoRows)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	revision, err := repo.FindLatest(tx, "template_block", "b1")
+	require.NoError(t, err)
+	assert.Nil(t, revision)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindByEntity(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewRevisionRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM public.revision WHERE entity_type = \$1 AND entity_id = \$2 ORDER BY revision_number DESC`).
+		WithArgs("workout_template", "t1").
+		WillReturnRows(sqlmock.NewRows(revisionColumns).
+			AddRow("revision-2", "workout_template", "t1", 2, "update", []byte(`{}`), "{description}", nil, nil, now).
+			AddRow("revision-1", "workout_template", "t1", 1, "baseline", []byte(`{}`), "{}", nil, nil, now))
+
+	revisions, err := repo.FindByEntity("workout_template", "t1")
+	require.NoError(t, err)
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, []string{"description"}, revisions[0].ChangedFields)
+		assert.Nil(t, revisions[0].AuthorID)
+		assert.Nil(t, revisions[0].RestoredFrom)
+		assert.Equal(t, []string{}, revisions[1].ChangedFields)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewRevisionRouter(handler interfaces.RevisionHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/{entityType}/{entityID}", handler.ListRevisions)                       // GET /revision/{entityType}/{entityID}
+	r.Get("/{entityType}/{entityID}/diff", handler.DiffRevisions)                  // GET /revision/{entityType}/{entityID}/diff?from=1&to=3
+	r.Get("/{entityType}/{entityID}/{revision}", handler.GetRevision)              // GET /revision/{entityType}/{entityID}/{revision}
+	r.Post("/{entityType}/{entityID}/{revision}/restore", handler.RestoreRevision) // POST /revision/{entityType}/{entityID}/{revision}/restore
+
+	return r
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/alejandro-albiol/athenai/internal/revision/enum"
+	"github.com/alejandro-albiol/athenai/internal/revision/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type RevisionService struct {
+	repo      interfaces.RevisionRepository
+	restorers map[enum.EntityType]interfaces.Restorer
+}
+
+func NewRevisionService(repo interfaces.RevisionRepository) *RevisionService {
+	return &RevisionService{repo: repo, restorers: map[enum.EntityType]interfaces.Restorer{}}
+}
+
+func (s *RevisionService) RegisterRestorer(entityType enum.EntityType, restorer interfaces.Restorer) {
+	s.restorers[entityType] = restorer
+}
+
+func (s *RevisionService) Record(tx *sql.Tx, entityType enum.EntityType, entityID string, before, after any, authorID string) error {
+	_, err := s.record(tx, entityType, entityID, before, after, authorID, enum.Update, nil)
+	return err
+}
+
+// record stores the baseline on the entity's first tracked change, then the new state
+// with the fields that differ from the latest stored snapshot
+func (s *RevisionService) record(tx *sql.Tx, entityType enum.EntityType, entityID string, before, after any, authorID string, action enum.Action, restoredFrom *int) (*dto.RevisionDTO, error) {
+	afterSnapshot, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.FindLatest(tx, string(entityType), entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous json.RawMessage
+	if latest != nil {
+		previous = latest.Snapshot
+	} else {
+		if previous, err = json.Marshal(before); err != nil {
+			return nil, err
+		}
+		if _, err := s.repo.Create(tx, &dto.RevisionCreationDTO{
+			EntityType: string(entityType),
+			EntityID:   entityID,
+			Action:     string(enum.Baseline),
+			Snapshot:   previous,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := diffSnapshots(previous, afterSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	changedFields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		changedFields = append(changedFields, change.Field)
+	}
+
+	var author *string
+	if authorID != "" {
+		author = &authorID
+	}
+	return s.repo.Create(tx, &dto.RevisionCreationDTO{
+		EntityType:    string(entityType),
+		EntityID:      entityID,
+		Action:        string(action),
+		Snapshot:      afterSnapshot,
+		ChangedFields: changedFields,
+		AuthorID:      author,
+		RestoredFrom:  restoredFrom,
+	})
+}
+
+func (s *RevisionService) ListRevisions(entityType, entityID string) ([]*dto.RevisionDTO, error) {
+	if !enum.EntityType(entityType).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid entity type", nil)
+	}
+	revisions, err := s.repo.FindByEntity(entityType, entityID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get revisions", err)
+	}
+	if revisions == nil {
+		revisions = []*dto.RevisionDTO{}
+	}
+	return revisions, nil
+}
+
+func (s *RevisionService) GetRevision(entityType, entityID string, revisionNumber int) (*dto.RevisionDTO, error) {
+	if !enum.EntityType(entityType).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid entity type", nil)
+	}
+	revision, err := s.repo.FindByNumber(entityType, entityID, revisionNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("Revision %d not found", revisionNumber), err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get revision", err)
+	}
+	return revision, nil
+}
+
+func (s *RevisionService) DiffRevisions(entityType, entityID string, from, to int) (*dto.RevisionDiff, error) {
+	fromRevision, err := s.GetRevision(entityType, entityID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(entityType, entityID, to)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffSnapshots(fromRevision.Snapshot, toRevision.Snapshot)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to compare revisions", err)
+	}
+	return &dto.RevisionDiff{
+		EntityType: entityType,
+		EntityID:   entityID,
+		From:       from,
+		To:         to,
+		Changes:    changes,
+	}, nil
+}
+
+// RestoreRevision writes the revision's snapshot back to the entity and records that as a new revision,
+// so history stays append-only and a restore can itself be rolled back
+func (s *RevisionService) RestoreRevision(entityType, entityID string, revisionNumber int, authorID string) (*dto.RevisionDTO, error) {
+	revision, err := s.GetRevision(entityType, entityID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	restorer, ok := s.restorers[enum.EntityType(entityType)]
+	if !ok {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Revisions of %s cannot be restored", entityType), nil)
+	}
+
+	var restored *dto.RevisionDTO
+	err = restorer.RestoreSnapshot(entityID, revision.Snapshot, func(tx *sql.Tx, before, after any) error {
+		var err error
+		restored, err = s.record(tx, enum.EntityType(entityType), entityID, before, after, authorID, enum.Restore, &revisionNumber)
+		return err
+	})
+	if err != nil {
+		var apiErr *apierror.APIError
+		if errors.As(err, &apiErr) {
+			return nil, apiErr
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, fmt.Sprintf("Failed to restore revision %d", revisionNumber), err)
+	}
+	return restored, nil
+}
+
+// diffSnapshots compares decoded snapshots rather than raw bytes because JSONB
+// does not keep the key order or spacing the snapshot was written with
+func diffSnapshots(from, to json.RawMessage) ([]dto.FieldChange, error) {
+	var fromFields, toFields map[string]any
+	if err := json.Unmarshal(from, &fromFields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &toFields); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]struct{}, len(toFields))
+	for field := range fromFields {
+		fields[field] = struct{}{}
+	}
+	for field := range toFields {
+		fields[field] = struct{}{}
+	}
+
+	changes := []dto.FieldChange{}
+	for field := range fields {
+		if !reflect.DeepEqual(fromFields[field], toFields[field]) {
+			changes = append(changes, dto.FieldChange{Field: field, From: fromFields[field], To: toFields[field]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/revision/dto"
+	"github.com/alejandro-albiol/athenai/internal/revision/enum"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository numbers revisions per entity like the unique constraint on public.revision
+type memoryRepository struct {
+	revisions []*dto.RevisionDTO
+	createErr error
+}
+
+func (m *memoryRepository) Create(tx *sql.Tx, revision *dto.RevisionCreationDTO) (*dto.RevisionDTO, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	number := 1
+	if latest, _ := m.FindLatest(tx, revision.EntityType, revision.EntityID); latest != nil {
+		number = latest.RevisionNumber + 1
+	}
+	created := &dto.RevisionDTO{
+		ID:             fmt.Sprintf("revision-%d", len(m.revisions)+1),
+		EntityType:     revision.EntityType,
+		EntityID:       revision.EntityID,
+		RevisionNumber: number,
+		Action:         revision.Action,
+		Snapshot:       revision.Snapshot,
+		ChangedFields:  revision.ChangedFields,
+		AuthorID:       revision.AuthorID,
+		RestoredFrom:   revision.RestoredFrom,
+		CreatedAt:      time.Now(),
+	}
+	m.revisions = append(m.revisions, created)
+	return created, nil
+}
+func (m *memoryRepository) FindByEntity(entityType, entityID string) ([]*dto.RevisionDTO, error) {
+	var found []*dto.RevisionDTO
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		if m.revisions[i].EntityType == entityType && m.revisions[i].EntityID == entityID {
+			found = append(found, m.revisions[i])
+		}
+	}
+	return found, nil
+}
+func (m *memoryRepository) FindByNumber(entityType, entityID string, revisionNumber int) (*dto.RevisionDTO, error) {
+	for _, revision := range m.revisions {
+		if revision.EntityType == entityType && revision.EntityID == entityID && revision.RevisionNumber == revisionNumber {
+			return revision, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *memoryRepository) FindLatest(tx *sql.Tx, entityType, entityID string) (*dto.RevisionDTO, error) {
+	found, _ := m.FindByEntity(entityType, entityID)
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+type snapshot struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// fakeExercise stands in for the service owning the entity; a failing record rolls the restore back
+type fakeExercise struct {
+	current snapshot
+}
+
+func (f *fakeExercise) RestoreSnapshot(entityID string, raw json.RawMessage, record func(tx *sql.Tx, before, after any) error) error {
+	var restored snapshot
+	if err := json.Unmarshal(raw, &restored); err != nil {
+		return err
+	}
+	if err := record(nil, f.current, restored); err != nil {
+		return err
+	}
+	f.current = restored
+	return nil
+}
+
+func TestRecordStoresBaselineOnFirstUpdate(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRevisionService(repo)
+
+	require.NoError(t, svc.Record(nil, enum.Exercise, "squat", snapshot{"Squat", "beginner"}, snapshot{"Back Squat", "beginner"}, "admin-1"))
+	require.NoError(t, svc.Record(nil, enum.Exercise, "squat", snapshot{"Back Squat", "beginner"}, snapshot{"Back Squat", "advanced"}, ""))
+
+	revisions, err := svc.ListRevisions("exercise", "squat")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	assert.Equal(t, 3, revisions[0].RevisionNumber)
+	assert.Equal(t, []string{"level"}, revisions[0].ChangedFields)
+	assert.Nil(t, revisions[0].AuthorID, "an unknown author is stored as null")
+
+	assert.Equal(t, "update", revisions[1].Action)
+	assert.Equal(t, []string{"name"}, revisions[1].ChangedFields)
+	assert.Equal(t, "admin-1", *revisions[1].AuthorID)
+
+	assert.Equal(t, "baseline", revisions[2].Action)
+	assert.JSONEq(t, `{"name":"Squat","level":"beginner"}`, string(revisions[2].Snapshot))
+}
+
+func TestDiffRevisions(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRevisionService(repo)
+	require.NoError(t, svc.Record(nil, enum.WorkoutTemplate, "t1", snapshot{"Push", "beginner"}, snapshot{"Push Day", "advanced"}, "admin-1"))
+
+	diff, err := svc.DiffRevisions("workout_template", "t1", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []dto.FieldChange{
+		{Field: "level", From: "beginner", To: "advanced"},
+		{Field: "name", From: "Push", To: "Push Day"},
+	}, diff.Changes)
+
+	_, err = svc.DiffRevisions("workout_template", "t1", 1, 9)
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	_, err = svc.DiffRevisions("workout", "t1", 1, 2)
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
+
+func TestRestoreRevisionRecordsRestore(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRevisionService(repo)
+	exercise := &fakeExercise{current: snapshot{"Back Squat", "advanced"}}
+	svc.RegisterRestorer(enum.Exercise, exercise)
+	require.NoError(t, svc.Record(nil, enum.Exercise, "squat", snapshot{"Squat", "beginner"}, exercise.current, "admin-1"))
+
+	restored, err := svc.RestoreRevision("exercise", "squat", 1, "admin-2")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot{"Squat", "beginner"}, exercise.current)
+	assert.Equal(t, 3, restored.RevisionNumber)
+	assert.Equal(t, "restore", restored.Action)
+	assert.Equal(t, 1, *restored.RestoredFrom)
+	assert.Equal(t, []string{"level", "name"}, restored.ChangedFields)
+	assert.Equal(t, "admin-2", *restored.AuthorID)
+}
+
+func TestRestoreRevisionWithoutRestorer(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRevisionService(repo)
+	require.NoError(t, svc.Record(nil, enum.TemplateBlock, "b1", snapshot{"Warmup", ""}, snapshot{"Warm-up", ""}, ""))
+
+	_, err := svc.RestoreRevision("template_block", "b1", 1, "admin-1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	_, err = svc.RestoreRevision("template_block", "b1", 7, "admin-1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	assert.Len(t, repo.revisions, 2, "failed restores leave history untouched")
+}
+
+func TestRestoreRevisionRollsBackWhenRecordingFails(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRevisionService(repo)
+	exercise := &fakeExercise{current: snapshot{"Back Squat", "advanced"}}
+	svc.RegisterRestorer(enum.Exercise, exercise)
+	require.NoError(t, svc.Record(nil, enum.Exercise, "squat", snapshot{"Squat", "beginner"}, exercise.current, "admin-1"))
+
+	repo.createErr = errors.New("revision insert failed")
+	_, err := svc.RestoreRevision("exercise", "squat", 1, "admin-2")
+	testutil.AssertCode(t, err, errorcode_enum.CodeInternal)
+	assert.Equal(t, snapshot{"Back Squat", "advanced"}, exercise.current, "the entity keeps its state when the restore is not recorded")
+	assert.Len(t, repo.revisions, 2)
+}
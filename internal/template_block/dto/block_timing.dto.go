@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/alejandro-albiol/athenai/internal/template_block/enum"
+)
+
+// BlockTiming is the timed format of a template block. Standard blocks leave every field but the mode empty.
+// Empty fields are kept in JSON so snapshots and merges see a parameter being cleared when the mode changes.
+type BlockTiming struct {
+	BlockMode           *string `json:"block_mode"`            // standard, emom, amrap, tabata, for_time or intervals
+	WorkSeconds         *int    `json:"work_seconds"`          // length of an EMOM minute or a work interval
+	IntervalRestSeconds *int    `json:"interval_rest_seconds"` // rest after each work interval
+	Rounds              *int    `json:"rounds"`
+	TimeCapSeconds      *int    `json:"time_cap_seconds"`
+}
+
+// Mode is the block mode, standard when none is set
+func (t BlockTiming) Mode() enum.BlockMode {
+	if t.BlockMode == nil || *t.BlockMode == "" {
+		return enum.Standard
+	}
+	return enum.BlockMode(*t.BlockMode)
+}
+
+// HasTimingFields reports whether any timing parameter besides the mode is set
+func (t BlockTiming) HasTimingFields() bool {
+	return t.WorkSeconds != nil || t.IntervalRestSeconds != nil || t.Rounds != nil || t.TimeCapSeconds != nil
+}
+
+// Normalize sets the mode and its defaults, then checks the parameters match the mode:
+// EMOM takes rounds (minutes of 60 seconds unless work_seconds says otherwise), AMRAP a time cap,
+// Tabata defaults to 8 rounds of 20/10, For-Time takes rounds (1 by default) and an optional cap,
+// and intervals need work, rest and rounds.
+func (t *BlockTiming) Normalize() error {
+	mode := t.Mode()
+	if !mode.IsValid() {
+		return errors.New("block_mode must be one of standard, emom, amrap, tabata, for_time or intervals")
+	}
+	value := string(mode)
+	t.BlockMode = &value
+
+	switch mode {
+	case enum.Standard:
+		if t.HasTimingFields() {
+			return errors.New("standard blocks take no work_seconds, interval_rest_seconds, rounds or time_cap_seconds")
+		}
+		return nil
+	case enum.EMOM:
+		t.WorkSeconds = defaultInt(t.WorkSeconds, 60)
+		if t.Rounds == nil {
+			return errors.New("emom blocks need rounds")
+		}
+		if t.IntervalRestSeconds != nil || t.TimeCapSeconds != nil {
+			return errors.New("emom blocks take no interval_rest_seconds or time_cap_seconds; rest is what is left of each interval")
+		}
+	case enum.AMRAP:
+		if t.TimeCapSeconds == nil {
+			return errors.New("amrap blocks need time_cap_seconds")
+		}
+		if t.WorkSeconds != nil || t.IntervalRestSeconds != nil || t.Rounds != nil {
+			return errors.New("amrap blocks take only time_cap_seconds")
+		}
+	case enum.Tabata:
+		t.WorkSeconds = defaultInt(t.WorkSeconds, 20)
+		t.IntervalRestSeconds = defaultInt(t.IntervalRestSeconds, 10)
+		t.Rounds = defaultInt(t.Rounds, 8)
+		if t.TimeCapSeconds != nil {
+			return errors.New("tabata blocks take no time_cap_seconds")
+		}
+	case enum.ForTime:
+		t.Rounds = defaultInt(t.Rounds, 1)
+		if t.WorkSeconds != nil || t.IntervalRestSeconds != nil {
+			return errors.New("for_time blocks take no work_seconds or interval_rest_seconds")
+		}
+	case enum.Intervals:
+		if t.WorkSeconds == nil || t.IntervalRestSeconds == nil || t.Rounds == nil {
+			return errors.New("intervals blocks need work_seconds, interval_rest_seconds and rounds")
+		}
+		if t.TimeCapSeconds != nil {
+			return errors.New("intervals blocks take no time_cap_seconds")
+		}
+	}
+
+	switch {
+	case t.WorkSeconds != nil && *t.WorkSeconds <= 0:
+		return errors.New("work_seconds must be greater than 0")
+	case t.IntervalRestSeconds != nil && *t.IntervalRestSeconds < 0:
+		return errors.New("interval_rest_seconds cannot be negative")
+	case t.Rounds != nil && *t.Rounds <= 0:
+		return errors.New("rounds must be greater than 0")
+	case t.TimeCapSeconds != nil && *t.TimeCapSeconds <= 0:
+		return errors.New("time_cap_seconds must be greater than 0")
+	}
+	return nil
+}
+
+// DurationSeconds is how long the block runs, 0 when its format does not fix it (standard blocks and uncapped For-Time)
+func (t BlockTiming) DurationSeconds() int {
+	switch t.Mode() {
+	case enum.EMOM:
+		return intValue(t.Rounds) * intValue(t.WorkSeconds)
+	case enum.AMRAP, enum.ForTime:
+		return intValue(t.TimeCapSeconds)
+	case enum.Tabata, enum.Intervals:
+		return intValue(t.Rounds) * (intValue(t.WorkSeconds) + intValue(t.IntervalRestSeconds))
+	}
+	return 0
+}
+
+// DurationMinutes rounds the block duration up to whole minutes, nil when the format does not fix it
+func (t BlockTiming) DurationMinutes() *int {
+	seconds := t.DurationSeconds()
+	if seconds == 0 {
+		return nil
+	}
+	minutes := (seconds + 59) / 60
+	return &minutes
+}
+
+func defaultInt(value *int, fallback int) *int {
+	if value != nil {
+		return value
+	}
+	return &fallback
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
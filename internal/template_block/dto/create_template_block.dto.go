@@ -13,4 +13,5 @@ type CreateTemplateBlockDTO struct {
 	Series                   *int    `json:"series,omitempty"`
 	RestTimeSeconds          *int    `json:"rest_time_seconds,omitempty"`
 	CreatedBy                string  `json:"created_by"`
+	BlockTiming
 }
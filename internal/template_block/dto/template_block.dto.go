@@ -14,4 +14,5 @@ type TemplateBlockDTO struct {
 	RestTimeSeconds          *int    `json:"rest_time_seconds,omitempty"`
 	CreatedAt                string  `json:"created_at"`
 	CreatedBy                string  `json:"created_by"`
+	BlockTiming
 }
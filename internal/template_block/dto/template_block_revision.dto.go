@@ -0,0 +1,31 @@
+package dto
+
+// TemplateBlockRevisionSnapshot is the editable state of a template block kept in its revision history.
+type TemplateBlockRevisionSnapshot struct {
+	BlockName                string  `json:"block_name"`
+	BlockType                string  `json:"block_type"`
+	BlockOrder               int     `json:"block_order"`
+	ExerciseCount            int     `json:"exercise_count"`
+	EstimatedDurationMinutes *int    `json:"estimated_duration_minutes"`
+	Instructions             *string `json:"instructions"`
+	Reps                     *int    `json:"reps"`
+	Series                   *int    `json:"series"`
+	RestTimeSeconds          *int    `json:"rest_time_seconds"`
+	BlockTiming
+}
+
+// NewTemplateBlockRevisionSnapshot copies the versioned fields of a block.
+func NewTemplateBlockRevisionSnapshot(block *TemplateBlockDTO) *TemplateBlockRevisionSnapshot {
+	return &TemplateBlockRevisionSnapshot{
+		BlockName:                block.BlockName,
+		BlockType:                block.BlockType,
+		BlockOrder:               block.BlockOrder,
+		ExerciseCount:            block.ExerciseCount,
+		EstimatedDurationMinutes: block.EstimatedDurationMinutes,
+		Instructions:             block.Instructions,
+		Reps:                     block.Reps,
+		Series:                   block.Series,
+		RestTimeSeconds:          block.RestTimeSeconds,
+		BlockTiming:              block.BlockTiming,
+	}
+}
@@ -11,4 +11,6 @@ type UpdateTemplateBlockDTO struct {
 	Reps                     *int    `json:"reps,omitempty"`
 	Series                   *int    `json:"series,omitempty"`
 	RestTimeSeconds          *int    `json:"rest_time_seconds,omitempty"`
+	UpdatedBy                string  `json:"-"` // author of the revision, set from the caller's token
+	BlockTiming
 }
@@ -0,0 +1,26 @@
+package enum
+
+// BlockMode is how the exercises of a block are timed; standard blocks use their reps, series and rest
+type BlockMode string
+
+const (
+	Standard  BlockMode = "standard"
+	EMOM      BlockMode = "emom"      // every minute on the minute, for a number of rounds
+	AMRAP     BlockMode = "amrap"     // as many rounds as possible within the time cap
+	Tabata    BlockMode = "tabata"    // 20 seconds of work and 10 of rest for 8 rounds unless overridden
+	ForTime   BlockMode = "for_time"  // the rounds as fast as possible, optionally capped
+	Intervals BlockMode = "intervals" // custom work and rest intervals
+)
+
+func (e BlockMode) IsValid() bool {
+	switch e {
+	case Standard, EMOM, AMRAP, Tabata, ForTime, Intervals:
+		return true
+	}
+	return false
+}
+
+// IsTimed reports whether sessions of the block record a result such as rounds completed or finish time
+func (e BlockMode) IsTimed() bool {
+	return e.IsValid() && e != Standard
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/template_block/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 	"github.com/go-chi/chi/v5"
 )
@@ -68,10 +69,11 @@ func (h *TemplateBlockHandler) UpdateTemplateBlock(w http.ResponseWriter, r *htt
 		return
 	}
 	var update *dto.UpdateTemplateBlockDTO
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil || update == nil {
 		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
 		return
 	}
+	update.UpdatedBy = middleware.GetUserID(r)
 	updatedBlock, err := h.service.UpdateTemplateBlock(id, update)
 	if err != nil {
 		if apiErr, ok := err.(*apierror.APIError); ok {
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"net/http"
 
+	revisionRepository "github.com/alejandro-albiol/athenai/internal/revision/repository"
+	revisionService "github.com/alejandro-albiol/athenai/internal/revision/service"
 	"github.com/alejandro-albiol/athenai/internal/template_block/handler"
 	"github.com/alejandro-albiol/athenai/internal/template_block/repository"
 	"github.com/alejandro-albiol/athenai/internal/template_block/router"
@@ -11,8 +13,13 @@ import (
 )
 
 func NewTemplateBlockModule(db *sql.DB) http.Handler {
-	repo := repository.NewTemplateBlockRepository(db)
-	service := service.NewTemplateBlockService(repo)
+	service := NewTemplateBlockService(db)
 	handler := handler.NewTemplateBlockHandler(service)
 	return router.NewTemplateBlockRouter(handler)
 }
+
+// NewTemplateBlockService builds the service with revision recording for modules that update blocks
+func NewTemplateBlockService(db *sql.DB) *service.TemplateBlockService {
+	recorder := revisionService.NewRevisionService(revisionRepository.NewRevisionRepository(db))
+	return service.NewTemplateBlockService(repository.NewTemplateBlockRepository(db), recorder)
+}
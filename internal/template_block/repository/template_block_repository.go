@@ -20,8 +20,9 @@ func NewTemplateBlockRepository(db *sql.DB) *TemplateBlockRepository {
 func (r *TemplateBlockRepository) CreateTemplateBlock(block *dto.CreateTemplateBlockDTO) (*string, error) {
 	query := `
 		INSERT INTO public.template_block 
-			(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			(template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_by,
+			block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id`
 	var id string
 	err := r.db.QueryRow(
@@ -37,6 +38,11 @@ func (r *TemplateBlockRepository) CreateTemplateBlock(block *dto.CreateTemplateB
 		block.Series,
 		block.RestTimeSeconds,
 		block.CreatedBy,
+		string(block.Mode()),
+		block.WorkSeconds,
+		block.IntervalRestSeconds,
+		block.Rounds,
+		block.TimeCapSeconds,
 	).Scan(&id)
 	if err != nil {
 		return nil, err
@@ -47,7 +53,7 @@ func (r *TemplateBlockRepository) CreateTemplateBlock(block *dto.CreateTemplateB
 // GetTemplateBlockByID retrieves a template block by its ID.
 func (r *TemplateBlockRepository) GetTemplateBlockByID(id string) (*dto.TemplateBlockDTO, error) {
 	query := `
-		SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by
+		SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by
 		FROM public.template_block WHERE id = $1`
 	var block dto.TemplateBlockDTO
 	err := r.db.QueryRow(query, id).Scan(
@@ -62,6 +68,11 @@ func (r *TemplateBlockRepository) GetTemplateBlockByID(id string) (*dto.Template
 		&block.Reps,
 		&block.Series,
 		&block.RestTimeSeconds,
+		&block.BlockMode,
+		&block.WorkSeconds,
+		&block.IntervalRestSeconds,
+		&block.Rounds,
+		&block.TimeCapSeconds,
 		&block.CreatedAt,
 		&block.CreatedBy,
 	)
@@ -77,7 +88,7 @@ func (r *TemplateBlockRepository) GetTemplateBlockByID(id string) (*dto.Template
 // GetTemplateBlocksByTemplateID retrieves all template blocks for a given template ID.
 func (r *TemplateBlockRepository) GetTemplateBlocksByTemplateID(templateID string) ([]*dto.TemplateBlockDTO, error) {
 	query := `
-		SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by
+		SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by
 		FROM public.template_block WHERE template_id = $1 ORDER BY block_order`
 	rows, err := r.db.Query(query, templateID)
 	if err != nil {
@@ -100,6 +111,11 @@ func (r *TemplateBlockRepository) GetTemplateBlocksByTemplateID(templateID strin
 			&block.Reps,
 			&block.Series,
 			&block.RestTimeSeconds,
+			&block.BlockMode,
+			&block.WorkSeconds,
+			&block.IntervalRestSeconds,
+			&block.Rounds,
+			&block.TimeCapSeconds,
 			&block.CreatedAt,
 			&block.CreatedBy,
 		)
@@ -114,7 +130,7 @@ func (r *TemplateBlockRepository) GetTemplateBlocksByTemplateID(templateID strin
 // GetTemplateBlockByTemplateIDAndName retrieves a template block by template ID and name.
 func (r *TemplateBlockRepository) GetTemplateBlockByTemplateIDAndName(templateID string, blockName string) (*dto.TemplateBlockDTO, error) {
 	block := &dto.TemplateBlockDTO{}
-	query := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by FROM public.template_block WHERE template_id = $1 AND block_name = $2`
+	query := `SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by FROM public.template_block WHERE template_id = $1 AND block_name = $2`
 	err := r.db.QueryRow(query, templateID, blockName).Scan(
 		&block.ID,
 		&block.TemplateID,
@@ -127,6 +143,11 @@ func (r *TemplateBlockRepository) GetTemplateBlockByTemplateIDAndName(templateID
 		&block.Reps,
 		&block.Series,
 		&block.RestTimeSeconds,
+		&block.BlockMode,
+		&block.WorkSeconds,
+		&block.IntervalRestSeconds,
+		&block.Rounds,
+		&block.TimeCapSeconds,
 		&block.CreatedAt,
 		&block.CreatedBy,
 	)
@@ -140,14 +161,22 @@ func (r *TemplateBlockRepository) GetTemplateBlockByTemplateIDAndName(templateID
 }
 
 // Update modifies an existing template block and returns the updated block.
-func (r *TemplateBlockRepository) UpdateTemplateBlock(id string, block *dto.UpdateTemplateBlockDTO) (*dto.TemplateBlockDTO, error) {
+// onUpdate runs in the same transaction, so when it fails the update is rolled back.
+func (r *TemplateBlockRepository) UpdateTemplateBlock(id string, block *dto.UpdateTemplateBlockDTO, onUpdate func(tx *sql.Tx, updated *dto.TemplateBlockDTO) error) (*dto.TemplateBlockDTO, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE public.template_block
-		SET block_name = $1, block_type = $2, block_order = $3, exercise_count = $4, estimated_duration_minutes = $5, instructions = $6, reps = $7, series = $8, rest_time_seconds = $9
-		WHERE id = $10
-		RETURNING id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by`
+		SET block_name = $1, block_type = $2, block_order = $3, exercise_count = $4, estimated_duration_minutes = $5, instructions = $6, reps = $7, series = $8, rest_time_seconds = $9,
+			block_mode = $10, work_seconds = $11, interval_rest_seconds = $12, rounds = $13, time_cap_seconds = $14
+		WHERE id = $15
+		RETURNING id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by`
 	updatedBlock := &dto.TemplateBlockDTO{}
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		block.BlockName,
 		block.BlockType,
@@ -158,6 +187,11 @@ func (r *TemplateBlockRepository) UpdateTemplateBlock(id string, block *dto.Upda
 		block.Reps,
 		block.Series,
 		block.RestTimeSeconds,
+		string(block.Mode()),
+		block.WorkSeconds,
+		block.IntervalRestSeconds,
+		block.Rounds,
+		block.TimeCapSeconds,
 		id,
 	).Scan(
 		&updatedBlock.ID,
@@ -171,12 +205,25 @@ func (r *TemplateBlockRepository) UpdateTemplateBlock(id string, block *dto.Upda
 		&updatedBlock.Reps,
 		&updatedBlock.Series,
 		&updatedBlock.RestTimeSeconds,
+		&updatedBlock.BlockMode,
+		&updatedBlock.WorkSeconds,
+		&updatedBlock.IntervalRestSeconds,
+		&updatedBlock.Rounds,
+		&updatedBlock.TimeCapSeconds,
 		&updatedBlock.CreatedAt,
 		&updatedBlock.CreatedBy,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if onUpdate != nil {
+		if err := onUpdate(tx, updatedBlock); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return updatedBlock, nil
 }
 
@@ -38,7 +38,8 @@ func TestCreateTemplateBlock(t *testing.T) {
 	}
 
 	mock.ExpectQuery("INSERT INTO public.template_block").
-		WithArgs(block.TemplateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes, block.Instructions, block.Reps, block.Series, block.RestTimeSeconds, block.CreatedBy).
+		WithArgs(block.TemplateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes, block.Instructions, block.Reps, block.Series, block.RestTimeSeconds, block.CreatedBy,
+			"standard", block.WorkSeconds, block.IntervalRestSeconds, block.Rounds, block.TimeCapSeconds).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("block-uuid"))
 
 	id, err := repo.CreateTemplateBlock(block)
@@ -63,9 +64,9 @@ func TestGetByID(t *testing.T) {
 
 	repo := repository.NewTemplateBlockRepository(mockDB)
 
-	row := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "created_at", "created_by"}).
-		AddRow("block-uuid", "template-uuid", "Block Name", "Type", 1, 5, 30, "Do this", 12, 3, 60, "2025-09-04T12:00:00Z", "admin-uuid")
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by\n\t\tFROM public.template_block WHERE id = $1")).
+	row := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds", "created_at", "created_by"}).
+		AddRow("block-uuid", "template-uuid", "Block Name", "Type", 1, 5, 30, "Do this", 12, 3, 60, "standard", nil, nil, nil, nil, "2025-09-04T12:00:00Z", "admin-uuid")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by\n\t\tFROM public.template_block WHERE id = $1")).
 		WithArgs("block-uuid").
 		WillReturnRows(row)
 
@@ -90,10 +91,10 @@ func TestGetByTemplateID(t *testing.T) {
 
 	repo := repository.NewTemplateBlockRepository(mockDB)
 
-	rows := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "created_at", "created_by"}).
-		AddRow("block-uuid-1", "template-uuid", "Block 1", "Type", 1, 5, 30, "Do this", 12, 3, 60, "2025-09-04T12:00:00Z", "admin-uuid").
-		AddRow("block-uuid-2", "template-uuid", "Block 2", "Type", 2, 6, 40, "Do that", 15, 4, 90, "2025-09-04T12:01:00Z", "admin-uuid")
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by\n\t\tFROM public.template_block WHERE template_id = $1 ORDER BY block_order")).
+	rows := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds", "created_at", "created_by"}).
+		AddRow("block-uuid-1", "template-uuid", "Block 1", "Type", 1, 5, 30, "Do this", 12, 3, 60, "standard", nil, nil, nil, nil, "2025-09-04T12:00:00Z", "admin-uuid").
+		AddRow("block-uuid-2", "template-uuid", "Block 2", "Type", 2, 6, 40, "Do that", 15, 4, 90, "standard", nil, nil, nil, nil, "2025-09-04T12:01:00Z", "admin-uuid")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by\n\t\tFROM public.template_block WHERE template_id = $1 ORDER BY block_order")).
 		WithArgs("template-uuid").
 		WillReturnRows(rows)
 
@@ -127,6 +128,9 @@ func TestUpdateTemplateBlock(t *testing.T) {
 	reps := 15
 	series := 4
 	restTimeSeconds := 120
+	mode := "emom"
+	workSeconds := 60
+	rounds := 10
 
 	update := &dto.UpdateTemplateBlockDTO{
 		BlockName:                &name,
@@ -138,21 +142,28 @@ func TestUpdateTemplateBlock(t *testing.T) {
 		Reps:                     &reps,
 		Series:                   &series,
 		RestTimeSeconds:          &restTimeSeconds,
+		BlockTiming:              dto.BlockTiming{BlockMode: &mode, WorkSeconds: &workSeconds, Rounds: &rounds},
 	}
 
-	row := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "created_at", "created_by"}).
-		AddRow("block-uuid", "template-uuid", update.BlockName, update.BlockType, update.BlockOrder, update.ExerciseCount, update.EstimatedDurationMinutes, update.Instructions, update.Reps, update.Series, update.RestTimeSeconds, "2025-09-04T12:02:00Z", "admin-uuid")
-	mock.ExpectQuery(regexp.QuoteMeta("UPDATE public.template_block\n\t\tSET block_name = $1, block_type = $2, block_order = $3, exercise_count = $4, estimated_duration_minutes = $5, instructions = $6, reps = $7, series = $8, rest_time_seconds = $9\n\t\tWHERE id = $10\n\t\tRETURNING id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, created_at, created_by")).
-		WithArgs(update.BlockName, update.BlockType, update.BlockOrder, update.ExerciseCount, update.EstimatedDurationMinutes, update.Instructions, update.Reps, update.Series, update.RestTimeSeconds, "block-uuid").
+	row := sqlmock.NewRows([]string{"id", "template_id", "block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds", "block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds", "created_at", "created_by"}).
+		AddRow("block-uuid", "template-uuid", update.BlockName, update.BlockType, update.BlockOrder, update.ExerciseCount, update.EstimatedDurationMinutes, update.Instructions, update.Reps, update.Series, update.RestTimeSeconds, "emom", 60, nil, 10, nil, "2025-09-04T12:02:00Z", "admin-uuid")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE public.template_block\n\t\tSET block_name = $1, block_type = $2, block_order = $3, exercise_count = $4, estimated_duration_minutes = $5, instructions = $6, reps = $7, series = $8, rest_time_seconds = $9,\n\t\t\tblock_mode = $10, work_seconds = $11, interval_rest_seconds = $12, rounds = $13, time_cap_seconds = $14\n\t\tWHERE id = $15\n\t\tRETURNING id, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes, instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds, created_at, created_by")).
+		WithArgs(update.BlockName, update.BlockType, update.BlockOrder, update.ExerciseCount, update.EstimatedDurationMinutes, update.Instructions, update.Reps, update.Series, update.RestTimeSeconds,
+			"emom", update.WorkSeconds, update.IntervalRestSeconds, update.Rounds, update.TimeCapSeconds, "block-uuid").
 		WillReturnRows(row)
+	mock.ExpectCommit()
 
-	updatedBlock, err := repo.UpdateTemplateBlock("block-uuid", update)
+	updatedBlock, err := repo.UpdateTemplateBlock("block-uuid", update, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	if updatedBlock == nil || updatedBlock.BlockName != "Updated Name" {
 		t.Errorf("expected updated block name 'Updated Name', got '%v'", updatedBlock)
 	}
+	if updatedBlock != nil && (updatedBlock.Mode() != "emom" || *updatedBlock.Rounds != 10) {
+		t.Errorf("expected a 10 round emom block, got %+v", updatedBlock.BlockTiming)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
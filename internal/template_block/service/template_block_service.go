@@ -2,9 +2,12 @@ package service
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
+	revisionIF "github.com/alejandro-albiol/athenai/internal/revision/interfaces"
 	"github.com/alejandro-albiol/athenai/internal/template_block/dto"
 	"github.com/alejandro-albiol/athenai/internal/template_block/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
@@ -13,16 +16,24 @@ import (
 
 type TemplateBlockService struct {
 	repository interfaces.TemplateBlockRepository
+	recorder   revisionIF.Recorder
 }
 
-func NewTemplateBlockService(repository interfaces.TemplateBlockRepository) *TemplateBlockService {
-	return &TemplateBlockService{repository: repository}
+// NewTemplateBlockService creates the service; without a recorder updates keep no revision history
+func NewTemplateBlockService(repository interfaces.TemplateBlockRepository, recorder revisionIF.Recorder) *TemplateBlockService {
+	return &TemplateBlockService{repository: repository, recorder: recorder}
 }
 
 func (s *TemplateBlockService) CreateTemplateBlock(block *dto.CreateTemplateBlockDTO) (*string, error) {
 	if block == nil {
 		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Block payload is nil", nil)
 	}
+	if err := block.Normalize(); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, err.Error(), nil)
+	}
+	if block.EstimatedDurationMinutes == nil {
+		block.EstimatedDurationMinutes = block.DurationMinutes()
+	}
 	existingBlock, err := s.repository.GetTemplateBlockByTemplateIDAndName(block.TemplateID, block.BlockName)
 	if err == nil && existingBlock != nil && existingBlock.ID != "" {
 		return nil, apierror.New(errorcode_enum.CodeConflict, fmt.Sprintf("Template block with name '%s' already exists in template", block.BlockName), nil)
@@ -55,8 +66,59 @@ func (s *TemplateBlockService) ListTemplateBlocksByTemplateID(templateID string)
 	return blocks, nil
 }
 
+// UpdateTemplateBlock applies the update and, when revisions are tracked, records the new state in the same transaction
 func (s *TemplateBlockService) UpdateTemplateBlock(id string, update *dto.UpdateTemplateBlockDTO) (*dto.TemplateBlockDTO, error) {
-	updatedBlock, err := s.repository.UpdateTemplateBlock(id, update)
+	if s.recorder == nil {
+		return s.applyUpdate(id, update, nil)
+	}
+	existing, err := s.GetTemplateBlockByID(id)
+	if err != nil {
+		return nil, err
+	}
+	before := dto.NewTemplateBlockRevisionSnapshot(existing)
+	return s.applyUpdate(id, update, func(tx *sql.Tx, updated *dto.TemplateBlockDTO) error {
+		return s.recorder.Record(tx, revisionEnum.TemplateBlock, id, before, dto.NewTemplateBlockRevisionSnapshot(updated), update.UpdatedBy)
+	})
+}
+
+// RestoreSnapshot writes a revision snapshot back to the block; record stores the restore in the same transaction.
+// Every field is overwritten, so an optional field that was empty in the snapshot is cleared again.
+func (s *TemplateBlockService) RestoreSnapshot(id string, snapshot json.RawMessage, record func(tx *sql.Tx, before, after any) error) error {
+	var restored dto.TemplateBlockRevisionSnapshot
+	if err := json.Unmarshal(snapshot, &restored); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Stored template block revision is unreadable", err)
+	}
+	existing, err := s.GetTemplateBlockByID(id)
+	if err != nil {
+		return err
+	}
+	before := dto.NewTemplateBlockRevisionSnapshot(existing)
+	_, err = s.applyUpdate(id, &dto.UpdateTemplateBlockDTO{
+		BlockName:                &restored.BlockName,
+		BlockType:                &restored.BlockType,
+		BlockOrder:               &restored.BlockOrder,
+		ExerciseCount:            &restored.ExerciseCount,
+		EstimatedDurationMinutes: restored.EstimatedDurationMinutes,
+		Instructions:             restored.Instructions,
+		Reps:                     restored.Reps,
+		Series:                   restored.Series,
+		RestTimeSeconds:          restored.RestTimeSeconds,
+		BlockTiming:              restored.BlockTiming,
+	}, func(tx *sql.Tx, updated *dto.TemplateBlockDTO) error {
+		return record(tx, before, dto.NewTemplateBlockRevisionSnapshot(updated))
+	})
+	return err
+}
+
+// applyUpdate writes the update over the block; the timing is replaced as a whole, so an update without block_mode makes the block standard
+func (s *TemplateBlockService) applyUpdate(id string, update *dto.UpdateTemplateBlockDTO, onUpdate func(tx *sql.Tx, updated *dto.TemplateBlockDTO) error) (*dto.TemplateBlockDTO, error) {
+	if err := update.Normalize(); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, err.Error(), nil)
+	}
+	if update.EstimatedDurationMinutes == nil {
+		update.EstimatedDurationMinutes = update.DurationMinutes()
+	}
+	updatedBlock, err := s.repository.UpdateTemplateBlock(id, update, onUpdate)
 	if err != nil {
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update template block", err)
 	}
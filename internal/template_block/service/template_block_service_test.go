@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
 	"github.com/alejandro-albiol/athenai/internal/template_block/dto"
 	"github.com/alejandro-albiol/athenai/internal/template_block/service"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
@@ -65,7 +66,8 @@ func (m *mockRepository) GetTemplateBlocksByTemplateID(templateID string) ([]*dt
 	return result, nil
 }
 
-func (m *mockRepository) UpdateTemplateBlock(id string, update *dto.UpdateTemplateBlockDTO) (*dto.TemplateBlockDTO, error) {
+// UpdateTemplateBlock keeps the stored block untouched when onUpdate fails, like the rolled back transaction
+func (m *mockRepository) UpdateTemplateBlock(id string, update *dto.UpdateTemplateBlockDTO, onUpdate func(tx *sql.Tx, updated *dto.TemplateBlockDTO) error) (*dto.TemplateBlockDTO, error) {
 	if m.updateErr != nil {
 		return nil, m.updateErr
 	}
@@ -73,10 +75,17 @@ func (m *mockRepository) UpdateTemplateBlock(id string, update *dto.UpdateTempla
 	if !ok {
 		return nil, errors.New("not found")
 	}
+	updated := *b
 	if update.BlockName != nil {
-		b.BlockName = *update.BlockName
+		updated.BlockName = *update.BlockName
 	}
-	return b, nil
+	if onUpdate != nil {
+		if err := onUpdate(nil, &updated); err != nil {
+			return nil, err
+		}
+	}
+	m.blocks[id] = &updated
+	return &updated, nil
 }
 
 func (m *mockRepository) DeleteTemplateBlock(id string) error {
@@ -92,7 +101,7 @@ func (m *mockRepository) DeleteTemplateBlock(id string) error {
 
 func TestTemplateBlockService_CreateTemplateBlock(t *testing.T) {
 	mock := &mockRepository{blocks: map[string]*dto.TemplateBlockDTO{}}
-	service := service.NewTemplateBlockService(mock)
+	service := service.NewTemplateBlockService(mock, nil)
 	block := &dto.CreateTemplateBlockDTO{BlockName: "Block1", TemplateID: "T1"}
 	id, err := service.CreateTemplateBlock(block)
 	if err != nil {
@@ -114,9 +123,52 @@ func TestTemplateBlockService_CreateTemplateBlock(t *testing.T) {
 	}
 }
 
+func TestTemplateBlockService_CreateTemplateBlockTiming(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+	tests := []struct {
+		name            string
+		timing          dto.BlockTiming
+		wantErr         bool
+		wantMinutes     int
+		wantRoundsAfter int
+	}{
+		{name: "tabata defaults", timing: dto.BlockTiming{BlockMode: strPtr("tabata")}, wantMinutes: 4, wantRoundsAfter: 8},
+		{name: "emom minutes", timing: dto.BlockTiming{BlockMode: strPtr("emom"), Rounds: intPtr(12)}, wantMinutes: 12, wantRoundsAfter: 12},
+		{name: "amrap cap", timing: dto.BlockTiming{BlockMode: strPtr("amrap"), TimeCapSeconds: intPtr(900)}, wantMinutes: 15},
+		{name: "amrap without cap", timing: dto.BlockTiming{BlockMode: strPtr("amrap")}, wantErr: true},
+		{name: "emom with cap", timing: dto.BlockTiming{BlockMode: strPtr("emom"), Rounds: intPtr(10), TimeCapSeconds: intPtr(600)}, wantErr: true},
+		{name: "intervals without rest", timing: dto.BlockTiming{BlockMode: strPtr("intervals"), WorkSeconds: intPtr(40), Rounds: intPtr(6)}, wantErr: true},
+		{name: "standard with rounds", timing: dto.BlockTiming{Rounds: intPtr(3)}, wantErr: true},
+		{name: "unknown mode", timing: dto.BlockTiming{BlockMode: strPtr("ladder")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := service.NewTemplateBlockService(&mockRepository{blocks: map[string]*dto.TemplateBlockDTO{}}, nil)
+			block := &dto.CreateTemplateBlockDTO{BlockName: "Finisher", TemplateID: "T1", BlockTiming: tt.timing}
+			_, err := service.CreateTemplateBlock(block)
+			if tt.wantErr {
+				if apierr, ok := err.(*apierror.APIError); !ok || apierr.Code != "BAD_REQUEST" {
+					t.Fatalf("expected bad request error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if block.EstimatedDurationMinutes == nil || *block.EstimatedDurationMinutes != tt.wantMinutes {
+				t.Fatalf("expected an estimate of %d minutes, got %v", tt.wantMinutes, block.EstimatedDurationMinutes)
+			}
+			if tt.wantRoundsAfter > 0 && *block.Rounds != tt.wantRoundsAfter {
+				t.Fatalf("expected %d rounds, got %d", tt.wantRoundsAfter, *block.Rounds)
+			}
+		})
+	}
+}
+
 func TestTemplateBlockService_GetTemplateBlockByID(t *testing.T) {
 	mock := &mockRepository{blocks: map[string]*dto.TemplateBlockDTO{"id1": {ID: "id1", BlockName: "Block1", TemplateID: "T1"}}}
-	service := service.NewTemplateBlockService(mock)
+	service := service.NewTemplateBlockService(mock, nil)
 	block, err := service.GetTemplateBlockByID("id1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -141,7 +193,7 @@ func TestTemplateBlockService_ListTemplateBlocksByTemplateID(t *testing.T) {
 		"id2": {ID: "id2", BlockName: "Block2", TemplateID: "T1"},
 		"id3": {ID: "id3", BlockName: "Block3", TemplateID: "T2"},
 	}}
-	service := service.NewTemplateBlockService(mock)
+	service := service.NewTemplateBlockService(mock, nil)
 	blocks, err := service.ListTemplateBlocksByTemplateID("T1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -159,7 +211,7 @@ func TestTemplateBlockService_ListTemplateBlocksByTemplateID(t *testing.T) {
 
 func TestTemplateBlockService_UpdateTemplateBlock(t *testing.T) {
 	mock := &mockRepository{blocks: map[string]*dto.TemplateBlockDTO{"id1": {ID: "id1", BlockName: "Block1", TemplateID: "T1"}}}
-	service := service.NewTemplateBlockService(mock)
+	service := service.NewTemplateBlockService(mock, nil)
 	newName := "Updated"
 	update := &dto.UpdateTemplateBlockDTO{BlockName: &newName}
 	block, err := service.UpdateTemplateBlock("id1", update)
@@ -177,9 +229,65 @@ func TestTemplateBlockService_UpdateTemplateBlock(t *testing.T) {
 	}
 }
 
+type mockRecorder struct {
+	before, after *dto.TemplateBlockRevisionSnapshot
+	authorID      string
+	err           error
+}
+
+func (m *mockRecorder) Record(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.before = before.(*dto.TemplateBlockRevisionSnapshot)
+	m.after = after.(*dto.TemplateBlockRevisionSnapshot)
+	m.authorID = authorID
+	return nil
+}
+
+func TestTemplateBlockService_UpdateTemplateBlockRecordsRevision(t *testing.T) {
+	mock := &mockRepository{blocks: map[string]*dto.TemplateBlockDTO{"id1": {ID: "id1", BlockName: "Block1", TemplateID: "T1"}}}
+	recorder := &mockRecorder{}
+	service := service.NewTemplateBlockService(mock, recorder)
+	newName := "Warm-up"
+	if _, err := service.UpdateTemplateBlock("id1", &dto.UpdateTemplateBlockDTO{BlockName: &newName, UpdatedBy: "admin-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recorder.before.BlockName != "Block1" || recorder.after.BlockName != "Warm-up" || recorder.authorID != "admin-1" {
+		t.Fatalf("unexpected revision %+v -> %+v by %q", recorder.before, recorder.after, recorder.authorID)
+	}
+
+	// Unknown blocks are reported before anything is written
+	_, err := service.UpdateTemplateBlock("missing", &dto.UpdateTemplateBlockDTO{BlockName: &newName})
+	if apierr, ok := err.(*apierror.APIError); !ok || apierr.Code != "NOT_FOUND" {
+		t.Fatalf("expected not_found error code, got %v", err)
+	}
+
+	var restoredTo any
+	err = service.RestoreSnapshot("id1", []byte(`{"block_name":"Block1","block_type":"main"}`), func(tx *sql.Tx, before, after any) error {
+		restoredTo = after
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.blocks["id1"].BlockName != "Block1" || restoredTo.(*dto.TemplateBlockRevisionSnapshot).BlockName != "Block1" {
+		t.Fatalf("expected the snapshot name restored, got %v", mock.blocks["id1"].BlockName)
+	}
+
+	// A revision that cannot be stored rolls the update back
+	recorder.err = errors.New("revision insert failed")
+	if _, err := service.UpdateTemplateBlock("id1", &dto.UpdateTemplateBlockDTO{BlockName: &newName}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if mock.blocks["id1"].BlockName != "Block1" {
+		t.Fatalf("expected the update rolled back, got %v", mock.blocks["id1"].BlockName)
+	}
+}
+
 func TestTemplateBlockService_DeleteTemplateBlock(t *testing.T) {
 	mock := &mockRepository{blocks: map[string]*dto.TemplateBlockDTO{"id1": {ID: "id1", BlockName: "Block1", TemplateID: "T1"}}}
-	service := service.NewTemplateBlockService(mock)
+	service := service.NewTemplateBlockService(mock, nil)
 	err := service.DeleteTemplateBlock("id1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -0,0 +1,36 @@
+package dto
+
+import templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+
+// CloneTemplateDTO copies a published version of a public template into the gym
+type CloneTemplateDTO struct {
+	Name            *string `json:"name,omitempty"`             // defaults to the public template's name
+	TemplateVersion *int    `json:"template_version,omitempty"` // defaults to the latest version
+	CreatedBy       string  `json:"-"`
+}
+
+// TemplateCloneDTO is a gym template together with the public template and version it was cloned from
+type TemplateCloneDTO struct {
+	ID               string                              `json:"id"`
+	SourceTemplateID string                              `json:"source_template_id"`
+	SourceVersion    int                                 `json:"source_version"`
+	Template         templateVersionDTO.TemplateSnapshot `json:"template"`
+	Blocks           []CloneBlockDTO                     `json:"blocks"`
+}
+
+// CloneBlockDTO is a block of a clone; SourceBlockName ties it to the upstream block even after a local rename
+type CloneBlockDTO struct {
+	ID              string  `json:"id"`
+	SourceBlockName *string `json:"source_block_name"` // empty for blocks the gym added
+	templateVersionDTO.BlockSnapshot
+}
+
+// CloneUpdate is what pulling upstream writes to a clone in one go
+type CloneUpdate struct {
+	Template      templateVersionDTO.TemplateSnapshot
+	AddedBlocks   []templateVersionDTO.BlockSnapshot
+	ChangedBlocks []CloneBlockDTO
+	RemovedBlocks []string // block ids
+	SourceVersion int
+	PulledBy      string
+}
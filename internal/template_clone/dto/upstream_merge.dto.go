@@ -0,0 +1,28 @@
+package dto
+
+import templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+
+// UpstreamMergeDTO is a three-way merge of the clone's source version, the clone and the latest public version.
+// Upstream changes to fields the gym left alone are taken; fields both sides changed are conflicts and keep the gym's value.
+type UpstreamMergeDTO struct {
+	CloneID          string                           `json:"clone_id"`
+	SourceTemplateID string                           `json:"source_template_id"`
+	FromVersion      int                              `json:"from_version"`
+	ToVersion        int                              `json:"to_version"`
+	UpToDate         bool                             `json:"up_to_date"`
+	Applied          bool                             `json:"applied"`
+	TemplateChanges  []templateVersionDTO.FieldChange `json:"template_changes"`
+	AddedBlocks      []string                         `json:"added_blocks"`
+	RemovedBlocks    []string                         `json:"removed_blocks"`
+	ChangedBlocks    []templateVersionDTO.BlockChange `json:"changed_blocks"`
+	Conflicts        []MergeConflict                  `json:"conflicts"`
+}
+
+// MergeConflict is a field changed both by the gym and upstream.
+// Field is empty when upstream removed a block the gym has changed.
+type MergeConflict struct {
+	BlockName string `json:"block_name,omitempty"` // empty for template fields
+	Field     string `json:"field,omitempty"`
+	Local     any    `json:"local"`
+	Upstream  any    `json:"upstream"`
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TemplateCloneHandler struct {
+	service interfaces.TemplateCloneService
+}
+
+func NewTemplateCloneHandler(service interfaces.TemplateCloneService) *TemplateCloneHandler {
+	return &TemplateCloneHandler{service: service}
+}
+
+func (h *TemplateCloneHandler) CloneTemplate(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var clone dto.CloneTemplateDTO
+	if err := json.NewDecoder(r.Body).Decode(&clone); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	clone.CreatedBy = middleware.GetUserID(r)
+
+	created, err := h.service.CloneTemplate(middleware.GetGymID(r), chi.URLParam(r, "publicTemplateID"), &clone)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to clone workout template")
+		return
+	}
+	response.WriteAPICreated(w, "Workout template cloned successfully", created)
+}
+
+func (h *TemplateCloneHandler) GetUpstreamChanges(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	merge, err := h.service.GetUpstreamChanges(middleware.GetGymID(r), chi.URLParam(r, "cloneID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get upstream changes")
+		return
+	}
+	response.WriteAPISuccess(w, "Upstream changes retrieved successfully", merge)
+}
+
+func (h *TemplateCloneHandler) PullUpstreamChanges(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	merge, err := h.service.PullUpstreamChanges(middleware.GetGymID(r), chi.URLParam(r, "cloneID"), middleware.GetUserID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to pull upstream changes")
+		return
+	}
+	response.WriteAPISuccess(w, "Upstream changes pulled successfully", merge)
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym the clone belongs to
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can clone workout templates", nil))
+	return false
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.TemplateCloneService
+	cloned   *dto.CloneTemplateDTO
+	pulledBy string
+}
+
+func (m *mockService) CloneTemplate(gymID, publicTemplateID string, clone *dto.CloneTemplateDTO) (*dto.TemplateCloneDTO, error) {
+	m.cloned = clone
+	return &dto.TemplateCloneDTO{ID: "clone-1", SourceTemplateID: publicTemplateID}, nil
+}
+func (m *mockService) PullUpstreamChanges(gymID, cloneID, pulledBy string) (*dto.UpstreamMergeDTO, error) {
+	m.pulledBy = pulledBy
+	return &dto.UpstreamMergeDTO{CloneID: cloneID, Applied: true}, nil
+}
+
+func serve(svc *mockService, method, target, body, userType, role, gymID string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewTemplateCloneRouter(NewTemplateCloneHandler(svc)),
+		testutil.Caller{UserType: userType, Role: role, UserID: "user-1", GymID: gymID}, method, target, body)
+}
+
+func TestCloneTemplateRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/tpl-1", "", "tenant_user", "member", "gym-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.cloned)
+
+	w = serve(svc, http.MethodPost, "/tpl-1", `{"name":"Our Full Body"}`, "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Our Full Body", *svc.cloned.Name)
+	assert.Equal(t, "user-1", svc.cloned.CreatedBy)
+}
+
+func TestCloneTemplateRejectsInvalidBody(t *testing.T) {
+	w := serve(&mockService{}, http.MethodPost, "/tpl-1", `{"name":`, "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPullUpstreamChanges(t *testing.T) {
+	svc := &mockService{}
+	w := serve(svc, http.MethodPost, "/clone-1/pull", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user-1", svc.pulledBy)
+	assert.Contains(t, w.Body.String(), `"applied":true`)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "net/http"
+
+type TemplateCloneHandler interface {
+	CloneTemplate(w http.ResponseWriter, r *http.Request)
+	GetUpstreamChanges(w http.ResponseWriter, r *http.Request)
+	PullUpstreamChanges(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+)
+
+type TemplateCloneRepository interface {
+	IsSharedPublicTemplate(templateID string) (bool, error)
+	NameExists(gymID, name string) (bool, error)
+	CreateClone(gymID, createdBy, sourceTemplateID string, sourceVersion int, snapshot *templateVersionDTO.TemplateSnapshot) (string, error)
+	// FindClone returns sql.ErrNoRows for templates that do not exist or were not cloned
+	FindClone(gymID, cloneID string) (*dto.TemplateCloneDTO, error)
+	ApplyUpdate(gymID, cloneID string, update *dto.CloneUpdate) error
+}
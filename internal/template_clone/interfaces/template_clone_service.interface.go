@@ -0,0 +1,9 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+
+type TemplateCloneService interface {
+	CloneTemplate(gymID, publicTemplateID string, clone *dto.CloneTemplateDTO) (*dto.TemplateCloneDTO, error)
+	GetUpstreamChanges(gymID, cloneID string) (*dto.UpstreamMergeDTO, error)
+	PullUpstreamChanges(gymID, cloneID, pulledBy string) (*dto.UpstreamMergeDTO, error)
+}
@@ -0,0 +1,19 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/handler"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/repository"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/router"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/service"
+	templateVersionModule "github.com/alejandro-albiol/athenai/internal/template_version/module"
+)
+
+func NewTemplateCloneModule(db *sql.DB) http.Handler {
+	repo := repository.NewTemplateCloneRepository(db)
+	service := service.NewTemplateCloneService(repo, templateVersionModule.NewTemplateVersionService(db))
+	handler := handler.NewTemplateCloneHandler(service)
+	return router.NewTemplateCloneRouter(handler)
+}
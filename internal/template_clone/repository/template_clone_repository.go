@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/lib/pq"
+)
+
+type TemplateCloneRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateCloneRepository(db *sql.DB) *TemplateCloneRepository {
+	return &TemplateCloneRepository{db: db}
+}
+
+// IsSharedPublicTemplate reports whether gyms may clone the public template
+func (r *TemplateCloneRepository) IsSharedPublicTemplate(templateID string) (bool, error) {
+	var shared bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM public.workout_template WHERE id = $1 AND is_active = TRUE AND is_public = TRUE)`,
+		templateID).Scan(&shared)
+	return shared, err
+}
+
+func (r *TemplateCloneRepository) NameExists(gymID, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.custom_workout_template WHERE name = $1 AND is_active = TRUE)`,
+		pq.QuoteIdentifier(gymID)), name).Scan(&exists)
+	return exists, err
+}
+
+// CreateClone writes the template and all its blocks in one transaction
+func (r *TemplateCloneRepository) CreateClone(gymID, createdBy, sourceTemplateID string, sourceVersion int, snapshot *templateVersionDTO.TemplateSnapshot) (string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.custom_workout_template
+		(created_by, name, description, difficulty_level, estimated_duration_minutes, target_audience, source_template_id, source_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, schema),
+		createdBy, snapshot.Name, snapshot.Description, snapshot.DifficultyLevel, snapshot.EstimatedDurationMinutes, snapshot.TargetAudience,
+		sourceTemplateID, sourceVersion,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	for _, block := range snapshot.Blocks {
+		if err := insertBlock(tx, schema, id, createdBy, block); err != nil {
+			return "", err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// insertBlock adds an upstream block, remembering its upstream name so later pulls can find it after a local rename
+func insertBlock(tx *sql.Tx, schema, templateID, createdBy string, block templateVersionDTO.BlockSnapshot) error {
+	_, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_template_block
+		(created_by, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes,
+		instructions, reps, series, rest_time_seconds, source_block_name,
+		block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $3, $12, $13, $14, $15, $16)`, schema),
+		createdBy, templateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes,
+		block.Instructions, block.Reps, block.Series, block.RestTimeSeconds,
+		string(block.Mode()), block.WorkSeconds, block.IntervalRestSeconds, block.Rounds, block.TimeCapSeconds,
+	)
+	return err
+}
+
+func (r *TemplateCloneRepository) FindClone(gymID, cloneID string) (*dto.TemplateCloneDTO, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	clone := &dto.TemplateCloneDTO{ID: cloneID}
+	template := &clone.Template
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT source_template_id, source_version, name, description, difficulty_level,
+		estimated_duration_minutes, target_audience
+		FROM %s.custom_workout_template
+		WHERE id = $1 AND is_active = TRUE AND source_template_id IS NOT NULL`, schema), cloneID).Scan(
+		&clone.SourceTemplateID, &clone.SourceVersion, &template.Name, &template.Description, &template.DifficultyLevel,
+		&template.EstimatedDurationMinutes, &template.TargetAudience,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT id, source_block_name, block_name, block_type, block_order, exercise_count,
+		estimated_duration_minutes, instructions, reps, series, rest_time_seconds,
+		block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds
+		FROM %s.custom_template_block
+		WHERE template_id = $1 AND deleted_at IS NULL
+		ORDER BY block_order`, schema), cloneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clone.Blocks = []dto.CloneBlockDTO{}
+	for rows.Next() {
+		var block dto.CloneBlockDTO
+		if err := rows.Scan(&block.ID, &block.SourceBlockName, &block.BlockName, &block.BlockType, &block.BlockOrder, &block.ExerciseCount,
+			&block.EstimatedDurationMinutes, &block.Instructions, &block.Reps, &block.Series, &block.RestTimeSeconds,
+			&block.BlockMode, &block.WorkSeconds, &block.IntervalRestSeconds, &block.Rounds, &block.TimeCapSeconds); err != nil {
+			return nil, err
+		}
+		clone.Blocks = append(clone.Blocks, block)
+	}
+	return clone, rows.Err()
+}
+
+// ApplyUpdate writes a merged pull and moves the clone to the pulled version in one transaction
+func (r *TemplateCloneRepository) ApplyUpdate(gymID, cloneID string, update *dto.CloneUpdate) error {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	template := update.Template
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_workout_template
+		SET name = $1, description = $2, difficulty_level = $3, estimated_duration_minutes = $4, target_audience = $5,
+		source_version = $6, updated_at = NOW()
+		WHERE id = $7`, schema),
+		template.Name, template.Description, template.DifficultyLevel, template.EstimatedDurationMinutes, template.TargetAudience,
+		update.SourceVersion, cloneID,
+	); err != nil {
+		return err
+	}
+
+	for _, block := range update.ChangedBlocks {
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_template_block
+			SET block_name = $1, block_type = $2, block_order = $3, exercise_count = $4, estimated_duration_minutes = $5,
+			instructions = $6, reps = $7, series = $8, rest_time_seconds = $9,
+			block_mode = $10, work_seconds = $11, interval_rest_seconds = $12, rounds = $13, time_cap_seconds = $14, updated_at = NOW()
+			WHERE id = $15 AND template_id = $16`, schema),
+			block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, block.EstimatedDurationMinutes,
+			block.Instructions, block.Reps, block.Series, block.RestTimeSeconds,
+			string(block.Mode()), block.WorkSeconds, block.IntervalRestSeconds, block.Rounds, block.TimeCapSeconds, block.ID, cloneID,
+		); err != nil {
+			return err
+		}
+	}
+	if len(update.RemovedBlocks) > 0 {
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_template_block
+			SET deleted_at = NOW(), is_active = FALSE
+			WHERE template_id = $1 AND id = ANY($2)`, schema), cloneID, pq.Array(update.RemovedBlocks)); err != nil {
+			return err
+		}
+	}
+	for _, block := range update.AddedBlocks {
+		if err := insertBlock(tx, schema, cloneID, update.PulledBy, block); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
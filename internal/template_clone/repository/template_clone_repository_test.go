@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCloneCopiesBlocksInOneTransaction(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateCloneRepository(db)
+	audience := "general_fitness"
+	snapshot := &templateVersionDTO.TemplateSnapshot{
+		Name: "Full Body", DifficultyLevel: "beginner", TargetAudience: &audience,
+		Blocks: []templateVersionDTO.BlockSnapshot{
+			{BlockName: "Warmup", BlockType: "warmup", BlockOrder: 1, ExerciseCount: 2},
+			{BlockName: "Main", BlockType: "main", BlockOrder: 2, ExerciseCount: 4},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "gym-1".custom_workout_template`)).
+		WithArgs("admin-1", "Full Body", nil, "beginner", nil, &audience, "tpl-1", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("clone-1"))
+	for _, block := range snapshot.Blocks {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "gym-1".custom_template_block`)+`(.+)source_block_name`).
+			WithArgs("admin-1", "clone-1", block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount, nil, nil, nil, nil, nil,
+				"standard", nil, nil, nil, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	id, err := repo.CreateClone("gym-1", "admin-1", "tpl-1", 3, snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, "clone-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindCloneReadsLiveBlocks(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateCloneRepository(db)
+
+	mock.ExpectQuery(`FROM "gym-1".custom_workout_template\s+WHERE id = \$1 AND is_active = TRUE AND source_template_id IS NOT NULL`).
+		WithArgs("clone-1").
+		WillReturnRows(sqlmock.NewRows([]string{"source_template_id", "source_version", "name", "description", "difficulty_level",
+			"estimated_duration_minutes", "target_audience"}).
+			AddRow("tpl-1", 2, "Full Body", nil, "beginner", 45, "strength"))
+	mock.ExpectQuery(`FROM "gym-1".custom_template_block\s+WHERE template_id = \$1 AND deleted_at IS NULL`).
+		WithArgs("clone-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source_block_name", "block_name", "block_type", "block_order", "exercise_count",
+			"estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds",
+			"block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds"}).
+			AddRow("block-1", "Warmup", "Our Warmup", "warmup", 1, 2, nil, nil, 10, nil, nil, "standard", nil, nil, nil, nil).
+			AddRow("block-2", nil, "Stretch", "cooldown", 2, 1, nil, nil, nil, nil, nil, "standard", nil, nil, nil, nil))
+
+	clone, err := repo.FindClone("gym-1", "clone-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, clone.SourceVersion)
+	assert.Equal(t, 45, *clone.Template.EstimatedDurationMinutes)
+	if assert.Len(t, clone.Blocks, 2) {
+		assert.Equal(t, "Warmup", *clone.Blocks[0].SourceBlockName)
+		assert.Equal(t, 10, *clone.Blocks[0].Reps)
+		assert.Nil(t, clone.Blocks[1].SourceBlockName)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyUpdateRollsBackOnFailure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateCloneRepository(db)
+	update := &dto.CloneUpdate{
+		Template:      templateVersionDTO.TemplateSnapshot{Name: "Full Body", DifficultyLevel: "intermediate"},
+		RemovedBlocks: []string{"block-3"},
+		SourceVersion: 2,
+		PulledBy:      "admin-1",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "gym-1".custom_workout_template`)).
+		WithArgs("Full Body", nil, "intermediate", nil, nil, 2, "clone-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "gym-1".custom_template_block\s+SET deleted_at = NOW\(\)`).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	assert.Error(t, repo.ApplyUpdate("gym-1", "clone-1", update))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTemplateCloneRouter(handler interfaces.TemplateCloneHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/{publicTemplateID}", handler.CloneTemplate)     // POST /template-clone/{publicTemplateID}
+	r.Get("/{cloneID}/upstream", handler.GetUpstreamChanges) // GET /template-clone/{cloneID}/upstream
+	r.Post("/{cloneID}/pull", handler.PullUpstreamChanges)   // POST /template-clone/{cloneID}/pull
+
+	return r
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/interfaces"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	templateVersionEnum "github.com/alejandro-albiol/athenai/internal/template_version/enum"
+	templateVersionIF "github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+// gymTargetAudiences are the audiences a gym template accepts; public templates leave the audience free-form
+var gymTargetAudiences = []string{"weight_loss", "muscle_building", "endurance", "strength", "flexibility", "general_fitness", "rehabilitation"}
+
+const defaultTargetAudience = "general_fitness"
+
+var publicSource = string(templateVersionEnum.Public)
+
+type TemplateCloneService struct {
+	repo      interfaces.TemplateCloneRepository
+	templates templateVersionIF.TemplateResolver
+}
+
+func NewTemplateCloneService(repo interfaces.TemplateCloneRepository, templates templateVersionIF.TemplateResolver) *TemplateCloneService {
+	return &TemplateCloneService{repo: repo, templates: templates}
+}
+
+// CloneTemplate copies a published version of a public template and its blocks into the gym's templates
+func (s *TemplateCloneService) CloneTemplate(gymID, publicTemplateID string, clone *dto.CloneTemplateDTO) (*dto.TemplateCloneDTO, error) {
+	if gymID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Templates are cloned into a gym", nil)
+	}
+	shared, err := s.repo.IsSharedPublicTemplate(publicTemplateID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout template", err)
+	}
+	if !shared {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Public workout template not found", nil)
+	}
+
+	versionNumber, err := s.templates.PinVersion("", publicSource, publicTemplateID, clone.TemplateVersion, clone.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.templates.GetVersion("", publicSource, publicTemplateID, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := normalize(version.Snapshot)
+	if clone.Name != nil {
+		snapshot.Name = *clone.Name
+	}
+	if snapshot.Name == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Template name is required", nil)
+	}
+
+	exists, err := s.repo.NameExists(gymID, snapshot.Name)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check template name", err)
+	}
+	if exists {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "A template named '"+snapshot.Name+"' already exists in this gym", nil)
+	}
+
+	id, err := s.repo.CreateClone(gymID, clone.CreatedBy, publicTemplateID, versionNumber, &snapshot)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to clone workout template", err)
+	}
+	return s.findClone(gymID, id)
+}
+
+// GetUpstreamChanges previews what pulling the latest public version into the clone would change
+func (s *TemplateCloneService) GetUpstreamChanges(gymID, cloneID string) (*dto.UpstreamMergeDTO, error) {
+	merge, _, err := s.planMerge(gymID, cloneID)
+	return merge, err
+}
+
+// PullUpstreamChanges applies the upstream changes the gym has not overridden; conflicting fields keep the gym's value
+func (s *TemplateCloneService) PullUpstreamChanges(gymID, cloneID, pulledBy string) (*dto.UpstreamMergeDTO, error) {
+	merge, update, err := s.planMerge(gymID, cloneID)
+	if err != nil || merge.UpToDate {
+		return merge, err
+	}
+	update.PulledBy = pulledBy
+	if err := s.repo.ApplyUpdate(gymID, cloneID, update); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to pull upstream changes", err)
+	}
+	merge.Applied = true
+	return merge, nil
+}
+
+func (s *TemplateCloneService) findClone(gymID, cloneID string) (*dto.TemplateCloneDTO, error) {
+	if gymID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Templates are cloned into a gym", nil)
+	}
+	clone, err := s.repo.FindClone(gymID, cloneID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Cloned workout template not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get cloned workout template", err)
+	}
+	return clone, nil
+}
+
+// planMerge three-way merges the version the clone was taken from, the clone and the latest public version.
+// Blocks are matched through the upstream name they were cloned with; blocks the gym added are left alone.
+func (s *TemplateCloneService) planMerge(gymID, cloneID string) (*dto.UpstreamMergeDTO, *dto.CloneUpdate, error) {
+	clone, err := s.findClone(gymID, cloneID)
+	if err != nil {
+		return nil, nil, err
+	}
+	latest, err := s.templates.PinVersion("", publicSource, clone.SourceTemplateID, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	merge := &dto.UpstreamMergeDTO{
+		CloneID:          clone.ID,
+		SourceTemplateID: clone.SourceTemplateID,
+		FromVersion:      clone.SourceVersion,
+		ToVersion:        latest,
+		UpToDate:         latest <= clone.SourceVersion,
+		TemplateChanges:  []templateVersionDTO.FieldChange{},
+		AddedBlocks:      []string{},
+		RemovedBlocks:    []string{},
+		ChangedBlocks:    []templateVersionDTO.BlockChange{},
+		Conflicts:        []dto.MergeConflict{},
+	}
+	if merge.UpToDate {
+		return merge, nil, nil
+	}
+
+	baseVersion, err := s.templates.GetVersion("", publicSource, clone.SourceTemplateID, clone.SourceVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	upstreamVersion, err := s.templates.GetVersion("", publicSource, clone.SourceTemplateID, latest)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, upstream := normalize(baseVersion.Snapshot), normalize(upstreamVersion.Snapshot)
+	update := &dto.CloneUpdate{SourceVersion: latest}
+
+	changes, conflicts, err := mergeFields(base, clone.Template, upstream, &update.Template)
+	if err != nil {
+		return nil, nil, apierror.New(errorcode_enum.CodeInternal, "Failed to compare template versions", err)
+	}
+	merge.TemplateChanges = changes
+	merge.Conflicts = append(merge.Conflicts, conflicts...)
+
+	baseBlocks := blocksByName(base.Blocks)
+	upstreamBlocks := blocksByName(upstream.Blocks)
+	localBlocks := make(map[string]dto.CloneBlockDTO)
+	for _, block := range clone.Blocks {
+		if block.SourceBlockName != nil {
+			localBlocks[*block.SourceBlockName] = block
+		}
+	}
+
+	for _, block := range upstream.Blocks {
+		baseBlock, existed := baseBlocks[block.BlockName]
+		if !existed {
+			if _, cloned := localBlocks[block.BlockName]; !cloned {
+				update.AddedBlocks = append(update.AddedBlocks, block)
+				merge.AddedBlocks = append(merge.AddedBlocks, block.BlockName)
+			}
+			continue
+		}
+		local, kept := localBlocks[block.BlockName]
+		if !kept {
+			continue // the gym deleted the block
+		}
+		merged := dto.CloneBlockDTO{ID: local.ID, SourceBlockName: local.SourceBlockName}
+		changes, conflicts, err := mergeFields(baseBlock, local.BlockSnapshot, block, &merged.BlockSnapshot)
+		if err != nil {
+			return nil, nil, apierror.New(errorcode_enum.CodeInternal, "Failed to compare template versions", err)
+		}
+		for i := range conflicts {
+			conflicts[i].BlockName = block.BlockName
+		}
+		merge.Conflicts = append(merge.Conflicts, conflicts...)
+		if len(changes) > 0 {
+			update.ChangedBlocks = append(update.ChangedBlocks, merged)
+			merge.ChangedBlocks = append(merge.ChangedBlocks, templateVersionDTO.BlockChange{BlockName: block.BlockName, Changes: changes})
+		}
+	}
+
+	for _, block := range base.Blocks {
+		if _, remains := upstreamBlocks[block.BlockName]; remains {
+			continue
+		}
+		local, kept := localBlocks[block.BlockName]
+		if !kept {
+			continue
+		}
+		if reflect.DeepEqual(local.BlockSnapshot, block) {
+			update.RemovedBlocks = append(update.RemovedBlocks, local.ID)
+			merge.RemovedBlocks = append(merge.RemovedBlocks, block.BlockName)
+			continue
+		}
+		merge.Conflicts = append(merge.Conflicts, dto.MergeConflict{BlockName: block.BlockName, Local: local.BlockSnapshot, Upstream: nil})
+	}
+	return merge, update, nil
+}
+
+// mergeFields takes each upstream change to a field the local copy still has at its base value, writing the result into merged.
+// Fields changed on both sides to different values are reported as conflicts and keep the local value.
+// merged must be a zero value: decoding into set pointers would write through to the local copy.
+func mergeFields(base, local, upstream, merged any) ([]templateVersionDTO.FieldChange, []dto.MergeConflict, error) {
+	baseFields, err := toFields(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	localFields, err := toFields(local)
+	if err != nil {
+		return nil, nil, err
+	}
+	upstreamFields, err := toFields(upstream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := []templateVersionDTO.FieldChange{}
+	conflicts := []dto.MergeConflict{}
+	fields := make([]string, 0, len(upstreamFields))
+	for field := range upstreamFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		value := upstreamFields[field]
+		switch {
+		case reflect.DeepEqual(baseFields[field], value), reflect.DeepEqual(localFields[field], value):
+		case reflect.DeepEqual(localFields[field], baseFields[field]):
+			changes = append(changes, templateVersionDTO.FieldChange{Field: field, From: localFields[field], To: value})
+			localFields[field] = value
+		default:
+			conflicts = append(conflicts, dto.MergeConflict{Field: field, Local: localFields[field], Upstream: value})
+		}
+	}
+
+	content, err := json.Marshal(localFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changes, conflicts, json.Unmarshal(content, merged)
+}
+
+func toFields(value any) (map[string]any, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	err = json.Unmarshal(content, &fields)
+	delete(fields, "blocks")
+	return fields, err
+}
+
+// normalize maps a public template's free-form audience onto one a gym template accepts
+func normalize(snapshot templateVersionDTO.TemplateSnapshot) templateVersionDTO.TemplateSnapshot {
+	if snapshot.TargetAudience == nil || !slices.Contains(gymTargetAudiences, *snapshot.TargetAudience) {
+		audience := defaultTargetAudience
+		snapshot.TargetAudience = &audience
+	}
+	return snapshot
+}
+
+func blocksByName(blocks []templateVersionDTO.BlockSnapshot) map[string]templateVersionDTO.BlockSnapshot {
+	byName := make(map[string]templateVersionDTO.BlockSnapshot, len(blocks))
+	for _, block := range blocks {
+		byName[block.BlockName] = block
+	}
+	return byName
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/template_clone/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_clone/interfaces"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.TemplateCloneRepository
+	shared   bool
+	names    []string
+	created  *templateVersionDTO.TemplateSnapshot
+	pinnedTo int
+	clone    *dto.TemplateCloneDTO
+	applied  *dto.CloneUpdate
+}
+
+func (m *mockRepository) IsSharedPublicTemplate(templateID string) (bool, error) {
+	return m.shared, nil
+}
+func (m *mockRepository) NameExists(gymID, name string) (bool, error) {
+	for _, existing := range m.names {
+		if existing == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockRepository) CreateClone(gymID, createdBy, sourceTemplateID string, sourceVersion int, snapshot *templateVersionDTO.TemplateSnapshot) (string, error) {
+	m.created, m.pinnedTo = snapshot, sourceVersion
+	m.clone = &dto.TemplateCloneDTO{ID: "clone-1", SourceTemplateID: sourceTemplateID, SourceVersion: sourceVersion}
+	return "clone-1", nil
+}
+func (m *mockRepository) FindClone(gymID, cloneID string) (*dto.TemplateCloneDTO, error) {
+	return m.clone, nil
+}
+func (m *mockRepository) ApplyUpdate(gymID, cloneID string, update *dto.CloneUpdate) error {
+	m.applied = update
+	return nil
+}
+
+type mockTemplates struct {
+	versions map[int]templateVersionDTO.TemplateSnapshot
+}
+
+func (m *mockTemplates) PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error) {
+	if requested != nil {
+		return *requested, nil
+	}
+	return len(m.versions), nil
+}
+func (m *mockTemplates) GetVersion(gymID, source, templateID string, versionNumber int) (*templateVersionDTO.TemplateVersionDTO, error) {
+	return &templateVersionDTO.TemplateVersionDTO{TemplateID: templateID, VersionNumber: versionNumber, Snapshot: m.versions[versionNumber]}, nil
+}
+
+func intPtr(i int) *int       { return &i }
+func strPtr(s string) *string { return &s }
+
+func fullBody() templateVersionDTO.TemplateSnapshot {
+	return templateVersionDTO.TemplateSnapshot{
+		Name:            "Full Body",
+		DifficultyLevel: "beginner",
+		TargetAudience:  strPtr("everyone"),
+		Blocks: []templateVersionDTO.BlockSnapshot{
+			{BlockName: "Warmup", BlockType: "warmup", BlockOrder: 1, ExerciseCount: 2, Reps: intPtr(10)},
+			{BlockName: "Main", BlockType: "main", BlockOrder: 2, ExerciseCount: 4, Reps: intPtr(8), Series: intPtr(3)},
+			{BlockName: "Finisher", BlockType: "cardio", BlockOrder: 3, ExerciseCount: 1},
+		},
+	}
+}
+
+// cloned mirrors what the repository reads back for a clone of version 1 of fullBody
+func cloned(snapshot templateVersionDTO.TemplateSnapshot) *dto.TemplateCloneDTO {
+	snapshot = normalize(snapshot)
+	clone := &dto.TemplateCloneDTO{ID: "clone-1", SourceTemplateID: "tpl-1", SourceVersion: 1, Template: snapshot}
+	clone.Template.Blocks = nil
+	for i, block := range snapshot.Blocks {
+		clone.Blocks = append(clone.Blocks, dto.CloneBlockDTO{ID: "block-" + block.BlockName, SourceBlockName: &snapshot.Blocks[i].BlockName, BlockSnapshot: block})
+	}
+	return clone
+}
+
+func TestCloneTemplateCopiesLatestVersion(t *testing.T) {
+	repo := &mockRepository{shared: true}
+	svc := NewTemplateCloneService(repo, &mockTemplates{versions: map[int]templateVersionDTO.TemplateSnapshot{1: fullBody(), 2: fullBody()}})
+
+	clone, err := svc.CloneTemplate("gym-1", "tpl-1", &dto.CloneTemplateDTO{CreatedBy: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "clone-1", clone.ID)
+	assert.Equal(t, 2, repo.pinnedTo)
+	assert.Equal(t, "Full Body", repo.created.Name)
+	assert.Equal(t, defaultTargetAudience, *repo.created.TargetAudience, "free-form public audiences fall back to one gym templates accept")
+	assert.Len(t, repo.created.Blocks, 3)
+}
+
+func TestCloneTemplateValidation(t *testing.T) {
+	templates := &mockTemplates{versions: map[int]templateVersionDTO.TemplateSnapshot{1: fullBody()}}
+
+	_, err := NewTemplateCloneService(&mockRepository{shared: false}, templates).CloneTemplate("gym-1", "tpl-1", &dto.CloneTemplateDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+
+	_, err = NewTemplateCloneService(&mockRepository{shared: true, names: []string{"Full Body"}}, templates).CloneTemplate("gym-1", "tpl-1", &dto.CloneTemplateDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+
+	repo := &mockRepository{shared: true, names: []string{"Full Body"}}
+	_, err = NewTemplateCloneService(repo, templates).CloneTemplate("gym-1", "tpl-1", &dto.CloneTemplateDTO{Name: strPtr("Our Full Body")})
+	require.NoError(t, err)
+	assert.Equal(t, "Our Full Body", repo.created.Name)
+
+	_, err = NewTemplateCloneService(&mockRepository{shared: true}, templates).CloneTemplate("", "tpl-1", &dto.CloneTemplateDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
+
+func TestUpToDateCloneHasNothingToPull(t *testing.T) {
+	repo := &mockRepository{clone: cloned(fullBody())}
+	svc := NewTemplateCloneService(repo, &mockTemplates{versions: map[int]templateVersionDTO.TemplateSnapshot{1: fullBody()}})
+
+	merge, err := svc.PullUpstreamChanges("gym-1", "clone-1", "admin-1")
+	require.NoError(t, err)
+	assert.True(t, merge.UpToDate)
+	assert.False(t, merge.Applied)
+	assert.Nil(t, repo.applied)
+}
+
+func TestPullUpstreamMergesThreeWays(t *testing.T) {
+	clone := cloned(fullBody())
+	// The gym renamed the template, doubled the warmup and reworked the finisher
+	clone.Template.Name = "Our Full Body"
+	clone.Blocks[0].ExerciseCount = 4
+	clone.Blocks[2].ExerciseCount = 3
+	clone.Blocks = append(clone.Blocks, dto.CloneBlockDTO{ID: "block-gym", BlockSnapshot: templateVersionDTO.BlockSnapshot{BlockName: "Stretch", BlockType: "cooldown", BlockOrder: 9}})
+
+	upstream := fullBody()
+	upstream.Name = "Full Body 2.0"
+	upstream.DifficultyLevel = "intermediate"
+	upstream.Blocks[0].ExerciseCount = 3 // conflicts with the gym's 4
+	upstream.Blocks[0].Reps = intPtr(12) // the gym left reps alone
+	upstream.Blocks[1].Series = intPtr(4)
+	upstream.Blocks = append(upstream.Blocks[:2], templateVersionDTO.BlockSnapshot{BlockName: "Core", BlockType: "core", BlockOrder: 3, ExerciseCount: 2})
+
+	repo := &mockRepository{clone: clone}
+	svc := NewTemplateCloneService(repo, &mockTemplates{versions: map[int]templateVersionDTO.TemplateSnapshot{1: fullBody(), 2: upstream}})
+
+	preview, err := svc.GetUpstreamChanges("gym-1", "clone-1")
+	require.NoError(t, err)
+	assert.False(t, preview.Applied)
+	assert.Nil(t, repo.applied)
+
+	merge, err := svc.PullUpstreamChanges("gym-1", "clone-1", "admin-1")
+	require.NoError(t, err)
+	assert.True(t, merge.Applied)
+	assert.Equal(t, 1, merge.FromVersion)
+	assert.Equal(t, 2, merge.ToVersion)
+
+	if assert.Len(t, merge.TemplateChanges, 1) {
+		assert.Equal(t, "difficulty_level", merge.TemplateChanges[0].Field)
+	}
+	assert.Equal(t, []string{"Core"}, merge.AddedBlocks)
+	assert.Empty(t, merge.RemovedBlocks, "the gym changed the finisher upstream removed")
+	assert.Len(t, merge.ChangedBlocks, 2)
+	if assert.Len(t, merge.Conflicts, 3) {
+		assert.Equal(t, dto.MergeConflict{Field: "name", Local: "Our Full Body", Upstream: "Full Body 2.0"}, merge.Conflicts[0])
+		assert.Equal(t, "Warmup", merge.Conflicts[1].BlockName)
+		assert.Equal(t, "exercise_count", merge.Conflicts[1].Field)
+		assert.Equal(t, "Finisher", merge.Conflicts[2].BlockName)
+		assert.Empty(t, merge.Conflicts[2].Field)
+	}
+
+	applied := repo.applied
+	require.NotNil(t, applied)
+	assert.Equal(t, 2, applied.SourceVersion)
+	assert.Equal(t, "admin-1", applied.PulledBy)
+	assert.Equal(t, "Our Full Body", applied.Template.Name)
+	assert.Equal(t, "intermediate", applied.Template.DifficultyLevel)
+	if assert.Len(t, applied.ChangedBlocks, 2) {
+		warmup := applied.ChangedBlocks[0]
+		assert.Equal(t, "block-Warmup", warmup.ID)
+		assert.Equal(t, 4, warmup.ExerciseCount)
+		assert.Equal(t, 12, *warmup.Reps)
+		assert.Equal(t, 4, *applied.ChangedBlocks[1].Series)
+	}
+	assert.Empty(t, applied.RemovedBlocks)
+	if assert.Len(t, applied.AddedBlocks, 1) {
+		assert.Equal(t, "Core", applied.AddedBlocks[0].BlockName)
+	}
+}
+
+func TestPullRemovesBlocksTheGymLeftAlone(t *testing.T) {
+	upstream := fullBody()
+	upstream.Blocks = upstream.Blocks[:2]
+
+	repo := &mockRepository{clone: cloned(fullBody())}
+	svc := NewTemplateCloneService(repo, &mockTemplates{versions: map[int]templateVersionDTO.TemplateSnapshot{1: fullBody(), 2: upstream}})
+
+	merge, err := svc.PullUpstreamChanges("gym-1", "clone-1", "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Finisher"}, merge.RemovedBlocks)
+	assert.Empty(t, merge.Conflicts)
+	assert.Equal(t, []string{"block-Finisher"}, repo.applied.RemovedBlocks)
+}
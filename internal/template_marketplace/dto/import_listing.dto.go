@@ -0,0 +1,24 @@
+package dto
+
+// ImportListingDTO copies an approved listing into the gym.
+// Bundled gym exercises are carried over as new custom exercises unless substituted, keyed by their source_id.
+type ImportListingDTO struct {
+	Name          *string                         `json:"name,omitempty"` // defaults to the template's name
+	Substitutions map[string]ExerciseSubstitution `json:"substitutions,omitempty"`
+	ImportedBy    string                          `json:"-"`
+}
+
+// ExerciseSubstitution is an exercise the importing gym already has, used in place of a bundled one
+type ExerciseSubstitution struct {
+	ExerciseSource   string  `json:"exercise_source"`
+	PublicExerciseID *string `json:"public_exercise_id,omitempty"`
+	GymExerciseID    *string `json:"gym_exercise_id,omitempty"`
+}
+
+type ImportResultDTO struct {
+	ListingID            string                          `json:"listing_id"`
+	TemplateID           string                          `json:"template_id"`
+	SampleInstanceID     *string                         `json:"sample_instance_id,omitempty"`
+	CarriedExercises     map[string]string               `json:"carried_exercises"` // source_id to the new custom exercise id
+	SubstitutedExercises map[string]ExerciseSubstitution `json:"substituted_exercises"`
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+)
+
+// PublishListingDTO submits the latest version of a gym template for moderation
+type PublishListingDTO struct {
+	Title            *string `json:"title,omitempty"` // defaults to the template's name
+	Summary          *string `json:"summary,omitempty"`
+	SampleInstanceID *string `json:"sample_instance_id,omitempty"` // workout instance of the template whose exercises are bundled
+	PublishedBy      string  `json:"-"`
+}
+
+// TemplateListingDTO is a gym template offered to other gyms, with where it came from and how it was received
+type TemplateListingDTO struct {
+	ID               string                              `json:"id"`
+	SourceGymID      string                              `json:"source_gym_id"`
+	SourceGymName    string                              `json:"source_gym_name"`
+	SourceTemplateID string                              `json:"source_template_id"`
+	SourceVersion    int                                 `json:"source_version"`
+	PublishedBy      string                              `json:"published_by"`
+	Title            string                              `json:"title"`
+	Summary          *string                             `json:"summary,omitempty"`
+	Snapshot         templateVersionDTO.TemplateSnapshot `json:"snapshot"`
+	Exercises        []ListingExercise                   `json:"exercises"`
+	Status           string                              `json:"status"`
+	ReviewNotes      *string                             `json:"review_notes,omitempty"`
+	ReviewedBy       *string                             `json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time                          `json:"reviewed_at,omitempty"`
+	AverageRating    *float64                            `json:"average_rating"`
+	RatingCount      int                                 `json:"rating_count"`
+	ImportCount      int                                 `json:"import_count"`
+	CreatedAt        time.Time                           `json:"created_at"`
+	UpdatedAt        time.Time                           `json:"updated_at"`
+}
+
+// ListingExercise is one exercise of the bundled sample workout.
+// Gym exercises are copied in full since importing gyms cannot read the publishing gym's schema.
+type ListingExercise struct {
+	BlockName        string                 `json:"block_name"`
+	ExerciseOrder    int                    `json:"exercise_order"`
+	ExerciseSource   string                 `json:"exercise_source"`
+	PublicExerciseID *string                `json:"public_exercise_id,omitempty"`
+	CustomExercise   *ListingCustomExercise `json:"custom_exercise,omitempty"`
+	Sets             *int                   `json:"sets,omitempty"`
+	RepsMin          *int                   `json:"reps_min,omitempty"`
+	RepsMax          *int                   `json:"reps_max,omitempty"`
+	WeightKg         *float64               `json:"weight_kg,omitempty"`
+	DurationSeconds  *int                   `json:"duration_seconds,omitempty"`
+	RestSeconds      *int                   `json:"rest_seconds,omitempty"`
+	Notes            *string                `json:"notes,omitempty"`
+}
+
+// ListingCustomExercise is a publishing gym's exercise; only its links to public muscles and equipment travel with it
+type ListingCustomExercise struct {
+	SourceID           string                 `json:"source_id"` // id in the publishing gym, used to substitute the exercise on import
+	Name               string                 `json:"name"`
+	Synonyms           string                 `json:"synonyms"`
+	DifficultyLevel    string                 `json:"difficulty_level"`
+	ExerciseType       string                 `json:"exercise_type"`
+	Instructions       string                 `json:"instructions"`
+	VideoURL           *string                `json:"video_url,omitempty"`
+	ImageURL           *string                `json:"image_url,omitempty"`
+	MuscularGroups     []ListingMuscularGroup `json:"muscular_groups"`
+	PublicEquipmentIDs []string               `json:"public_equipment_ids"`
+}
+
+type ListingMuscularGroup struct {
+	MuscularGroupID  string  `json:"muscular_group_id"`
+	Role             string  `json:"role"`
+	ActivationWeight float64 `json:"activation_weight"`
+}
+
+// ListingFilter narrows marketplace browsing; empty fields match everything
+type ListingFilter struct {
+	Search          string
+	DifficultyLevel string
+	Sort            string
+}
+
+type ReviewListingDTO struct {
+	Notes      *string `json:"notes,omitempty"`
+	ReviewedBy string  `json:"-"`
+}
+
+type RateListingDTO struct {
+	Rating  int     `json:"rating"`
+	Review  *string `json:"review,omitempty"`
+	RatedBy string  `json:"-"`
+}
@@ -0,0 +1,18 @@
+package enum
+
+// ListingSort orders marketplace browsing
+type ListingSort string
+
+const (
+	Newest  ListingSort = "newest"
+	Rating  ListingSort = "rating"
+	Imports ListingSort = "imports"
+)
+
+func (s ListingSort) IsValid() bool {
+	switch s {
+	case Newest, Rating, Imports:
+		return true
+	}
+	return false
+}
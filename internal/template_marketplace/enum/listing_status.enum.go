@@ -0,0 +1,12 @@
+package enum
+
+// ListingStatus is where a marketplace listing is in moderation
+type ListingStatus string
+
+const (
+	Pending    ListingStatus = "pending"
+	Approved   ListingStatus = "approved"
+	Rejected   ListingStatus = "rejected"
+	Withdrawn  ListingStatus = "withdrawn"
+	Superseded ListingStatus = "superseded" // replaced by a newer approved listing of the same template
+)
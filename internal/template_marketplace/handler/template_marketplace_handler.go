@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TemplateMarketplaceHandler struct {
+	service interfaces.TemplateMarketplaceService
+}
+
+func NewTemplateMarketplaceHandler(service interfaces.TemplateMarketplaceService) *TemplateMarketplaceHandler {
+	return &TemplateMarketplaceHandler{service: service}
+}
+
+func (h *TemplateMarketplaceHandler) PublishTemplate(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var publish dto.PublishListingDTO
+	if !decodeOptional(w, r, &publish) {
+		return
+	}
+	publish.PublishedBy = middleware.GetUserID(r)
+
+	listing, err := h.service.PublishTemplate(middleware.GetGymID(r), chi.URLParam(r, "templateID"), &publish)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to publish template listing")
+		return
+	}
+	response.WriteAPICreated(w, "Template submitted for review successfully", listing)
+}
+
+func (h *TemplateMarketplaceHandler) WithdrawListing(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.WithdrawListing(middleware.GetGymID(r), chi.URLParam(r, "listingID")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to withdraw template listing")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listing withdrawn successfully", nil)
+}
+
+func (h *TemplateMarketplaceHandler) ListGymListings(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	listings, err := h.service.ListGymListings(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list template listings")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listings retrieved successfully", listings)
+}
+
+func (h *TemplateMarketplaceHandler) ListPendingListings(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	listings, err := h.service.ListPendingListings()
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list template listings")
+		return
+	}
+	response.WriteAPISuccess(w, "Pending template listings retrieved successfully", listings)
+}
+
+func (h *TemplateMarketplaceHandler) ApproveListing(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	var review dto.ReviewListingDTO
+	if !decodeOptional(w, r, &review) {
+		return
+	}
+	review.ReviewedBy = middleware.GetUserID(r)
+
+	if err := h.service.ApproveListing(chi.URLParam(r, "listingID"), &review); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to approve template listing")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listing approved successfully", nil)
+}
+
+func (h *TemplateMarketplaceHandler) RejectListing(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	var review dto.ReviewListingDTO
+	if !decodeOptional(w, r, &review) {
+		return
+	}
+	review.ReviewedBy = middleware.GetUserID(r)
+
+	if err := h.service.RejectListing(chi.URLParam(r, "listingID"), &review); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to reject template listing")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listing rejected successfully", nil)
+}
+
+func (h *TemplateMarketplaceHandler) BrowseListings(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	filter := dto.ListingFilter{
+		Search:          query.Get("search"),
+		DifficultyLevel: query.Get("difficulty_level"),
+		Sort:            query.Get("sort"),
+	}
+	listings, err := h.service.BrowseListings(filter)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to browse template listings")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listings retrieved successfully", listings)
+}
+
+func (h *TemplateMarketplaceHandler) GetListing(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	listing, err := h.service.GetListing(middleware.GetGymID(r), middleware.IsPlatformAdmin(r), chi.URLParam(r, "listingID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get template listing")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listing retrieved successfully", listing)
+}
+
+func (h *TemplateMarketplaceHandler) ImportListing(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var importListing dto.ImportListingDTO
+	if !decodeOptional(w, r, &importListing) {
+		return
+	}
+	importListing.ImportedBy = middleware.GetUserID(r)
+
+	result, err := h.service.ImportListing(middleware.GetGymID(r), chi.URLParam(r, "listingID"), &importListing)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to import template listing")
+		return
+	}
+	response.WriteAPICreated(w, "Template listing imported successfully", result)
+}
+
+func (h *TemplateMarketplaceHandler) RateListing(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var rating dto.RateListingDTO
+	if err := json.NewDecoder(r.Body).Decode(&rating); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	rating.RatedBy = middleware.GetUserID(r)
+
+	if err := h.service.RateListing(middleware.GetGymID(r), chi.URLParam(r, "listingID"), &rating); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to rate template listing")
+		return
+	}
+	response.WriteAPISuccess(w, "Template listing rated successfully", nil)
+}
+
+// decodeOptional decodes a request body that may be left out, writing a 400 when it is malformed
+func decodeOptional(w http.ResponseWriter, r *http.Request, body any) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return false
+	}
+	return true
+}
+
+// requireAdmin writes a 403 unless the caller administers a gym or the platform
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only administrators can browse the template marketplace", nil))
+	return false
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym that publishes, imports or rates
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can share and import templates", nil))
+	return false
+}
+
+func requirePlatformAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsPlatformAdmin(r) {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only platform administrators can review template listings", nil))
+	return false
+}
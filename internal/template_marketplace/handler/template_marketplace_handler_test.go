@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.TemplateMarketplaceService
+	published *dto.PublishListingDTO
+	reviewed  *dto.ReviewListingDTO
+	filter    dto.ListingFilter
+	imported  *dto.ImportListingDTO
+}
+
+func (m *mockService) PublishTemplate(gymID, templateID string, publish *dto.PublishListingDTO) (*dto.TemplateListingDTO, error) {
+	m.published = publish
+	return &dto.TemplateListingDTO{ID: "listing-1", SourceGymID: gymID, SourceTemplateID: templateID}, nil
+}
+func (m *mockService) ApproveListing(listingID string, review *dto.ReviewListingDTO) error {
+	m.reviewed = review
+	return nil
+}
+func (m *mockService) BrowseListings(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error) {
+	m.filter = filter
+	return []*dto.TemplateListingDTO{}, nil
+}
+func (m *mockService) ImportListing(gymID, listingID string, importListing *dto.ImportListingDTO) (*dto.ImportResultDTO, error) {
+	m.imported = importListing
+	return &dto.ImportResultDTO{ListingID: listingID, TemplateID: "template-1"}, nil
+}
+
+func serve(svc *mockService, method, target, body, userType, role, gymID string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewTemplateMarketplaceRouter(NewTemplateMarketplaceHandler(svc)),
+		testutil.Caller{UserType: userType, Role: role, UserID: "user-1", GymID: gymID}, method, target, body)
+}
+
+func TestPublishTemplateRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/publish/tpl-1", "", "platform_admin", "", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodPost, "/publish/tpl-1", `{"summary":"Heavy legs"}`, "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Heavy legs", *svc.published.Summary)
+	assert.Equal(t, "user-1", svc.published.PublishedBy)
+}
+
+func TestApproveListingRequiresPlatformAdmin(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/listing-1/approve", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.reviewed)
+
+	w = serve(svc, http.MethodPost, "/listing-1/approve", "", "platform_admin", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user-1", svc.reviewed.ReviewedBy)
+}
+
+func TestBrowseListingsReadsFilter(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/?search=legs&sort=rating", "", "tenant_user", "member", "gym-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(svc, http.MethodGet, "/?search=legs&sort=rating", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, dto.ListingFilter{Search: "legs", Sort: "rating"}, svc.filter)
+}
+
+func TestImportListing(t *testing.T) {
+	svc := &mockService{}
+	body := `{"substitutions":{"sled-push":{"exercise_source":"public","public_exercise_id":"prowler"}}}`
+
+	w := serve(svc, http.MethodPost, "/listing-1/import", body, "tenant_user", "admin", "gym-2")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "prowler", *svc.imported.Substitutions["sled-push"].PublicExerciseID)
+	assert.Equal(t, "user-1", svc.imported.ImportedBy)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import "net/http"
+
+type TemplateMarketplaceHandler interface {
+	PublishTemplate(w http.ResponseWriter, r *http.Request)
+	WithdrawListing(w http.ResponseWriter, r *http.Request)
+	ListGymListings(w http.ResponseWriter, r *http.Request)
+	ListPendingListings(w http.ResponseWriter, r *http.Request)
+	ApproveListing(w http.ResponseWriter, r *http.Request)
+	RejectListing(w http.ResponseWriter, r *http.Request)
+	BrowseListings(w http.ResponseWriter, r *http.Request)
+	GetListing(w http.ResponseWriter, r *http.Request)
+	ImportListing(w http.ResponseWriter, r *http.Request)
+	RateListing(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+
+type TemplateMarketplaceRepository interface {
+	// FindSampleExercises returns sql.ErrNoRows unless the instance was built from the gym template
+	FindSampleExercises(gymID, templateID, instanceID string) ([]dto.ListingExercise, error)
+	HasPendingListing(gymID, templateID string) (bool, error)
+	Create(listing *dto.TemplateListingDTO) (string, error)
+	FindByID(listingID string) (*dto.TemplateListingDTO, error)
+	FindApproved(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error)
+	FindByStatus(status string) ([]*dto.TemplateListingDTO, error)
+	FindByGym(gymID string) ([]*dto.TemplateListingDTO, error)
+	// Approve supersedes earlier approved listings of the same template
+	Approve(listingID, reviewedBy string, notes *string) error
+	SetStatus(listingID, status string, reviewedBy, notes *string) error
+
+	NameExists(gymID, name string) (bool, error)
+	ExerciseExists(gymID, source, exerciseID string) (bool, error)
+	Import(gymID string, listing *dto.TemplateListingDTO, name string, substitutions map[string]dto.ExerciseSubstitution, importedBy string) (*dto.ImportResultDTO, error)
+	HasImported(listingID, gymID string) (bool, error)
+	SaveRating(listingID, gymID string, rating *dto.RateListingDTO) error
+}
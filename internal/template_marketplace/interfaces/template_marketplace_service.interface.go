@@ -0,0 +1,19 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+
+type TemplateMarketplaceService interface {
+	PublishTemplate(gymID, templateID string, publish *dto.PublishListingDTO) (*dto.TemplateListingDTO, error)
+	WithdrawListing(gymID, listingID string) error
+	ListGymListings(gymID string) ([]*dto.TemplateListingDTO, error)
+
+	ListPendingListings() ([]*dto.TemplateListingDTO, error)
+	ApproveListing(listingID string, review *dto.ReviewListingDTO) error
+	RejectListing(listingID string, review *dto.ReviewListingDTO) error
+
+	BrowseListings(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error)
+	// GetListing shows approved listings to everyone and any listing to its own gym and platform administrators
+	GetListing(gymID string, platformAdmin bool, listingID string) (*dto.TemplateListingDTO, error)
+	ImportListing(gymID, listingID string, importListing *dto.ImportListingDTO) (*dto.ImportResultDTO, error)
+	RateListing(gymID, listingID string, rating *dto.RateListingDTO) error
+}
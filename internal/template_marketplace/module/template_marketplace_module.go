@@ -0,0 +1,19 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/handler"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/repository"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/router"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/service"
+	templateVersionModule "github.com/alejandro-albiol/athenai/internal/template_version/module"
+)
+
+func NewTemplateMarketplaceModule(db *sql.DB) http.Handler {
+	repo := repository.NewTemplateMarketplaceRepository(db)
+	service := service.NewTemplateMarketplaceService(repo, templateVersionModule.NewTemplateVersionService(db))
+	handler := handler.NewTemplateMarketplaceHandler(service)
+	return router.NewTemplateMarketplaceRouter(handler)
+}
@@ -0,0 +1,425 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/enum"
+	"github.com/lib/pq"
+)
+
+type TemplateMarketplaceRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateMarketplaceRepository(db *sql.DB) *TemplateMarketplaceRepository {
+	return &TemplateMarketplaceRepository{db: db}
+}
+
+const selectListings = `SELECT l.id, l.source_gym_id, g.name, l.source_template_id, l.source_version, l.published_by, l.title, l.summary,
+	l.snapshot, l.exercises, l.status, l.review_notes, l.reviewed_by, l.reviewed_at, l.created_at, l.updated_at,
+	(SELECT AVG(rating)::float8 FROM public.template_listing_rating r WHERE r.listing_id = l.id) AS average_rating,
+	(SELECT COUNT(*) FROM public.template_listing_rating r WHERE r.listing_id = l.id) AS rating_count,
+	(SELECT COUNT(*) FROM public.template_listing_import i WHERE i.listing_id = l.id) AS import_count
+	FROM public.template_listing l
+	JOIN public.gym g ON g.id = l.source_gym_id`
+
+var sortOrders = map[enum.ListingSort]string{
+	enum.Newest:  "l.created_at DESC",
+	enum.Rating:  "average_rating DESC NULLS LAST, rating_count DESC, l.created_at DESC",
+	enum.Imports: "import_count DESC, l.created_at DESC",
+}
+
+func (r *TemplateMarketplaceRepository) FindSampleExercises(gymID, templateID, instanceID string) ([]dto.ListingExercise, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	var id string
+	if err := r.db.QueryRow(fmt.Sprintf(`SELECT id FROM %s.custom_workout_instance
+		WHERE id = $1 AND template_source = 'gym' AND gym_template_id = $2`, schema), instanceID, templateID).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT block_name, exercise_order, exercise_source, public_exercise_id, gym_exercise_id,
+		sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes
+		FROM %s.custom_workout_exercise
+		WHERE workout_instance_id = $1
+		ORDER BY block_name, exercise_order`, schema), instanceID)
+	if err != nil {
+		return nil, err
+	}
+	exercises := []dto.ListingExercise{}
+	var gymExerciseIDs []*string
+	for rows.Next() {
+		var exercise dto.ListingExercise
+		var gymExerciseID *string
+		if err := rows.Scan(&exercise.BlockName, &exercise.ExerciseOrder, &exercise.ExerciseSource, &exercise.PublicExerciseID, &gymExerciseID,
+			&exercise.Sets, &exercise.RepsMin, &exercise.RepsMax, &exercise.WeightKg, &exercise.DurationSeconds, &exercise.RestSeconds,
+			&exercise.Notes); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+		gymExerciseIDs = append(gymExerciseIDs, gymExerciseID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	copies := map[string]*dto.ListingCustomExercise{}
+	for i, id := range gymExerciseIDs {
+		if id == nil {
+			continue
+		}
+		if copies[*id] == nil {
+			if copies[*id], err = r.copyCustomExercise(schema, *id); err != nil {
+				return nil, err
+			}
+		}
+		exercises[i].CustomExercise = copies[*id]
+	}
+	return exercises, nil
+}
+
+// copyCustomExercise reads a gym exercise with its public muscle and equipment links; links to gym equipment stay behind
+func (r *TemplateMarketplaceRepository) copyCustomExercise(schema, exerciseID string) (*dto.ListingCustomExercise, error) {
+	exercise := &dto.ListingCustomExercise{SourceID: exerciseID, MuscularGroups: []dto.ListingMuscularGroup{}, PublicEquipmentIDs: []string{}}
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url
+		FROM %s.custom_exercise WHERE id = $1`, schema), exerciseID).Scan(
+		&exercise.Name, &exercise.Synonyms, &exercise.DifficultyLevel, &exercise.ExerciseType, &exercise.Instructions,
+		&exercise.VideoURL, &exercise.ImageURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT muscular_group_id, role, activation_weight
+		FROM %s.custom_exercise_muscular_group WHERE custom_exercise_id = $1 ORDER BY muscular_group_id`, schema), exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var group dto.ListingMuscularGroup
+		if err := rows.Scan(&group.MuscularGroupID, &group.Role, &group.ActivationWeight); err != nil {
+			return nil, err
+		}
+		exercise.MuscularGroups = append(exercise.MuscularGroups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	equipment, err := r.db.Query(fmt.Sprintf(`SELECT public_equipment_id
+		FROM %s.custom_exercise_equipment WHERE custom_exercise_id = $1 AND equipment_source = 'public' ORDER BY public_equipment_id`, schema), exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer equipment.Close()
+	for equipment.Next() {
+		var id string
+		if err := equipment.Scan(&id); err != nil {
+			return nil, err
+		}
+		exercise.PublicEquipmentIDs = append(exercise.PublicEquipmentIDs, id)
+	}
+	return exercise, equipment.Err()
+}
+
+func (r *TemplateMarketplaceRepository) HasPendingListing(gymID, templateID string) (bool, error) {
+	var pending bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM public.template_listing
+		WHERE source_gym_id = $1 AND source_template_id = $2 AND status = $3)`, gymID, templateID, enum.Pending).Scan(&pending)
+	return pending, err
+}
+
+func (r *TemplateMarketplaceRepository) Create(listing *dto.TemplateListingDTO) (string, error) {
+	snapshot, err := json.Marshal(listing.Snapshot)
+	if err != nil {
+		return "", err
+	}
+	exercises, err := json.Marshal(listing.Exercises)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = r.db.QueryRow(`INSERT INTO public.template_listing
+		(source_gym_id, source_template_id, source_version, published_by, title, summary, snapshot, exercises, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		listing.SourceGymID, listing.SourceTemplateID, listing.SourceVersion, listing.PublishedBy, listing.Title, listing.Summary,
+		snapshot, exercises, listing.Status,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *TemplateMarketplaceRepository) FindByID(listingID string) (*dto.TemplateListingDTO, error) {
+	return scanListing(r.db.QueryRow(selectListings+` WHERE l.id = $1`, listingID))
+}
+
+func (r *TemplateMarketplaceRepository) FindApproved(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error) {
+	args := []any{enum.Approved}
+	conditions := []string{"l.status = $1"}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(l.title ILIKE $%[1]d OR l.summary ILIKE $%[1]d)", len(args)))
+	}
+	if filter.DifficultyLevel != "" {
+		args = append(args, filter.DifficultyLevel)
+		conditions = append(conditions, fmt.Sprintf("l.snapshot->>'difficulty_level' = $%d", len(args)))
+	}
+	order, ok := sortOrders[enum.ListingSort(filter.Sort)]
+	if !ok {
+		order = sortOrders[enum.Newest]
+	}
+	return r.queryListings(selectListings+" WHERE "+strings.Join(conditions, " AND ")+" ORDER BY "+order, args...)
+}
+
+func (r *TemplateMarketplaceRepository) FindByStatus(status string) ([]*dto.TemplateListingDTO, error) {
+	return r.queryListings(selectListings+` WHERE l.status = $1 ORDER BY l.created_at`, status)
+}
+
+func (r *TemplateMarketplaceRepository) FindByGym(gymID string) ([]*dto.TemplateListingDTO, error) {
+	return r.queryListings(selectListings+` WHERE l.source_gym_id = $1 ORDER BY l.created_at DESC`, gymID)
+}
+
+func (r *TemplateMarketplaceRepository) queryListings(query string, args ...any) ([]*dto.TemplateListingDTO, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []*dto.TemplateListingDTO
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, listing)
+	}
+	return listings, rows.Err()
+}
+
+func (r *TemplateMarketplaceRepository) Approve(listingID, reviewedBy string, notes *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE public.template_listing previous SET status = $1, updated_at = NOW()
+		FROM public.template_listing l
+		WHERE l.id = $2 AND previous.source_gym_id = l.source_gym_id AND previous.source_template_id = l.source_template_id AND previous.status = $3`,
+		enum.Superseded, listingID, enum.Approved); err != nil {
+		return err
+	}
+	if err := setStatus(tx, listingID, string(enum.Approved), &reviewedBy, notes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *TemplateMarketplaceRepository) SetStatus(listingID, status string, reviewedBy, notes *string) error {
+	return setStatus(r.db, listingID, status, reviewedBy, notes)
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// setStatus records the reviewer only for moderation decisions; a gym withdrawing its listing passes none
+func setStatus(db execer, listingID, status string, reviewedBy, notes *string) error {
+	result, err := db.Exec(`UPDATE public.template_listing
+		SET status = $1,
+			reviewed_by = COALESCE($2, reviewed_by),
+			review_notes = COALESCE($3, review_notes),
+			reviewed_at = CASE WHEN $2::uuid IS NULL THEN reviewed_at ELSE NOW() END,
+			updated_at = NOW()
+		WHERE id = $4`, status, reviewedBy, notes, listingID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *TemplateMarketplaceRepository) NameExists(gymID, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.custom_workout_template WHERE name = $1 AND is_active = TRUE)`,
+		pq.QuoteIdentifier(gymID)), name).Scan(&exists)
+	return exists, err
+}
+
+func (r *TemplateMarketplaceRepository) ExerciseExists(gymID, source, exerciseID string) (bool, error) {
+	table := "public.exercise"
+	if source == "gym" {
+		table = pq.QuoteIdentifier(gymID) + ".custom_exercise"
+	}
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND is_active = TRUE)`, table), exerciseID).Scan(&exists)
+	return exists, err
+}
+
+// Import writes the template, its first version, carried exercises and the sample workout in one transaction
+func (r *TemplateMarketplaceRepository) Import(gymID string, listing *dto.TemplateListingDTO, name string, substitutions map[string]dto.ExerciseSubstitution, importedBy string) (*dto.ImportResultDTO, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &dto.ImportResultDTO{
+		ListingID:            listing.ID,
+		CarriedExercises:     map[string]string{},
+		SubstitutedExercises: map[string]dto.ExerciseSubstitution{},
+	}
+	snapshot := listing.Snapshot
+	snapshot.Name = name
+	err = tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.custom_workout_template
+		(created_by, name, description, difficulty_level, estimated_duration_minutes, target_audience, source_listing_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`, schema),
+		importedBy, snapshot.Name, snapshot.Description, snapshot.DifficultyLevel, snapshot.EstimatedDurationMinutes, snapshot.TargetAudience,
+		listing.ID,
+	).Scan(&result.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range snapshot.Blocks {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_template_block
+			(created_by, template_id, block_name, block_type, block_order, exercise_count, estimated_duration_minutes,
+			instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`, schema),
+			importedBy, result.TemplateID, block.BlockName, block.BlockType, block.BlockOrder, block.ExerciseCount,
+			block.EstimatedDurationMinutes, block.Instructions, block.Reps, block.Series, block.RestTimeSeconds,
+			string(block.Mode()), block.WorkSeconds, block.IntervalRestSeconds, block.Rounds, block.TimeCapSeconds,
+		); err != nil {
+			return nil, err
+		}
+	}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_workout_template_version (template_id, version_number, snapshot, notes, published_by)
+		VALUES ($1, 1, $2, $3, $4)`, schema), result.TemplateID, content, "Imported from the template marketplace", importedBy); err != nil {
+		return nil, err
+	}
+
+	if len(listing.Exercises) > 0 {
+		var instanceID string
+		if err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.custom_workout_instance
+			(created_by, name, description, template_source, gym_template_id, template_version)
+			VALUES ($1, $2, $3, 'gym', $4, 1) RETURNING id`, schema),
+			importedBy, snapshot.Name, listing.Summary, result.TemplateID,
+		).Scan(&instanceID); err != nil {
+			return nil, err
+		}
+		result.SampleInstanceID = &instanceID
+	}
+	for _, exercise := range listing.Exercises {
+		source, publicID, gymExerciseID := exercise.ExerciseSource, exercise.PublicExerciseID, (*string)(nil)
+		if custom := exercise.CustomExercise; custom != nil {
+			if substitution, ok := substitutions[custom.SourceID]; ok {
+				source, publicID, gymExerciseID = substitution.ExerciseSource, substitution.PublicExerciseID, substitution.GymExerciseID
+				result.SubstitutedExercises[custom.SourceID] = substitution
+			} else {
+				if _, carried := result.CarriedExercises[custom.SourceID]; !carried {
+					if result.CarriedExercises[custom.SourceID], err = carryExercise(tx, schema, custom, importedBy); err != nil {
+						return nil, err
+					}
+				}
+				id := result.CarriedExercises[custom.SourceID]
+				source, publicID, gymExerciseID = "gym", nil, &id
+			}
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_workout_exercise
+			(created_by, workout_instance_id, exercise_source, public_exercise_id, gym_exercise_id, block_name, exercise_order,
+			sets, reps_min, reps_max, weight_kg, duration_seconds, rest_seconds, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`, schema),
+			importedBy, *result.SampleInstanceID, source, publicID, gymExerciseID, exercise.BlockName, exercise.ExerciseOrder,
+			exercise.Sets, exercise.RepsMin, exercise.RepsMax, exercise.WeightKg, exercise.DurationSeconds, exercise.RestSeconds, exercise.Notes,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO public.template_listing_import (listing_id, gym_id, template_id, imported_by) VALUES ($1, $2, $3, $4)`,
+		listing.ID, gymID, result.TemplateID, importedBy); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit()
+}
+
+// carryExercise creates a copy of a bundled exercise in the importing gym
+func carryExercise(tx *sql.Tx, schema string, exercise *dto.ListingCustomExercise, createdBy string) (string, error) {
+	var id string
+	err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.custom_exercise
+		(created_by, name, synonyms, difficulty_level, exercise_type, instructions, video_url, image_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, schema),
+		createdBy, exercise.Name, exercise.Synonyms, exercise.DifficultyLevel, exercise.ExerciseType, exercise.Instructions,
+		exercise.VideoURL, exercise.ImageURL,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	for _, group := range exercise.MuscularGroups {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_exercise_muscular_group (custom_exercise_id, muscular_group_id, role, activation_weight)
+			VALUES ($1, $2, $3, $4)`, schema), id, group.MuscularGroupID, group.Role, group.ActivationWeight); err != nil {
+			return "", err
+		}
+	}
+	for _, equipmentID := range exercise.PublicEquipmentIDs {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_exercise_equipment (custom_exercise_id, equipment_source, public_equipment_id)
+			VALUES ($1, 'public', $2)`, schema), id, equipmentID); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (r *TemplateMarketplaceRepository) HasImported(listingID, gymID string) (bool, error) {
+	var imported bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM public.template_listing_import WHERE listing_id = $1 AND gym_id = $2)`,
+		listingID, gymID).Scan(&imported)
+	return imported, err
+}
+
+func (r *TemplateMarketplaceRepository) SaveRating(listingID, gymID string, rating *dto.RateListingDTO) error {
+	_, err := r.db.Exec(`INSERT INTO public.template_listing_rating (listing_id, gym_id, rating, review, rated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (listing_id, gym_id) DO UPDATE
+		SET rating = EXCLUDED.rating, review = EXCLUDED.review, rated_by = EXCLUDED.rated_by, updated_at = NOW()`,
+		listingID, gymID, rating.Rating, rating.Review, rating.RatedBy)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanListing(row rowScanner) (*dto.TemplateListingDTO, error) {
+	listing := &dto.TemplateListingDTO{}
+	var snapshot, exercises []byte
+	if err := row.Scan(&listing.ID, &listing.SourceGymID, &listing.SourceGymName, &listing.SourceTemplateID, &listing.SourceVersion,
+		&listing.PublishedBy, &listing.Title, &listing.Summary, &snapshot, &exercises, &listing.Status, &listing.ReviewNotes,
+		&listing.ReviewedBy, &listing.ReviewedAt, &listing.CreatedAt, &listing.UpdatedAt, &listing.AverageRating, &listing.RatingCount,
+		&listing.ImportCount); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &listing.Snapshot); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(exercises, &listing.Exercises); err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var listingColumns = []string{"id", "source_gym_id", "name", "source_template_id", "source_version", "published_by", "title", "summary",
+	"snapshot", "exercises", "status", "review_notes", "reviewed_by", "reviewed_at", "created_at", "updated_at",
+	"average_rating", "rating_count", "import_count"}
+
+func TestFindApprovedFiltersAndSorts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateMarketplaceRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`WHERE l.status = \$1 AND \(l.title ILIKE \$2 OR l.summary ILIKE \$2\) AND l.snapshot->>'difficulty_level' = \$3 ORDER BY average_rating DESC NULLS LAST`).
+		WithArgs("approved", "%legs%", "advanced").
+		WillReturnRows(sqlmock.NewRows(listingColumns).
+			AddRow("listing-1", "gym-a", "Iron Gym", "tpl-1", 2, "admin-a", "Leg Day", nil, []byte(`{"name":"Leg Day","blocks":[]}`), []byte(`[]`),
+				"approved", nil, "platform-1", now, now, now, 4.5, 2, 7))
+
+	listings, err := repo.FindApproved(dto.ListingFilter{Search: "legs", DifficultyLevel: "advanced", Sort: "rating"})
+	require.NoError(t, err)
+	if assert.Len(t, listings, 1) {
+		assert.Equal(t, "Iron Gym", listings[0].SourceGymName)
+		assert.Equal(t, 4.5, *listings[0].AverageRating)
+		assert.Equal(t, 7, listings[0].ImportCount)
+		assert.Equal(t, "Leg Day", listings[0].Snapshot.Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveSupersedesEarlierListings(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateMarketplaceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE public.template_listing previous SET status = \$1`).
+		WithArgs("superseded", "listing-2", "approved").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE public.template_listing\s+SET status = \$1`).
+		WithArgs("approved", "platform-1", nil, "listing-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Approve("listing-2", "platform-1", nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportCarriesUnsubstitutedExercises(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateMarketplaceRepository(db)
+	audience := "strength"
+	sled := &dto.ListingCustomExercise{SourceID: "sled-push", Name: "Sled Push", Synonyms: "", DifficultyLevel: "advanced",
+		ExerciseType: "functional", Instructions: "Drive", MuscularGroups: []dto.ListingMuscularGroup{{MuscularGroupID: "quads", Role: "primary", ActivationWeight: 1}},
+		PublicEquipmentIDs: []string{"sled"}}
+	listing := &dto.TemplateListingDTO{
+		ID:       "listing-1",
+		Snapshot: templateVersionDTO.TemplateSnapshot{Name: "Leg Day", DifficultyLevel: "advanced", TargetAudience: &audience},
+		Exercises: []dto.ListingExercise{
+			{BlockName: "Main", ExerciseOrder: 1, ExerciseSource: "gym", CustomExercise: sled},
+			{BlockName: "Finisher", ExerciseOrder: 1, ExerciseSource: "gym", CustomExercise: sled},
+		},
+	}
+	snapshot, _ := json.Marshal(templateVersionDTO.TemplateSnapshot{Name: "Our Leg Day", DifficultyLevel: "advanced", TargetAudience: &audience})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_workout_template`)).
+		WithArgs("admin-b", "Our Leg Day", nil, "advanced", nil, &audience, "listing-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("template-1"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_workout_template_version`)).
+		WithArgs("template-1", snapshot, "Imported from the template marketplace", "admin-b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_workout_instance`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("instance-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_exercise`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("exercise-1"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_exercise_muscular_group`)).
+		WithArgs("exercise-1", "quads", "primary", 1.0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_exercise_equipment`)).
+		WithArgs("exercise-1", "sled").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	for _, block := range []string{"Main", "Finisher"} {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "gym-b".custom_workout_exercise`)).
+			WithArgs("admin-b", "instance-1", "gym", nil, "exercise-1", block, 1, nil, nil, nil, nil, nil, nil, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO public.template_listing_import`)).
+		WithArgs("listing-1", "gym-b", "template-1", "admin-b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.Import("gym-b", listing, "Our Leg Day", nil, "admin-b")
+	require.NoError(t, err)
+	assert.Equal(t, "template-1", result.TemplateID)
+	assert.Equal(t, "instance-1", *result.SampleInstanceID)
+	assert.Equal(t, map[string]string{"sled-push": "exercise-1"}, result.CarriedExercises)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTemplateMarketplaceRouter(handler interfaces.TemplateMarketplaceHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/", handler.BrowseListings)                       // GET /template-marketplace?search=&difficulty_level=&sort=rating
+	r.Get("/mine", handler.ListGymListings)                  // GET /template-marketplace/mine
+	r.Get("/review", handler.ListPendingListings)            // GET /template-marketplace/review
+	r.Post("/publish/{templateID}", handler.PublishTemplate) // POST /template-marketplace/publish/{templateID}
+	r.Get("/{listingID}", handler.GetListing)                // GET /template-marketplace/{listingID}
+	r.Post("/{listingID}/approve", handler.ApproveListing)   // POST /template-marketplace/{listingID}/approve
+	r.Post("/{listingID}/reject", handler.RejectListing)     // POST /template-marketplace/{listingID}/reject
+	r.Post("/{listingID}/withdraw", handler.WithdrawListing) // POST /template-marketplace/{listingID}/withdraw
+	r.Post("/{listingID}/import", handler.ImportListing)     // POST /template-marketplace/{listingID}/import
+	r.Put("/{listingID}/rating", handler.RateListing)        // PUT /template-marketplace/{listingID}/rating
+
+	return r
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/enum"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/interfaces"
+	templateVersionEnum "github.com/alejandro-albiol/athenai/internal/template_version/enum"
+	templateVersionIF "github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+var validDifficultyLevels = map[string]bool{"beginner": true, "intermediate": true, "advanced": true}
+
+type TemplateMarketplaceService struct {
+	repo      interfaces.TemplateMarketplaceRepository
+	templates templateVersionIF.TemplateResolver
+}
+
+func NewTemplateMarketplaceService(repo interfaces.TemplateMarketplaceRepository, templates templateVersionIF.TemplateResolver) *TemplateMarketplaceService {
+	return &TemplateMarketplaceService{repo: repo, templates: templates}
+}
+
+// PublishTemplate submits the latest version of a gym template, publishing its first version if it has none
+func (s *TemplateMarketplaceService) PublishTemplate(gymID, templateID string, publish *dto.PublishListingDTO) (*dto.TemplateListingDTO, error) {
+	if gymID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Templates are published by a gym", nil)
+	}
+	pending, err := s.repo.HasPendingListing(gymID, templateID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check marketplace listings", err)
+	}
+	if pending {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "Template already has a listing awaiting review", nil)
+	}
+
+	gymSource := string(templateVersionEnum.Gym)
+	versionNumber, err := s.templates.PinVersion(gymID, gymSource, templateID, nil, publish.PublishedBy)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.templates.GetVersion(gymID, gymSource, templateID, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &dto.TemplateListingDTO{
+		SourceGymID:      gymID,
+		SourceTemplateID: templateID,
+		SourceVersion:    versionNumber,
+		PublishedBy:      publish.PublishedBy,
+		Title:            version.Snapshot.Name,
+		Summary:          publish.Summary,
+		Snapshot:         version.Snapshot,
+		Exercises:        []dto.ListingExercise{},
+		Status:           string(enum.Pending),
+	}
+	if publish.Title != nil {
+		listing.Title = strings.TrimSpace(*publish.Title)
+	}
+	if listing.Title == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Listing title is required", nil)
+	}
+	if publish.SampleInstanceID != nil {
+		listing.Exercises, err = s.repo.FindSampleExercises(gymID, templateID, *publish.SampleInstanceID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apierror.New(errorcode_enum.CodeBadRequest, "Sample workout must be a workout instance of this template", err)
+			}
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read sample workout", err)
+		}
+	}
+
+	id, err := s.repo.Create(listing)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to publish template listing", err)
+	}
+	return s.findListing(id)
+}
+
+// WithdrawListing takes a gym's own pending or approved listing off the marketplace
+func (s *TemplateMarketplaceService) WithdrawListing(gymID, listingID string) error {
+	listing, err := s.findListing(listingID)
+	if err != nil {
+		return err
+	}
+	if listing.SourceGymID != gymID {
+		return apierror.New(errorcode_enum.CodeNotFound, "Template listing not found", nil)
+	}
+	if listing.Status != string(enum.Pending) && listing.Status != string(enum.Approved) {
+		return apierror.New(errorcode_enum.CodeConflict, "Only pending or approved listings can be withdrawn", nil)
+	}
+	return s.setStatus(listingID, enum.Withdrawn, nil, nil)
+}
+
+func (s *TemplateMarketplaceService) ListGymListings(gymID string) ([]*dto.TemplateListingDTO, error) {
+	listings, err := s.repo.FindByGym(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list template listings", err)
+	}
+	return orEmpty(listings), nil
+}
+
+func (s *TemplateMarketplaceService) ListPendingListings() ([]*dto.TemplateListingDTO, error) {
+	listings, err := s.repo.FindByStatus(string(enum.Pending))
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list template listings", err)
+	}
+	return orEmpty(listings), nil
+}
+
+func (s *TemplateMarketplaceService) ApproveListing(listingID string, review *dto.ReviewListingDTO) error {
+	if err := s.requirePending(listingID); err != nil {
+		return err
+	}
+	if err := s.repo.Approve(listingID, review.ReviewedBy, review.Notes); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to approve template listing", err)
+	}
+	return nil
+}
+
+// RejectListing requires notes so the publishing gym knows what to change
+func (s *TemplateMarketplaceService) RejectListing(listingID string, review *dto.ReviewListingDTO) error {
+	if review.Notes == nil || strings.TrimSpace(*review.Notes) == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Rejection notes are required", nil)
+	}
+	if err := s.requirePending(listingID); err != nil {
+		return err
+	}
+	return s.setStatus(listingID, enum.Rejected, &review.ReviewedBy, review.Notes)
+}
+
+func (s *TemplateMarketplaceService) BrowseListings(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error) {
+	if filter.Sort != "" && !enum.ListingSort(filter.Sort).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Sort must be 'newest', 'rating' or 'imports'", nil)
+	}
+	if filter.DifficultyLevel != "" && !validDifficultyLevels[filter.DifficultyLevel] {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Difficulty level must be 'beginner', 'intermediate' or 'advanced'", nil)
+	}
+	listings, err := s.repo.FindApproved(filter)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to browse template listings", err)
+	}
+	return orEmpty(listings), nil
+}
+
+func (s *TemplateMarketplaceService) GetListing(gymID string, platformAdmin bool, listingID string) (*dto.TemplateListingDTO, error) {
+	listing, err := s.findListing(listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != string(enum.Approved) && listing.SourceGymID != gymID && !platformAdmin {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Template listing not found", nil)
+	}
+	return listing, nil
+}
+
+// ImportListing copies an approved listing into the gym as a new template with its first version and sample workout
+func (s *TemplateMarketplaceService) ImportListing(gymID, listingID string, importListing *dto.ImportListingDTO) (*dto.ImportResultDTO, error) {
+	listing, err := s.approvedListing(gymID, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.SourceGymID == gymID {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Gyms cannot import their own listings", nil)
+	}
+
+	name := listing.Snapshot.Name
+	if importListing.Name != nil {
+		name = strings.TrimSpace(*importListing.Name)
+	}
+	if name == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Template name is required", nil)
+	}
+	exists, err := s.repo.NameExists(gymID, name)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check template name", err)
+	}
+	if exists {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "A template named '"+name+"' already exists in this gym", nil)
+	}
+	if err := s.validateSubstitutions(gymID, listing, importListing.Substitutions); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Import(gymID, listing, name, importListing.Substitutions, importListing.ImportedBy)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to import template listing", err)
+	}
+	return result, nil
+}
+
+// RateListing records the gym's rating of a listing it imported, replacing an earlier one
+func (s *TemplateMarketplaceService) RateListing(gymID, listingID string, rating *dto.RateListingDTO) error {
+	if rating.Rating < 1 || rating.Rating > 5 {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Rating must be between 1 and 5", nil)
+	}
+	listing, err := s.approvedListing(gymID, listingID)
+	if err != nil {
+		return err
+	}
+	if listing.SourceGymID == gymID {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Gyms cannot rate their own listings", nil)
+	}
+	imported, err := s.repo.HasImported(listingID, gymID)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to check template imports", err)
+	}
+	if !imported {
+		return apierror.New(errorcode_enum.CodeForbidden, "Only gyms that imported the template can rate it", nil)
+	}
+	if err := s.repo.SaveRating(listingID, gymID, rating); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to rate template listing", err)
+	}
+	return nil
+}
+
+// validateSubstitutions checks every substitution replaces a bundled gym exercise with one the importing gym can use
+func (s *TemplateMarketplaceService) validateSubstitutions(gymID string, listing *dto.TemplateListingDTO, substitutions map[string]dto.ExerciseSubstitution) error {
+	bundled := map[string]bool{}
+	for _, exercise := range listing.Exercises {
+		if exercise.CustomExercise != nil {
+			bundled[exercise.CustomExercise.SourceID] = true
+		}
+	}
+	for sourceID, substitution := range substitutions {
+		if !bundled[sourceID] {
+			return apierror.New(errorcode_enum.CodeBadRequest, "Substitution for '"+sourceID+"' does not match a bundled gym exercise", nil)
+		}
+		var exerciseID *string
+		switch substitution.ExerciseSource {
+		case "public":
+			if substitution.GymExerciseID == nil {
+				exerciseID = substitution.PublicExerciseID
+			}
+		case "gym":
+			if substitution.PublicExerciseID == nil {
+				exerciseID = substitution.GymExerciseID
+			}
+		}
+		if exerciseID == nil {
+			return apierror.New(errorcode_enum.CodeBadRequest, "Substitutions need exercise_source and the matching exercise id", nil)
+		}
+		exists, err := s.repo.ExerciseExists(gymID, substitution.ExerciseSource, *exerciseID)
+		if err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Failed to check substitute exercise", err)
+		}
+		if !exists {
+			return apierror.New(errorcode_enum.CodeBadRequest, "Substitute exercise '"+*exerciseID+"' not found", nil)
+		}
+	}
+	return nil
+}
+
+// approvedListing hides listings that are not on the marketplace from gyms other than the publisher
+func (s *TemplateMarketplaceService) approvedListing(gymID, listingID string) (*dto.TemplateListingDTO, error) {
+	if gymID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Listings are imported and rated by a gym", nil)
+	}
+	listing, err := s.GetListing(gymID, false, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != string(enum.Approved) {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "Template listing is not approved", nil)
+	}
+	return listing, nil
+}
+
+func (s *TemplateMarketplaceService) requirePending(listingID string) error {
+	listing, err := s.findListing(listingID)
+	if err != nil {
+		return err
+	}
+	if listing.Status != string(enum.Pending) {
+		return apierror.New(errorcode_enum.CodeConflict, "Template listing is "+listing.Status+", not pending", nil)
+	}
+	return nil
+}
+
+func (s *TemplateMarketplaceService) setStatus(listingID string, status enum.ListingStatus, reviewedBy, notes *string) error {
+	if err := s.repo.SetStatus(listingID, string(status), reviewedBy, notes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Template listing not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to update template listing", err)
+	}
+	return nil
+}
+
+func (s *TemplateMarketplaceService) findListing(listingID string) (*dto.TemplateListingDTO, error) {
+	listing, err := s.repo.FindByID(listingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Template listing not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template listing", err)
+	}
+	return listing, nil
+}
+
+func orEmpty(listings []*dto.TemplateListingDTO) []*dto.TemplateListingDTO {
+	if listings == nil {
+		return []*dto.TemplateListingDTO{}
+	}
+	return listings
+}
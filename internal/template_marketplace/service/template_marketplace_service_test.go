@@ -0,0 +1,223 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_marketplace/interfaces"
+	templateVersionDTO "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.TemplateMarketplaceRepository
+	listings  map[string]*dto.TemplateListingDTO
+	pending   bool
+	sample    []dto.ListingExercise
+	created   *dto.TemplateListingDTO
+	statuses  map[string]string
+	approved  string
+	exercises map[string]bool
+	imported  map[string]bool
+	importArg map[string]dto.ExerciseSubstitution
+	rated     *dto.RateListingDTO
+}
+
+func (m *mockRepository) FindSampleExercises(gymID, templateID, instanceID string) ([]dto.ListingExercise, error) {
+	if m.sample == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.sample, nil
+}
+func (m *mockRepository) HasPendingListing(gymID, templateID string) (bool, error) {
+	return m.pending, nil
+}
+func (m *mockRepository) Create(listing *dto.TemplateListingDTO) (string, error) {
+	listing.ID = "listing-new"
+	m.created = listing
+	m.listings[listing.ID] = listing
+	return listing.ID, nil
+}
+func (m *mockRepository) FindByID(listingID string) (*dto.TemplateListingDTO, error) {
+	listing, ok := m.listings[listingID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return listing, nil
+}
+func (m *mockRepository) FindApproved(filter dto.ListingFilter) ([]*dto.TemplateListingDTO, error) {
+	return nil, nil
+}
+func (m *mockRepository) Approve(listingID, reviewedBy string, notes *string) error {
+	m.approved = listingID
+	return nil
+}
+func (m *mockRepository) SetStatus(listingID, status string, reviewedBy, notes *string) error {
+	m.statuses[listingID] = status
+	return nil
+}
+func (m *mockRepository) NameExists(gymID, name string) (bool, error) { return name == "Taken", nil }
+func (m *mockRepository) ExerciseExists(gymID, source, exerciseID string) (bool, error) {
+	return m.exercises[source+":"+exerciseID], nil
+}
+func (m *mockRepository) Import(gymID string, listing *dto.TemplateListingDTO, name string, substitutions map[string]dto.ExerciseSubstitution, importedBy string) (*dto.ImportResultDTO, error) {
+	m.importArg = substitutions
+	return &dto.ImportResultDTO{ListingID: listing.ID, TemplateID: "template-new"}, nil
+}
+func (m *mockRepository) HasImported(listingID, gymID string) (bool, error) {
+	return m.imported[listingID+":"+gymID], nil
+}
+func (m *mockRepository) SaveRating(listingID, gymID string, rating *dto.RateListingDTO) error {
+	m.rated = rating
+	return nil
+}
+
+type mockTemplates struct {
+	pinnedBy string
+}
+
+func (m *mockTemplates) PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error) {
+	m.pinnedBy = authorID
+	return 3, nil
+}
+func (m *mockTemplates) GetVersion(gymID, source, templateID string, versionNumber int) (*templateVersionDTO.TemplateVersionDTO, error) {
+	return &templateVersionDTO.TemplateVersionDTO{TemplateID: templateID, VersionNumber: versionNumber,
+		Snapshot: templateVersionDTO.TemplateSnapshot{Name: "Leg Day", DifficultyLevel: "advanced"}}, nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func newFixture() *mockRepository {
+	sled := &dto.ListingCustomExercise{SourceID: "sled-push", Name: "Sled Push"}
+	return &mockRepository{
+		listings: map[string]*dto.TemplateListingDTO{
+			"approved": {ID: "approved", SourceGymID: "gym-a", Status: "approved",
+				Snapshot: templateVersionDTO.TemplateSnapshot{Name: "Leg Day"},
+				Exercises: []dto.ListingExercise{
+					{BlockName: "Main", ExerciseOrder: 1, ExerciseSource: "public", PublicExerciseID: strPtr("squat")},
+					{BlockName: "Main", ExerciseOrder: 2, ExerciseSource: "gym", CustomExercise: sled},
+				}},
+			"pending": {ID: "pending", SourceGymID: "gym-a", Status: "pending"},
+		},
+		statuses:  map[string]string{},
+		exercises: map[string]bool{"public:prowler": true},
+		imported:  map[string]bool{"approved:gym-b": true},
+	}
+}
+
+func TestPublishTemplateSnapshotsLatestVersion(t *testing.T) {
+	repo := newFixture()
+	repo.sample = []dto.ListingExercise{{BlockName: "Main", ExerciseOrder: 1, ExerciseSource: "public", PublicExerciseID: strPtr("squat")}}
+	templates := &mockTemplates{}
+	svc := NewTemplateMarketplaceService(repo, templates)
+
+	listing, err := svc.PublishTemplate("gym-a", "tpl-1", &dto.PublishListingDTO{SampleInstanceID: strPtr("instance-1"), PublishedBy: "admin-a"})
+	require.NoError(t, err)
+	assert.Equal(t, "listing-new", listing.ID)
+	assert.Equal(t, "pending", repo.created.Status)
+	assert.Equal(t, 3, repo.created.SourceVersion)
+	assert.Equal(t, "Leg Day", repo.created.Title)
+	assert.Len(t, repo.created.Exercises, 1)
+	assert.Equal(t, "admin-a", templates.pinnedBy)
+}
+
+func TestPublishTemplateValidation(t *testing.T) {
+	repo := newFixture()
+	_, err := NewTemplateMarketplaceService(repo, &mockTemplates{}).PublishTemplate("gym-a", "tpl-1", &dto.PublishListingDTO{SampleInstanceID: strPtr("other")})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	repo.pending = true
+	_, err = NewTemplateMarketplaceService(repo, &mockTemplates{}).PublishTemplate("gym-a", "tpl-1", &dto.PublishListingDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+}
+
+func TestModeration(t *testing.T) {
+	repo := newFixture()
+	svc := NewTemplateMarketplaceService(repo, &mockTemplates{})
+
+	testutil.AssertCode(t, svc.RejectListing("pending", &dto.ReviewListingDTO{ReviewedBy: "platform-1"}), errorcode_enum.CodeBadRequest)
+	testutil.AssertCode(t, svc.ApproveListing("approved", &dto.ReviewListingDTO{}), errorcode_enum.CodeConflict)
+
+	require.NoError(t, svc.RejectListing("pending", &dto.ReviewListingDTO{Notes: strPtr("Add block instructions"), ReviewedBy: "platform-1"}))
+	assert.Equal(t, "rejected", repo.statuses["pending"])
+	require.NoError(t, svc.ApproveListing("pending", &dto.ReviewListingDTO{ReviewedBy: "platform-1"}))
+	assert.Equal(t, "pending", repo.approved)
+}
+
+func TestOnlyThePublisherSeesUnapprovedListings(t *testing.T) {
+	svc := NewTemplateMarketplaceService(newFixture(), &mockTemplates{})
+
+	_, err := svc.GetListing("gym-b", false, "pending")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	_, err = svc.GetListing("gym-a", false, "pending")
+	assert.NoError(t, err)
+	_, err = svc.GetListing("", true, "pending")
+	assert.NoError(t, err)
+
+	testutil.AssertCode(t, svc.WithdrawListing("gym-b", "approved"), errorcode_enum.CodeNotFound)
+}
+
+func TestImportListingSubstitutions(t *testing.T) {
+	repo := newFixture()
+	svc := NewTemplateMarketplaceService(repo, &mockTemplates{})
+	prowler := map[string]dto.ExerciseSubstitution{"sled-push": {ExerciseSource: "public", PublicExerciseID: strPtr("prowler")}}
+
+	tests := []struct {
+		name    string
+		gymID   string
+		request *dto.ImportListingDTO
+		code    string
+	}{
+		{"own listing", "gym-a", &dto.ImportListingDTO{}, errorcode_enum.CodeBadRequest},
+		{"name taken", "gym-b", &dto.ImportListingDTO{Name: strPtr("Taken")}, errorcode_enum.CodeConflict},
+		{"unknown bundled exercise", "gym-b", &dto.ImportListingDTO{Substitutions: map[string]dto.ExerciseSubstitution{
+			"squat": {ExerciseSource: "public", PublicExerciseID: strPtr("prowler")}}}, errorcode_enum.CodeBadRequest},
+		{"missing substitute", "gym-b", &dto.ImportListingDTO{Substitutions: map[string]dto.ExerciseSubstitution{
+			"sled-push": {ExerciseSource: "gym", GymExerciseID: strPtr("nope")}}}, errorcode_enum.CodeBadRequest},
+		{"mismatched source", "gym-b", &dto.ImportListingDTO{Substitutions: map[string]dto.ExerciseSubstitution{
+			"sled-push": {ExerciseSource: "gym", PublicExerciseID: strPtr("prowler")}}}, errorcode_enum.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ImportListing(tt.gymID, "approved", tt.request)
+			testutil.AssertCode(t, err, tt.code)
+		})
+	}
+
+	result, err := svc.ImportListing("gym-b", "approved", &dto.ImportListingDTO{Substitutions: prowler, ImportedBy: "admin-b"})
+	require.NoError(t, err)
+	assert.Equal(t, "template-new", result.TemplateID)
+	assert.Equal(t, prowler, repo.importArg)
+
+	_, err = svc.ImportListing("gym-b", "pending", &dto.ImportListingDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
+
+func TestRateListingRequiresImport(t *testing.T) {
+	repo := newFixture()
+	svc := NewTemplateMarketplaceService(repo, &mockTemplates{})
+
+	testutil.AssertCode(t, svc.RateListing("gym-b", "approved", &dto.RateListingDTO{Rating: 6}), errorcode_enum.CodeBadRequest)
+	testutil.AssertCode(t, svc.RateListing("gym-c", "approved", &dto.RateListingDTO{Rating: 4}), errorcode_enum.CodeForbidden)
+	testutil.AssertCode(t, svc.RateListing("gym-a", "approved", &dto.RateListingDTO{Rating: 5}), errorcode_enum.CodeBadRequest)
+
+	require.NoError(t, svc.RateListing("gym-b", "approved", &dto.RateListingDTO{Rating: 4, RatedBy: "admin-b"}))
+	assert.Equal(t, 4, repo.rated.Rating)
+}
+
+func TestBrowseListingsValidatesFilter(t *testing.T) {
+	svc := NewTemplateMarketplaceService(newFixture(), &mockTemplates{})
+
+	_, err := svc.BrowseListings(dto.ListingFilter{Sort: "popular"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	_, err = svc.BrowseListings(dto.ListingFilter{DifficultyLevel: "elite"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	listings, err := svc.BrowseListings(dto.ListingFilter{Sort: "rating"})
+	require.NoError(t, err)
+	assert.NotNil(t, listings)
+}
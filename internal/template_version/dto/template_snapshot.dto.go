@@ -0,0 +1,27 @@
+package dto
+
+import templateBlockDTO "github.com/alejandro-albiol/athenai/internal/template_block/dto"
+
+// TemplateSnapshot is the content of a workout template and its blocks frozen by publishing
+type TemplateSnapshot struct {
+	Name                     string          `json:"name"`
+	Description              *string         `json:"description"`
+	DifficultyLevel          string          `json:"difficulty_level"`
+	EstimatedDurationMinutes *int            `json:"estimated_duration_minutes"`
+	TargetAudience           *string         `json:"target_audience"`
+	Blocks                   []BlockSnapshot `json:"blocks"`
+}
+
+// BlockSnapshot is one template block; blocks are identified by name within a template
+type BlockSnapshot struct {
+	BlockName                string  `json:"block_name"`
+	BlockType                string  `json:"block_type"`
+	BlockOrder               int     `json:"block_order"`
+	ExerciseCount            int     `json:"exercise_count"`
+	EstimatedDurationMinutes *int    `json:"estimated_duration_minutes"`
+	Instructions             *string `json:"instructions"`
+	Reps                     *int    `json:"reps"`
+	Series                   *int    `json:"series"`
+	RestTimeSeconds          *int    `json:"rest_time_seconds"`
+	templateBlockDTO.BlockTiming
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type PublishTemplateVersionDTO struct {
+	Notes       *string `json:"notes,omitempty"`
+	PublishedBy string  `json:"-"` // set from the caller's token
+}
+
+type TemplateVersionDTO struct {
+	ID             string           `json:"id"`
+	TemplateSource string           `json:"template_source"`
+	TemplateID     string           `json:"template_id"`
+	VersionNumber  int              `json:"version_number"`
+	Snapshot       TemplateSnapshot `json:"snapshot"`
+	Notes          *string          `json:"notes,omitempty"`
+	PublishedBy    *string          `json:"published_by,omitempty"`
+	PublishedAt    time.Time        `json:"published_at"`
+}
+
+// OutdatedInstanceDTO is a gym workout instance pinned to an older version of its template, or to none
+type OutdatedInstanceDTO struct {
+	InstanceID    string    `json:"instance_id"`
+	Name          string    `json:"name"`
+	CreatedBy     string    `json:"created_by"`
+	PinnedVersion *int      `json:"pinned_version"`
+	LatestVersion int       `json:"latest_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
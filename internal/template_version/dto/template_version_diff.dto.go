@@ -0,0 +1,24 @@
+package dto
+
+// TemplateVersionDiff describes how a template changed between two published versions
+type TemplateVersionDiff struct {
+	TemplateSource string        `json:"template_source"`
+	TemplateID     string        `json:"template_id"`
+	From           int           `json:"from"`
+	To             int           `json:"to"`
+	Changes        []FieldChange `json:"changes"`
+	AddedBlocks    []string      `json:"added_blocks"`
+	RemovedBlocks  []string      `json:"removed_blocks"`
+	ChangedBlocks  []BlockChange `json:"changed_blocks"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type BlockChange struct {
+	BlockName string        `json:"block_name"`
+	Changes   []FieldChange `json:"changes"`
+}
@@ -0,0 +1,17 @@
+package enum
+
+// TemplateSource is where a versioned template lives, matching custom_workout_instance.template_source
+type TemplateSource string
+
+const (
+	Public TemplateSource = "public"
+	Gym    TemplateSource = "gym"
+)
+
+func (s TemplateSource) IsValid() bool {
+	switch s {
+	case Public, Gym:
+		return true
+	}
+	return false
+}
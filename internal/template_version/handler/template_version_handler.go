@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_version/enum"
+	"github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TemplateVersionHandler struct {
+	service interfaces.TemplateVersionService
+}
+
+func NewTemplateVersionHandler(service interfaces.TemplateVersionService) *TemplateVersionHandler {
+	return &TemplateVersionHandler{service: service}
+}
+
+func (h *TemplateVersionHandler) PublishVersion(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	if !requireAccess(w, r, source, true) {
+		return
+	}
+	var publish dto.PublishTemplateVersionDTO
+	if err := json.NewDecoder(r.Body).Decode(&publish); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	publish.PublishedBy = middleware.GetUserID(r)
+
+	version, err := h.service.PublishVersion(middleware.GetGymID(r), source, chi.URLParam(r, "templateID"), &publish)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to publish template version")
+		return
+	}
+	response.WriteAPICreated(w, "Template version published successfully", version)
+}
+
+func (h *TemplateVersionHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	if !requireAccess(w, r, source, false) {
+		return
+	}
+	versions, err := h.service.ListVersions(middleware.GetGymID(r), source, chi.URLParam(r, "templateID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get template versions")
+		return
+	}
+	response.WriteAPISuccess(w, "Template versions retrieved successfully", versions)
+}
+
+func (h *TemplateVersionHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	if !requireAccess(w, r, source, false) {
+		return
+	}
+	number, ok := versionNumber(w, chi.URLParam(r, "version"))
+	if !ok {
+		return
+	}
+	version, err := h.service.GetVersion(middleware.GetGymID(r), source, chi.URLParam(r, "templateID"), number)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get template version")
+		return
+	}
+	response.WriteAPISuccess(w, "Template version retrieved successfully", version)
+}
+
+func (h *TemplateVersionHandler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	if !requireAccess(w, r, source, false) {
+		return
+	}
+	query := r.URL.Query()
+	from, ok := versionNumber(w, query.Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := versionNumber(w, query.Get("to"))
+	if !ok {
+		return
+	}
+	diff, err := h.service.DiffVersions(middleware.GetGymID(r), source, chi.URLParam(r, "templateID"), from, to)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to compare template versions")
+		return
+	}
+	response.WriteAPISuccess(w, "Template versions compared successfully", diff)
+}
+
+func (h *TemplateVersionHandler) OutdatedInstances(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	instances, err := h.service.OutdatedInstances(middleware.GetGymID(r), chi.URLParam(r, "source"), chi.URLParam(r, "templateID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to find outdated instances")
+		return
+	}
+	response.WriteAPISuccess(w, "Outdated instances retrieved successfully", instances)
+}
+
+// requireAccess writes a 403 unless the caller may read, or publish, versions of templates from source.
+// Public templates are published by platform administrators only; gym templates by the gym's administrators.
+func requireAccess(w http.ResponseWriter, r *http.Request, source string, publish bool) bool {
+	if enum.TemplateSource(source) == enum.Public {
+		if !publish && middleware.IsGymAdmin(r) || middleware.IsPlatformAdmin(r) {
+			return true
+		}
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only platform administrators can publish public template versions", nil))
+		return false
+	}
+	return requireGymAdmin(w, r)
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym the templates and instances belong to
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage gym template versions", nil))
+	return false
+}
+
+// versionNumber writes a 400 unless value is a positive version number
+func versionNumber(w http.ResponseWriter, value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Version must be a positive number", err))
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/template_version/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.TemplateVersionService
+	published *dto.PublishTemplateVersionDTO
+	gymID     string
+	diffed    []int
+}
+
+func (m *mockService) PublishVersion(gymID, source, templateID string, publish *dto.PublishTemplateVersionDTO) (*dto.TemplateVersionDTO, error) {
+	m.published, m.gymID = publish, gymID
+	return &dto.TemplateVersionDTO{TemplateSource: source, TemplateID: templateID, VersionNumber: 1}, nil
+}
+func (m *mockService) ListVersions(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error) {
+	m.gymID = gymID
+	return []*dto.TemplateVersionDTO{}, nil
+}
+func (m *mockService) DiffVersions(gymID, source, templateID string, from, to int) (*dto.TemplateVersionDiff, error) {
+	m.diffed = []int{from, to}
+	return &dto.TemplateVersionDiff{TemplateSource: source, TemplateID: templateID, From: from, To: to}, nil
+}
+
+func serve(svc *mockService, method, target, body, userType, role, gymID string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewTemplateVersionRouter(NewTemplateVersionHandler(svc)),
+		testutil.Caller{UserType: userType, Role: role, UserID: "user-1", GymID: gymID}, method, target, body)
+}
+
+func TestPublishPublicVersionRequiresPlatformAdmin(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/public/t1/publish", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.published)
+
+	w = serve(svc, http.MethodPost, "/public/t1/publish", `{"notes":"First cut"}`, "platform_admin", "", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "First cut", *svc.published.Notes)
+	assert.Equal(t, "user-1", svc.published.PublishedBy)
+}
+
+func TestPublishGymVersionWithoutBody(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/gym/t1/publish", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "gym-1", svc.gymID)
+
+	w = serve(svc, http.MethodPost, "/gym/t1/publish", "", "tenant_user", "member", "gym-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGymAdminsReadPublicVersions(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/public/t1", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(svc, http.MethodGet, "/public/t1/diff?from=1&to=2", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int{1, 2}, svc.diffed)
+
+	w = serve(svc, http.MethodGet, "/public/t1/diff?from=0&to=2", "", "tenant_user", "admin", "gym-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "net/http"
+
+type TemplateVersionHandler interface {
+	PublishVersion(w http.ResponseWriter, r *http.Request)
+	ListVersions(w http.ResponseWriter, r *http.Request)
+	GetVersion(w http.ResponseWriter, r *http.Request)
+	DiffVersions(w http.ResponseWriter, r *http.Request)
+	OutdatedInstances(w http.ResponseWriter, r *http.Request)
+}
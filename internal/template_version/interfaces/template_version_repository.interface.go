@@ -0,0 +1,14 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+
+// TemplateVersionRepository reads public templates from the public schema and gym templates from the gym's schema.
+// Versions are only ever appended.
+type TemplateVersionRepository interface {
+	LoadTemplate(gymID, source, templateID string) (*dto.TemplateSnapshot, error)
+	Create(gymID, source, templateID string, snapshot *dto.TemplateSnapshot, notes, publishedBy *string) (*dto.TemplateVersionDTO, error)
+	FindByTemplate(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error)
+	FindByNumber(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error)
+	FindLatest(gymID, source, templateID string) (*dto.TemplateVersionDTO, error)
+	FindOutdatedInstances(gymID, source, templateID string, latestVersion int) ([]*dto.OutdatedInstanceDTO, error)
+}
@@ -0,0 +1,26 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/template_version/dto"
+
+type TemplateVersionService interface {
+	VersionPinner
+
+	PublishVersion(gymID, source, templateID string, publish *dto.PublishTemplateVersionDTO) (*dto.TemplateVersionDTO, error)
+	ListVersions(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error)
+	GetVersion(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error)
+	DiffVersions(gymID, source, templateID string, from, to int) (*dto.TemplateVersionDiff, error)
+	OutdatedInstances(gymID, source, templateID string) ([]*dto.OutdatedInstanceDTO, error)
+}
+
+// VersionPinner resolves the template version a new workout instance is pinned to
+type VersionPinner interface {
+	// PinVersion checks the requested version exists, or picks the latest one when none is requested.
+	// A template that was never published gets its first version so the instance can pin to it.
+	PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error)
+}
+
+// TemplateResolver gives workout building the pinned version of a template together with its content
+type TemplateResolver interface {
+	VersionPinner
+	GetVersion(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error)
+}
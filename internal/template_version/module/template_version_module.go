@@ -0,0 +1,23 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/handler"
+	"github.com/alejandro-albiol/athenai/internal/template_version/repository"
+	"github.com/alejandro-albiol/athenai/internal/template_version/router"
+	"github.com/alejandro-albiol/athenai/internal/template_version/service"
+)
+
+func NewTemplateVersionModule(db *sql.DB) http.Handler {
+	repo := repository.NewTemplateVersionRepository(db)
+	service := service.NewTemplateVersionService(repo)
+	handler := handler.NewTemplateVersionHandler(service)
+	return router.NewTemplateVersionRouter(handler)
+}
+
+// NewTemplateVersionService builds the version service other modules use to pin workout instances
+func NewTemplateVersionService(db *sql.DB) *service.TemplateVersionService {
+	return service.NewTemplateVersionService(repository.NewTemplateVersionRepository(db))
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_version/enum"
+	"github.com/lib/pq"
+)
+
+type TemplateVersionRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateVersionRepository(db *sql.DB) *TemplateVersionRepository {
+	return &TemplateVersionRepository{db: db}
+}
+
+// templateTables names the template, block and version tables of a source; gym tables live in the gym's schema
+type templateTables struct {
+	template, block, version string
+	activeBlocks             string
+}
+
+func tablesFor(gymID, source string) templateTables {
+	if enum.TemplateSource(source) == enum.Gym {
+		schema := pq.QuoteIdentifier(gymID)
+		return templateTables{
+			template:     schema + ".custom_workout_template",
+			block:        schema + ".custom_template_block",
+			version:      schema + ".custom_workout_template_version",
+			activeBlocks: " AND deleted_at IS NULL",
+		}
+	}
+	return templateTables{
+		template: "public.workout_template",
+		block:    "public.template_block",
+		version:  "public.workout_template_version",
+	}
+}
+
+// LoadTemplate reads the current content of an active template; sql.ErrNoRows when there is none
+func (r *TemplateVersionRepository) LoadTemplate(gymID, source, templateID string) (*dto.TemplateSnapshot, error) {
+	tables := tablesFor(gymID, source)
+	var snapshot dto.TemplateSnapshot
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT name, description, difficulty_level, estimated_duration_minutes, target_audience
+		FROM %s WHERE id = $1 AND is_active = TRUE`, tables.template), templateID).Scan(
+		&snapshot.Name, &snapshot.Description, &snapshot.DifficultyLevel, &snapshot.EstimatedDurationMinutes, &snapshot.TargetAudience,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT block_name, block_type, block_order, exercise_count, estimated_duration_minutes,
+		instructions, reps, series, rest_time_seconds, block_mode, work_seconds, interval_rest_seconds, rounds, time_cap_seconds
+		FROM %s WHERE template_id = $1%s ORDER BY block_order`, tables.block, tables.activeBlocks), templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshot.Blocks = []dto.BlockSnapshot{}
+	for rows.Next() {
+		var block dto.BlockSnapshot
+		if err := rows.Scan(&block.BlockName, &block.BlockType, &block.BlockOrder, &block.ExerciseCount, &block.EstimatedDurationMinutes,
+			&block.Instructions, &block.Reps, &block.Series, &block.RestTimeSeconds,
+			&block.BlockMode, &block.WorkSeconds, &block.IntervalRestSeconds, &block.Rounds, &block.TimeCapSeconds); err != nil {
+			return nil, err
+		}
+		snapshot.Blocks = append(snapshot.Blocks, block)
+	}
+	return &snapshot, rows.Err()
+}
+
+// Create numbers the version after the template's latest one; the unique constraint rejects a concurrent publish
+func (r *TemplateVersionRepository) Create(gymID, source, templateID string, snapshot *dto.TemplateSnapshot, notes, publishedBy *string) (*dto.TemplateVersionDTO, error) {
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`INSERT INTO %[1]s (template_id, version_number, snapshot, notes, published_by)
+		SELECT $1, COALESCE(MAX(version_number), 0) + 1, $2, $3, $4
+		FROM %[1]s WHERE template_id = $1
+		RETURNING %[2]s`, tablesFor(gymID, source).version, versionColumns)
+	return scanVersion(r.db.QueryRow(query, templateID, content, notes, publishedBy), source)
+}
+
+const versionColumns = `id, template_id, version_number, snapshot, notes, published_by, published_at`
+
+func (r *TemplateVersionRepository) FindByTemplate(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT %s FROM %s WHERE template_id = $1 ORDER BY version_number DESC`,
+		versionColumns, tablesFor(gymID, source).version), templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*dto.TemplateVersionDTO
+	for rows.Next() {
+		version, err := scanVersion(rows, source)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (r *TemplateVersionRepository) FindByNumber(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error) {
+	return scanVersion(r.db.QueryRow(fmt.Sprintf(`SELECT %s FROM %s WHERE template_id = $1 AND version_number = $2`,
+		versionColumns, tablesFor(gymID, source).version), templateID, versionNumber), source)
+}
+
+// FindLatest returns nil without error when the template was never published
+func (r *TemplateVersionRepository) FindLatest(gymID, source, templateID string) (*dto.TemplateVersionDTO, error) {
+	version, err := scanVersion(r.db.QueryRow(fmt.Sprintf(`SELECT %s FROM %s WHERE template_id = $1 ORDER BY version_number DESC LIMIT 1`,
+		versionColumns, tablesFor(gymID, source).version), templateID), source)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return version, err
+}
+
+// FindOutdatedInstances lists the gym's instances of the template pinned below latestVersion, including unpinned ones
+func (r *TemplateVersionRepository) FindOutdatedInstances(gymID, source, templateID string, latestVersion int) ([]*dto.OutdatedInstanceDTO, error) {
+	templateColumn := "public_template_id"
+	if enum.TemplateSource(source) == enum.Gym {
+		templateColumn = "gym_template_id"
+	}
+	query := fmt.Sprintf(`SELECT id, name, created_by, template_version, created_at
+		FROM %s.custom_workout_instance
+		WHERE template_source = $1 AND %s = $2 AND (template_version IS NULL OR template_version < $3)
+		ORDER BY created_at`, pq.QuoteIdentifier(gymID), templateColumn)
+	rows, err := r.db.Query(query, source, templateID, latestVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*dto.OutdatedInstanceDTO
+	for rows.Next() {
+		instance := &dto.OutdatedInstanceDTO{LatestVersion: latestVersion}
+		if err := rows.Scan(&instance.InstanceID, &instance.Name, &instance.CreatedBy, &instance.PinnedVersion, &instance.CreatedAt); err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	return instances, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVersion(row rowScanner, source string) (*dto.TemplateVersionDTO, error) {
+	version := &dto.TemplateVersionDTO{TemplateSource: source}
+	var snapshot []byte
+	if err := row.Scan(&version.ID, &version.TemplateID, &version.VersionNumber, &snapshot, &version.Notes,
+		&version.PublishedBy, &version.PublishedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &version.Snapshot); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var versionRowColumns = []string{"id", "template_id", "version_number", "snapshot", "notes", "published_by", "published_at"}
+
+func TestLoadTemplateReadsGymSchema(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateVersionRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "gym-1".custom_workout_template WHERE id = $1 AND is_active = TRUE`)).
+		WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "difficulty_level", "estimated_duration_minutes", "target_audience"}).
+			AddRow("Full Body", nil, "beginner", 45, "strength"))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "gym-1".custom_template_block WHERE template_id = $1 AND deleted_at IS NULL ORDER BY block_order`)).
+		WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"block_name", "block_type", "block_order", "exercise_count", "estimated_duration_minutes", "instructions", "reps", "series", "rest_time_seconds",
+			"block_mode", "work_seconds", "interval_rest_seconds", "rounds", "time_cap_seconds"}).
+			AddRow("Warmup", "warmup", 1, 3, nil, nil, nil, nil, nil, "standard", nil, nil, nil, nil).
+			AddRow("Main", "main", 2, 5, 30, "Go heavy", 8, 4, 90, "standard", nil, nil, nil, nil).
+			AddRow("Finisher", "cardio", 3, 1, 4, nil, nil, nil, nil, "tabata", 20, 10, 8, nil))
+
+	snapshot, err := repo.LoadTemplate("gym-1", "gym", "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "Full Body", snapshot.Name)
+	assert.Equal(t, 45, *snapshot.EstimatedDurationMinutes)
+	require.Len(t, snapshot.Blocks, 3)
+	assert.Equal(t, 90, *snapshot.Blocks[1].RestTimeSeconds)
+	assert.Equal(t, 240, snapshot.Blocks[2].DurationSeconds(), "timed blocks are frozen with their timing")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateNumbersAfterLatest(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateVersionRepository(db)
+	notes := "Longer main block"
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO public.workout_template_version (template_id, version_number, snapshot, notes, published_by)
+		SELECT $1, COALESCE(MAX(version_number), 0) + 1, $2, $3, $4
+		FROM public.workout_template_version WHERE template_id = $1`)).
+		WithArgs("t1", []byte(`{"name":"Full Body","description":null,"difficulty_level":"beginner","estimated_duration_minutes":null,"target_audience":null,"blocks":[]}`), &notes, nil).
+		WillReturnRows(sqlmock.NewRows(versionRowColumns).
+			AddRow("version-3", "t1", 3, []byte(`{"name":"Full Body","difficulty_level":"beginner","blocks":[]}`), notes, nil, time.Now()))
+
+	version, err := repo.Create("", "public", "t1", &dto.TemplateSnapshot{Name: "Full Body", DifficultyLevel: "beginner", Blocks: []dto.BlockSnapshot{}}, &notes, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version.VersionNumber)
+	assert.Equal(t, "public", version.TemplateSource)
+	assert.Equal(t, "Full Body", version.Snapshot.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindLatestWithoutVersions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateVersionRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "gym-1".custom_workout_template_version WHERE template_id = $1 ORDER BY version_number DESC LIMIT 1`)).
+		WithArgs("t1").
+		WillReturnError(sql.ErrNoRows)
+
+	version, err := repo.FindLatest("gym-1", "gym", "t1")
+	require.NoError(t, err)
+	assert.Nil(t, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindOutdatedInstancesIncludesUnpinned(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTemplateVersionRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "gym-1".custom_workout_instance
+		WHERE template_source = $1 AND public_template_id = $2 AND (template_version IS NULL OR template_version < $3)`)).
+		WithArgs("public", "t1", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_by", "template_version", "created_at"}).
+			AddRow("i1", "Monday", "trainer-1", nil, time.Now()).
+			AddRow("i2", "Tuesday", "trainer-1", 2, time.Now()))
+
+	instances, err := repo.FindOutdatedInstances("gym-1", "public", "t1", 3)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Nil(t, instances[0].PinnedVersion)
+	assert.Equal(t, 2, *instances[1].PinnedVersion)
+	assert.Equal(t, 3, instances[1].LatestVersion)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
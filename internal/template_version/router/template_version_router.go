@@ -0,0 +1,20 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTemplateVersionRouter(handler interfaces.TemplateVersionHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/{source}/{templateID}/publish", handler.PublishVersion)              // POST /template-version/{source}/{templateID}/publish
+	r.Get("/{source}/{templateID}", handler.ListVersions)                         // GET /template-version/{source}/{templateID}
+	r.Get("/{source}/{templateID}/diff", handler.DiffVersions)                    // GET /template-version/{source}/{templateID}/diff?from=1&to=2
+	r.Get("/{source}/{templateID}/outdated-instances", handler.OutdatedInstances) // GET /template-version/{source}/{templateID}/outdated-instances
+	r.Get("/{source}/{templateID}/{version}", handler.GetVersion)                 // GET /template-version/{source}/{templateID}/{version}
+
+	return r
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	"github.com/alejandro-albiol/athenai/internal/template_version/enum"
+	"github.com/alejandro-albiol/athenai/internal/template_version/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type TemplateVersionService struct {
+	repo interfaces.TemplateVersionRepository
+}
+
+func NewTemplateVersionService(repo interfaces.TemplateVersionRepository) *TemplateVersionService {
+	return &TemplateVersionService{repo: repo}
+}
+
+// PublishVersion freezes the template and its blocks as the next version
+func (s *TemplateVersionService) PublishVersion(gymID, source, templateID string, publish *dto.PublishTemplateVersionDTO) (*dto.TemplateVersionDTO, error) {
+	if err := validateSource(gymID, source); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.loadTemplate(gymID, source, templateID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.FindLatest(gymID, source, templateID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template versions", err)
+	}
+	if latest != nil && reflect.DeepEqual(latest.Snapshot, *snapshot) {
+		return nil, apierror.New(errorcode_enum.CodeConflict,
+			fmt.Sprintf("Template has not changed since version %d", latest.VersionNumber), nil)
+	}
+
+	var publishedBy *string
+	if publish.PublishedBy != "" {
+		publishedBy = &publish.PublishedBy
+	}
+	version, err := s.repo.Create(gymID, source, templateID, snapshot, publish.Notes, publishedBy)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to publish template version", err)
+	}
+	return version, nil
+}
+
+func (s *TemplateVersionService) ListVersions(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error) {
+	if err := validateSource(gymID, source); err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.FindByTemplate(gymID, source, templateID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template versions", err)
+	}
+	if versions == nil {
+		versions = []*dto.TemplateVersionDTO{}
+	}
+	return versions, nil
+}
+
+func (s *TemplateVersionService) GetVersion(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error) {
+	if err := validateSource(gymID, source); err != nil {
+		return nil, err
+	}
+	version, err := s.repo.FindByNumber(gymID, source, templateID, versionNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("Template version %d not found", versionNumber), err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template version", err)
+	}
+	return version, nil
+}
+
+func (s *TemplateVersionService) DiffVersions(gymID, source, templateID string, from, to int) (*dto.TemplateVersionDiff, error) {
+	fromVersion, err := s.GetVersion(gymID, source, templateID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.GetVersion(gymID, source, templateID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &dto.TemplateVersionDiff{
+		TemplateSource: source,
+		TemplateID:     templateID,
+		From:           from,
+		To:             to,
+		AddedBlocks:    []string{},
+		RemovedBlocks:  []string{},
+		ChangedBlocks:  []dto.BlockChange{},
+	}
+	fromTemplate, toTemplate := fromVersion.Snapshot, toVersion.Snapshot
+	fromTemplate.Blocks, toTemplate.Blocks = nil, nil
+	if diff.Changes, err = diffFields(fromTemplate, toTemplate); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to compare template versions", err)
+	}
+
+	fromBlocks := make(map[string]dto.BlockSnapshot, len(fromVersion.Snapshot.Blocks))
+	for _, block := range fromVersion.Snapshot.Blocks {
+		fromBlocks[block.BlockName] = block
+	}
+	for _, block := range toVersion.Snapshot.Blocks {
+		previous, existed := fromBlocks[block.BlockName]
+		if !existed {
+			diff.AddedBlocks = append(diff.AddedBlocks, block.BlockName)
+			continue
+		}
+		delete(fromBlocks, block.BlockName)
+		changes, err := diffFields(previous, block)
+		if err != nil {
+			return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to compare template versions", err)
+		}
+		if len(changes) > 0 {
+			diff.ChangedBlocks = append(diff.ChangedBlocks, dto.BlockChange{BlockName: block.BlockName, Changes: changes})
+		}
+	}
+	for name := range fromBlocks {
+		diff.RemovedBlocks = append(diff.RemovedBlocks, name)
+	}
+	sort.Strings(diff.RemovedBlocks)
+	return diff, nil
+}
+
+// OutdatedInstances lists the gym's workout instances that are not on the template's latest version
+func (s *TemplateVersionService) OutdatedInstances(gymID, source, templateID string) ([]*dto.OutdatedInstanceDTO, error) {
+	if gymID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Outdated instances are listed per gym", nil)
+	}
+	if err := validateSource(gymID, source); err != nil {
+		return nil, err
+	}
+	latest, err := s.repo.FindLatest(gymID, source, templateID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template versions", err)
+	}
+	if latest == nil {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Template has no published versions", nil)
+	}
+	instances, err := s.repo.FindOutdatedInstances(gymID, source, templateID, latest.VersionNumber)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to find outdated instances", err)
+	}
+	if instances == nil {
+		instances = []*dto.OutdatedInstanceDTO{}
+	}
+	return instances, nil
+}
+
+func (s *TemplateVersionService) PinVersion(gymID, source, templateID string, requested *int, authorID string) (int, error) {
+	if requested != nil {
+		version, err := s.GetVersion(gymID, source, templateID, *requested)
+		if err != nil {
+			var apiErr *apierror.APIError
+			if errors.As(err, &apiErr) && apiErr.Code == errorcode_enum.CodeNotFound {
+				return 0, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Template version %d does not exist", *requested), err)
+			}
+			return 0, err
+		}
+		return version.VersionNumber, nil
+	}
+
+	if err := validateSource(gymID, source); err != nil {
+		return 0, err
+	}
+	latest, err := s.repo.FindLatest(gymID, source, templateID)
+	if err != nil {
+		return 0, apierror.New(errorcode_enum.CodeInternal, "Failed to get template versions", err)
+	}
+	if latest != nil {
+		return latest.VersionNumber, nil
+	}
+	first, err := s.PublishVersion(gymID, source, templateID, &dto.PublishTemplateVersionDTO{PublishedBy: authorID})
+	if err != nil {
+		return 0, err
+	}
+	return first.VersionNumber, nil
+}
+
+func (s *TemplateVersionService) loadTemplate(gymID, source, templateID string) (*dto.TemplateSnapshot, error) {
+	snapshot, err := s.repo.LoadTemplate(gymID, source, templateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Workout template not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read workout template", err)
+	}
+	return snapshot, nil
+}
+
+func validateSource(gymID, source string) error {
+	if !enum.TemplateSource(source).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Template source must be 'public' or 'gym'", nil)
+	}
+	if enum.TemplateSource(source) == enum.Gym && gymID == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Gym templates require a gym", nil)
+	}
+	return nil
+}
+
+// diffFields compares two values field by field through their JSON form, sorted by field name
+func diffFields(from, to any) ([]dto.FieldChange, error) {
+	fromFields, err := toFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFieldsMap, err := toFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []dto.FieldChange{}
+	for field, value := range toFieldsMap {
+		if !reflect.DeepEqual(fromFields[field], value) {
+			changes = append(changes, dto.FieldChange{Field: field, From: fromFields[field], To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func toFields(value any) (map[string]any, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	err = json.Unmarshal(content, &fields)
+	delete(fields, "blocks")
+	return fields, err
+}
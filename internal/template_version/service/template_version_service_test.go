@@ -0,0 +1,193 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/template_version/dto"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository serves one template per source and numbers versions like the unique constraint does
+type memoryRepository struct {
+	templates map[string]*dto.TemplateSnapshot
+	versions  []*dto.TemplateVersionDTO
+	outdated  []*dto.OutdatedInstanceDTO
+	latestAsk int
+}
+
+func (m *memoryRepository) LoadTemplate(gymID, source, templateID string) (*dto.TemplateSnapshot, error) {
+	template, ok := m.templates[templateID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *template
+	copied.Blocks = append([]dto.BlockSnapshot{}, template.Blocks...)
+	return &copied, nil
+}
+func (m *memoryRepository) Create(gymID, source, templateID string, snapshot *dto.TemplateSnapshot, notes, publishedBy *string) (*dto.TemplateVersionDTO, error) {
+	number := 1
+	if latest, _ := m.FindLatest(gymID, source, templateID); latest != nil {
+		number = latest.VersionNumber + 1
+	}
+	version := &dto.TemplateVersionDTO{
+		ID:             fmt.Sprintf("version-%d", len(m.versions)+1),
+		TemplateSource: source,
+		TemplateID:     templateID,
+		VersionNumber:  number,
+		Snapshot:       *snapshot,
+		Notes:          notes,
+		PublishedBy:    publishedBy,
+		PublishedAt:    time.Now(),
+	}
+	m.versions = append(m.versions, version)
+	return version, nil
+}
+func (m *memoryRepository) FindByTemplate(gymID, source, templateID string) ([]*dto.TemplateVersionDTO, error) {
+	var found []*dto.TemplateVersionDTO
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if m.versions[i].TemplateID == templateID {
+			found = append(found, m.versions[i])
+		}
+	}
+	return found, nil
+}
+func (m *memoryRepository) FindByNumber(gymID, source, templateID string, versionNumber int) (*dto.TemplateVersionDTO, error) {
+	for _, version := range m.versions {
+		if version.TemplateID == templateID && version.VersionNumber == versionNumber {
+			return version, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *memoryRepository) FindLatest(gymID, source, templateID string) (*dto.TemplateVersionDTO, error) {
+	found, _ := m.FindByTemplate(gymID, source, templateID)
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+func (m *memoryRepository) FindOutdatedInstances(gymID, source, templateID string, latestVersion int) ([]*dto.OutdatedInstanceDTO, error) {
+	m.latestAsk = latestVersion
+	return m.outdated, nil
+}
+
+func intPtr(value int) *int { return &value }
+
+func newTemplate() *dto.TemplateSnapshot {
+	return &dto.TemplateSnapshot{
+		Name:            "Full Body",
+		DifficultyLevel: "beginner",
+		Blocks: []dto.BlockSnapshot{
+			{BlockName: "Warmup", BlockType: "warmup", BlockOrder: 1, ExerciseCount: 3},
+			{BlockName: "Main", BlockType: "main", BlockOrder: 2, ExerciseCount: 5, Reps: intPtr(10)},
+		},
+	}
+}
+
+func TestPublishVersionNumbersSnapshots(t *testing.T) {
+	repo := &memoryRepository{templates: map[string]*dto.TemplateSnapshot{"t1": newTemplate()}}
+	svc := NewTemplateVersionService(repo)
+
+	first, err := svc.PublishVersion("", "public", "t1", &dto.PublishTemplateVersionDTO{PublishedBy: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.VersionNumber)
+	assert.Equal(t, "admin-1", *first.PublishedBy)
+
+	_, err = svc.PublishVersion("", "public", "t1", &dto.PublishTemplateVersionDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+
+	repo.templates["t1"].Blocks[1].ExerciseCount = 6
+	second, err := svc.PublishVersion("", "public", "t1", &dto.PublishTemplateVersionDTO{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.VersionNumber)
+	assert.Nil(t, second.PublishedBy)
+	assert.Equal(t, 5, first.Snapshot.Blocks[1].ExerciseCount, "published versions must not change")
+}
+
+func TestPublishVersionValidatesSource(t *testing.T) {
+	svc := NewTemplateVersionService(&memoryRepository{templates: map[string]*dto.TemplateSnapshot{}})
+
+	_, err := svc.PublishVersion("gym-1", "shared", "t1", &dto.PublishTemplateVersionDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	_, err = svc.PublishVersion("", "gym", "t1", &dto.PublishTemplateVersionDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	_, err = svc.PublishVersion("gym-1", "gym", "missing", &dto.PublishTemplateVersionDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
+
+func TestDiffVersionsComparesTemplateAndBlocks(t *testing.T) {
+	repo := &memoryRepository{templates: map[string]*dto.TemplateSnapshot{"t1": newTemplate()}}
+	svc := NewTemplateVersionService(repo)
+	_, err := svc.PublishVersion("gym-1", "gym", "t1", &dto.PublishTemplateVersionDTO{})
+	require.NoError(t, err)
+
+	template := repo.templates["t1"]
+	template.DifficultyLevel = "intermediate"
+	template.Blocks = []dto.BlockSnapshot{
+		{BlockName: "Main", BlockType: "main", BlockOrder: 1, ExerciseCount: 5, Reps: intPtr(12)},
+		{BlockName: "Cool Down", BlockType: "cooldown", BlockOrder: 2, ExerciseCount: 2},
+	}
+	_, err = svc.PublishVersion("gym-1", "gym", "t1", &dto.PublishTemplateVersionDTO{})
+	require.NoError(t, err)
+
+	diff, err := svc.DiffVersions("gym-1", "gym", "t1", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []dto.FieldChange{{Field: "difficulty_level", From: "beginner", To: "intermediate"}}, diff.Changes)
+	assert.Equal(t, []string{"Cool Down"}, diff.AddedBlocks)
+	assert.Equal(t, []string{"Warmup"}, diff.RemovedBlocks)
+	require.Len(t, diff.ChangedBlocks, 1)
+	assert.Equal(t, "Main", diff.ChangedBlocks[0].BlockName)
+	assert.Equal(t, []dto.FieldChange{
+		{Field: "block_order", From: float64(2), To: float64(1)},
+		{Field: "reps", From: float64(10), To: float64(12)},
+	}, diff.ChangedBlocks[0].Changes)
+
+	_, err = svc.DiffVersions("gym-1", "gym", "t1", 1, 5)
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
+
+func TestPinVersion(t *testing.T) {
+	repo := &memoryRepository{templates: map[string]*dto.TemplateSnapshot{"t1": newTemplate()}}
+	svc := NewTemplateVersionService(repo)
+
+	version, err := svc.PinVersion("gym-1", "public", "t1", nil, "trainer-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, version, "a never published template gets its first version")
+	require.Len(t, repo.versions, 1)
+	assert.Equal(t, "trainer-1", *repo.versions[0].PublishedBy)
+
+	version, err = svc.PinVersion("gym-1", "public", "t1", nil, "trainer-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Len(t, repo.versions, 1, "pinning to the latest version publishes nothing")
+
+	_, err = svc.PinVersion("gym-1", "public", "t1", intPtr(4), "trainer-1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
+
+func TestOutdatedInstances(t *testing.T) {
+	repo := &memoryRepository{templates: map[string]*dto.TemplateSnapshot{"t1": newTemplate()}}
+	svc := NewTemplateVersionService(repo)
+
+	_, err := svc.OutdatedInstances("gym-1", "public", "t1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+
+	_, err = svc.PublishVersion("", "public", "t1", &dto.PublishTemplateVersionDTO{})
+	require.NoError(t, err)
+	instances, err := svc.OutdatedInstances("gym-1", "public", "t1")
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+	assert.NotNil(t, instances)
+	assert.Equal(t, 1, repo.latestAsk)
+
+	_, err = svc.OutdatedInstances("", "public", "t1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
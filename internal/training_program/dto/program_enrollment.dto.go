@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+)
+
+type EnrollMemberDTO struct {
+	MemberID  string `json:"member_id"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD, day 1 of week 1
+	CreatedBy string `json:"-"`
+}
+
+type ProgramEnrollmentDTO struct {
+	ID          string    `json:"id"`
+	ProgramID   string    `json:"program_id"`
+	ProgramName string    `json:"program_name"`
+	MemberID    string    `json:"member_id"`
+	StartDate   string    `json:"start_date"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// EnrollmentResultDTO is a new enrollment with the member workouts generated for it
+type EnrollmentResultDTO struct {
+	EnrollmentID      string                                          `json:"enrollment_id"`
+	ScheduledWorkouts int                                             `json:"scheduled_workouts"`
+	Warnings          []*contraindication_dto.ContraindicationWarning `json:"warnings,omitempty"`
+}
+
+// ScheduledSession is one member workout an enrollment generates
+type ScheduledSession struct {
+	WorkoutInstanceID string
+	ScheduledDate     string
+	WeekNumber        int
+	DayNumber         int
+}
+
+// EnrollmentProgressDTO counts the member workouts of an enrollment by status, overall and per week
+type EnrollmentProgressDTO struct {
+	ProgramEnrollmentDTO
+	CurrentWeek     *int              `json:"current_week"` // empty before the start date
+	TotalWorkouts   int               `json:"total_workouts"`
+	Completed       int               `json:"completed"`
+	Skipped         int               `json:"skipped"`
+	Remaining       int               `json:"remaining"` // scheduled or in progress
+	PercentComplete float64           `json:"percent_complete"`
+	Weeks           []WeekProgressDTO `json:"weeks"`
+}
+
+type WeekProgressDTO struct {
+	WeekNumber int  `json:"week_number"`
+	IsDeload   bool `json:"is_deload"`
+	Total      int  `json:"total"`
+	Completed  int  `json:"completed"`
+	Skipped    int  `json:"skipped"`
+	Remaining  int  `json:"remaining"`
+}
+
+// SessionStatus is the status of one generated member workout
+type SessionStatus struct {
+	WeekNumber int
+	Status     string
+}
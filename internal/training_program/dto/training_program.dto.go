@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// TrainingProgramDTO is a multi-week program; each week scales the base workouts of its days by its progression rule
+type TrainingProgramDTO struct {
+	ID          string           `json:"id"`
+	CreatedBy   string           `json:"created_by"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	IsActive    bool             `json:"is_active"`
+	Weeks       []ProgramWeekDTO `json:"weeks,omitempty"` // left out of listings
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// ProgramWeekDTO changes are percentages against the base workouts, not the previous week, so a deload week does not carry over
+type ProgramWeekDTO struct {
+	WeekNumber          int             `json:"week_number"`
+	IsDeload            bool            `json:"is_deload"`
+	LoadChangePercent   float64         `json:"load_change_percent"`   // applied to exercise weights
+	VolumeChangePercent float64         `json:"volume_change_percent"` // applied to exercise sets
+	Notes               *string         `json:"notes,omitempty"`
+	Days                []ProgramDayDTO `json:"days"`
+}
+
+// ProgramDayDTO references the base workout of a day: an existing workout instance, or a template built with auto-picked exercises
+type ProgramDayDTO struct {
+	DayNumber         int     `json:"day_number"` // 1 to 7 within the program week
+	Name              string  `json:"name"`
+	WorkoutInstanceID *string `json:"workout_instance_id,omitempty"`
+	TemplateSource    *string `json:"template_source,omitempty"`
+	PublicTemplateID  *string `json:"public_template_id,omitempty"`
+	GymTemplateID     *string `json:"gym_template_id,omitempty"`
+	TemplateVersion   *int    `json:"template_version,omitempty"`
+}
+
+type CreateTrainingProgramDTO struct {
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	Weeks       []ProgramWeekDTO `json:"weeks"`
+	CreatedBy   string           `json:"-"`
+}
@@ -0,0 +1,10 @@
+package enum
+
+// EnrollmentStatus is where a member is in a training program; completion is derived from the member's workouts
+type EnrollmentStatus string
+
+const (
+	Active    EnrollmentStatus = "active"
+	Completed EnrollmentStatus = "completed"
+	Cancelled EnrollmentStatus = "cancelled"
+)
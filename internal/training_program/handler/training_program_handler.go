@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TrainingProgramHandler struct {
+	service interfaces.TrainingProgramService
+}
+
+func NewTrainingProgramHandler(service interfaces.TrainingProgramService) *TrainingProgramHandler {
+	return &TrainingProgramHandler{service: service}
+}
+
+func (h *TrainingProgramHandler) CreateProgram(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var program dto.CreateTrainingProgramDTO
+	if err := json.NewDecoder(r.Body).Decode(&program); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	program.CreatedBy = middleware.GetUserID(r)
+
+	created, err := h.service.CreateProgram(middleware.GetGymID(r), &program)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to create training program")
+		return
+	}
+	response.WriteAPICreated(w, "Training program created successfully", created)
+}
+
+func (h *TrainingProgramHandler) ListPrograms(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	programs, err := h.service.ListPrograms(middleware.GetGymID(r))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list training programs")
+		return
+	}
+	response.WriteAPISuccess(w, "Training programs retrieved successfully", programs)
+}
+
+func (h *TrainingProgramHandler) GetProgram(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	program, err := h.service.GetProgram(middleware.GetGymID(r), chi.URLParam(r, "programID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get training program")
+		return
+	}
+	response.WriteAPISuccess(w, "Training program retrieved successfully", program)
+}
+
+func (h *TrainingProgramHandler) DeleteProgram(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.DeleteProgram(middleware.GetGymID(r), chi.URLParam(r, "programID")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete training program")
+		return
+	}
+	response.WriteAPISuccess(w, "Training program deleted successfully", nil)
+}
+
+func (h *TrainingProgramHandler) EnrollMember(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var enrollment dto.EnrollMemberDTO
+	if err := json.NewDecoder(r.Body).Decode(&enrollment); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	enrollment.CreatedBy = middleware.GetUserID(r)
+
+	result, err := h.service.EnrollMember(middleware.GetGymID(r), chi.URLParam(r, "programID"), &enrollment)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to enroll member")
+		return
+	}
+	response.WriteAPICreated(w, "Member enrolled successfully", result)
+}
+
+func (h *TrainingProgramHandler) ListProgramEnrollments(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	enrollments, err := h.service.ListProgramEnrollments(middleware.GetGymID(r), chi.URLParam(r, "programID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list program enrollments")
+		return
+	}
+	response.WriteAPISuccess(w, "Program enrollments retrieved successfully", enrollments)
+}
+
+func (h *TrainingProgramHandler) ListMemberEnrollments(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "memberID")
+	if !requireGymUser(w, r) || !requireSelfOrAdmin(w, r, memberID) {
+		return
+	}
+	enrollments, err := h.service.ListMemberEnrollments(middleware.GetGymID(r), memberID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list member enrollments")
+		return
+	}
+	response.WriteAPISuccess(w, "Member enrollments retrieved successfully", enrollments)
+}
+
+func (h *TrainingProgramHandler) GetEnrollmentProgress(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	progress, err := h.service.GetEnrollmentProgress(middleware.GetGymID(r), chi.URLParam(r, "enrollmentID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get enrollment progress")
+		return
+	}
+	if !requireSelfOrAdmin(w, r, progress.MemberID) {
+		return
+	}
+	response.WriteAPISuccess(w, "Enrollment progress retrieved successfully", progress)
+}
+
+func (h *TrainingProgramHandler) CancelEnrollment(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.CancelEnrollment(middleware.GetGymID(r), chi.URLParam(r, "enrollmentID")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to cancel enrollment")
+		return
+	}
+	response.WriteAPISuccess(w, "Enrollment cancelled successfully", nil)
+}
+
+// requireGymUser writes a 400 unless the request is scoped to a gym
+func requireGymUser(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Training programs belong to a gym", nil))
+	return false
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym that runs the programs
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage training programs", nil))
+	return false
+}
+
+// requireSelfOrAdmin writes a 403 unless members follow their own enrollments
+func requireSelfOrAdmin(w http.ResponseWriter, r *http.Request, memberID string) bool {
+	if middleware.IsGymAdmin(r) || middleware.GetUserID(r) == memberID {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Members can only follow their own enrollments", nil))
+	return false
+}
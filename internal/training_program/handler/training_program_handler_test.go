@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/training_program/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.TrainingProgramService
+	created  *dto.CreateTrainingProgramDTO
+	enrolled *dto.EnrollMemberDTO
+	memberID string
+}
+
+func (m *mockService) CreateProgram(gymID string, program *dto.CreateTrainingProgramDTO) (*dto.TrainingProgramDTO, error) {
+	m.created = program
+	return &dto.TrainingProgramDTO{ID: "program-1", Name: program.Name}, nil
+}
+func (m *mockService) EnrollMember(gymID, programID string, enrollment *dto.EnrollMemberDTO) (*dto.EnrollmentResultDTO, error) {
+	m.enrolled = enrollment
+	return &dto.EnrollmentResultDTO{EnrollmentID: "enrollment-1", ScheduledWorkouts: 3}, nil
+}
+func (m *mockService) ListMemberEnrollments(gymID, memberID string) ([]*dto.ProgramEnrollmentDTO, error) {
+	m.memberID = memberID
+	return []*dto.ProgramEnrollmentDTO{}, nil
+}
+func (m *mockService) GetEnrollmentProgress(gymID, enrollmentID string) (*dto.EnrollmentProgressDTO, error) {
+	return &dto.EnrollmentProgressDTO{ProgramEnrollmentDTO: dto.ProgramEnrollmentDTO{ID: enrollmentID, MemberID: "member-1"}}, nil
+}
+
+func serve(svc *mockService, method, target, body, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewTrainingProgramRouter(NewTrainingProgramHandler(svc)),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, method, target, body)
+}
+
+func TestCreateProgramRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+	body := `{"name":"Hypertrophy","weeks":[{"week_number":1,"days":[{"day_number":1,"name":"Legs","workout_instance_id":"instance-1"}]}]}`
+
+	w := serve(svc, http.MethodPost, "/", body, "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.created)
+
+	w = serve(svc, http.MethodPost, "/", body, "coach-1", "admin")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "coach-1", svc.created.CreatedBy)
+	assert.Equal(t, "instance-1", *svc.created.Weeks[0].Days[0].WorkoutInstanceID)
+}
+
+func TestEnrollMember(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPost, "/program-1/enrollments", `{"member_id":"member-1","start_date":"2026-03-02"}`, "coach-1", "admin")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "member-1", svc.enrolled.MemberID)
+	assert.Equal(t, "coach-1", svc.enrolled.CreatedBy)
+	assert.Contains(t, w.Body.String(), `"scheduled_workouts":3`)
+
+	w = serve(svc, http.MethodPost, "/program-1/enrollments", `{`, "coach-1", "admin")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMembersFollowOnlyTheirOwnEnrollments(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/enrollments/member/member-1", "", "member-2", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(svc, http.MethodGet, "/enrollments/member/member-1", "", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-1", svc.memberID)
+
+	w = serve(svc, http.MethodGet, "/enrollments/enrollment-1", "", "member-2", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(svc, http.MethodGet, "/enrollments/enrollment-1", "", "coach-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "net/http"
+
+type TrainingProgramHandler interface {
+	CreateProgram(w http.ResponseWriter, r *http.Request)
+	ListPrograms(w http.ResponseWriter, r *http.Request)
+	GetProgram(w http.ResponseWriter, r *http.Request)
+	DeleteProgram(w http.ResponseWriter, r *http.Request)
+	EnrollMember(w http.ResponseWriter, r *http.Request)
+	ListProgramEnrollments(w http.ResponseWriter, r *http.Request)
+	ListMemberEnrollments(w http.ResponseWriter, r *http.Request)
+	GetEnrollmentProgress(w http.ResponseWriter, r *http.Request)
+	CancelEnrollment(w http.ResponseWriter, r *http.Request)
+}
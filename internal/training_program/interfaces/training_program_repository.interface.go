@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+)
+
+type TrainingProgramRepository interface {
+	Create(gymID string, program *dto.CreateTrainingProgramDTO) (string, error)
+	// FindByID returns deleted programs too so their enrollments can still be followed
+	FindByID(gymID, programID string) (*dto.TrainingProgramDTO, error)
+	FindAll(gymID string) ([]*dto.TrainingProgramDTO, error)
+	Deactivate(gymID, programID string) error
+
+	InstanceExists(gymID, instanceID string) (bool, error)
+	// FindInstanceDraft loads a saved workout instance as a draft so it can be scaled and saved again
+	FindInstanceDraft(gymID, instanceID string) (*instanceDTO.WorkoutInstanceDraftDTO, error)
+
+	// CreateEnrollment saves the enrollment and schedules its member workouts in one transaction
+	CreateEnrollment(gymID, programID string, enrollment *dto.EnrollMemberDTO, sessions []dto.ScheduledSession) (string, error)
+	FindEnrollment(gymID, enrollmentID string) (*dto.ProgramEnrollmentDTO, error)
+	FindEnrollmentsByProgram(gymID, programID string) ([]*dto.ProgramEnrollmentDTO, error)
+	FindEnrollmentsByMember(gymID, memberID string) ([]*dto.ProgramEnrollmentDTO, error)
+	FindSessionStatuses(gymID, enrollmentID string) ([]dto.SessionStatus, error)
+	// CancelEnrollment also cancels the member workouts still scheduled
+	CancelEnrollment(gymID, enrollmentID string) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/training_program/dto"
+
+type TrainingProgramService interface {
+	CreateProgram(gymID string, program *dto.CreateTrainingProgramDTO) (*dto.TrainingProgramDTO, error)
+	ListPrograms(gymID string) ([]*dto.TrainingProgramDTO, error)
+	GetProgram(gymID, programID string) (*dto.TrainingProgramDTO, error)
+	DeleteProgram(gymID, programID string) error
+
+	// EnrollMember generates the member's workouts for every program day, scaled by the week's progression
+	EnrollMember(gymID, programID string, enrollment *dto.EnrollMemberDTO) (*dto.EnrollmentResultDTO, error)
+	ListProgramEnrollments(gymID, programID string) ([]*dto.ProgramEnrollmentDTO, error)
+	ListMemberEnrollments(gymID, memberID string) ([]*dto.ProgramEnrollmentDTO, error)
+	GetEnrollmentProgress(gymID, enrollmentID string) (*dto.EnrollmentProgressDTO, error)
+	CancelEnrollment(gymID, enrollmentID string) error
+}
@@ -0,0 +1,27 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	contraindication_repository "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/repository"
+	contraindication_service "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/service"
+	workoutInstanceModule "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/module"
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	"github.com/alejandro-albiol/athenai/internal/training_program/handler"
+	"github.com/alejandro-albiol/athenai/internal/training_program/repository"
+	"github.com/alejandro-albiol/athenai/internal/training_program/router"
+	"github.com/alejandro-albiol/athenai/internal/training_program/service"
+)
+
+func NewTrainingProgramModule(db *sql.DB) http.Handler {
+	repo := repository.NewTrainingProgramRepository(db)
+	builder := workoutInstanceModule.NewWorkoutInstanceBuilderService(db)
+	checker := contraindication_service.NewContraindicationChecker(
+		contraindication_repository.NewCustomExerciseContraindicationRepository(db),
+		gym_repository.NewGymRepository(db),
+	)
+	service := service.NewTrainingProgramService(repo, builder, builder.Instances, checker)
+	handler := handler.NewTrainingProgramHandler(service)
+	return router.NewTrainingProgramRouter(handler)
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/enum"
+	"github.com/lib/pq"
+)
+
+type TrainingProgramRepository struct {
+	db *sql.DB
+}
+
+func NewTrainingProgramRepository(db *sql.DB) *TrainingProgramRepository {
+	return &TrainingProgramRepository{db: db}
+}
+
+const dateLayout = "2006-01-02"
+
+func (r *TrainingProgramRepository) Create(gymID string, program *dto.CreateTrainingProgramDTO) (string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id string
+	if err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.training_program (created_by, name, description)
+		VALUES ($1, $2, $3) RETURNING id`, schema), program.CreatedBy, program.Name, program.Description).Scan(&id); err != nil {
+		return "", err
+	}
+	for _, week := range program.Weeks {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.training_program_week
+			(program_id, week_number, is_deload, load_change_percent, volume_change_percent, notes)
+			VALUES ($1, $2, $3, $4, $5, $6)`, schema),
+			id, week.WeekNumber, week.IsDeload, week.LoadChangePercent, week.VolumeChangePercent, week.Notes); err != nil {
+			return "", err
+		}
+		for _, day := range week.Days {
+			if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.training_program_day
+				(program_id, week_number, day_number, name, workout_instance_id, template_source, public_template_id, gym_template_id, template_version)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, schema),
+				id, week.WeekNumber, day.DayNumber, day.Name, day.WorkoutInstanceID, day.TemplateSource, day.PublicTemplateID,
+				day.GymTemplateID, day.TemplateVersion); err != nil {
+				return "", err
+			}
+		}
+	}
+	return id, tx.Commit()
+}
+
+func (r *TrainingProgramRepository) FindByID(gymID, programID string) (*dto.TrainingProgramDTO, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	program := &dto.TrainingProgramDTO{Weeks: []dto.ProgramWeekDTO{}}
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT id, created_by, name, description, is_active, created_at, updated_at
+		FROM %s.training_program WHERE id = $1`, schema), programID).Scan(
+		&program.ID, &program.CreatedBy, &program.Name, &program.Description, &program.IsActive, &program.CreatedAt, &program.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	weeks, err := r.db.Query(fmt.Sprintf(`SELECT week_number, is_deload, load_change_percent::float8, volume_change_percent::float8, notes
+		FROM %s.training_program_week WHERE program_id = $1 ORDER BY week_number`, schema), programID)
+	if err != nil {
+		return nil, err
+	}
+	positions := map[int]int{}
+	for weeks.Next() {
+		week := dto.ProgramWeekDTO{Days: []dto.ProgramDayDTO{}}
+		if err := weeks.Scan(&week.WeekNumber, &week.IsDeload, &week.LoadChangePercent, &week.VolumeChangePercent, &week.Notes); err != nil {
+			weeks.Close()
+			return nil, err
+		}
+		positions[week.WeekNumber] = len(program.Weeks)
+		program.Weeks = append(program.Weeks, week)
+	}
+	weeks.Close()
+	if err := weeks.Err(); err != nil {
+		return nil, err
+	}
+
+	days, err := r.db.Query(fmt.Sprintf(`SELECT week_number, day_number, name, workout_instance_id, template_source,
+		public_template_id, gym_template_id, template_version
+		FROM %s.training_program_day WHERE program_id = $1 ORDER BY week_number, day_number`, schema), programID)
+	if err != nil {
+		return nil, err
+	}
+	defer days.Close()
+	for days.Next() {
+		var weekNumber int
+		var day dto.ProgramDayDTO
+		if err := days.Scan(&weekNumber, &day.DayNumber, &day.Name, &day.WorkoutInstanceID, &day.TemplateSource,
+			&day.PublicTemplateID, &day.GymTemplateID, &day.TemplateVersion); err != nil {
+			return nil, err
+		}
+		if i, ok := positions[weekNumber]; ok {
+			program.Weeks[i].Days = append(program.Weeks[i].Days, day)
+		}
+	}
+	return program, days.Err()
+}
+
+func (r *TrainingProgramRepository) FindAll(gymID string) ([]*dto.TrainingProgramDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT id, created_by, name, description, is_active, created_at, updated_at
+		FROM %s.training_program WHERE is_active = TRUE ORDER BY name`, pq.QuoteIdentifier(gymID)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	programs := []*dto.TrainingProgramDTO{}
+	for rows.Next() {
+		var program dto.TrainingProgramDTO
+		if err := rows.Scan(&program.ID, &program.CreatedBy, &program.Name, &program.Description, &program.IsActive,
+			&program.CreatedAt, &program.UpdatedAt); err != nil {
+			return nil, err
+		}
+		programs = append(programs, &program)
+	}
+	return programs, rows.Err()
+}
+
+func (r *TrainingProgramRepository) Deactivate(gymID, programID string) error {
+	result, err := r.db.Exec(fmt.Sprintf(`UPDATE %s.training_program SET is_active = FALSE, updated_at = NOW()
+		WHERE id = $1 AND is_active = TRUE`, pq.QuoteIdentifier(gymID)), programID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *TrainingProgramRepository) InstanceExists(gymID, instanceID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.custom_workout_instance WHERE id = $1)`,
+		pq.QuoteIdentifier(gymID)), instanceID).Scan(&exists)
+	return exists, err
+}
+
+func (r *TrainingProgramRepository) FindInstanceDraft(gymID, instanceID string) (*instanceDTO.WorkoutInstanceDraftDTO, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	draft := &instanceDTO.WorkoutInstanceDraftDTO{Blocks: []instanceDTO.DraftBlockDTO{}}
+	var description sql.NullString
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT name, description, template_source, public_template_id, gym_template_id, template_version
+		FROM %s.custom_workout_instance WHERE id = $1`, schema), instanceID).Scan(
+		&draft.Name, &description, &draft.TemplateSource, &draft.PublicTemplateID, &draft.GymTemplateID, &draft.TemplateVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+	draft.Description = description.String
+
+	// Blocks keep the order their exercises were saved in
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT block_name, exercise_order, exercise_source, public_exercise_id, gym_exercise_id,
+		sets, reps_min, reps_max, weight_kg::float8, duration_seconds, rest_seconds, notes,
+		group_id, group_type, group_rounds, group_rest_seconds
+		FROM %s.custom_workout_exercise
+		WHERE workout_instance_id = $1
+		ORDER BY created_at, block_name, exercise_order`, schema), instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	positions := map[string]int{}
+	for rows.Next() {
+		var blockName string
+		var exercise instanceDTO.DraftExerciseDTO
+		if err := rows.Scan(&blockName, &exercise.ExerciseOrder, &exercise.ExerciseSource, &exercise.PublicExerciseID,
+			&exercise.GymExerciseID, &exercise.Sets, &exercise.RepsMin, &exercise.RepsMax, &exercise.WeightKg,
+			&exercise.DurationSeconds, &exercise.RestSeconds, &exercise.Notes,
+			&exercise.GroupID, &exercise.GroupType, &exercise.GroupRounds, &exercise.GroupRestSeconds); err != nil {
+			return nil, err
+		}
+		i, ok := positions[blockName]
+		if !ok {
+			i = len(draft.Blocks)
+			positions[blockName] = i
+			draft.Blocks = append(draft.Blocks, instanceDTO.DraftBlockDTO{BlockName: blockName, BlockOrder: i + 1})
+		}
+		draft.Blocks[i].Exercises = append(draft.Blocks[i].Exercises, exercise)
+	}
+	return draft, rows.Err()
+}
+
+func (r *TrainingProgramRepository) CreateEnrollment(gymID, programID string, enrollment *dto.EnrollMemberDTO, sessions []dto.ScheduledSession) (string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id string
+	if err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.training_program_enrollment (program_id, member_id, start_date, status, created_by)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, schema),
+		programID, enrollment.MemberID, enrollment.StartDate, enum.Active, enrollment.CreatedBy).Scan(&id); err != nil {
+		return "", err
+	}
+	for _, session := range sessions {
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_member_workout
+			(created_by, member_id, workout_instance_id, scheduled_date, status, program_enrollment_id, program_week, program_day)
+			VALUES ($1, $2, $3, $4, 'scheduled', $5, $6, $7)`, schema),
+			enrollment.CreatedBy, enrollment.MemberID, session.WorkoutInstanceID, session.ScheduledDate, id,
+			session.WeekNumber, session.DayNumber); err != nil {
+			return "", err
+		}
+	}
+	return id, tx.Commit()
+}
+
+const selectEnrollments = `SELECT e.id, e.program_id, p.name, e.member_id, e.start_date, e.status, e.created_by, e.created_at
+	FROM %[1]s.training_program_enrollment e
+	JOIN %[1]s.training_program p ON p.id = e.program_id`
+
+func scanEnrollment(scanner interface{ Scan(...any) error }) (*dto.ProgramEnrollmentDTO, error) {
+	var enrollment dto.ProgramEnrollmentDTO
+	var startDate time.Time
+	if err := scanner.Scan(&enrollment.ID, &enrollment.ProgramID, &enrollment.ProgramName, &enrollment.MemberID, &startDate,
+		&enrollment.Status, &enrollment.CreatedBy, &enrollment.CreatedAt); err != nil {
+		return nil, err
+	}
+	enrollment.StartDate = startDate.Format(dateLayout)
+	return &enrollment, nil
+}
+
+func (r *TrainingProgramRepository) FindEnrollment(gymID, enrollmentID string) (*dto.ProgramEnrollmentDTO, error) {
+	return scanEnrollment(r.db.QueryRow(fmt.Sprintf(selectEnrollments+` WHERE e.id = $1`, pq.QuoteIdentifier(gymID)), enrollmentID))
+}
+
+func (r *TrainingProgramRepository) FindEnrollmentsByProgram(gymID, programID string) ([]*dto.ProgramEnrollmentDTO, error) {
+	return r.findEnrollments(fmt.Sprintf(selectEnrollments+` WHERE e.program_id = $1 ORDER BY e.start_date DESC`, pq.QuoteIdentifier(gymID)), programID)
+}
+
+func (r *TrainingProgramRepository) FindEnrollmentsByMember(gymID, memberID string) ([]*dto.ProgramEnrollmentDTO, error) {
+	return r.findEnrollments(fmt.Sprintf(selectEnrollments+` WHERE e.member_id = $1 ORDER BY e.start_date DESC`, pq.QuoteIdentifier(gymID)), memberID)
+}
+
+func (r *TrainingProgramRepository) findEnrollments(query string, args ...any) ([]*dto.ProgramEnrollmentDTO, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	enrollments := []*dto.ProgramEnrollmentDTO{}
+	for rows.Next() {
+		enrollment, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	return enrollments, rows.Err()
+}
+
+func (r *TrainingProgramRepository) FindSessionStatuses(gymID, enrollmentID string) ([]dto.SessionStatus, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT program_week, status FROM %s.custom_member_workout
+		WHERE program_enrollment_id = $1 ORDER BY program_week, program_day`, pq.QuoteIdentifier(gymID)), enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	statuses := []dto.SessionStatus{}
+	for rows.Next() {
+		var status dto.SessionStatus
+		if err := rows.Scan(&status.WeekNumber, &status.Status); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+func (r *TrainingProgramRepository) CancelEnrollment(gymID, enrollmentID string) error {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE %s.training_program_enrollment SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3`, schema), enrollmentID, enum.Cancelled, enum.Active)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	// Cancelled workouts get the same transition event as a cancel through the member workout endpoints.
+	// The gym's event lock keeps event sequences in commit order for consumers paging through them.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1 || '.custom_member_workout_event'))`, gymID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`WITH cancelled AS (
+			UPDATE %s.custom_member_workout SET status = 'cancelled', updated_at = NOW()
+			WHERE program_enrollment_id = $1 AND status = 'scheduled'
+			RETURNING id, member_id
+		)
+		INSERT INTO %s.custom_member_workout_event (member_workout_id, member_id, transition, from_status, to_status)
+		SELECT id, member_id, 'cancel', 'scheduled', 'cancelled' FROM cancelled`, schema, schema), enrollmentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
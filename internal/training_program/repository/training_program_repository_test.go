@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStoresWeeksAndDays(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTrainingProgramRepository(db)
+	gymTemplate := "gym"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "gym-1".training_program`).
+		WithArgs("coach-1", "Hypertrophy", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("program-1"))
+	mock.ExpectExec(`INSERT INTO "gym-1".training_program_week`).
+		WithArgs("program-1", 1, true, -10.0, -40.0, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "gym-1".training_program_day`).
+		WithArgs("program-1", 1, 2, "Push", nil, &gymTemplate, nil, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.Create("gym-1", &dto.CreateTrainingProgramDTO{Name: "Hypertrophy", CreatedBy: "coach-1", Weeks: []dto.ProgramWeekDTO{
+		{WeekNumber: 1, IsDeload: true, LoadChangePercent: -10, VolumeChangePercent: -40, Days: []dto.ProgramDayDTO{
+			{DayNumber: 2, Name: "Push", TemplateSource: &gymTemplate, GymTemplateID: &gymTemplate},
+		}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "program-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindInstanceDraftGroupsExercisesByBlock(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTrainingProgramRepository(db)
+
+	mock.ExpectQuery(`FROM "gym-1".custom_workout_instance WHERE id = \$1`).
+		WithArgs("instance-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "template_source", "public_template_id", "gym_template_id", "template_version"}).
+			AddRow("Legs", nil, "gym", nil, "template-1", 2))
+	mock.ExpectQuery(`FROM "gym-1".custom_workout_exercise`).
+		WithArgs("instance-1").
+		WillReturnRows(sqlmock.NewRows([]string{"block_name", "exercise_order", "exercise_source", "public_exercise_id", "gym_exercise_id",
+			"sets", "reps_min", "reps_max", "weight_kg", "duration_seconds", "rest_seconds", "notes",
+			"group_id", "group_type", "group_rounds", "group_rest_seconds"}).
+			AddRow("Warm-up", 1, "public", "bike", nil, nil, nil, nil, nil, 300, nil, nil, nil, nil, nil, nil).
+			AddRow("Main", 1, "public", "squat", nil, 4, 6, 8, 100.0, nil, 120, nil, "A", "superset", 4, 120).
+			AddRow("Main", 2, "gym", nil, "sled", 3, nil, nil, 40.0, nil, 90, nil, "A", "superset", 4, 120))
+
+	draft, err := repo.FindInstanceDraft("gym-1", "instance-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Legs", draft.Name)
+	assert.Equal(t, 2, *draft.TemplateVersion)
+	if assert.Len(t, draft.Blocks, 2) {
+		assert.Equal(t, "Warm-up", draft.Blocks[0].BlockName)
+		assert.Equal(t, 2, draft.Blocks[1].BlockOrder)
+		assert.Len(t, draft.Blocks[1].Exercises, 2)
+		assert.Equal(t, 100.0, *draft.Blocks[1].Exercises[0].WeightKg)
+		assert.Equal(t, "A", *draft.Blocks[1].Exercises[1].GroupID, "progressed copies keep their supersets")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateEnrollmentSchedulesWorkouts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTrainingProgramRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "gym-1".training_program_enrollment`).
+		WithArgs("program-1", "member-1", "2026-03-02", "active", "coach-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("enrollment-1"))
+	mock.ExpectExec(`INSERT INTO "gym-1".custom_member_workout`).
+		WithArgs("coach-1", "member-1", "instance-1", "2026-03-02", "enrollment-1", 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "gym-1".custom_member_workout`).
+		WithArgs("coach-1", "member-1", "instance-2", "2026-03-09", "enrollment-1", 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateEnrollment("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02", CreatedBy: "coach-1"},
+		[]dto.ScheduledSession{
+			{WorkoutInstanceID: "instance-1", ScheduledDate: "2026-03-02", WeekNumber: 1, DayNumber: 1},
+			{WorkoutInstanceID: "instance-2", ScheduledDate: "2026-03-09", WeekNumber: 2, DayNumber: 1},
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "enrollment-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindEnrollmentFormatsStartDate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTrainingProgramRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM "gym-1".training_program_enrollment e`).
+		WithArgs("enrollment-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "name", "member_id", "start_date", "status", "created_by", "created_at"}).
+			AddRow("enrollment-1", "program-1", "Hypertrophy", "member-1", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "active", "coach-1", now))
+
+	enrollment, err := repo.FindEnrollment("gym-1", "enrollment-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", enrollment.StartDate)
+	assert.Equal(t, "Hypertrophy", enrollment.ProgramName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelEnrollmentCancelsScheduledWorkouts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTrainingProgramRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "gym-1".training_program_enrollment SET status = \$2`).
+		WithArgs("enrollment-1", "cancelled", "active").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("gym-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`WITH cancelled AS \( UPDATE "gym-1".custom_member_workout SET status = 'cancelled'.* INSERT INTO "gym-1".custom_member_workout_event`).
+		WithArgs("enrollment-1").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+	require.NoError(t, repo.CancelEnrollment("gym-1", "enrollment-1"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "gym-1".training_program_enrollment`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.CancelEnrollment("gym-1", "enrollment-1"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/training_program/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTrainingProgramRouter(handler interfaces.TrainingProgramHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/", handler.CreateProgram)                                     // POST /training-program
+	r.Get("/", handler.ListPrograms)                                       // GET /training-program
+	r.Get("/enrollments/member/{memberID}", handler.ListMemberEnrollments) // GET /training-program/enrollments/member/{memberID}
+	r.Get("/enrollments/{enrollmentID}", handler.GetEnrollmentProgress)    // GET /training-program/enrollments/{enrollmentID}
+	r.Post("/enrollments/{enrollmentID}/cancel", handler.CancelEnrollment) // POST /training-program/enrollments/{enrollmentID}/cancel
+	r.Get("/{programID}", handler.GetProgram)                              // GET /training-program/{programID}
+	r.Delete("/{programID}", handler.DeleteProgram)                        // DELETE /training-program/{programID}
+	r.Post("/{programID}/enrollments", handler.EnrollMember)               // POST /training-program/{programID}/enrollments
+	r.Get("/{programID}/enrollments", handler.ListProgramEnrollments)      // GET /training-program/{programID}/enrollments
+
+	return r
+}
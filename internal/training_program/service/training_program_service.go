@@ -0,0 +1,380 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	contraindicationIF "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	instanceIF "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/enum"
+	"github.com/alejandro-albiol/athenai/internal/training_program/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+const dateLayout = "2006-01-02"
+
+type TrainingProgramService struct {
+	repo      interfaces.TrainingProgramRepository
+	builder   instanceIF.WorkoutInstanceBuilderService
+	instances instanceIF.CustomWorkoutInstanceService
+	checker   contraindicationIF.ContraindicationChecker
+	now       func() time.Time
+}
+
+func NewTrainingProgramService(
+	repo interfaces.TrainingProgramRepository,
+	builder instanceIF.WorkoutInstanceBuilderService,
+	instances instanceIF.CustomWorkoutInstanceService,
+	checker contraindicationIF.ContraindicationChecker,
+) *TrainingProgramService {
+	return &TrainingProgramService{repo: repo, builder: builder, instances: instances, checker: checker, now: time.Now}
+}
+
+func (s *TrainingProgramService) CreateProgram(gymID string, program *dto.CreateTrainingProgramDTO) (*dto.TrainingProgramDTO, error) {
+	program.Name = strings.TrimSpace(program.Name)
+	if program.Name == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Program name is required", nil)
+	}
+	if len(program.Weeks) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Program needs at least one week", nil)
+	}
+	for i, week := range program.Weeks {
+		if week.WeekNumber != i+1 {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Weeks must be numbered from 1 in order without gaps", nil)
+		}
+		if week.LoadChangePercent <= -100 || week.VolumeChangePercent <= -100 {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Week %d cannot reduce load or volume by 100%% or more", week.WeekNumber), nil)
+		}
+		days := map[int]bool{}
+		for _, day := range week.Days {
+			if err := s.validateDay(gymID, week.WeekNumber, day); err != nil {
+				return nil, err
+			}
+			if days[day.DayNumber] {
+				return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Week %d has day %d twice", week.WeekNumber, day.DayNumber), nil)
+			}
+			days[day.DayNumber] = true
+		}
+	}
+
+	id, err := s.repo.Create(gymID, program)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create training program", err)
+	}
+	return s.GetProgram(gymID, id)
+}
+
+// validateDay checks that a day names exactly one base workout and that a referenced instance exists
+func (s *TrainingProgramService) validateDay(gymID string, weekNumber int, day dto.ProgramDayDTO) error {
+	label := fmt.Sprintf("Week %d day %d", weekNumber, day.DayNumber)
+	if day.DayNumber < 1 || day.DayNumber > 7 {
+		return apierror.New(errorcode_enum.CodeBadRequest, label+": day_number must be between 1 and 7", nil)
+	}
+	if strings.TrimSpace(day.Name) == "" {
+		return apierror.New(errorcode_enum.CodeBadRequest, label+": name is required", nil)
+	}
+
+	if day.WorkoutInstanceID != nil {
+		if day.TemplateSource != nil || day.PublicTemplateID != nil || day.GymTemplateID != nil || day.TemplateVersion != nil {
+			return apierror.New(errorcode_enum.CodeBadRequest, label+": reference either a workout instance or a template, not both", nil)
+		}
+		exists, err := s.repo.InstanceExists(gymID, *day.WorkoutInstanceID)
+		if err != nil {
+			return apierror.New(errorcode_enum.CodeInternal, "Failed to check workout instance", err)
+		}
+		if !exists {
+			return apierror.New(errorcode_enum.CodeBadRequest, label+": workout instance not found", nil)
+		}
+		return nil
+	}
+
+	switch {
+	case day.TemplateSource == nil:
+		return apierror.New(errorcode_enum.CodeBadRequest, label+": workout_instance_id or template_source is required", nil)
+	case *day.TemplateSource == "public" && day.PublicTemplateID != nil && day.GymTemplateID == nil:
+	case *day.TemplateSource == "gym" && day.GymTemplateID != nil && day.PublicTemplateID == nil:
+	default:
+		return apierror.New(errorcode_enum.CodeBadRequest, label+": template_source must be 'public' or 'gym' with its matching template id", nil)
+	}
+	return nil
+}
+
+func (s *TrainingProgramService) ListPrograms(gymID string) ([]*dto.TrainingProgramDTO, error) {
+	programs, err := s.repo.FindAll(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list training programs", err)
+	}
+	return programs, nil
+}
+
+func (s *TrainingProgramService) GetProgram(gymID, programID string) (*dto.TrainingProgramDTO, error) {
+	program, err := s.findProgram(gymID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if !program.IsActive {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, "Training program not found", nil)
+	}
+	return program, nil
+}
+
+func (s *TrainingProgramService) findProgram(gymID, programID string) (*dto.TrainingProgramDTO, error) {
+	program, err := s.repo.FindByID(gymID, programID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Training program not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get training program", err)
+	}
+	return program, nil
+}
+
+// DeleteProgram retires a program; members already enrolled keep their schedule
+func (s *TrainingProgramService) DeleteProgram(gymID, programID string) error {
+	if err := s.repo.Deactivate(gymID, programID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Training program not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete training program", err)
+	}
+	return nil
+}
+
+// EnrollMember builds one workout instance per program day and week, scaled by the week's progression, and
+// schedules them for the member from the start date. Days whose week changes nothing reuse their instance.
+// Instances built before a failure are deleted again.
+func (s *TrainingProgramService) EnrollMember(gymID, programID string, enrollment *dto.EnrollMemberDTO) (*dto.EnrollmentResultDTO, error) {
+	if enrollment.MemberID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "member_id is required", nil)
+	}
+	start, err := time.Parse(dateLayout, enrollment.StartDate)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "start_date must be a date in YYYY-MM-DD format", err)
+	}
+	program, err := s.GetProgram(gymID, programID)
+	if err != nil {
+		return nil, err
+	}
+
+	var built []string
+	cleanUp := func() {
+		for _, id := range built {
+			_ = s.instances.DeleteCustomWorkoutInstance(gymID, id)
+		}
+	}
+	result := &dto.EnrollmentResultDTO{}
+	checked := map[string]bool{}
+	var sessions []dto.ScheduledSession
+	for _, week := range program.Weeks {
+		for _, day := range week.Days {
+			instanceID, created, err := s.dayInstance(gymID, enrollment.CreatedBy, week, day)
+			if err != nil {
+				cleanUp()
+				return nil, err
+			}
+			if created {
+				built = append(built, instanceID)
+			}
+			if s.checker != nil && !checked[instanceID] {
+				checked[instanceID] = true
+				warnings, err := s.checker.CheckMemberWorkout(gymID, enrollment.MemberID, instanceID)
+				if err != nil {
+					cleanUp()
+					return nil, err
+				}
+				result.Warnings = appendWarnings(result.Warnings, warnings)
+			}
+			sessions = append(sessions, dto.ScheduledSession{
+				WorkoutInstanceID: instanceID,
+				ScheduledDate:     start.AddDate(0, 0, (week.WeekNumber-1)*7+day.DayNumber-1).Format(dateLayout),
+				WeekNumber:        week.WeekNumber,
+				DayNumber:         day.DayNumber,
+			})
+		}
+	}
+	if len(sessions) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Training program has no workout days", nil)
+	}
+
+	id, err := s.repo.CreateEnrollment(gymID, programID, enrollment, sessions)
+	if err != nil {
+		cleanUp()
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to enroll member", err)
+	}
+	result.EnrollmentID = id
+	result.ScheduledWorkouts = len(sessions)
+	return result, nil
+}
+
+// dayInstance returns the workout instance a member does on a program day, reporting whether it was built for it
+func (s *TrainingProgramService) dayInstance(gymID, createdBy string, week dto.ProgramWeekDTO, day dto.ProgramDayDTO) (string, bool, error) {
+	unchanged := week.LoadChangePercent == 0 && week.VolumeChangePercent == 0
+	var draft *instanceDTO.WorkoutInstanceDraftDTO
+	if day.WorkoutInstanceID != nil {
+		if unchanged {
+			return *day.WorkoutInstanceID, false, nil
+		}
+		var err error
+		if draft, err = s.repo.FindInstanceDraft(gymID, *day.WorkoutInstanceID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", false, apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("Workout instance of week %d day %d not found", week.WeekNumber, day.DayNumber), err)
+			}
+			return "", false, apierror.New(errorcode_enum.CodeInternal, "Failed to read workout instance", err)
+		}
+	} else {
+		build := &instanceDTO.BuildCustomWorkoutInstanceDTO{AutoPick: true}
+		build.TemplateSource = *day.TemplateSource
+		build.PublicTemplateID = day.PublicTemplateID
+		build.GymTemplateID = day.GymTemplateID
+		build.TemplateVersion = day.TemplateVersion
+		var err error
+		if draft, err = s.builder.PreviewBuild(gymID, createdBy, build); err != nil {
+			return "", false, err
+		}
+	}
+
+	draft.Name = fmt.Sprintf("%s - Week %d", day.Name, week.WeekNumber)
+	ApplyProgression(draft, week.LoadChangePercent, week.VolumeChangePercent)
+	id, _, err := s.builder.SaveBuild(gymID, createdBy, draft)
+	if err != nil {
+		return "", false, err
+	}
+	return *id, true, nil
+}
+
+// ApplyProgression scales exercise weights by loadPercent, rounded to 0.5 kg, and sets by volumePercent,
+// keeping at least one set
+func ApplyProgression(draft *instanceDTO.WorkoutInstanceDraftDTO, loadPercent, volumePercent float64) {
+	for i := range draft.Blocks {
+		for j := range draft.Blocks[i].Exercises {
+			exercise := &draft.Blocks[i].Exercises[j]
+			if exercise.WeightKg != nil {
+				weight := math.Round(*exercise.WeightKg*(1+loadPercent/100)*2) / 2
+				exercise.WeightKg = &weight
+			}
+			if exercise.Sets != nil {
+				sets := max(1, int(math.Round(float64(*exercise.Sets)*(1+volumePercent/100))))
+				exercise.Sets = &sets
+			}
+		}
+	}
+}
+
+// appendWarnings skips warnings already reported for the same exercise and situation
+func appendWarnings(all, warnings []*contraindication_dto.ContraindicationWarning) []*contraindication_dto.ContraindicationWarning {
+	for _, warning := range warnings {
+		duplicate := false
+		for _, existing := range all {
+			if existing.ExerciseSource == warning.ExerciseSource && existing.ExerciseID == warning.ExerciseID &&
+				existing.SpecialSituation == warning.SpecialSituation {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			all = append(all, warning)
+		}
+	}
+	return all
+}
+
+func (s *TrainingProgramService) ListProgramEnrollments(gymID, programID string) ([]*dto.ProgramEnrollmentDTO, error) {
+	if _, err := s.findProgram(gymID, programID); err != nil {
+		return nil, err
+	}
+	enrollments, err := s.repo.FindEnrollmentsByProgram(gymID, programID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list program enrollments", err)
+	}
+	return enrollments, nil
+}
+
+func (s *TrainingProgramService) ListMemberEnrollments(gymID, memberID string) ([]*dto.ProgramEnrollmentDTO, error) {
+	enrollments, err := s.repo.FindEnrollmentsByMember(gymID, memberID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list member enrollments", err)
+	}
+	return enrollments, nil
+}
+
+// GetEnrollmentProgress counts the enrollment's workouts by status. An active enrollment with nothing
+// left to do is reported as completed.
+func (s *TrainingProgramService) GetEnrollmentProgress(gymID, enrollmentID string) (*dto.EnrollmentProgressDTO, error) {
+	enrollment, err := s.repo.FindEnrollment(gymID, enrollmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Enrollment not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get enrollment", err)
+	}
+	program, err := s.findProgram(gymID, enrollment.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.repo.FindSessionStatuses(gymID, enrollmentID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get enrollment workouts", err)
+	}
+
+	progress := &dto.EnrollmentProgressDTO{ProgramEnrollmentDTO: *enrollment, Weeks: []dto.WeekProgressDTO{}}
+	positions := map[int]int{}
+	for _, week := range program.Weeks {
+		positions[week.WeekNumber] = len(progress.Weeks)
+		progress.Weeks = append(progress.Weeks, dto.WeekProgressDTO{WeekNumber: week.WeekNumber, IsDeload: week.IsDeload})
+	}
+	for _, session := range statuses {
+		i, ok := positions[session.WeekNumber]
+		if !ok {
+			continue
+		}
+		week := &progress.Weeks[i]
+		week.Total++
+		switch session.Status {
+		case "completed":
+			week.Completed++
+		case "skipped":
+			week.Skipped++
+		case "scheduled", "in_progress", "paused":
+			week.Remaining++
+		}
+	}
+	for _, week := range progress.Weeks {
+		progress.TotalWorkouts += week.Total
+		progress.Completed += week.Completed
+		progress.Skipped += week.Skipped
+		progress.Remaining += week.Remaining
+	}
+	if progress.TotalWorkouts > 0 {
+		progress.PercentComplete = math.Round(float64(progress.Completed)/float64(progress.TotalWorkouts)*1000) / 10
+	}
+	if progress.Status == string(enum.Active) && progress.TotalWorkouts > 0 && progress.Remaining == 0 {
+		progress.Status = string(enum.Completed)
+	}
+
+	if start, err := time.Parse(dateLayout, enrollment.StartDate); err == nil && len(program.Weeks) > 0 {
+		today, _ := time.Parse(dateLayout, s.now().Format(dateLayout))
+		if !today.Before(start) {
+			week := min(int(today.Sub(start).Hours()/24)/7+1, len(program.Weeks))
+			progress.CurrentWeek = &week
+		}
+	}
+	return progress, nil
+}
+
+func (s *TrainingProgramService) CancelEnrollment(gymID, enrollmentID string) error {
+	if err := s.repo.CancelEnrollment(gymID, enrollmentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Active enrollment not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to cancel enrollment", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	workoutExerciseDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_exercise/dto"
+	instanceDTO "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/dto"
+	instanceIF "github.com/alejandro-albiol/athenai/internal/custom_workout_instance/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/training_program/dto"
+	"github.com/alejandro-albiol/athenai/internal/training_program/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.TrainingProgramRepository
+	programs    map[string]*dto.TrainingProgramDTO
+	created     *dto.CreateTrainingProgramDTO
+	instances   map[string]func() *instanceDTO.WorkoutInstanceDraftDTO
+	sessions    []dto.ScheduledSession
+	enrollment  *dto.ProgramEnrollmentDTO
+	statuses    []dto.SessionStatus
+	enrollError error
+}
+
+func (m *mockRepository) Create(gymID string, program *dto.CreateTrainingProgramDTO) (string, error) {
+	m.created = program
+	m.programs["program-new"] = &dto.TrainingProgramDTO{ID: "program-new", Name: program.Name, IsActive: true, Weeks: program.Weeks}
+	return "program-new", nil
+}
+func (m *mockRepository) FindByID(gymID, programID string) (*dto.TrainingProgramDTO, error) {
+	program, ok := m.programs[programID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return program, nil
+}
+func (m *mockRepository) InstanceExists(gymID, instanceID string) (bool, error) {
+	_, ok := m.instances[instanceID]
+	return ok, nil
+}
+func (m *mockRepository) FindInstanceDraft(gymID, instanceID string) (*instanceDTO.WorkoutInstanceDraftDTO, error) {
+	load, ok := m.instances[instanceID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return load(), nil
+}
+func (m *mockRepository) CreateEnrollment(gymID, programID string, enrollment *dto.EnrollMemberDTO, sessions []dto.ScheduledSession) (string, error) {
+	if m.enrollError != nil {
+		return "", m.enrollError
+	}
+	m.sessions = sessions
+	return "enrollment-new", nil
+}
+func (m *mockRepository) FindEnrollment(gymID, enrollmentID string) (*dto.ProgramEnrollmentDTO, error) {
+	if m.enrollment == nil || m.enrollment.ID != enrollmentID {
+		return nil, sql.ErrNoRows
+	}
+	return m.enrollment, nil
+}
+func (m *mockRepository) FindSessionStatuses(gymID, enrollmentID string) ([]dto.SessionStatus, error) {
+	return m.statuses, nil
+}
+func (m *mockRepository) Deactivate(gymID, programID string) error {
+	if _, ok := m.programs[programID]; !ok {
+		return sql.ErrNoRows
+	}
+	m.programs[programID].IsActive = false
+	return nil
+}
+
+type mockBuilder struct {
+	instanceIF.WorkoutInstanceBuilderService
+	saved []*instanceDTO.WorkoutInstanceDraftDTO
+}
+
+func (m *mockBuilder) PreviewBuild(gymID, createdBy string, build *instanceDTO.BuildCustomWorkoutInstanceDTO) (*instanceDTO.WorkoutInstanceDraftDTO, error) {
+	return &instanceDTO.WorkoutInstanceDraftDTO{
+		CreateCustomWorkoutInstanceDTO: build.CreateCustomWorkoutInstanceDTO,
+		Blocks: []instanceDTO.DraftBlockDTO{{BlockName: "Main", Exercises: []instanceDTO.DraftExerciseDTO{
+			{ExerciseOrder: 1, ExerciseSource: strPtr("public"), PublicExerciseID: strPtr("squat"), Sets: intPtr(4), WeightKg: floatPtr(60)},
+		}}},
+	}, nil
+}
+func (m *mockBuilder) SaveBuild(gymID, createdBy string, draft *instanceDTO.WorkoutInstanceDraftDTO) (*string, *workoutExerciseDTO.CreationWarnings, error) {
+	m.saved = append(m.saved, draft)
+	id := "built-" + draft.Name
+	return &id, &workoutExerciseDTO.CreationWarnings{}, nil
+}
+
+type mockInstances struct {
+	instanceIF.CustomWorkoutInstanceService
+	deleted []string
+}
+
+func (m *mockInstances) DeleteCustomWorkoutInstance(gymID, id string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+type mockChecker struct {
+	warnings []*contraindication_dto.ContraindicationWarning
+	err      error
+}
+
+func (m *mockChecker) CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return m.warnings, m.err
+}
+func (m *mockChecker) CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return nil, nil
+}
+
+func strPtr(s string) *string     { return &s }
+func intPtr(i int) *int           { return &i }
+func floatPtr(f float64) *float64 { return &f }
+
+func hypertrophyBlock() *dto.TrainingProgramDTO {
+	return &dto.TrainingProgramDTO{ID: "program-1", Name: "Hypertrophy", IsActive: true, Weeks: []dto.ProgramWeekDTO{
+		{WeekNumber: 1, Days: []dto.ProgramDayDTO{
+			{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")},
+			{DayNumber: 3, Name: "Push", TemplateSource: strPtr("gym"), GymTemplateID: strPtr("template-push")},
+		}},
+		{WeekNumber: 2, LoadChangePercent: 5, VolumeChangePercent: 25, Days: []dto.ProgramDayDTO{
+			{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")},
+		}},
+		{WeekNumber: 3, IsDeload: true, LoadChangePercent: -20, VolumeChangePercent: -50, Days: []dto.ProgramDayDTO{
+			{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")},
+		}},
+	}}
+}
+
+func legsDraft() *instanceDTO.WorkoutInstanceDraftDTO {
+	draft := &instanceDTO.WorkoutInstanceDraftDTO{Blocks: []instanceDTO.DraftBlockDTO{{BlockName: "Main", Exercises: []instanceDTO.DraftExerciseDTO{
+		{ExerciseOrder: 1, ExerciseSource: strPtr("public"), PublicExerciseID: strPtr("deadlift"), Sets: intPtr(3), WeightKg: floatPtr(100)},
+	}}}}
+	draft.Name = "Legs"
+	draft.TemplateSource = "gym"
+	draft.GymTemplateID = strPtr("template-legs")
+	return draft
+}
+
+func newTestService() (*TrainingProgramService, *mockRepository, *mockBuilder, *mockInstances) {
+	repo := &mockRepository{
+		programs:  map[string]*dto.TrainingProgramDTO{"program-1": hypertrophyBlock()},
+		instances: map[string]func() *instanceDTO.WorkoutInstanceDraftDTO{"instance-legs": legsDraft},
+	}
+	builder := &mockBuilder{}
+	instances := &mockInstances{}
+	return NewTrainingProgramService(repo, builder, instances, &mockChecker{}), repo, builder, instances
+}
+
+func TestCreateProgram(t *testing.T) {
+	svc, repo, _, _ := newTestService()
+	program, err := svc.CreateProgram("gym-1", &dto.CreateTrainingProgramDTO{Name: " Strength ", Weeks: []dto.ProgramWeekDTO{
+		{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 2, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")}}},
+		{WeekNumber: 2, LoadChangePercent: 2.5, Days: []dto.ProgramDayDTO{{DayNumber: 2, Name: "Push", TemplateSource: strPtr("public"), PublicTemplateID: strPtr("template-1")}}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "program-new", program.ID)
+	assert.Equal(t, "Strength", repo.created.Name)
+}
+
+func TestCreateProgramRejectsInvalidWeeks(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	legs := dto.ProgramDayDTO{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")}
+	cases := map[string][]dto.ProgramWeekDTO{
+		"no weeks":          nil,
+		"gap":               {{WeekNumber: 1}, {WeekNumber: 3}},
+		"full deload":       {{WeekNumber: 1, VolumeChangePercent: -100}},
+		"day out of range":  {{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 8, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs")}}}},
+		"duplicate day":     {{WeekNumber: 1, Days: []dto.ProgramDayDTO{legs, legs}}},
+		"unknown instance":  {{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("missing")}}}},
+		"no reference":      {{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 1, Name: "Legs"}}}},
+		"both references":   {{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 1, Name: "Legs", WorkoutInstanceID: strPtr("instance-legs"), TemplateSource: strPtr("gym")}}}},
+		"mismatched source": {{WeekNumber: 1, Days: []dto.ProgramDayDTO{{DayNumber: 1, Name: "Legs", TemplateSource: strPtr("gym"), PublicTemplateID: strPtr("template-1")}}}},
+	}
+	for name, weeks := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.CreateProgram("gym-1", &dto.CreateTrainingProgramDTO{Name: "Strength", Weeks: weeks})
+			testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+		})
+	}
+}
+
+func TestGetProgramHidesDeletedPrograms(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	require.NoError(t, svc.DeleteProgram("gym-1", "program-1"))
+	_, err := svc.GetProgram("gym-1", "program-1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	testutil.AssertCode(t, svc.DeleteProgram("gym-1", "missing"), errorcode_enum.CodeNotFound)
+}
+
+func TestEnrollMemberSchedulesProgressedWorkouts(t *testing.T) {
+	svc, repo, builder, _ := newTestService()
+	result, err := svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02", CreatedBy: "coach-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "enrollment-new", result.EnrollmentID)
+	assert.Equal(t, 4, result.ScheduledWorkouts)
+
+	assert.Equal(t, []dto.ScheduledSession{
+		{WorkoutInstanceID: "instance-legs", ScheduledDate: "2026-03-02", WeekNumber: 1, DayNumber: 1},
+		{WorkoutInstanceID: "built-Push - Week 1", ScheduledDate: "2026-03-04", WeekNumber: 1, DayNumber: 3},
+		{WorkoutInstanceID: "built-Legs - Week 2", ScheduledDate: "2026-03-09", WeekNumber: 2, DayNumber: 1},
+		{WorkoutInstanceID: "built-Legs - Week 3", ScheduledDate: "2026-03-16", WeekNumber: 3, DayNumber: 1},
+	}, repo.sessions)
+
+	require.Len(t, builder.saved, 3)
+	week2 := builder.saved[1].Blocks[0].Exercises[0]
+	assert.Equal(t, 105.0, *week2.WeightKg)
+	assert.Equal(t, 4, *week2.Sets)
+	deload := builder.saved[2].Blocks[0].Exercises[0]
+	assert.Equal(t, 80.0, *deload.WeightKg)
+	assert.Equal(t, 2, *deload.Sets)
+}
+
+func TestEnrollMemberDeletesBuiltInstancesOnFailure(t *testing.T) {
+	svc, repo, _, instances := newTestService()
+	repo.enrollError = errors.New("db down")
+	_, err := svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeInternal)
+	assert.ElementsMatch(t, []string{"built-Push - Week 1", "built-Legs - Week 2", "built-Legs - Week 3"}, instances.deleted)
+}
+
+func TestEnrollMemberStopsOnBlockedContraindication(t *testing.T) {
+	svc, _, _, instances := newTestService()
+	svc.checker = &mockChecker{err: apierror.New(errorcode_enum.CodeConflict, "blocked", nil)}
+	_, err := svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+	assert.Empty(t, instances.deleted)
+}
+
+func TestEnrollMemberReportsWarningsOnce(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	warning := &contraindication_dto.ContraindicationWarning{MemberID: "member-1", ExerciseSource: "public", ExerciseID: "squat", SpecialSituation: "knee"}
+	svc.checker = &mockChecker{warnings: []*contraindication_dto.ContraindicationWarning{warning}}
+	result, err := svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02"})
+	require.NoError(t, err)
+	assert.Len(t, result.Warnings, 1)
+}
+
+func TestEnrollMemberValidatesInput(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	_, err := svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{StartDate: "2026-03-02"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	_, err = svc.EnrollMember("gym-1", "program-1", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "03/02/2026"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+	_, err = svc.EnrollMember("gym-1", "missing", &dto.EnrollMemberDTO{MemberID: "member-1", StartDate: "2026-03-02"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
+
+func TestApplyProgressionKeepsOneSet(t *testing.T) {
+	draft := &instanceDTO.WorkoutInstanceDraftDTO{Blocks: []instanceDTO.DraftBlockDTO{{Exercises: []instanceDTO.DraftExerciseDTO{
+		{Sets: intPtr(1), WeightKg: floatPtr(22.5)},
+		{DurationSeconds: intPtr(60)},
+	}}}}
+	ApplyProgression(draft, 10, -60)
+	assert.Equal(t, 1, *draft.Blocks[0].Exercises[0].Sets)
+	assert.Equal(t, 25.0, *draft.Blocks[0].Exercises[0].WeightKg)
+	assert.Nil(t, draft.Blocks[0].Exercises[1].Sets)
+}
+
+func TestGetEnrollmentProgress(t *testing.T) {
+	svc, repo, _, _ := newTestService()
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC) }
+	repo.enrollment = &dto.ProgramEnrollmentDTO{ID: "enrollment-1", ProgramID: "program-1", MemberID: "member-1", StartDate: "2026-03-02", Status: "active"}
+	repo.statuses = []dto.SessionStatus{
+		{WeekNumber: 1, Status: "completed"},
+		{WeekNumber: 1, Status: "skipped"},
+		{WeekNumber: 2, Status: "completed"},
+		{WeekNumber: 3, Status: "scheduled"},
+	}
+
+	progress, err := svc.GetEnrollmentProgress("gym-1", "enrollment-1")
+	require.NoError(t, err)
+	assert.Equal(t, "active", progress.Status)
+	require.NotNil(t, progress.CurrentWeek)
+	assert.Equal(t, 2, *progress.CurrentWeek)
+	assert.Equal(t, 4, progress.TotalWorkouts)
+	assert.Equal(t, 2, progress.Completed)
+	assert.Equal(t, 1, progress.Skipped)
+	assert.Equal(t, 1, progress.Remaining)
+	assert.Equal(t, 50.0, progress.PercentComplete)
+	require.Len(t, progress.Weeks, 3)
+	assert.True(t, progress.Weeks[2].IsDeload)
+	assert.Equal(t, 1, progress.Weeks[2].Remaining)
+
+	repo.statuses[3].Status = "completed"
+	progress, err = svc.GetEnrollmentProgress("gym-1", "enrollment-1")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", progress.Status)
+}
+
+func TestGetEnrollmentProgressBeforeStart(t *testing.T) {
+	svc, repo, _, _ := newTestService()
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC) }
+	repo.enrollment = &dto.ProgramEnrollmentDTO{ID: "enrollment-1", ProgramID: "program-1", StartDate: "2026-03-02", Status: "active"}
+	progress, err := svc.GetEnrollmentProgress("gym-1", "enrollment-1")
+	require.NoError(t, err)
+	assert.Nil(t, progress.CurrentWeek)
+
+	_, err = svc.GetEnrollmentProgress("gym-1", "missing")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
@@ -0,0 +1,16 @@
+package dto
+
+// MissingTranslation is a catalog field that has no translation in a locale yet
+type MissingTranslation struct {
+	EntityType   string `json:"entity_type"`
+	EntityID     string `json:"entity_id"`
+	EntityName   string `json:"entity_name"`
+	Field        string `json:"field"`
+	DefaultValue string `json:"default_value"`
+}
+
+type MissingTranslationReport struct {
+	Locale  string                `json:"locale"`
+	Total   int                   `json:"total"`
+	Missing []*MissingTranslation `json:"missing"`
+}
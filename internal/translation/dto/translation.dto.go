@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// TranslationDTO creates or replaces the translation of one field of a catalog entity
+type TranslationDTO struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"`
+	Locale     string `json:"locale"`
+	Value      string `json:"value"`
+	UpdatedBy  string `json:"-"`
+}
+
+type TranslationResponseDTO struct {
+	ID         string    `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Field      string    `json:"field"`
+	Locale     string    `json:"locale"`
+	Value      string    `json:"value"`
+	UpdatedBy  *string   `json:"updated_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TranslationSet holds translated values by entity ID and field
+type TranslationSet map[string]map[string]string
+
+// Apply overwrites target with the translation of the entity's field, if there is one
+func (s TranslationSet) Apply(entityID, field string, target *string) {
+	if value, ok := s[entityID][field]; ok {
+		*target = value
+	}
+}
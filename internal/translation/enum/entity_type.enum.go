@@ -0,0 +1,41 @@
+package enum
+
+type EntityType string
+
+const (
+	Exercise      EntityType = "exercise"
+	MuscularGroup EntityType = "muscular_group"
+	Equipment     EntityType = "equipment"
+)
+
+func (e EntityType) IsValid() bool {
+	switch e {
+	case Exercise, MuscularGroup, Equipment:
+		return true
+	}
+	return false
+}
+
+// Fields lists the translatable text columns of the entity
+func (e EntityType) Fields() []string {
+	switch e {
+	case Exercise:
+		return []string{"name", "instructions"}
+	case MuscularGroup, Equipment:
+		return []string{"name", "description"}
+	}
+	return nil
+}
+
+func (e EntityType) HasField(field string) bool {
+	for _, f := range e.Fields() {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func AllEntityTypes() []EntityType {
+	return []EntityType{Exercise, MuscularGroup, Equipment}
+}
@@ -0,0 +1,33 @@
+package enum
+
+import "strings"
+
+type Locale string
+
+const (
+	English Locale = "en"
+	Spanish Locale = "es"
+)
+
+// DefaultLocale is the language of the catalog columns themselves, it never has translations
+const DefaultLocale = English
+
+func (l Locale) IsValid() bool {
+	switch l {
+	case English, Spanish:
+		return true
+	}
+	return false
+}
+
+// TranslatedLocales lists the locales that are stored in the translation table
+func TranslatedLocales() []Locale {
+	return []Locale{Spanish}
+}
+
+// MatchLocale maps a language tag such as "es-ES" to a supported locale
+func MatchLocale(tag string) (Locale, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	locale := Locale(primary)
+	return locale, locale.IsValid()
+}
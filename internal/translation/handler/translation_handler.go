@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/alejandro-albiol/athenai/internal/translation/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type TranslationHandler struct {
+	service interfaces.TranslationService
+}
+
+func NewTranslationHandler(service interfaces.TranslationService) *TranslationHandler {
+	return &TranslationHandler{service: service}
+}
+
+func (h *TranslationHandler) SaveTranslation(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	var translation dto.TranslationDTO
+	if err := json.NewDecoder(r.Body).Decode(&translation); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	translation.UpdatedBy = middleware.GetUserID(r)
+
+	id, err := h.service.SaveTranslation(&translation)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to save translation")
+		return
+	}
+	response.WriteAPISuccess(w, "Translation saved successfully", id)
+}
+
+func (h *TranslationHandler) GetEntityTranslations(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	translations, err := h.service.GetEntityTranslations(chi.URLParam(r, "entityType"), chi.URLParam(r, "entityID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get translations")
+		return
+	}
+	response.WriteAPISuccess(w, "Translations retrieved successfully", translations)
+}
+
+func (h *TranslationHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	if err := h.service.DeleteTranslation(chi.URLParam(r, "id")); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to delete translation")
+		return
+	}
+	response.WriteAPISuccess(w, "Translation deleted successfully", nil)
+}
+
+func (h *TranslationHandler) MissingTranslations(w http.ResponseWriter, r *http.Request) {
+	if !requirePlatformAdmin(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	reports, err := h.service.MissingTranslations(query.Get("locale"), query.Get("entity_type"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to find missing translations")
+		return
+	}
+	response.WriteAPISuccess(w, "Missing translations retrieved successfully", reports)
+}
+
+// requirePlatformAdmin writes a 403 unless the caller administers the public catalog
+func requirePlatformAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsPlatformAdmin(r) {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only platform administrators can manage translations", nil))
+	return false
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/alejandro-albiol/athenai/internal/translation/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/translation/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.TranslationService
+	saved   *dto.TranslationDTO
+	filters []string
+}
+
+func (m *mockService) SaveTranslation(translation *dto.TranslationDTO) (*string, error) {
+	m.saved = translation
+	id := "translation-1"
+	return &id, nil
+}
+func (m *mockService) MissingTranslations(locale, entityType string) ([]*dto.MissingTranslationReport, error) {
+	m.filters = []string{locale, entityType}
+	return []*dto.MissingTranslationReport{{Locale: "es", Missing: []*dto.MissingTranslation{}}}, nil
+}
+
+func serve(svc *mockService, method, target, body, userType string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewTranslationRouter(NewTranslationHandler(svc)),
+		testutil.Caller{UserType: userType, Role: "admin", UserID: "admin-1"}, method, target, body)
+}
+
+func TestSaveTranslationRequiresPlatformAdmin(t *testing.T) {
+	svc := &mockService{}
+	body := `{"entity_type":"exercise","entity_id":"squat","field":"name","locale":"es","value":"Sentadilla"}`
+
+	w := serve(svc, http.MethodPut, "/", body, "tenant_user")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.saved)
+
+	w = serve(svc, http.MethodPut, "/", body, "platform_admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, svc.saved) {
+		assert.Equal(t, "Sentadilla", svc.saved.Value)
+		assert.Equal(t, "admin-1", svc.saved.UpdatedBy)
+	}
+}
+
+func TestMissingTranslationsPassesFilters(t *testing.T) {
+	svc := &mockService{}
+	w := serve(svc, http.MethodGet, "/missing?locale=es&entity_type=equipment", "", "platform_admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"es", "equipment"}, svc.filters)
+}
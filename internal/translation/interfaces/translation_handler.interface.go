@@ -0,0 +1,10 @@
+package interfaces
+
+import "net/http"
+
+type TranslationHandler interface {
+	SaveTranslation(w http.ResponseWriter, r *http.Request)
+	GetEntityTranslations(w http.ResponseWriter, r *http.Request)
+	DeleteTranslation(w http.ResponseWriter, r *http.Request)
+	MissingTranslations(w http.ResponseWriter, r *http.Request)
+}
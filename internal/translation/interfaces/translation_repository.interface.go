@@ -0,0 +1,13 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/translation/dto"
+
+type TranslationRepository interface {
+	Upsert(translation *dto.TranslationDTO) (*string, error)
+	FindByEntity(entityType, entityID string) ([]*dto.TranslationResponseDTO, error)
+	FindByEntities(entityType, locale string, entityIDs []string) ([]*dto.TranslationResponseDTO, error)
+	Delete(id string) error
+	EntityExists(entityType, entityID string) (bool, error)
+	FindMissing(entityType, field, locale string) ([]*dto.MissingTranslation, error)
+	GetUserLocale(gymID, userID string) (*string, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/alejandro-albiol/athenai/internal/translation/enum"
+)
+
+type TranslationService interface {
+	Localizer
+
+	SaveTranslation(translation *dto.TranslationDTO) (*string, error)
+	GetEntityTranslations(entityType, entityID string) ([]*dto.TranslationResponseDTO, error)
+	DeleteTranslation(id string) error
+	MissingTranslations(locale, entityType string) ([]*dto.MissingTranslationReport, error)
+}
+
+// Localizer resolves the caller's locale and looks up catalog translations for it
+type Localizer interface {
+	// ResolveLocale prefers the user's stored preference, then Accept-Language, then the default locale
+	ResolveLocale(gymID, userID, acceptLanguage string) enum.Locale
+	Lookup(locale enum.Locale, entityType enum.EntityType, entityIDs []string) (dto.TranslationSet, error)
+}
@@ -0,0 +1,23 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/handler"
+	"github.com/alejandro-albiol/athenai/internal/translation/repository"
+	"github.com/alejandro-albiol/athenai/internal/translation/router"
+	"github.com/alejandro-albiol/athenai/internal/translation/service"
+)
+
+func NewTranslationModule(db *sql.DB) http.Handler {
+	repo := repository.NewTranslationRepository(db)
+	service := service.NewTranslationService(repo)
+	handler := handler.NewTranslationHandler(service)
+	return router.NewTranslationRouter(handler)
+}
+
+// NewTranslationService builds the service for modules that localize catalog reads
+func NewTranslationService(db *sql.DB) *service.TranslationService {
+	return service.NewTranslationService(repository.NewTranslationRepository(db))
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/lib/pq"
+)
+
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+// catalogTables maps translatable entity types to the public tables holding their default-language text
+var catalogTables = map[string]string{
+	"exercise":       "public.exercise",
+	"muscular_group": "public.muscular_group",
+	"equipment":      "public.equipment",
+}
+
+const selectTranslations = `SELECT id, entity_type, entity_id, field, locale, value, updated_by, created_at, updated_at
+		FROM public.translation`
+
+func (r *TranslationRepository) Upsert(translation *dto.TranslationDTO) (*string, error) {
+	query := `INSERT INTO public.translation (entity_type, entity_id, field, locale, value, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (entity_type, entity_id, field, locale) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING id`
+	var id string
+	err := r.db.QueryRow(query, translation.EntityType, translation.EntityID, translation.Field,
+		translation.Locale, translation.Value, translation.UpdatedBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *TranslationRepository) FindByEntity(entityType, entityID string) ([]*dto.TranslationResponseDTO, error) {
+	return r.queryTranslations(selectTranslations+" WHERE entity_type = $1 AND entity_id = $2 ORDER BY locale, field", entityType, entityID)
+}
+
+func (r *TranslationRepository) FindByEntities(entityType, locale string, entityIDs []string) ([]*dto.TranslationResponseDTO, error) {
+	return r.queryTranslations(selectTranslations+" WHERE entity_type = $1 AND locale = $2 AND entity_id::text = ANY($3)",
+		entityType, locale, pq.Array(entityIDs))
+}
+
+func (r *TranslationRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM public.translation WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *TranslationRepository) EntityExists(entityType, entityID string) (bool, error) {
+	table, ok := catalogTables[entityType]
+	if !ok {
+		return false, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", table), entityID).Scan(&exists)
+	return exists, err
+}
+
+// FindMissing lists active entities whose field has default-language text but no translation in the locale
+func (r *TranslationRepository) FindMissing(entityType, field, locale string) ([]*dto.MissingTranslation, error) {
+	table, ok := catalogTables[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	column := "e." + pq.QuoteIdentifier(field)
+	query := fmt.Sprintf(`SELECT e.id, e.name, %[1]s FROM %[2]s e
+		WHERE e.is_active = TRUE AND %[1]s IS NOT NULL AND %[1]s <> ''
+		AND NOT EXISTS (
+			SELECT 1 FROM public.translation t
+			WHERE t.entity_type = $1 AND t.entity_id = e.id AND t.field = $2 AND t.locale = $3
+		)
+		ORDER BY e.name`, column, table)
+	rows, err := r.db.Query(query, entityType, field, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []*dto.MissingTranslation
+	for rows.Next() {
+		m := &dto.MissingTranslation{EntityType: entityType, Field: field}
+		if err := rows.Scan(&m.EntityID, &m.EntityName, &m.DefaultValue); err != nil {
+			return nil, err
+		}
+		missing = append(missing, m)
+	}
+	return missing, rows.Err()
+}
+
+func (r *TranslationRepository) GetUserLocale(gymID, userID string) (*string, error) {
+	query := fmt.Sprintf("SELECT preferred_locale FROM %s.user WHERE id = $1", pq.QuoteIdentifier(gymID))
+	var locale *string
+	if err := r.db.QueryRow(query, userID).Scan(&locale); err != nil {
+		return nil, err
+	}
+	return locale, nil
+}
+
+func (r *TranslationRepository) queryTranslations(query string, args ...any) ([]*dto.TranslationResponseDTO, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*dto.TranslationResponseDTO
+	for rows.Next() {
+		var t dto.TranslationResponseDTO
+		if err := rows.Scan(&t.ID, &t.EntityType, &t.EntityID, &t.Field, &t.Locale, &t.Value, &t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, &t)
+	}
+	return translations, rows.Err()
+}
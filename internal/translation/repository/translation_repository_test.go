@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsert(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTranslationRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO public.translation`)+`(.+)ON CONFLICT \(entity_type, entity_id, field, locale\) DO UPDATE`).
+		WithArgs("exercise", "squat", "name", "es", "Sentadilla", "admin-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("translation-1"))
+
+	id, err := repo.Upsert(&dto.TranslationDTO{EntityType: "exercise", EntityID: "squat", Field: "name", Locale: "es", Value: "Sentadilla", UpdatedBy: "admin-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "translation-1", *id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindByEntities(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTranslationRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`FROM public.translation WHERE entity_type = \$1 AND locale = \$2 AND entity_id::text = ANY\(\$3\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "field", "locale", "value", "updated_by", "created_at", "updated_at"}).
+			AddRow("translation-1", "equipment", "rack", "name", "es", "Jaula", nil, now, now))
+
+	translations, err := repo.FindByEntities("equipment", "es", []string{"rack"})
+	require.NoError(t, err)
+	if assert.Len(t, translations, 1) {
+		assert.Equal(t, "Jaula", translations[0].Value)
+		assert.Nil(t, translations[0].UpdatedBy)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindMissing(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTranslationRepository(db)
+	mock.ExpectQuery(`SELECT e.id, e.name, e."description" FROM public.muscular_group e(.+)NOT EXISTS`).
+		WithArgs("muscular_group", "description", "es").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}).AddRow("glutes", "Glutes", "Hip extensors"))
+
+	missing, err := repo.FindMissing("muscular_group", "description", "es")
+	require.NoError(t, err)
+	if assert.Len(t, missing, 1) {
+		assert.Equal(t, "Glutes", missing[0].EntityName)
+		assert.Equal(t, "Hip extensors", missing[0].DefaultValue)
+		assert.Equal(t, "description", missing[0].Field)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = repo.FindMissing("workout", "name", "es")
+	assert.Error(t, err)
+}
+
+func TestGetUserLocale(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewTranslationRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT preferred_locale FROM "gym1".user WHERE id = $1`)).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"preferred_locale"}).AddRow("es"))
+
+	locale, err := repo.GetUserLocale("gym1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "es", *locale)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTranslationRouter(handler interfaces.TranslationHandler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Put("/", handler.SaveTranslation)                              // PUT /translation
+	r.Get("/missing", handler.MissingTranslations)                   // GET /translation/missing?locale=es&entity_type=exercise
+	r.Get("/{entityType}/{entityID}", handler.GetEntityTranslations) // GET /translation/{entityType}/{entityID}
+	r.Delete("/{id}", handler.DeleteTranslation)                     // DELETE /translation/{id}
+
+	return r
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/alejandro-albiol/athenai/internal/translation/enum"
+	"github.com/alejandro-albiol/athenai/internal/translation/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+type TranslationService struct {
+	repo interfaces.TranslationRepository
+}
+
+func NewTranslationService(repo interfaces.TranslationRepository) *TranslationService {
+	return &TranslationService{repo: repo}
+}
+
+func (s *TranslationService) SaveTranslation(translation *dto.TranslationDTO) (*string, error) {
+	entityType := enum.EntityType(translation.EntityType)
+	if !entityType.IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid entity type", nil)
+	}
+	if !entityType.HasField(translation.Field) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest,
+			fmt.Sprintf("Field must be one of %s for %s", strings.Join(entityType.Fields(), ", "), entityType), nil)
+	}
+	locale := enum.Locale(translation.Locale)
+	if !locale.IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Unsupported locale", nil)
+	}
+	if locale == enum.DefaultLocale {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest,
+			fmt.Sprintf("'%s' is the default language, update the %s itself", locale, entityType), nil)
+	}
+	translation.Value = strings.TrimSpace(translation.Value)
+	if translation.Value == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Value cannot be empty", nil)
+	}
+
+	exists, err := s.repo.EntityExists(translation.EntityType, translation.EntityID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to check entity", err)
+	}
+	if !exists {
+		return nil, apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("%s not found", entityType), nil)
+	}
+
+	id, err := s.repo.Upsert(translation)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to save translation", err)
+	}
+	return id, nil
+}
+
+func (s *TranslationService) GetEntityTranslations(entityType, entityID string) ([]*dto.TranslationResponseDTO, error) {
+	if !enum.EntityType(entityType).IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid entity type", nil)
+	}
+	translations, err := s.repo.FindByEntity(entityType, entityID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get translations", err)
+	}
+	if translations == nil {
+		translations = []*dto.TranslationResponseDTO{}
+	}
+	return translations, nil
+}
+
+func (s *TranslationService) DeleteTranslation(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeNotFound, "Translation not found", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to delete translation", err)
+	}
+	return nil
+}
+
+// MissingTranslations reports untranslated fields per locale. Empty filters cover every translated locale and entity type.
+func (s *TranslationService) MissingTranslations(locale, entityType string) ([]*dto.MissingTranslationReport, error) {
+	locales := enum.TranslatedLocales()
+	if locale != "" {
+		l := enum.Locale(locale)
+		if !l.IsValid() || l == enum.DefaultLocale {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Locale must be a translated locale", nil)
+		}
+		locales = []enum.Locale{l}
+	}
+	entityTypes := enum.AllEntityTypes()
+	if entityType != "" {
+		e := enum.EntityType(entityType)
+		if !e.IsValid() {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid entity type", nil)
+		}
+		entityTypes = []enum.EntityType{e}
+	}
+
+	reports := make([]*dto.MissingTranslationReport, 0, len(locales))
+	for _, l := range locales {
+		report := &dto.MissingTranslationReport{Locale: string(l), Missing: []*dto.MissingTranslation{}}
+		for _, e := range entityTypes {
+			for _, field := range e.Fields() {
+				missing, err := s.repo.FindMissing(string(e), field, string(l))
+				if err != nil {
+					return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to find missing translations", err)
+				}
+				report.Missing = append(report.Missing, missing...)
+			}
+		}
+		report.Total = len(report.Missing)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (s *TranslationService) ResolveLocale(gymID, userID, acceptLanguage string) enum.Locale {
+	if gymID != "" && userID != "" {
+		// A failed lookup only loses the preference, the request still gets a language
+		if preferred, err := s.repo.GetUserLocale(gymID, userID); err == nil && preferred != nil {
+			if locale := enum.Locale(*preferred); locale.IsValid() {
+				return locale
+			}
+		}
+	}
+	if locale, ok := parseAcceptLanguage(acceptLanguage); ok {
+		return locale
+	}
+	return enum.DefaultLocale
+}
+
+func (s *TranslationService) Lookup(locale enum.Locale, entityType enum.EntityType, entityIDs []string) (dto.TranslationSet, error) {
+	set := dto.TranslationSet{}
+	if locale == enum.DefaultLocale || len(entityIDs) == 0 {
+		return set, nil
+	}
+	translations, err := s.repo.FindByEntities(string(entityType), string(locale), entityIDs)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load translations", err)
+	}
+	for _, t := range translations {
+		if set[t.EntityID] == nil {
+			set[t.EntityID] = map[string]string{}
+		}
+		set[t.EntityID][t.Field] = t.Value
+	}
+	return set, nil
+}
+
+// parseAcceptLanguage picks the highest weighted supported language of an Accept-Language header
+func parseAcceptLanguage(header string) (enum.Locale, bool) {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	for _, c := range candidates {
+		if locale, ok := enum.MatchLocale(c.tag); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/translation/dto"
+	"github.com/alejandro-albiol/athenai/internal/translation/enum"
+	"github.com/alejandro-albiol/athenai/internal/translation/interfaces"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.TranslationRepository
+	exists      bool
+	saved       *dto.TranslationDTO
+	userLocale  *string
+	stored      []*dto.TranslationResponseDTO
+	lookups     int
+	missingArgs []string
+}
+
+func (m *mockRepository) EntityExists(entityType, entityID string) (bool, error) {
+	return m.exists, nil
+}
+func (m *mockRepository) Upsert(translation *dto.TranslationDTO) (*string, error) {
+	m.saved = translation
+	id := "translation-1"
+	return &id, nil
+}
+func (m *mockRepository) GetUserLocale(gymID, userID string) (*string, error) {
+	if m.userLocale == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.userLocale, nil
+}
+func (m *mockRepository) FindByEntities(entityType, locale string, entityIDs []string) ([]*dto.TranslationResponseDTO, error) {
+	m.lookups++
+	return m.stored, nil
+}
+func (m *mockRepository) FindMissing(entityType, field, locale string) ([]*dto.MissingTranslation, error) {
+	m.missingArgs = append(m.missingArgs, entityType+"."+field+"@"+locale)
+	if entityType == "exercise" && field == "name" {
+		return []*dto.MissingTranslation{{EntityType: entityType, EntityID: "squat", EntityName: "Back Squat", Field: field, DefaultValue: "Back Squat"}}, nil
+	}
+	return nil, nil
+}
+
+func TestSaveTranslation(t *testing.T) {
+	tests := []struct {
+		name        string
+		translation dto.TranslationDTO
+		code        string
+	}{
+		{"unknown entity type", dto.TranslationDTO{EntityType: "workout", Field: "name", Locale: "es", Value: "x"}, errorcode_enum.CodeBadRequest},
+		{"field not translatable", dto.TranslationDTO{EntityType: "exercise", Field: "description", Locale: "es", Value: "x"}, errorcode_enum.CodeBadRequest},
+		{"unsupported locale", dto.TranslationDTO{EntityType: "exercise", Field: "name", Locale: "fr", Value: "x"}, errorcode_enum.CodeBadRequest},
+		{"default locale", dto.TranslationDTO{EntityType: "exercise", Field: "name", Locale: "en", Value: "x"}, errorcode_enum.CodeBadRequest},
+		{"blank value", dto.TranslationDTO{EntityType: "exercise", Field: "name", Locale: "es", Value: "  "}, errorcode_enum.CodeBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{exists: true}
+			_, err := NewTranslationService(repo).SaveTranslation(&tc.translation)
+			testutil.AssertCode(t, err, tc.code)
+			assert.Nil(t, repo.saved)
+		})
+	}
+
+	t.Run("missing entity", func(t *testing.T) {
+		_, err := NewTranslationService(&mockRepository{}).SaveTranslation(&dto.TranslationDTO{EntityType: "equipment", EntityID: "rack", Field: "description", Locale: "es", Value: "Jaula"})
+		testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	})
+
+	t.Run("saves trimmed value", func(t *testing.T) {
+		repo := &mockRepository{exists: true}
+		id, err := NewTranslationService(repo).SaveTranslation(&dto.TranslationDTO{EntityType: "exercise", EntityID: "squat", Field: "name", Locale: "es", Value: " Sentadilla "})
+		require.NoError(t, err)
+		assert.Equal(t, "translation-1", *id)
+		assert.Equal(t, "Sentadilla", repo.saved.Value)
+	})
+}
+
+func TestResolveLocale(t *testing.T) {
+	svc := NewTranslationService(&mockRepository{})
+	assert.Equal(t, enum.Spanish, svc.ResolveLocale("", "", "es-ES,es;q=0.9,en;q=0.8"))
+	assert.Equal(t, enum.Spanish, svc.ResolveLocale("", "", "fr-FR, es;q=0.7, en;q=0.5"))
+	assert.Equal(t, enum.English, svc.ResolveLocale("", "", "de, es;q=0"))
+	assert.Equal(t, enum.English, svc.ResolveLocale("", "", ""))
+
+	// A stored preference beats the header
+	en := "en"
+	svc = NewTranslationService(&mockRepository{userLocale: &en})
+	assert.Equal(t, enum.English, svc.ResolveLocale("gym1", "user-1", "es"))
+}
+
+func TestLookup(t *testing.T) {
+	repo := &mockRepository{stored: []*dto.TranslationResponseDTO{
+		{EntityID: "squat", Field: "name", Value: "Sentadilla"},
+		{EntityID: "squat", Field: "instructions", Value: "Baja"},
+	}}
+	svc := NewTranslationService(repo)
+
+	set, err := svc.Lookup(enum.English, enum.Exercise, []string{"squat"})
+	require.NoError(t, err)
+	assert.Empty(t, set)
+	assert.Equal(t, 0, repo.lookups)
+
+	set, err = svc.Lookup(enum.Spanish, enum.Exercise, []string{"squat", "lunge"})
+	require.NoError(t, err)
+	name, other := "Back Squat", "Lunge"
+	set.Apply("squat", "name", &name)
+	set.Apply("lunge", "name", &other)
+	assert.Equal(t, "Sentadilla", name)
+	assert.Equal(t, "Lunge", other)
+}
+
+func TestMissingTranslations(t *testing.T) {
+	repo := &mockRepository{}
+	reports, err := NewTranslationService(repo).MissingTranslations("", "exercise")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "es", reports[0].Locale)
+	assert.Equal(t, 1, reports[0].Total)
+	assert.Equal(t, []string{"exercise.name@es", "exercise.instructions@es"}, repo.missingArgs)
+
+	_, err = NewTranslationService(repo).MissingTranslations("en", "")
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
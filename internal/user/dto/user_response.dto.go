@@ -18,6 +18,7 @@ type UserResponseDTO struct {
 	TrainingPhase    string                 `json:"training_phase"`
 	Motivation       string                 `json:"motivation"`
 	SpecialSituation string                 `json:"special_situation"`
+	PreferredLocale  *string                `json:"preferred_locale,omitempty"`
 	CreatedAt        time.Time              `json:"created_at"`
 	UpdatedAt        time.Time              `json:"updated_at"`
 }
@@ -5,10 +5,12 @@ import (
 	"net/http"
 
 	gymrepository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	personalrecordmodule "github.com/alejandro-albiol/athenai/internal/personal_record/module"
 	"github.com/alejandro-albiol/athenai/internal/user/handler"
 	"github.com/alejandro-albiol/athenai/internal/user/repository"
 	"github.com/alejandro-albiol/athenai/internal/user/router"
 	"github.com/alejandro-albiol/athenai/internal/user/service"
+	workoutanalyticsmodule "github.com/alejandro-albiol/athenai/internal/workout_analytics/module"
 )
 
 func NewUserModule(db *sql.DB) http.Handler {
@@ -19,5 +21,8 @@ func NewUserModule(db *sql.DB) http.Handler {
 	repo := repository.NewUsersRepository(db, gymRepo)
 	service := service.NewUsersService(repo)
 	handler := handler.NewUsersHandler(service)
-	return router.NewUsersRouter(handler)
+	return router.NewUsersRouter(handler,
+		personalrecordmodule.NewPersonalRecordModule(db),
+		workoutanalyticsmodule.NewWorkoutAnalyticsModule(db),
+	)
 }
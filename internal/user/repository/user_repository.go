@@ -93,7 +93,7 @@ func (r *UserRepository) GetUserByID(gymID, id string) (*dto.UserResponseDTO, er
 
 	query := fmt.Sprintf(`
 		SELECT id, username, email, password_hash, role, is_verified, is_active,
-			   description, training_phase, motivation, special_situation,
+			   description, training_phase, motivation, special_situation, preferred_locale,
 			   created_at, updated_at 
 		FROM %s 
 		WHERE id = $1 AND is_active = TRUE`, tableName)
@@ -104,7 +104,7 @@ func (r *UserRepository) GetUserByID(gymID, id string) (*dto.UserResponseDTO, er
 	err = row.Scan(
 		&user.ID, &user.Username, &user.Email, &passwordHash, &user.Role,
 		&user.Verified, &user.IsActive,
-		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation,
+		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation, &user.PreferredLocale,
 		&user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
@@ -128,7 +128,7 @@ func (r *UserRepository) GetUserByUsername(gymID, username string) (*dto.UserRes
 
 	query := fmt.Sprintf(`
 		SELECT id, username, email, password_hash, role, is_verified, is_active,
-			   description, training_phase, motivation, special_situation,
+			   description, training_phase, motivation, special_situation, preferred_locale,
 			   created_at, updated_at 
 		FROM %s 
 		WHERE username = $1 AND is_active = TRUE`, tableName)
@@ -136,7 +136,7 @@ func (r *UserRepository) GetUserByUsername(gymID, username string) (*dto.UserRes
 	user := &dto.UserResponseDTO{}
 	var passwordHash string // We don't return this in the DTO
 	err = row.Scan(&user.ID, &user.Username, &user.Email, &passwordHash, &user.Role, &user.Verified, &user.IsActive,
-		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation,
+		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation, &user.PreferredLocale,
 		&user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -157,12 +157,12 @@ func (r *UserRepository) GetUserByEmail(gymID, email string) (*dto.UserResponseD
 	// Construct tenant-specific table name
 	tableName := pq.QuoteIdentifier(gym.ID) + ".user"
 
-	query := fmt.Sprintf("SELECT id, username, email, password_hash, role, is_verified, is_active, description, training_phase, motivation, special_situation, created_at, updated_at FROM %s WHERE email = $1 AND is_active = TRUE", tableName)
+	query := fmt.Sprintf("SELECT id, username, email, password_hash, role, is_verified, is_active, description, training_phase, motivation, special_situation, preferred_locale, created_at, updated_at FROM %s WHERE email = $1 AND is_active = TRUE", tableName)
 	row := r.db.QueryRow(query, email)
 	user := &dto.UserResponseDTO{}
 	var passwordHash string // We don't return this in the DTO
 	err = row.Scan(&user.ID, &user.Username, &user.Email, &passwordHash, &user.Role, &user.Verified, &user.IsActive,
-		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation,
+		&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation, &user.PreferredLocale,
 		&user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -190,7 +190,7 @@ func (r *UserRepository) GetAllUsers(gymID string) ([]*dto.UserResponseDTO, erro
 	users := make([]*dto.UserResponseDTO, 0) // Initialize empty slice
 
 	query := fmt.Sprintf(`
-		SELECT id, username, email, password_hash, role, is_verified, is_active, description, training_phase, motivation, special_situation, created_at, updated_at 
+		SELECT id, username, email, password_hash, role, is_verified, is_active, description, training_phase, motivation, special_situation, preferred_locale, created_at, updated_at 
 		FROM %s 
 		WHERE is_active = TRUE`, tableName)
 
@@ -210,7 +210,7 @@ func (r *UserRepository) GetAllUsers(gymID string) ([]*dto.UserResponseDTO, erro
 		if err := rows.Scan(
 			&user.ID, &user.Username, &user.Email, &passwordHash, &user.Role,
 			&user.Verified, &user.IsActive,
-			&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation,
+			&user.Description, &user.TrainingPhase, &user.Motivation, &user.SpecialSituation, &user.PreferredLocale,
 			&user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, err
 		}
@@ -283,6 +283,11 @@ func (r *UserRepository) UpdateUser(gymID string, id string, user *dto.UserUpdat
 		setClause += fmt.Sprintf(", special_situation = $%d", paramCount)
 		params = append(params, user.SpecialSituation)
 	}
+	if user.PreferredLocale != nil {
+		paramCount++
+		setClause += fmt.Sprintf(", preferred_locale = $%d", paramCount)
+		params = append(params, user.PreferredLocale)
+	}
 
 	query := fmt.Sprintf("UPDATE %s %s WHERE id = $1 AND is_active = TRUE", tableName, setClause)
 	_, err = r.db.Exec(query, params...)
@@ -7,7 +7,8 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func NewUsersRouter(handler interfaces.UserHandler) http.Handler {
+// NewUsersRouter mounts the records router under /user/{id}/records and the analytics router under /user/{id}/analytics
+func NewUsersRouter(handler interfaces.UserHandler, records, analytics http.Handler) http.Handler {
 	r := chi.NewRouter()
 
 	// Auth middleware is applied globally at the API level
@@ -34,6 +35,8 @@ func NewUsersRouter(handler interfaces.UserHandler) http.Handler {
 	r.Delete("/{id}", handler.DeleteUser)                    // DELETE /user/{id}
 	r.Post("/{id}/verify", handler.VerifyUser)               // POST /user/{id}/verify
 	r.Post("/{id}/active", handler.SetUserActive)            // POST /user/{id}/active
+	r.Mount("/{id}/records", records)                        // GET /user/{id}/records, personal records
+	r.Mount("/{id}/analytics", analytics)                    // GET /user/{id}/analytics, training load series
 
 	return r
 }
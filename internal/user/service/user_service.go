@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	locale_enum "github.com/alejandro-albiol/athenai/internal/translation/enum"
 	dto "github.com/alejandro-albiol/athenai/internal/user/dto"
 	"github.com/alejandro-albiol/athenai/internal/user/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
@@ -99,6 +100,10 @@ func (s *UsersService) UpdateUser(gymID, id string, user *dto.UserUpdateDTO) err
 		return apierror.New(errorcode_enum.CodeNotFound, fmt.Sprintf("User with ID %s not found", id), err)
 	}
 
+	if user.PreferredLocale != nil && !locale_enum.Locale(*user.PreferredLocale).IsValid() {
+		return apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("Unsupported locale %s", *user.PreferredLocale), nil)
+	}
+
 	if user.Username != existingUser.Username {
 		existingUsername, err := s.repository.GetUserByUsername(gymID, user.Username)
 		if err == nil && existingUsername != nil && existingUsername.ID != "" {
@@ -598,6 +598,25 @@ func TestUpdateUser(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:   "unsupported locale",
+			gymID:  "gym123",
+			userID: "user123",
+			userDTO: dto.UserUpdateDTO{
+				Username:        "olduser",
+				Email:           "old@test.com",
+				PreferredLocale: func() *string { s := "fr"; return &s }(),
+			},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetUserByID", "gym123", "user123").Return(&dto.UserResponseDTO{
+					ID:       "user123",
+					Username: "olduser",
+					Email:    "old@test.com",
+					Role:     userrole_enum.Member,
+				}, nil)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// AnalyticsQuery are the parameters of a member's analytics; dates are YYYY-MM-DD in the gym's timezone and
+// empty ones take the service defaults
+type AnalyticsQuery struct {
+	From   string
+	To     string
+	Bucket string
+}
+
+// AnalyticsDTO is the training load of a member over completed workouts, as a series of weeks or months
+type AnalyticsDTO struct {
+	MemberID string       `json:"member_id"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Bucket   string       `json:"bucket"`
+	Timezone string       `json:"timezone"`
+	Series   []*BucketDTO `json:"series"`
+}
+
+// BucketDTO gathers the completed workouts of a week or month. Buckets at the edges are clipped to the
+// requested range, and per-week figures are scaled by the days the bucket covers.
+type BucketDTO struct {
+	Start           string  `json:"start"`
+	End             string  `json:"end"` // inclusive
+	Sessions        int     `json:"sessions"`
+	TrainingDays    int     `json:"training_days"`
+	SessionsPerWeek float64 `json:"sessions_per_week"`
+	Sets            int     `json:"sets"`
+	// Weighted sets per muscular group: completed sets x activation weight of each exercise link
+	SetsPerMuscleGroup       map[string]float64 `json:"sets_per_muscle_group"`
+	WeeklySetsPerMuscleGroup map[string]float64 `json:"weekly_sets_per_muscle_group"`
+	TonnageKg                float64            `json:"tonnage_kg"` // weight x reps of completed sets
+	// SessionRPELoad sums average set RPE x duration in minutes of each session; UnratedSessions have no
+	// RPE or start time to compute it from
+	SessionRPELoad  float64 `json:"session_rpe_load"`
+	UnratedSessions int     `json:"unrated_sessions"`
+	// Acute load is the session RPE load of the 7 days ending on End, chronic load the weekly average of
+	// the 28 days ending on End; ACWR is their ratio, unset without chronic load
+	AcuteLoad   float64  `json:"acute_load"`
+	ChronicLoad float64  `json:"chronic_load"`
+	ACWR        *float64 `json:"acwr"`
+}
+
+// Session is a completed member workout with the totals of its completed sets
+type Session struct {
+	MemberWorkoutID string
+	StartedAt       *time.Time
+	CompletedAt     time.Time
+	PausedSeconds   int
+	Sets            int
+	TonnageKg       float64
+	AverageRPE      *float64 // unset when no set has an RPE
+	// MuscleSets are the weighted sets of the session per muscular group
+	MuscleSets map[string]float64
+}
+
+// MuscleSets are the weighted sets a member workout gave a muscular group
+type MuscleSets struct {
+	MemberWorkoutID string
+	MuscleGroup     string
+	Sets            float64
+}
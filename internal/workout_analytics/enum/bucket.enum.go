@@ -0,0 +1,17 @@
+package enum
+
+// Bucket is the period analytics series are grouped by
+type Bucket string
+
+const (
+	Week  Bucket = "week" // ISO weeks, from Monday
+	Month Bucket = "month"
+)
+
+func (b Bucket) IsValid() bool {
+	switch b {
+	case Week, Month:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	user_enum "github.com/alejandro-albiol/athenai/internal/user/enum"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type WorkoutAnalyticsHandler struct {
+	service interfaces.WorkoutAnalyticsService
+}
+
+func NewWorkoutAnalyticsHandler(service interfaces.WorkoutAnalyticsService) *WorkoutAnalyticsHandler {
+	return &WorkoutAnalyticsHandler{service: service}
+}
+
+func (h *WorkoutAnalyticsHandler) GetMemberAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAnalyticsAccess(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	analytics, err := h.service.GetMemberAnalytics(middleware.GetGymID(r), userID, dto.AnalyticsQuery{
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+		Bucket: query.Get("bucket"),
+	})
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get workout analytics")
+		return
+	}
+	response.WriteAPISuccess(w, "Workout analytics retrieved successfully", analytics)
+}
+
+// requireAnalyticsAccess gives the user of the path when the caller may see their analytics: members see their
+// own, gym administrators and trainers anyone's in the gym
+func requireAnalyticsAccess(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if middleware.GetGymID(r) == "" {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Workout analytics belong to a gym user", nil))
+		return "", false
+	}
+	userID := chi.URLParam(r, "id")
+	if userID != middleware.GetUserID(r) && !middleware.IsGymAdmin(r) && middleware.GetUserRole(r) != string(user_enum.Trainer) {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: You can only see your own workout analytics", nil))
+		return "", false
+	}
+	return userID, true
+}
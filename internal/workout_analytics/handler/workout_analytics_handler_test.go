@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/router"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.WorkoutAnalyticsService
+	memberID string
+	query    dto.AnalyticsQuery
+}
+
+func (m *mockService) GetMemberAnalytics(gymID, memberID string, query dto.AnalyticsQuery) (*dto.AnalyticsDTO, error) {
+	m.memberID, m.query = memberID, query
+	if query.Bucket == "day" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Bucket must be week or month", nil)
+	}
+	return &dto.AnalyticsDTO{MemberID: memberID, Bucket: "week", Series: []*dto.BucketDTO{}}, nil
+}
+
+// serve routes the request the way the user router mounts analytics
+func serve(svc *mockService, target, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(testutil.Mount("/{id}/analytics", router.NewWorkoutAnalyticsRouter(NewWorkoutAnalyticsHandler(svc))),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, http.MethodGet, target, "")
+}
+
+func TestMembersOnlySeeTheirOwnAnalytics(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, "/member-1/analytics?from=2026-07-01&to=2026-09-30&bucket=month", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-1", svc.memberID)
+	assert.Equal(t, dto.AnalyticsQuery{From: "2026-07-01", To: "2026-09-30", Bucket: "month"}, svc.query)
+
+	svc.memberID = ""
+	w = serve(svc, "/member-2/analytics", "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "", svc.memberID)
+
+	w = serve(svc, "/member-2/analytics", "coach-1", "trainer")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-2", svc.memberID)
+
+	w = serve(svc, "/member-2/analytics?bucket=day", "coach-1", "trainer")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
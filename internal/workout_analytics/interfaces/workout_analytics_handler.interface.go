@@ -0,0 +1,7 @@
+package interfaces
+
+import "net/http"
+
+type WorkoutAnalyticsHandler interface {
+	GetMemberAnalytics(w http.ResponseWriter, r *http.Request)
+}
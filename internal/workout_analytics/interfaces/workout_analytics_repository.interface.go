@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+)
+
+type WorkoutAnalyticsRepository interface {
+	// FindCompletedSessions lists the member workouts completed in [from, to) with the totals of their
+	// completed sets, oldest first
+	FindCompletedSessions(gymID, memberID string, from, to time.Time) ([]*dto.Session, error)
+	// FindMuscleSets gives the weighted sets per muscular group of the member workouts completed in [from, to)
+	FindMuscleSets(gymID, memberID string, from, to time.Time) ([]dto.MuscleSets, error)
+}
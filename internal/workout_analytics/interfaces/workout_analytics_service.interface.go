@@ -0,0 +1,7 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+
+type WorkoutAnalyticsService interface {
+	GetMemberAnalytics(gymID, memberID string, query dto.AnalyticsQuery) (*dto.AnalyticsDTO, error)
+}
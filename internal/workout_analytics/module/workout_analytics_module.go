@@ -0,0 +1,19 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/handler"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/repository"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/router"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/service"
+)
+
+// NewWorkoutAnalyticsModule returns the analytics router, mounted by the user module under /user/{id}/analytics
+func NewWorkoutAnalyticsModule(db *sql.DB) http.Handler {
+	service := service.NewWorkoutAnalyticsService(repository.NewWorkoutAnalyticsRepository(db), gym_repository.NewGymRepository(db))
+	handler := handler.NewWorkoutAnalyticsHandler(service)
+	return router.NewWorkoutAnalyticsRouter(handler)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+	"github.com/lib/pq"
+)
+
+type WorkoutAnalyticsRepository struct {
+	db *sql.DB
+}
+
+func NewWorkoutAnalyticsRepository(db *sql.DB) *WorkoutAnalyticsRepository {
+	return &WorkoutAnalyticsRepository{db: db}
+}
+
+func (r *WorkoutAnalyticsRepository) FindCompletedSessions(gymID, memberID string, from, to time.Time) ([]*dto.Session, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT w.id, w.started_at, w.completed_at, w.paused_seconds,
+			COUNT(s.id), COALESCE(SUM(s.weight_kg * s.reps), 0), AVG(s.rpe)
+		FROM %[1]s.custom_member_workout w
+		LEFT JOIN %[1]s.custom_member_workout_set_log s ON s.member_workout_id = w.id AND s.completed
+		WHERE w.member_id = $1 AND w.status = 'completed' AND w.completed_at >= $2 AND w.completed_at < $3
+		GROUP BY w.id
+		ORDER BY w.completed_at, w.id`, pq.QuoteIdentifier(gymID)), memberID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := []*dto.Session{}
+	for rows.Next() {
+		var session dto.Session
+		if err := rows.Scan(&session.MemberWorkoutID, &session.StartedAt, &session.CompletedAt, &session.PausedSeconds,
+			&session.Sets, &session.TonnageKg, &session.AverageRPE); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, rows.Err()
+}
+
+// FindMuscleSets weights sets by the muscle links of public exercises or of the gym's own, as workout stats do
+func (r *WorkoutAnalyticsRepository) FindMuscleSets(gymID, memberID string, from, to time.Time) ([]dto.MuscleSets, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT s.member_workout_id, mg.name, SUM(l.activation_weight)
+		FROM %[1]s.custom_member_workout_set_log s
+		JOIN %[1]s.custom_member_workout w ON w.id = s.member_workout_id
+		JOIN %[1]s.custom_workout_exercise e ON e.id = s.workout_exercise_id
+		JOIN (
+			SELECT 'public' AS exercise_source, exercise_id, muscular_group_id, activation_weight
+			FROM public.exercise_muscular_group
+			UNION ALL
+			SELECT 'gym', custom_exercise_id, muscular_group_id, activation_weight
+			FROM %[1]s.custom_exercise_muscular_group
+		) l ON l.exercise_source = e.exercise_source AND l.exercise_id = COALESCE(e.public_exercise_id, e.gym_exercise_id)
+		JOIN public.muscular_group mg ON mg.id = l.muscular_group_id
+		WHERE w.member_id = $1 AND w.status = 'completed' AND w.completed_at >= $2 AND w.completed_at < $3 AND s.completed
+		GROUP BY s.member_workout_id, mg.name
+		ORDER BY s.member_workout_id, mg.name`, pq.QuoteIdentifier(gymID)), memberID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sets := []dto.MuscleSets{}
+	for rows.Next() {
+		var muscle dto.MuscleSets
+		if err := rows.Scan(&muscle.MemberWorkoutID, &muscle.MuscleGroup, &muscle.Sets); err != nil {
+			return nil, err
+		}
+		sets = append(sets, muscle)
+	}
+	return sets, rows.Err()
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCompletedSessionsTotalsCompletedSets(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewWorkoutAnalyticsRepository(db)
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 9, 14, 19, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`LEFT JOIN "gym-1".custom_member_workout_set_log s ON s.member_workout_id = w.id AND s.completed\s+WHERE w.member_id = \$1 AND w.status = 'completed'`).
+		WithArgs("member-1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "completed_at", "paused_seconds", "count", "tonnage", "rpe"}).
+			AddRow("workout-1", completedAt.Add(-time.Hour), completedAt, 300, 12, 4200.5, 7.5).
+			AddRow("workout-2", nil, completedAt.Add(48*time.Hour), 0, 0, 0, nil))
+	sessions, err := repo.FindCompletedSessions("gym-1", "member-1", from, to)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, 12, sessions[0].Sets)
+	assert.Equal(t, 7.5, *sessions[0].AverageRPE)
+	assert.Nil(t, sessions[1].StartedAt)
+	assert.Nil(t, sessions[1].AverageRPE)
+
+	mock.ExpectQuery(`SELECT 'gym', custom_exercise_id, muscular_group_id, activation_weight\s+FROM "gym-1".custom_exercise_muscular_group`).
+		WithArgs("member-1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"member_workout_id", "name", "sum"}).
+			AddRow("workout-1", "Chest", 4.0).
+			AddRow("workout-1", "Triceps", 1.5))
+	muscles, err := repo.FindMuscleSets("gym-1", "member-1", from, to)
+	require.NoError(t, err)
+	require.Len(t, muscles, 2)
+	assert.Equal(t, 1.5, muscles[1].Sets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
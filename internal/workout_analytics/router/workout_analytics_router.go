@@ -0,0 +1,15 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+// NewWorkoutAnalyticsRouter is mounted under /user/{id}/analytics
+func NewWorkoutAnalyticsRouter(handler interfaces.WorkoutAnalyticsHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", handler.GetMemberAnalytics) // GET /user/{id}/analytics?from=&to=&bucket=week|month
+	return r
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/enum"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// Without a start date series cover defaultWeeks weeks or defaultMonths months up to the end date
+	defaultWeeks  = 12
+	defaultMonths = 6
+	// maxRangeDays caps the span of a request
+	maxRangeDays = 731
+	// acuteDays and chronicDays are the windows of the acute:chronic workload ratio
+	acuteDays   = 7
+	chronicDays = 28
+)
+
+type WorkoutAnalyticsService struct {
+	repository interfaces.WorkoutAnalyticsRepository
+	gyms       gymIF.GymRepository
+	now        func() time.Time
+}
+
+func NewWorkoutAnalyticsService(repo interfaces.WorkoutAnalyticsRepository, gyms gymIF.GymRepository) *WorkoutAnalyticsService {
+	return &WorkoutAnalyticsService{repository: repo, gyms: gyms, now: time.Now}
+}
+
+// GetMemberAnalytics buckets the member's completed workouts by the day they were completed in the gym's timezone
+func (s *WorkoutAnalyticsService) GetMemberAnalytics(gymID, memberID string, query dto.AnalyticsQuery) (*dto.AnalyticsDTO, error) {
+	bucket := enum.Week
+	if query.Bucket != "" {
+		bucket = enum.Bucket(query.Bucket)
+	}
+	if !bucket.IsValid() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Bucket must be week or month", nil)
+	}
+
+	gym, err := s.gyms.GetGymByID(gymID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym", err)
+	}
+	timezone := gym.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Invalid gym timezone", err)
+	}
+
+	now := s.now().In(loc)
+	to := civilDate(now)
+	if query.To != "" {
+		if to, err = time.Parse(dateLayout, query.To); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "To must be a date in YYYY-MM-DD format", err)
+		}
+	}
+	from := defaultFrom(to, bucket)
+	if query.From != "" {
+		if from, err = time.Parse(dateLayout, query.From); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "From must be a date in YYYY-MM-DD format", err)
+		}
+	}
+	if from.After(to) {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "From must not be after to", nil)
+	}
+	if to.Sub(from).Hours()/24 >= maxRangeDays {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "The date range can span at most two years", nil)
+	}
+
+	// Sessions from before the range feed the chronic load of the first buckets
+	start := inLocation(from.AddDate(0, 0, 1-chronicDays), loc)
+	end := inLocation(to.AddDate(0, 0, 1), loc)
+	sessions, err := s.repository.FindCompletedSessions(gymID, memberID, start, end)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get completed workouts", err)
+	}
+	muscles, err := s.repository.FindMuscleSets(gymID, memberID, start, end)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get muscle group sets", err)
+	}
+	byWorkout := make(map[string]*dto.Session, len(sessions))
+	for _, session := range sessions {
+		byWorkout[session.MemberWorkoutID] = session
+	}
+	for _, muscle := range muscles {
+		if session, ok := byWorkout[muscle.MemberWorkoutID]; ok {
+			if session.MuscleSets == nil {
+				session.MuscleSets = map[string]float64{}
+			}
+			session.MuscleSets[muscle.MuscleGroup] += muscle.Sets
+		}
+	}
+
+	return &dto.AnalyticsDTO{
+		MemberID: memberID,
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Bucket:   string(bucket),
+		Timezone: timezone,
+		Series:   BuildSeries(sessions, from, to, bucket, loc),
+	}, nil
+}
+
+// BuildSeries buckets the sessions completed between the dates, both inclusive, and computes the workload
+// ratio at the end of each bucket from every session given, including those before from
+func BuildSeries(sessions []*dto.Session, from, to time.Time, bucket enum.Bucket, loc *time.Location) []*dto.BucketDTO {
+	dailyLoad := map[time.Time]float64{}
+	series := []*dto.BucketDTO{}
+	for start := from; !start.After(to); {
+		next := nextBucket(start, bucket)
+		end := next.AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		series = append(series, &dto.BucketDTO{
+			Start:                    start.Format(dateLayout),
+			End:                      end.Format(dateLayout),
+			SetsPerMuscleGroup:       map[string]float64{},
+			WeeklySetsPerMuscleGroup: map[string]float64{},
+		})
+		start = next
+	}
+
+	trainingDays := map[string]map[time.Time]bool{}
+	for _, session := range sessions {
+		day := civilDate(session.CompletedAt.In(loc))
+		load, rated := sessionLoad(session)
+		dailyLoad[day] += load
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		b := series[0]
+		for _, candidate := range series {
+			if candidate.Start <= day.Format(dateLayout) {
+				b = candidate
+			}
+		}
+		b.Sessions++
+		if trainingDays[b.Start] == nil {
+			trainingDays[b.Start] = map[time.Time]bool{}
+		}
+		trainingDays[b.Start][day] = true
+		b.Sets += session.Sets
+		b.TonnageKg += session.TonnageKg
+		b.SessionRPELoad += load
+		if !rated {
+			b.UnratedSessions++
+		}
+		for muscle, sets := range session.MuscleSets {
+			b.SetsPerMuscleGroup[muscle] += sets
+		}
+	}
+
+	for _, b := range series {
+		start, _ := time.Parse(dateLayout, b.Start)
+		end, _ := time.Parse(dateLayout, b.End)
+		weeks := (end.Sub(start).Hours()/24 + 1) / 7
+		b.TrainingDays = len(trainingDays[b.Start])
+		b.SessionsPerWeek = round(float64(b.Sessions) / weeks)
+		for muscle, sets := range b.SetsPerMuscleGroup {
+			b.SetsPerMuscleGroup[muscle] = round(sets)
+			b.WeeklySetsPerMuscleGroup[muscle] = round(sets / weeks)
+		}
+		b.TonnageKg = round(b.TonnageKg)
+		b.SessionRPELoad = round(b.SessionRPELoad)
+
+		var acute, chronic float64
+		for i := 0; i < chronicDays; i++ {
+			load := dailyLoad[end.AddDate(0, 0, -i)]
+			chronic += load
+			if i < acuteDays {
+				acute += load
+			}
+		}
+		chronic /= chronicDays / acuteDays
+		b.AcuteLoad, b.ChronicLoad = round(acute), round(chronic)
+		if chronic > 0 {
+			ratio := round(acute / chronic)
+			b.ACWR = &ratio
+		}
+	}
+	return series
+}
+
+// sessionLoad is the session RPE load of a workout: its average set RPE times the minutes it lasted, pauses
+// excluded. Sessions without an RPE or a start time give no load and are reported as unrated.
+func sessionLoad(session *dto.Session) (float64, bool) {
+	if session.AverageRPE == nil || session.StartedAt == nil {
+		return 0, false
+	}
+	minutes := (session.CompletedAt.Sub(*session.StartedAt).Seconds() - float64(session.PausedSeconds)) / 60
+	if minutes <= 0 {
+		return 0, false
+	}
+	return *session.AverageRPE * minutes, true
+}
+
+// defaultFrom starts the default range on a bucket boundary, so its first bucket is whole
+func defaultFrom(to time.Time, bucket enum.Bucket) time.Time {
+	if bucket == enum.Month {
+		return time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-defaultMonths, 0)
+	}
+	return weekStart(to).AddDate(0, 0, -7*(defaultWeeks-1))
+}
+
+// nextBucket is the first day of the bucket after the one holding day
+func nextBucket(day time.Time, bucket enum.Bucket) time.Time {
+	if bucket == enum.Month {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	}
+	return weekStart(day).AddDate(0, 0, 7)
+}
+
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// civilDate keeps the calendar day of a time, as a UTC midnight so days can be compared and added safely
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// inLocation is the midnight a calendar day starts at in loc
+func inLocation(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	gymDTO "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_analytics/dto"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	sessions []*dto.Session
+	muscles  []dto.MuscleSets
+	from, to time.Time
+}
+
+func (m *mockRepo) FindCompletedSessions(gymID, memberID string, from, to time.Time) ([]*dto.Session, error) {
+	m.from, m.to = from, to
+	return m.sessions, nil
+}
+func (m *mockRepo) FindMuscleSets(gymID, memberID string, from, to time.Time) ([]dto.MuscleSets, error) {
+	return m.muscles, nil
+}
+
+type mockGyms struct {
+	gymIF.GymRepository
+}
+
+func (m *mockGyms) GetGymByID(id string) (*gymDTO.GymResponseDTO, error) {
+	return &gymDTO.GymResponseDTO{ID: id, Timezone: "Europe/Madrid"}, nil
+}
+
+func newService(repo *mockRepo) *WorkoutAnalyticsService {
+	s := NewWorkoutAnalyticsService(repo, &mockGyms{})
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func session(id string, completedAt time.Time, minutes, pausedSeconds int, rpe *float64, sets int, tonnage float64) *dto.Session {
+	return &dto.Session{
+		MemberWorkoutID: id,
+		StartedAt:       ptr(completedAt.Add(-time.Duration(minutes) * time.Minute)),
+		CompletedAt:     completedAt,
+		PausedSeconds:   pausedSeconds,
+		Sets:            sets,
+		TonnageKg:       tonnage,
+		AverageRPE:      rpe,
+	}
+}
+
+func TestWeeklySeriesWithWorkloadRatio(t *testing.T) {
+	repo := &mockRepo{
+		sessions: []*dto.Session{
+			// Before the range: only counts towards chronic load
+			session("before", time.Date(2026, 9, 20, 19, 0, 0, 0, time.UTC), 60, 0, ptr(8.0), 12, 5000),
+			// 60 minutes with a 10 minute pause at RPE 7
+			session("w1", time.Date(2026, 9, 29, 17, 0, 0, 0, time.UTC), 60, 600, ptr(7.0), 10, 3000),
+			// Sunday night in UTC is already Monday in Madrid, and has no RPE
+			session("w2-unrated", time.Date(2026, 10, 4, 22, 30, 0, 0, time.UTC), 45, 0, nil, 8, 2000),
+			session("w2", time.Date(2026, 10, 6, 18, 0, 0, 0, time.UTC), 60, 0, ptr(6.0), 6, 1500),
+		},
+		muscles: []dto.MuscleSets{
+			{MemberWorkoutID: "w1", MuscleGroup: "Chest", Sets: 4},
+			{MemberWorkoutID: "w1", MuscleGroup: "Triceps", Sets: 2},
+			{MemberWorkoutID: "w2-unrated", MuscleGroup: "Chest", Sets: 3},
+		},
+	}
+	analytics, err := newService(repo).GetMemberAnalytics("gym-1", "member-1", dto.AnalyticsQuery{From: "2026-09-28", To: "2026-10-18"})
+	require.NoError(t, err)
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, madrid), repo.from)
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, madrid), repo.to)
+	assert.Equal(t, "week", analytics.Bucket)
+	assert.Equal(t, "Europe/Madrid", analytics.Timezone)
+	require.Len(t, analytics.Series, 3)
+
+	first := analytics.Series[0]
+	assert.Equal(t, "2026-09-28", first.Start)
+	assert.Equal(t, "2026-10-04", first.End)
+	assert.Equal(t, 1, first.Sessions)
+	assert.Equal(t, 10, first.Sets)
+	assert.Equal(t, 3000.0, first.TonnageKg)
+	assert.Equal(t, 350.0, first.SessionRPELoad)
+	assert.Equal(t, map[string]float64{"Chest": 4, "Triceps": 2}, first.SetsPerMuscleGroup)
+	assert.Equal(t, 350.0, first.AcuteLoad)
+	assert.Equal(t, 207.5, first.ChronicLoad) // (480 + 350) / 4
+	assert.Equal(t, 1.69, *first.ACWR)
+
+	second := analytics.Series[1]
+	assert.Equal(t, 2, second.Sessions)
+	assert.Equal(t, 2, second.TrainingDays)
+	assert.Equal(t, 1, second.UnratedSessions)
+	assert.Equal(t, 360.0, second.SessionRPELoad)
+	assert.Equal(t, map[string]float64{"Chest": 3}, second.WeeklySetsPerMuscleGroup)
+	assert.Equal(t, 1.21, *second.ACWR) // 360 / ((480 + 350 + 360) / 4)
+
+	// A week without training still has chronic load, so its ratio drops to zero
+	third := analytics.Series[2]
+	assert.Equal(t, 0, third.Sessions)
+	assert.Equal(t, 177.5, third.ChronicLoad)
+	assert.Equal(t, 0.0, *third.ACWR)
+}
+
+func TestMonthlySeriesDefaultsToTheLastSixMonths(t *testing.T) {
+	repo := &mockRepo{sessions: []*dto.Session{
+		session("oct", time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC), 60, 0, nil, 4, 800),
+	}}
+	analytics, err := newService(repo).GetMemberAnalytics("gym-1", "member-1", dto.AnalyticsQuery{Bucket: "month"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-05-01", analytics.From)
+	assert.Equal(t, "2026-10-19", analytics.To)
+	require.Len(t, analytics.Series, 6)
+	assert.Equal(t, "2026-05-31", analytics.Series[0].End)
+	last := analytics.Series[5]
+	assert.Equal(t, "2026-10-01", last.Start)
+	assert.Equal(t, "2026-10-19", last.End) // clipped to the range
+	assert.Equal(t, 1, last.Sessions)
+	assert.Equal(t, 0.37, last.SessionsPerWeek) // one session over 19 days
+	assert.Nil(t, last.ACWR)
+}
+
+func TestRejectsInvalidRanges(t *testing.T) {
+	s := newService(&mockRepo{})
+	for _, query := range []dto.AnalyticsQuery{
+		{Bucket: "day"},
+		{From: "2026-10-19", To: "2026-10-01"},
+		{From: "19/10/2026"},
+		{From: "2024-01-01", To: "2026-10-01"},
+	} {
+		_, err := s.GetMemberAnalytics("gym-1", "member-1", query)
+		var apiErr *apierror.APIError
+		require.ErrorAs(t, err, &apiErr, "%+v", query)
+		assert.Equal(t, errorcode_enum.CodeBadRequest, apiErr.Code)
+	}
+}
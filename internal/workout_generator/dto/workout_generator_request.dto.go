@@ -7,4 +7,5 @@ type WorkoutGeneratorRequest struct {
 	Motivation       string   `json:"motivation"`
 	SpecialSituation string   `json:"special_situation"`
 	TemplateName     string   `json:"template_name"`
+	GymID            string   `json:"-"` // set from the auth context, scopes the equipment inventory
 }
@@ -5,6 +5,17 @@ type WorkoutGeneratorBlock struct {
 	BlockType    string   `json:"block_type"`
 	Instructions string   `json:"instructions"`
 	Exercises    []string `json:"exercises"`
+	// Groups lay out the block's supersets, giant sets and circuits; exercises outside a group are done straight
+	Groups []WorkoutGeneratorGroup `json:"groups,omitempty"`
+}
+
+// WorkoutGeneratorGroup is a set of the block's exercises done back to back for a number of rounds
+type WorkoutGeneratorGroup struct {
+	GroupID                  string   `json:"group_id"`
+	GroupType                string   `json:"group_type"` // superset, giant_set or circuit
+	Rounds                   int      `json:"rounds"`
+	RestBetweenRoundsSeconds int      `json:"rest_between_rounds_seconds"`
+	Exercises                []string `json:"exercises"`
 }
 
 type WorkoutGeneratorResponse struct {
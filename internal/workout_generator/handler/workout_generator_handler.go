@@ -8,6 +8,7 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/workout_generator/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 )
 
@@ -27,6 +28,7 @@ func (h *WorkoutGeneratorHandler) GenerateWorkout(w http.ResponseWriter, r *http
 		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "invalid request", err))
 		return
 	}
+	req.GymID = middleware.GetGymID(r)
 
 	resp, err := h.service.GenerateWorkout(req)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"os"
 
+	inventoryIF "github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
 	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
 	templateIF "github.com/alejandro-albiol/athenai/internal/template_block/interfaces"
 	userIF "github.com/alejandro-albiol/athenai/internal/user/interfaces"
@@ -19,6 +20,7 @@ func NewWorkoutGeneratorModule(
 	workoutTemplateSvc workoutTemplateIF.WorkoutTemplateService,
 	templateBlockSvc templateIF.TemplateBlockService,
 	userSvc userIF.UserService,
+	equipmentChecker inventoryIF.EquipmentAvailabilityChecker,
 ) http.Handler {
 	svc := service.NewWorkoutGeneratorService(
 		os.Getenv("LLM_ENDPOINT"),
@@ -27,6 +29,7 @@ func NewWorkoutGeneratorModule(
 		workoutTemplateSvc,
 		templateBlockSvc,
 		userSvc,
+		equipmentChecker,
 	)
 	h := handler.NewWorkoutGeneratorHandler(svc)
 	return router.NewWorkoutGeneratorRouter(h)
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"strings"
 
+	inventorydto "github.com/alejandro-albiol/athenai/internal/equipment_inventory/dto"
+	inventoryIF "github.com/alejandro-albiol/athenai/internal/equipment_inventory/interfaces"
 	exdto "github.com/alejandro-albiol/athenai/internal/exercise/dto"
 	exerciseIF "github.com/alejandro-albiol/athenai/internal/exercise/interfaces"
 	tbdto "github.com/alejandro-albiol/athenai/internal/template_block/dto"
@@ -26,6 +28,7 @@ type WorkoutGeneratorService struct {
 	TemplateService workoutTemplateIF.WorkoutTemplateService
 	BlockService    templateIF.TemplateBlockService
 	UserService     userIF.UserService
+	Equipment       inventoryIF.EquipmentAvailabilityChecker
 }
 
 func NewWorkoutGeneratorService(
@@ -34,6 +37,7 @@ func NewWorkoutGeneratorService(
 	templateService workoutTemplateIF.WorkoutTemplateService,
 	blockService templateIF.TemplateBlockService,
 	userService userIF.UserService,
+	equipment inventoryIF.EquipmentAvailabilityChecker,
 ) *WorkoutGeneratorService {
 	return &WorkoutGeneratorService{
 		LLMEndpoint:     llmEndpoint,
@@ -42,6 +46,7 @@ func NewWorkoutGeneratorService(
 		TemplateService: templateService,
 		BlockService:    blockService,
 		UserService:     userService,
+		Equipment:       equipment,
 	}
 }
 
@@ -70,10 +75,19 @@ func (s *WorkoutGeneratorService) GenerateWorkout(req *dto.WorkoutGeneratorReque
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get template blocks", err)
 	}
 
-	// 4. Build LLM prompt
-	prompt := buildPrompt(req, exercises, template, blocks)
+	// 4. Get the equipment the gym can use right now, if it keeps an inventory
+	var equipment []*inventorydto.AvailableEquipment
+	if s.Equipment != nil && req.GymID != "" {
+		equipment, err = s.Equipment.ListAvailableEquipment(req.GymID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Build LLM prompt
+	prompt := buildPrompt(req, exercises, equipment, template, blocks)
 
-	// 5. Call LLM
+	// 6. Call LLM
 	llmReq := map[string]interface{}{"prompt": prompt}
 	payload, err := json.Marshal(llmReq)
 	if err != nil {
@@ -110,7 +124,7 @@ func mergeExercises(public, tenant []*exdto.ExerciseResponseDTO) []*exdto.Exerci
 }
 
 // buildPrompt creates a rich prompt for the LLM
-func buildPrompt(req *dto.WorkoutGeneratorRequest, exercises []*exdto.ExerciseResponseDTO, template *wtdto.ResponseWorkoutTemplateDTO, blocks []*tbdto.TemplateBlockDTO) string {
+func buildPrompt(req *dto.WorkoutGeneratorRequest, exercises []*exdto.ExerciseResponseDTO, equipment []*inventorydto.AvailableEquipment, template *wtdto.ResponseWorkoutTemplateDTO, blocks []*tbdto.TemplateBlockDTO) string {
 	var sb strings.Builder
 	sb.WriteString("User Context:\n")
 	sb.WriteString(fmt.Sprintf("ID: %s\n", req.UserID))
@@ -122,6 +136,12 @@ func buildPrompt(req *dto.WorkoutGeneratorRequest, exercises []*exdto.ExerciseRe
 	for _, ex := range exercises {
 		sb.WriteString(fmt.Sprintf("- %s (%s)\n", ex.Name, ex.ExerciseType))
 	}
+	if len(equipment) > 0 {
+		sb.WriteString("\nEquipment Available Right Now (only use exercises that need this equipment or none):\n")
+		for _, eq := range equipment {
+			sb.WriteString(fmt.Sprintf("- %s (%s): %d\n", eq.EquipmentName, eq.EquipmentCategory, eq.AvailableQuantity))
+		}
+	}
 	sb.WriteString("\nWorkout Template:\n")
 	sb.WriteString(fmt.Sprintf("Name: %s\n", template.Name))
 	sb.WriteString(fmt.Sprintf("Description: %s\n", template.Description))
@@ -130,6 +150,8 @@ func buildPrompt(req *dto.WorkoutGeneratorRequest, exercises []*exdto.ExerciseRe
 	for _, block := range blocks {
 		sb.WriteString(fmt.Sprintf("- %s (%s): %d exercises\n", block.BlockName, block.BlockType, block.ExerciseCount))
 	}
+	sb.WriteString("\nExercises of a block may be grouped into a superset (2 exercises), giant set or circuit; list each group " +
+		"with its group_type, rounds, rest between rounds in seconds and the exercises it cycles through.\n")
 	sb.WriteString("\nPlease generate a workout plan for this user based on the above context, available exercises, and template structure.")
 	return sb.String()
 }
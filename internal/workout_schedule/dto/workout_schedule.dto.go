@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+)
+
+// CreateWorkoutScheduleDTO repeats a workout instance for a member following an RFC 5545 RRULE, such as
+// "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=24". Dates and times are in the gym's timezone.
+type CreateWorkoutScheduleDTO struct {
+	MemberID          string  `json:"member_id"`
+	WorkoutInstanceID string  `json:"workout_instance_id"`
+	RRule             string  `json:"rrule"`      // needs COUNT or UNTIL
+	StartDate         string  `json:"start_date"` // YYYY-MM-DD, the first occurrence when it matches the rule
+	StartTime         string  `json:"start_time"` // HH:MM
+	Notes             *string `json:"notes,omitempty"`
+	CreatedBy         string  `json:"-"`
+}
+
+// WorkoutScheduleDTO keeps the timezone it was created in, so later changes to the gym's timezone do not move its workouts
+type WorkoutScheduleDTO struct {
+	ID                string                  `json:"id"`
+	MemberID          string                  `json:"member_id"`
+	WorkoutInstanceID string                  `json:"workout_instance_id"`
+	RRule             string                  `json:"rrule"`
+	StartDate         string                  `json:"start_date"`
+	StartTime         string                  `json:"start_time"`
+	Timezone          string                  `json:"timezone"`
+	Notes             *string                 `json:"notes,omitempty"`
+	Status            string                  `json:"status"`
+	ParentScheduleID  *string                 `json:"parent_schedule_id,omitempty"` // the schedule this one continues after an "all future occurrences" edit
+	CreatedBy         string                  `json:"created_by"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+	Occurrences       []ScheduleOccurrenceDTO `json:"occurrences,omitempty"` // left out of listings
+	Exceptions        []ScheduleExceptionDTO  `json:"exceptions,omitempty"`
+}
+
+// ScheduleOccurrenceDTO is a member workout expanded from a schedule; OccurrenceDate is the rule date it was
+// expanded from and stays the same when the occurrence is moved
+type ScheduleOccurrenceDTO struct {
+	MemberWorkoutID   string     `json:"member_workout_id"`
+	OccurrenceDate    string     `json:"occurrence_date"`
+	ScheduledDate     string     `json:"scheduled_date"`
+	ScheduledAt       *time.Time `json:"scheduled_at,omitempty"`
+	WorkoutInstanceID string     `json:"workout_instance_id"`
+	Status            string     `json:"status"`
+}
+
+type ScheduleExceptionDTO struct {
+	OccurrenceDate string    `json:"occurrence_date"`
+	Kind           string    `json:"kind"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// WorkoutScheduleResultDTO is a new schedule with the member workouts expanded for it
+type WorkoutScheduleResultDTO struct {
+	ScheduleID        string                                          `json:"schedule_id"`
+	ScheduledWorkouts int                                             `json:"scheduled_workouts"`
+	Warnings          []*contraindication_dto.ContraindicationWarning `json:"warnings,omitempty"`
+}
+
+// ScheduledOccurrence is one member workout a schedule generates
+type ScheduledOccurrence struct {
+	OccurrenceDate    string
+	ScheduledDate     string
+	ScheduledAt       time.Time
+	WorkoutInstanceID string
+}
+
+// UpdateOccurrenceDTO edits a single occurrence; fields left out keep their value
+type UpdateOccurrenceDTO struct {
+	ScheduledDate     *string `json:"scheduled_date,omitempty"`
+	StartTime         *string `json:"start_time,omitempty"`
+	WorkoutInstanceID *string `json:"workout_instance_id,omitempty"`
+	UpdatedBy         string  `json:"-"`
+}
+
+// OccurrenceResultDTO is an edited occurrence with the contraindication warnings of its workout instance
+type OccurrenceResultDTO struct {
+	ScheduleOccurrenceDTO
+	Warnings []*contraindication_dto.ContraindicationWarning `json:"warnings,omitempty"`
+}
+
+// UpdateScheduleDTO edits all occurrences from FromDate on. The schedule is split: it ends the day before
+// and a new schedule continues with the changes. Fields left out keep their value.
+type UpdateScheduleDTO struct {
+	FromDate          string  `json:"from_date"` // YYYY-MM-DD
+	RRule             *string `json:"rrule,omitempty"`
+	StartTime         *string `json:"start_time,omitempty"`
+	WorkoutInstanceID *string `json:"workout_instance_id,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+	UpdatedBy         string  `json:"-"`
+}
@@ -0,0 +1,18 @@
+package enum
+
+// ScheduleStatus is whether a recurring schedule still generates workouts; a schedule split by an
+// "all future occurrences" edit stays active with its rule ending the day before the split
+type ScheduleStatus string
+
+const (
+	Active ScheduleStatus = "active"
+	Ended  ScheduleStatus = "ended"
+)
+
+// ExceptionKind is how one occurrence departs from its schedule's rule
+type ExceptionKind string
+
+const (
+	Cancelled ExceptionKind = "cancelled"
+	Modified  ExceptionKind = "modified" // moved to another date or time, or given another workout instance
+)
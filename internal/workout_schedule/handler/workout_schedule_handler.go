@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/alejandro-albiol/athenai/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type WorkoutScheduleHandler struct {
+	service interfaces.WorkoutScheduleService
+}
+
+func NewWorkoutScheduleHandler(service interfaces.WorkoutScheduleService) *WorkoutScheduleHandler {
+	return &WorkoutScheduleHandler{service: service}
+}
+
+func (h *WorkoutScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var schedule dto.CreateWorkoutScheduleDTO
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	schedule.CreatedBy = middleware.GetUserID(r)
+
+	result, err := h.service.CreateSchedule(middleware.GetGymID(r), &schedule)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to create workout schedule")
+		return
+	}
+	response.WriteAPICreated(w, "Workout schedule created successfully", result)
+}
+
+func (h *WorkoutScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymUser(w, r) {
+		return
+	}
+	schedule, err := h.service.GetSchedule(middleware.GetGymID(r), chi.URLParam(r, "scheduleID"))
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to get workout schedule")
+		return
+	}
+	if !requireSelfOrAdmin(w, r, schedule.MemberID) {
+		return
+	}
+	response.WriteAPISuccess(w, "Workout schedule retrieved successfully", schedule)
+}
+
+func (h *WorkoutScheduleHandler) ListMemberSchedules(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "memberID")
+	if !requireGymUser(w, r) || !requireSelfOrAdmin(w, r, memberID) {
+		return
+	}
+	schedules, err := h.service.ListMemberSchedules(middleware.GetGymID(r), memberID)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to list member schedules")
+		return
+	}
+	response.WriteAPISuccess(w, "Member schedules retrieved successfully", schedules)
+}
+
+func (h *WorkoutScheduleHandler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var update dto.UpdateOccurrenceDTO
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	update.UpdatedBy = middleware.GetUserID(r)
+
+	result, err := h.service.UpdateOccurrence(middleware.GetGymID(r), chi.URLParam(r, "scheduleID"), chi.URLParam(r, "date"), &update)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update occurrence")
+		return
+	}
+	response.WriteAPISuccess(w, "Occurrence updated successfully", result)
+}
+
+func (h *WorkoutScheduleHandler) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.CancelOccurrence(middleware.GetGymID(r), chi.URLParam(r, "scheduleID"), chi.URLParam(r, "date"), middleware.GetUserID(r)); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to cancel occurrence")
+		return
+	}
+	response.WriteAPISuccess(w, "Occurrence cancelled successfully", nil)
+}
+
+func (h *WorkoutScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	var update dto.UpdateScheduleDTO
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Invalid request body", err))
+		return
+	}
+	update.UpdatedBy = middleware.GetUserID(r)
+
+	result, err := h.service.UpdateSchedule(middleware.GetGymID(r), chi.URLParam(r, "scheduleID"), &update)
+	if err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to update workout schedule")
+		return
+	}
+	response.WriteAPISuccess(w, "Workout schedule updated successfully", result)
+}
+
+func (h *WorkoutScheduleHandler) EndSchedule(w http.ResponseWriter, r *http.Request) {
+	if !requireGymAdmin(w, r) {
+		return
+	}
+	if err := h.service.EndSchedule(middleware.GetGymID(r), chi.URLParam(r, "scheduleID"), middleware.GetUserID(r)); err != nil {
+		response.WriteAPIErrorOr(w, err, "Failed to end workout schedule")
+		return
+	}
+	response.WriteAPISuccess(w, "Workout schedule ended successfully", nil)
+}
+
+// requireGymUser writes a 400 unless the request is scoped to a gym
+func requireGymUser(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeBadRequest, "Workout schedules belong to a gym", nil))
+	return false
+}
+
+// requireGymAdmin writes a 403 unless the caller administers the gym that runs the schedules
+func requireGymAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.IsGymAdmin(r) && middleware.GetGymID(r) != "" {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Only gym administrators can manage workout schedules", nil))
+	return false
+}
+
+// requireSelfOrAdmin writes a 403 unless members follow their own schedules
+func requireSelfOrAdmin(w http.ResponseWriter, r *http.Request, memberID string) bool {
+	if middleware.IsGymAdmin(r) || middleware.GetUserID(r) == memberID {
+		return true
+	}
+	response.WriteAPIError(w, apierror.New(errorcode_enum.CodeForbidden, "Access denied: Members can only follow their own schedules", nil))
+	return false
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/router"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	interfaces.WorkoutScheduleService
+	created        *dto.CreateWorkoutScheduleDTO
+	occurrenceDate string
+	occurrence     *dto.UpdateOccurrenceDTO
+	series         *dto.UpdateScheduleDTO
+	memberID       string
+}
+
+func (m *mockService) CreateSchedule(gymID string, schedule *dto.CreateWorkoutScheduleDTO) (*dto.WorkoutScheduleResultDTO, error) {
+	m.created = schedule
+	return &dto.WorkoutScheduleResultDTO{ScheduleID: "schedule-1", ScheduledWorkouts: 12}, nil
+}
+func (m *mockService) GetSchedule(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error) {
+	return &dto.WorkoutScheduleDTO{ID: scheduleID, MemberID: "member-1"}, nil
+}
+func (m *mockService) ListMemberSchedules(gymID, memberID string) ([]*dto.WorkoutScheduleDTO, error) {
+	m.memberID = memberID
+	return []*dto.WorkoutScheduleDTO{}, nil
+}
+func (m *mockService) UpdateOccurrence(gymID, scheduleID, occurrenceDate string, update *dto.UpdateOccurrenceDTO) (*dto.OccurrenceResultDTO, error) {
+	m.occurrenceDate, m.occurrence = occurrenceDate, update
+	return &dto.OccurrenceResultDTO{}, nil
+}
+func (m *mockService) UpdateSchedule(gymID, scheduleID string, update *dto.UpdateScheduleDTO) (*dto.WorkoutScheduleResultDTO, error) {
+	m.series = update
+	return &dto.WorkoutScheduleResultDTO{ScheduleID: "schedule-2"}, nil
+}
+
+func serve(svc *mockService, method, target, body, userID, role string) *httptest.ResponseRecorder {
+	return testutil.Serve(router.NewWorkoutScheduleRouter(NewWorkoutScheduleHandler(svc)),
+		testutil.Caller{Role: role, UserID: userID, GymID: "gym-1"}, method, target, body)
+}
+
+func TestCreateScheduleRequiresGymAdmin(t *testing.T) {
+	svc := &mockService{}
+	body := `{"member_id":"member-1","workout_instance_id":"instance-1","rrule":"FREQ=WEEKLY;BYDAY=MO,TH;COUNT=12","start_date":"2026-10-19","start_time":"07:30"}`
+
+	w := serve(svc, http.MethodPost, "/", body, "member-1", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, svc.created)
+
+	w = serve(svc, http.MethodPost, "/", body, "coach-1", "admin")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "coach-1", svc.created.CreatedBy)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=12", svc.created.RRule)
+	assert.Contains(t, w.Body.String(), `"scheduled_workouts":12`)
+}
+
+func TestEditThisOccurrenceOrAllFuture(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodPut, "/schedule-1/occurrences/2026-10-22", `{"start_time":"18:00"}`, "coach-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2026-10-22", svc.occurrenceDate)
+	assert.Equal(t, "18:00", *svc.occurrence.StartTime)
+	assert.Equal(t, "coach-1", svc.occurrence.UpdatedBy)
+
+	w = serve(svc, http.MethodPut, "/schedule-1", `{"from_date":"2026-10-24","rrule":"FREQ=WEEKLY;BYDAY=SA;COUNT=4"}`, "coach-1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2026-10-24", svc.series.FromDate)
+	assert.Contains(t, w.Body.String(), `"schedule_id":"schedule-2"`)
+
+	w = serve(svc, http.MethodPut, "/schedule-1", `{`, "coach-1", "admin")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMembersFollowOnlyTheirOwnSchedules(t *testing.T) {
+	svc := &mockService{}
+
+	w := serve(svc, http.MethodGet, "/member/member-1", "", "member-2", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(svc, http.MethodGet, "/member/member-1", "", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "member-1", svc.memberID)
+
+	w = serve(svc, http.MethodGet, "/schedule-1", "", "member-2", "member")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(svc, http.MethodGet, "/schedule-1", "", "member-1", "member")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "net/http"
+
+type WorkoutScheduleHandler interface {
+	CreateSchedule(w http.ResponseWriter, r *http.Request)
+	GetSchedule(w http.ResponseWriter, r *http.Request)
+	ListMemberSchedules(w http.ResponseWriter, r *http.Request)
+	UpdateOccurrence(w http.ResponseWriter, r *http.Request)
+	CancelOccurrence(w http.ResponseWriter, r *http.Request)
+	UpdateSchedule(w http.ResponseWriter, r *http.Request)
+	EndSchedule(w http.ResponseWriter, r *http.Request)
+}
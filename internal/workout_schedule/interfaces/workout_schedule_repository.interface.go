@@ -0,0 +1,26 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+
+type WorkoutScheduleRepository interface {
+	InstanceExists(gymID, instanceID string) (bool, error)
+
+	// Create saves the schedule and its member workouts in one transaction
+	Create(gymID string, schedule *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, error)
+	FindByID(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error)
+	FindByMember(gymID, memberID string) ([]*dto.WorkoutScheduleDTO, error)
+	FindOccurrences(gymID, scheduleID string) ([]dto.ScheduleOccurrenceDTO, error)
+	FindExceptions(gymID, scheduleID string) ([]dto.ScheduleExceptionDTO, error)
+
+	// UpdateOccurrence changes a still scheduled occurrence and records it as modified; sql.ErrNoRows when it is no longer scheduled
+	UpdateOccurrence(gymID, scheduleID string, occurrence dto.ScheduledOccurrence, updatedBy string) error
+	// CancelOccurrence cancels a still scheduled occurrence with a cancel event and records the exception;
+	// sql.ErrNoRows when it is no longer scheduled
+	CancelOccurrence(gymID, scheduleID, occurrenceDate, cancelledBy string) error
+	// Split ends the schedule with truncatedRule and continues it from fromDate as a new schedule. Occurrences from
+	// fromDate that were edited or already left the scheduled status move to the new schedule with their exceptions,
+	// the others are replaced by the new occurrences. Returns the new schedule and how many workouts it scheduled.
+	Split(gymID, scheduleID, fromDate, truncatedRule string, next *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, int, error)
+	// End marks the schedule ended and cancels its occurrences still scheduled from fromDate, returning how many
+	End(gymID, scheduleID, fromDate, endedBy string) (int, error)
+}
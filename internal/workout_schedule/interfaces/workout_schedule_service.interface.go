@@ -0,0 +1,18 @@
+package interfaces
+
+import "github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+
+type WorkoutScheduleService interface {
+	// CreateSchedule expands the rule into scheduled member workouts
+	CreateSchedule(gymID string, schedule *dto.CreateWorkoutScheduleDTO) (*dto.WorkoutScheduleResultDTO, error)
+	GetSchedule(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error)
+	ListMemberSchedules(gymID, memberID string) ([]*dto.WorkoutScheduleDTO, error)
+
+	// UpdateOccurrence edits this occurrence only
+	UpdateOccurrence(gymID, scheduleID, occurrenceDate string, update *dto.UpdateOccurrenceDTO) (*dto.OccurrenceResultDTO, error)
+	CancelOccurrence(gymID, scheduleID, occurrenceDate, cancelledBy string) error
+	// UpdateSchedule edits all future occurrences from update.FromDate
+	UpdateSchedule(gymID, scheduleID string, update *dto.UpdateScheduleDTO) (*dto.WorkoutScheduleResultDTO, error)
+	// EndSchedule stops the schedule, cancelling its occurrences from today on
+	EndSchedule(gymID, scheduleID, endedBy string) error
+}
@@ -0,0 +1,26 @@
+package module
+
+import (
+	"database/sql"
+	"net/http"
+
+	contraindication_repository "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/repository"
+	contraindication_service "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/service"
+	gym_repository "github.com/alejandro-albiol/athenai/internal/gym/repository"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/handler"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/repository"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/router"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/service"
+)
+
+func NewWorkoutScheduleModule(db *sql.DB) http.Handler {
+	repo := repository.NewWorkoutScheduleRepository(db)
+	gyms := gym_repository.NewGymRepository(db)
+	checker := contraindication_service.NewContraindicationChecker(
+		contraindication_repository.NewCustomExerciseContraindicationRepository(db),
+		gyms,
+	)
+	service := service.NewWorkoutScheduleService(repo, gyms, checker)
+	handler := handler.NewWorkoutScheduleHandler(service)
+	return router.NewWorkoutScheduleRouter(handler)
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/enum"
+	"github.com/lib/pq"
+)
+
+type WorkoutScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewWorkoutScheduleRepository(db *sql.DB) *WorkoutScheduleRepository {
+	return &WorkoutScheduleRepository{db: db}
+}
+
+const dateLayout = "2006-01-02"
+
+func (r *WorkoutScheduleRepository) InstanceExists(gymID, instanceID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.custom_workout_instance WHERE id = $1)`,
+		pq.QuoteIdentifier(gymID)), instanceID).Scan(&exists)
+	return exists, err
+}
+
+func (r *WorkoutScheduleRepository) Create(gymID string, schedule *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, err := insertSchedule(tx, schema, schedule, timezone, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := insertOccurrences(tx, schema, id, schedule, occurrences); err != nil {
+		return "", err
+	}
+	return id, tx.Commit()
+}
+
+func insertSchedule(tx *sql.Tx, schema string, schedule *dto.CreateWorkoutScheduleDTO, timezone string, parentID *string) (string, error) {
+	var id string
+	err := tx.QueryRow(fmt.Sprintf(`INSERT INTO %s.workout_schedule
+		(member_id, workout_instance_id, rrule, start_date, start_time, timezone, notes, status, parent_schedule_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`, schema),
+		schedule.MemberID, schedule.WorkoutInstanceID, schedule.RRule, schedule.StartDate, schedule.StartTime, timezone,
+		schedule.Notes, enum.Active, parentID, schedule.CreatedBy).Scan(&id)
+	return id, err
+}
+
+// insertOccurrences skips rule dates the schedule already has a member workout for, returning how many it inserted
+func insertOccurrences(tx *sql.Tx, schema, scheduleID string, schedule *dto.CreateWorkoutScheduleDTO, occurrences []dto.ScheduledOccurrence) (int, error) {
+	inserted := 0
+	for _, occurrence := range occurrences {
+		result, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.custom_member_workout
+			(created_by, member_id, workout_instance_id, scheduled_date, status, schedule_id, occurrence_date, scheduled_at)
+			VALUES ($1, $2, $3, $4, 'scheduled', $5, $6, $7)
+			ON CONFLICT (schedule_id, occurrence_date) DO NOTHING`, schema),
+			schedule.CreatedBy, schedule.MemberID, occurrence.WorkoutInstanceID, occurrence.ScheduledDate, scheduleID,
+			occurrence.OccurrenceDate, occurrence.ScheduledAt)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		inserted += int(affected)
+	}
+	return inserted, nil
+}
+
+const selectSchedules = `SELECT id, member_id, workout_instance_id, rrule, start_date, to_char(start_time, 'HH24:MI'), timezone,
+	notes, status, parent_schedule_id, created_by, created_at, updated_at
+	FROM %s.workout_schedule`
+
+func scanSchedule(scanner interface{ Scan(...any) error }) (*dto.WorkoutScheduleDTO, error) {
+	var schedule dto.WorkoutScheduleDTO
+	var startDate time.Time
+	if err := scanner.Scan(&schedule.ID, &schedule.MemberID, &schedule.WorkoutInstanceID, &schedule.RRule, &startDate,
+		&schedule.StartTime, &schedule.Timezone, &schedule.Notes, &schedule.Status, &schedule.ParentScheduleID,
+		&schedule.CreatedBy, &schedule.CreatedAt, &schedule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	schedule.StartDate = startDate.Format(dateLayout)
+	return &schedule, nil
+}
+
+func (r *WorkoutScheduleRepository) FindByID(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error) {
+	return scanSchedule(r.db.QueryRow(fmt.Sprintf(selectSchedules+` WHERE id = $1`, pq.QuoteIdentifier(gymID)), scheduleID))
+}
+
+func (r *WorkoutScheduleRepository) FindByMember(gymID, memberID string) ([]*dto.WorkoutScheduleDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(selectSchedules+` WHERE member_id = $1 ORDER BY start_date DESC, created_at DESC`,
+		pq.QuoteIdentifier(gymID)), memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	schedules := []*dto.WorkoutScheduleDTO{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *WorkoutScheduleRepository) FindOccurrences(gymID, scheduleID string) ([]dto.ScheduleOccurrenceDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT id, occurrence_date, scheduled_date, scheduled_at, workout_instance_id, status
+		FROM %s.custom_member_workout WHERE schedule_id = $1 ORDER BY occurrence_date`, pq.QuoteIdentifier(gymID)), scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	occurrences := []dto.ScheduleOccurrenceDTO{}
+	for rows.Next() {
+		var occurrence dto.ScheduleOccurrenceDTO
+		var occurrenceDate, scheduledDate time.Time
+		if err := rows.Scan(&occurrence.MemberWorkoutID, &occurrenceDate, &scheduledDate, &occurrence.ScheduledAt,
+			&occurrence.WorkoutInstanceID, &occurrence.Status); err != nil {
+			return nil, err
+		}
+		occurrence.OccurrenceDate = occurrenceDate.Format(dateLayout)
+		occurrence.ScheduledDate = scheduledDate.Format(dateLayout)
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, rows.Err()
+}
+
+func (r *WorkoutScheduleRepository) FindExceptions(gymID, scheduleID string) ([]dto.ScheduleExceptionDTO, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT occurrence_date, kind, created_by, created_at
+		FROM %s.workout_schedule_exception WHERE schedule_id = $1 ORDER BY occurrence_date`, pq.QuoteIdentifier(gymID)), scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	exceptions := []dto.ScheduleExceptionDTO{}
+	for rows.Next() {
+		var exception dto.ScheduleExceptionDTO
+		var occurrenceDate time.Time
+		if err := rows.Scan(&occurrenceDate, &exception.Kind, &exception.CreatedBy, &exception.CreatedAt); err != nil {
+			return nil, err
+		}
+		exception.OccurrenceDate = occurrenceDate.Format(dateLayout)
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, rows.Err()
+}
+
+func (r *WorkoutScheduleRepository) UpdateOccurrence(gymID, scheduleID string, occurrence dto.ScheduledOccurrence, updatedBy string) error {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_member_workout
+		SET scheduled_date = $3, scheduled_at = $4, workout_instance_id = $5, updated_at = NOW()
+		WHERE schedule_id = $1 AND occurrence_date = $2 AND status = 'scheduled'`, schema),
+		scheduleID, occurrence.OccurrenceDate, occurrence.ScheduledDate, occurrence.ScheduledAt, occurrence.WorkoutInstanceID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	if err := upsertException(tx, schema, scheduleID, occurrence.OccurrenceDate, enum.Modified, updatedBy); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// eventLock is the gym's member workout event lock, taken before inserting transition events so their
+// sequences become visible in commit order
+const eventLock = `SELECT pg_advisory_xact_lock(hashtext($1 || '.custom_member_workout_event'))`
+
+func (r *WorkoutScheduleRepository) CancelOccurrence(gymID, scheduleID, occurrenceDate, cancelledBy string) error {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(eventLock, gymID); err != nil {
+		return err
+	}
+	result, err := tx.Exec(fmt.Sprintf(`WITH cancelled AS (
+			UPDATE %[1]s.custom_member_workout SET status = 'cancelled', updated_at = NOW()
+			WHERE schedule_id = $1 AND occurrence_date = $2 AND status = 'scheduled'
+			RETURNING id, member_id
+		)
+		INSERT INTO %[1]s.custom_member_workout_event (member_workout_id, member_id, transition, from_status, to_status, changed_by)
+		SELECT id, member_id, 'cancel', 'scheduled', 'cancelled', $3 FROM cancelled`, schema),
+		scheduleID, occurrenceDate, nullableUUID(cancelledBy))
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	if err := upsertException(tx, schema, scheduleID, occurrenceDate, enum.Cancelled, cancelledBy); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *WorkoutScheduleRepository) Split(gymID, scheduleID, fromDate, truncatedRule string, next *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, int, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE %s.workout_schedule SET rrule = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3`, schema), scheduleID, truncatedRule, enum.Active)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := requireAffected(result); err != nil {
+		return "", 0, err
+	}
+	id, err := insertSchedule(tx, schema, next, timezone, &scheduleID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s.workout_schedule_exception SET schedule_id = $2
+		WHERE schedule_id = $1 AND occurrence_date >= $3`, schema), scheduleID, id, fromDate); err != nil {
+		return "", 0, err
+	}
+	// Untouched occurrences are replaced; edited ones, those past scheduled and those with logged sets are kept
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %[1]s.custom_member_workout w
+		WHERE w.schedule_id = $1 AND w.occurrence_date >= $2 AND w.status = 'scheduled'
+		AND NOT EXISTS (SELECT 1 FROM %[1]s.workout_schedule_exception e WHERE e.schedule_id = $3 AND e.occurrence_date = w.occurrence_date)
+		AND NOT EXISTS (SELECT 1 FROM %[1]s.custom_member_workout_set_log l WHERE l.member_workout_id = w.id)`, schema),
+		scheduleID, fromDate, id); err != nil {
+		return "", 0, err
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s.custom_member_workout SET schedule_id = $2, updated_at = NOW()
+		WHERE schedule_id = $1 AND occurrence_date >= $3`, schema), scheduleID, id, fromDate); err != nil {
+		return "", 0, err
+	}
+
+	inserted, err := insertOccurrences(tx, schema, id, next, occurrences)
+	if err != nil {
+		return "", 0, err
+	}
+	return id, inserted, tx.Commit()
+}
+
+func (r *WorkoutScheduleRepository) End(gymID, scheduleID, fromDate, endedBy string) (int, error) {
+	schema := pq.QuoteIdentifier(gymID)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE %s.workout_schedule SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3`, schema), scheduleID, enum.Ended, enum.Active)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireAffected(result); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(eventLock, gymID); err != nil {
+		return 0, err
+	}
+	result, err = tx.Exec(fmt.Sprintf(`WITH cancelled AS (
+			UPDATE %[1]s.custom_member_workout SET status = 'cancelled', updated_at = NOW()
+			WHERE schedule_id = $1 AND scheduled_date >= $2 AND status = 'scheduled'
+			RETURNING id, member_id
+		)
+		INSERT INTO %[1]s.custom_member_workout_event (member_workout_id, member_id, transition, from_status, to_status, changed_by)
+		SELECT id, member_id, 'cancel', 'scheduled', 'cancelled', $3 FROM cancelled`, schema),
+		scheduleID, fromDate, nullableUUID(endedBy))
+	if err != nil {
+		return 0, err
+	}
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(cancelled), tx.Commit()
+}
+
+func upsertException(tx *sql.Tx, schema, scheduleID, occurrenceDate string, kind enum.ExceptionKind, createdBy string) error {
+	_, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s.workout_schedule_exception (schedule_id, occurrence_date, kind, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (schedule_id, occurrence_date) DO UPDATE SET kind = EXCLUDED.kind, created_by = EXCLUDED.created_by, created_at = NOW()`, schema),
+		scheduleID, occurrenceDate, kind, createdBy)
+	return err
+}
+
+func requireAffected(result sql.Result) error {
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func nullableUUID(id string) any {
+	if id == "" {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSchedulesOccurrences(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewWorkoutScheduleRepository(db)
+	at := time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "gym-1".workout_schedule`).
+		WithArgs("member-1", "instance-1", "FREQ=DAILY;COUNT=1", "2026-10-19", "07:30", "Europe/Madrid", nil, "active", nil, "coach-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("schedule-1"))
+	mock.ExpectExec(`INSERT INTO "gym-1".custom_member_workout .* ON CONFLICT \(schedule_id, occurrence_date\) DO NOTHING`).
+		WithArgs("coach-1", "member-1", "instance-1", "2026-10-19", "schedule-1", "2026-10-19", at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.Create("gym-1", &dto.CreateWorkoutScheduleDTO{
+		MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "FREQ=DAILY;COUNT=1",
+		StartDate: "2026-10-19", StartTime: "07:30", CreatedBy: "coach-1",
+	}, "Europe/Madrid", []dto.ScheduledOccurrence{
+		{OccurrenceDate: "2026-10-19", ScheduledDate: "2026-10-19", ScheduledAt: at, WorkoutInstanceID: "instance-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "schedule-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelOccurrenceRecordsEventAndException(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewWorkoutScheduleRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("gym-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`WITH cancelled AS \( UPDATE "gym-1".custom_member_workout .* INSERT INTO "gym-1".custom_member_workout_event`).
+		WithArgs("schedule-1", "2026-10-22", "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "gym-1".workout_schedule_exception`).
+		WithArgs("schedule-1", "2026-10-22", "cancelled", "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.CancelOccurrence("gym-1", "schedule-1", "2026-10-22", "coach-1"))
+
+	// An occurrence no longer scheduled is left alone
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("gym-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`WITH cancelled AS`).
+		WithArgs("schedule-1", "2026-10-19", "coach-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.CancelOccurrence("gym-1", "schedule-1", "2026-10-19", "coach-1"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSplitMovesEditedOccurrencesToTheNewSchedule(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewWorkoutScheduleRepository(db)
+	at := time.Date(2026, 10, 26, 17, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "gym-1".workout_schedule SET rrule = \$2`).
+		WithArgs("schedule-1", "FREQ=WEEKLY;UNTIL=20261023;BYDAY=MO,TH", "active").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "gym-1".workout_schedule`).
+		WithArgs("member-1", "instance-1", "FREQ=WEEKLY;COUNT=1;BYDAY=MO,TH", "2026-10-26", "18:00", "Europe/Madrid", nil, "active", "schedule-1", "coach-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("schedule-2"))
+	mock.ExpectExec(`UPDATE "gym-1".workout_schedule_exception SET schedule_id = \$2`).
+		WithArgs("schedule-1", "schedule-2", "2026-10-24").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "gym-1".custom_member_workout w`).
+		WithArgs("schedule-1", "2026-10-24", "schedule-2").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`UPDATE "gym-1".custom_member_workout SET schedule_id = \$2`).
+		WithArgs("schedule-1", "schedule-2", "2026-10-24").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "gym-1".custom_member_workout`).
+		WithArgs("coach-1", "member-1", "instance-1", "2026-10-26", "schedule-2", "2026-10-26", at).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	id, scheduled, err := repo.Split("gym-1", "schedule-1", "2026-10-24", "FREQ=WEEKLY;UNTIL=20261023;BYDAY=MO,TH",
+		&dto.CreateWorkoutScheduleDTO{
+			MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "FREQ=WEEKLY;COUNT=1;BYDAY=MO,TH",
+			StartDate: "2026-10-26", StartTime: "18:00", CreatedBy: "coach-1",
+		}, "Europe/Madrid", []dto.ScheduledOccurrence{
+			{OccurrenceDate: "2026-10-26", ScheduledDate: "2026-10-26", ScheduledAt: at, WorkoutInstanceID: "instance-1"},
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "schedule-2", id)
+	assert.Equal(t, 0, scheduled, "the date kept from the old schedule is not scheduled twice")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindByIDFormatsDates(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := NewWorkoutScheduleRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM "gym-1".workout_schedule WHERE id = \$1`).
+		WithArgs("schedule-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "member_id", "workout_instance_id", "rrule", "start_date", "start_time", "timezone",
+			"notes", "status", "parent_schedule_id", "created_by", "created_at", "updated_at"}).
+			AddRow("schedule-1", "member-1", "instance-1", "FREQ=DAILY;COUNT=3", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), "07:30",
+				"Europe/Madrid", nil, "active", nil, "coach-1", now, now))
+
+	schedule, err := repo.FindByID("gym-1", "schedule-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-19", schedule.StartDate)
+	assert.Equal(t, "07:30", schedule.StartTime)
+	assert.Nil(t, schedule.ParentScheduleID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/interfaces"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewWorkoutScheduleRouter(handler interfaces.WorkoutScheduleHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/", handler.CreateSchedule)                                    // POST /workout-schedule
+	r.Get("/member/{memberID}", handler.ListMemberSchedules)               // GET /workout-schedule/member/{memberID}
+	r.Get("/{scheduleID}", handler.GetSchedule)                            // GET /workout-schedule/{scheduleID}
+	r.Put("/{scheduleID}", handler.UpdateSchedule)                         // PUT /workout-schedule/{scheduleID}, all future occurrences
+	r.Delete("/{scheduleID}", handler.EndSchedule)                         // DELETE /workout-schedule/{scheduleID}
+	r.Put("/{scheduleID}/occurrences/{date}", handler.UpdateOccurrence)    // PUT /workout-schedule/{scheduleID}/occurrences/{date}, this occurrence
+	r.Delete("/{scheduleID}/occurrences/{date}", handler.CancelOccurrence) // DELETE /workout-schedule/{scheduleID}/occurrences/{date}
+	return r
+}
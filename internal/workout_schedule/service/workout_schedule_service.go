@@ -0,0 +1,431 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	contraindicationIF "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/interfaces"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/enum"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/interfaces"
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/rrule"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+	// maxOccurrences caps how many member workouts one schedule expands into, a year of daily sessions
+	maxOccurrences = 366
+)
+
+type WorkoutScheduleService struct {
+	repo    interfaces.WorkoutScheduleRepository
+	gyms    gymIF.GymRepository
+	checker contraindicationIF.ContraindicationChecker
+	now     func() time.Time
+}
+
+func NewWorkoutScheduleService(
+	repo interfaces.WorkoutScheduleRepository,
+	gyms gymIF.GymRepository,
+	checker contraindicationIF.ContraindicationChecker,
+) *WorkoutScheduleService {
+	return &WorkoutScheduleService{repo: repo, gyms: gyms, checker: checker, now: time.Now}
+}
+
+// CreateSchedule expands the rule from the start date and time in the gym's timezone and schedules a member
+// workout for every occurrence. The rule must end, by COUNT or UNTIL, within maxOccurrences.
+func (s *WorkoutScheduleService) CreateSchedule(gymID string, schedule *dto.CreateWorkoutScheduleDTO) (*dto.WorkoutScheduleResultDTO, error) {
+	if schedule.MemberID == "" || schedule.WorkoutInstanceID == "" {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "member_id and workout_instance_id are required", nil)
+	}
+	startDate, err := time.Parse(dateLayout, schedule.StartDate)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "start_date must be a date in YYYY-MM-DD format", err)
+	}
+	startTime, err := parseTime(schedule.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := parseRule(schedule.RRule)
+	if err != nil {
+		return nil, err
+	}
+	timezone, loc, err := s.gymLocation(gymID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireInstance(gymID, schedule.WorkoutInstanceID); err != nil {
+		return nil, err
+	}
+
+	occurrences, err := expand(rule, at(startDate, startTime, loc), schedule.WorkoutInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.check(gymID, schedule.MemberID, schedule.WorkoutInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.RRule = rule.String()
+	schedule.StartTime = startTime.Format(timeLayout)
+	id, err := s.repo.Create(gymID, schedule, timezone, occurrences)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to create workout schedule", err)
+	}
+	return &dto.WorkoutScheduleResultDTO{ScheduleID: id, ScheduledWorkouts: len(occurrences), Warnings: warnings}, nil
+}
+
+func (s *WorkoutScheduleService) GetSchedule(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error) {
+	schedule, err := s.findSchedule(gymID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Occurrences, err = s.repo.FindOccurrences(gymID, scheduleID); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get schedule occurrences", err)
+	}
+	if schedule.Exceptions, err = s.repo.FindExceptions(gymID, scheduleID); err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get schedule exceptions", err)
+	}
+	return schedule, nil
+}
+
+func (s *WorkoutScheduleService) ListMemberSchedules(gymID, memberID string) ([]*dto.WorkoutScheduleDTO, error) {
+	schedules, err := s.repo.FindByMember(gymID, memberID)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to list member schedules", err)
+	}
+	return schedules, nil
+}
+
+// UpdateOccurrence moves one occurrence to another date or time, or gives it another workout instance.
+// The occurrence keeps its rule date, so later edits of the series leave it alone.
+func (s *WorkoutScheduleService) UpdateOccurrence(gymID, scheduleID, occurrenceDate string, update *dto.UpdateOccurrenceDTO) (*dto.OccurrenceResultDTO, error) {
+	if update.ScheduledDate == nil && update.StartTime == nil && update.WorkoutInstanceID == nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "scheduled_date, start_time or workout_instance_id is required", nil)
+	}
+	schedule, occurrence, err := s.findScheduledOccurrence(gymID, scheduleID, occurrenceDate)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load schedule timezone", err)
+	}
+
+	date, err := time.Parse(dateLayout, occurrence.ScheduledDate)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read occurrence date", err)
+	}
+	if update.ScheduledDate != nil {
+		if date, err = time.Parse(dateLayout, *update.ScheduledDate); err != nil {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, "scheduled_date must be a date in YYYY-MM-DD format", err)
+		}
+	}
+	startTime, err := parseTime(schedule.StartTime)
+	if occurrence.ScheduledAt != nil {
+		startTime, err = parseTime(occurrence.ScheduledAt.In(loc).Format(timeLayout))
+	}
+	if update.StartTime != nil {
+		startTime, err = parseTime(*update.StartTime)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.OccurrenceResultDTO{ScheduleOccurrenceDTO: *occurrence}
+	if update.WorkoutInstanceID != nil && *update.WorkoutInstanceID != occurrence.WorkoutInstanceID {
+		if err := s.requireInstance(gymID, *update.WorkoutInstanceID); err != nil {
+			return nil, err
+		}
+		if result.Warnings, err = s.check(gymID, schedule.MemberID, *update.WorkoutInstanceID); err != nil {
+			return nil, err
+		}
+		result.WorkoutInstanceID = *update.WorkoutInstanceID
+	}
+	scheduledAt := at(date, startTime, loc)
+	result.ScheduledDate = date.Format(dateLayout)
+	result.ScheduledAt = &scheduledAt
+
+	if err := s.repo.UpdateOccurrence(gymID, scheduleID, dto.ScheduledOccurrence{
+		OccurrenceDate:    occurrence.OccurrenceDate,
+		ScheduledDate:     result.ScheduledDate,
+		ScheduledAt:       scheduledAt,
+		WorkoutInstanceID: result.WorkoutInstanceID,
+	}, update.UpdatedBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "Only scheduled occurrences can be edited", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update occurrence", err)
+	}
+	return result, nil
+}
+
+// CancelOccurrence cancels one occurrence and records it as an exception of the schedule
+func (s *WorkoutScheduleService) CancelOccurrence(gymID, scheduleID, occurrenceDate, cancelledBy string) error {
+	if _, _, err := s.findScheduledOccurrence(gymID, scheduleID, occurrenceDate); err != nil {
+		return err
+	}
+	if err := s.repo.CancelOccurrence(gymID, scheduleID, occurrenceDate, cancelledBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeConflict, "Only scheduled occurrences can be cancelled", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to cancel occurrence", err)
+	}
+	return nil
+}
+
+// UpdateSchedule applies changes to all occurrences from update.FromDate by splitting the series: the schedule
+// ends the day before and a new one continues in the same timezone. Without a new rule the new schedule picks up
+// at the next occurrence of the old one, with what is left of its COUNT; a new rule starts at from_date.
+func (s *WorkoutScheduleService) UpdateSchedule(gymID, scheduleID string, update *dto.UpdateScheduleDTO) (*dto.WorkoutScheduleResultDTO, error) {
+	fromDate, err := time.Parse(dateLayout, update.FromDate)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "from_date must be a date in YYYY-MM-DD format", err)
+	}
+	schedule, err := s.findSchedule(gymID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != string(enum.Active) {
+		return nil, apierror.New(errorcode_enum.CodeConflict, "Workout schedule has ended", nil)
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load schedule timezone", err)
+	}
+	current, err := rrule.Parse(schedule.RRule)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to read schedule rule", err)
+	}
+
+	startTimeValue := schedule.StartTime
+	if update.StartTime != nil {
+		startTimeValue = *update.StartTime
+	}
+	startTime, err := parseTime(startTimeValue)
+	if err != nil {
+		return nil, err
+	}
+	instanceID := schedule.WorkoutInstanceID
+	if update.WorkoutInstanceID != nil && *update.WorkoutInstanceID != instanceID {
+		instanceID = *update.WorkoutInstanceID
+		if err := s.requireInstance(gymID, instanceID); err != nil {
+			return nil, err
+		}
+	}
+
+	var next *rrule.Rule
+	start := fromDate
+	if update.RRule != nil {
+		if next, err = parseRule(*update.RRule); err != nil {
+			return nil, err
+		}
+	} else if next, start, err = continueRule(current, schedule.StartDate, fromDate); err != nil {
+		return nil, err
+	}
+	occurrences, err := expand(next, at(start, startTime, loc), instanceID)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := s.check(gymID, schedule.MemberID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := schedule.Notes
+	if update.Notes != nil {
+		notes = update.Notes
+	}
+	continued := &dto.CreateWorkoutScheduleDTO{
+		MemberID:          schedule.MemberID,
+		WorkoutInstanceID: instanceID,
+		RRule:             next.String(),
+		StartDate:         start.Format(dateLayout),
+		StartTime:         startTime.Format(timeLayout),
+		Notes:             notes,
+		CreatedBy:         update.UpdatedBy,
+	}
+	truncated := current.EndingOn(fromDate.AddDate(0, 0, -1)).String()
+	id, scheduled, err := s.repo.Split(gymID, scheduleID, fromDate.Format(dateLayout), truncated, continued, schedule.Timezone, occurrences)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeConflict, "Workout schedule has ended", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update workout schedule", err)
+	}
+	return &dto.WorkoutScheduleResultDTO{ScheduleID: id, ScheduledWorkouts: scheduled, Warnings: warnings}, nil
+}
+
+// continueRule returns the rule and start date that carry a series on from fromDate: the first occurrence on or
+// after it keeps the rule's phase, and COUNT drops the occurrences already before it
+func continueRule(rule *rrule.Rule, startDate string, fromDate time.Time) (*rrule.Rule, time.Time, error) {
+	start, err := time.Parse(dateLayout, startDate)
+	if err != nil {
+		return nil, time.Time{}, apierror.New(errorcode_enum.CodeInternal, "Failed to read schedule start date", err)
+	}
+	dates, err := rule.Expand(start, maxOccurrences)
+	if err != nil {
+		return nil, time.Time{}, apierror.New(errorcode_enum.CodeInternal, "Failed to expand schedule rule", err)
+	}
+	for i, date := range dates {
+		if date.Before(fromDate) {
+			continue
+		}
+		next := *rule
+		if next.Count > 0 {
+			next.Count -= i
+		}
+		return &next, date, nil
+	}
+	return nil, time.Time{}, apierror.New(errorcode_enum.CodeBadRequest, "Workout schedule has no occurrences from from_date", nil)
+}
+
+// EndSchedule stops the schedule and cancels its occurrences still scheduled from today, in the schedule's timezone
+func (s *WorkoutScheduleService) EndSchedule(gymID, scheduleID, endedBy string) error {
+	schedule, err := s.findSchedule(gymID, scheduleID)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to load schedule timezone", err)
+	}
+	if _, err := s.repo.End(gymID, scheduleID, s.now().In(loc).Format(dateLayout), endedBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierror.New(errorcode_enum.CodeConflict, "Workout schedule has already ended", err)
+		}
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to end workout schedule", err)
+	}
+	return nil
+}
+
+func (s *WorkoutScheduleService) findSchedule(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error) {
+	schedule, err := s.repo.FindByID(gymID, scheduleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierror.New(errorcode_enum.CodeNotFound, "Workout schedule not found", err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get workout schedule", err)
+	}
+	return schedule, nil
+}
+
+// findScheduledOccurrence returns the occurrence expanded from a rule date, which must still be scheduled
+func (s *WorkoutScheduleService) findScheduledOccurrence(gymID, scheduleID, occurrenceDate string) (*dto.WorkoutScheduleDTO, *dto.ScheduleOccurrenceDTO, error) {
+	if _, err := time.Parse(dateLayout, occurrenceDate); err != nil {
+		return nil, nil, apierror.New(errorcode_enum.CodeBadRequest, "Occurrence date must be in YYYY-MM-DD format", err)
+	}
+	schedule, err := s.findSchedule(gymID, scheduleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	occurrences, err := s.repo.FindOccurrences(gymID, scheduleID)
+	if err != nil {
+		return nil, nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get schedule occurrences", err)
+	}
+	for i := range occurrences {
+		if occurrences[i].OccurrenceDate != occurrenceDate {
+			continue
+		}
+		if occurrences[i].Status != "scheduled" {
+			return nil, nil, apierror.New(errorcode_enum.CodeConflict, fmt.Sprintf("Occurrence is %s, only scheduled occurrences can change", occurrences[i].Status), nil)
+		}
+		return schedule, &occurrences[i], nil
+	}
+	return nil, nil, apierror.New(errorcode_enum.CodeNotFound, "Occurrence not found", nil)
+}
+
+// gymLocation returns the gym's timezone, UTC when it has none
+func (s *WorkoutScheduleService) gymLocation(gymID string) (string, *time.Location, error) {
+	gym, err := s.gyms.GetGymByID(gymID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, apierror.New(errorcode_enum.CodeNotFound, "Gym not found", err)
+		}
+		return "", nil, apierror.New(errorcode_enum.CodeInternal, "Failed to get gym", err)
+	}
+	timezone := gym.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", nil, apierror.New(errorcode_enum.CodeInternal, "Failed to load gym timezone", err)
+	}
+	return timezone, loc, nil
+}
+
+func (s *WorkoutScheduleService) requireInstance(gymID, instanceID string) error {
+	exists, err := s.repo.InstanceExists(gymID, instanceID)
+	if err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Failed to check workout instance", err)
+	}
+	if !exists {
+		return apierror.New(errorcode_enum.CodeBadRequest, "Workout instance not found", nil)
+	}
+	return nil
+}
+
+// check runs the contraindication checker once for the whole series
+func (s *WorkoutScheduleService) check(gymID, memberID, instanceID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	if s.checker == nil {
+		return nil, nil
+	}
+	return s.checker.CheckMemberWorkout(gymID, memberID, instanceID)
+}
+
+func parseRule(value string) (*rrule.Rule, error) {
+	rule, err := rrule.Parse(value)
+	if err != nil {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "Invalid rrule: "+err.Error(), err)
+	}
+	if !rule.IsBounded() {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "rrule needs COUNT or UNTIL", nil)
+	}
+	return rule, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return time.Time{}, apierror.New(errorcode_enum.CodeBadRequest, "start_time must be a time in HH:MM format", err)
+	}
+	return parsed, nil
+}
+
+// at combines a date and a wall-clock time in the schedule's location
+func at(date, clock time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+}
+
+func expand(rule *rrule.Rule, start time.Time, instanceID string) ([]dto.ScheduledOccurrence, error) {
+	times, err := rule.Expand(start, maxOccurrences)
+	if err != nil {
+		if errors.Is(err, rrule.ErrTooManyOccurrences) {
+			return nil, apierror.New(errorcode_enum.CodeBadRequest, fmt.Sprintf("rrule yields more than %d occurrences", maxOccurrences), err)
+		}
+		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to expand rrule", err)
+	}
+	if len(times) == 0 {
+		return nil, apierror.New(errorcode_enum.CodeBadRequest, "rrule yields no occurrences from the start date", nil)
+	}
+	occurrences := make([]dto.ScheduledOccurrence, len(times))
+	for i, occurrence := range times {
+		date := occurrence.Format(dateLayout)
+		occurrences[i] = dto.ScheduledOccurrence{
+			OccurrenceDate:    date,
+			ScheduledDate:     date,
+			ScheduledAt:       occurrence,
+			WorkoutInstanceID: instanceID,
+		}
+	}
+	return occurrences, nil
+}
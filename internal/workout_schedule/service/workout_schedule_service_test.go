@@ -0,0 +1,226 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	contraindication_dto "github.com/alejandro-albiol/athenai/internal/custom_exercise_contraindication/dto"
+	gymDTO "github.com/alejandro-albiol/athenai/internal/gym/dto"
+	gymIF "github.com/alejandro-albiol/athenai/internal/gym/interfaces"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/dto"
+	"github.com/alejandro-albiol/athenai/internal/workout_schedule/interfaces"
+	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	interfaces.WorkoutScheduleRepository
+	schedule    *dto.WorkoutScheduleDTO
+	occurrences []dto.ScheduleOccurrenceDTO
+	created     *dto.CreateWorkoutScheduleDTO
+	timezone    string
+	scheduled   []dto.ScheduledOccurrence
+	updated     *dto.ScheduledOccurrence
+	truncated   string
+	endedFrom   string
+	splitError  error
+}
+
+func (m *mockRepository) InstanceExists(gymID, instanceID string) (bool, error) {
+	return instanceID != "missing", nil
+}
+func (m *mockRepository) Create(gymID string, schedule *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, error) {
+	m.created, m.timezone, m.scheduled = schedule, timezone, occurrences
+	return "schedule-new", nil
+}
+func (m *mockRepository) FindByID(gymID, scheduleID string) (*dto.WorkoutScheduleDTO, error) {
+	if m.schedule == nil || m.schedule.ID != scheduleID {
+		return nil, sql.ErrNoRows
+	}
+	return m.schedule, nil
+}
+func (m *mockRepository) FindOccurrences(gymID, scheduleID string) ([]dto.ScheduleOccurrenceDTO, error) {
+	return m.occurrences, nil
+}
+func (m *mockRepository) UpdateOccurrence(gymID, scheduleID string, occurrence dto.ScheduledOccurrence, updatedBy string) error {
+	m.updated = &occurrence
+	return nil
+}
+func (m *mockRepository) Split(gymID, scheduleID, fromDate, truncatedRule string, next *dto.CreateWorkoutScheduleDTO, timezone string, occurrences []dto.ScheduledOccurrence) (string, int, error) {
+	if m.splitError != nil {
+		return "", 0, m.splitError
+	}
+	m.truncated, m.created, m.timezone, m.scheduled = truncatedRule, next, timezone, occurrences
+	return "schedule-next", len(occurrences), nil
+}
+func (m *mockRepository) End(gymID, scheduleID, fromDate, endedBy string) (int, error) {
+	m.endedFrom = fromDate
+	return 2, nil
+}
+
+type mockGyms struct {
+	gymIF.GymRepository
+	timezone string
+}
+
+func (m *mockGyms) GetGymByID(id string) (*gymDTO.GymResponseDTO, error) {
+	return &gymDTO.GymResponseDTO{ID: id, Timezone: m.timezone}, nil
+}
+
+type mockChecker struct {
+	warnings []*contraindication_dto.ContraindicationWarning
+	calls    int
+}
+
+func (m *mockChecker) CheckMemberWorkout(gymID, memberID, workoutInstanceID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	m.calls++
+	return m.warnings, nil
+}
+func (m *mockChecker) CheckWorkoutExercise(gymID, workoutInstanceID, exerciseSource, exerciseID string) ([]*contraindication_dto.ContraindicationWarning, error) {
+	return nil, nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func madrid(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	return loc
+}
+
+func activeSchedule() *dto.WorkoutScheduleDTO {
+	return &dto.WorkoutScheduleDTO{
+		ID: "schedule-1", MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=6",
+		StartDate: "2026-10-19", StartTime: "07:30", Timezone: "Europe/Madrid", Status: "active",
+	}
+}
+
+func TestCreateScheduleExpandsInGymTimezone(t *testing.T) {
+	repo := &mockRepository{}
+	checker := &mockChecker{warnings: []*contraindication_dto.ContraindicationWarning{{ExerciseID: "squat"}}}
+	svc := NewWorkoutScheduleService(repo, &mockGyms{timezone: "Europe/Madrid"}, checker)
+
+	result, err := svc.CreateSchedule("gym-1", &dto.CreateWorkoutScheduleDTO{
+		MemberID: "member-1", WorkoutInstanceID: "instance-1", RRule: "rrule:freq=weekly;byday=mo,th;count=4",
+		StartDate: "2026-10-19", StartTime: "7:30",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "schedule-new", result.ScheduleID)
+	assert.Equal(t, 4, result.ScheduledWorkouts)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, 1, checker.calls, "the instance is checked once for the whole series")
+
+	assert.Equal(t, "Europe/Madrid", repo.timezone)
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=4;BYDAY=MO,TH", repo.created.RRule)
+	assert.Equal(t, "07:30", repo.created.StartTime)
+	if assert.Len(t, repo.scheduled, 4) {
+		assert.Equal(t, "2026-10-22", repo.scheduled[1].OccurrenceDate)
+		// Summer time ends on 2026-10-25: the wall-clock time stays, the UTC time moves
+		assert.Equal(t, 5, repo.scheduled[1].ScheduledAt.UTC().Hour())
+		assert.Equal(t, 6, repo.scheduled[2].ScheduledAt.UTC().Hour())
+		assert.Equal(t, 7, repo.scheduled[2].ScheduledAt.In(madrid(t)).Hour())
+	}
+}
+
+func TestCreateScheduleValidation(t *testing.T) {
+	svc := NewWorkoutScheduleService(&mockRepository{}, &mockGyms{}, nil)
+	valid := func() *dto.CreateWorkoutScheduleDTO {
+		return &dto.CreateWorkoutScheduleDTO{MemberID: "member-1", WorkoutInstanceID: "instance-1",
+			RRule: "FREQ=DAILY;COUNT=3", StartDate: "2026-10-19", StartTime: "18:00"}
+	}
+
+	cases := map[string]func(*dto.CreateWorkoutScheduleDTO){
+		"unbounded rule":   func(s *dto.CreateWorkoutScheduleDTO) { s.RRule = "FREQ=DAILY" },
+		"invalid rule":     func(s *dto.CreateWorkoutScheduleDTO) { s.RRule = "FREQ=HOURLY;COUNT=3" },
+		"too many":         func(s *dto.CreateWorkoutScheduleDTO) { s.RRule = "FREQ=DAILY;COUNT=400" },
+		"no occurrences":   func(s *dto.CreateWorkoutScheduleDTO) { s.RRule = "FREQ=DAILY;UNTIL=20261001" },
+		"invalid time":     func(s *dto.CreateWorkoutScheduleDTO) { s.StartTime = "6pm" },
+		"invalid date":     func(s *dto.CreateWorkoutScheduleDTO) { s.StartDate = "19/10/2026" },
+		"missing member":   func(s *dto.CreateWorkoutScheduleDTO) { s.MemberID = "" },
+		"missing instance": func(s *dto.CreateWorkoutScheduleDTO) { s.WorkoutInstanceID = "missing" },
+	}
+	for name, mutate := range cases {
+		schedule := valid()
+		mutate(schedule)
+		_, err := svc.CreateSchedule("gym-1", schedule)
+		testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+		assert.NotNil(t, err, name)
+	}
+
+	result, err := svc.CreateSchedule("gym-1", valid())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.ScheduledWorkouts)
+}
+
+func TestUpdateOccurrenceMovesOnlyThatOccurrence(t *testing.T) {
+	scheduledAt := time.Date(2026, 10, 22, 7, 30, 0, 0, madrid(t))
+	repo := &mockRepository{schedule: activeSchedule(), occurrences: []dto.ScheduleOccurrenceDTO{
+		{MemberWorkoutID: "workout-1", OccurrenceDate: "2026-10-19", ScheduledDate: "2026-10-19", WorkoutInstanceID: "instance-1", Status: "completed"},
+		{MemberWorkoutID: "workout-2", OccurrenceDate: "2026-10-22", ScheduledDate: "2026-10-22", ScheduledAt: &scheduledAt, WorkoutInstanceID: "instance-1", Status: "scheduled"},
+	}}
+	svc := NewWorkoutScheduleService(repo, &mockGyms{}, nil)
+
+	result, err := svc.UpdateOccurrence("gym-1", "schedule-1", "2026-10-22", &dto.UpdateOccurrenceDTO{ScheduledDate: strPtr("2026-10-23")})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-22", repo.updated.OccurrenceDate, "the rule date stays so series edits can find it")
+	assert.Equal(t, "2026-10-23", repo.updated.ScheduledDate)
+	assert.Equal(t, "07:30", repo.updated.ScheduledAt.In(madrid(t)).Format(timeLayout), "the time is kept when only the date moves")
+	assert.Equal(t, "2026-10-23", result.ScheduledDate)
+
+	_, err = svc.UpdateOccurrence("gym-1", "schedule-1", "2026-10-19", &dto.UpdateOccurrenceDTO{StartTime: strPtr("09:00")})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+	_, err = svc.UpdateOccurrence("gym-1", "schedule-1", "2026-10-20", &dto.UpdateOccurrenceDTO{StartTime: strPtr("09:00")})
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+	_, err = svc.UpdateOccurrence("gym-1", "schedule-1", "2026-10-22", &dto.UpdateOccurrenceDTO{})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+}
+
+func TestUpdateScheduleSplitsTheSeries(t *testing.T) {
+	repo := &mockRepository{schedule: activeSchedule()}
+	svc := NewWorkoutScheduleService(repo, &mockGyms{}, nil)
+
+	// Mondays and Thursdays from 2026-10-19: 19, 22, 26, 29 October, 2 and 5 November
+	result, err := svc.UpdateSchedule("gym-1", "schedule-1", &dto.UpdateScheduleDTO{FromDate: "2026-10-24", StartTime: strPtr("18:00")})
+	require.NoError(t, err)
+	assert.Equal(t, "schedule-next", result.ScheduleID)
+	assert.Equal(t, "FREQ=WEEKLY;UNTIL=20261023;BYDAY=MO,TH", repo.truncated)
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=4;BYDAY=MO,TH", repo.created.RRule, "the two occurrences already past are taken off the count")
+	assert.Equal(t, "2026-10-26", repo.created.StartDate)
+	assert.Equal(t, "Europe/Madrid", repo.timezone, "the series keeps its timezone")
+	if assert.Len(t, repo.scheduled, 4) {
+		assert.Equal(t, "2026-11-05", repo.scheduled[3].OccurrenceDate)
+		assert.Equal(t, 18, repo.scheduled[3].ScheduledAt.In(madrid(t)).Hour())
+	}
+
+	_, err = svc.UpdateSchedule("gym-1", "schedule-1", &dto.UpdateScheduleDTO{FromDate: "2026-10-24", RRule: strPtr("FREQ=WEEKLY;BYDAY=SA;COUNT=2")})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-24", repo.created.StartDate, "a new rule starts at from_date")
+	assert.Equal(t, "2026-10-31", repo.scheduled[1].OccurrenceDate)
+
+	_, err = svc.UpdateSchedule("gym-1", "schedule-1", &dto.UpdateScheduleDTO{FromDate: "2026-11-06"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeBadRequest)
+
+	repo.splitError = sql.ErrNoRows
+	_, err = svc.UpdateSchedule("gym-1", "schedule-1", &dto.UpdateScheduleDTO{FromDate: "2026-10-24"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+
+	repo.schedule.Status = "ended"
+	_, err = svc.UpdateSchedule("gym-1", "schedule-1", &dto.UpdateScheduleDTO{FromDate: "2026-10-24"})
+	testutil.AssertCode(t, err, errorcode_enum.CodeConflict)
+}
+
+func TestEndScheduleCancelsFromTodayInScheduleTimezone(t *testing.T) {
+	repo := &mockRepository{schedule: activeSchedule()}
+	svc := NewWorkoutScheduleService(repo, &mockGyms{}, nil)
+	// 23:30 UTC on the 24th is already the 25th in Madrid
+	svc.now = func() time.Time { return time.Date(2026, 10, 24, 23, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, svc.EndSchedule("gym-1", "schedule-1", "coach-1"))
+	assert.Equal(t, "2026-10-25", repo.endedFrom)
+
+	err := svc.EndSchedule("gym-1", "schedule-2", "coach-1")
+	testutil.AssertCode(t, err, errorcode_enum.CodeNotFound)
+}
@@ -9,4 +9,5 @@ type UpdateWorkoutTemplateDTO struct {
 	TargetAudience           *string  `json:"target_audience,omitempty"`
 	IsActive                 *bool    `json:"is_active"`
 	IsPublic                 *bool    `json:"is_public"`
+	UpdatedBy                string   `json:"-"` // author of the revision, set from the caller's token
 }
@@ -0,0 +1,23 @@
+package dto
+
+// WorkoutTemplateRevisionSnapshot is the editable state of a public workout template kept in its revision history.
+type WorkoutTemplateRevisionSnapshot struct {
+	Name                     string `json:"name"`
+	Description              string `json:"description"`
+	DifficultyLevel          string `json:"difficulty_level"`
+	EstimatedDurationMinutes int    `json:"estimated_duration_minutes"`
+	TargetAudience           string `json:"target_audience"`
+	IsPublic                 bool   `json:"is_public"`
+}
+
+// NewWorkoutTemplateRevisionSnapshot copies the versioned fields of a template.
+func NewWorkoutTemplateRevisionSnapshot(template *ResponseWorkoutTemplateDTO) *WorkoutTemplateRevisionSnapshot {
+	return &WorkoutTemplateRevisionSnapshot{
+		Name:                     template.Name,
+		Description:              template.Description,
+		DifficultyLevel:          template.DifficultyLevel,
+		EstimatedDurationMinutes: template.EstimatedDurationMinutes,
+		TargetAudience:           template.TargetAudience,
+		IsPublic:                 template.IsPublic,
+	}
+}
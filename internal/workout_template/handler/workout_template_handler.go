@@ -10,6 +10,7 @@ import (
 	"github.com/alejandro-albiol/athenai/internal/workout_template/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
 	errorcode_enum "github.com/alejandro-albiol/athenai/pkg/apierror/enum"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
 	"github.com/alejandro-albiol/athenai/pkg/response"
 	"github.com/go-chi/chi/v5"
 )
@@ -164,6 +165,7 @@ func (h *WorkoutTemplateHandler) UpdateWorkoutTemplate(w http.ResponseWriter, r
 		))
 		return
 	}
+	updateDTO.UpdatedBy = middleware.GetUserID(r)
 	workoutTemplate, err := h.Service.UpdateWorkoutTemplate(templateID, &updateDTO)
 	if err != nil {
 		var apiErr *apierror.APIError
@@ -1,6 +1,10 @@
 package interfaces
 
-import "github.com/alejandro-albiol/athenai/internal/workout_template/dto"
+import (
+	"database/sql"
+
+	"github.com/alejandro-albiol/athenai/internal/workout_template/dto"
+)
 
 type WorkoutTemplateRepository interface {
 	// Create inserts a new workout template into the database.
@@ -21,8 +25,8 @@ type WorkoutTemplateRepository interface {
 	// GetAll fetches all workout templates from the database.
 	GetAllWorkoutTemplates() ([]*dto.ResponseWorkoutTemplateDTO, error)
 
-	// Update modifies an existing workout template by ID.
-	UpdateWorkoutTemplate(id string, dto *dto.UpdateWorkoutTemplateDTO) (*dto.ResponseWorkoutTemplateDTO, error)
+	// Update modifies an existing workout template by ID; onUpdate, when set, runs with the updated template before committing.
+	UpdateWorkoutTemplate(id string, dto *dto.UpdateWorkoutTemplateDTO, onUpdate func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error) (*dto.ResponseWorkoutTemplateDTO, error)
 
 	// Delete removes a workout template by ID.
 	DeleteWorkoutTemplate(id string) error
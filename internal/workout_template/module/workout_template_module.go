@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"net/http"
 
+	revisionRepository "github.com/alejandro-albiol/athenai/internal/revision/repository"
+	revisionService "github.com/alejandro-albiol/athenai/internal/revision/service"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/handler"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/repository"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/router"
@@ -11,8 +13,13 @@ import (
 )
 
 func NewWorkoutTemplateModule(db *sql.DB) http.Handler {
-	repo := repository.NewWorkoutTemplateRepository(db)
-	service := service.NewWorkoutTemplateService(repo)
+	service := NewWorkoutTemplateService(db)
 	handler := handler.NewWorkoutTemplateHandler(service)
 	return router.NewWorkoutTemplateRouter(handler)
 }
+
+// NewWorkoutTemplateService builds the service with revision recording for modules that update templates
+func NewWorkoutTemplateService(db *sql.DB) *service.WorkoutTemplateService {
+	recorder := revisionService.NewRevisionService(revisionRepository.NewRevisionRepository(db))
+	return service.NewWorkoutTemplateService(repository.NewWorkoutTemplateRepository(db), recorder)
+}
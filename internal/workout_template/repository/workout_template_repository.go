@@ -29,9 +29,18 @@ func (r *WorkoutTemplateRepository) CreateWorkoutTemplate(template *dto.CreateWo
 
 // Get a workout template by its ID from the database
 func (r *WorkoutTemplateRepository) GetWorkoutTemplateByID(id string) (*dto.ResponseWorkoutTemplateDTO, error) {
+	return findWorkoutTemplateByID(r.db, id)
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func findWorkoutTemplateByID(q rowQuerier, id string) (*dto.ResponseWorkoutTemplateDTO, error) {
 	query := `SELECT id, name, description, difficulty_level, estimated_duration_minutes, target_audience, created_by, is_active, is_public, created_at, updated_at FROM public.workout_template WHERE id = $1`
 	var wt dto.ResponseWorkoutTemplateDTO
-	err := r.db.QueryRow(query, id).Scan(
+	err := q.QueryRow(query, id).Scan(
 		&wt.ID,
 		&wt.Name,
 		&wt.Description,
@@ -157,20 +166,34 @@ func (r *WorkoutTemplateRepository) GetAllWorkoutTemplates() ([]*dto.ResponseWor
 	return templates, nil
 }
 
-// Update a workout template in the database
-func (r *WorkoutTemplateRepository) UpdateWorkoutTemplate(id string, template *dto.UpdateWorkoutTemplateDTO) (*dto.ResponseWorkoutTemplateDTO, error) {
+// Update a workout template in the database. onUpdate runs in the same transaction, so when it fails the update is rolled back.
+func (r *WorkoutTemplateRepository) UpdateWorkoutTemplate(id string, template *dto.UpdateWorkoutTemplateDTO, onUpdate func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error) (*dto.ResponseWorkoutTemplateDTO, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workout template: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE public.workout_template SET name = $1, description = $2, difficulty_level = $3, estimated_duration_minutes = $4, target_audience = $5, is_active = $6, is_public = $7, updated_at = NOW() WHERE id = $8 RETURNING id`
-	_, err := r.db.Exec(query, template.Name, template.Description, template.DifficultyLevel,
+	_, err = tx.Exec(query, template.Name, template.Description, template.DifficultyLevel,
 		template.EstimatedDurationMinutes, template.TargetAudience,
 		template.IsActive, template.IsPublic, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update workout template: %w", err)
 	}
 
-	updatedWorkoutTemplate, err := r.GetWorkoutTemplateByID(id)
+	updatedWorkoutTemplate, err := findWorkoutTemplateByID(tx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve updated workout template: %w", err)
 	}
+	if onUpdate != nil {
+		if err := onUpdate(tx, updatedWorkoutTemplate); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update workout template: %w", err)
+	}
 
 	return updatedWorkoutTemplate, nil
 }
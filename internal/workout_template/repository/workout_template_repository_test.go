@@ -1,6 +1,8 @@
 package repository_test
 
 import (
+	"database/sql"
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -104,6 +106,7 @@ func TestUpdateWorkoutTemplate_Success(t *testing.T) {
 		IsPublic:                 &isPublic,
 	}
 	resp := getTestResponseWorkoutTemplate()
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE public.workout_template SET name = $1, description = $2, difficulty_level = $3, estimated_duration_minutes = $4, target_audience = $5, is_active = $6, is_public = $7, updated_at = NOW() WHERE id = $8 RETURNING id`)).
 		WithArgs(updateDTO.Name, updateDTO.Description, updateDTO.DifficultyLevel, updateDTO.EstimatedDurationMinutes, updateDTO.TargetAudience, updateDTO.IsActive, updateDTO.IsPublic, resp.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -111,10 +114,33 @@ func TestUpdateWorkoutTemplate_Success(t *testing.T) {
 		WithArgs(resp.ID).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "difficulty_level", "estimated_duration_minutes", "target_audience", "created_by", "is_active", "is_public", "created_at", "updated_at"}).
 			AddRow(resp.ID, resp.Name, resp.Description, resp.DifficultyLevel, resp.EstimatedDurationMinutes, resp.TargetAudience, resp.CreatedBy, resp.IsActive, resp.IsPublic, resp.CreatedAt, resp.UpdatedAt))
+	mock.ExpectCommit()
 
-	result, err := repo.UpdateWorkoutTemplate(resp.ID, updateDTO)
+	result, err := repo.UpdateWorkoutTemplate(resp.ID, updateDTO, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, resp.ID, result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateWorkoutTemplate_RollsBackWhenOnUpdateFails(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := repository.NewWorkoutTemplateRepository(db)
+	resp := getTestResponseWorkoutTemplate()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE public.workout_template SET`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM public.workout_template WHERE id = $1`)).
+		WithArgs(resp.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "difficulty_level", "estimated_duration_minutes", "target_audience", "created_by", "is_active", "is_public", "created_at", "updated_at"}).
+			AddRow(resp.ID, resp.Name, resp.Description, resp.DifficultyLevel, resp.EstimatedDurationMinutes, resp.TargetAudience, resp.CreatedBy, resp.IsActive, resp.IsPublic, resp.CreatedAt, resp.UpdatedAt))
+	mock.ExpectRollback()
+
+	result, err := repo.UpdateWorkoutTemplate(resp.ID, &dto.UpdateWorkoutTemplateDTO{}, func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error {
+		return errors.New("revision insert failed")
+	})
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteWorkoutTemplate_Success(t *testing.T) {
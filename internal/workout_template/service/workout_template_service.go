@@ -2,8 +2,11 @@ package service
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
+	revisionIF "github.com/alejandro-albiol/athenai/internal/revision/interfaces"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/dto"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/interfaces"
 	"github.com/alejandro-albiol/athenai/pkg/apierror"
@@ -12,11 +15,14 @@ import (
 
 type WorkoutTemplateService struct {
 	repository interfaces.WorkoutTemplateRepository
+	recorder   revisionIF.Recorder
 }
 
-func NewWorkoutTemplateService(repository interfaces.WorkoutTemplateRepository) *WorkoutTemplateService {
+// NewWorkoutTemplateService creates the service; without a recorder updates keep no revision history.
+func NewWorkoutTemplateService(repository interfaces.WorkoutTemplateRepository, recorder revisionIF.Recorder) *WorkoutTemplateService {
 	return &WorkoutTemplateService{
 		repository: repository,
+		recorder:   recorder,
 	}
 }
 
@@ -97,14 +103,51 @@ func (s *WorkoutTemplateService) GetAllWorkoutTemplates() ([]*dto.ResponseWorkou
 	return templates, nil
 }
 
-// UpdateWorkoutTemplate updates an existing workout template by ID.
+// UpdateWorkoutTemplate updates an existing workout template by ID and records the new revision in the same transaction.
 func (s *WorkoutTemplateService) UpdateWorkoutTemplate(id string, input *dto.UpdateWorkoutTemplateDTO) (*dto.ResponseWorkoutTemplateDTO, error) {
+	var record func(tx *sql.Tx, before *dto.WorkoutTemplateRevisionSnapshot, updated *dto.ResponseWorkoutTemplateDTO) error
+	if s.recorder != nil {
+		record = func(tx *sql.Tx, before *dto.WorkoutTemplateRevisionSnapshot, updated *dto.ResponseWorkoutTemplateDTO) error {
+			return s.recorder.Record(tx, revisionEnum.WorkoutTemplate, id, before, dto.NewWorkoutTemplateRevisionSnapshot(updated), input.UpdatedBy)
+		}
+	}
+	return s.applyUpdate(id, input, record)
+}
+
+// RestoreSnapshot writes a revision snapshot back to the workout template; record stores the restore in the same transaction.
+func (s *WorkoutTemplateService) RestoreSnapshot(id string, snapshot json.RawMessage, record func(tx *sql.Tx, before, after any) error) error {
+	var restored dto.WorkoutTemplateRevisionSnapshot
+	if err := json.Unmarshal(snapshot, &restored); err != nil {
+		return apierror.New(errorcode_enum.CodeInternal, "Stored workout template revision is unreadable", err)
+	}
+	_, err := s.applyUpdate(id, &dto.UpdateWorkoutTemplateDTO{
+		Name:                     &restored.Name,
+		Description:              &restored.Description,
+		DifficultyLevel:          &restored.DifficultyLevel,
+		EstimatedDurationMinutes: &restored.EstimatedDurationMinutes,
+		TargetAudience:           &restored.TargetAudience,
+		IsPublic:                 &restored.IsPublic,
+	}, func(tx *sql.Tx, before *dto.WorkoutTemplateRevisionSnapshot, updated *dto.ResponseWorkoutTemplateDTO) error {
+		return record(tx, before, dto.NewWorkoutTemplateRevisionSnapshot(updated))
+	})
+	return err
+}
+
+// applyUpdate hands record, when set, the template's snapshot from before the update along with the updated template.
+func (s *WorkoutTemplateService) applyUpdate(id string, input *dto.UpdateWorkoutTemplateDTO, record func(tx *sql.Tx, before *dto.WorkoutTemplateRevisionSnapshot, updated *dto.ResponseWorkoutTemplateDTO) error) (*dto.ResponseWorkoutTemplateDTO, error) {
 	findExistingTemplate, err := s.repository.GetWorkoutTemplateByID(id)
-	if (findExistingTemplate == nil || findExistingTemplate.ID == "") && err != nil {
+	if findExistingTemplate == nil || findExistingTemplate.ID == "" {
 		return nil, apierror.New(errorcode_enum.CodeNotFound, "Workout template not found", err)
 	}
+	before := dto.NewWorkoutTemplateRevisionSnapshot(findExistingTemplate)
 
-	template, err := s.repository.UpdateWorkoutTemplate(id, input)
+	var onUpdate func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error
+	if record != nil {
+		onUpdate = func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error {
+			return record(tx, before, updated)
+		}
+	}
+	template, err := s.repository.UpdateWorkoutTemplate(id, input, onUpdate)
 	if err != nil {
 		return nil, apierror.New(errorcode_enum.CodeInternal, "Failed to update workout template", err)
 	}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	revisionEnum "github.com/alejandro-albiol/athenai/internal/revision/enum"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/dto"
 	"github.com/alejandro-albiol/athenai/internal/workout_template/service"
 	"github.com/stretchr/testify/assert"
@@ -49,12 +50,18 @@ func (m *MockWorkoutTemplateRepository) GetAllWorkoutTemplates() ([]*dto.Respons
 	args := m.Called()
 	return args.Get(0).([]*dto.ResponseWorkoutTemplateDTO), args.Error(1)
 }
-func (m *MockWorkoutTemplateRepository) UpdateWorkoutTemplate(id string, input *dto.UpdateWorkoutTemplateDTO) (*dto.ResponseWorkoutTemplateDTO, error) {
+func (m *MockWorkoutTemplateRepository) UpdateWorkoutTemplate(id string, input *dto.UpdateWorkoutTemplateDTO, onUpdate func(tx *sql.Tx, updated *dto.ResponseWorkoutTemplateDTO) error) (*dto.ResponseWorkoutTemplateDTO, error) {
 	args := m.Called(id, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*dto.ResponseWorkoutTemplateDTO), args.Error(1)
+	updated := args.Get(0).(*dto.ResponseWorkoutTemplateDTO)
+	if onUpdate != nil {
+		if err := onUpdate(nil, updated); err != nil {
+			return nil, err
+		}
+	}
+	return updated, args.Error(1)
 }
 func (m *MockWorkoutTemplateRepository) DeleteWorkoutTemplate(id string) error {
 	args := m.Called(id)
@@ -63,7 +70,7 @@ func (m *MockWorkoutTemplateRepository) DeleteWorkoutTemplate(id string) error {
 
 func TestCreateWorkoutTemplate_Success(t *testing.T) {
 	repo := new(MockWorkoutTemplateRepository)
-	svc := service.NewWorkoutTemplateService(repo)
+	svc := service.NewWorkoutTemplateService(repo, nil)
 	createDTO := &dto.CreateWorkoutTemplateDTO{Name: "Test Template"}
 	repo.On("GetWorkoutTemplateByName", createDTO.Name).Return(nil, sql.ErrNoRows)
 	repo.On("CreateWorkoutTemplate", createDTO).Return("123", nil)
@@ -77,7 +84,7 @@ func TestCreateWorkoutTemplate_Success(t *testing.T) {
 
 func TestCreateWorkoutTemplate_Conflict(t *testing.T) {
 	repo := new(MockWorkoutTemplateRepository)
-	svc := service.NewWorkoutTemplateService(repo)
+	svc := service.NewWorkoutTemplateService(repo, nil)
 	createDTO := &dto.CreateWorkoutTemplateDTO{Name: "Test Template"}
 	repo.On("GetWorkoutTemplateByName", createDTO.Name).Return(&dto.ResponseWorkoutTemplateDTO{ID: "123"}, nil)
 
@@ -88,7 +95,7 @@ func TestCreateWorkoutTemplate_Conflict(t *testing.T) {
 
 func TestGetWorkoutTemplateByID_Success(t *testing.T) {
 	repo := new(MockWorkoutTemplateRepository)
-	svc := service.NewWorkoutTemplateService(repo)
+	svc := service.NewWorkoutTemplateService(repo, nil)
 	repo.On("GetWorkoutTemplateByID", "123").Return(&dto.ResponseWorkoutTemplateDTO{ID: "123", Name: "Test"}, nil)
 
 	template, err := svc.GetWorkoutTemplateByID("123")
@@ -98,7 +105,7 @@ func TestGetWorkoutTemplateByID_Success(t *testing.T) {
 
 func TestGetWorkoutTemplateByID_NotFound(t *testing.T) {
 	repo := new(MockWorkoutTemplateRepository)
-	svc := service.NewWorkoutTemplateService(repo)
+	svc := service.NewWorkoutTemplateService(repo, nil)
 	repo.On("GetWorkoutTemplateByID", "123").Return(nil, errors.New("not found"))
 
 	template, err := svc.GetWorkoutTemplateByID("123")
@@ -106,4 +113,44 @@ func TestGetWorkoutTemplateByID_NotFound(t *testing.T) {
 	assert.Nil(t, template)
 }
 
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) Record(tx *sql.Tx, entityType revisionEnum.EntityType, entityID string, before, after any, authorID string) error {
+	args := m.Called(entityType, entityID, before, after, authorID)
+	return args.Error(0)
+}
+
+func TestUpdateWorkoutTemplate_RecordsRevision(t *testing.T) {
+	repo := new(MockWorkoutTemplateRepository)
+	recorder := new(MockRecorder)
+	svc := service.NewWorkoutTemplateService(repo, recorder)
+	name := "Push Day"
+	input := &dto.UpdateWorkoutTemplateDTO{Name: &name, UpdatedBy: "admin-1"}
+	repo.On("GetWorkoutTemplateByID", "123").Return(&dto.ResponseWorkoutTemplateDTO{ID: "123", Name: "Push"}, nil)
+	repo.On("UpdateWorkoutTemplate", "123", input).Return(&dto.ResponseWorkoutTemplateDTO{ID: "123", Name: name}, nil)
+	recorder.On("Record", revisionEnum.WorkoutTemplate, "123",
+		&dto.WorkoutTemplateRevisionSnapshot{Name: "Push"}, &dto.WorkoutTemplateRevisionSnapshot{Name: name}, "admin-1").Return(nil)
+
+	template, err := svc.UpdateWorkoutTemplate("123", input)
+	assert.NoError(t, err)
+	assert.Equal(t, name, template.Name)
+	recorder.AssertExpectations(t)
+}
+
+func TestUpdateWorkoutTemplate_RevisionFailure(t *testing.T) {
+	repo := new(MockWorkoutTemplateRepository)
+	recorder := new(MockRecorder)
+	svc := service.NewWorkoutTemplateService(repo, recorder)
+	input := &dto.UpdateWorkoutTemplateDTO{}
+	repo.On("GetWorkoutTemplateByID", "123").Return(&dto.ResponseWorkoutTemplateDTO{ID: "123"}, nil)
+	repo.On("UpdateWorkoutTemplate", "123", input).Return(&dto.ResponseWorkoutTemplateDTO{ID: "123"}, nil)
+	recorder.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	template, err := svc.UpdateWorkoutTemplate("123", input)
+	assert.Error(t, err)
+	assert.Nil(t, template)
+}
+
 // Additional tests for update, delete, and list methods can be added similarly.
@@ -7,5 +7,7 @@ const (
 	CodeUnauthorized = "UNAUTHORIZED"
 	CodeForbidden    = "FORBIDDEN"
 	CodeInternal     = "INTERNAL_ERROR"
+	// CodeInvalidTransition is a conflict with the current state of a resource, such as completing a workout that never started
+	CodeInvalidTransition = "INVALID_TRANSITION"
 	// Add more as needed
 )
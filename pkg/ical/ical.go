@@ -0,0 +1,295 @@
+// Package ical writes RFC 5545 calendars: VEVENTs with recurrence rules, exception dates and overridden
+// occurrences. Local times carry an IANA TZID, and each TZID gets a VTIMEZONE built from the Go zone data.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+	// maxLineOctets is the longest content line before it is folded, CRLF excluded
+	maxLineOctets = 75
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR. Name and Timezone fill X-WR-CALNAME and X-WR-TIMEZONE when set.
+type Calendar struct {
+	ProdID   string
+	Name     string
+	Timezone string
+	Events   []Event
+}
+
+// Event is a VEVENT. Start sets the form of every date in the event: a date when AllDay, a UTC date-time
+// when its location is UTC, otherwise a local date-time with the location name as TZID. RecurrenceID marks
+// an override of one occurrence of the recurring event with the same UID.
+type Event struct {
+	UID          string
+	Stamp        time.Time
+	Start        time.Time
+	AllDay       bool
+	Duration     time.Duration
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Summary      string
+	Description  string
+	URL          string
+	Status       string
+}
+
+// WriteTo writes the calendar with CRLF line endings and folded lines
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + c.ProdID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + EscapeText(c.Name))
+	}
+	if c.Timezone != "" {
+		lw.line("X-WR-TIMEZONE:" + c.Timezone)
+	}
+	c.writeTimezones(lw)
+	for _, event := range c.Events {
+		event.write(lw)
+	}
+	lw.line("END:VCALENDAR")
+	return lw.n, lw.err
+}
+
+// String formats the calendar
+func (c *Calendar) String() string {
+	var b strings.Builder
+	c.WriteTo(&b)
+	return b.String()
+}
+
+func (e *Event) write(lw *lineWriter) {
+	lw.line("BEGIN:VEVENT")
+	lw.line("UID:" + e.UID)
+	lw.line("DTSTAMP:" + e.Stamp.UTC().Format(dateTimeLayout) + "Z")
+	if e.RecurrenceID != nil {
+		lw.line("RECURRENCE-ID" + e.formatTime(*e.RecurrenceID))
+	}
+	lw.line("DTSTART" + e.formatTime(e.Start))
+	if e.Duration > 0 {
+		lw.line("DURATION:" + FormatDuration(e.Duration))
+	}
+	if e.RRule != "" {
+		lw.line("RRULE:" + e.RRule)
+	}
+	for _, exDate := range e.ExDates {
+		lw.line("EXDATE" + e.formatTime(exDate))
+	}
+	if e.Summary != "" {
+		lw.line("SUMMARY:" + EscapeText(e.Summary))
+	}
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + EscapeText(e.Description))
+	}
+	if e.URL != "" {
+		lw.line("URL:" + e.URL)
+	}
+	if e.Status != "" {
+		lw.line("STATUS:" + e.Status)
+	}
+	lw.line("END:VEVENT")
+}
+
+// formatTime gives the parameters and value of a date property, from the colon on, in the form Start sets
+func (e *Event) formatTime(t time.Time) string {
+	switch {
+	case e.AllDay:
+		return ";VALUE=DATE:" + t.Format(dateLayout)
+	case e.Start.Location() == time.UTC:
+		return ":" + t.UTC().Format(dateTimeLayout) + "Z"
+	default:
+		return fmt.Sprintf(";TZID=%s:%s", e.Start.Location(), t.In(e.Start.Location()).Format(dateTimeLayout))
+	}
+}
+
+// zoneSpan is a location the events use as TZID, with the first and last time they write in it
+type zoneSpan struct {
+	loc         *time.Location
+	first, last time.Time
+}
+
+// writeTimezones writes a VTIMEZONE for every TZID of the events, in order of first use
+func (c *Calendar) writeTimezones(lw *lineWriter) {
+	var spans []*zoneSpan
+	byName := make(map[string]*zoneSpan)
+	for _, event := range c.Events {
+		loc := event.Start.Location()
+		if event.AllDay || loc == time.UTC {
+			continue
+		}
+		times := append([]time.Time{event.Start}, event.ExDates...)
+		if event.RecurrenceID != nil {
+			times = append(times, *event.RecurrenceID)
+		}
+		span, ok := byName[loc.String()]
+		if !ok {
+			span = &zoneSpan{loc: loc, first: event.Start, last: event.Start}
+			byName[loc.String()] = span
+			spans = append(spans, span)
+		}
+		for _, t := range times {
+			if t.Before(span.first) {
+				span.first = t
+			}
+			if t.After(span.last) {
+				span.last = t
+			}
+		}
+	}
+	for _, span := range spans {
+		span.write(lw)
+	}
+}
+
+// observance is the offset a zone takes from onset on
+type observance struct {
+	onset      time.Time
+	name       string
+	offsetFrom int
+	offset     int
+	dst        bool
+}
+
+// write writes the zone with every offset change from the start of the year of its first time until the end
+// of the year after its last. Recurring events can run past that, so when the zone still changes offset in its
+// last year, the last change of each kind repeats yearly on the same weekday of the month.
+func (z *zoneSpan) write(lw *lineWriter) {
+	start := time.Date(z.first.In(z.loc).Year(), time.January, 1, 0, 0, 0, 0, z.loc)
+	lastYear := z.last.In(z.loc).Year() + 1
+	end := time.Date(lastYear+1, time.January, 1, 0, 0, 0, 0, z.loc)
+
+	name, offset := start.Zone()
+	observances := []observance{{onset: start, name: name, offsetFrom: offset, offset: offset, dst: start.IsDST()}}
+	for t := start; ; {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		name, offset := next.Zone()
+		observances = append(observances, observance{onset: next, name: name, offsetFrom: observances[len(observances)-1].offset, offset: offset, dst: next.IsDST()})
+		t = next
+	}
+
+	repeatFrom := len(observances)
+	if n := len(observances); n >= 3 && observances[n-2].onset.In(z.loc).Year() == lastYear && observances[n-1].dst != observances[n-2].dst {
+		repeatFrom = n - 2
+	}
+
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + z.loc.String())
+	for i, o := range observances {
+		kind := "STANDARD"
+		if o.dst {
+			kind = "DAYLIGHT"
+		}
+		// DTSTART is the local time of the onset before the change
+		onset := o.onset.In(time.FixedZone("", o.offsetFrom))
+		lw.line("BEGIN:" + kind)
+		lw.line("DTSTART:" + onset.Format(dateTimeLayout))
+		if i >= repeatFrom {
+			lw.line("RRULE:FREQ=YEARLY;BYMONTH=" + fmt.Sprint(int(onset.Month())) + ";BYDAY=" + weekdayOfMonth(onset))
+		}
+		lw.line("TZOFFSETFROM:" + formatOffset(o.offsetFrom))
+		lw.line("TZOFFSETTO:" + formatOffset(o.offset))
+		lw.line("TZNAME:" + o.name)
+		lw.line("END:" + kind)
+	}
+	lw.line("END:VTIMEZONE")
+}
+
+var weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// weekdayOfMonth gives the BYDAY of a date as the nth weekday of its month, or -1 for the last one
+func weekdayOfMonth(t time.Time) string {
+	n := (t.Day()-1)/7 + 1
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		n = -1
+	}
+	return fmt.Sprintf("%d%s", n, weekdays[t.Weekday()])
+}
+
+// formatOffset formats a UTC offset in seconds as a UTC-OFFSET value, such as +0130
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	out := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if s := seconds % 60; s > 0 {
+		out += fmt.Sprintf("%02d", s)
+	}
+	return out
+}
+
+// FormatDuration formats a positive duration as an RFC 5545 DURATION value, such as PT1H30M
+func FormatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := seconds / 3600; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := seconds % 3600 / 60; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := seconds % 60; s > 0 || seconds == 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// EscapeText escapes a TEXT value: backslashes, semicolons, commas and newlines
+func EscapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// lineWriter writes content lines, folding them at 75 octets without splitting UTF-8 characters
+type lineWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (lw *lineWriter) line(content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		lw.write(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Continuation lines start with the folding space
+		limit = maxLineOctets - 1
+	}
+	lw.write(content + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err != nil {
+		return
+	}
+	n, err := io.WriteString(lw.w, s)
+	lw.n += int64(n)
+	lw.err = err
+}
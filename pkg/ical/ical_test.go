@@ -0,0 +1,98 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/pkg/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringEventWithExceptions(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	start := time.Date(2026, 10, 19, 7, 30, 0, 0, madrid)
+	moved := time.Date(2026, 10, 22, 7, 30, 0, 0, madrid)
+
+	cal := ical.Calendar{ProdID: "-//AthenAI//Workouts//EN", Name: "Workouts, Alice", Timezone: "Europe/Madrid", Events: []ical.Event{
+		{
+			UID: "schedule-1@athenai", Stamp: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), Start: start, Duration: 45 * time.Minute,
+			RRule: "FREQ=WEEKLY;COUNT=12;BYDAY=MO,TH", ExDates: []time.Time{time.Date(2026, 10, 26, 7, 30, 0, 0, madrid)},
+			Summary: "Upper body; push", Status: ical.StatusConfirmed,
+		},
+		{
+			UID: "schedule-1@athenai", Stamp: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), RecurrenceID: &moved,
+			Start: time.Date(2026, 10, 22, 18, 0, 0, 0, madrid), Duration: time.Hour, Description: "Status: completed\nBlocks: Main",
+		},
+	}}
+
+	out := cal.String()
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "X-WR-CALNAME:Workouts\\, Alice\r\n")
+	assert.Contains(t, out, "DTSTAMP:20261001T090000Z\r\n")
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Madrid:20261019T073000\r\nDURATION:PT45M\r\nRRULE:FREQ=WEEKLY;COUNT=12;BYDAY=MO,TH\r\n")
+	assert.Contains(t, out, "EXDATE;TZID=Europe/Madrid:20261026T073000\r\n")
+	assert.Contains(t, out, "SUMMARY:Upper body\\; push\r\n")
+	assert.Contains(t, out, "RECURRENCE-ID;TZID=Europe/Madrid:20261022T073000\r\nDTSTART;TZID=Europe/Madrid:20261022T180000\r\nDURATION:PT1H\r\n")
+	assert.Contains(t, out, "DESCRIPTION:Status: completed\\nBlocks: Main\r\n")
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"))
+}
+
+func TestTimezonesAreWritten(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	stamp := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	out := (&ical.Calendar{ProdID: "-//test//EN", Events: []ical.Event{
+		{UID: "a@athenai", Stamp: stamp, Start: time.Date(2026, 10, 19, 7, 30, 0, 0, madrid), RRule: "FREQ=WEEKLY;BYDAY=MO"},
+		{UID: "b@athenai", Stamp: stamp, Start: time.Date(2026, 11, 2, 7, 30, 0, 0, madrid)},
+		{UID: "c@athenai", Stamp: stamp, Start: time.Date(2026, 10, 20, 18, 0, 0, 0, tokyo)},
+		{UID: "d@athenai", Stamp: stamp, Start: time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC)},
+	}}).String()
+
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VTIMEZONE"))
+	assert.Less(t, strings.Index(out, "END:VTIMEZONE"), strings.Index(out, "BEGIN:VEVENT"))
+	assert.Contains(t, out, "BEGIN:VTIMEZONE\r\nTZID:Europe/Madrid\r\nBEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nEND:STANDARD\r\n")
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nEND:DAYLIGHT\r\n")
+	// The changes of the year after the last event repeat for the open-ended rule
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20270328T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20271031T030000\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n")
+	assert.Contains(t, out, "BEGIN:VTIMEZONE\r\nTZID:Asia/Tokyo\r\nBEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n")
+}
+
+func TestAllDayAndUTCEvents(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	allDay := ical.Event{UID: "a@athenai", Stamp: day, Start: day, AllDay: true, Status: ical.StatusCancelled}
+	utc := ical.Event{UID: "b@athenai", Stamp: day, Start: day.Add(90 * time.Minute), Duration: 90 * time.Minute}
+
+	out := (&ical.Calendar{ProdID: "-//test//EN", Events: []ical.Event{allDay, utc}}).String()
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20261019\r\nSTATUS:CANCELLED\r\n")
+	assert.Contains(t, out, "DTSTART:20261019T013000Z\r\nDURATION:PT1H30M\r\n")
+	assert.NotContains(t, out, "X-WR-CALNAME")
+	assert.NotContains(t, out, "VTIMEZONE")
+}
+
+func TestLongLinesAreFolded(t *testing.T) {
+	description := strings.Repeat("Sentadilla búlgara ", 10)
+	out := (&ical.Calendar{ProdID: "-//test//EN", Events: []ical.Event{
+		{UID: "a@athenai", Start: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), AllDay: true, Description: description},
+	}}).String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "") == line, "folding must not split a character")
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+description+"\r\n")
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "PT1H", ical.FormatDuration(time.Hour))
+	assert.Equal(t, "PT2H5M30S", ical.FormatDuration(2*time.Hour+5*time.Minute+30*time.Second))
+	assert.Equal(t, "PT0S", ical.FormatDuration(0))
+}
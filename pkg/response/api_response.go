@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 
@@ -38,11 +39,26 @@ func WriteAPICreated(w http.ResponseWriter, message string, data any) {
 	})
 }
 
+// WriteAPICreatedWithDetails writes a 201 response carrying the new id next to extra fields, such as several kinds of warnings.
+func WriteAPICreatedWithDetails(w http.ResponseWriter, message string, id any, details map[string]any) {
+	data := map[string]any{"id": id}
+	for key, value := range details {
+		data[key] = value
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(APIResponse[any]{
+		Status:  "success",
+		Message: message,
+		Data:    data,
+	})
+}
+
 func WriteAPIError(w http.ResponseWriter, apiErr *apierror.APIError) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	status := http.StatusBadRequest
 	switch apiErr.Code {
-	case errorcode_enum.CodeConflict:
+	case errorcode_enum.CodeConflict, errorcode_enum.CodeInvalidTransition:
 		status = http.StatusConflict
 	case errorcode_enum.CodeInternal:
 		status = http.StatusInternalServerError
@@ -75,6 +91,17 @@ func WriteAPIError(w http.ResponseWriter, apiErr *apierror.APIError) {
 	})
 }
 
+// WriteAPIErrorOr writes err as is when it is an APIError, keeping its status, and as an internal error with the
+// fallback message otherwise.
+func WriteAPIErrorOr(w http.ResponseWriter, err error, fallback string) {
+	var apiErr *apierror.APIError
+	if errors.As(err, &apiErr) {
+		WriteAPIError(w, apiErr)
+		return
+	}
+	WriteAPIError(w, apierror.New(errorcode_enum.CodeInternal, fallback, err))
+}
+
 // isDevelopmentMode checks if the application is running in development mode
 func isDevelopmentMode() bool {
 	env := os.Getenv("APP_ENV")
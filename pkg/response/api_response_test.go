@@ -76,6 +76,7 @@ func TestWriteAPIError_StatusCodes(t *testing.T) {
 		{errorcode_enum.CodeForbidden, 403},
 		{errorcode_enum.CodeNotFound, 404},
 		{errorcode_enum.CodeConflict, 409},
+		{errorcode_enum.CodeInvalidTransition, 409},
 		{errorcode_enum.CodeInternal, 500},
 	}
 
@@ -91,3 +92,25 @@ func TestWriteAPIError_StatusCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteAPICreatedWithDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	response.WriteAPICreatedWithDetails(w, "Created", "abc", map[string]any{"warnings": []string{"careful"}})
+
+	assert.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"abc"`)
+	assert.Contains(t, w.Body.String(), `"warnings":["careful"]`)
+}
+
+func TestWriteAPIErrorOr(t *testing.T) {
+	w := httptest.NewRecorder()
+	response.WriteAPIErrorOr(w, apierror.New(errorcode_enum.CodeNotFound, "Missing", nil), "Failed")
+	assert.Equal(t, 404, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"Missing"`)
+
+	w = httptest.NewRecorder()
+	response.WriteAPIErrorOr(w, errors.New("connection refused"), "Failed")
+	assert.Equal(t, 500, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"Failed"`)
+}
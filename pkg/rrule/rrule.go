@@ -0,0 +1,388 @@
+// Package rrule parses and expands the subset of RFC 5545 recurrence rules used for workout schedules:
+// DAILY, WEEKLY and MONTHLY frequencies with INTERVAL, COUNT or UNTIL, BYDAY, BYMONTHDAY and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+const (
+	dateLayout     = "20060102"
+	datetimeLayout = "20060102T150405Z"
+	// maxEmptyPeriods stops expanding rules that can never match again, such as BYMONTHDAY=31 every 12 months from February
+	maxEmptyPeriods = 1000
+)
+
+// ErrTooManyOccurrences is returned by Expand when a rule yields more occurrences than allowed, unbounded rules included
+var ErrTooManyOccurrences = errors.New("recurrence rule yields too many occurrences")
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY entry; N is the ordinal within the month (1MO, -1FR) or 0 for every such weekday
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE. Until, when set, is inclusive: a date for UntilIsDate, otherwise a UTC instant.
+type Rule struct {
+	Freq        Frequency
+	Interval    int
+	Count       int
+	Until       *time.Time
+	UntilIsDate bool
+	ByDay       []WeekdayNum
+	ByMonthDay  []int
+	WeekStart   time.Weekday
+}
+
+// Parse reads an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=12", with or without the "RRULE:" prefix
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule is empty")
+	}
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		val = strings.TrimSpace(val)
+		if !ok || name == "" || val == "" {
+			return nil, fmt.Errorf("rrule part %q must be NAME=VALUE", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("rrule part %s is repeated", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				err = errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			rule.Interval, err = positiveInt(name, val)
+		case "COUNT":
+			rule.Count, err = positiveInt(name, val)
+		case "UNTIL":
+			err = rule.parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(val)
+		case "WKST":
+			day, ok := weekdayCodes[val]
+			if !ok {
+				err = fmt.Errorf("WKST %q is not a weekday", val)
+			}
+			rule.WeekStart = day
+		default:
+			err = fmt.Errorf("rrule part %s is not supported", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("rrule needs FREQ")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("rrule cannot have both COUNT and UNTIL")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly {
+			return nil, errors.New("BYDAY ordinals such as 1MO are only allowed with FREQ=MONTHLY")
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return nil, errors.New("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	return rule, nil
+}
+
+// IsBounded reports whether the rule ends, by COUNT or UNTIL
+func (r *Rule) IsBounded() bool {
+	return r.Count > 0 || r.Until != nil
+}
+
+// String formats the rule back into an RRULE value, parts in a fixed order
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		if r.UntilIsDate {
+			parts = append(parts, "UNTIL="+r.Until.Format(dateLayout))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format(datetimeLayout))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (d WeekdayNum) String() string {
+	if d.N == 0 {
+		return weekdayCode(d.Day)
+	}
+	return strconv.Itoa(d.N) + weekdayCode(d.Day)
+}
+
+// EndingOn returns a copy of the rule that ends on the given date, replacing COUNT or UNTIL
+func (r *Rule) EndingOn(date time.Time) *Rule {
+	ended := *r
+	until := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	ended.Count = 0
+	ended.Until = &until
+	ended.UntilIsDate = true
+	return &ended
+}
+
+// Expand lists the occurrences from start, which sets the wall-clock time and location of every occurrence.
+// Occurrences keep that wall-clock time across daylight saving changes, and start itself is only included
+// when it matches the rule. More than max occurrences is an ErrTooManyOccurrences.
+func (r *Rule) Expand(start time.Time, max int) ([]time.Time, error) {
+	loc := start.Location()
+	startDate := civilDate(start)
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var occurrences []time.Time
+	empty := 0
+	for period := 0; ; period++ {
+		candidates := r.periodDates(startDate, period*interval)
+		found := false
+		for _, date := range candidates {
+			if date.Before(startDate) {
+				continue
+			}
+			occurrence := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+			if r.pastUntil(date, occurrence) {
+				return occurrences, nil
+			}
+			found = true
+			occurrences = append(occurrences, occurrence)
+			if r.Count > 0 && len(occurrences) == r.Count {
+				return occurrences, nil
+			}
+			if len(occurrences) > max {
+				return nil, ErrTooManyOccurrences
+			}
+		}
+		if found {
+			empty = 0
+		} else if empty++; empty > maxEmptyPeriods {
+			return occurrences, nil
+		}
+	}
+}
+
+func (r *Rule) pastUntil(date, occurrence time.Time) bool {
+	if r.Until == nil {
+		return false
+	}
+	if r.UntilIsDate {
+		return date.After(*r.Until)
+	}
+	return occurrence.After(*r.Until)
+}
+
+// periodDates lists the sorted candidate dates, as UTC midnights, of the period offset steps after the one holding start
+func (r *Rule) periodDates(start time.Time, offset int) []time.Time {
+	var dates []time.Time
+	switch r.Freq {
+	case Daily:
+		day := start.AddDate(0, 0, offset)
+		if r.matchesWeekday(day) && r.matchesMonthDay(day) {
+			dates = append(dates, day)
+		}
+	case Weekly:
+		weekStart := start.AddDate(0, 0, -((int(start.Weekday())-int(r.WeekStart)+7)%7)+7*offset)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() == start.Weekday() || len(r.ByDay) > 0 && r.matchesWeekday(day) {
+				dates = append(dates, day)
+			}
+		}
+	case Monthly:
+		monthStart := time.Date(start.Year(), start.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		dates = r.monthDates(monthStart, start.Day())
+	}
+	return dates
+}
+
+func (r *Rule) monthDates(monthStart time.Time, startDay int) []time.Time {
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+	var dates []time.Time
+	for d := 1; d <= daysInMonth; d++ {
+		day := monthStart.AddDate(0, 0, d-1)
+		var matches bool
+		switch {
+		case len(r.ByDay) == 0 && len(r.ByMonthDay) == 0:
+			matches = d == startDay
+		case len(r.ByDay) == 0:
+			matches = r.matchesMonthDay(day)
+		default:
+			matches = r.matchesMonthlyWeekday(day, daysInMonth) && r.matchesMonthDay(day)
+		}
+		if matches {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, byDay := range r.ByDay {
+		if byDay.Day == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthlyWeekday(day time.Time, daysInMonth int) bool {
+	for _, byDay := range r.ByDay {
+		if byDay.Day != day.Weekday() {
+			continue
+		}
+		switch {
+		case byDay.N == 0:
+			return true
+		case byDay.N > 0 && (day.Day()-1)/7+1 == byDay.N:
+			return true
+		case byDay.N < 0 && (daysInMonth-day.Day())/7+1 == -byDay.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay == day.Day() || monthDay < 0 && daysInMonth+monthDay+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) parseUntil(value string) error {
+	if until, err := time.Parse(dateLayout, value); err == nil {
+		r.Until = &until
+		r.UntilIsDate = true
+		return nil
+	}
+	until, err := time.Parse(datetimeLayout, value)
+	if err != nil {
+		return fmt.Errorf("UNTIL %q must be a date such as 20261231 or a UTC time such as 20261231T235959Z", value)
+	}
+	r.Until = &until
+	return nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("BYDAY %q is not a weekday", item)
+		}
+		day, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("BYDAY %q is not a weekday", item)
+		}
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			var err error
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("BYDAY ordinal %q must be between -5 and 5, not 0", ordinal)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Day: day})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("BYMONTHDAY %q must be between -31 and 31, not 0", item)
+		}
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days, nil
+}
+
+func positiveInt(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+func weekdayCode(day time.Weekday) string {
+	for code, weekday := range weekdayCodes {
+		if weekday == day {
+			return code
+		}
+	}
+	return ""
+}
+
+// civilDate is the wall-clock date of t as a UTC midnight, so date arithmetic ignores daylight saving
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
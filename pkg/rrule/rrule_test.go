@@ -0,0 +1,127 @@
+package rrule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alejandro-albiol/athenai/pkg/rrule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dates(occurrences []time.Time) []string {
+	out := make([]string, len(occurrences))
+	for i, occurrence := range occurrences {
+		out[i] = occurrence.Format("2006-01-02")
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	rule, err := rrule.Parse("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=1MO,-1FR;UNTIL=20261231")
+	require.NoError(t, err)
+	assert.Equal(t, rrule.Monthly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []rrule.WeekdayNum{{N: 1, Day: time.Monday}, {N: -1, Day: time.Friday}}, rule.ByDay)
+	assert.True(t, rule.UntilIsDate)
+	assert.True(t, rule.IsBounded())
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;UNTIL=20261231;BYDAY=1MO,-1FR", rule.String())
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=7",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	}
+	for _, value := range invalid {
+		_, err := rrule.Parse(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestExpandWeekly(t *testing.T) {
+	rule, err := rrule.Parse("FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=5")
+	require.NoError(t, err)
+
+	// Starting on a Tuesday, the first occurrence is the Wednesday after
+	start := time.Date(2026, 10, 20, 7, 30, 0, 0, time.UTC)
+	occurrences, err := rule.Expand(start, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-10-21", "2026-10-23", "2026-10-26", "2026-10-28", "2026-10-30"}, dates(occurrences))
+}
+
+func TestExpandIntervalAndUntil(t *testing.T) {
+	rule, err := rrule.Parse("FREQ=WEEKLY;INTERVAL=2;UNTIL=20261116")
+	require.NoError(t, err)
+
+	occurrences, err := rule.Expand(time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-10-19", "2026-11-02", "2026-11-16"}, dates(occurrences))
+}
+
+func TestExpandMonthly(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	rule, err := rrule.Parse("FREQ=MONTHLY;COUNT=3")
+	require.NoError(t, err)
+	occurrences, err := rule.Expand(start, 100)
+	require.NoError(t, err)
+	// Months without a 31st are skipped
+	assert.Equal(t, []string{"2026-01-31", "2026-03-31", "2026-05-31"}, dates(occurrences))
+
+	rule, err = rrule.Parse("FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3")
+	require.NoError(t, err)
+	occurrences, err = rule.Expand(start, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-01-31", "2026-02-28", "2026-03-31"}, dates(occurrences))
+
+	rule, err = rrule.Parse("FREQ=MONTHLY;BYDAY=1MO,-1FR;COUNT=4")
+	require.NoError(t, err)
+	occurrences, err = rule.Expand(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-10-05", "2026-10-30", "2026-11-02", "2026-11-27"}, dates(occurrences))
+}
+
+func TestExpandKeepsWallClockAcrossDST(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	rule, err := rrule.Parse("FREQ=DAILY;UNTIL=20261026")
+	require.NoError(t, err)
+
+	// Summer time ends in Madrid on 2026-10-25
+	occurrences, err := rule.Expand(time.Date(2026, 10, 24, 7, 0, 0, 0, madrid), 100)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+	for _, occurrence := range occurrences {
+		assert.Equal(t, 7, occurrence.Hour())
+	}
+	assert.Equal(t, 5, occurrences[0].UTC().Hour())
+	assert.Equal(t, 6, occurrences[2].UTC().Hour())
+}
+
+func TestExpandTooManyOccurrences(t *testing.T) {
+	rule, err := rrule.Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	_, err = rule.Expand(time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC), 30)
+	assert.True(t, errors.Is(err, rrule.ErrTooManyOccurrences))
+}
+
+func TestEndingOn(t *testing.T) {
+	rule, err := rrule.Parse("FREQ=DAILY;COUNT=10")
+	require.NoError(t, err)
+	ended := rule.EndingOn(time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "FREQ=DAILY;UNTIL=20261021", ended.String())
+	assert.Equal(t, 10, rule.Count)
+
+	occurrences, err := ended.Expand(time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	assert.Len(t, occurrences, 3)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when a key does not exist in the store
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned for keys that are empty or could escape their tenant prefix
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore is the storage backend for uploaded media.
+// Keys are slash separated and always start with the owning tenant, e.g. "{gym_id}/exercises/{id}/{file}".
+type BlobStore interface {
+	Put(key, contentType string, body io.Reader) error
+	Get(key string) (io.ReadCloser, string, error)
+	Delete(key string) error
+	DeletePrefix(prefix string) error
+}
+
+// ValidateKey rejects keys that are absolute or contain empty or relative segments
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults used when the upload variables are not set
+const (
+	defaultStoragePath   = "./uploads"
+	defaultMaxImageSize  = 10 << 20
+	defaultMaxVideoSize  = 200 << 20
+	defaultSignedURLTTL  = 15 * time.Minute
+	defaultMediaFilePath = "/api/v1/media/file"
+)
+
+// NewBlobStoreFromEnv builds the store selected by MEDIA_STORAGE_BACKEND (local or s3)
+func NewBlobStoreFromEnv() (BlobStore, error) {
+	switch backend := getEnv("MEDIA_STORAGE_BACKEND", "local"); backend {
+	case "local":
+		return NewLocalStore(getEnv("UPLOAD_STORAGE_PATH", defaultStoragePath)), nil
+	case "s3":
+		cfg := S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		}
+		if cfg.Endpoint == "" || cfg.Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 media backend")
+		}
+		return NewS3Store(cfg, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown MEDIA_STORAGE_BACKEND %q", backend)
+	}
+}
+
+// NewURLSignerFromEnv signs links with MEDIA_SIGNING_SECRET, falling back to JWT_SECRET
+func NewURLSignerFromEnv() *URLSigner {
+	ttl, err := time.ParseDuration(os.Getenv("MEDIA_URL_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = defaultSignedURLTTL
+	}
+	secret := getEnv("MEDIA_SIGNING_SECRET", getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-in-production"))
+	return NewURLSigner(secret, ttl, getEnv("MEDIA_BASE_URL", defaultMediaFilePath))
+}
+
+// UploadLimitsFromEnv returns the maximum image and video sizes in bytes
+func UploadLimitsFromEnv() (maxImage, maxVideo int64) {
+	return parseSize(os.Getenv("UPLOAD_MAX_SIZE"), defaultMaxImageSize), parseSize(os.Getenv("UPLOAD_MAX_VIDEO_SIZE"), defaultMaxVideoSize)
+}
+
+// parseSize reads values such as 10MB, 512KB or a plain byte count
+func parseSize(value string, fallback int64) int64 {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for suffix, m := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(value, suffix) {
+			value, multiplier = strings.TrimSuffix(value, suffix), m
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n * multiplier
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
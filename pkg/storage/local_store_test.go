@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+
+	assert.NoError(t, store.Put("gym1/exercises/ex1/a.png", "image/png", strings.NewReader("png-bytes")))
+	assert.NoError(t, store.Put("gym1/exercises/ex1/b.mp4", "video/mp4", strings.NewReader("mp4-bytes")))
+
+	body, contentType, err := store.Get("gym1/exercises/ex1/a.png")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png-bytes", string(data))
+	assert.Equal(t, "image/png", contentType)
+
+	assert.NoError(t, store.DeletePrefix("gym1/exercises/ex1/"))
+	_, _, err = store.Get("gym1/exercises/ex1/b.mp4")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, store.Delete("gym1/exercises/ex1/b.mp4"))
+
+	for _, key := range []string{"", "/etc/passwd", "gym1/../gym2/a.png", "gym1//a.png"} {
+		assert.ErrorIs(t, store.Put(key, "image/png", strings.NewReader("x")), ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible bucket
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") || r.Header.Get("x-amz-date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != "media" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		type contents struct {
+			Key string `xml:"Key"`
+		}
+		var result struct {
+			XMLName  xml.Name   `xml:"ListBucketResult"`
+			Contents []contents `xml:"Contents"`
+		}
+		keys := []string{}
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result.Contents = append(result.Contents, contents{Key: k})
+		}
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		if r.ContentLength < 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = string(data)
+		f.types[key] = r.Header.Get("Content-Type")
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		io.WriteString(w, data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "media", Region: "us-east-1", AccessKeyID: "key", SecretAccessKey: "secret"}, server.Client())
+
+	assert.NoError(t, store.Put("gym1/exercises/ex1/a b.jpg", "image/jpeg", strings.NewReader("jpeg-bytes")))
+	assert.NoError(t, store.Put("gym1/exercises/ex2/c.jpg", "image/jpeg", strings.NewReader("other")))
+
+	body, contentType, err := store.Get("gym1/exercises/ex1/a b.jpg")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "jpeg-bytes", string(data))
+	assert.Equal(t, "image/jpeg", contentType)
+
+	assert.NoError(t, store.DeletePrefix("gym1/exercises/ex1/"))
+	_, _, err = store.Get("gym1/exercises/ex1/a b.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, fake.objects, "gym1/exercises/ex2/c.jpg")
+
+	bad := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "media", AccessKeyID: "wrong"}, server.Client())
+	assert.ErrorContains(t, bad.Put("gym1/a.jpg", "image/jpeg", strings.NewReader("x")), "status 403")
+}
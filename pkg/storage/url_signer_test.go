@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewURLSigner("secret", 15*time.Minute, "/api/v1/media/file/")
+	signer.now = func() time.Time { return now }
+
+	link, expiresAt := signer.SignedURL("gym1/exercises/ex1/photo 1.jpg")
+	assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+	assert.True(t, strings.HasPrefix(link, "/api/v1/media/file/gym1/exercises/ex1/photo%201.jpg?"))
+
+	parsed, _ := url.Parse(link)
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+	assert.True(t, signer.Verify("gym1/exercises/ex1/photo 1.jpg", expires, signature))
+	assert.False(t, signer.Verify("gym2/exercises/ex1/photo 1.jpg", expires, signature))
+	assert.False(t, signer.Verify("gym1/exercises/ex1/photo 1.jpg", "9999999999", signature))
+
+	now = now.Add(16 * time.Minute)
+	assert.False(t, signer.Verify("gym1/exercises/ex1/photo 1.jpg", expires, signature))
+}
+
+func TestParseSize(t *testing.T) {
+	assert.Equal(t, int64(10<<20), parseSize("10MB", 1))
+	assert.Equal(t, int64(512<<10), parseSize(" 512kb ", 1))
+	assert.Equal(t, int64(2048), parseSize("2048", 1))
+	assert.Equal(t, int64(1), parseSize("lots", 1))
+}
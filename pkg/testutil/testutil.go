@@ -0,0 +1,57 @@
+// Package testutil holds the request and error helpers shared by handler and service tests.
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alejandro-albiol/athenai/pkg/apierror"
+	"github.com/alejandro-albiol/athenai/pkg/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// Caller is who a test request comes from, as the auth middleware puts it in the request context
+type Caller struct {
+	UserType string // tenant_user when empty
+	Role     string
+	UserID   string
+	GymID    string // left out of the context when empty
+}
+
+// Serve sends a request with body through handler as caller and records the response
+func Serve(handler http.Handler, caller Caller, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	userType := caller.UserType
+	if userType == "" {
+		userType = "tenant_user"
+	}
+	ctx := context.WithValue(req.Context(), middleware.UserTypeKey, userType)
+	ctx = context.WithValue(ctx, middleware.UserRoleKey, caller.Role)
+	ctx = context.WithValue(ctx, middleware.UserIDKey, caller.UserID)
+	if caller.GymID != "" {
+		ctx = context.WithValue(ctx, middleware.GymIDKey, caller.GymID)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+	return w
+}
+
+// Mount serves handler below pattern, for routers mounted under a path parameter such as /{id}/records
+func Mount(pattern string, handler http.Handler) http.Handler {
+	r := chi.NewRouter()
+	r.Mount(pattern, handler)
+	return r
+}
+
+// AssertCode checks that err is an APIError with the given code
+func AssertCode(t *testing.T, err error, code string) {
+	t.Helper()
+	apiErr, ok := err.(*apierror.APIError)
+	if assert.True(t, ok, "expected APIError, got %v", err) {
+		assert.Equal(t, code, apiErr.Code)
+	}
+}